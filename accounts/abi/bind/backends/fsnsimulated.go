@@ -0,0 +1,271 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package backends
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/consensus"
+	"github.com/FusionFoundation/go-fusion/consensus/ethash"
+	"github.com/FusionFoundation/go-fusion/core"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/core/vm"
+	"github.com/FusionFoundation/go-fusion/crypto"
+	"github.com/FusionFoundation/go-fusion/params"
+)
+
+// FsnCallGas is the gas limit of the FSN calls sent with SendFsnCall.
+const FsnCallGas = 90000
+
+// FsnCallParam is the parameter of an FSN call, e.g. a common.SendAssetParam.
+type FsnCallParam interface {
+	ToBytes() ([]byte, error)
+}
+
+// FsnSimulatedBackend is a SimulatedBackend running the FSN calls: assets,
+// time locks, swaps and tickets. Every block runs UpdateTickets like the DaTong
+// engine does, so the tickets expire as Commit and AdjustTime move the chain
+// time forward. Blocks are not sealed with tickets, no ticket is selected or
+// retreated and the miner reward is the one of ethash.
+//
+// The hard forks follow the network rules in use, which the backend leaves to
+// the caller: run it after common.InitDevnet to have every hard fork active from
+// the genesis block. As the tickets can not all expire, the genesis block holds
+// a never expiring ticket of the zero address.
+type FsnSimulatedBackend struct {
+	*SimulatedBackend
+}
+
+// NewFsnSimulatedBackend creates a new binding backend using a simulated FSN
+// blockchain with the given chain config, params.DevnetChainConfig if nil. The
+// FSN accounts are imported in the genesis block along with alloc, see
+// core.FsnAccount.
+func NewFsnSimulatedBackend(chainConfig *params.ChainConfig, alloc core.GenesisAlloc, accounts []*core.FsnAccount, gasLimit uint64) *FsnSimulatedBackend {
+	if chainConfig == nil {
+		chainConfig = params.DevnetChainConfig
+	}
+	config := *chainConfig
+	database := rawdb.NewMemoryDatabase()
+	genesis := core.Genesis{
+		Config:           &config,
+		GasLimit:         gasLimit,
+		Alloc:            alloc,
+		FsnAccounts:      accounts,
+		TicketCreateInfo: &core.TicketsCreate{Count: 1},
+	}
+	genesis.MustCommit(database)
+
+	engine := &fsnFaker{Engine: ethash.NewFaker()}
+	blockchain, _ := core.NewBlockChain(database, nil, genesis.Config, engine, vm.Config{}, nil)
+	engine.chain = blockchain
+
+	return &FsnSimulatedBackend{newSimulatedBackend(database, blockchain, engine)}
+}
+
+// SendTransaction updates the pending block to include the given transaction.
+// Unlike SimulatedBackend.SendTransaction it returns an error instead of
+// panicking if the transaction is invalid or its FSN call fails, as the miner
+// would not include it.
+func (b *FsnSimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) (err error) {
+	if err := b.checkRawTransaction(tx); err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = e
+				return
+			}
+			panic(r)
+		}
+	}()
+	return b.SimulatedBackend.SendTransaction(ctx, tx)
+}
+
+// checkRawTransaction checks the pending block with tx added against the
+// block validation of the raw transactions.
+func (b *FsnSimulatedBackend) checkRawTransaction(tx *types.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	txs := append(b.pendingBlock.Transactions(), tx)
+	block := types.NewBlockWithHeader(b.pendingBlock.Header()).WithBody(txs, nil)
+	return core.NewBlockValidator(b.config, b.blockchain, b.engine).ValidateRawTransaction(block)
+}
+
+// SendFsnCall signs an FSN call with key and adds it to the pending block,
+// using the pending nonce of the sender, FsnCallGas and a gas price of 1 gwei.
+func (b *FsnSimulatedBackend) SendFsnCall(ctx context.Context, key *ecdsa.PrivateKey, fn common.FSNCallFunc, param FsnCallParam) (*types.Transaction, error) {
+	data, err := param.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+	call, err := (&common.FSNCallParam{Func: fn, Data: data}).ToBytes()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := b.PendingNonceAt(ctx, crypto.PubkeyToAddress(key.PublicKey))
+	if err != nil {
+		return nil, err
+	}
+	tx := types.NewTransaction(nonce, common.FSNCallAddress, new(big.Int), FsnCallGas, big.NewInt(params.GWei), call)
+	tx, err = types.SignTx(tx, types.NewEIP155Signer(b.config.ChainID), key)
+	if err != nil {
+		return nil, err
+	}
+	return tx, b.SendTransaction(ctx, tx)
+}
+
+// PendingTime returns the timestamp of the pending block. The FSN calls of the
+// pending block are checked against the time of the latest block.
+func (b *FsnSimulatedBackend) PendingTime() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.pendingBlock.Time()
+}
+
+// fsnState returns the state at the given block number, nil meaning the latest
+// block.
+func (b *FsnSimulatedBackend) fsnState(ctx context.Context, blockNumber *big.Int) (*state.StateDB, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.stateByBlockNumber(ctx, blockNumber)
+}
+
+// AssetBalanceAt returns the balance of an asset of an account at a block.
+func (b *FsnSimulatedBackend) AssetBalanceAt(ctx context.Context, assetID common.Hash, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	statedb, err := b.fsnState(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return statedb.GetBalance(assetID, account), nil
+}
+
+// TimeLockBalanceAt returns the time locked balance of an asset of an account
+// at a block.
+func (b *FsnSimulatedBackend) TimeLockBalanceAt(ctx context.Context, assetID common.Hash, account common.Address, blockNumber *big.Int) (*common.TimeLock, error) {
+	statedb, err := b.fsnState(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return statedb.GetTimeLockBalance(assetID, account), nil
+}
+
+// AssetAt returns an asset at a block.
+func (b *FsnSimulatedBackend) AssetAt(ctx context.Context, assetID common.Hash, blockNumber *big.Int) (common.Asset, error) {
+	statedb, err := b.fsnState(ctx, blockNumber)
+	if err != nil {
+		return common.Asset{}, err
+	}
+	return statedb.GetAsset(assetID)
+}
+
+// SwapAt returns an open swap at a block.
+func (b *FsnSimulatedBackend) SwapAt(ctx context.Context, swapID common.Hash, blockNumber *big.Int) (common.Swap, error) {
+	statedb, err := b.fsnState(ctx, blockNumber)
+	if err != nil {
+		return common.Swap{}, err
+	}
+	return statedb.GetSwap(swapID)
+}
+
+// TicketsAt returns the tickets of an account at a block.
+func (b *FsnSimulatedBackend) TicketsAt(ctx context.Context, account common.Address, blockNumber *big.Int) (common.TicketSlice, error) {
+	statedb, err := b.fsnState(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return statedb.TicketsByOwner(account)
+}
+
+// FsnCallID returns the ID of the asset or swap created by a committed FSN
+// call transaction.
+func (b *FsnSimulatedBackend) FsnCallID(ctx context.Context, tx *types.Transaction) (common.Hash, error) {
+	receipt, err := b.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if receipt == nil {
+		return common.Hash{}, errTransactionDoesNotExist
+	}
+	from, err := types.Sender(types.NewEIP155Signer(b.config.ChainID), tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	unsigned := types.NewTransaction(tx.Nonce(), *tx.To(), tx.Value(), tx.Gas(), tx.GasPrice(), tx.Data())
+	return core.GetUniqueHashAt(unsigned, from, receipt.BlockNumber), nil
+}
+
+// fsnFaker is an ethash faker which updates the tickets of every block and
+// stores their hash in the MixDigest of the header, as DaTong does.
+type fsnFaker struct {
+	consensus.Engine
+	chain *core.BlockChain // Chain the parents of the generated blocks are read from
+}
+
+// parentTime returns the time of the parent of header, the tickets expire
+// against it.
+func (e *fsnFaker) parentTime(chain consensus.ChainReader, header *types.Header) (uint64, error) {
+	number := header.Number.Uint64() - 1
+	parent := chain.GetHeader(header.ParentHash, number)
+	if parent == nil && e.chain != nil {
+		parent = e.chain.GetHeader(header.ParentHash, number)
+	}
+	if parent == nil {
+		return 0, consensus.ErrUnknownAncestor
+	}
+	return parent.Time, nil
+}
+
+// Finalize implements consensus.Engine, updating the tickets before the
+// ethash rewards.
+func (e *fsnFaker) Finalize(chain consensus.ChainReader, header *types.Header, statedb *state.StateDB, txs []*types.Transaction, uncles []*types.Header) error {
+	parentTime, err := e.parentTime(chain, header)
+	if err != nil {
+		return err
+	}
+	if _, err := statedb.AllTickets(); err != nil {
+		return err
+	}
+	hash, err := statedb.UpdateTickets(header.Number, parentTime)
+	if err != nil {
+		return err
+	}
+	if header.MixDigest == (common.Hash{}) {
+		header.MixDigest = hash
+	} else if header.MixDigest != hash {
+		return fmt.Errorf("MixDigest mismatch, have:%v, want:%v", header.MixDigest, hash)
+	}
+	return e.Engine.Finalize(chain, header, statedb, txs, uncles)
+}
+
+// FinalizeAndAssemble implements consensus.Engine, updating the tickets and
+// assembling the block.
+func (e *fsnFaker) FinalizeAndAssemble(chain consensus.ChainReader, header *types.Header, statedb *state.StateDB, txs []*types.Transaction, uncles []*types.Header, receipts []*types.Receipt) (*types.Block, error) {
+	if err := e.Finalize(chain, header, statedb, txs, uncles); err != nil {
+		return nil, err
+	}
+	return types.NewBlock(header, txs, uncles, receipts), nil
+}
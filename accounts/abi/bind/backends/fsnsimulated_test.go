@@ -0,0 +1,115 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package backends
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/math"
+	"github.com/FusionFoundation/go-fusion/core"
+	"github.com/FusionFoundation/go-fusion/crypto"
+)
+
+func TestFsnSimulatedBackend(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	fsn := new(big.Int).Mul(big.NewInt(1e6), big.NewInt(1e18))
+	sim := NewFsnSimulatedBackend(nil, nil, []*core.FsnAccount{{
+		Address:  addr,
+		Balances: map[common.Hash]*math.HexOrDecimal256{common.SystemAssetID: (*math.HexOrDecimal256)(fsn)},
+	}}, 10000000)
+	defer sim.Close()
+	ctx := context.Background()
+
+	// Asset creation
+	genAsset, err := sim.SendFsnCall(ctx, key, common.GenAssetFunc, &common.GenAssetParam{Name: "Test", Symbol: "TST", Decimals: 2, Total: big.NewInt(1000)})
+	if err != nil {
+		t.Fatalf("failed to generate asset: %v", err)
+	}
+	sim.Commit()
+	assetID, err := sim.FsnCallID(ctx, genAsset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asset, err := sim.AssetAt(ctx, assetID, nil); err != nil || asset.Symbol != "TST" {
+		t.Fatalf("asset not created: %v %v", asset, err)
+	}
+	if balance, _ := sim.AssetBalanceAt(ctx, assetID, addr, nil); balance.Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("asset balance mismatch: have %v, want 1000", balance)
+	}
+
+	// A failing FSN call is not added to the pending block
+	if _, err := sim.SendFsnCall(ctx, key, common.SendAssetFunc, &common.SendAssetParam{AssetID: assetID, To: addr, Value: big.NewInt(2000)}); err == nil {
+		t.Error("sending more than the balance succeeded")
+	}
+
+	// Time locks and swaps
+	now := sim.Blockchain().CurrentHeader().Time
+	if _, err := sim.SendFsnCall(ctx, key, common.TimeLockFunc, &common.TimeLockParam{
+		Type: common.AssetToTimeLock, AssetID: assetID, To: addr, StartTime: now, EndTime: now + 3600, Value: big.NewInt(100),
+	}); err != nil {
+		t.Fatalf("failed to time lock: %v", err)
+	}
+	makeSwap, err := sim.SendFsnCall(ctx, key, common.MakeSwapFunc, &common.MakeSwapParam{
+		FromAssetID: assetID, FromEndTime: common.TimeLockForever, MinFromAmount: big.NewInt(10),
+		ToAssetID: common.SystemAssetID, ToEndTime: common.TimeLockForever, MinToAmount: big.NewInt(1),
+		SwapSize: big.NewInt(2), Time: new(big.Int).SetUint64(now),
+	})
+	if err != nil {
+		t.Fatalf("failed to make swap: %v", err)
+	}
+	sim.Commit()
+	if timelock, _ := sim.TimeLockBalanceAt(ctx, assetID, addr, nil); timelock.IsEmpty() {
+		t.Error("time lock balance missing")
+	}
+	swapID, _ := sim.FsnCallID(ctx, makeSwap)
+	if swap, err := sim.SwapAt(ctx, swapID, nil); err != nil || swap.Owner != addr {
+		t.Errorf("swap not made: %v %v", swap, err)
+	}
+
+	// Tickets expire as the time travels
+	now = sim.Blockchain().CurrentHeader().Time
+	expiry := now + 31*24*3600
+	if _, err := sim.SendFsnCall(ctx, key, common.BuyTicketFunc, &common.BuyTicketParam{Start: now, End: expiry}); err != nil {
+		t.Fatalf("failed to buy ticket: %v", err)
+	}
+	if _, err := sim.SendFsnCall(ctx, key, common.BuyTicketFunc, &common.BuyTicketParam{Start: now, End: expiry}); err == nil {
+		t.Error("bought two tickets in one block")
+	}
+	sim.Commit()
+	if tickets, _ := sim.TicketsAt(ctx, addr, nil); len(tickets) != 1 {
+		t.Fatalf("got %d tickets, want 1", len(tickets))
+	}
+	if err := sim.AdjustTime(32 * 24 * time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	if sim.PendingTime() <= expiry {
+		t.Fatalf("pending time %d not after the ticket expiry %d", sim.PendingTime(), expiry)
+	}
+	sim.Commit()
+	if tickets, _ := sim.TicketsAt(ctx, addr, nil); len(tickets) != 0 {
+		t.Errorf("got %d tickets after expiry, want 0", len(tickets))
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/FusionFoundation/go-fusion/accounts/abi/bind"
 	"github.com/FusionFoundation/go-fusion/common"
 	"github.com/FusionFoundation/go-fusion/common/math"
+	"github.com/FusionFoundation/go-fusion/consensus"
 	"github.com/FusionFoundation/go-fusion/consensus/ethash"
 	"github.com/FusionFoundation/go-fusion/core"
 	"github.com/FusionFoundation/go-fusion/core/bloombits"
@@ -68,6 +69,7 @@ type SimulatedBackend struct {
 	events *filters.EventSystem // Event system for filtering log events live
 
 	config *params.ChainConfig
+	engine consensus.Engine // Engine generating the pending blocks
 }
 
 // NewSimulatedBackendWithDatabase creates a new binding backend based on the given database
@@ -75,12 +77,20 @@ type SimulatedBackend struct {
 func NewSimulatedBackendWithDatabase(database ethdb.Database, alloc core.GenesisAlloc, gasLimit uint64) *SimulatedBackend {
 	genesis := core.Genesis{Config: params.AllEthashProtocolChanges, GasLimit: gasLimit, Alloc: alloc}
 	genesis.MustCommit(database)
-	blockchain, _ := core.NewBlockChain(database, nil, genesis.Config, ethash.NewFaker(), vm.Config{}, nil)
+	engine := ethash.NewFaker()
+	blockchain, _ := core.NewBlockChain(database, nil, genesis.Config, engine, vm.Config{}, nil)
 
+	return newSimulatedBackend(database, blockchain, engine)
+}
+
+// newSimulatedBackend creates a binding backend on top of an existing blockchain,
+// generating its pending blocks with the given engine.
+func newSimulatedBackend(database ethdb.Database, blockchain *core.BlockChain, engine consensus.Engine) *SimulatedBackend {
 	backend := &SimulatedBackend{
 		database:   database,
 		blockchain: blockchain,
-		config:     genesis.Config,
+		config:     blockchain.Config(),
+		engine:     engine,
 		events:     filters.NewEventSystem(&filterBackend{database, blockchain}, false),
 	}
 	backend.rollback()
@@ -120,7 +130,7 @@ func (b *SimulatedBackend) Rollback() {
 }
 
 func (b *SimulatedBackend) rollback() {
-	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), ethash.NewFaker(), b.database, 1, func(int, *core.BlockGen) {})
+	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), b.engine, b.database, 1, func(int, *core.BlockGen) {})
 	statedb, _ := b.blockchain.State()
 
 	b.pendingBlock = blocks[0]
@@ -471,7 +481,7 @@ func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transa
 		panic(fmt.Errorf("invalid transaction nonce: got %d, want %d", tx.Nonce(), nonce))
 	}
 
-	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), ethash.NewFaker(), b.database, 1, func(number int, block *core.BlockGen) {
+	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), b.engine, b.database, 1, func(number int, block *core.BlockGen) {
 		for _, tx := range b.pendingBlock.Transactions() {
 			block.AddTxWithChain(b.blockchain, tx)
 		}
@@ -584,9 +594,9 @@ func (b *SimulatedBackend) AdjustTime(adjustment time.Duration) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), ethash.NewFaker(), b.database, 1, func(number int, block *core.BlockGen) {
+	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), b.engine, b.database, 1, func(number int, block *core.BlockGen) {
 		for _, tx := range b.pendingBlock.Transactions() {
-			block.AddTx(tx)
+			block.AddTxWithChain(b.blockchain, tx)
 		}
 		block.OffsetTime(int64(adjustment.Seconds()))
 	})
@@ -124,7 +124,7 @@ func TestNewSimulatedBackend(t *testing.T) {
 	}
 
 	statedb, _ := sim.blockchain.State()
-	bal := statedb.GetBalance(testAddr)
+	bal := statedb.GetBalance(common.SystemAssetID, testAddr)
 	if bal.Cmp(expectedBal) != 0 {
 		t.Errorf("expected balance for test address not received. expected: %v actual: %v", expectedBal, bal)
 	}
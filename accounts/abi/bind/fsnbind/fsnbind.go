@@ -0,0 +1,299 @@
+// Package fsnbind generates the Solidity interface and the Go bindings of the
+// FSN contract and of the FSN call logs from the FSN contract schema.
+package fsnbind
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+
+	"github.com/FusionFoundation/go-fusion/accounts/abi"
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core/vm"
+)
+
+// goTypes maps the Solidity types of the schema to Go types
+var goTypes = map[string]string{
+	"bytes32":   "common.Hash",
+	"address":   "common.Address",
+	"uint256":   "*big.Int",
+	"uint64":    "uint64",
+	"uint8":     "uint8",
+	"bool":      "bool",
+	"uint256[]": "[]*big.Int",
+}
+
+type tmplArgument struct {
+	Name    string // Go name
+	Param   string // lower camel case parameter name
+	SolName string
+	SolType string
+	GoType  string
+	Indexed bool
+}
+
+type tmplStruct struct {
+	Name   string
+	Fields []tmplArgument
+}
+
+type tmplFunction struct {
+	Name    string
+	Title   string
+	Type    uint8
+	Inputs  []tmplArgument
+	Outputs []tmplArgument
+	View    bool
+}
+
+type tmplError struct {
+	Name     string
+	Selector string
+}
+
+type tmplEvent struct {
+	Name   string
+	Topic  string
+	Inputs []tmplArgument
+}
+
+type tmplCallback struct {
+	Name     string
+	Title    string
+	Selector string
+	Inputs   []tmplArgument
+	Doc      string
+}
+
+type tmplFSNCall struct {
+	Name string
+	Func uint8
+}
+
+type tmplData struct {
+	Package   string
+	ABI       string
+	Address   string
+	Structs   []tmplStruct
+	Functions []tmplFunction
+	Errors    []tmplError
+	Events    []tmplEvent
+	Callbacks []tmplCallback
+	FSNCalls  []tmplFSNCall
+	FSNCall   string
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+func hasStruct(structs []tmplStruct, name string) bool {
+	for _, s := range structs {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// convertArguments converts schema arguments to template arguments, collecting
+// the tuple types
+func convertArguments(args []vm.FcArgument, structs *[]tmplStruct) ([]tmplArgument, error) {
+	converted := make([]tmplArgument, len(args))
+	for i, arg := range args {
+		goType, ok := goTypes[arg.Type]
+		solType := arg.Type
+		if arg.Type == "tuple[]" {
+			if arg.Struct == "" {
+				return nil, fmt.Errorf("tuple %s has no struct name", arg.Name)
+			}
+			fields, err := convertArguments(arg.Components, structs)
+			if err != nil {
+				return nil, err
+			}
+			if !hasStruct(*structs, arg.Struct) {
+				*structs = append(*structs, tmplStruct{Name: arg.Struct, Fields: fields})
+			}
+			goType, ok, solType = "[]"+arg.Struct, true, arg.Struct+"[]"
+		}
+		if !ok {
+			return nil, fmt.Errorf("unsupported type %s of %s", arg.Type, arg.Name)
+		}
+		converted[i] = tmplArgument{
+			Name:    abi.ToCamelCase(arg.Name),
+			Param:   lowerFirst(arg.Name),
+			SolName: arg.Name,
+			SolType: solType,
+			GoType:  goType,
+			Indexed: arg.Indexed,
+		}
+	}
+	return converted, nil
+}
+
+// abiArgument is the JSON ABI encoding of an argument
+type abiArgument struct {
+	Name       string        `json:"name"`
+	Type       string        `json:"type"`
+	Indexed    bool          `json:"indexed,omitempty"`
+	Components []abiArgument `json:"components,omitempty"`
+}
+
+type abiEntry struct {
+	Type            string        `json:"type"`
+	Name            string        `json:"name"`
+	StateMutability string        `json:"stateMutability,omitempty"`
+	Inputs          []abiArgument `json:"inputs"`
+	Outputs         []abiArgument `json:"outputs,omitempty"`
+	Anonymous       bool          `json:"anonymous,omitempty"`
+}
+
+func abiArguments(args []vm.FcArgument) []abiArgument {
+	converted := make([]abiArgument, len(args))
+	for i, arg := range args {
+		converted[i] = abiArgument{Name: arg.Name, Type: arg.Type, Indexed: arg.Indexed}
+		if arg.Type == "tuple[]" {
+			converted[i].Components = abiArguments(arg.Components)
+		}
+	}
+	return converted
+}
+
+// ABI returns the JSON ABI of the functions, errors, events and callbacks of
+// the schema. The functions are not called by selector, see vm.FcFunction.
+func ABI(schema *vm.FcSchema) (string, error) {
+	var entries []abiEntry
+	for _, f := range schema.Functions {
+		mutability := "nonpayable"
+		if f.View {
+			mutability = "view"
+		}
+		entries = append(entries, abiEntry{Type: "function", Name: f.Type.Name(), StateMutability: mutability,
+			Inputs: abiArguments(f.Inputs), Outputs: abiArguments(f.Outputs)})
+	}
+	for _, e := range schema.Errors {
+		entries = append(entries, abiEntry{Type: "error", Name: e.Name, Inputs: []abiArgument{}})
+	}
+	for _, e := range schema.Events {
+		entries = append(entries, abiEntry{Type: "event", Name: e.Name, Inputs: abiArguments(e.Inputs)})
+	}
+	for _, c := range schema.Callbacks {
+		entries = append(entries, abiEntry{Type: "function", Name: c.Name, StateMutability: "payable",
+			Inputs: abiArguments(c.Inputs)})
+	}
+	data, err := json.Marshal(entries)
+	return string(data), err
+}
+
+func newTmplData(schema *vm.FcSchema, pkg string) (*tmplData, error) {
+	abiJSON, err := ABI(schema)
+	if err != nil {
+		return nil, err
+	}
+	data := &tmplData{
+		Package: pkg,
+		ABI:     abiJSON,
+		Address: schema.Address.Hex(),
+		FSNCall: common.FSNCallAddress.Hex(),
+	}
+	for _, f := range schema.Functions {
+		inputs, err := convertArguments(f.Inputs, &data.Structs)
+		if err != nil {
+			return nil, err
+		}
+		for _, arg := range f.Inputs {
+			if arg.Type == "tuple[]" && len(f.Inputs) != 1 {
+				return nil, fmt.Errorf("%s: a tuple array must be the only input", f.Type.Name())
+			}
+		}
+		outputs, err := convertArguments(f.Outputs, &data.Structs)
+		if err != nil {
+			return nil, err
+		}
+		data.Functions = append(data.Functions, tmplFunction{
+			Name:    f.Type.Name(),
+			Title:   abi.ToCamelCase(f.Type.Name()),
+			Type:    uint8(f.Type),
+			Inputs:  inputs,
+			Outputs: outputs,
+			View:    f.View,
+		})
+	}
+	for _, e := range schema.Errors {
+		data.Errors = append(data.Errors, tmplError{Name: e.Name, Selector: hexutil.Encode(e.Selector())})
+	}
+	for _, e := range schema.Events {
+		inputs, err := convertArguments(e.Inputs, &data.Structs)
+		if err != nil {
+			return nil, err
+		}
+		data.Events = append(data.Events, tmplEvent{Name: e.Name, Topic: e.Topic().Hex(), Inputs: inputs})
+	}
+	for _, c := range schema.Callbacks {
+		inputs, err := convertArguments(c.Inputs, &data.Structs)
+		if err != nil {
+			return nil, err
+		}
+		selector := common.Keccak256Hash([]byte(c.Signature())).Bytes()[:4]
+		data.Callbacks = append(data.Callbacks, tmplCallback{
+			Name:     c.Name,
+			Title:    abi.ToCamelCase(c.Name),
+			Selector: hexutil.Encode(selector),
+			Inputs:   inputs,
+			Doc:      c.Doc,
+		})
+	}
+	for f := 0; f < int(common.UnknownFunc); f++ {
+		if name := common.FSNCallFunc(f).Name(); name != "Unknown" {
+			data.FSNCalls = append(data.FSNCalls, tmplFSNCall{Name: name, Func: uint8(f)})
+		}
+	}
+	return data, nil
+}
+
+// Solidity returns the Solidity library calling the FSN contract and the
+// interfaces of its callbacks
+func Solidity(schema *vm.FcSchema) (string, error) {
+	data, err := newTmplData(schema, "")
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := solidityTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Go returns the Go bindings of the FSN contract and the FSN call logs in the
+// given package
+func Go(schema *vm.FcSchema, pkg string) (string, error) {
+	data, err := newTmplData(schema, pkg)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := goTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("%v\n%s", err, buf.String())
+	}
+	return string(code), nil
+}
+
+var funcs = template.FuncMap{
+	"add": func(a, b int) int { return a + b },
+}
+
+var solidityTemplate = template.Must(template.New("sol").Funcs(funcs).Parse(soliditySource))
+
+var goTemplate = template.Must(template.New("go").Funcs(funcs).Parse(goSource))
@@ -0,0 +1,104 @@
+package fsnbind
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/contracts/fsncontract"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/core/vm"
+)
+
+// TestGeneratedUpToDate checks the checked-in bindings match the schema
+func TestGeneratedUpToDate(t *testing.T) {
+	goCode, err := Go(&vm.FSNContractSchema, "fsncontract")
+	if err != nil {
+		t.Fatal(err)
+	}
+	solCode, err := Solidity(&vm.FSNContractSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for file, code := range map[string]string{
+		"../../../../contracts/fsncontract/fsncontract.go":  goCode,
+		"../../../../contracts/fsncontract/FSNContract.sol": solCode,
+	} {
+		have, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(have) != code {
+			t.Errorf("%s is outdated, run go generate ./contracts/fsncontract", file)
+		}
+	}
+}
+
+func TestBindings(t *testing.T) {
+	items := []fsncontract.SendAssetItem{
+		{Asset: common.SystemAssetID, To: common.HexToAddress("0x1"), Value: big.NewInt(10), Start: 1, End: 2, Flag: 0},
+		{Asset: common.SystemAssetID, To: common.HexToAddress("0x2"), Value: big.NewInt(20), Start: 3, End: 4, Flag: 1},
+	}
+	input, err := fsncontract.PackBatchSendAsset(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// [func][offset][count][items...]
+	if len(input) != 3*32+len(items)*6*32 {
+		t.Fatalf("batch input length %d", len(input))
+	}
+	word := func(i int) *big.Int { return new(big.Int).SetBytes(input[i*32 : i*32+32]) }
+	if word(0).Uint64() != uint64(vm.FcBatchSendAsset) || word(1).Uint64() != 32 || word(2).Uint64() != 2 {
+		t.Fatalf("bad batch header %x", input[:96])
+	}
+	if word(3+6+2).Uint64() != 20 || word(3+6+5).Uint64() != 1 {
+		t.Fatalf("bad second item %x", input[3*32+6*32:])
+	}
+
+	count, err := fsncontract.UnpackBatchSendAsset(common.LeftPadBytes([]byte{2}, 32))
+	if err != nil || count.Uint64() != 2 {
+		t.Fatalf("unpack count %v %v", count, err)
+	}
+
+	receive, err := fsncontract.PackReceiveAsset(common.SystemAssetID, 1, 2, 0, []*big.Int{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(receive[:4], common.ReceiveAssetFuncHash[:4]) {
+		t.Fatalf("receiveAsset selector %x", receive[:4])
+	}
+
+	if err := fsncontract.UnpackError(vm.FSNContractSchema.Errors[1].Selector()); err != fsncontract.ErrNotEnoughBalance {
+		t.Fatalf("unpack error %v", err)
+	}
+}
+
+func TestParseSendAssetTimeLock(t *testing.T) {
+	from, to := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	data := append(common.LeftPadBytes([]byte{5}, 32), common.LeftPadBytes([]byte{1}, 32)...)
+	data = append(data, common.LeftPadBytes([]byte{2}, 32)...)
+	log := &types.Log{
+		Address: fsncontract.FSNContractAddress,
+		Topics:  []common.Hash{vm.FcSendAssetTimeLockTopic, from.Hash(), to.Hash(), common.SystemAssetID},
+		Data:    data,
+	}
+	event, err := fsncontract.ParseSendAssetTimeLock(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.From != from || event.To != to || event.Asset != common.SystemAssetID || event.Value.Uint64() != 5 || event.Start != 1 || event.End != 2 {
+		t.Fatalf("bad event %+v", event)
+	}
+
+	call := &types.Log{
+		Address: fsncontract.FSNCallAddress,
+		Topics:  []common.Hash{common.BytesToHash([]byte{fsncontract.FSNCallSendAssetFunc})},
+		Data:    []byte(`{"AssetID":"0x01"}`),
+	}
+	parsed, err := fsncontract.ParseFSNCallLog(call)
+	if err != nil || parsed.Name != "SendAssetFunc" || parsed.Data["AssetID"] != "0x01" {
+		t.Fatalf("bad FSN call log %+v %v", parsed, err)
+	}
+}
@@ -0,0 +1,234 @@
+package fsnbind
+
+const soliditySource = `// Code generated by efsn fsnabigen - DO NOT EDIT.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+pragma solidity ^0.8.4;
+
+/// @title FSNContract calls the FSN contract of the Fusion chain
+/// @notice The FSN contract is not called by selector, its input is the
+/// function type as a word followed by the ABI encoding of the arguments.
+/// Failed calls revert with the errors below, since the FSN contract ABI fork.
+library FSNContract {
+    address internal constant ADDRESS = {{.Address}};
+{{range .Errors}}
+    error {{.Name}}();{{end}}
+{{range .Events}}
+    event {{.Name}}({{range $i, $a := .Inputs}}{{if $i}}, {{end}}{{$a.SolType}}{{if $a.Indexed}} indexed{{end}} {{$a.SolName}}{{end}});{{end}}
+{{range .Structs}}
+    struct {{.Name}} {
+{{- range .Fields}}
+        {{.SolType}} {{.SolName}};{{end}}
+    }
+{{end}}{{range .Functions}}
+    function {{.Name}}({{range $i, $a := .Inputs}}{{if $i}}, {{end}}{{$a.SolType}}{{if eq (printf "%.1s" $a.GoType) "["}} memory{{end}} {{$a.SolName}}{{end}}) internal {{if .View}}view {{end}}returns ({{range $i, $a := .Outputs}}{{if $i}}, {{end}}{{$a.SolType}} {{$a.SolName}}{{end}}) {
+        bytes memory ret = _{{if .View}}static{{end}}call(abi.encodePacked(uint256({{.Type}}), abi.encode({{range $i, $a := .Inputs}}{{if $i}}, {{end}}{{$a.SolName}}{{end}})));
+        ({{range $i, $a := .Outputs}}{{if $i}}, {{end}}{{$a.SolName}}{{end}}) = abi.decode(ret, ({{range $i, $a := .Outputs}}{{if $i}}, {{end}}{{$a.SolType}}{{end}}));
+    }
+{{end}}
+    function _call(bytes memory input) private returns (bytes memory ret) {
+        bool ok;
+        (ok, ret) = ADDRESS.call(input);
+        _check(ok, ret);
+    }
+
+    function _staticcall(bytes memory input) private view returns (bytes memory ret) {
+        bool ok;
+        (ok, ret) = ADDRESS.staticcall(input);
+        _check(ok, ret);
+    }
+
+    function _check(bool ok, bytes memory ret) private pure {
+        if (!ok) {
+            assembly {
+                revert(add(ret, 32), mload(ret))
+            }
+        }
+    }
+}
+{{range .Callbacks}}
+/// @notice {{.Doc}}
+interface I{{.Title}} {
+    function {{.Name}}({{range $i, $a := .Inputs}}{{if $i}}, {{end}}{{$a.SolType}}{{if eq (printf "%.1s" $a.GoType) "["}} calldata{{end}} {{$a.SolName}}{{end}}) external payable;
+}
+{{end}}`
+
+const goSource = `// Code generated by efsn fsnabigen - DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/FusionFoundation/go-fusion/accounts/abi"
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core/types"
+)
+
+// FSNContractABI is the JSON ABI of the FSN contract functions, errors,
+// events and callbacks. The functions are not called by selector, their input
+// is the function type as a word followed by the ABI encoding of the inputs.
+const FSNContractABI = {{printf "%q" .ABI}}
+
+// FSNContractAddress is the address of the FSN contract
+var FSNContractAddress = common.HexToAddress("{{.Address}}")
+
+var fsnContractABI, _ = abi.JSON(strings.NewReader(FSNContractABI))
+
+// Function types of the FSN contract
+const (
+{{- range .Functions}}
+	Func{{.Title}} = {{.Type}}{{end}}
+)
+{{range .Structs}}
+// {{.Name}} is the Go representation of the {{.Name}} tuple
+type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.GoType}}{{end}}
+}
+{{end}}{{range .Functions}}
+// Pack{{.Title}} returns the input of a {{.Name}} call
+func Pack{{.Title}}({{range $i, $a := .Inputs}}{{if $i}}, {{end}}{{$a.Param}} {{$a.GoType}}{{end}}) ([]byte, error) {
+	data, err := fsnContractABI.Methods["{{.Name}}"].Inputs.Pack({{range $i, $a := .Inputs}}{{if $i}}, {{end}}{{$a.Param}}{{end}})
+	if err != nil {
+		return nil, err
+	}
+	return append(common.LeftPadBytes([]byte{Func{{.Title}}}, 32), data...), nil
+}
+{{if eq (len .Outputs) 1}}{{$out := index .Outputs 0}}
+// Unpack{{.Title}} decodes the result of a {{.Name}} call
+func Unpack{{.Title}}(ret []byte) ({{$out.GoType}}, error) {
+	var out {{$out.GoType}}
+	err := fsnContractABI.Unpack(&out, "{{.Name}}", ret)
+	return out, err
+}
+{{else}}
+// {{.Title}}Result is the result of a {{.Name}} call
+type {{.Title}}Result struct {
+{{- range .Outputs}}
+	{{.Name}} {{.GoType}}{{end}}
+}
+
+// Unpack{{.Title}} decodes the result of a {{.Name}} call
+func Unpack{{.Title}}(ret []byte) (*{{.Title}}Result, error) {
+	out := new({{.Title}}Result)
+	err := fsnContractABI.Unpack(out, "{{.Name}}", ret)
+	return out, err
+}
+{{end}}{{end}}
+// Errors the FSN contract reverts with
+var (
+{{- range .Errors}}
+	Err{{.Name}} = errors.New("{{.Name}}()"){{end}}
+)
+
+var fsnContractErrors = map[string]error{
+{{- range .Errors}}
+	"{{.Selector}}": Err{{.Name}},{{end}}
+}
+
+// errorStringSelector is the selector of the Solidity Error(string) revert
+const errorStringSelector = "0x08c379a0"
+
+// UnpackError returns the error of the revert data of an FSN contract call
+func UnpackError(ret []byte) error {
+	if len(ret) < 4 {
+		return errors.New("FSN contract call reverted")
+	}
+	selector := hexutil.Encode(ret[:4])
+	if err, ok := fsnContractErrors[selector]; ok {
+		return err
+	}
+	if selector == errorStringSelector {
+		stringType, _ := abi.NewType("string", "", nil)
+		var reason string
+		if err := (abi.Arguments{{"{{"}}Type: stringType{{"}}"}}).Unpack(&reason, ret[4:]); err == nil {
+			return errors.New(reason)
+		}
+	}
+	return fmt.Errorf("FSN contract call reverted with %x", ret)
+}
+{{range .Events}}
+// {{.Name}}Topic is the first topic of the {{.Name}} logs
+var {{.Name}}Topic = common.HexToHash("{{.Topic}}")
+
+// {{.Name}} is a {{.Name}} log of the FSN contract
+type {{.Name}} struct {
+{{- range .Inputs}}
+	{{.Name}} {{.GoType}}{{end}}
+	Raw *types.Log
+}
+
+// Parse{{.Name}} decodes a {{.Name}} log of the FSN contract
+func Parse{{.Name}}(log *types.Log) (*{{.Name}}, error) {
+	if log.Address != FSNContractAddress || len(log.Topics) == 0 || log.Topics[0] != {{.Name}}Topic {
+		return nil, errors.New("not a {{.Name}} log")
+	}
+	event := &{{.Name}}{Raw: log}
+	if err := fsnContractABI.Unpack(event, "{{.Name}}", log.Data); err != nil {
+		return nil, err
+	}
+	topics := log.Topics[1:]
+{{- range .Inputs}}{{if .Indexed}}
+	if len(topics) == 0 {
+		return nil, errors.New("missing {{.SolName}} topic")
+	}
+	{{if eq .GoType "common.Address"}}event.{{.Name}} = common.BytesToAddress(topics[0].Bytes()){{else if eq .GoType "common.Hash"}}event.{{.Name}} = topics[0]{{else if eq .GoType "*big.Int"}}event.{{.Name}} = topics[0].Big(){{else if eq .GoType "uint64"}}event.{{.Name}} = topics[0].Big().Uint64(){{else if eq .GoType "uint8"}}event.{{.Name}} = uint8(topics[0].Big().Uint64()){{else if eq .GoType "bool"}}event.{{.Name}} = topics[0].Big().Sign() != 0{{end}}
+	topics = topics[1:]{{end}}{{end}}
+	return event, nil
+}
+{{end}}{{range .Callbacks}}
+// Pack{{.Title}} returns the input of a {{.Name}} call of a contract.
+// {{.Doc}}.
+func Pack{{.Title}}({{range $i, $a := .Inputs}}{{if $i}}, {{end}}{{$a.Param}} {{$a.GoType}}{{end}}) ([]byte, error) {
+	data, err := fsnContractABI.Methods["{{.Name}}"].Inputs.Pack({{range $i, $a := .Inputs}}{{if $i}}, {{end}}{{$a.Param}}{{end}})
+	if err != nil {
+		return nil, err
+	}
+	return append(hexutil.MustDecode("{{.Selector}}"), data...), nil
+}
+{{end}}
+// FSNCallAddress is the address FSN calls are sent to and logged from
+var FSNCallAddress = common.HexToAddress("{{.FSNCall}}")
+
+// FSN call functions, the last byte of the topic of their logs
+const (
+{{- range .FSNCalls}}
+	FSNCall{{.Name}} = {{.Func}}{{end}}
+)
+
+var fsnCallNames = map[uint8]string{
+{{- range .FSNCalls}}
+	FSNCall{{.Name}}: "{{.Name}}",{{end}}
+}
+
+// FSNCallLog is the log of an FSN call, its data is a JSON object of the call
+// parameters and results
+type FSNCallLog struct {
+	Func uint8
+	Name string
+	Data map[string]interface{}
+	Raw  *types.Log
+}
+
+// ParseFSNCallLog decodes the log of an FSN call
+func ParseFSNCallLog(log *types.Log) (*FSNCallLog, error) {
+	if log.Address != FSNCallAddress || len(log.Topics) == 0 {
+		return nil, errors.New("not an FSN call log")
+	}
+	fn := log.Topics[0][common.HashLength-1]
+	name, ok := fsnCallNames[fn]
+	if !ok || log.Topics[0] != common.BytesToHash([]byte{fn}) {
+		return nil, fmt.Errorf("unknown FSN call topic %v", log.Topics[0].Hex())
+	}
+	event := &FSNCallLog{Func: fn, Name: name, Raw: log}
+	if err := json.Unmarshal(log.Data, &event.Data); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+`
@@ -57,6 +57,12 @@ const (
 	ledgerP1InitTransactionData     ledgerParam1 = 0x00 // First transaction data block for signing
 	ledgerP1ContTransactionData     ledgerParam1 = 0x80 // Subsequent transaction data block for signing
 	ledgerP2DiscardAddressChainCode ledgerParam2 = 0x00 // Do not return the chain code along with the address
+
+	ledgerStatusOK          = 0x9000 // Command executed successfully
+	ledgerStatusDenied      = 0x6985 // User rejected the request on the device
+	ledgerStatusInvalidData = 0x6a80 // Request data rejected (contract data disabled)
+
+	ledgerMaxChunk = 255 // Maximum APDU payload size of a single request
 )
 
 // errLedgerReplyInvalidHeader is the error message returned by a Ledger data exchange
@@ -68,6 +74,15 @@ var errLedgerReplyInvalidHeader = errors.New("ledger: invalid reply header")
 // when a response does arrive, but it does not contain the expected data.
 var errLedgerInvalidVersionReply = errors.New("ledger: invalid version reply")
 
+// errLedgerSignDenied is the error message returned if the user rejected the
+// transaction on the device.
+var errLedgerSignDenied = errors.New("ledger: transaction denied by user")
+
+// errLedgerContractData is the error message returned if the Ethereum app refused
+// the transaction data. FSN calls always carry data, so the "Contract data" (or
+// "Blind signing") setting has to be enabled in the app to sign them.
+var errLedgerContractData = errors.New("ledger: transaction data rejected, enable contract data in the Ethereum app settings")
+
 // ledgerDriver implements the communication with a Ledger hardware wallet.
 type ledgerDriver struct {
 	device  io.ReadWriter // USB device connection to communicate through
@@ -320,26 +335,22 @@ func (w *ledgerDriver) ledgerSign(derivationPath []uint32, tx *types.Transaction
 			return common.Address{}, nil, err
 		}
 	}
-	payload := append(path, txrlp...)
-
-	// Send the request and wait for the response
+	// Send the request in chunks and wait for the response
 	var (
 		op    = ledgerP1InitTransactionData
 		reply []byte
+		sw    uint16
 	)
-	for len(payload) > 0 {
-		// Calculate the size of the next data chunk
-		chunk := 255
-		if chunk > len(payload) {
-			chunk = len(payload)
-		}
+	for _, chunk := range ledgerChunks(path, txrlp, chainID) {
 		// Send the chunk over, ensuring it's processed correctly
-		reply, err = w.ledgerExchange(ledgerOpSignTransaction, op, 0, payload[:chunk])
+		reply, sw, err = w.ledgerExchangeStatus(ledgerOpSignTransaction, op, 0, chunk)
 		if err != nil {
 			return common.Address{}, nil, err
 		}
-		// Shift the payload and ensure subsequent chunks are marked as such
-		payload = payload[chunk:]
+		if err = ledgerSignStatus(sw); err != nil {
+			return common.Address{}, nil, err
+		}
+		// Ensure subsequent chunks are marked as such
 		op = ledgerP1ContTransactionData
 	}
 	// Extract the Ethereum signature and do a sanity validation
@@ -367,6 +378,51 @@ func (w *ledgerDriver) ledgerSign(derivationPath []uint32, tx *types.Transaction
 	return sender, signed, nil
 }
 
+// ledgerChunks splits the derivation path and the transaction RLP into APDU
+// sized chunks. FSN calls (especially swaps) carry large payloads, so a chunk
+// boundary may land right before the EIP-155 chain ID trailer, which the Ledger
+// Ethereum app fails to parse; in that case the chunk is shortened by a byte.
+func ledgerChunks(path, txrlp []byte, chainID *big.Int) [][]byte {
+	payload := append(append([]byte{}, path...), txrlp...)
+
+	marker := -1
+	if chainID != nil {
+		trailer, _ := rlp.EncodeToBytes([]interface{}{chainID, big.NewInt(0), big.NewInt(0)})
+		marker = len(payload) - (len(trailer) - 1) // list header stripped
+	}
+	var (
+		chunks [][]byte
+		offset int
+	)
+	for offset < len(payload) {
+		// Calculate the size of the next data chunk
+		chunk := ledgerMaxChunk
+		if chunk > len(payload)-offset {
+			chunk = len(payload) - offset
+		}
+		if offset+chunk == marker {
+			chunk--
+		}
+		chunks = append(chunks, payload[offset:offset+chunk])
+		offset += chunk
+	}
+	return chunks
+}
+
+// ledgerSignStatus converts the status word of a signing reply into an error.
+func ledgerSignStatus(sw uint16) error {
+	switch sw {
+	case ledgerStatusOK:
+		return nil
+	case ledgerStatusDenied:
+		return errLedgerSignDenied
+	case ledgerStatusInvalidData:
+		return errLedgerContractData
+	default:
+		return fmt.Errorf("ledger: signing failed with status %#04x", sw)
+	}
+}
+
 // ledgerExchange performs a data exchange with the Ledger wallet, sending it a
 // message and retrieving the response.
 //
@@ -401,6 +457,13 @@ func (w *ledgerDriver) ledgerSign(derivationPath []uint32, tx *types.Transaction
 //  APDU length              | 1 byte
 //  Optional APDU data       | arbitrary
 func (w *ledgerDriver) ledgerExchange(opcode ledgerOpcode, p1 ledgerParam1, p2 ledgerParam2, data []byte) ([]byte, error) {
+	reply, _, err := w.ledgerExchangeStatus(opcode, p1, p2, data)
+	return reply, err
+}
+
+// ledgerExchangeStatus is ledgerExchange, but also returns the APDU status word
+// trailing the reply payload.
+func (w *ledgerDriver) ledgerExchangeStatus(opcode ledgerOpcode, p1 ledgerParam1, p2 ledgerParam2, data []byte) ([]byte, uint16, error) {
 	// Construct the message payload, possibly split into multiple chunks
 	apdu := make([]byte, 2, 7+len(data))
 
@@ -428,7 +491,7 @@ func (w *ledgerDriver) ledgerExchange(opcode ledgerOpcode, p1 ledgerParam1, p2 l
 		// Send over to the device
 		w.log.Trace("Data chunk sent to the Ledger", "chunk", hexutil.Bytes(chunk))
 		if _, err := w.device.Write(chunk); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 	}
 	// Stream the reply back from the wallet in 64 byte chunks
@@ -437,13 +500,13 @@ func (w *ledgerDriver) ledgerExchange(opcode ledgerOpcode, p1 ledgerParam1, p2 l
 	for {
 		// Read the next chunk from the Ledger wallet
 		if _, err := io.ReadFull(w.device, chunk); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		w.log.Trace("Data chunk received from the Ledger", "chunk", hexutil.Bytes(chunk))
 
 		// Make sure the transport header matches
 		if chunk[0] != 0x01 || chunk[1] != 0x01 || chunk[2] != 0x05 {
-			return nil, errLedgerReplyInvalidHeader
+			return nil, 0, errLedgerReplyInvalidHeader
 		}
 		// If it's the first chunk, retrieve the total message length
 		var payload []byte
@@ -462,5 +525,8 @@ func (w *ledgerDriver) ledgerExchange(opcode ledgerOpcode, p1 ledgerParam1, p2 l
 			break
 		}
 	}
-	return reply[:len(reply)-2], nil
+	if len(reply) < 2 {
+		return nil, 0, errLedgerReplyInvalidHeader
+	}
+	return reply[:len(reply)-2], binary.BigEndian.Uint16(reply[len(reply)-2:]), nil
 }
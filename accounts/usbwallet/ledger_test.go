@@ -0,0 +1,59 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package usbwallet
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+// Tests that large FSN call payloads are chunked within the APDU limit and that
+// no chunk ends right before the EIP-155 trailer.
+func TestLedgerChunks(t *testing.T) {
+	path := make([]byte, 1+4*5)
+	chainID := big.NewInt(32659)
+
+	trailer, _ := rlp.EncodeToBytes([]interface{}{chainID, big.NewInt(0), big.NewInt(0)})
+	for size := 0; size < 1500; size++ {
+		txrlp, err := rlp.EncodeToBytes([]interface{}{uint64(1), big.NewInt(1), uint64(90000), &common.FSNCallAddress, big.NewInt(0), make([]byte, size), chainID, big.NewInt(0), big.NewInt(0)})
+		if err != nil {
+			t.Fatalf("size %d: failed to encode: %v", size, err)
+		}
+		marker := len(path) + len(txrlp) - (len(trailer) - 1)
+
+		var (
+			joined []byte
+			offset int
+		)
+		for i, chunk := range ledgerChunks(path, txrlp, chainID) {
+			if len(chunk) == 0 || len(chunk) > ledgerMaxChunk {
+				t.Fatalf("size %d: chunk %d has invalid length %d", size, i, len(chunk))
+			}
+			if offset += len(chunk); offset == marker {
+				t.Fatalf("size %d: chunk %d ends on the EIP-155 trailer", size, i)
+			}
+			joined = append(joined, chunk...)
+		}
+		if !bytes.Equal(joined, append(append([]byte{}, path...), txrlp...)) {
+			t.Fatalf("size %d: chunks don't reassemble the payload", size)
+		}
+	}
+}
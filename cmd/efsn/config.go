@@ -174,6 +174,11 @@ func makeFullNode(ctx *cli.Context) *node.Node {
 	if ctx.GlobalIsSet(utils.GraphQLEnabledFlag.Name) {
 		utils.RegisterGraphQLService(stack, cfg.Node.GraphQLEndpoint(), cfg.Node.GraphQLCors, cfg.Node.GraphQLVirtualHosts, cfg.Node.HTTPTimeouts)
 	}
+	// Configure the gRPC gateway if requested
+	if ctx.GlobalIsSet(utils.GRPCEnabledFlag.Name) {
+		endpoint := fmt.Sprintf("%s:%d", ctx.GlobalString(utils.GRPCListenAddrFlag.Name), ctx.GlobalInt(utils.GRPCPortFlag.Name))
+		utils.RegisterGRPCService(stack, endpoint)
+	}
 	// Add the Ethereum Stats daemon if requested.
 	if cfg.Ethstats.URL != "" {
 		utils.RegisterEthStatsService(stack, cfg.Ethstats.URL)
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/FusionFoundation/go-fusion/accounts/abi/bind/fsnbind"
+	"github.com/FusionFoundation/go-fusion/cmd/utils"
+	"github.com/FusionFoundation/go-fusion/core/vm"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	fsnabigenSolFlag = cli.StringFlag{
+		Name:  "sol",
+		Usage: "Output file of the Solidity library and interfaces",
+	}
+	fsnabigenGoFlag = cli.StringFlag{
+		Name:  "go",
+		Usage: "Output file of the Go bindings",
+	}
+	fsnabigenPkgFlag = cli.StringFlag{
+		Name:  "pkg",
+		Usage: "Package name of the Go bindings",
+		Value: "fsncontract",
+	}
+
+	fsnabigenCommand = cli.Command{
+		Action:    utils.MigrateFlags(fsnabigen),
+		Name:      "fsnabigen",
+		Usage:     "Generate the FSN contract bindings",
+		ArgsUsage: " ",
+		Category:  "MISCELLANEOUS COMMANDS",
+		Flags: []cli.Flag{
+			fsnabigenSolFlag,
+			fsnabigenGoFlag,
+			fsnabigenPkgFlag,
+		},
+		Description: `
+    efsn fsnabigen --sol FSNContract.sol --go fsncontract.go --pkg fsncontract
+
+Generates from the FSN contract schema of the node the Solidity library calling
+the FSN contract, the interfaces of its callbacks, and the Go package packing
+its calls, decoding its results, errors and logs, and decoding FSN call logs.
+Without output files the Go bindings are printed.
+`,
+	}
+)
+
+func fsnabigen(ctx *cli.Context) error {
+	solOut, goOut := ctx.String(fsnabigenSolFlag.Name), ctx.String(fsnabigenGoFlag.Name)
+	if solOut != "" {
+		code, err := fsnbind.Solidity(&vm.FSNContractSchema)
+		if err != nil {
+			utils.Fatalf("Failed to generate Solidity: %v", err)
+		}
+		if err := ioutil.WriteFile(solOut, []byte(code), 0644); err != nil {
+			utils.Fatalf("Failed to write Solidity: %v", err)
+		}
+	}
+	if goOut != "" || solOut == "" {
+		code, err := fsnbind.Go(&vm.FSNContractSchema, ctx.String(fsnabigenPkgFlag.Name))
+		if err != nil {
+			utils.Fatalf("Failed to generate Go bindings: %v", err)
+		}
+		if goOut == "" {
+			fmt.Print(code)
+			return nil
+		}
+		if err := ioutil.WriteFile(goOut, []byte(code), 0644); err != nil {
+			utils.Fatalf("Failed to write Go bindings: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,316 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/FusionFoundation/go-fusion/cmd/utils"
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/timelockprop"
+	"github.com/FusionFoundation/go-fusion/core"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/eth/fsnindex"
+	"github.com/FusionFoundation/go-fusion/rlp"
+	"github.com/FusionFoundation/go-fusion/trie"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	fsnBlockFlag = cli.StringFlag{
+		Name:  "block",
+		Usage: "Number or hash of the block to export the account at (defaults to the head block)",
+	}
+	fsnGenesisFlag = cli.StringFlag{
+		Name:  "genesis",
+		Usage: "Genesis file the accounts are imported in, it is rewritten in place",
+	}
+	fsnSamplesFlag = cli.IntFlag{
+		Name:  "samples",
+		Usage: "Number of time lock balances to test",
+		Value: 100,
+	}
+	fsnStepsFlag = cli.IntFlag{
+		Name:  "steps",
+		Usage: "Number of random operations applied to every time lock balance",
+		Value: 50,
+	}
+	fsnSeedFlag = cli.Int64Flag{
+		Name:  "seed",
+		Usage: "Seed of the random operations (defaults to the current time)",
+	}
+
+	fsnCommand = cli.Command{
+		Name:     "fsn",
+		Usage:    "Manage FSN account fixtures",
+		Category: "BLOCKCHAIN COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Name:      "export-account",
+				Usage:     "Export the FSN state of an account as JSON",
+				ArgsUsage: "<address>",
+				Action:    utils.MigrateFlags(fsnExportAccount),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.AncientFlag,
+					utils.CacheFlag,
+					utils.TestnetFlag,
+					fsnBlockFlag,
+				},
+				Description: `
+    efsn fsn export-account <address> [--block N] > account.json
+
+Prints the balances, time lock segments, tickets, notation and open swaps of
+the account at the given block, together with the definitions of the assets it
+holds. The open swaps are found with the swap history index, so they are only
+exported if the node ran with the index enabled; the swaps open at an old
+block but taken or recalled since are missed.
+
+The node must be stopped while exporting.`,
+			},
+			{
+				Name:      "import-account",
+				Usage:     "Import exported accounts in a genesis file",
+				ArgsUsage: "<account.json> [<account.json>...]",
+				Action:    utils.MigrateFlags(fsnImportAccount),
+				Flags: []cli.Flag{
+					fsnGenesisFlag,
+				},
+				Description: `
+    efsn fsn import-account --genesis genesis.json account.json...
+
+Adds the exported accounts to the "fsnAccounts" of the genesis file, replacing
+the ones of the same address, so that a devnet initialized with it starts
+with these accounts. The assets they hold are created if the genesis does not
+define them, the accounts get new notations.`,
+			},
+			{
+				Name:   "selftest",
+				Usage:  "Check the time lock arithmetic on time locks of the state",
+				Action: utils.MigrateFlags(fsnSelfTest),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.AncientFlag,
+					utils.CacheFlag,
+					utils.TestnetFlag,
+					fsnBlockFlag,
+					fsnSamplesFlag,
+					fsnStepsFlag,
+					fsnSeedFlag,
+				},
+				Description: `
+    efsn fsn selftest [--block N] [--samples 100] [--steps 50] [--seed S]
+
+Reads the time lock balances of accounts from a random place of the state
+and runs random sequences of Add, Sub, ClearExpired and GetSpendableValue
+starting from each of them, checking the invariants of the time lock
+arithmetic, see the timelockprop package. The times of the operations are
+drawn around the item boundaries of the sample. The violations are printed
+with the seed, which replays the same sequences on the same block.
+
+The node must be stopped while testing.`,
+			},
+		},
+	}
+)
+
+// fsnBlockHeader returns the header of the block given with --block, the head
+// block by default.
+func fsnBlockHeader(ctx *cli.Context, chain *core.BlockChain) *types.Header {
+	var header *types.Header
+	switch arg := ctx.String(fsnBlockFlag.Name); {
+	case arg == "":
+		header = chain.CurrentHeader()
+	case hashish(arg):
+		header = chain.GetHeaderByHash(common.HexToHash(arg))
+	default:
+		number, err := strconv.ParseUint(arg, 0, 64)
+		if err != nil {
+			utils.Fatalf("Invalid block %q: %v", arg, err)
+		}
+		header = chain.GetHeaderByNumber(number)
+	}
+	if header == nil {
+		utils.Fatalf("Block not found")
+	}
+	return header
+}
+
+// fsnExportAccount prints the FSN state of an account at a block as JSON.
+func fsnExportAccount(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 || !common.IsHexAddress(ctx.Args().First()) {
+		utils.Fatalf("This command requires an address argument.")
+	}
+	addr := common.HexToAddress(ctx.Args().First())
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+
+	header := fsnBlockHeader(ctx, chain)
+	statedb, err := chain.StateAt(header.Root, header.MixDigest)
+	if err != nil {
+		utils.Fatalf("Could not open the state of block #%d: %v", header.Number, err)
+	}
+	swapIDs, err := fsnindex.ReadOpenSwaps(chainDb, addr, header.Time)
+	if err != nil {
+		utils.Fatalf("Could not read the open swaps: %v", err)
+	}
+	account, err := core.ExportFsnAccount(statedb, header, addr, swapIDs)
+	if err != nil {
+		utils.Fatalf("Export error: %v", err)
+	}
+	out, err := json.MarshalIndent(account, "", "  ")
+	if err != nil {
+		utils.Fatalf("Failed to encode the account: %v", err)
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+// fsnImportAccount adds exported accounts to the FSN accounts of a genesis
+// file.
+func fsnImportAccount(ctx *cli.Context) error {
+	path := ctx.String(fsnGenesisFlag.Name)
+	if path == "" {
+		utils.Fatalf("Must supply the genesis file with --%s", fsnGenesisFlag.Name)
+	}
+	if len(ctx.Args()) == 0 {
+		utils.Fatalf("This command requires the exported account files as arguments.")
+	}
+	blob, err := ioutil.ReadFile(path)
+	if err != nil {
+		utils.Fatalf("Failed to read genesis file: %v", err)
+	}
+	genesis := new(core.Genesis)
+	if err := json.Unmarshal(blob, genesis); err != nil {
+		utils.Fatalf("Invalid genesis file: %v", err)
+	}
+	for _, file := range ctx.Args() {
+		blob, err := ioutil.ReadFile(file)
+		if err != nil {
+			utils.Fatalf("Failed to read account file: %v", err)
+		}
+		account := new(core.FsnAccount)
+		if err := json.Unmarshal(blob, account); err != nil {
+			utils.Fatalf("Invalid account file %s: %v", file, err)
+		}
+		replaced := false
+		for i, existing := range genesis.FsnAccounts {
+			if existing.Address == account.Address {
+				genesis.FsnAccounts[i], replaced = account, true
+			}
+		}
+		if !replaced {
+			genesis.FsnAccounts = append(genesis.FsnAccounts, account)
+		}
+		fmt.Fprintf(os.Stderr, "Imported account %s of block #%d\n", account.Address.Hex(), account.BlockNumber)
+	}
+	out, err := json.MarshalIndent(genesis, "", "  ")
+	if err != nil {
+		utils.Fatalf("Failed to encode the genesis: %v", err)
+	}
+	if err := ioutil.WriteFile(path, append(out, '\n'), 0644); err != nil {
+		utils.Fatalf("Failed to write genesis file: %v", err)
+	}
+	return nil
+}
+
+// fsnSelfTest runs random time lock operations starting from time lock
+// balances sampled from the state and reports the broken invariants.
+func fsnSelfTest(ctx *cli.Context) error {
+	seed := ctx.Int64(fsnSeedFlag.Name)
+	if !ctx.IsSet(fsnSeedFlag.Name) {
+		seed = time.Now().UnixNano()
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+
+	header := fsnBlockHeader(ctx, chain)
+	statedb, err := chain.StateAt(header.Root, header.MixDigest)
+	if err != nil {
+		utils.Fatalf("Could not open the state of block #%d: %v", header.Number, err)
+	}
+	r := rand.New(rand.NewSource(seed))
+	samples, err := sampleTimeLocks(statedb, r, ctx.Int(fsnSamplesFlag.Name))
+	if err != nil {
+		utils.Fatalf("Could not read the time lock balances: %v", err)
+	}
+	steps, failed := ctx.Int(fsnStepsFlag.Name), 0
+	for _, sample := range samples {
+		g := timelockprop.NewGenerator(r, timelockprop.Anchors(sample), 3600)
+		if err := timelockprop.Run(g, sample, steps); err != nil {
+			fmt.Printf("%v\n", err)
+			failed++
+		}
+	}
+	fmt.Printf("Tested %d time lock balances of block #%d with seed %d, %d failed\n", len(samples), header.Number, seed, failed)
+	if failed > 0 {
+		utils.Fatalf("Time lock invariants violated")
+	}
+	return nil
+}
+
+// sampleTimeLocks returns up to n non empty time lock balances of the state,
+// iterating the accounts from a random key and wrapping around.
+func sampleTimeLocks(statedb *state.StateDB, r *rand.Rand, n int) ([]*common.TimeLock, error) {
+	tr, err := statedb.Database().OpenTrie(statedb.IntermediateRoot(false))
+	if err != nil {
+		return nil, err
+	}
+	var start common.Hash
+	r.Read(start[:])
+
+	var samples []*common.TimeLock
+	collect := func(it *trie.Iterator, stop []byte) error {
+		for len(samples) < n && it.Next() {
+			if stop != nil && bytes.Compare(it.Key, stop) >= 0 {
+				break
+			}
+			var account state.Account
+			if err := rlp.DecodeBytes(it.Value, &account); err != nil {
+				return err
+			}
+			for _, timelock := range account.TimeLockBalancesVal {
+				if len(samples) < n && timelock != nil && !timelock.IsEmpty() {
+					samples = append(samples, timelock)
+				}
+			}
+		}
+		return it.Err
+	}
+	if err := collect(trie.NewIterator(tr.NodeIterator(start[:])), nil); err != nil {
+		return nil, err
+	}
+	if err := collect(trie.NewIterator(tr.NodeIterator(nil)), start[:]); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
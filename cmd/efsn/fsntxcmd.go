@@ -0,0 +1,178 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FusionFoundation/go-fusion/accounts/keystore"
+	"github.com/FusionFoundation/go-fusion/cmd/utils"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/internal/fsntx"
+	"github.com/FusionFoundation/go-fusion/rlp"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	fsntxOfflineFlag = cli.BoolFlag{
+		Name:  "offline",
+		Usage: "Build the transaction locally without contacting a node",
+	}
+	fsntxEndpointFlag = cli.StringFlag{
+		Name:  "endpoint",
+		Usage: "Node endpoint used to build the transaction (defaults to the local IPC endpoint)",
+	}
+	fsntxKeyFileFlag = cli.StringFlag{
+		Name:  "keyfile",
+		Usage: "Keystore file used to sign the built transaction",
+	}
+	fsntxChainIDFlag = cli.Uint64Flag{
+		Name:  "chainid",
+		Usage: "Chain ID to sign the transaction for (EIP-155)",
+	}
+	fsntxTimeFlag = cli.Uint64Flag{
+		Name:  "time",
+		Usage: "Unix time used for time lock defaults and checks in offline mode (defaults to now)",
+	}
+
+	fsntxCommand = cli.Command{
+		Name:     "fsntx",
+		Usage:    "Build FSN call transactions",
+		Category: "MISCELLANEOUS COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Name:      "build",
+				Usage:     "Build and optionally sign an FSN call transaction",
+				ArgsUsage: "<input.json | ->",
+				Action:    utils.MigrateFlags(fsntxBuild),
+				Flags: []cli.Flag{
+					fsntxOfflineFlag,
+					fsntxEndpointFlag,
+					fsntxKeyFileFlag,
+					fsntxChainIDFlag,
+					fsntxTimeFlag,
+					utils.PasswordFileFlag,
+				},
+				Description: `
+    efsn fsntx build [--offline] [--keyfile <file> --chainid <id>] <input.json>
+
+Builds the FSN call transaction described by the JSON input, which names the
+fsntx API method and its arguments:
+
+    {"func": "sendAsset", "args": {"from": "0x..", "nonce": "0x0", "gas": "0x15f90",
+     "gasPrice": "0x3b9aca00", "asset": "0xff..ff", "to": "0x..", "value": "0x1"}}
+
+By default the transaction is built by a running node (see --endpoint). With
+--offline it is built locally without any network access, using the same
+encoding as the node; nonce, gas and gasPrice must then be given and checks
+which need chain state are left to the node the transaction is submitted to.
+
+If --keyfile is given the transaction is signed with that key, prompting for
+its password unless --password is given. The result is printed as JSON with
+the RLP encoded transaction in "raw", ready for fsntx.sendRawTransaction.`,
+			},
+		},
+	}
+)
+
+// fsntxBuild builds, and optionally signs, an FSN call transaction.
+func fsntxBuild(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an input file argument (or - for stdin).")
+	}
+	var (
+		input []byte
+		err   error
+	)
+	if path := ctx.Args().First(); path == "-" {
+		input, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		input, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		utils.Fatalf("Failed to read input: %v", err)
+	}
+	var req fsntx.Request
+	if err := json.Unmarshal(input, &req); err != nil {
+		utils.Fatalf("Invalid input: %v", err)
+	}
+	if req.Func == "" {
+		utils.Fatalf("Invalid input: missing func")
+	}
+	// Build the transaction, either locally or by the node
+	var tx *types.Transaction
+	if ctx.Bool(fsntxOfflineFlag.Name) {
+		now := ctx.Uint64(fsntxTimeFlag.Name)
+		if now == 0 {
+			now = uint64(time.Now().Unix())
+		}
+		if tx, err = fsntx.Build(&req, now); err != nil {
+			funcs := fsntx.Funcs()
+			sort.Strings(funcs)
+			utils.Fatalf("Failed to build transaction: %v (supported: %s)", err, strings.Join(funcs, ", "))
+		}
+	} else {
+		client, err := dialRPC(ctx.String(fsntxEndpointFlag.Name))
+		if err != nil {
+			utils.Fatalf("Unable to attach to node: %v", err)
+		}
+		defer client.Close()
+
+		method := "fsntx_build" + strings.ToUpper(req.Func[:1]) + req.Func[1:] + "Tx"
+		if err := client.CallContext(context.Background(), &tx, method, req.Args); err != nil {
+			utils.Fatalf("Failed to build transaction: %v", err)
+		}
+	}
+	// Sign the transaction if a key was given
+	if keyfile := ctx.String(fsntxKeyFileFlag.Name); keyfile != "" {
+		if !ctx.IsSet(fsntxChainIDFlag.Name) {
+			utils.Fatalf("Signing requires --%s", fsntxChainIDFlag.Name)
+		}
+		keyjson, err := ioutil.ReadFile(keyfile)
+		if err != nil {
+			utils.Fatalf("Failed to read the keyfile at '%s': %v", keyfile, err)
+		}
+		passphrase := getPassPhrase("", false, 0, utils.MakePasswordList(ctx))
+		key, err := keystore.DecryptKey(keyjson, passphrase)
+		if err != nil {
+			utils.Fatalf("Error decrypting key: %v", err)
+		}
+		signer := types.NewEIP155Signer(new(big.Int).SetUint64(ctx.Uint64(fsntxChainIDFlag.Name)))
+		if tx, err = types.SignTx(tx, signer, key.PrivateKey); err != nil {
+			utils.Fatalf("Failed to sign transaction: %v", err)
+		}
+	}
+	raw, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		utils.Fatalf("Failed to encode transaction: %v", err)
+	}
+	out, _ := json.MarshalIndent(struct {
+		Raw hexutil.Bytes      `json:"raw"`
+		Tx  *types.Transaction `json:"tx"`
+	}{raw, tx}, "", "  ")
+	fmt.Println(string(out))
+	return nil
+}
@@ -49,6 +49,8 @@ import (
 
 const (
 	clientIdentifier = "efsn" // Client identifier to advertise over the network
+
+	devFsnTicketTarget = 20 // tickets the --dev.fsn developer account keeps by default
 )
 
 var (
@@ -65,6 +67,7 @@ var (
 		utils.BootnodesFlag,
 		utils.BootnodesV4Flag,
 		utils.BootnodesV5Flag,
+		utils.BootnodesDNSFlag,
 		utils.DataDirFlag,
 		utils.AncientFlag,
 		utils.KeyStoreDirFlag,
@@ -90,9 +93,19 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
+		utils.TxPoolTicketLifetimeFlag,
+		utils.TxPoolPendingFsnStateFlag,
 		utils.SyncModeFlag,
 		utils.ExitWhenSyncedFlag,
 		utils.GCModeFlag,
+		utils.AssetHoldersIndexFlag,
+		utils.SwapHistoryIndexFlag,
+		utils.FsnHistoryIndexFlag,
+		utils.FsnFuncIndexFlag,
+		utils.FsnResultCacheFlag,
+		utils.FsnExportURLFlag,
+		utils.FsnExportTopicFlag,
+		utils.FsnExportFromFlag,
 		utils.LightServeFlag,
 		utils.LightLegacyServFlag,
 		utils.LightIngressFlag,
@@ -109,6 +122,7 @@ var (
 		utils.CacheTrieFlag,
 		utils.CacheGCFlag,
 		utils.CacheNoPrefetchFlag,
+		utils.CacheTicketsFlag,
 		utils.ListenPortFlag,
 		utils.MaxPeersFlag,
 		utils.MaxPendingPeersFlag,
@@ -126,8 +140,12 @@ var (
 		utils.MinerExtraDataFlag,
 		utils.MinerLegacyExtraDataFlag,
 		utils.MinerRecommitIntervalFlag,
+		utils.MinerFsnFeeWeightFlag,
 		utils.MinerNoVerfiyFlag,
 		utils.AutoBuyTicketsEnabledFlag,
+		utils.AutoBuyTicketsTargetFlag,
+		utils.AutoReportEnabledFlag,
+		utils.AutoReportMaxFeeFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
 		utils.DiscoveryV5Flag,
@@ -137,6 +155,7 @@ var (
 		utils.DNSDiscoveryFlag,
 		utils.DeveloperFlag,
 		utils.DeveloperPeriodFlag,
+		utils.DeveloperFsnFlag,
 		utils.TestnetFlag,
 		utils.RinkebyFlag,
 		utils.GoerliFlag,
@@ -153,6 +172,9 @@ var (
 		utils.EVMInterpreterFlag,
 		utils.ResyncFromHeightFlag,
 		utils.CheckPointsFileFlag,
+		utils.SignedCheckpointFileFlag,
+		utils.SignedCheckpointDNSFlag,
+		utils.SignedCheckpointSignersFlag,
 		configFileFlag,
 	}
 
@@ -167,6 +189,9 @@ var (
 		utils.GraphQLPortFlag,
 		utils.GraphQLCORSDomainFlag,
 		utils.GraphQLVirtualHostsFlag,
+		utils.GRPCEnabledFlag,
+		utils.GRPCListenAddrFlag,
+		utils.GRPCPortFlag,
 		utils.RPCApiFlag,
 		utils.WSEnabledFlag,
 		utils.WSListenAddrFlag,
@@ -177,6 +202,11 @@ var (
 		utils.IPCPathFlag,
 		utils.InsecureUnlockAllowedFlag,
 		utils.RPCGlobalGasCap,
+		utils.RPCCheapRateFlag,
+		utils.RPCExpensiveRateFlag,
+		utils.RPCCheapConcurrencyFlag,
+		utils.RPCExpensiveConcurrencyFlag,
+		utils.RPCExpensiveMethodsFlag,
 	}
 
 	whisperFlags = []cli.Flag{
@@ -215,9 +245,13 @@ func init() {
 		dumpCommand,
 		dumpGenesisCommand,
 		inspectCommand,
+		// See snapshotcmd.go:
+		snapshotCommand,
 		// See accountcmd.go:
 		accountCommand,
 		walletCommand,
+		fsntxCommand,
+		fsnCommand,
 		// See consolecmd.go:
 		consoleCommand,
 		attachCommand,
@@ -227,6 +261,10 @@ func init() {
 		makedagCommand,
 		versionCommand,
 		licenseCommand,
+		// See fsnabigencmd.go:
+		fsnabigenCommand,
+		// See simulatecmd.go:
+		simulateCommand,
 		// See config.go
 		dumpConfigCommand,
 		// See retesteth.go
@@ -264,7 +302,7 @@ func prepare(ctx *cli.Context) {
 	// If we're a full node on mainnet without --cache specified, bump default cache allowance
 	if ctx.GlobalString(utils.SyncModeFlag.Name) != "light" && !ctx.GlobalIsSet(utils.CacheFlag.Name) && !ctx.GlobalIsSet(utils.NetworkIdFlag.Name) {
 		// Make sure we're not on any supported preconfigured testnet either
-		if !ctx.GlobalIsSet(utils.TestnetFlag.Name) && !ctx.GlobalIsSet(utils.RinkebyFlag.Name) && !ctx.GlobalIsSet(utils.GoerliFlag.Name) && !ctx.GlobalIsSet(utils.DeveloperFlag.Name) {
+		if !ctx.GlobalIsSet(utils.TestnetFlag.Name) && !ctx.GlobalIsSet(utils.RinkebyFlag.Name) && !ctx.GlobalIsSet(utils.GoerliFlag.Name) && !ctx.GlobalIsSet(utils.DeveloperFlag.Name) && !ctx.GlobalIsSet(utils.DeveloperFsnFlag.Name) {
 			// Nope, we're really on mainnet. Bump that cache up!
 			log.Info("Bumping default cache on mainnet", "provided", ctx.GlobalInt(utils.CacheFlag.Name), "updated", 4096)
 			ctx.GlobalSet(utils.CacheFlag.Name, strconv.Itoa(4096))
@@ -324,12 +362,16 @@ func startNode(ctx *cli.Context, stack *node.Node) {
 	debug.Memsize.Add("node", stack)
 
 	// add more log and checking in devnet
-	if ctx.GlobalBool(utils.DevnetFlag.Name) {
+	if ctx.GlobalBool(utils.DevnetFlag.Name) || ctx.GlobalBool(utils.DeveloperFsnFlag.Name) {
 		common.InitDevnet()
 	} else if ctx.GlobalBool(utils.TestnetFlag.Name) {
 		common.InitTestnet()
 	}
 	datong.InitCheckPoints(ctx.GlobalString(utils.CheckPointsFileFlag.Name))
+	if err := datong.InitSignedCheckpoint(ctx.GlobalString(utils.SignedCheckpointFileFlag.Name),
+		ctx.GlobalString(utils.SignedCheckpointDNSFlag.Name), utils.SignedCheckpointSigners(ctx)); err != nil {
+		utils.Fatalf("Failed to load signed checkpoint: %v", err)
+	}
 
 	// Start up the node itself
 	utils.StartNode(stack)
@@ -424,13 +466,21 @@ func startNode(ctx *cli.Context, stack *node.Node) {
 		}()
 	}
 
-	// Start auto buy tickets
-	go ethapi.AutoBuyTicket(ctx.GlobalBool(utils.AutoBuyTicketsEnabledFlag.Name))
-	// Start report illegal
-	go ethapi.ReportIllegal()
+	// Start auto buy tickets, the FSN developer chain keeps a fixed number of
+	// tickets as it only mines blocks with transactions
+	autoBuy, autoBuyTarget := ctx.GlobalBool(utils.AutoBuyTicketsEnabledFlag.Name), ctx.GlobalUint64(utils.AutoBuyTicketsTargetFlag.Name)
+	if ctx.GlobalBool(utils.DeveloperFsnFlag.Name) {
+		autoBuy = true
+		if !ctx.GlobalIsSet(utils.AutoBuyTicketsTargetFlag.Name) {
+			autoBuyTarget = devFsnTicketTarget
+		}
+	}
+	go ethapi.AutoBuyTicket(autoBuy, autoBuyTarget)
+	// Start auto report double mining
+	go ethapi.AutoReportIllegal(ctx.GlobalBool(utils.AutoReportEnabledFlag.Name), utils.GlobalBig(ctx, utils.AutoReportMaxFeeFlag.Name))
 
 	// Start auxiliary services if enabled
-	if ctx.GlobalBool(utils.MiningEnabledFlag.Name) || ctx.GlobalBool(utils.DeveloperFlag.Name) {
+	if ctx.GlobalBool(utils.MiningEnabledFlag.Name) || ctx.GlobalBool(utils.DeveloperFlag.Name) || ctx.GlobalBool(utils.DeveloperFsnFlag.Name) {
 		// Mining only makes sense if a full Ethereum node is running
 		if ctx.GlobalString(utils.SyncModeFlag.Name) == "light" {
 			utils.Fatalf("Light clients do not support mining")
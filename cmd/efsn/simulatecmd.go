@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/FusionFoundation/go-fusion/cmd/utils"
+	"github.com/FusionFoundation/go-fusion/consensus/datong/datongsim"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	simulateTicketsFlag = cli.StringFlag{
+		Name:  "tickets",
+		Usage: "JSON file of the ticket set, as returned by fsn_allTickets",
+	}
+	simulateRoundsFlag = cli.IntFlag{
+		Name:  "rounds",
+		Usage: "Number of selection rounds",
+		Value: 10000,
+	}
+	simulateSeedFlag = cli.Int64Flag{
+		Name:  "seed",
+		Usage: "Seed of the simulated parent blocks",
+		Value: 1,
+	}
+	simulateNumberFlag = cli.Uint64Flag{
+		Name:  "number",
+		Usage: "Number of the simulated parent blocks",
+	}
+	simulateJSONFlag = cli.BoolFlag{
+		Name:  "json",
+		Usage: "Print the statistics as JSON",
+	}
+
+	simulateCommand = cli.Command{
+		Action:    utils.MigrateFlags(simulate),
+		Name:      "simulate",
+		Usage:     "Simulate the ticket selection of a ticket set",
+		ArgsUsage: " ",
+		Category:  "MISCELLANEOUS COMMANDS",
+		Flags: []cli.Flag{
+			simulateTicketsFlag,
+			simulateRoundsFlag,
+			simulateSeedFlag,
+			simulateNumberFlag,
+			simulateJSONFlag,
+		},
+		Description: `
+    efsn simulate --tickets tickets.json --rounds 10000 --seed 1 --number 2000000
+
+Ranks the tickets of the ticket set after random parent blocks drawn from the
+seed, and prints how often every owner mined first against the share of its
+ticket weight. The same seed gives the same statistics. The number of the
+parent blocks defaults to the height of the latest ticket.
+`,
+	}
+)
+
+func simulate(ctx *cli.Context) error {
+	path := ctx.String(simulateTicketsFlag.Name)
+	if path == "" {
+		utils.Fatalf("The ticket set file is required (--%s)", simulateTicketsFlag.Name)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		utils.Fatalf("Failed to open the ticket set: %v", err)
+	}
+	tickets, err := datongsim.LoadTickets(f)
+	f.Close()
+	if err != nil {
+		utils.Fatalf("Failed to load the ticket set: %v", err)
+	}
+
+	number := ctx.Uint64(simulateNumberFlag.Name)
+	if !ctx.IsSet(simulateNumberFlag.Name) {
+		for _, v := range tickets {
+			for _, t := range v.Tickets {
+				if t.Height > number {
+					number = t.Height
+				}
+			}
+		}
+	}
+	result, err := datongsim.Simulate(tickets, datongsim.Config{
+		Rounds: ctx.Int(simulateRoundsFlag.Name),
+		Seed:   ctx.Int64(simulateSeedFlag.Name),
+		Number: number,
+	})
+	if err != nil {
+		utils.Fatalf("Failed to simulate: %v", err)
+	}
+
+	if ctx.Bool(simulateJSONFlag.Name) {
+		out, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			utils.Fatalf("Failed to encode the statistics: %v", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	fmt.Printf("rounds %d, seed %d, parent %d, ticket weight %d\n", result.Rounds, result.Seed, result.Number, result.Tickets)
+	fmt.Printf("%-42s %8s %8s %8s %10s %6s %9s\n", "owner", "tickets", "share", "wins", "expected", "ratio", "avgOrder")
+	for _, s := range result.Owners {
+		fmt.Printf("%-42s %8d %7.3f%% %8d %10.1f %6.3f %9.2f\n", s.Owner.Hex(), s.Tickets, s.Share*100, s.Wins, s.Expected, s.Ratio, s.AvgOrder)
+	}
+	fmt.Printf("chi-square %.3f (%d degrees of freedom), max deviation %.2f sd\n", result.ChiSquare, result.DoF, result.MaxDeviation())
+	return nil
+}
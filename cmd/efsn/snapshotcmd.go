@@ -0,0 +1,91 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/FusionFoundation/go-fusion/cmd/utils"
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	pruneKeepTicketsFlag = cli.Uint64Flag{
+		Name:  "keep-tickets",
+		Usage: "Number of recent blocks whose ticket blobs are kept",
+		Value: 10000,
+	}
+
+	snapshotCommand = cli.Command{
+		Name:      "snapshot",
+		Usage:     "Manage the stored chain state",
+		ArgsUsage: "",
+		Category:  "BLOCKCHAIN COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Name:      "prune-fsn",
+				Usage:     "Prune the FSN data of old blocks",
+				ArgsUsage: " ",
+				Action:    utils.MigrateFlags(pruneFsn),
+				Category:  "BLOCKCHAIN COMMANDS",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.AncientFlag,
+					utils.CacheFlag,
+					utils.TestnetFlag,
+					pruneKeepTicketsFlag,
+				},
+				Description: `
+    efsn snapshot prune-fsn --keep-tickets 10000
+
+Deletes the FSN data which is no longer retained from the database:
+
+    * tickets: only the ticket blobs of the latest --keep-tickets blocks are
+      kept, ticket queries against older states fail afterwards
+    * assets and notations: kept
+    * swaps: kept, removed swaps are deleted from the state by consensus
+
+The node must be stopped while pruning. Archive nodes which have to serve the
+tickets of every block should not be pruned.`,
+			},
+		},
+	}
+)
+
+// pruneFsn deletes the FSN data of old blocks according to the retention
+// policy given by the flags.
+func pruneFsn(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack)
+	defer db.Close()
+
+	start := time.Now()
+	stats, err := core.PruneFsnData(db, core.FsnPrunePolicy{
+		KeepTicketBlocks: ctx.Uint64(pruneKeepTicketsFlag.Name),
+	})
+	if err != nil {
+		utils.Fatalf("Prune error: %v", err)
+	}
+	fmt.Printf("Pruned %d ticket blobs (%v) of %d blocks below head #%d, %d already missing, in %v\n",
+		stats.TicketBlobs, common.StorageSize(stats.TicketBytes), stats.Blocks, stats.Head, stats.MissingBlobs, time.Since(start))
+	return nil
+}
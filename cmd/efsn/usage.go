@@ -80,12 +80,23 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.SyncModeFlag,
 			utils.ExitWhenSyncedFlag,
 			utils.GCModeFlag,
+			utils.AssetHoldersIndexFlag,
+			utils.SwapHistoryIndexFlag,
+			utils.FsnHistoryIndexFlag,
+			utils.FsnFuncIndexFlag,
+			utils.FsnResultCacheFlag,
+			utils.FsnExportURLFlag,
+			utils.FsnExportTopicFlag,
+			utils.FsnExportFromFlag,
 			utils.EthStatsURLFlag,
 			utils.IdentityFlag,
 			utils.LightKDFFlag,
 			utils.WhitelistFlag,
 			utils.ResyncFromHeightFlag,
 			utils.CheckPointsFileFlag,
+			utils.SignedCheckpointFileFlag,
+			utils.SignedCheckpointDNSFlag,
+			utils.SignedCheckpointSignersFlag,
 		},
 	},
 	{
@@ -105,6 +116,7 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.DeveloperFlag,
 			utils.DeveloperPeriodFlag,
+			utils.DeveloperFsnFlag,
 		},
 	},
 	{
@@ -132,6 +144,8 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TxPoolAccountQueueFlag,
 			utils.TxPoolGlobalQueueFlag,
 			utils.TxPoolLifetimeFlag,
+			utils.TxPoolTicketLifetimeFlag,
+			utils.TxPoolPendingFsnStateFlag,
 		},
 	},
 	{
@@ -142,6 +156,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.CacheTrieFlag,
 			utils.CacheGCFlag,
 			utils.CacheNoPrefetchFlag,
+			utils.CacheTicketsFlag,
 		},
 	},
 	{
@@ -163,6 +178,11 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.RPCPortFlag,
 			utils.RPCApiFlag,
 			utils.RPCGlobalGasCap,
+			utils.RPCCheapRateFlag,
+			utils.RPCExpensiveRateFlag,
+			utils.RPCCheapConcurrencyFlag,
+			utils.RPCExpensiveConcurrencyFlag,
+			utils.RPCExpensiveMethodsFlag,
 			utils.RPCCORSDomainFlag,
 			utils.RPCVirtualHostsFlag,
 			utils.WSEnabledFlag,
@@ -175,6 +195,9 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.GraphQLPortFlag,
 			utils.GraphQLCORSDomainFlag,
 			utils.GraphQLVirtualHostsFlag,
+			utils.GRPCEnabledFlag,
+			utils.GRPCListenAddrFlag,
+			utils.GRPCPortFlag,
 			utils.JSpathFlag,
 			utils.ExecFlag,
 			utils.PreloadJSFlag,
@@ -186,6 +209,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.BootnodesFlag,
 			utils.BootnodesV4Flag,
 			utils.BootnodesV5Flag,
+			utils.BootnodesDNSFlag,
 			utils.DNSDiscoveryFlag,
 			utils.ListenPortFlag,
 			utils.MaxPeersFlag,
@@ -210,8 +234,12 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.MinerEtherbaseFlag,
 			utils.MinerExtraDataFlag,
 			utils.MinerRecommitIntervalFlag,
+			utils.MinerFsnFeeWeightFlag,
 			utils.MinerNoVerfiyFlag,
 			utils.AutoBuyTicketsEnabledFlag,
+			utils.AutoBuyTicketsTargetFlag,
+			utils.AutoReportEnabledFlag,
+			utils.AutoReportMaxFeeFlag,
 		},
 	},
 	{
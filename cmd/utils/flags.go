@@ -47,7 +47,9 @@ import (
 	"github.com/FusionFoundation/go-fusion/eth/gasprice"
 	"github.com/FusionFoundation/go-fusion/ethdb"
 	"github.com/FusionFoundation/go-fusion/ethstats"
+	"github.com/FusionFoundation/go-fusion/fsngrpc"
 	"github.com/FusionFoundation/go-fusion/graphql"
+	"github.com/FusionFoundation/go-fusion/internal/ethapi"
 	"github.com/FusionFoundation/go-fusion/les"
 	"github.com/FusionFoundation/go-fusion/log"
 	"github.com/FusionFoundation/go-fusion/metrics"
@@ -193,6 +195,10 @@ var (
 		Name:  "dev.period",
 		Usage: "Block period to use in developer mode (0 = mine only if transaction pending)",
 	}
+	DeveloperFsnFlag = cli.BoolFlag{
+		Name:  "dev.fsn",
+		Usage: "Ephemeral single node FSN network with a pre-funded developer account holding tickets, mining on demand",
+	}
 	IdentityFlag = cli.StringFlag{
 		Name:  "identity",
 		Usage: "Custom node name",
@@ -233,6 +239,39 @@ var (
 		Usage: `Blockchain garbage collection mode ("full", "archive")`,
 		Value: "full",
 	}
+	AssetHoldersIndexFlag = cli.BoolFlag{
+		Name:  "index.assetholders",
+		Usage: "Maintain an index of the holders of every asset (enables fsn_getAssetHolders)",
+	}
+	SwapHistoryIndexFlag = cli.BoolFlag{
+		Name:  "index.swaphistory",
+		Usage: "Maintain an index of all made, taken and recalled swaps (enables fsn_getSwapHistory)",
+	}
+	FsnHistoryIndexFlag = cli.BoolFlag{
+		Name:  "index.fsnhistory",
+		Usage: "Maintain per block ticket, asset supply and swap statistics (enables fsn_getHistoricalStats)",
+	}
+	FsnFuncIndexFlag = cli.BoolFlag{
+		Name:  "index.fsnfuncs",
+		Usage: "Maintain an index of the FSN call transactions by function (enables fsn_getTransactionsByFunc)",
+	}
+	FsnResultCacheFlag = cli.IntFlag{
+		Name:  "cache.fsnresults",
+		Usage: "Number of historical FSN query results cached on disk (0 = disabled, enables fsn_getAllAssetsAtHash)",
+	}
+	FsnExportURLFlag = cli.StringFlag{
+		Name:  "export.url",
+		Usage: "Message broker to stream the FSN events to (kafka://host:port[,host:port...] or nats://host:port)",
+	}
+	FsnExportTopicFlag = cli.StringFlag{
+		Name:  "export.topic",
+		Usage: "Topic prefix of the exported FSN events",
+		Value: eth.DefaultConfig.FsnExport.Topic,
+	}
+	FsnExportFromFlag = cli.Uint64Flag{
+		Name:  "export.from",
+		Usage: "Block to start the FSN event export from (default = resume after the last exported block)",
+	}
 	LightKDFFlag = cli.BoolFlag{
 		Name:  "lightkdf",
 		Usage: "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
@@ -378,6 +417,15 @@ var (
 		Usage: "Maximum amount of time non-executable transaction are queued",
 		Value: eth.DefaultConfig.TxPool.Lifetime,
 	}
+	TxPoolTicketLifetimeFlag = cli.DurationFlag{
+		Name:  "txpool.ticketlifetime",
+		Usage: "Maximum amount of time buy ticket transaction are pooled",
+		Value: eth.DefaultConfig.TxPool.TicketTxLifetime,
+	}
+	TxPoolPendingFsnStateFlag = cli.BoolFlag{
+		Name:  "txpool.pendingfsnstate",
+		Usage: "Validates FSN calls with the earlier pooled transactions of their sender applied",
+	}
 	// Performance tuning settings
 	CacheFlag = cli.IntFlag{
 		Name:  "cache",
@@ -403,6 +451,11 @@ var (
 		Name:  "cache.noprefetch",
 		Usage: "Disable heuristic state prefetch during block import (less CPU and disk IO, more time waiting for data)",
 	}
+	CacheTicketsFlag = cli.IntFlag{
+		Name:  "cache.tickets",
+		Usage: "Number of ticket sets to keep in memory (raise for archive nodes serving historical ticket queries)",
+		Value: eth.DefaultConfig.TicketCache,
+	}
 	// Miner settings
 	MiningEnabledFlag = cli.BoolFlag{
 		Name:  "mine",
@@ -470,6 +523,11 @@ var (
 		Usage: "Time interval to recreate the block being mined",
 		Value: eth.DefaultConfig.Miner.Recommit,
 	}
+	MinerFsnFeeWeightFlag = cli.Uint64Flag{
+		Name:  "miner.fsnfeeweight",
+		Usage: "Percentage of the FSN call fee added to the gas price when ordering transactions (0 = gas price only)",
+		Value: eth.DefaultConfig.Miner.FsnFeeWeight,
+	}
 	MinerNoVerfiyFlag = cli.BoolFlag{
 		Name:  "miner.noverify",
 		Usage: "Disable remote sealing verification",
@@ -479,6 +537,20 @@ var (
 		Name:  "autobt",
 		Usage: "Enable auto buy tickets",
 	}
+	AutoBuyTicketsTargetFlag = cli.Uint64Flag{
+		Name:  "autobt.target",
+		Usage: "Number of tickets to maintain by auto buying (0 = buy every block)",
+	}
+	// Auto report double mining
+	AutoReportEnabledFlag = cli.BoolFlag{
+		Name:  "autoreport",
+		Usage: "Report miners seen signing two blocks at the same height from the etherbase",
+	}
+	AutoReportMaxFeeFlag = BigFlag{
+		Name:  "autoreport.maxfee",
+		Usage: "Maximum fee in wei spent on one automatic report (0 = no limit)",
+		Value: big.NewInt(0),
+	}
 	// Account settings
 	UnlockedAccountFlag = cli.StringFlag{
 		Name:  "unlock",
@@ -507,6 +579,27 @@ var (
 		Name:  "rpc.gascap",
 		Usage: "Sets a cap on gas that can be used in eth_call/estimateGas",
 	}
+	RPCCheapRateFlag = cli.Float64Flag{
+		Name:  "rpc.ratelimit.cheap",
+		Usage: "Calls per second of cheap methods served to each HTTP-RPC and WS-RPC client (0 = unlimited)",
+	}
+	RPCExpensiveRateFlag = cli.Float64Flag{
+		Name:  "rpc.ratelimit.expensive",
+		Usage: "Calls per second of expensive methods served to each HTTP-RPC and WS-RPC client (0 = unlimited)",
+	}
+	RPCCheapConcurrencyFlag = cli.IntFlag{
+		Name:  "rpc.concurrency.cheap",
+		Usage: "Maximum number of concurrent calls of cheap methods per client (0 = unlimited)",
+	}
+	RPCExpensiveConcurrencyFlag = cli.IntFlag{
+		Name:  "rpc.concurrency.expensive",
+		Usage: "Maximum number of concurrent calls of expensive methods per client (0 = unlimited)",
+	}
+	RPCExpensiveMethodsFlag = cli.StringFlag{
+		Name:  "rpc.expensive",
+		Usage: "Comma separated list of the methods limited as expensive",
+		Value: strings.Join(ethapi.ExpensiveMethods, ","),
+	}
 	// Logging and debug settings
 	EthStatsURLFlag = cli.StringFlag{
 		Name:  "ethstats",
@@ -606,6 +699,20 @@ var (
 		Usage: "Comma separated list of virtual hostnames from which to accept requests (server enforced). Accepts '*' wildcard.",
 		Value: strings.Join(node.DefaultConfig.GraphQLVirtualHosts, ","),
 	}
+	GRPCEnabledFlag = cli.BoolFlag{
+		Name:  "grpc",
+		Usage: "Enable the gRPC gateway of the fsn APIs",
+	}
+	GRPCListenAddrFlag = cli.StringFlag{
+		Name:  "grpc.addr",
+		Usage: "gRPC server listening interface",
+		Value: fsngrpc.DefaultHost,
+	}
+	GRPCPortFlag = cli.IntFlag{
+		Name:  "grpc.port",
+		Usage: "gRPC server listening port",
+		Value: fsngrpc.DefaultPort,
+	}
 	ExecFlag = cli.StringFlag{
 		Name:  "exec",
 		Usage: "Execute JavaScript statement",
@@ -646,6 +753,11 @@ var (
 		Usage: "Comma separated enode URLs for P2P v5 discovery bootstrap (light server, light nodes)",
 		Value: "",
 	}
+	BootnodesDNSFlag = cli.StringFlag{
+		Name:  "bootnodes.dns",
+		Usage: "Comma separated EIP-1459 DNS tree URLs (enrtree://...) resolved on start for P2P discovery bootstrap",
+		Value: "",
+	}
 	NodeKeyFileFlag = cli.StringFlag{
 		Name:  "nodekey",
 		Usage: "P2P node key file",
@@ -777,6 +889,21 @@ var (
 		Usage: "Specify check points custom file",
 		Value: "",
 	}
+	SignedCheckpointFileFlag = cli.StringFlag{
+		Name:  "checkpoint.file",
+		Usage: "JSON file of a signed checkpoint to fast sync from",
+		Value: "",
+	}
+	SignedCheckpointDNSFlag = cli.StringFlag{
+		Name:  "checkpoint.dns",
+		Usage: "DNS name whose TXT record publishes the signed checkpoint to fast sync from",
+		Value: "",
+	}
+	SignedCheckpointSignersFlag = cli.StringFlag{
+		Name:  "checkpoint.signers",
+		Usage: "Comma separated addresses of the trusted checkpoint signers (default = built-in signers of the network)",
+		Value: "",
+	}
 )
 
 // MakeDataDir retrieves the currently requested data directory, terminating
@@ -855,8 +982,10 @@ func setBootstrapNodes(ctx *cli.Context, cfg *p2p.Config) {
 	case ctx.GlobalBool(GoerliFlag.Name):
 		urls = params.GoerliBootnodes
 	case cfg.BootstrapNodes != nil:
+		setBootstrapDNS(ctx, cfg)
 		return // already set, don't apply defaults.
 	}
+	setBootstrapDNS(ctx, cfg)
 
 	cfg.BootstrapNodes = make([]*enode.Node, 0, len(urls))
 	for _, url := range urls {
@@ -869,6 +998,50 @@ func setBootstrapNodes(ctx *cli.Context, cfg *p2p.Config) {
 			cfg.BootstrapNodes = append(cfg.BootstrapNodes, node)
 		}
 	}
+	if len(cfg.BootstrapNodes) == 0 && len(cfg.BootstrapDNS) == 0 && !ctx.GlobalBool(NoDiscoverFlag.Name) {
+		log.Warn("No bootstrap nodes configured, set --bootnodes or --bootnodes.dns to discover peers")
+	}
+}
+
+// setBootstrapDNS sets the DNS trees of the bootstrap nodes from the command
+// line flags, reverting to the known tree of the network if none have been
+// specified.
+func setBootstrapDNS(ctx *cli.Context, cfg *p2p.Config) {
+	var url string
+	switch {
+	case ctx.GlobalIsSet(BootnodesDNSFlag.Name):
+		urls := ctx.GlobalString(BootnodesDNSFlag.Name)
+		if urls == "" {
+			cfg.BootstrapDNS = []string{}
+		} else {
+			cfg.BootstrapDNS = splitAndTrim(urls)
+		}
+		return
+	case cfg.BootstrapDNS != nil:
+		return // already set, don't apply defaults.
+	case ctx.GlobalBool(TestnetFlag.Name):
+		url = params.KnownDNSNetworks[params.TestnetGenesisHash]
+	case ctx.GlobalBool(DevnetFlag.Name), ctx.GlobalBool(RinkebyFlag.Name), ctx.GlobalBool(GoerliFlag.Name), ctx.GlobalBool(DeveloperFlag.Name), ctx.GlobalBool(DeveloperFsnFlag.Name):
+		// devnets are not published, their genesis depends on the flags
+	default:
+		url = params.KnownDNSNetworks[params.MainnetGenesisHash]
+	}
+	if url != "" {
+		cfg.BootstrapDNS = []string{url}
+	}
+}
+
+// SignedCheckpointSigners returns the trusted checkpoint signers given by the
+// command line flags, nil for the built-in ones.
+func SignedCheckpointSigners(ctx *cli.Context) []common.Address {
+	var signers []common.Address
+	for _, s := range splitAndTrim(ctx.GlobalString(SignedCheckpointSignersFlag.Name)) {
+		if !common.IsHexAddress(s) {
+			Fatalf("Invalid checkpoint signer: %v", s)
+		}
+		signers = append(signers, common.HexToAddress(s))
+	}
+	return signers
 }
 
 // setBootstrapNodesV5 creates a list of bootstrap nodes from the command line
@@ -997,6 +1170,26 @@ func setWS(ctx *cli.Context, cfg *node.Config) {
 	}
 }
 
+// setRPCPolicy creates the RPC call limits from the command line flags, no
+// policy is set if no limit is.
+func setRPCPolicy(ctx *cli.Context, cfg *node.Config) {
+	policy := &rpc.PolicyConfig{
+		Cheap: rpc.ClassLimits{
+			Rate:        ctx.GlobalFloat64(RPCCheapRateFlag.Name),
+			Concurrency: ctx.GlobalInt(RPCCheapConcurrencyFlag.Name),
+		},
+		Expensive: rpc.ClassLimits{
+			Rate:        ctx.GlobalFloat64(RPCExpensiveRateFlag.Name),
+			Concurrency: ctx.GlobalInt(RPCExpensiveConcurrencyFlag.Name),
+		},
+		ExpensiveMethods: splitAndTrim(ctx.GlobalString(RPCExpensiveMethodsFlag.Name)),
+	}
+	if policy.Cheap == (rpc.ClassLimits{}) && policy.Expensive == (rpc.ClassLimits{}) {
+		return
+	}
+	cfg.RPCPolicy = policy
+}
+
 // setIPC creates an IPC path configuration from the set command line flags,
 // returning an empty string if IPC was explicitly disabled, or the set path.
 func setIPC(ctx *cli.Context, cfg *node.Config) {
@@ -1192,7 +1385,7 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 		cfg.NetRestrict = list
 	}
 
-	if ctx.GlobalBool(DeveloperFlag.Name) {
+	if ctx.GlobalBool(DeveloperFlag.Name) || ctx.GlobalBool(DeveloperFsnFlag.Name) {
 		// --dev mode can't use p2p networking.
 		cfg.MaxPeers = 0
 		cfg.ListenAddr = ":0"
@@ -1208,6 +1401,7 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	setHTTP(ctx, cfg)
 	setGraphQL(ctx, cfg)
 	setWS(ctx, cfg)
+	setRPCPolicy(ctx, cfg)
 	setNodeUserIdent(ctx, cfg)
 	setDataDir(ctx, cfg)
 	setSmartCard(ctx, cfg)
@@ -1254,7 +1448,7 @@ func setDataDir(ctx *cli.Context, cfg *node.Config) {
 	switch {
 	case ctx.GlobalIsSet(DataDirFlag.Name):
 		cfg.DataDir = ctx.GlobalString(DataDirFlag.Name)
-	case ctx.GlobalBool(DeveloperFlag.Name):
+	case ctx.GlobalBool(DeveloperFlag.Name), ctx.GlobalBool(DeveloperFsnFlag.Name):
 		cfg.DataDir = "" // unless explicitly requested, use memory databases
 	case ctx.GlobalBool(TestnetFlag.Name) && cfg.DataDir == node.DefaultDataDir():
 		cfg.DataDir = filepath.Join(node.DefaultDataDir(), "testnet")
@@ -1317,6 +1511,12 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolTicketLifetimeFlag.Name) {
+		cfg.TicketTxLifetime = ctx.GlobalDuration(TxPoolTicketLifetimeFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolPendingFsnStateFlag.Name) {
+		cfg.PendingFsnState = ctx.GlobalBool(TxPoolPendingFsnStateFlag.Name)
+	}
 }
 
 func setEthash(ctx *cli.Context, cfg *eth.Config) {
@@ -1368,6 +1568,9 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.GlobalIsSet(MinerRecommitIntervalFlag.Name) {
 		cfg.Recommit = ctx.Duration(MinerRecommitIntervalFlag.Name)
 	}
+	if ctx.GlobalIsSet(MinerFsnFeeWeightFlag.Name) {
+		cfg.FsnFeeWeight = ctx.GlobalUint64(MinerFsnFeeWeightFlag.Name)
+	}
 	if ctx.GlobalIsSet(MinerNoVerfiyFlag.Name) {
 		cfg.Noverify = ctx.Bool(MinerNoVerfiyFlag.Name)
 	}
@@ -1453,9 +1656,10 @@ func SetShhConfig(ctx *cli.Context, stack *node.Node, cfg *whisper.Config) {
 // SetEthConfig applies eth-related command line flags to the config.
 func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	// Avoid conflicting network flags
-	CheckExclusive(ctx, DeveloperFlag, TestnetFlag, RinkebyFlag, GoerliFlag, DevnetFlag)
+	CheckExclusive(ctx, DeveloperFlag, DeveloperFsnFlag, TestnetFlag, RinkebyFlag, GoerliFlag, DevnetFlag)
 	CheckExclusive(ctx, LightLegacyServFlag, LightServeFlag, SyncModeFlag, "light")
-	CheckExclusive(ctx, DeveloperFlag, ExternalSignerFlag) // Can't use both ephemeral unlocked and external signer
+	CheckExclusive(ctx, DeveloperFlag, ExternalSignerFlag)    // Can't use both ephemeral unlocked and external signer
+	CheckExclusive(ctx, DeveloperFsnFlag, ExternalSignerFlag) // Can't use both ephemeral unlocked and external signer
 
 	var ks *keystore.KeyStore
 	if keystores := stack.AccountManager().Backends(keystore.KeyStoreType); len(keystores) > 0 {
@@ -1496,6 +1700,33 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(CacheNoPrefetchFlag.Name) {
 		cfg.NoPrefetch = ctx.GlobalBool(CacheNoPrefetchFlag.Name)
 	}
+	if ctx.GlobalIsSet(CacheTicketsFlag.Name) {
+		cfg.TicketCache = ctx.GlobalInt(CacheTicketsFlag.Name)
+	}
+	if ctx.GlobalIsSet(AssetHoldersIndexFlag.Name) {
+		cfg.AssetHoldersIndex = ctx.GlobalBool(AssetHoldersIndexFlag.Name)
+	}
+	if ctx.GlobalIsSet(SwapHistoryIndexFlag.Name) {
+		cfg.SwapHistoryIndex = ctx.GlobalBool(SwapHistoryIndexFlag.Name)
+	}
+	if ctx.GlobalIsSet(FsnHistoryIndexFlag.Name) {
+		cfg.FsnHistoryIndex = ctx.GlobalBool(FsnHistoryIndexFlag.Name)
+	}
+	if ctx.GlobalIsSet(FsnFuncIndexFlag.Name) {
+		cfg.FsnFuncIndex = ctx.GlobalBool(FsnFuncIndexFlag.Name)
+	}
+	if ctx.GlobalIsSet(FsnResultCacheFlag.Name) {
+		cfg.FsnResultCache = ctx.GlobalInt(FsnResultCacheFlag.Name)
+	}
+	if ctx.GlobalIsSet(FsnExportURLFlag.Name) {
+		cfg.FsnExport.URL = ctx.GlobalString(FsnExportURLFlag.Name)
+	}
+	if ctx.GlobalIsSet(FsnExportTopicFlag.Name) {
+		cfg.FsnExport.Topic = ctx.GlobalString(FsnExportTopicFlag.Name)
+	}
+	if ctx.GlobalIsSet(FsnExportFromFlag.Name) {
+		cfg.FsnExport.FromBlock = ctx.GlobalUint64(FsnExportFromFlag.Name)
+	}
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheTrieFlag.Name) {
 		cfg.TrieCleanCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheTrieFlag.Name) / 100
 	}
@@ -1563,7 +1794,7 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 			cfg.Genesis.Alloc[devnetAddr] = genesisAccount
 		}
 
-	case ctx.GlobalBool(DeveloperFlag.Name):
+	case ctx.GlobalBool(DeveloperFlag.Name), ctx.GlobalBool(DeveloperFsnFlag.Name):
 		if !ctx.GlobalIsSet(NetworkIdFlag.Name) {
 			cfg.NetworkId = 1337
 		}
@@ -1585,7 +1816,11 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 		}
 		log.Info("Using developer account", "address", developer.Address)
 
-		cfg.Genesis = core.DeveloperGenesisBlock(uint64(ctx.GlobalInt(DeveloperPeriodFlag.Name)), developer.Address)
+		if ctx.GlobalBool(DeveloperFsnFlag.Name) {
+			cfg.Genesis = core.DeveloperFsnGenesisBlock(developer.Address)
+		} else {
+			cfg.Genesis = core.DeveloperGenesisBlock(uint64(ctx.GlobalInt(DeveloperPeriodFlag.Name)), developer.Address)
+		}
 		if !ctx.GlobalIsSet(MinerGasPriceFlag.Name) && !ctx.GlobalIsSet(MinerLegacyGasPriceFlag.Name) {
 			cfg.Miner.GasPrice = big.NewInt(1)
 		}
@@ -1675,6 +1910,23 @@ func RegisterGraphQLService(stack *node.Node, endpoint string, cors, vhosts []st
 	}
 }
 
+// RegisterGRPCService is a utility function to construct a new gRPC gateway and register it against a node.
+func RegisterGRPCService(stack *node.Node, endpoint string) {
+	if err := stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		var ethServ *eth.Ethereum
+		if err := ctx.Service(&ethServ); err == nil {
+			return fsngrpc.New(ethServ.APIBackend, endpoint)
+		}
+		var lesServ *les.LightEthereum
+		if err := ctx.Service(&lesServ); err == nil {
+			return fsngrpc.New(lesServ.ApiBackend, endpoint)
+		}
+		return nil, errors.New("no Ethereum service")
+	}); err != nil {
+		Fatalf("Failed to register the gRPC service: %v", err)
+	}
+}
+
 func SetupMetrics(ctx *cli.Context) {
 	if metrics.Enabled {
 		log.Info("Enabling metrics collection")
@@ -1741,7 +1993,7 @@ func MakeGenesis(ctx *cli.Context) *core.Genesis {
 		genesis = core.DefaultGoerliGenesisBlock()
 	case ctx.GlobalBool(DevnetFlag.Name):
 		genesis = core.DefaultDevnetGenesisBlock()
-	case ctx.GlobalBool(DeveloperFlag.Name):
+	case ctx.GlobalBool(DeveloperFlag.Name), ctx.GlobalBool(DeveloperFsnFlag.Name):
 		Fatalf("Developer chains are ephemeral")
 	}
 	return genesis
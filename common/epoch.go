@@ -0,0 +1,40 @@
+package common
+
+import (
+	"math/big"
+
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+// EpochSummaryLength is the number of blocks of an epoch, the last block of
+// every epoch commits a summary of the chain to the state
+const EpochSummaryLength uint64 = 10000
+
+// EpochSummary is a checkpoint of the chain committed by the last block of an
+// epoch. The summaries are chained by the digest of the previous one.
+type EpochSummary struct {
+	Epoch       uint64
+	Number      uint64   // last block of the epoch
+	TicketsHash Hash     // hash of the ticket pool after the block
+	Tickets     uint64   // number of tickets of the pool
+	Rewards     *big.Int `json:",string"` // block rewards of the epoch
+	FsnSupply   *big.Int `json:",string"` // genesis FSN supply plus the block rewards up to the block
+	Prev        Hash     // digest of the summary of the previous epoch
+}
+
+// EpochOf returns the epoch of block number, the genesis block is the only
+// block of epoch 0
+func EpochOf(number uint64) uint64 {
+	return (number + EpochSummaryLength - 1) / EpochSummaryLength
+}
+
+// IsEpochEnd reports whether block number is the last block of an epoch
+func IsEpochEnd(number uint64) bool {
+	return number > 0 && number%EpochSummaryLength == 0
+}
+
+// Digest returns the hash of the RLP encoding of the summary
+func (s *EpochSummary) Digest() Hash {
+	data, _ := rlp.EncodeToBytes(s)
+	return Keccak256Hash(data)
+}
@@ -46,6 +46,23 @@ func GetForkHeight(n int) uint64 {
 	return math.MaxUint64
 }
 
+// ForkHeights returns the heights of the scheduled hard forks of the network
+// rules in use
+func ForkHeights() []uint64 {
+	if UseDevnetRule {
+		n := len(MAINNET_FORKS)
+		if len(TESTNET_FORKS) > n {
+			n = len(TESTNET_FORKS)
+		}
+		return make([]uint64, n)
+	}
+	forkArray := MAINNET_FORKS
+	if UseTestnetRule {
+		forkArray = TESTNET_FORKS
+	}
+	return append([]uint64(nil), forkArray...)
+}
+
 func IsHardFork(n int, blockNumber *big.Int) bool {
 	return blockNumber == nil || blockNumber.Uint64() >= GetForkHeight(n)
 }
@@ -76,6 +93,148 @@ func IsSmartTransferEnabled(blockNumber *big.Int) bool {
 	return IsHardFork(2, blockNumber)
 }
 
+func IsNotationHistoryEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsTypedCallEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsSponsoredTxEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsStakingKeyEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsSwapDeletionEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsAssetSymbolRegistryEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsAssetTransferRestrictionEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsFsnCallFeeScheduleEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsGovernanceEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsReportExpiryEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsFcBatchSendAssetEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsFsnContractAbiEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsFsnContractLogEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsFsnContractGasScheduleEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsReceiveAssetGuardEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsUsanContractEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsSwapFillModeEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsTicketRateLimitEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsTicketDenominationEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsTicketRevokeEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsMinTicketGuardEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsEpochSummaryEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsAssetMultiOwnerEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsAssetSupplyHistoryEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsAssetEscrowEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsPaymentStreamEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsConditionalTransferEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsBridgeEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsBtcSpvContractEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsEthProofContractEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+func IsTicketRetreatRecordEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+// IsIndexedFsnLogEnabled reports whether FSN call logs are followed by an
+// index log carrying the sender and the primary object ID as indexed topics
+func IsIndexedFsnLogEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+// IsSaltedUniqueHashEnabled reports whether the IDs created by FSN calls are
+// derived from the unique hash of the transaction salted with its sender
+func IsSaltedUniqueHashEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
+// IsFsnErrorCodeLogEnabled reports whether the logs of failed FSN calls carry
+// the code of the failure next to its message
+func IsFsnErrorCodeLogEnabled(blockNumber *big.Int) bool {
+	return IsHardFork(3, blockNumber)
+}
+
 func GetConstantinopleEnableHeight() *big.Int {
 	if UseDevnetRule {
 		return DevnetConstantinopleEnableHeight
@@ -1,9 +1,14 @@
 package common
 
 import (
+	"encoding/json"
+	"fmt"
 	"math/big"
+	"strconv"
+	"strings"
 
 	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/rlp"
 )
 
 type FSNBaseArgsInterface interface {
@@ -15,6 +20,43 @@ func (args *FusionBaseArgs) BaseArgs() *FusionBaseArgs {
 	return args
 }
 
+// AddressOrNotation accepts either a hex address or a notation (USAN)
+// wherever an address is expected in the fsn apis
+type AddressOrNotation struct {
+	Address  Address
+	Notation uint64
+}
+
+// IsNotation returns true if a notation is given instead of an address
+func (a *AddressOrNotation) IsNotation() bool {
+	return a.Notation != 0
+}
+
+// MarshalJSON implements json.Marshaler
+func (a AddressOrNotation) MarshalJSON() ([]byte, error) {
+	if a.IsNotation() {
+		return json.Marshal(a.Notation)
+	}
+	return json.Marshal(a.Address)
+}
+
+// UnmarshalJSON parses a hex address (with 0x prefix), or a notation
+// given as a json number or a decimal string
+func (a *AddressOrNotation) UnmarshalJSON(input []byte) error {
+	str := strings.Trim(string(input), "\"")
+	if has0xPrefix(str) {
+		a.Notation = 0
+		return a.Address.UnmarshalJSON(input)
+	}
+	notation, err := strconv.ParseUint(str, 10, 64)
+	if err != nil || notation == 0 {
+		return fmt.Errorf("invalid address or notation: %s", str)
+	}
+	a.Address = Address{}
+	a.Notation = notation
+	return nil
+}
+
 // FusionBaseArgs wacom
 type FusionBaseArgs struct {
 	From     Address         `json:"from"`
@@ -32,6 +74,14 @@ type GenAssetArgs struct {
 	Total       *hexutil.Big `json:"total"`
 	CanChange   bool         `json:"canChange"`
 	Description string       `json:"description"`
+
+	// TransferRestriction opts the asset into an owner managed transfer list
+	TransferRestriction AssetTransferRestriction `json:"transferRestriction"`
+
+	// Owners and Threshold make the asset owned by an M-of-N key set
+	// instead of the sender
+	Owners    []Address      `json:"owners"`
+	Threshold hexutil.Uint64 `json:"threshold"`
 }
 
 // SendAssetArgs wacom
@@ -54,14 +104,16 @@ type TimeLockArgs struct {
 // BuyTicketArgs wacom
 type BuyTicketArgs struct {
 	FusionBaseArgs
-	Start *hexutil.Uint64 `json:"start"`
-	End   *hexutil.Uint64 `json:"end"`
+	Start        *hexutil.Uint64 `json:"start"`
+	End          *hexutil.Uint64 `json:"end"`
+	Denomination *hexutil.Uint64 `json:"denomination"`
 }
 
 type AssetValueChangeExArgs struct {
 	FusionBaseArgs
 	AssetID     Hash         `json:"asset"`
 	To          Address      `json:"to"`
+	ToUSAN      uint64       `json:"toUSAN"`
 	Value       *hexutil.Big `json:"value"`
 	IsInc       bool         `json:"isInc"`
 	TransacData string       `json:"transacData"`
@@ -80,6 +132,7 @@ type MakeSwapArgs struct {
 	MinToAmount   *hexutil.Big
 	SwapSize      *big.Int
 	Targes        []Address
+	TargesUSAN    []uint64
 	Time          *big.Int
 	Description   string
 }
@@ -91,10 +144,13 @@ type RecallSwapArgs struct {
 }
 
 // TakeSwapArgs wacom
+// FillMode and MinSize select the fill mode, exact takes if not set
 type TakeSwapArgs struct {
 	FusionBaseArgs
-	SwapID Hash
-	Size   *big.Int
+	SwapID   Hash
+	Size     *big.Int
+	FillMode *TakeSwapFillMode
+	MinSize  *big.Int
 }
 
 // MakeMultiSwapArgs wacom
@@ -110,6 +166,7 @@ type MakeMultiSwapArgs struct {
 	MinToAmount   []*hexutil.Big
 	SwapSize      *big.Int
 	Targes        []Address
+	TargesUSAN    []uint64
 	Time          *big.Int
 	Description   string
 }
@@ -127,6 +184,154 @@ type TakeMultiSwapArgs struct {
 	Size   *big.Int
 }
 
+// TypedCallArgs wacom
+// Call is the data of the FSN call transaction signed as typed data
+type TypedCallArgs struct {
+	FusionBaseArgs
+	Call      hexutil.Bytes  `json:"call"`
+	Nonce     hexutil.Uint64 `json:"typedNonce"`
+	Deadline  hexutil.Uint64 `json:"deadline"`
+	Signature hexutil.Bytes  `json:"signature"`
+}
+
+// AssetTransferListArgs wacom
+type AssetTransferListArgs struct {
+	FusionBaseArgs
+	AssetID   Hash      `json:"asset"`
+	Addresses []Address `json:"addresses"`
+	Listed    bool      `json:"listed"`
+}
+
+// SetFsnCallFeeArgs wacom
+type SetFsnCallFeeArgs struct {
+	FusionBaseArgs
+	Func   FSNCallFunc    `json:"func"`
+	Fee    *hexutil.Big   `json:"fee"`
+	Height hexutil.Uint64 `json:"height"`
+}
+
+// CreateProposalArgs wacom
+type CreateProposalArgs struct {
+	FusionBaseArgs
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	EndHeight   hexutil.Uint64 `json:"endHeight"`
+}
+
+// VoteProposalArgs wacom
+type VoteProposalArgs struct {
+	FusionBaseArgs
+	ProposalID Hash `json:"proposal"`
+	Approve    bool `json:"approve"`
+}
+
+// RevokeTicketArgs wacom
+type RevokeTicketArgs struct {
+	FusionBaseArgs
+	TicketID Hash `json:"ticket"`
+}
+
+// EscrowAssetArgs wacom
+type EscrowAssetArgs struct {
+	FusionBaseArgs
+	AssetID  Hash           `json:"asset"`
+	To       Address        `json:"to"`
+	ToUSAN   uint64         `json:"toUSAN"`
+	Value    *hexutil.Big   `json:"value"`
+	Deadline hexutil.Uint64 `json:"deadline"`
+}
+
+// ClaimEscrowArgs wacom
+type ClaimEscrowArgs struct {
+	FusionBaseArgs
+	EscrowID Hash `json:"escrow"`
+}
+
+// CreateStreamArgs wacom
+type CreateStreamArgs struct {
+	FusionBaseArgs
+	AssetID   Hash           `json:"asset"`
+	To        Address        `json:"to"`
+	ToUSAN    uint64         `json:"toUSAN"`
+	Value     *hexutil.Big   `json:"value"`
+	StartTime hexutil.Uint64 `json:"start"`
+	EndTime   hexutil.Uint64 `json:"end"`
+}
+
+// WithdrawStreamArgs wacom
+type WithdrawStreamArgs struct {
+	FusionBaseArgs
+	StreamID Hash `json:"stream"`
+}
+
+// CreateConditionArgs wacom
+type CreateConditionArgs struct {
+	FusionBaseArgs
+	Kind   ConditionKind `json:"kind"`
+	Hash   Hash          `json:"hash"`
+	Oracle Address       `json:"oracle"`
+}
+
+// ResolveConditionArgs wacom
+type ResolveConditionArgs struct {
+	FusionBaseArgs
+	ConditionID Hash          `json:"condition"`
+	Preimage    hexutil.Bytes `json:"preimage"`
+	Signature   hexutil.Bytes `json:"signature"`
+}
+
+// ConditionalTransferArgs wacom
+type ConditionalTransferArgs struct {
+	FusionBaseArgs
+	AssetID     Hash           `json:"asset"`
+	To          Address        `json:"to"`
+	ToUSAN      uint64         `json:"toUSAN"`
+	Value       *hexutil.Big   `json:"value"`
+	ConditionID Hash           `json:"condition"`
+	Deadline    hexutil.Uint64 `json:"deadline"`
+}
+
+// SettleConditionalArgs wacom
+type SettleConditionalArgs struct {
+	FusionBaseArgs
+	TransferID Hash `json:"transfer"`
+}
+
+// AttestDepositArgs wacom
+type AttestDepositArgs struct {
+	FusionBaseArgs
+	AssetID  Hash           `json:"asset"`
+	ChainID  hexutil.Uint64 `json:"chainId"`
+	TxHash   Hash           `json:"txHash"`
+	LogIndex hexutil.Uint64 `json:"logIndex"`
+	To       Address        `json:"to"`
+	Value    *hexutil.Big   `json:"value"`
+}
+
+// BridgeWithdrawArgs wacom
+type BridgeWithdrawArgs struct {
+	FusionBaseArgs
+	AssetID   Hash           `json:"asset"`
+	ChainID   hexutil.Uint64 `json:"chainId"`
+	Recipient hexutil.Bytes  `json:"recipient"`
+	Value     *hexutil.Big   `json:"value"`
+}
+
+// StakingKeyArgs wacom
+type StakingKeyArgs struct {
+	FusionBaseArgs
+	Key Address `json:"key"`
+}
+
+// StakingBuyTicketArgs wacom
+type StakingBuyTicketArgs struct {
+	FusionBaseArgs
+	Owner        Address         `json:"owner"`
+	Start        *hexutil.Uint64 `json:"start"`
+	End          *hexutil.Uint64 `json:"end"`
+	Denomination *hexutil.Uint64 `json:"denomination"`
+}
+
 //////////////////// args ToParam, ToData, Init ///////////////////////
 
 func (args *FusionBaseArgs) ToData() ([]byte, error) {
@@ -172,13 +377,109 @@ func (args *GenAssetArgs) ToParam() *GenAssetParam {
 }
 
 func (args *GenAssetArgs) ToData() ([]byte, error) {
+	switch args.FuncType() {
+	case GenRestrictedAssetFunc:
+		return args.ToRestrictedParam().ToBytes()
+	case GenMultiOwnerAssetFunc:
+		return args.ToMultiOwnerParam().ToBytes()
+	}
+	return args.ToParam().ToBytes()
+}
+
+func (args *GenAssetArgs) ToRestrictedParam() *GenRestrictedAssetParam {
+	return &GenRestrictedAssetParam{
+		Asset:       *args.ToParam(),
+		Restriction: args.TransferRestriction,
+	}
+}
+
+func (args *GenAssetArgs) ToMultiOwnerParam() *GenMultiOwnerAssetParam {
+	return &GenMultiOwnerAssetParam{
+		Asset: *args.ToParam(),
+		Owners: AssetOwnerSet{
+			Threshold: uint64(args.Threshold),
+			Owners:    args.Owners,
+		},
+	}
+}
+
+// FuncType returns the FSN call generating the asset
+func (args *GenAssetArgs) FuncType() FSNCallFunc {
+	if len(args.Owners) != 0 {
+		return GenMultiOwnerAssetFunc
+	}
+	if args.TransferRestriction != AssetTransferUnrestricted {
+		return GenRestrictedAssetFunc
+	}
+	return GenAssetFunc
+}
+
+// Check checks the asset to generate in the given block
+func (args *GenAssetArgs) Check(blockNumber *big.Int) error {
+	switch args.FuncType() {
+	case GenRestrictedAssetFunc:
+		return args.ToRestrictedParam().Check(blockNumber)
+	case GenMultiOwnerAssetFunc:
+		if args.TransferRestriction != AssetTransferUnrestricted {
+			return fmt.Errorf("assets owned by a key set can not restrict transfers")
+		}
+		return args.ToMultiOwnerParam().Check(blockNumber)
+	}
+	return args.ToParam().Check(blockNumber)
+}
+
+func (args *AssetTransferListArgs) ToParam() *AssetTransferListParam {
+	return &AssetTransferListParam{
+		AssetID:   args.AssetID,
+		Addresses: args.Addresses,
+		Listed:    args.Listed,
+	}
+}
+
+func (args *AssetTransferListArgs) ToData() ([]byte, error) {
+	return args.ToParam().ToBytes()
+}
+
+func (args *SetFsnCallFeeArgs) ToParam() *SetFsnCallFeeParam {
+	return &SetFsnCallFeeParam{
+		Func:   args.Func,
+		Fee:    args.Fee.ToInt(),
+		Height: uint64(args.Height),
+	}
+}
+
+func (args *SetFsnCallFeeArgs) ToData() ([]byte, error) {
+	return args.ToParam().ToBytes()
+}
+
+func (args *CreateProposalArgs) ToParam() *CreateProposalParam {
+	return &CreateProposalParam{
+		Title:       args.Title,
+		Description: args.Description,
+		EndHeight:   uint64(args.EndHeight),
+	}
+}
+
+func (args *CreateProposalArgs) ToData() ([]byte, error) {
+	return args.ToParam().ToBytes()
+}
+
+func (args *VoteProposalArgs) ToParam() *VoteProposalParam {
+	return &VoteProposalParam{
+		ProposalID: args.ProposalID,
+		Approve:    args.Approve,
+	}
+}
+
+func (args *VoteProposalArgs) ToData() ([]byte, error) {
 	return args.ToParam().ToBytes()
 }
 
 func (args *BuyTicketArgs) ToParam() *BuyTicketParam {
 	return &BuyTicketParam{
-		Start: uint64(*args.Start),
-		End:   uint64(*args.End),
+		Start:        uint64(*args.Start),
+		End:          uint64(*args.End),
+		Denomination: denominationParam(args.Denomination),
 	}
 }
 
@@ -186,6 +487,15 @@ func (args *BuyTicketArgs) ToData() ([]byte, error) {
 	return args.ToParam().ToBytes()
 }
 
+// denominationParam returns the Denomination param field of a ticket
+// denomination argument, empty for tickets of one ticket price
+func denominationParam(denomination *hexutil.Uint64) []uint64 {
+	if denomination == nil || *denomination <= 1 {
+		return nil
+	}
+	return []uint64{uint64(*denomination)}
+}
+
 func (args *BuyTicketArgs) Init(defStart uint64) {
 
 	if args.Start == nil {
@@ -269,10 +579,21 @@ func (args *RecallSwapArgs) ToData() ([]byte, error) {
 }
 
 func (args *TakeSwapArgs) ToParam() *TakeSwapParam {
-	return &TakeSwapParam{
+	param := &TakeSwapParam{
 		SwapID: args.SwapID,
 		Size:   args.Size,
 	}
+	if args.FillMode != nil || args.MinSize != nil {
+		fill := TakeSwapFill{MinSize: args.MinSize}
+		if args.FillMode != nil {
+			fill.Mode = *args.FillMode
+		}
+		if fill.MinSize == nil {
+			fill.MinSize = new(big.Int)
+		}
+		param.Fill = []TakeSwapFill{fill}
+	}
+	return param
 }
 
 func (args *TakeSwapArgs) ToData() ([]byte, error) {
@@ -397,6 +718,26 @@ func (args *TakeMultiSwapArgs) ToData() ([]byte, error) {
 	return args.ToParam().ToBytes()
 }
 
+func (args *TypedCallArgs) ToParam() (*TypedCallParam, error) {
+	param := &TypedCallParam{
+		Nonce:     uint64(args.Nonce),
+		Deadline:  uint64(args.Deadline),
+		Signature: args.Signature,
+	}
+	if err := rlp.DecodeBytes(args.Call, &param.Call); err != nil {
+		return nil, fmt.Errorf("decode call to FSNCallParam err %v", err)
+	}
+	return param, nil
+}
+
+func (args *TypedCallArgs) ToData() ([]byte, error) {
+	param, err := args.ToParam()
+	if err != nil {
+		return nil, err
+	}
+	return param.ToBytes()
+}
+
 func (args *TimeLockArgs) Init(timeLockType TimeLockType) {
 	args.TimeLockType = timeLockType
 
@@ -410,3 +751,165 @@ func (args *TimeLockArgs) Init(timeLockType TimeLockType) {
 		*(*uint64)(args.EndTime) = TimeLockForever
 	}
 }
+
+func (args *RevokeTicketArgs) ToParam() *RevokeTicketParam {
+	return &RevokeTicketParam{
+		TicketID: args.TicketID,
+	}
+}
+
+func (args *RevokeTicketArgs) ToData() ([]byte, error) {
+	return args.ToParam().ToBytes()
+}
+
+func (args *EscrowAssetArgs) ToParam() *EscrowAssetParam {
+	return &EscrowAssetParam{
+		AssetID:  args.AssetID,
+		To:       args.To,
+		Value:    args.Value.ToInt(),
+		Deadline: uint64(args.Deadline),
+	}
+}
+
+func (args *EscrowAssetArgs) ToData() ([]byte, error) {
+	return args.ToParam().ToBytes()
+}
+
+func (args *ClaimEscrowArgs) ToParam() *ClaimEscrowParam {
+	return &ClaimEscrowParam{
+		EscrowID: args.EscrowID,
+	}
+}
+
+func (args *ClaimEscrowArgs) ToData() ([]byte, error) {
+	return args.ToParam().ToBytes()
+}
+
+func (args *CreateStreamArgs) ToParam() *CreateStreamParam {
+	return &CreateStreamParam{
+		AssetID:   args.AssetID,
+		To:        args.To,
+		Value:     args.Value.ToInt(),
+		StartTime: uint64(args.StartTime),
+		EndTime:   uint64(args.EndTime),
+	}
+}
+
+func (args *CreateStreamArgs) ToData() ([]byte, error) {
+	return args.ToParam().ToBytes()
+}
+
+func (args *WithdrawStreamArgs) ToParam() *WithdrawStreamParam {
+	return &WithdrawStreamParam{
+		StreamID: args.StreamID,
+	}
+}
+
+func (args *WithdrawStreamArgs) ToData() ([]byte, error) {
+	return args.ToParam().ToBytes()
+}
+
+func (args *CreateConditionArgs) ToParam() *CreateConditionParam {
+	return &CreateConditionParam{
+		Kind:   args.Kind,
+		Hash:   args.Hash,
+		Oracle: args.Oracle,
+	}
+}
+
+func (args *CreateConditionArgs) ToData() ([]byte, error) {
+	return args.ToParam().ToBytes()
+}
+
+func (args *ResolveConditionArgs) ToParam() *ResolveConditionParam {
+	return &ResolveConditionParam{
+		ConditionID: args.ConditionID,
+		Preimage:    args.Preimage,
+		Signature:   args.Signature,
+	}
+}
+
+func (args *ResolveConditionArgs) ToData() ([]byte, error) {
+	return args.ToParam().ToBytes()
+}
+
+func (args *ConditionalTransferArgs) ToParam() *ConditionalTransferParam {
+	return &ConditionalTransferParam{
+		AssetID:     args.AssetID,
+		To:          args.To,
+		Value:       args.Value.ToInt(),
+		ConditionID: args.ConditionID,
+		Deadline:    uint64(args.Deadline),
+	}
+}
+
+func (args *ConditionalTransferArgs) ToData() ([]byte, error) {
+	return args.ToParam().ToBytes()
+}
+
+func (args *SettleConditionalArgs) ToParam() *SettleConditionalParam {
+	return &SettleConditionalParam{
+		TransferID: args.TransferID,
+	}
+}
+
+func (args *SettleConditionalArgs) ToData() ([]byte, error) {
+	return args.ToParam().ToBytes()
+}
+
+func (args *AttestDepositArgs) ToParam() *AttestDepositParam {
+	return &AttestDepositParam{
+		AssetID:  args.AssetID,
+		ChainID:  uint64(args.ChainID),
+		TxHash:   args.TxHash,
+		LogIndex: uint64(args.LogIndex),
+		To:       args.To,
+		Value:    args.Value.ToInt(),
+	}
+}
+
+func (args *AttestDepositArgs) ToData() ([]byte, error) {
+	return args.ToParam().ToBytes()
+}
+
+func (args *BridgeWithdrawArgs) ToParam() *BridgeWithdrawParam {
+	return &BridgeWithdrawParam{
+		AssetID:   args.AssetID,
+		ChainID:   uint64(args.ChainID),
+		Recipient: args.Recipient,
+		Value:     args.Value.ToInt(),
+	}
+}
+
+func (args *BridgeWithdrawArgs) ToData() ([]byte, error) {
+	return args.ToParam().ToBytes()
+}
+
+func (args *StakingKeyArgs) ToParam() *StakingKeyParam {
+	return &StakingKeyParam{
+		Key: args.Key,
+	}
+}
+
+func (args *StakingKeyArgs) ToData() ([]byte, error) {
+	return args.ToParam().ToBytes()
+}
+
+func (args *StakingBuyTicketArgs) ToParam() *StakingBuyTicketParam {
+	return &StakingBuyTicketParam{
+		Owner:        args.Owner,
+		Start:        uint64(*args.Start),
+		End:          uint64(*args.End),
+		Denomination: denominationParam(args.Denomination),
+	}
+}
+
+func (args *StakingBuyTicketArgs) ToData() ([]byte, error) {
+	return args.ToParam().ToBytes()
+}
+
+func (args *StakingBuyTicketArgs) Init(defStart uint64) {
+	ticket := BuyTicketArgs{Start: args.Start, End: args.End}
+	ticket.Init(defStart)
+	args.Start, args.End = ticket.Start, ticket.End
+}
@@ -0,0 +1,62 @@
+package common
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAddressOrNotationUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input    string
+		address  Address
+		notation uint64
+		wantErr  bool
+	}{
+		{input: `"0x0000000000000000000000000000000000000010"`, address: HexToAddress("0x10")},
+		{input: `10057`, notation: 10057},
+		{input: `"10057"`, notation: 10057},
+		{input: `0`, wantErr: true},
+		{input: `"abc"`, wantErr: true},
+		{input: `"0x10"`, wantErr: true},
+		{input: `-1`, wantErr: true},
+	}
+	for i, test := range tests {
+		var v AddressOrNotation
+		err := json.Unmarshal([]byte(test.input), &v)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("test %d: expected error for input %s", i, test.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+			continue
+		}
+		if v.Address != test.address || v.Notation != test.notation {
+			t.Errorf("test %d: have (%x, %d), want (%x, %d)", i, v.Address, v.Notation, test.address, test.notation)
+		}
+		if v.IsNotation() != (test.notation != 0) {
+			t.Errorf("test %d: IsNotation mismatch", i)
+		}
+	}
+}
+
+func TestAddressOrNotationMarshalJSON(t *testing.T) {
+	for _, v := range []AddressOrNotation{
+		{Address: HexToAddress("0x10")},
+		{Notation: 10057},
+	} {
+		enc, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var dec AddressOrNotation
+		if err := json.Unmarshal(enc, &dec); err != nil {
+			t.Fatalf("unable to decode %s: %v", enc, err)
+		}
+		if dec != v {
+			t.Errorf("roundtrip mismatch: have %v, want %v", dec, v)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package common
+
+import "math/big"
+
+// bridge committees of each network, a deposit attested by
+// BridgeAttestationThreshold of them is minted on Fusion. The bridge stays
+// disabled on a network until its committee and assets are set, see
+// IsBridgeConfigured.
+var (
+	MAINNET_BRIDGE_COMMITTEE = []Address{}
+	TESTNET_BRIDGE_COMMITTEE = []Address{}
+	DEVNET_BRIDGE_COMMITTEE  = []Address{}
+)
+
+// assets of each network minted and burned by the bridge
+var (
+	MAINNET_BRIDGE_ASSETS = []Hash{}
+	TESTNET_BRIDGE_ASSETS = []Hash{}
+	DEVNET_BRIDGE_ASSETS  = []Hash{}
+)
+
+// MaxBridgeRecipient is the maximum size of the external chain address a
+// withdrawal is released to
+const MaxBridgeRecipient = 64
+
+// GetBridgeCommittee returns the bridge committee of the network
+func GetBridgeCommittee() []Address {
+	if UseDevnetRule {
+		return DEVNET_BRIDGE_COMMITTEE
+	}
+	if UseTestnetRule {
+		return TESTNET_BRIDGE_COMMITTEE
+	}
+	return MAINNET_BRIDGE_COMMITTEE
+}
+
+// GetBridgeAssets returns the assets of the network minted by the bridge
+func GetBridgeAssets() []Hash {
+	if UseDevnetRule {
+		return DEVNET_BRIDGE_ASSETS
+	}
+	if UseTestnetRule {
+		return TESTNET_BRIDGE_ASSETS
+	}
+	return MAINNET_BRIDGE_ASSETS
+}
+
+// IsBridgeConfigured reports whether the network has a bridge committee and
+// bridge assets, the bridge calls fail without them even after the fork
+func IsBridgeConfigured() bool {
+	return len(GetBridgeCommittee()) > 0 && len(GetBridgeAssets()) > 0
+}
+
+// IsBridgeCommitteeMember wacom
+func IsBridgeCommitteeMember(addr Address) bool {
+	for _, member := range GetBridgeCommittee() {
+		if member == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBridgeAsset wacom
+func IsBridgeAsset(assetID Hash) bool {
+	for _, id := range GetBridgeAssets() {
+		if id == assetID {
+			return true
+		}
+	}
+	return false
+}
+
+// BridgeAttestationThreshold is the number of committee attestations a
+// deposit needs, a majority of the committee
+func BridgeAttestationThreshold() int {
+	return len(GetBridgeCommittee())/2 + 1
+}
+
+// BridgeDeposit is the state of a deposit on an external chain, Minted is the
+// height of the block which minted it, 0 while it lacks attestations. The
+// asset, receiver and value are the ones of the first attestation, the later
+// ones must match them.
+type BridgeDeposit struct {
+	AssetID      Hash
+	To           Address
+	Value        *big.Int
+	Attestations []Address
+	Minted       uint64
+}
+
+// NewBridgeDeposit returns the deposit attested first by p
+func NewBridgeDeposit(p *AttestDepositParam) *BridgeDeposit {
+	return &BridgeDeposit{AssetID: p.AssetID, To: p.To, Value: new(big.Int).Set(p.Value)}
+}
+
+// Matches reports whether p attests the asset, receiver and value of the
+// deposit
+func (d *BridgeDeposit) Matches(p *AttestDepositParam) bool {
+	return d.AssetID == p.AssetID && d.To == p.To && d.Value != nil && d.Value.Cmp(p.Value) == 0
+}
@@ -0,0 +1,72 @@
+package common
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestBridgeParams(t *testing.T) {
+	defer func(devnet bool) { UseDevnetRule = devnet }(UseDevnetRule)
+	defer func(committee []Address, assets []Hash) {
+		DEVNET_BRIDGE_COMMITTEE, DEVNET_BRIDGE_ASSETS = committee, assets
+	}(DEVNET_BRIDGE_COMMITTEE, DEVNET_BRIDGE_ASSETS)
+	UseDevnetRule = true
+	DEVNET_BRIDGE_COMMITTEE, DEVNET_BRIDGE_ASSETS = nil, []Hash{HexToHash("0x10")}
+	unconfigured := AttestDepositParam{AssetID: HexToHash("0x10"), ChainID: 1, TxHash: HexToHash("0x20"), To: HexToAddress("0x30"), Value: big.NewInt(100)}
+	if err := unconfigured.Check(big.NewInt(1)); err == nil {
+		t.Errorf("deposit accepted without a bridge committee")
+	}
+	DEVNET_BRIDGE_COMMITTEE = []Address{HexToAddress("0x01"), HexToAddress("0x02"), HexToAddress("0x03")}
+	DEVNET_BRIDGE_ASSETS = []Hash{HexToHash("0x10")}
+
+	if have := BridgeAttestationThreshold(); have != 2 {
+		t.Errorf("threshold mismatch: have %d, want 2", have)
+	}
+	if !IsBridgeCommitteeMember(HexToAddress("0x02")) || IsBridgeCommitteeMember(HexToAddress("0x04")) {
+		t.Errorf("committee membership mismatch")
+	}
+
+	deposit := AttestDepositParam{
+		AssetID: HexToHash("0x10"),
+		ChainID: 1,
+		TxHash:  HexToHash("0x20"),
+		To:      HexToAddress("0x30"),
+		Value:   big.NewInt(100),
+	}
+	if err := deposit.Check(big.NewInt(1)); err != nil {
+		t.Errorf("deposit rejected: %v", err)
+	}
+	other := deposit
+	other.LogIndex = 1
+	if deposit.DepositID() == other.DepositID() {
+		t.Errorf("deposit ID ignores the log index")
+	}
+	other = deposit
+	other.To, other.Value = HexToAddress("0x31"), big.NewInt(101)
+	if deposit.DepositID() != other.DepositID() {
+		t.Errorf("deposit ID depends on the receiver and value")
+	}
+	attested := NewBridgeDeposit(&deposit)
+	if !attested.Matches(&deposit) || attested.Matches(&other) {
+		t.Errorf("deposit matching mismatch")
+	}
+	other = deposit
+	other.AssetID = HexToHash("0x11")
+	if err := other.Check(big.NewInt(1)); err == nil {
+		t.Errorf("deposit of a non bridge asset accepted")
+	}
+
+	withdraw := BridgeWithdrawParam{
+		AssetID:   HexToHash("0x10"),
+		ChainID:   1,
+		Recipient: HexToAddress("0x40").Bytes(),
+		Value:     big.NewInt(100),
+	}
+	if err := withdraw.Check(big.NewInt(1)); err != nil {
+		t.Errorf("withdrawal rejected: %v", err)
+	}
+	withdraw.Recipient = make([]byte, MaxBridgeRecipient+1)
+	if err := withdraw.Check(big.NewInt(1)); err == nil {
+		t.Errorf("withdrawal to a long recipient accepted")
+	}
+}
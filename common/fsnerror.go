@@ -0,0 +1,145 @@
+package common
+
+import "fmt"
+
+// FsnErrorCode is the stable numeric code of an FSN call failure. The codes
+// are never renumbered, new ones are appended.
+type FsnErrorCode int
+
+// FSN call failure codes, starting at 1000 to stay clear of the JSON-RPC
+// reserved and execution error codes they are returned as
+const (
+	FsnErrUnknown FsnErrorCode = iota + 1000
+	FsnErrNotEnoughAsset
+	FsnErrNotEnoughFromAsset
+	FsnErrNotEnoughTimeLock
+	FsnErrNotEnoughTimeLockOrAsset
+	FsnErrNotEnoughBalance
+	FsnErrAssetNotFound
+	FsnErrToAssetNotFound
+	FsnErrNotAssetOwner
+	FsnErrAssetNotChangeable
+	FsnErrSwapNotFound
+	FsnErrSwapExists
+	FsnErrNotSwapOwner
+	FsnErrSwapNotationInvalid
+	FsnErrNoNotation
+	FsnErrTicketExists
+	FsnErrGenesisTicketRevoke
+	FsnErrNotTicketHolder
+	FsnErrNotEnabled
+	FsnErrAssetExists
+	FsnErrInvalidParam
+	FsnErrUnsupported
+	FsnErrStateUpdate
+	FsnErrNotAllowed
+	FsnErrAlreadyApproved
+	FsnErrExpired
+	FsnErrPending
+	FsnErrAlreadySettled
+	FsnErrMismatch
+	FsnErrLimitReached
+)
+
+var fsnErrorCodeNames = map[FsnErrorCode]string{
+	FsnErrUnknown:                  "Unknown",
+	FsnErrNotEnoughAsset:           "NotEnoughAsset",
+	FsnErrNotEnoughFromAsset:       "NotEnoughFromAsset",
+	FsnErrNotEnoughTimeLock:        "NotEnoughTimeLock",
+	FsnErrNotEnoughTimeLockOrAsset: "NotEnoughTimeLockOrAsset",
+	FsnErrNotEnoughBalance:         "NotEnoughBalance",
+	FsnErrAssetNotFound:            "AssetNotFound",
+	FsnErrToAssetNotFound:          "ToAssetNotFound",
+	FsnErrNotAssetOwner:            "NotAssetOwner",
+	FsnErrAssetNotChangeable:       "AssetNotChangeable",
+	FsnErrSwapNotFound:             "SwapNotFound",
+	FsnErrSwapExists:               "SwapExists",
+	FsnErrNotSwapOwner:             "NotSwapOwner",
+	FsnErrSwapNotationInvalid:      "SwapNotationInvalid",
+	FsnErrNoNotation:               "NoNotation",
+	FsnErrTicketExists:             "TicketExists",
+	FsnErrGenesisTicketRevoke:      "GenesisTicketRevoke",
+	FsnErrNotTicketHolder:          "NotTicketHolder",
+	FsnErrNotEnabled:               "NotEnabled",
+	FsnErrAssetExists:              "AssetExists",
+	FsnErrInvalidParam:             "InvalidParam",
+	FsnErrUnsupported:              "Unsupported",
+	FsnErrStateUpdate:              "StateUpdateFailed",
+	FsnErrNotAllowed:               "NotAllowed",
+	FsnErrAlreadyApproved:          "AlreadyApproved",
+	FsnErrExpired:                  "Expired",
+	FsnErrPending:                  "Pending",
+	FsnErrAlreadySettled:           "AlreadySettled",
+	FsnErrMismatch:                 "Mismatch",
+	FsnErrLimitReached:             "LimitReached",
+}
+
+// Name returns the name of the code, the one of FsnErrUnknown for unknown codes
+func (c FsnErrorCode) Name() string {
+	if name, ok := fsnErrorCodeNames[c]; ok {
+		return name
+	}
+	return fsnErrorCodeNames[FsnErrUnknown]
+}
+
+// FsnError is an FSN call failure with its code. Its message is the one the
+// failure always had, so that the failure logs stay the same.
+type FsnError struct {
+	Code    FsnErrorCode
+	Message string
+}
+
+// NewFsnError returns an FsnError of the given code with a specific message
+func NewFsnError(code FsnErrorCode, format string, args ...interface{}) *FsnError {
+	return &FsnError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// WrapFsnError returns err as an FsnError, with the given code unless err
+// already has one
+func WrapFsnError(code FsnErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*FsnError); ok {
+		return err
+	}
+	return &FsnError{Code: code, Message: err.Error()}
+}
+
+func (e *FsnError) Error() string { return e.Message }
+
+// ErrorCode returns the code of the failure as its JSON-RPC error code
+func (e *FsnError) ErrorCode() int { return int(e.Code) }
+
+// FSN call failures
+var (
+	ErrNotEnoughAsset           = &FsnError{FsnErrNotEnoughAsset, "not enough asset"}
+	ErrNotEnoughFromAsset       = &FsnError{FsnErrNotEnoughFromAsset, "not enough from asset"}
+	ErrNotEnoughTimeLock        = &FsnError{FsnErrNotEnoughTimeLock, "not enough time lock balance"}
+	ErrNotEnoughTimeLockOrAsset = &FsnError{FsnErrNotEnoughTimeLockOrAsset, "not enough time lock or asset balance"}
+	ErrNotEnoughBalance         = &FsnError{FsnErrNotEnoughBalance, "not enough balance"}
+	ErrAssetNotFound            = &FsnError{FsnErrAssetNotFound, "asset not found"}
+	ErrToAssetNotFound          = &FsnError{FsnErrToAssetNotFound, "ToAssetID's asset not found"}
+	ErrNotAssetOwner            = &FsnError{FsnErrNotAssetOwner, "can only be changed by owner"}
+	ErrAssetNotChangeable       = &FsnError{FsnErrAssetNotChangeable, "asset can't inc or dec"}
+	ErrSwapNotFound             = &FsnError{FsnErrSwapNotFound, "Swap not found"}
+	ErrSwapExists               = &FsnError{FsnErrSwapExists, "Swap already exist"}
+	ErrNotSwapOwner             = &FsnError{FsnErrNotSwapOwner, "Must be swap onwer can recall"}
+	ErrSwapNotationInvalid      = &FsnError{FsnErrSwapNotationInvalid, "notation in swap is no longer valid"}
+	ErrNoNotation               = &FsnError{FsnErrNoNotation, "the from address does not have a notation"}
+	ErrTicketExists             = &FsnError{FsnErrTicketExists, "Ticket already exist"}
+	ErrGenesisTicketRevoke      = &FsnError{FsnErrGenesisTicketRevoke, "genesis tickets can not be revoked"}
+	ErrUnsupported              = &FsnError{FsnErrUnsupported, "Unsupported"}
+)
+
+// FsnErrorCodeOf returns the code of an FSN call failure, FsnErrUnknown if
+// it is not an FsnError.
+func FsnErrorCodeOf(err error) FsnErrorCode {
+	if err == nil {
+		return 0
+	}
+	if e, ok := err.(*FsnError); ok {
+		return e.Code
+	}
+	return FsnErrUnknown
+}
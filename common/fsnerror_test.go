@@ -0,0 +1,40 @@
+package common
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestFsnErrorCodeOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		code FsnErrorCode
+	}{
+		{nil, 0},
+		{ErrNotEnoughAsset, FsnErrNotEnoughAsset},
+		{NewFsnError(FsnErrTicketExists, "%s Ticket already exist", "0x01"), FsnErrTicketExists},
+		{WrapFsnError(FsnErrStateUpdate, errors.New("missing trie node")), FsnErrStateUpdate},
+		{WrapFsnError(FsnErrStateUpdate, ErrSwapNotFound), FsnErrSwapNotFound},
+		{(&TimeLockItem{StartTime: 2, EndTime: 1, Value: big.NewInt(1)}).IsValid(), FsnErrInvalidParam},
+		{(&BuyTicketParam{Start: 2, End: 1}).Check(big.NewInt(1), 0), FsnErrInvalidParam},
+		{errors.New("Swap not found"), FsnErrUnknown},
+	}
+	for _, test := range tests {
+		if code := FsnErrorCodeOf(test.err); code != test.code {
+			t.Errorf("%v: code mismatch: have %d (%s), want %d (%s)", test.err, code, code.Name(), test.code, test.code.Name())
+		}
+	}
+}
+
+func TestFsnErrorCodesStable(t *testing.T) {
+	// the codes are part of the RPC interface and must never change
+	if FsnErrUnknown != 1000 || FsnErrNotEnoughAsset != 1001 || FsnErrNotEnabled != 1018 {
+		t.Fatalf("FSN error codes renumbered")
+	}
+	for code := FsnErrUnknown; code <= FsnErrLimitReached; code++ {
+		if _, ok := fsnErrorCodeNames[code]; !ok {
+			t.Errorf("code %d has no name", code)
+		}
+	}
+}
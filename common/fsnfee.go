@@ -0,0 +1,87 @@
+package common
+
+import (
+	"math/big"
+)
+
+// MaxFsnCallFee is the highest fee the governors can set for an FSN call
+var MaxFsnCallFee = new(big.Int).Mul(big.NewInt(1000), big.NewInt(1000000000000000000)) // 1000 FSN
+
+// fee schedule governors of each network, a fee change approved by
+// FeeGovernanceThreshold of them takes effect at its height. The mainnet and
+// testnet governors are set with the activation of the fee schedule fork on
+// those networks, the devnet governors are its genesis accounts.
+var (
+	MAINNET_FEE_GOVERNORS = []Address{}
+	TESTNET_FEE_GOVERNORS = []Address{}
+	DEVNET_FEE_GOVERNORS  = []Address{
+		HexToAddress("0x3a1b3b81ed061581558a81f11d63e03129347437"),
+		HexToAddress("0x0963a18ea497b7724340fdfe4ff6e060d3f9e388"),
+		HexToAddress("0x5ee5c548a649594feb24882c3deb456d36a78801"),
+	}
+)
+
+// GetFeeGovernors returns the fee schedule governors of the network
+func GetFeeGovernors() []Address {
+	if UseDevnetRule {
+		return DEVNET_FEE_GOVERNORS
+	}
+	if UseTestnetRule {
+		return TESTNET_FEE_GOVERNORS
+	}
+	return MAINNET_FEE_GOVERNORS
+}
+
+// IsFeeGovernor wacom
+func IsFeeGovernor(addr Address) bool {
+	for _, governor := range GetFeeGovernors() {
+		if governor == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// FeeGovernanceThreshold is the number of governor approvals a fee change
+// needs, a majority of the governors
+func FeeGovernanceThreshold() int {
+	return len(GetFeeGovernors())/2 + 1
+}
+
+// FsnCallFeeEntry is the fee of an FSN call set by the governors, Fee applies
+// from block Height on and Prev before it. Changes take effect in a later
+// block so that the fee of every block can be read from its state.
+type FsnCallFeeEntry struct {
+	Prev   *big.Int
+	Fee    *big.Int
+	Height uint64
+}
+
+// FeeAt returns the fee of the call in block number
+func (e *FsnCallFeeEntry) FeeAt(number *big.Int) *big.Int {
+	if number == nil || number.Uint64() >= e.Height {
+		return new(big.Int).Set(e.Fee)
+	}
+	return new(big.Int).Set(e.Prev)
+}
+
+// FsnCallFeeSchedule is the state of the fee schedule
+type FsnCallFeeSchedule interface {
+	GetFsnCallFeeEntry(funcType FSNCallFunc) *FsnCallFeeEntry
+}
+
+// GetFsnCallFeeAt returns the fee of an FSN call in block number, the fee set
+// in the schedule once enabled or else the static fee of GetFsnCallFee. A func
+// not enabled yet is unsupported and pays no fee.
+func GetFsnCallFeeAt(to *Address, funcType FSNCallFunc, number *big.Int, schedule FsnCallFeeSchedule) *big.Int {
+	if IsFsnCall(to) && !IsFsnCallFuncEnabled(funcType, number) {
+		return big.NewInt(0)
+	}
+	if !IsFsnCall(to) || !IsFsnCallFeeScheduleEnabled(number) {
+		return GetFsnCallFee(to, funcType)
+	}
+	if entry := schedule.GetFsnCallFeeEntry(funcType); entry != nil {
+		return entry.FeeAt(number)
+	}
+	return GetFsnCallFee(to, funcType)
+}
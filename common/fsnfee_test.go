@@ -0,0 +1,52 @@
+package common
+
+import (
+	"math/big"
+	"testing"
+)
+
+type testFeeSchedule map[FSNCallFunc]*FsnCallFeeEntry
+
+func (s testFeeSchedule) GetFsnCallFeeEntry(funcType FSNCallFunc) *FsnCallFeeEntry {
+	return s[funcType]
+}
+
+func TestGetFsnCallFeeAt(t *testing.T) {
+	defer func(devnet bool) { UseDevnetRule = devnet }(UseDevnetRule)
+	UseDevnetRule = true
+
+	schedule := testFeeSchedule{
+		TimeLockFunc: {Prev: big.NewInt(1), Fee: big.NewInt(2), Height: 100},
+	}
+	tests := []struct {
+		to     *Address
+		fn     FSNCallFunc
+		number int64
+		want   *big.Int
+	}{
+		{&FSNCallAddress, TimeLockFunc, 99, big.NewInt(1)},
+		{&FSNCallAddress, TimeLockFunc, 100, big.NewInt(2)},
+		{&FSNCallAddress, GenNotationFunc, 100, GetFsnCallFee(&FSNCallAddress, GenNotationFunc)},
+		{&TicketKeyAddress, TimeLockFunc, 100, big.NewInt(0)},
+	}
+	for i, test := range tests {
+		if have := GetFsnCallFeeAt(test.to, test.fn, big.NewInt(test.number), schedule); have.Cmp(test.want) != 0 {
+			t.Errorf("test %d: fee mismatch: have %v, want %v", i, have, test.want)
+		}
+	}
+}
+
+func TestGetFsnCallFeeBeforeFork(t *testing.T) {
+	number := big.NewInt(1)
+	for _, fn := range []FSNCallFunc{GenRestrictedAssetFunc, GenMultiOwnerAssetFunc, CreateProposalFunc, EscrowAssetFunc, CreateStreamFunc, CreateConditionFunc, ConditionalTransferFunc} {
+		if IsFsnCallFuncEnabled(fn, number) {
+			t.Errorf("%v enabled before its fork", fn.Name())
+		}
+		if have := GetFsnCallFeeAt(&FSNCallAddress, fn, number, testFeeSchedule{}); have.Sign() != 0 {
+			t.Errorf("%v pays %v before its fork", fn.Name(), have)
+		}
+	}
+	if have := GetFsnCallFeeAt(&FSNCallAddress, GenAssetFunc, number, testFeeSchedule{}); have.Cmp(GetFsnCallFee(&FSNCallAddress, GenAssetFunc)) != 0 {
+		t.Errorf("asset generation fee mismatch: have %v", have)
+	}
+}
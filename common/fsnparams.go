@@ -1,7 +1,6 @@
 package common
 
 import (
-	"fmt"
 	"math/big"
 
 	"github.com/FusionFoundation/go-fusion/rlp"
@@ -25,9 +24,12 @@ type GenAssetParam struct {
 }
 
 // BuyTicketParam wacom
+// Denomination is empty for tickets of one ticket price, so legacy encodings
+// are unchanged. It is ignored before the denomination fork.
 type BuyTicketParam struct {
-	Start uint64
-	End   uint64
+	Start        uint64
+	End          uint64
+	Denomination []uint64 `json:",omitempty" rlp:"tail"`
 }
 
 // SendAssetParam wacom
@@ -99,9 +101,28 @@ type RecallMultiSwapParam struct {
 }
 
 // TakeSwapParam wacom
+// Fill is empty for exact takes, so legacy encodings are unchanged. It is
+// ignored before the swap fill mode fork.
 type TakeSwapParam struct {
 	SwapID Hash
-	Size   *big.Int `json:",string"`
+	Size   *big.Int       `json:",string"`
+	Fill   []TakeSwapFill `json:",omitempty" rlp:"tail"`
+}
+
+// TakeSwapFillMode is how much of a swap a take must fill
+type TakeSwapFillMode uint8
+
+const (
+	TakeSwapExact      TakeSwapFillMode = iota // take Size, legacy takes
+	TakeSwapAtLeast                            // take up to Size but at least MinSize
+	TakeSwapFillOrKill                         // take Size only if it is the whole remaining swap
+	TakeSwapInvalidFillMode
+)
+
+// TakeSwapFill is the fill mode of a take since the swap fill mode fork
+type TakeSwapFill struct {
+	Mode    TakeSwapFillMode
+	MinSize *big.Int `json:",string"`
 }
 
 // TakeMultiSwapParam wacom
@@ -110,6 +131,168 @@ type TakeMultiSwapParam struct {
 	Size   *big.Int `json:",string"`
 }
 
+// TypedCallParam wacom
+// executes Call on behalf of the signer of its EIP-712 typed data
+type TypedCallParam struct {
+	Call      FSNCallParam
+	Nonce     uint64
+	Deadline  uint64
+	Signature []byte
+}
+
+// GenRestrictedAssetParam wacom
+// generates an asset whose transfers are restricted by a list managed by its owner
+type GenRestrictedAssetParam struct {
+	Asset       GenAssetParam
+	Restriction AssetTransferRestriction
+}
+
+// GenMultiOwnerAssetParam wacom
+// generates an asset owned by an M-of-N key set
+type GenMultiOwnerAssetParam struct {
+	Asset  GenAssetParam
+	Owners AssetOwnerSet
+}
+
+// EscrowAssetParam wacom
+// sends an asset into an escrow claimable by To until Deadline
+type EscrowAssetParam struct {
+	AssetID  Hash
+	To       Address
+	Value    *big.Int `json:",string"`
+	Deadline uint64
+}
+
+// ClaimEscrowParam wacom
+// claims an escrow, by its recipient until the deadline and by its sender after
+type ClaimEscrowParam struct {
+	EscrowID Hash
+}
+
+// CreateStreamParam wacom
+// streams Value to To, vesting linearly between StartTime and EndTime
+type CreateStreamParam struct {
+	AssetID   Hash
+	To        Address
+	Value     *big.Int `json:",string"`
+	StartTime uint64
+	EndTime   uint64
+}
+
+// WithdrawStreamParam wacom
+// withdraws the vested part of a stream to its recipient
+type WithdrawStreamParam struct {
+	StreamID Hash
+}
+
+// CreateConditionParam wacom
+// registers a condition, Hash is the hash of the preimage of a hash lock or
+// the statement expected from an oracle
+type CreateConditionParam struct {
+	Kind   ConditionKind
+	Hash   Hash
+	Oracle Address
+}
+
+// ResolveConditionParam wacom
+// resolves a condition by the preimage of its hash lock, or by the signature
+// of its oracle when not sent by the oracle itself
+type ResolveConditionParam struct {
+	ConditionID Hash
+	Preimage    []byte
+	Signature   []byte
+}
+
+// ConditionalTransferParam wacom
+// sends an asset to To once the condition is resolved before Deadline
+type ConditionalTransferParam struct {
+	AssetID     Hash
+	To          Address
+	Value       *big.Int `json:",string"`
+	ConditionID Hash
+	Deadline    uint64
+}
+
+// SettleConditionalParam wacom
+// pays a conditional transfer to its recipient or refunds it to its sender
+type SettleConditionalParam struct {
+	TransferID Hash
+}
+
+// AttestDepositParam wacom
+// attests a deposit of Value on the external chain ChainID by its transaction
+// and log index, to be minted to To
+type AttestDepositParam struct {
+	AssetID  Hash
+	ChainID  uint64
+	TxHash   Hash
+	LogIndex uint64
+	To       Address
+	Value    *big.Int `json:",string"`
+}
+
+// BridgeWithdrawParam wacom
+// burns Value to release it to Recipient on the external chain ChainID
+type BridgeWithdrawParam struct {
+	AssetID   Hash
+	ChainID   uint64
+	Recipient []byte
+	Value     *big.Int `json:",string"`
+}
+
+// AssetTransferListParam wacom
+// adds the addresses to (Listed) or removes them from the transfer list of an asset
+type AssetTransferListParam struct {
+	AssetID   Hash
+	Addresses []Address
+	Listed    bool
+}
+
+// SetFsnCallFeeParam wacom
+// approves changing the fee of Func to Fee from block Height on
+type SetFsnCallFeeParam struct {
+	Func   FSNCallFunc
+	Fee    *big.Int
+	Height uint64
+}
+
+// CreateProposalParam wacom
+// opens a proposal voted on by the ticket holders until block EndHeight
+type CreateProposalParam struct {
+	Title       string
+	Description string
+	EndHeight   uint64
+}
+
+// VoteProposalParam wacom
+// votes for or against a proposal with the tickets of the sender, a later
+// vote replaces the earlier one
+type VoteProposalParam struct {
+	ProposalID Hash
+	Approve    bool
+}
+
+// RevokeTicketParam wacom
+// cancels an unexpired ticket of the sender and refunds its value as a time lock
+type RevokeTicketParam struct {
+	TicketID Hash
+}
+
+// StakingKeyParam wacom
+// authorizes Key to buy tickets for the sender, zero Key revokes it
+type StakingKeyParam struct {
+	Key Address
+}
+
+// StakingBuyTicketParam wacom
+// buys a ticket for Owner, sent by the staking key of Owner
+type StakingBuyTicketParam struct {
+	Owner        Address
+	Start        uint64
+	End          uint64
+	Denomination []uint64 `json:",omitempty" rlp:"tail"`
+}
+
 /////////////////// param ToBytes ///////////////////////
 // ToBytes wacom
 func (p *FSNCallParam) ToBytes() ([]byte, error) {
@@ -171,6 +354,121 @@ func (p *TakeMultiSwapParam) ToBytes() ([]byte, error) {
 	return rlp.EncodeToBytes(p)
 }
 
+// ToBytes wacom
+func (p *TypedCallParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *StakingKeyParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *StakingBuyTicketParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *GenRestrictedAssetParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *GenMultiOwnerAssetParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *EscrowAssetParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *ClaimEscrowParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *CreateStreamParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *WithdrawStreamParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *CreateConditionParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *ResolveConditionParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *ConditionalTransferParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *SettleConditionalParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *AttestDepositParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *BridgeWithdrawParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *AssetTransferListParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *SetFsnCallFeeParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *CreateProposalParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *VoteProposalParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// ToBytes wacom
+func (p *RevokeTicketParam) ToBytes() ([]byte, error) {
+	return rlp.EncodeToBytes(p)
+}
+
+// Hash identifies the fee change approved by the governors
+func (p *SetFsnCallFeeParam) Hash() Hash {
+	data, _ := p.ToBytes()
+	return Keccak256Hash(data)
+}
+
+// DepositID returns the ID of the attested deposit, its transaction and log
+// index on the external chain. The asset, receiver and value are not part of
+// it, an attestation disagreeing on them is rejected instead of starting
+// another deposit.
+func (p *AttestDepositParam) DepositID() Hash {
+	data, _ := rlp.EncodeToBytes([]interface{}{p.ChainID, p.TxHash, p.LogIndex})
+	return Keccak256Hash([]byte("bridgeDeposit"), data)
+}
+
 type EmptyParam struct{}
 
 func (p *EmptyParam) ToBytes() ([]byte, error) {
@@ -181,7 +479,7 @@ func DecodeFsnCallParam(fsnCall *FSNCallParam, funcParam interface{}) (interface
 	if len(fsnCall.Data) != 0 {
 		err := rlp.DecodeBytes(fsnCall.Data, funcParam)
 		if err != nil {
-			return nil, fmt.Errorf("decode FSNCallParam err %v", err)
+			return nil, NewFsnError(FsnErrInvalidParam, "decode FSNCallParam err %v", err)
 		}
 	}
 	decodedParam := &struct {
@@ -198,7 +496,7 @@ func DecodeTxInput(input []byte) (interface{}, error) {
 	var fsnCall FSNCallParam
 	err := rlp.DecodeBytes(input, &fsnCall)
 	if err != nil {
-		return nil, fmt.Errorf("decode to FSNCallParam err %v", err)
+		return nil, NewFsnError(FsnErrInvalidParam, "decode to FSNCallParam err %v", err)
 	}
 
 	switch fsnCall.Func {
@@ -228,9 +526,49 @@ func DecodeTxInput(input []byte) (interface{}, error) {
 	case TakeMultiSwapFunc:
 		return DecodeFsnCallParam(&fsnCall, &TakeMultiSwapParam{})
 	case ReportIllegalFunc:
-		return fsnCall, fmt.Errorf("ReportIllegal should processed by datong.DecodeTxInput")
+		return fsnCall, NewFsnError(FsnErrInvalidParam, "ReportIllegal should processed by datong.DecodeTxInput")
+	case TypedCallFunc:
+		return DecodeFsnCallParam(&fsnCall, &TypedCallParam{})
+	case StakingKeyFunc:
+		return DecodeFsnCallParam(&fsnCall, &StakingKeyParam{})
+	case StakingBuyTicketFunc:
+		return DecodeFsnCallParam(&fsnCall, &StakingBuyTicketParam{})
+	case GenRestrictedAssetFunc:
+		return DecodeFsnCallParam(&fsnCall, &GenRestrictedAssetParam{})
+	case AssetTransferListFunc:
+		return DecodeFsnCallParam(&fsnCall, &AssetTransferListParam{})
+	case SetFsnCallFeeFunc:
+		return DecodeFsnCallParam(&fsnCall, &SetFsnCallFeeParam{})
+	case CreateProposalFunc:
+		return DecodeFsnCallParam(&fsnCall, &CreateProposalParam{})
+	case VoteProposalFunc:
+		return DecodeFsnCallParam(&fsnCall, &VoteProposalParam{})
+	case RevokeTicketFunc:
+		return DecodeFsnCallParam(&fsnCall, &RevokeTicketParam{})
+	case GenMultiOwnerAssetFunc:
+		return DecodeFsnCallParam(&fsnCall, &GenMultiOwnerAssetParam{})
+	case EscrowAssetFunc:
+		return DecodeFsnCallParam(&fsnCall, &EscrowAssetParam{})
+	case ClaimEscrowFunc:
+		return DecodeFsnCallParam(&fsnCall, &ClaimEscrowParam{})
+	case CreateStreamFunc:
+		return DecodeFsnCallParam(&fsnCall, &CreateStreamParam{})
+	case WithdrawStreamFunc:
+		return DecodeFsnCallParam(&fsnCall, &WithdrawStreamParam{})
+	case CreateConditionFunc:
+		return DecodeFsnCallParam(&fsnCall, &CreateConditionParam{})
+	case ResolveConditionFunc:
+		return DecodeFsnCallParam(&fsnCall, &ResolveConditionParam{})
+	case ConditionalTransferFunc:
+		return DecodeFsnCallParam(&fsnCall, &ConditionalTransferParam{})
+	case SettleConditionalFunc:
+		return DecodeFsnCallParam(&fsnCall, &SettleConditionalParam{})
+	case AttestDepositFunc:
+		return DecodeFsnCallParam(&fsnCall, &AttestDepositParam{})
+	case BridgeWithdrawFunc:
+		return DecodeFsnCallParam(&fsnCall, &BridgeWithdrawParam{})
 	}
-	return nil, fmt.Errorf("Unknown FuncType %v", fsnCall.Func)
+	return nil, NewFsnError(FsnErrInvalidParam, "Unknown FuncType %v", fsnCall.Func)
 }
 
 /////////////////// param checking ///////////////////////
@@ -242,34 +580,340 @@ func (p *FSNCallParam) Check(blockNumber *big.Int) error {
 // Check wacom
 func (p *GenAssetParam) Check(blockNumber *big.Int) error {
 	if len(p.Name) == 0 || len(p.Symbol) == 0 || p.Total == nil || p.Total.Cmp(Big0) < 0 {
-		return fmt.Errorf("GenAssetFunc name, symbol and total must be set")
+		return NewFsnError(FsnErrInvalidParam, "GenAssetFunc name, symbol and total must be set")
 	}
 	if p.Decimals > 18 {
-		return fmt.Errorf("GenAssetFunc decimals must be between 0 and 18")
+		return NewFsnError(FsnErrInvalidParam, "GenAssetFunc decimals must be between 0 and 18")
 	}
 	if len(p.Description) > 1024 {
-		return fmt.Errorf("GenAsset description length is greater than 1024 chars")
+		return NewFsnError(FsnErrInvalidParam, "GenAsset description length is greater than 1024 chars")
 	}
 	if len(p.Name) > 128 {
-		return fmt.Errorf("GenAsset name length is greater than 128 chars")
+		return NewFsnError(FsnErrInvalidParam, "GenAsset name length is greater than 128 chars")
 	}
 	if len(p.Symbol) > 64 {
-		return fmt.Errorf("GenAsset symbol length is greater than 64 chars")
+		return NewFsnError(FsnErrInvalidParam, "GenAsset symbol length is greater than 64 chars")
 
 	}
+	if IsAssetSymbolRegistryEnabled(blockNumber) {
+		for i := 0; i < len(p.Name); i++ {
+			if !IsAssetNameByte(p.Name[i]) {
+				return NewFsnError(FsnErrInvalidParam, "GenAsset name must be printable ASCII")
+			}
+		}
+		for i := 0; i < len(p.Symbol); i++ {
+			if !IsAssetSymbolByte(p.Symbol[i]) {
+				return NewFsnError(FsnErrInvalidParam, "GenAsset symbol must be ASCII letters, digits, '-', '.' or '_'")
+			}
+		}
+	}
+	return nil
+}
+
+// Check wacom
+func (p *GenRestrictedAssetParam) Check(blockNumber *big.Int) error {
+	if !IsAssetTransferRestrictionEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "asset transfer restriction is not enabled")
+	}
+	if p.Restriction != AssetTransferWhitelist && p.Restriction != AssetTransferBlacklist {
+		return NewFsnError(FsnErrInvalidParam, "unknown asset transfer restriction %v", p.Restriction)
+	}
+	return p.Asset.Check(blockNumber)
+}
+
+// Check wacom
+func (p *GenMultiOwnerAssetParam) Check(blockNumber *big.Int) error {
+	if !IsAssetMultiOwnerEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "multi owner assets are not enabled")
+	}
+	owners := p.Owners.Owners
+	if len(owners) == 0 || len(owners) > MaxAssetOwners {
+		return NewFsnError(FsnErrInvalidParam, "the number of owners must be between 1 and %d", MaxAssetOwners)
+	}
+	if p.Owners.Threshold == 0 || p.Owners.Threshold > uint64(len(owners)) {
+		return NewFsnError(FsnErrInvalidParam, "the threshold must be between 1 and the number of owners")
+	}
+	seen := make(map[Address]bool, len(owners))
+	for _, owner := range owners {
+		if owner == (Address{}) || seen[owner] {
+			return NewFsnError(FsnErrInvalidParam, "owner %v is empty or duplicated", owner.String())
+		}
+		seen[owner] = true
+	}
+	return p.Asset.Check(blockNumber)
+}
+
+// Check wacom
+func (p *AssetTransferListParam) Check(blockNumber *big.Int) error {
+	if !IsAssetTransferRestrictionEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "asset transfer restriction is not enabled")
+	}
+	if p.AssetID == (Hash{}) {
+		return NewFsnError(FsnErrInvalidParam, "empty asset ID")
+	}
+	if len(p.Addresses) == 0 || len(p.Addresses) > MaxAssetTransferListChange {
+		return NewFsnError(FsnErrInvalidParam, "the number of addresses must be between 1 and %d", MaxAssetTransferListChange)
+	}
+	return nil
+}
+
+// Check wacom
+func (p *SetFsnCallFeeParam) Check(blockNumber *big.Int) error {
+	if !IsFsnCallFeeScheduleEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "fee schedule is not enabled")
+	}
+	if p.Func.Name() == "Unknown" || p.Func == SetFsnCallFeeFunc {
+		return NewFsnError(FsnErrInvalidParam, "the fee of %v can not be set", p.Func.Name())
+	}
+	if p.Fee == nil || p.Fee.Sign() < 0 || p.Fee.Cmp(MaxFsnCallFee) > 0 {
+		return NewFsnError(FsnErrInvalidParam, "Fee must be between 0 and %v", MaxFsnCallFee)
+	}
+	if blockNumber != nil && p.Height <= blockNumber.Uint64() {
+		return NewFsnError(FsnErrInvalidParam, "Height must be greater than the current block number")
+	}
+	return nil
+}
+
+// Check wacom
+func (p *CreateProposalParam) Check(blockNumber *big.Int) error {
+	if !IsGovernanceEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "governance is not enabled")
+	}
+	if len(p.Title) == 0 || len(p.Title) > MaxProposalTitle {
+		return NewFsnError(FsnErrInvalidParam, "proposal title length must be between 1 and %d chars", MaxProposalTitle)
+	}
+	if len(p.Description) > MaxProposalDescription {
+		return NewFsnError(FsnErrInvalidParam, "proposal description length is greater than %d chars", MaxProposalDescription)
+	}
+	if blockNumber != nil {
+		number := blockNumber.Uint64()
+		if p.EndHeight < number+MinProposalVotingBlocks || p.EndHeight > number+MaxProposalVotingBlocks {
+			return NewFsnError(FsnErrInvalidParam, "proposal EndHeight must be between %d and %d blocks ahead", MinProposalVotingBlocks, MaxProposalVotingBlocks)
+		}
+	}
+	return nil
+}
+
+// Check wacom
+func (p *VoteProposalParam) Check(blockNumber *big.Int) error {
+	if !IsGovernanceEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "governance is not enabled")
+	}
+	if p.ProposalID == (Hash{}) {
+		return NewFsnError(FsnErrInvalidParam, "empty proposal ID")
+	}
+	return nil
+}
+
+// Check wacom
+func (p *RevokeTicketParam) Check(blockNumber *big.Int) error {
+	if !IsTicketRevokeEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "ticket revoke is not enabled")
+	}
+	if p.TicketID == (Hash{}) {
+		return NewFsnError(FsnErrInvalidParam, "empty ticket ID")
+	}
+	return nil
+}
+
+// Check wacom
+func (p *EscrowAssetParam) Check(blockNumber *big.Int, timestamp uint64) error {
+	if !IsAssetEscrowEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "asset escrow is not enabled")
+	}
+	if p.Value == nil || p.Value.Cmp(Big0) <= 0 {
+		return NewFsnError(FsnErrInvalidParam, "Value must be set and greater than 0")
+	}
+	if p.To == (Address{}) {
+		return NewFsnError(FsnErrInvalidParam, "receiver address must be set and not zero address")
+	}
+	if p.AssetID == (Hash{}) {
+		return NewFsnError(FsnErrInvalidParam, "empty asset ID, 'asset' must be specified instead of AssetID.")
+	}
+	if p.Deadline <= timestamp {
+		return NewFsnError(FsnErrInvalidParam, "escrow deadline %d is not in the future", p.Deadline)
+	}
+	return nil
+}
+
+// Check wacom
+func (p *ClaimEscrowParam) Check(blockNumber *big.Int) error {
+	if !IsAssetEscrowEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "asset escrow is not enabled")
+	}
+	if p.EscrowID == (Hash{}) {
+		return NewFsnError(FsnErrInvalidParam, "empty escrow ID")
+	}
+	return nil
+}
+
+// Check wacom
+func (p *CreateStreamParam) Check(blockNumber *big.Int, timestamp uint64) error {
+	if !IsPaymentStreamEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "payment streams are not enabled")
+	}
+	if p.Value == nil || p.Value.Cmp(Big0) <= 0 {
+		return NewFsnError(FsnErrInvalidParam, "Value must be set and greater than 0")
+	}
+	if p.To == (Address{}) {
+		return NewFsnError(FsnErrInvalidParam, "receiver address must be set and not zero address")
+	}
+	if p.AssetID == (Hash{}) {
+		return NewFsnError(FsnErrInvalidParam, "empty asset ID, 'asset' must be specified instead of AssetID.")
+	}
+	if p.StartTime >= p.EndTime {
+		return NewFsnError(FsnErrInvalidParam, "stream start time %d must be before its end time %d", p.StartTime, p.EndTime)
+	}
+	if p.EndTime <= timestamp {
+		return NewFsnError(FsnErrInvalidParam, "stream end time %d is not in the future", p.EndTime)
+	}
+	return nil
+}
+
+// Check wacom
+func (p *WithdrawStreamParam) Check(blockNumber *big.Int) error {
+	if !IsPaymentStreamEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "payment streams are not enabled")
+	}
+	if p.StreamID == (Hash{}) {
+		return NewFsnError(FsnErrInvalidParam, "empty stream ID")
+	}
+	return nil
+}
+
+// Check wacom
+func (p *CreateConditionParam) Check(blockNumber *big.Int) error {
+	if !IsConditionalTransferEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "conditional transfers are not enabled")
+	}
+	if p.Hash == (Hash{}) {
+		return NewFsnError(FsnErrInvalidParam, "empty condition hash")
+	}
+	switch p.Kind {
+	case HashLockCondition:
+		if p.Oracle != (Address{}) {
+			return NewFsnError(FsnErrInvalidParam, "hash lock condition with an oracle")
+		}
+	case OracleCondition:
+		if p.Oracle == (Address{}) {
+			return NewFsnError(FsnErrInvalidParam, "oracle condition without an oracle")
+		}
+	default:
+		return NewFsnError(FsnErrInvalidParam, "unknown condition kind %v", p.Kind)
+	}
+	return nil
+}
+
+// Check wacom
+func (p *ResolveConditionParam) Check(blockNumber *big.Int) error {
+	if !IsConditionalTransferEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "conditional transfers are not enabled")
+	}
+	if p.ConditionID == (Hash{}) {
+		return NewFsnError(FsnErrInvalidParam, "empty condition ID")
+	}
+	if len(p.Preimage) > MaxConditionPreimage {
+		return NewFsnError(FsnErrInvalidParam, "preimage longer than %d bytes", MaxConditionPreimage)
+	}
+	if len(p.Signature) != 0 && len(p.Signature) != 65 {
+		return NewFsnError(FsnErrInvalidParam, "invalid signature length %d", len(p.Signature))
+	}
+	return nil
+}
+
+// Check wacom
+func (p *ConditionalTransferParam) Check(blockNumber *big.Int, timestamp uint64) error {
+	if !IsConditionalTransferEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "conditional transfers are not enabled")
+	}
+	if p.Value == nil || p.Value.Cmp(Big0) <= 0 {
+		return NewFsnError(FsnErrInvalidParam, "Value must be set and greater than 0")
+	}
+	if p.To == (Address{}) {
+		return NewFsnError(FsnErrInvalidParam, "receiver address must be set and not zero address")
+	}
+	if p.AssetID == (Hash{}) {
+		return NewFsnError(FsnErrInvalidParam, "empty asset ID, 'asset' must be specified instead of AssetID.")
+	}
+	if p.ConditionID == (Hash{}) {
+		return NewFsnError(FsnErrInvalidParam, "empty condition ID")
+	}
+	if p.Deadline <= timestamp {
+		return NewFsnError(FsnErrInvalidParam, "transfer deadline %d is not in the future", p.Deadline)
+	}
+	return nil
+}
+
+// Check wacom
+func (p *SettleConditionalParam) Check(blockNumber *big.Int) error {
+	if !IsConditionalTransferEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "conditional transfers are not enabled")
+	}
+	if p.TransferID == (Hash{}) {
+		return NewFsnError(FsnErrInvalidParam, "empty transfer ID")
+	}
+	return nil
+}
+
+// Check wacom
+func (p *AttestDepositParam) Check(blockNumber *big.Int) error {
+	if !IsBridgeEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "bridge is not enabled")
+	}
+	if !IsBridgeConfigured() {
+		return NewFsnError(FsnErrInvalidParam, "bridge is not configured on this network")
+	}
+	if !IsBridgeAsset(p.AssetID) {
+		return NewFsnError(FsnErrInvalidParam, "asset %v is not a bridge asset", p.AssetID.Hex())
+	}
+	if p.ChainID == 0 {
+		return NewFsnError(FsnErrInvalidParam, "empty chain ID")
+	}
+	if p.TxHash == (Hash{}) {
+		return NewFsnError(FsnErrInvalidParam, "empty deposit transaction hash")
+	}
+	if p.To == (Address{}) {
+		return NewFsnError(FsnErrInvalidParam, "receiver address must be set and not zero address")
+	}
+	if p.Value == nil || p.Value.Cmp(Big0) <= 0 {
+		return NewFsnError(FsnErrInvalidParam, "Value must be set and greater than 0")
+	}
+	return nil
+}
+
+// Check wacom
+func (p *BridgeWithdrawParam) Check(blockNumber *big.Int) error {
+	if !IsBridgeEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "bridge is not enabled")
+	}
+	if !IsBridgeConfigured() {
+		return NewFsnError(FsnErrInvalidParam, "bridge is not configured on this network")
+	}
+	if !IsBridgeAsset(p.AssetID) {
+		return NewFsnError(FsnErrInvalidParam, "asset %v is not a bridge asset", p.AssetID.Hex())
+	}
+	if p.ChainID == 0 {
+		return NewFsnError(FsnErrInvalidParam, "empty chain ID")
+	}
+	if len(p.Recipient) == 0 || len(p.Recipient) > MaxBridgeRecipient {
+		return NewFsnError(FsnErrInvalidParam, "recipient must be between 1 and %d bytes", MaxBridgeRecipient)
+	}
+	if p.Value == nil || p.Value.Cmp(Big0) <= 0 {
+		return NewFsnError(FsnErrInvalidParam, "Value must be set and greater than 0")
+	}
 	return nil
 }
 
 // Check wacom
 func (p *SendAssetParam) Check(blockNumber *big.Int) error {
 	if p.Value == nil || p.Value.Cmp(Big0) <= 0 {
-		return fmt.Errorf("Value must be set and greater than 0")
+		return NewFsnError(FsnErrInvalidParam, "Value must be set and greater than 0")
 	}
 	if p.To == (Address{}) {
-		return fmt.Errorf("receiver address must be set and not zero address")
+		return NewFsnError(FsnErrInvalidParam, "receiver address must be set and not zero address")
 	}
 	if p.AssetID == (Hash{}) {
-		return fmt.Errorf("empty asset ID, 'asset' must be specified instead of AssetID.")
+		return NewFsnError(FsnErrInvalidParam, "empty asset ID, 'asset' must be specified instead of AssetID.")
 	}
 	return nil
 }
@@ -278,13 +922,13 @@ func (p *SendAssetParam) Check(blockNumber *big.Int) error {
 func (p *TimeLockParam) Check(blockNumber *big.Int, timestamp uint64) error {
 
 	if p.Value == nil || p.Value.Cmp(Big0) <= 0 {
-		return fmt.Errorf("Value must be set and greater than 0")
+		return NewFsnError(FsnErrInvalidParam, "Value must be set and greater than 0")
 	}
 	if p.StartTime > p.EndTime {
-		return fmt.Errorf("StartTime must be less than or equal to EndTime")
+		return NewFsnError(FsnErrInvalidParam, "StartTime must be less than or equal to EndTime")
 	}
 	if p.EndTime < timestamp {
-		return fmt.Errorf("EndTime must be greater than latest block time")
+		return NewFsnError(FsnErrInvalidParam, "EndTime must be greater than latest block time")
 	}
 
 	return nil
@@ -292,38 +936,64 @@ func (p *TimeLockParam) Check(blockNumber *big.Int, timestamp uint64) error {
 
 // Check wacom
 func (p *BuyTicketParam) Check(blockNumber *big.Int, timestamp uint64) error {
+	if len(p.Denomination) != 0 && IsTicketDenominationEnabled(blockNumber) {
+		if err := p.checkDenomination(); err != nil {
+			return err
+		}
+	}
 	start, end := p.Start, p.End
 	// check lifetime too short ticket
 	if end <= start || end < start+30*24*3600 {
-		return fmt.Errorf("BuyTicket end must be greater than start + 1 month")
+		return NewFsnError(FsnErrInvalidParam, "BuyTicket end must be greater than start + 1 month")
 	}
 	if timestamp != 0 {
 		// check future ticket
 		if start > timestamp+3*3600 {
-			return fmt.Errorf("BuyTicket start must be lower than latest block time + 3 hour")
+			return NewFsnError(FsnErrInvalidParam, "BuyTicket start must be lower than latest block time + 3 hour")
 		}
 		// check ticket lifetime
 		if IsHardFork(2, blockNumber) {
 			// use 29 days here to check lifetime, to relax auto buy ticket tx checking in txpool
 			if end < timestamp+29*24*3600 {
-				return fmt.Errorf("BuyTicket end must be greater than latest block time + 1 month")
+				return NewFsnError(FsnErrInvalidParam, "BuyTicket end must be greater than latest block time + 1 month")
 			}
 		} else {
 			if end < timestamp+7*24*3600 {
-				return fmt.Errorf("BuyTicket end must be greater than latest block time + 1 week")
+				return NewFsnError(FsnErrInvalidParam, "BuyTicket end must be greater than latest block time + 1 week")
 			}
 		}
 	}
 	return nil
 }
 
+func (p *BuyTicketParam) checkDenomination() error {
+	if len(p.Denomination) > 1 {
+		return NewFsnError(FsnErrInvalidParam, "only one ticket denomination is allowed")
+	}
+	for _, d := range TicketDenominations {
+		if d == p.Denomination[0] {
+			return nil
+		}
+	}
+	return NewFsnError(FsnErrInvalidParam, "ticket denomination must be one of %v", TicketDenominations)
+}
+
+// Weight returns the number of ticket prices the ticket bought in block number
+// is worth, always 1 before the denomination fork
+func (p *BuyTicketParam) Weight(blockNumber *big.Int) uint64 {
+	if len(p.Denomination) == 0 || p.Denomination[0] == 0 || !IsTicketDenominationEnabled(blockNumber) {
+		return 1
+	}
+	return p.Denomination[0]
+}
+
 // Check wacom
 func (p *AssetValueChangeExParam) Check(blockNumber *big.Int) error {
 	if p.Value == nil || p.Value.Cmp(Big0) <= 0 {
-		return fmt.Errorf("Value must be set and greater than 0")
+		return NewFsnError(FsnErrInvalidParam, "Value must be set and greater than 0")
 	}
 	if len(p.TransacData) > 256 {
-		return fmt.Errorf("TransacData must not be greater than 256")
+		return NewFsnError(FsnErrInvalidParam, "TransacData must not be greater than 256")
 	}
 	return nil
 }
@@ -333,37 +1003,37 @@ func (p *MakeSwapParam) Check(blockNumber *big.Int, timestamp uint64) error {
 	if p.MinFromAmount == nil || p.MinFromAmount.Cmp(Big0) <= 0 ||
 		p.MinToAmount == nil || p.MinToAmount.Cmp(Big0) <= 0 ||
 		p.SwapSize == nil || p.SwapSize.Cmp(Big0) <= 0 {
-		return fmt.Errorf("MinFromAmount,MinToAmount and SwapSize must be ge 1")
+		return NewFsnError(FsnErrInvalidParam, "MinFromAmount,MinToAmount and SwapSize must be ge 1")
 	}
 	if len(p.Description) > 1024 {
-		return fmt.Errorf("MakeSwap description length is greater than 1024 chars")
+		return NewFsnError(FsnErrInvalidParam, "MakeSwap description length is greater than 1024 chars")
 	}
 	total := new(big.Int).Mul(p.MinFromAmount, p.SwapSize)
 	if total.Cmp(Big0) <= 0 {
-		return fmt.Errorf("size * MinFromAmount too large")
+		return NewFsnError(FsnErrInvalidParam, "size * MinFromAmount too large")
 	}
 
 	toTotal := new(big.Int).Mul(p.MinToAmount, p.SwapSize)
 	if toTotal.Cmp(Big0) <= 0 {
-		return fmt.Errorf("size * MinToAmount too large")
+		return NewFsnError(FsnErrInvalidParam, "size * MinToAmount too large")
 	}
 
 	if p.FromStartTime > p.FromEndTime {
-		return fmt.Errorf("MakeSwap FromStartTime > FromEndTime")
+		return NewFsnError(FsnErrInvalidParam, "MakeSwap FromStartTime > FromEndTime")
 	}
 	if p.ToStartTime > p.ToEndTime {
-		return fmt.Errorf("MakeSwap ToStartTime > ToEndTime")
+		return NewFsnError(FsnErrInvalidParam, "MakeSwap ToStartTime > ToEndTime")
 	}
 
 	if p.FromEndTime <= timestamp {
-		return fmt.Errorf("MakeSwap FromEndTime <= latest blockTime")
+		return NewFsnError(FsnErrInvalidParam, "MakeSwap FromEndTime <= latest blockTime")
 	}
 	if p.ToEndTime <= timestamp {
-		return fmt.Errorf("MakeSwap ToEndTime <= latest blockTime")
+		return NewFsnError(FsnErrInvalidParam, "MakeSwap ToEndTime <= latest blockTime")
 	}
 
 	if p.ToAssetID == OwnerUSANAssetID {
-		return fmt.Errorf("USAN's cannot be swapped")
+		return NewFsnError(FsnErrInvalidParam, "USAN's cannot be swapped")
 	}
 
 	return nil
@@ -376,91 +1046,149 @@ func (p *RecallSwapParam) Check(blockNumber *big.Int, swap *Swap) error {
 
 // Check wacom
 func (p *TakeSwapParam) Check(blockNumber *big.Int, swap *Swap, timestamp uint64) error {
-	if p.Size == nil || p.Size.Cmp(Big0) <= 0 ||
-		swap.SwapSize == nil || p.Size.Cmp(swap.SwapSize) > 0 {
+	fill := p.fill(blockNumber)
+	if len(fill) == 0 || fill[0].Mode == TakeSwapExact {
+		if p.Size == nil || p.Size.Cmp(Big0) <= 0 ||
+			swap.SwapSize == nil || p.Size.Cmp(swap.SwapSize) > 0 {
 
-		return fmt.Errorf("Size must be ge 1 and le Swapsize")
+			return NewFsnError(FsnErrInvalidParam, "Size must be ge 1 and le Swapsize")
+		}
+	}
+	if len(fill) != 0 {
+		if err := p.checkFill(swap); err != nil {
+			return err
+		}
 	}
 
 	if swap.FromEndTime <= timestamp {
-		return fmt.Errorf("swap expired: FromEndTime <= latest blockTime")
+		return NewFsnError(FsnErrInvalidParam, "swap expired: FromEndTime <= latest blockTime")
 	}
 	if swap.ToEndTime <= timestamp {
-		return fmt.Errorf("swap expired: ToEndTime <= latest blockTime")
+		return NewFsnError(FsnErrInvalidParam, "swap expired: ToEndTime <= latest blockTime")
 	}
 
 	return nil
 }
 
+// fill returns the fill modes of a take in block number, none before the
+// swap fill mode fork
+func (p *TakeSwapParam) fill(blockNumber *big.Int) []TakeSwapFill {
+	if !IsSwapFillModeEnabled(blockNumber) {
+		return nil
+	}
+	return p.Fill
+}
+
+func (p *TakeSwapParam) checkFill(swap *Swap) error {
+	if len(p.Fill) > 1 {
+		return NewFsnError(FsnErrInvalidParam, "only one fill mode is allowed")
+	}
+	if p.Size == nil || p.Size.Cmp(Big0) <= 0 || swap.SwapSize == nil {
+		return NewFsnError(FsnErrInvalidParam, "Size must be ge 1")
+	}
+	fill := p.Fill[0]
+	switch fill.Mode {
+	case TakeSwapExact:
+		if fill.MinSize != nil && fill.MinSize.Sign() != 0 {
+			return NewFsnError(FsnErrInvalidParam, "MinSize is only allowed for at least fills")
+		}
+	case TakeSwapAtLeast:
+		if fill.MinSize == nil || fill.MinSize.Cmp(Big0) <= 0 || fill.MinSize.Cmp(p.Size) > 0 {
+			return NewFsnError(FsnErrInvalidParam, "MinSize must be ge 1 and le Size")
+		}
+		if swap.SwapSize.Cmp(fill.MinSize) < 0 {
+			return NewFsnError(FsnErrInvalidParam, "Swapsize %v is less than MinSize %v", swap.SwapSize, fill.MinSize)
+		}
+	case TakeSwapFillOrKill:
+		if fill.MinSize != nil && fill.MinSize.Sign() != 0 {
+			return NewFsnError(FsnErrInvalidParam, "MinSize is only allowed for at least fills")
+		}
+		if p.Size.Cmp(swap.SwapSize) != 0 {
+			return NewFsnError(FsnErrInvalidParam, "fill or kill Size %v is not the Swapsize %v", p.Size, swap.SwapSize)
+		}
+	default:
+		return NewFsnError(FsnErrInvalidParam, "unknown fill mode %v", fill.Mode)
+	}
+	return nil
+}
+
+// FillSize returns the size a take checked in block number takes from the swap
+func (p *TakeSwapParam) FillSize(blockNumber *big.Int, swap *Swap) *big.Int {
+	if fill := p.fill(blockNumber); len(fill) != 0 && fill[0].Mode == TakeSwapAtLeast && swap.SwapSize.Cmp(p.Size) < 0 {
+		return new(big.Int).Set(swap.SwapSize)
+	}
+	return p.Size
+}
+
 // Check wacom
 func (p *MakeMultiSwapParam) Check(blockNumber *big.Int, timestamp uint64) error {
 	if p.MinFromAmount == nil || len(p.MinFromAmount) == 0 {
-		return fmt.Errorf("MinFromAmount must be specified")
+		return NewFsnError(FsnErrInvalidParam, "MinFromAmount must be specified")
 	}
 	if p.MinToAmount == nil || len(p.MinToAmount) == 0 {
-		return fmt.Errorf("MinToAmount must be specified")
+		return NewFsnError(FsnErrInvalidParam, "MinToAmount must be specified")
 	}
 	if p.SwapSize == nil || p.SwapSize.Cmp(Big0) <= 0 {
-		return fmt.Errorf("SwapSize must be ge 1")
+		return NewFsnError(FsnErrInvalidParam, "SwapSize must be ge 1")
 	}
 
 	if len(p.MinFromAmount) != len(p.FromEndTime) ||
 		len(p.MinFromAmount) != len(p.FromAssetID) ||
 		len(p.MinFromAmount) != len(p.FromStartTime) {
-		return fmt.Errorf("MinFromAmount FromEndTime and FromStartTime array length must be same size")
+		return NewFsnError(FsnErrInvalidParam, "MinFromAmount FromEndTime and FromStartTime array length must be same size")
 	}
 	if len(p.MinToAmount) != len(p.ToEndTime) ||
 		len(p.MinToAmount) != len(p.ToAssetID) ||
 		len(p.MinToAmount) != len(p.ToStartTime) {
-		return fmt.Errorf("MinToAmount ToEndTime and ToStartTime array length must be same size")
+		return NewFsnError(FsnErrInvalidParam, "MinToAmount ToEndTime and ToStartTime array length must be same size")
 	}
 
 	ln := len(p.MinFromAmount)
 	for i := 0; i < ln; i++ {
 		if p.MinFromAmount[i] == nil || p.MinFromAmount[i].Cmp(Big0) <= 0 {
-			return fmt.Errorf("MinFromAmounts must be ge 1")
+			return NewFsnError(FsnErrInvalidParam, "MinFromAmounts must be ge 1")
 		}
 		total := new(big.Int).Mul(p.MinFromAmount[i], p.SwapSize)
 		if total.Cmp(Big0) <= 0 {
-			return fmt.Errorf("size * MinFromAmount too large")
+			return NewFsnError(FsnErrInvalidParam, "size * MinFromAmount too large")
 		}
 		if p.FromStartTime[i] > p.FromEndTime[i] {
-			return fmt.Errorf("MakeMultiSwap FromStartTime > FromEndTime")
+			return NewFsnError(FsnErrInvalidParam, "MakeMultiSwap FromStartTime > FromEndTime")
 		}
 		if p.FromEndTime[i] <= timestamp {
-			return fmt.Errorf("MakeMultiSwap FromEndTime <= latest blockTime")
+			return NewFsnError(FsnErrInvalidParam, "MakeMultiSwap FromEndTime <= latest blockTime")
 		}
 	}
 
 	ln = len(p.MinToAmount)
 	for i := 0; i < ln; i++ {
 		if p.MinToAmount[i] == nil || p.MinToAmount[i].Cmp(Big0) <= 0 {
-			return fmt.Errorf("MinToAmounts must be ge 1")
+			return NewFsnError(FsnErrInvalidParam, "MinToAmounts must be ge 1")
 		}
 		toTotal := new(big.Int).Mul(p.MinToAmount[i], p.SwapSize)
 		if toTotal.Cmp(Big0) <= 0 {
-			return fmt.Errorf("size * MinToAmount too large")
+			return NewFsnError(FsnErrInvalidParam, "size * MinToAmount too large")
 		}
 		if p.ToStartTime[i] > p.ToEndTime[i] {
-			return fmt.Errorf("MakeMultiSwap ToStartTime > ToEndTime")
+			return NewFsnError(FsnErrInvalidParam, "MakeMultiSwap ToStartTime > ToEndTime")
 		}
 		if p.ToEndTime[i] <= timestamp {
-			return fmt.Errorf("MakeMultiSwap ToEndTime <= latest blockTime")
+			return NewFsnError(FsnErrInvalidParam, "MakeMultiSwap ToEndTime <= latest blockTime")
 		}
 	}
 
 	if len(p.Description) > 1024 {
-		return fmt.Errorf("MakeSwap description length is greater than 1024 chars")
+		return NewFsnError(FsnErrInvalidParam, "MakeSwap description length is greater than 1024 chars")
 	}
 
 	for _, toAssetID := range p.ToAssetID {
 		if toAssetID == OwnerUSANAssetID {
-			return fmt.Errorf("USAN's cannot be multi swapped")
+			return NewFsnError(FsnErrInvalidParam, "USAN's cannot be multi swapped")
 		}
 	}
 	for _, fromAssetID := range p.FromAssetID {
 		if fromAssetID == OwnerUSANAssetID {
-			return fmt.Errorf("USAN's cannot be multi swapped")
+			return NewFsnError(FsnErrInvalidParam, "USAN's cannot be multi swapped")
 		}
 	}
 	return nil
@@ -476,21 +1204,76 @@ func (p *TakeMultiSwapParam) Check(blockNumber *big.Int, swap *MultiSwap, timest
 	if p.Size == nil || p.Size.Cmp(Big0) <= 0 ||
 		swap.SwapSize == nil || p.Size.Cmp(swap.SwapSize) > 0 {
 
-		return fmt.Errorf("Size must be ge 1 and le Swapsize")
+		return NewFsnError(FsnErrInvalidParam, "Size must be ge 1 and le Swapsize")
 	}
 
 	ln := len(swap.FromEndTime)
 	for i := 0; i < ln; i++ {
 		if swap.FromEndTime[i] <= timestamp {
-			return fmt.Errorf("swap expired: FromEndTime <= latest blockTime")
+			return NewFsnError(FsnErrInvalidParam, "swap expired: FromEndTime <= latest blockTime")
 		}
 	}
 
 	ln = len(swap.ToEndTime)
 	for i := 0; i < ln; i++ {
 		if swap.ToEndTime[i] <= timestamp {
-			return fmt.Errorf("swap expired: ToEndTime <= latest blockTime")
+			return NewFsnError(FsnErrInvalidParam, "swap expired: ToEndTime <= latest blockTime")
 		}
 	}
 	return nil
 }
+
+// Check wacom
+func (p *TypedCallParam) Check(blockNumber *big.Int, timestamp uint64) error {
+	if !IsTypedCallEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "typed call is not enabled")
+	}
+	switch p.Call.Func {
+	case GenNotationFunc, GenAssetFunc, SendAssetFunc, TimeLockFunc, AssetValueChangeFunc,
+		MakeSwapFunc, MakeSwapFuncExt, RecallSwapFunc, TakeSwapFunc, TakeSwapFuncExt,
+		MakeMultiSwapFunc, RecallMultiSwapFunc, TakeMultiSwapFunc:
+	default:
+		return NewFsnError(FsnErrInvalidParam, "%v can not be a typed call", p.Call.Func.Name())
+	}
+	if p.Deadline < timestamp {
+		return NewFsnError(FsnErrInvalidParam, "typed call expired: Deadline < latest blockTime")
+	}
+	if len(p.Signature) != 65 {
+		return NewFsnError(FsnErrInvalidParam, "invalid typed call signature length")
+	}
+	return nil
+}
+
+// Check wacom
+func (p *StakingKeyParam) Check(blockNumber *big.Int, owner Address) error {
+	if !IsStakingKeyEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "staking key is not enabled")
+	}
+	if p.Key == owner {
+		return NewFsnError(FsnErrInvalidParam, "staking key must differ from the owner")
+	}
+	if p.Key.IsSpecialKeyAddress() || p.Key == FSNCallAddress {
+		return NewFsnError(FsnErrInvalidParam, "staking key can not be a system address")
+	}
+	return nil
+}
+
+// Check wacom
+func (p *StakingBuyTicketParam) Check(blockNumber *big.Int, timestamp uint64) error {
+	if !IsStakingKeyEnabled(blockNumber) {
+		return NewFsnError(FsnErrNotEnabled, "staking key is not enabled")
+	}
+	if p.Owner == (Address{}) {
+		return NewFsnError(FsnErrInvalidParam, "staking ticket owner must be set")
+	}
+	return p.ToBuyTicketParam().Check(blockNumber, timestamp)
+}
+
+// ToBuyTicketParam wacom
+func (p *StakingBuyTicketParam) ToBuyTicketParam() *BuyTicketParam {
+	return &BuyTicketParam{
+		Start:        p.Start,
+		End:          p.End,
+		Denomination: p.Denomination,
+	}
+}
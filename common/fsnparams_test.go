@@ -0,0 +1,366 @@
+package common
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+func TestTakeSwapFillEncoding(t *testing.T) {
+	legacy, _ := rlp.EncodeToBytes(&struct {
+		SwapID Hash
+		Size   *big.Int
+	}{HexToHash("0x01"), big.NewInt(3)})
+	exact := &TakeSwapParam{SwapID: HexToHash("0x01"), Size: big.NewInt(3)}
+	if enc, _ := exact.ToBytes(); !bytes.Equal(enc, legacy) {
+		t.Fatalf("exact take encoding changed: %x", enc)
+	}
+
+	fill := &TakeSwapParam{SwapID: HexToHash("0x01"), Size: big.NewInt(3), Fill: []TakeSwapFill{{TakeSwapAtLeast, big.NewInt(2)}}}
+	enc, _ := fill.ToBytes()
+	var dec TakeSwapParam
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if len(dec.Fill) != 1 || dec.Fill[0].Mode != TakeSwapAtLeast || dec.Fill[0].MinSize.Int64() != 2 {
+		t.Fatalf("wrong fill %+v", dec.Fill)
+	}
+}
+
+func TestTakeSwapFillCheck(t *testing.T) {
+	swap := &Swap{SwapSize: big.NewInt(5), FromEndTime: TimeLockForever, ToEndTime: TimeLockForever}
+	take := func(size int64, mode TakeSwapFillMode, minSize int64) *TakeSwapParam {
+		return &TakeSwapParam{Size: big.NewInt(size), Fill: []TakeSwapFill{{mode, big.NewInt(minSize)}}}
+	}
+
+	// the tail is ignored before the fork, as older nodes decoded it
+	if p := take(8, TakeSwapAtLeast, 4); p.Check(big.NewInt(1), swap, 0) == nil {
+		t.Fatalf("oversized take accepted as a fill before the fork")
+	}
+	if p := take(3, TakeSwapInvalidFillMode, 1); p.Check(big.NewInt(1), swap, 0) != nil || p.FillSize(big.NewInt(1), swap).Int64() != 3 {
+		t.Fatalf("fill mode not ignored before the fork")
+	}
+
+	UseDevnetRule = true
+	defer func() { UseDevnetRule = false }()
+
+	tests := []struct {
+		param *TakeSwapParam
+		fill  int64 // -1 if invalid
+	}{
+		{&TakeSwapParam{Size: big.NewInt(3)}, 3},
+		{&TakeSwapParam{Size: big.NewInt(6)}, -1},
+		{take(3, TakeSwapExact, 0), 3},
+		{take(3, TakeSwapExact, 1), -1},
+		{take(8, TakeSwapAtLeast, 4), 5},
+		{take(8, TakeSwapAtLeast, 6), -1},
+		{take(3, TakeSwapAtLeast, 4), -1},
+		{take(3, TakeSwapAtLeast, 0), -1},
+		{take(5, TakeSwapFillOrKill, 0), 5},
+		{take(4, TakeSwapFillOrKill, 0), -1},
+		{take(5, TakeSwapInvalidFillMode, 0), -1},
+	}
+	for i, tt := range tests {
+		err := tt.param.Check(big.NewInt(1), swap, 0)
+		if tt.fill < 0 {
+			if err == nil {
+				t.Errorf("test %d: invalid take accepted", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: %v", i, err)
+		} else if size := tt.param.FillSize(big.NewInt(1), swap); size.Int64() != tt.fill {
+			t.Errorf("test %d: fill size %v, want %d", i, size, tt.fill)
+		}
+	}
+}
+
+func TestBuyTicketDenomination(t *testing.T) {
+	legacy, _ := rlp.EncodeToBytes(&struct{ Start, End uint64 }{1, 2})
+	var p BuyTicketParam
+	if err := rlp.DecodeBytes(legacy, &p); err != nil || p.Weight(big.NewInt(1)) != 1 {
+		t.Fatalf("legacy param decoding mismatch: weight %d, err %v", p.Weight(big.NewInt(1)), err)
+	}
+	if enc, _ := p.ToBytes(); string(enc) != string(legacy) {
+		t.Fatalf("legacy param encoding changed")
+	}
+
+	p = BuyTicketParam{Start: 0, End: 60 * 24 * 3600, Denomination: []uint64{5}}
+	// the tail is ignored before the fork, as older nodes decoded it
+	if err := p.Check(big.NewInt(1), 0); err != nil || p.Weight(big.NewInt(1)) != 1 {
+		t.Errorf("denomination not ignored before the fork: weight %d, err %v", p.Weight(big.NewInt(1)), err)
+	}
+	p.Denomination = []uint64{3}
+	if err := p.Check(big.NewInt(1), 0); err != nil {
+		t.Errorf("invalid denomination checked before the fork: %v", err)
+	}
+	p.Denomination = []uint64{5}
+	UseDevnetRule = true
+	defer func() { UseDevnetRule = false }()
+	if err := p.Check(big.NewInt(1), 0); err != nil || p.Weight(big.NewInt(1)) != 5 {
+		t.Errorf("denomination 5 rejected: %v", err)
+	}
+	for _, d := range [][]uint64{{0}, {3}, {5, 5}} {
+		p.Denomination = d
+		if err := p.Check(big.NewInt(1), 0); err == nil {
+			t.Errorf("denomination %v accepted", d)
+		}
+	}
+}
+
+func TestRevokeTicket(t *testing.T) {
+	p := RevokeTicketParam{TicketID: HexToHash("0x01")}
+	if err := p.Check(big.NewInt(1)); err == nil {
+		t.Errorf("revoke accepted before the fork")
+	}
+	UseDevnetRule = true
+	defer func() { UseDevnetRule = false }()
+	if err := p.Check(big.NewInt(1)); err != nil {
+		t.Errorf("revoke rejected: %v", err)
+	}
+	if err := (&RevokeTicketParam{}).Check(big.NewInt(1)); err == nil {
+		t.Errorf("empty ticket ID accepted")
+	}
+
+	ticket := TicketBody{ID: p.TicketID, Height: 1}
+	ticket.SetWeight(5)
+	price := TicketPrice(nil)
+	want := new(big.Int).Mul(price, big.NewInt(5*(100-TicketRevokePenaltyPercent)))
+	if refund := ticket.RevokeRefund(); refund.Cmp(want.Div(want, big.NewInt(100))) != 0 {
+		t.Errorf("refund mismatch: have %v, want %v", refund, want)
+	}
+}
+
+func TestGenMultiOwnerAsset(t *testing.T) {
+	owners := []Address{HexToAddress("0x01"), HexToAddress("0x02"), HexToAddress("0x03")}
+	p := GenMultiOwnerAssetParam{
+		Asset:  GenAssetParam{Name: "Multi", Symbol: "MOA", Total: big.NewInt(100)},
+		Owners: AssetOwnerSet{Threshold: 2, Owners: owners},
+	}
+	if err := p.Check(big.NewInt(1)); err == nil {
+		t.Errorf("multi owner asset accepted before the fork")
+	}
+	UseDevnetRule = true
+	defer func() { UseDevnetRule = false }()
+	if err := p.Check(big.NewInt(1)); err != nil {
+		t.Errorf("multi owner asset rejected: %v", err)
+	}
+	for i, set := range []AssetOwnerSet{
+		{Threshold: 1},
+		{Threshold: 0, Owners: owners},
+		{Threshold: 4, Owners: owners},
+		{Threshold: 1, Owners: []Address{owners[0], owners[0]}},
+		{Threshold: 1, Owners: []Address{{}}},
+		{Threshold: 1, Owners: make([]Address, MaxAssetOwners+1)},
+	} {
+		invalid := p
+		invalid.Owners = set
+		if err := invalid.Check(big.NewInt(1)); err == nil {
+			t.Errorf("invalid owner set %d accepted", i)
+		}
+	}
+
+	owner := p.Owners.Address()
+	if owner == (Address{}) || p.Owners.IsOwner(owner) {
+		t.Fatalf("invalid owner address %x", owner)
+	}
+	other := AssetOwnerSet{Threshold: 3, Owners: owners}
+	if other.Address() == owner {
+		t.Errorf("owner address ignores the threshold")
+	}
+	if !p.Owners.IsOwner(owners[1]) || p.Owners.IsOwner(HexToAddress("0x04")) {
+		t.Errorf("owner membership mismatch")
+	}
+}
+
+func TestEscrowAsset(t *testing.T) {
+	p := EscrowAssetParam{
+		AssetID:  HexToHash("0x01"),
+		To:       HexToAddress("0x02"),
+		Value:    big.NewInt(10),
+		Deadline: 2000,
+	}
+	if err := p.Check(big.NewInt(1), 1000); err == nil {
+		t.Errorf("escrow accepted before the fork")
+	}
+	UseDevnetRule = true
+	defer func() { UseDevnetRule = false }()
+	if err := p.Check(big.NewInt(1), 1000); err != nil {
+		t.Errorf("escrow rejected: %v", err)
+	}
+	if err := p.Check(big.NewInt(1), 2000); err == nil {
+		t.Errorf("escrow accepted at its deadline")
+	}
+	invalid := p
+	invalid.Value = new(big.Int)
+	if err := invalid.Check(big.NewInt(1), 1000); err == nil {
+		t.Errorf("escrow of zero value accepted")
+	}
+	invalid = p
+	invalid.To = Address{}
+	if err := invalid.Check(big.NewInt(1), 1000); err == nil {
+		t.Errorf("escrow without recipient accepted")
+	}
+
+	data, err := p.ToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded EscrowAssetParam
+	if err := rlp.DecodeBytes(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.To != p.To || decoded.Value.Cmp(p.Value) != 0 || decoded.Deadline != p.Deadline {
+		t.Errorf("decoded escrow mismatch: %+v", decoded)
+	}
+	if err := (&ClaimEscrowParam{}).Check(big.NewInt(1)); err == nil {
+		t.Errorf("claim without escrow ID accepted")
+	}
+}
+
+func TestCreateStream(t *testing.T) {
+	p := CreateStreamParam{
+		AssetID:   HexToHash("0x01"),
+		To:        HexToAddress("0x02"),
+		Value:     big.NewInt(10),
+		StartTime: 1000,
+		EndTime:   2000,
+	}
+	if err := p.Check(big.NewInt(1), 500); err == nil {
+		t.Errorf("stream accepted before the fork")
+	}
+	UseDevnetRule = true
+	defer func() { UseDevnetRule = false }()
+	if err := p.Check(big.NewInt(1), 1500); err != nil {
+		t.Errorf("stream rejected: %v", err)
+	}
+	if err := p.Check(big.NewInt(1), 2000); err == nil {
+		t.Errorf("stream accepted at its end")
+	}
+	invalid := p
+	invalid.StartTime = invalid.EndTime
+	if err := invalid.Check(big.NewInt(1), 500); err == nil {
+		t.Errorf("stream without duration accepted")
+	}
+	if err := (&WithdrawStreamParam{}).Check(big.NewInt(1)); err == nil {
+		t.Errorf("withdrawal without stream ID accepted")
+	}
+}
+
+func TestConditionParams(t *testing.T) {
+	hashLock := CreateConditionParam{Kind: HashLockCondition, Hash: HexToHash("0x01")}
+	oracle := CreateConditionParam{Kind: OracleCondition, Hash: HexToHash("0x01"), Oracle: HexToAddress("0x02")}
+	if err := hashLock.Check(big.NewInt(1)); err == nil {
+		t.Errorf("condition accepted before the fork")
+	}
+	UseDevnetRule = true
+	defer func() { UseDevnetRule = false }()
+	for _, p := range []CreateConditionParam{hashLock, oracle} {
+		if err := p.Check(big.NewInt(1)); err != nil {
+			t.Errorf("%v condition rejected: %v", p.Kind, err)
+		}
+	}
+	for i, p := range []CreateConditionParam{
+		{Kind: HashLockCondition},
+		{Kind: HashLockCondition, Hash: HexToHash("0x01"), Oracle: HexToAddress("0x02")},
+		{Kind: OracleCondition, Hash: HexToHash("0x01")},
+		{Kind: ConditionKind(7), Hash: HexToHash("0x01")},
+	} {
+		if err := p.Check(big.NewInt(1)); err == nil {
+			t.Errorf("invalid condition %d accepted", i)
+		}
+	}
+
+	resolve := ResolveConditionParam{ConditionID: HexToHash("0x01"), Preimage: []byte("secret")}
+	if err := resolve.Check(big.NewInt(1)); err != nil {
+		t.Errorf("resolution rejected: %v", err)
+	}
+	resolve.Signature = make([]byte, 64)
+	if err := resolve.Check(big.NewInt(1)); err == nil {
+		t.Errorf("short signature accepted")
+	}
+	resolve.Signature = nil
+	resolve.Preimage = make([]byte, MaxConditionPreimage+1)
+	if err := resolve.Check(big.NewInt(1)); err == nil {
+		t.Errorf("long preimage accepted")
+	}
+
+	transfer := ConditionalTransferParam{
+		AssetID:     HexToHash("0x01"),
+		To:          HexToAddress("0x02"),
+		Value:       big.NewInt(10),
+		ConditionID: HexToHash("0x03"),
+		Deadline:    2000,
+	}
+	if err := transfer.Check(big.NewInt(1), 1000); err != nil {
+		t.Errorf("conditional transfer rejected: %v", err)
+	}
+	if err := transfer.Check(big.NewInt(1), 2000); err == nil {
+		t.Errorf("conditional transfer accepted at its deadline")
+	}
+	transfer.ConditionID = Hash{}
+	if err := transfer.Check(big.NewInt(1), 1000); err == nil {
+		t.Errorf("conditional transfer without condition accepted")
+	}
+}
+
+func TestGenAssetCharset(t *testing.T) {
+	valid := GenAssetParam{Name: "Fusion Token (v2)", Symbol: "FSN-2.x_y", Total: big.NewInt(1)}
+	lookalikes := []GenAssetParam{
+		{Name: "Fusion", Symbol: "ＦＳＮ", Total: big.NewInt(1)},
+		{Name: "Fusion", Symbol: "FS\u039d", Total: big.NewInt(1)}, // greek capital nu
+		{Name: "Fusion", Symbol: "F SN", Total: big.NewInt(1)},
+		{Name: "Fusion\u200b", Symbol: "FSN", Total: big.NewInt(1)},
+		{Name: "Fusion\n", Symbol: "FSN", Total: big.NewInt(1)},
+	}
+	for i, p := range lookalikes {
+		if err := p.Check(big.NewInt(1)); err != nil {
+			t.Errorf("asset %d rejected before the registry fork: %v", i, err)
+		}
+	}
+	UseDevnetRule = true
+	defer func() { UseDevnetRule = false }()
+	if err := valid.Check(big.NewInt(1)); err != nil {
+		t.Errorf("ASCII asset rejected: %v", err)
+	}
+	for i, p := range lookalikes {
+		if err := p.Check(big.NewInt(1)); err == nil {
+			t.Errorf("asset %d with name %q symbol %q accepted", i, p.Name, p.Symbol)
+		}
+	}
+}
+
+func TestStakingKeyParams(t *testing.T) {
+	owner := HexToAddress("0x01")
+	key := StakingKeyParam{Key: HexToAddress("0x02")}
+	if err := key.Check(big.NewInt(1), owner); err == nil {
+		t.Errorf("staking key accepted before the fork")
+	}
+	UseDevnetRule = true
+	defer func() { UseDevnetRule = false }()
+	if err := key.Check(big.NewInt(1), owner); err != nil {
+		t.Errorf("staking key rejected: %v", err)
+	}
+	for _, addr := range []Address{owner, StakingKeyAddress, TicketKeyAddress, FSNCallAddress} {
+		p := StakingKeyParam{Key: addr}
+		if err := p.Check(big.NewInt(1), owner); err == nil {
+			t.Errorf("staking key %v accepted", addr.Hex())
+		}
+	}
+
+	buy := StakingBuyTicketParam{Owner: owner, Start: 1000, End: 1000 + 40*24*3600}
+	if err := buy.Check(big.NewInt(1), 1000); err != nil {
+		t.Errorf("staking ticket rejected: %v", err)
+	}
+	buy.End = 2000
+	if err := buy.Check(big.NewInt(1), 1000); err == nil {
+		t.Errorf("staking ticket of a too short lifetime accepted")
+	}
+	buy = StakingBuyTicketParam{Start: 1000, End: 1000 + 40*24*3600}
+	if err := buy.Check(big.NewInt(1), 1000); err == nil {
+		t.Errorf("staking ticket without owner accepted")
+	}
+}
@@ -0,0 +1,39 @@
+package common
+
+import "math/big"
+
+// Internal transfer types
+const (
+	TransferSendAsset      = "SendAsset"      // asset sent by SendAsset
+	TransferTimeLock       = "TimeLock"       // asset or time lock delivered by TimeLock
+	TransferSwapLock       = "SwapLock"       // maker asset locked by a swap
+	TransferSwapRefund     = "SwapRefund"     // maker asset returned by a recall
+	TransferSwapSettlement = "SwapSettlement" // asset exchanged by a take
+	TransferTicketLock     = "TicketLock"     // FSN locked by a ticket
+	TransferTicketRefund   = "TicketRefund"   // FSN returned by a revoked ticket
+	TransferTicketReturn   = "TicketReturn"   // FSN unit returned by a spent ticket when finalizing a block
+	TransferEscrowLock     = "EscrowLock"     // asset locked by an escrow
+	TransferEscrowRelease  = "EscrowRelease"  // escrowed asset claimed or refunded
+	TransferStreamLock     = "StreamLock"     // asset locked by a payment stream
+	TransferStreamWithdraw = "StreamWithdraw" // streamed asset withdrawn by the recipient
+	TransferCondLock       = "CondLock"       // asset locked by a conditional transfer
+	TransferCondSettle     = "CondSettle"     // conditional transfer paid out or refunded
+	TransferMint           = "Mint"           // asset issued by AssetValueChange or a bridge deposit
+	TransferBurn           = "Burn"           // asset destroyed by AssetValueChange or a bridge withdrawal
+)
+
+// InternalTransfer is a movement of value made by an FSN call or by the
+// finalization of a block. The value held by swaps, tickets, escrows, streams
+// and conditional transfers moves from and to FSNCallAddress, minted value
+// comes from and burnt value goes to the zero address. StartTime and EndTime
+// are zero for asset balances.
+type InternalTransfer struct {
+	Type      string
+	From      Address
+	To        Address
+	AssetID   Hash
+	Value     *big.Int `json:",string"`
+	StartTime uint64
+	EndTime   uint64
+	Ref       Hash // ID of the swap, ticket, escrow, stream, transfer or deposit, zero if none
+}
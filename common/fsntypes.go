@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"strings"
+
+	"github.com/FusionFoundation/go-fusion/rlp"
 )
 
 // SystemAssetID wacom
@@ -36,6 +39,33 @@ var (
 
 	// ReportIllegalAddress wacom
 	ReportKeyAddress = HexToAddress("0xfffffffffffffffffffffffffffffffffffffff8")
+
+	// TypedCallKeyAddress wacom
+	TypedCallKeyAddress = HexToAddress("0xfffffffffffffffffffffffffffffffffffffff7")
+
+	// StakingKeyAddress wacom
+	StakingKeyAddress = HexToAddress("0xfffffffffffffffffffffffffffffffffffffff6")
+
+	// FeeScheduleKeyAddress wacom
+	FeeScheduleKeyAddress = HexToAddress("0xfffffffffffffffffffffffffffffffffffffff5")
+
+	// GovernanceKeyAddress wacom
+	GovernanceKeyAddress = HexToAddress("0xfffffffffffffffffffffffffffffffffffffff4")
+
+	// EscrowKeyAddress wacom
+	EscrowKeyAddress = HexToAddress("0xfffffffffffffffffffffffffffffffffffffff3")
+
+	// StreamKeyAddress wacom
+	StreamKeyAddress = HexToAddress("0xfffffffffffffffffffffffffffffffffffffff2")
+
+	// ConditionKeyAddress wacom
+	ConditionKeyAddress = HexToAddress("0xfffffffffffffffffffffffffffffffffffffff1")
+
+	// BridgeKeyAddress wacom
+	BridgeKeyAddress = HexToAddress("0xfffffffffffffffffffffffffffffffffffffff0")
+
+	// EpochKeyAddress wacom
+	EpochKeyAddress = HexToAddress("0xffffffffffffffffffffffffffffffffffffffef")
 )
 
 func (addr Address) IsSpecialKeyAddress() bool {
@@ -44,7 +74,16 @@ func (addr Address) IsSpecialKeyAddress() bool {
 		addr == AssetKeyAddress ||
 		addr == SwapKeyAddress ||
 		addr == MultiSwapKeyAddress ||
-		addr == ReportKeyAddress
+		addr == ReportKeyAddress ||
+		addr == TypedCallKeyAddress ||
+		addr == StakingKeyAddress ||
+		addr == FeeScheduleKeyAddress ||
+		addr == GovernanceKeyAddress ||
+		addr == EscrowKeyAddress ||
+		addr == StreamKeyAddress ||
+		addr == ConditionKeyAddress ||
+		addr == BridgeKeyAddress ||
+		addr == EpochKeyAddress
 }
 
 var (
@@ -53,10 +92,20 @@ var (
 	// AutoBuyTicketChan wacom
 	AutoBuyTicketChan = make(chan int, 10)
 
+	// AutoReportIllegal enables reporting double mining seen by the node
+	AutoReportIllegal = false
 	// ReportIllegal wacom
 	ReportIllegalChan = make(chan []byte)
 )
 
+// SwapRetentionBlocks is the number of blocks a recalled or taken swap is kept
+// in the state before it is deleted (about two weeks)
+const SwapRetentionBlocks uint64 = 100000
+
+// ReportEpochLength is the number of blocks of a report epoch, reports are
+// deleted from the state at the start of their expiry epoch
+const ReportEpochLength uint64 = 1000
+
 // FSNCallFunc wacom
 type FSNCallFunc uint8
 
@@ -95,6 +144,46 @@ const (
 	TakeMultiSwapFunc
 	// ReportIllegalFunc wacom
 	ReportIllegalFunc
+	// TypedCallFunc wacom
+	TypedCallFunc
+	// StakingKeyFunc wacom
+	StakingKeyFunc
+	// StakingBuyTicketFunc wacom
+	StakingBuyTicketFunc
+	// GenRestrictedAssetFunc wacom
+	GenRestrictedAssetFunc
+	// AssetTransferListFunc wacom
+	AssetTransferListFunc
+	// SetFsnCallFeeFunc wacom
+	SetFsnCallFeeFunc
+	// CreateProposalFunc wacom
+	CreateProposalFunc
+	// VoteProposalFunc wacom
+	VoteProposalFunc
+	// RevokeTicketFunc wacom
+	RevokeTicketFunc
+	// GenMultiOwnerAssetFunc wacom
+	GenMultiOwnerAssetFunc
+	// EscrowAssetFunc wacom
+	EscrowAssetFunc
+	// ClaimEscrowFunc wacom
+	ClaimEscrowFunc
+	// CreateStreamFunc wacom
+	CreateStreamFunc
+	// WithdrawStreamFunc wacom
+	WithdrawStreamFunc
+	// CreateConditionFunc wacom
+	CreateConditionFunc
+	// ResolveConditionFunc wacom
+	ResolveConditionFunc
+	// ConditionalTransferFunc wacom
+	ConditionalTransferFunc
+	// SettleConditionalFunc wacom
+	SettleConditionalFunc
+	// AttestDepositFunc wacom
+	AttestDepositFunc
+	// BridgeWithdrawFunc wacom
+	BridgeWithdrawFunc
 	// UnknownFunc
 	UnknownFunc = 0xff
 )
@@ -135,14 +224,106 @@ func (f FSNCallFunc) Name() string {
 		return "TakeMultiSwapFunc"
 	case ReportIllegalFunc:
 		return "ReportIllegalFunc"
+	case TypedCallFunc:
+		return "TypedCallFunc"
+	case StakingKeyFunc:
+		return "StakingKeyFunc"
+	case StakingBuyTicketFunc:
+		return "StakingBuyTicketFunc"
+	case GenRestrictedAssetFunc:
+		return "GenRestrictedAssetFunc"
+	case AssetTransferListFunc:
+		return "AssetTransferListFunc"
+	case SetFsnCallFeeFunc:
+		return "SetFsnCallFeeFunc"
+	case CreateProposalFunc:
+		return "CreateProposalFunc"
+	case VoteProposalFunc:
+		return "VoteProposalFunc"
+	case RevokeTicketFunc:
+		return "RevokeTicketFunc"
+	case GenMultiOwnerAssetFunc:
+		return "GenMultiOwnerAssetFunc"
+	case EscrowAssetFunc:
+		return "EscrowAssetFunc"
+	case ClaimEscrowFunc:
+		return "ClaimEscrowFunc"
+	case CreateStreamFunc:
+		return "CreateStreamFunc"
+	case WithdrawStreamFunc:
+		return "WithdrawStreamFunc"
+	case CreateConditionFunc:
+		return "CreateConditionFunc"
+	case ResolveConditionFunc:
+		return "ResolveConditionFunc"
+	case ConditionalTransferFunc:
+		return "ConditionalTransferFunc"
+	case SettleConditionalFunc:
+		return "SettleConditionalFunc"
+	case AttestDepositFunc:
+		return "AttestDepositFunc"
+	case BridgeWithdrawFunc:
+		return "BridgeWithdrawFunc"
 	}
 	return "Unknown"
 }
 
+// Topic returns the first log topic of the FSN calls of the function
+func (f FSNCallFunc) Topic() Hash {
+	return BytesToHash([]byte{byte(f)})
+}
+
+// FsnCallIndexTopic is the first topic of the index log following the log of
+// an FSN call since the indexed log fork, its other topics are the func topic,
+// the sender and the primary object ID of the call
+var FsnCallIndexTopic = Keccak256Hash([]byte("FsnCallIndex(bytes32,address,bytes32)"))
+
+// FsnCallFuncOfTopic returns the func of an FSN call log with first topic
+// topic, false for the other logs of the FSN call address
+func FsnCallFuncOfTopic(topic Hash) (FSNCallFunc, bool) {
+	for _, b := range topic[:HashLength-1] {
+		if b != 0 {
+			return 0, false
+		}
+	}
+	return FSNCallFunc(topic[HashLength-1]), true
+}
+
 func IsFsnCall(to *Address) bool {
 	return to != nil && *to == FSNCallAddress
 }
 
+// IsFsnCallFuncEnabled reports whether an FSN call func is supported in block
+// number. The funcs added by a fork are unsupported before it, like unknown
+// funcs: they fail without a fee or a log.
+func IsFsnCallFuncEnabled(funcType FSNCallFunc, number *big.Int) bool {
+	switch funcType {
+	case TypedCallFunc:
+		return IsTypedCallEnabled(number)
+	case StakingKeyFunc, StakingBuyTicketFunc:
+		return IsStakingKeyEnabled(number)
+	case GenRestrictedAssetFunc, AssetTransferListFunc:
+		return IsAssetTransferRestrictionEnabled(number)
+	case SetFsnCallFeeFunc:
+		return IsFsnCallFeeScheduleEnabled(number)
+	case CreateProposalFunc, VoteProposalFunc:
+		return IsGovernanceEnabled(number)
+	case RevokeTicketFunc:
+		return IsTicketRevokeEnabled(number)
+	case GenMultiOwnerAssetFunc:
+		return IsAssetMultiOwnerEnabled(number)
+	case EscrowAssetFunc, ClaimEscrowFunc:
+		return IsAssetEscrowEnabled(number)
+	case CreateStreamFunc, WithdrawStreamFunc:
+		return IsPaymentStreamEnabled(number)
+	case CreateConditionFunc, ResolveConditionFunc, ConditionalTransferFunc, SettleConditionalFunc:
+		return IsConditionalTransferEnabled(number)
+	case AttestDepositFunc, BridgeWithdrawFunc:
+		return IsBridgeEnabled(number)
+	}
+	return true
+}
+
 func GetFsnCallFee(to *Address, funcType FSNCallFunc) *big.Int {
 	fee := big.NewInt(0)
 	if !IsFsnCall(to) {
@@ -151,9 +332,11 @@ func GetFsnCallFee(to *Address, funcType FSNCallFunc) *big.Int {
 	switch funcType {
 	case GenNotationFunc:
 		fee = big.NewInt(100000000000000000) // 0.1 FSN
-	case GenAssetFunc:
+	case GenAssetFunc, GenRestrictedAssetFunc, GenMultiOwnerAssetFunc:
 		fee = big.NewInt(10000000000000000) // 0.01 FSN
-	case MakeSwapFunc, MakeSwapFuncExt, MakeMultiSwapFunc:
+	case CreateProposalFunc:
+		fee = big.NewInt(1000000000000000000) // 1 FSN
+	case MakeSwapFunc, MakeSwapFuncExt, MakeMultiSwapFunc, EscrowAssetFunc, CreateStreamFunc, CreateConditionFunc, ConditionalTransferFunc:
 		fee = big.NewInt(1000000000000000) // 0.001 FSN
 	case TimeLockFunc:
 		fee = big.NewInt(1000000000000000) // 0.001 FSN
@@ -185,6 +368,268 @@ type Asset struct {
 	Description string
 }
 
+// AssetTransferRestriction is the mode of the transfer list of an asset
+type AssetTransferRestriction uint8
+
+const (
+	// AssetTransferUnrestricted wacom
+	AssetTransferUnrestricted AssetTransferRestriction = iota
+	// AssetTransferWhitelist only lets listed addresses send and receive the asset
+	AssetTransferWhitelist
+	// AssetTransferBlacklist lets all but the listed addresses send and receive the asset
+	AssetTransferBlacklist
+)
+
+func (r AssetTransferRestriction) String() string {
+	switch r {
+	case AssetTransferUnrestricted:
+		return "none"
+	case AssetTransferWhitelist:
+		return "whitelist"
+	case AssetTransferBlacklist:
+		return "blacklist"
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(r))
+}
+
+// MarshalText wacom
+func (r AssetTransferRestriction) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText wacom
+func (r *AssetTransferRestriction) UnmarshalText(input []byte) error {
+	switch string(input) {
+	case "", "none":
+		*r = AssetTransferUnrestricted
+	case "whitelist":
+		*r = AssetTransferWhitelist
+	case "blacklist":
+		*r = AssetTransferBlacklist
+	default:
+		return fmt.Errorf("unknown asset transfer restriction %q", input)
+	}
+	return nil
+}
+
+// MaxAssetTransferListChange is the maximum number of addresses added to or
+// removed from an asset transfer list by one call
+const MaxAssetTransferListChange = 100
+
+// MaxAssetOwners is the maximum number of keys of an asset owner set
+const MaxAssetOwners = 16
+
+// AssetOwnerSet is an M-of-N key set owning an asset. The owner of the asset
+// is the address derived from the set, the calls of the owner are made once
+// Threshold keys of the set sent the same call.
+type AssetOwnerSet struct {
+	Threshold uint64
+	Owners    []Address
+}
+
+// Address returns the owner address derived from the set
+func (s *AssetOwnerSet) Address() Address {
+	data, _ := rlp.EncodeToBytes(s)
+	return BytesToAddress(Keccak256Hash([]byte("assetOwners"), data).Bytes()[12:])
+}
+
+// IsOwner reports whether addr is one of the keys of the set
+func (s *AssetOwnerSet) IsOwner(addr Address) bool {
+	for _, owner := range s.Owners {
+		if owner == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// AssetOwnerApprovalBlocks is the number of blocks, about a week, the
+// approvals of an owner call are kept. A call which did not reach the
+// threshold in time starts over.
+const AssetOwnerApprovalBlocks = 7 * 6500
+
+// AssetOwnerApprovals are the keys of an owner set which approved a call
+// since the block Height of the first approval
+type AssetOwnerApprovals struct {
+	Height uint64
+	Keys   []Address
+}
+
+// IsExpired reports whether the approvals expired in block number
+func (a *AssetOwnerApprovals) IsExpired(number uint64) bool {
+	return number > a.Height+AssetOwnerApprovalBlocks
+}
+
+// Escrow is an asset sent to To which To can claim until Deadline, after the
+// deadline the sender can claim it back
+type Escrow struct {
+	ID       Hash
+	Sender   Address
+	To       Address
+	AssetID  Hash
+	Value    *big.Int `json:",string"`
+	Deadline uint64
+	Time     uint64 // time of the block which created the escrow
+}
+
+// Stream is a payment of Value from Sender to To which vests linearly between
+// StartTime and EndTime, To withdraws the vested part not yet withdrawn
+type Stream struct {
+	ID        Hash
+	Sender    Address
+	To        Address
+	AssetID   Hash
+	Value     *big.Int `json:",string"`
+	StartTime uint64
+	EndTime   uint64
+	Withdrawn *big.Int `json:",string"`
+}
+
+// Vested returns the part of the stream value vested at the given time
+func (s *Stream) Vested(timestamp uint64) *big.Int {
+	switch {
+	case timestamp <= s.StartTime:
+		return new(big.Int)
+	case timestamp >= s.EndTime:
+		return new(big.Int).Set(s.Value)
+	}
+	vested := new(big.Int).Mul(s.Value, new(big.Int).SetUint64(timestamp-s.StartTime))
+	return vested.Div(vested, new(big.Int).SetUint64(s.EndTime-s.StartTime))
+}
+
+// Withdrawable returns the vested part of the stream not yet withdrawn
+func (s *Stream) Withdrawable(timestamp uint64) *big.Int {
+	return new(big.Int).Sub(s.Vested(timestamp), s.Withdrawn)
+}
+
+// ConditionKind is the way a condition of the condition registry is resolved
+type ConditionKind uint8
+
+const (
+	// HashLockCondition is resolved by revealing a preimage of its hash
+	HashLockCondition ConditionKind = iota
+	// OracleCondition is resolved by its oracle, or by a statement of the
+	// oracle signed over its StatementHash
+	OracleCondition
+)
+
+func (k ConditionKind) String() string {
+	switch k {
+	case HashLockCondition:
+		return "hashLock"
+	case OracleCondition:
+		return "oracle"
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(k))
+}
+
+// MarshalText wacom
+func (k ConditionKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText wacom
+func (k *ConditionKind) UnmarshalText(input []byte) error {
+	switch string(input) {
+	case "hashLock":
+		*k = HashLockCondition
+	case "oracle":
+		*k = OracleCondition
+	default:
+		return fmt.Errorf("unknown condition kind %q", input)
+	}
+	return nil
+}
+
+// MaxConditionPreimage is the maximum size of the preimage revealed to resolve
+// a hash lock condition
+const MaxConditionPreimage = 256
+
+// Condition is an entry of the condition registry, ResolvedTime is the time of
+// the block which resolved it, 0 while it is unresolved
+type Condition struct {
+	ID           Hash
+	Creator      Address
+	Kind         ConditionKind
+	Hash         Hash
+	Oracle       Address
+	ResolvedTime uint64
+}
+
+// StatementHash returns the hash an oracle signs to resolve the condition
+func (c *Condition) StatementHash() Hash {
+	return Keccak256Hash([]byte("condition"), c.ID[:], c.Hash[:])
+}
+
+// ResolvedBefore reports whether the condition was resolved before the given
+// time
+func (c *Condition) ResolvedBefore(timestamp uint64) bool {
+	return c.ResolvedTime != 0 && c.ResolvedTime < timestamp
+}
+
+// ConditionalTransfer is an asset sent to To once its condition is resolved
+// before Deadline, and refunded to the sender otherwise
+type ConditionalTransfer struct {
+	ID          Hash
+	Sender      Address
+	To          Address
+	AssetID     Hash
+	Value       *big.Int `json:",string"`
+	ConditionID Hash
+	Deadline    uint64
+}
+
+// AssetSupplyChange is an entry of the supply history of an asset, appended
+// by every AssetValueChange since the asset supply history fork
+type AssetSupplyChange struct {
+	Height uint64
+	Time   uint64
+	Sender Address
+	To     Address
+	IsInc  bool
+	Value  *big.Int `json:",string"`
+	Total  *big.Int `json:",string"` // supply after the change
+}
+
+// SystemAssetSymbol is the normalized symbol of the system asset, which is
+// reserved in the asset symbol registry
+const SystemAssetSymbol = "FSN"
+
+// NormalizeAssetSymbol returns the form under which an asset symbol is
+// registered: ASCII letters are upper cased and every other byte but ASCII
+// digits dropped, so "fsn" and "F.S.N" collide. It depends on no unicode
+// tables, which change between releases, and symbols registered since the
+// registry fork are ASCII only, see IsAssetSymbolByte.
+func NormalizeAssetSymbol(symbol string) string {
+	normalized := make([]byte, 0, len(symbol))
+	for i := 0; i < len(symbol); i++ {
+		switch c := symbol[i]; {
+		case 'a' <= c && c <= 'z':
+			normalized = append(normalized, c-'a'+'A')
+		case 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+			normalized = append(normalized, c)
+		}
+	}
+	if len(normalized) == 0 {
+		return strings.TrimSpace(symbol)
+	}
+	return string(normalized)
+}
+
+// IsAssetSymbolByte reports whether c may be used in the symbol of an asset
+// created since the asset symbol registry fork: ASCII letters, digits, '-',
+// '.' and '_'.
+func IsAssetSymbolByte(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_'
+}
+
+// IsAssetNameByte reports whether c may be used in the name of an asset
+// created since the asset symbol registry fork: printable ASCII.
+func IsAssetNameByte(c byte) bool {
+	return 0x20 <= c && c <= 0x7e
+}
+
 func (u *Asset) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
 		ID          Hash
@@ -264,7 +709,44 @@ func CheckSwapTargets(targets []Address, addr Address) error {
 			return nil
 		}
 	}
-	return fmt.Errorf("swap taker does not match the specified targets")
+	return NewFsnError(FsnErrNotAllowed, "swap taker does not match the specified targets")
+}
+
+// NotationAction wacom
+type NotationAction uint8
+
+const (
+	// NotationGen wacom
+	NotationGen NotationAction = iota
+	// NotationTransfer wacom
+	NotationTransfer
+	// NotationBurn wacom
+	NotationBurn
+)
+
+func (a NotationAction) Name() string {
+	switch a {
+	case NotationGen:
+		return "Gen"
+	case NotationTransfer:
+		return "Transfer"
+	case NotationBurn:
+		return "Burn"
+	}
+	return "Unknown"
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (a NotationAction) MarshalText() ([]byte, error) {
+	return []byte(a.Name()), nil
+}
+
+// NotationRecord is an ownership change record of a notation
+type NotationRecord struct {
+	Action      NotationAction
+	From        Address
+	To          Address
+	BlockNumber uint64
 }
 
 // KeyValue wacom
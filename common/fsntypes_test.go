@@ -0,0 +1,121 @@
+package common
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestNormalizeAssetSymbol(t *testing.T) {
+	tests := []struct {
+		symbol, want string
+	}{
+		{"FSN", "FSN"},
+		{"fsn", "FSN"},
+		{" F.S.N ", "FSN"},
+		{"USDT-2", "USDT2"},
+		{"...", "..."},
+	}
+	for _, tt := range tests {
+		if have := NormalizeAssetSymbol(tt.symbol); have != tt.want {
+			t.Errorf("NormalizeAssetSymbol(%q): have %q, want %q", tt.symbol, have, tt.want)
+		}
+	}
+}
+
+func TestAssetTransferRestrictionJSON(t *testing.T) {
+	for _, r := range []AssetTransferRestriction{AssetTransferUnrestricted, AssetTransferWhitelist, AssetTransferBlacklist} {
+		enc, err := r.MarshalText()
+		if err != nil {
+			t.Fatalf("failed to marshal %v: %v", r, err)
+		}
+		var dec AssetTransferRestriction
+		if err := dec.UnmarshalText(enc); err != nil || dec != r {
+			t.Errorf("round trip of %v: have %v, err %v", r, dec, err)
+		}
+	}
+	var r AssetTransferRestriction
+	if err := r.UnmarshalText([]byte("greylist")); err == nil {
+		t.Errorf("expected error for unknown restriction")
+	}
+}
+
+func TestStreamVesting(t *testing.T) {
+	s := Stream{Value: big.NewInt(1000), StartTime: 100, EndTime: 200, Withdrawn: big.NewInt(300)}
+	tests := []struct {
+		time                 uint64
+		vested, withdrawable int64
+	}{
+		{50, 0, -300},
+		{100, 0, -300},
+		{150, 500, 200},
+		{133, 330, 30},
+		{200, 1000, 700},
+		{500, 1000, 700},
+	}
+	for _, test := range tests {
+		if got := s.Vested(test.time); got.Int64() != test.vested {
+			t.Errorf("vested at %d: got %v, want %d", test.time, got, test.vested)
+		}
+		if got := s.Withdrawable(test.time); got.Int64() != test.withdrawable {
+			t.Errorf("withdrawable at %d: got %v, want %d", test.time, got, test.withdrawable)
+		}
+	}
+	if s.Value.Int64() != 1000 || s.Withdrawn.Int64() != 300 {
+		t.Errorf("stream modified: %+v", s)
+	}
+}
+
+func TestConditionKindJSON(t *testing.T) {
+	for _, k := range []ConditionKind{HashLockCondition, OracleCondition} {
+		enc, err := k.MarshalText()
+		if err != nil {
+			t.Fatalf("failed to marshal %v: %v", k, err)
+		}
+		var dec ConditionKind
+		if err := dec.UnmarshalText(enc); err != nil || dec != k {
+			t.Errorf("round trip of %v: have %v, err %v", k, dec, err)
+		}
+	}
+	var k ConditionKind
+	if err := k.UnmarshalText([]byte("vote")); err == nil {
+		t.Errorf("expected error for unknown condition kind")
+	}
+}
+
+func TestConditionResolvedBefore(t *testing.T) {
+	c := Condition{ID: HexToHash("0x01"), Hash: HexToHash("0x02")}
+	if c.ResolvedBefore(100) {
+		t.Errorf("unresolved condition resolved")
+	}
+	c.ResolvedTime = 99
+	if !c.ResolvedBefore(100) || c.ResolvedBefore(99) {
+		t.Errorf("resolution time not compared to the deadline")
+	}
+	other := c
+	other.ID = HexToHash("0x03")
+	if c.StatementHash() == other.StatementHash() {
+		t.Errorf("statement hash ignores the condition ID")
+	}
+}
+
+func TestFSNCallFuncTopic(t *testing.T) {
+	for _, f := range []FSNCallFunc{GenNotationFunc, BuyTicketFunc, BridgeWithdrawFunc} {
+		topic := f.Topic()
+		if topic[HashLength-1] != byte(f) || BytesToHash(topic[:HashLength-1]) != (Hash{}) {
+			t.Errorf("topic of %v: have %x", f.Name(), topic)
+		}
+	}
+}
+
+func TestIsSpecialKeyAddress(t *testing.T) {
+	for _, addr := range []Address{TicketKeyAddress, TypedCallKeyAddress, StakingKeyAddress, EpochKeyAddress} {
+		if !addr.IsSpecialKeyAddress() {
+			t.Errorf("%v is not a special key address", addr.Hex())
+		}
+	}
+	for _, addr := range []Address{{}, HexToAddress("0x01"), FSNCallAddress} {
+		if addr.IsSpecialKeyAddress() {
+			t.Errorf("%v is a special key address", addr.Hex())
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package common
+
+const (
+	// MinProposalVotingBlocks is the shortest voting period of a proposal,
+	// about one day
+	MinProposalVotingBlocks = 6500
+	// MaxProposalVotingBlocks is the longest voting period of a proposal,
+	// about thirty days
+	MaxProposalVotingBlocks = 30 * MinProposalVotingBlocks
+
+	// MaxProposalTitle is the maximum length of a proposal title
+	MaxProposalTitle = 128
+	// MaxProposalDescription is the maximum length of a proposal description
+	MaxProposalDescription = 4096
+
+	// ProposalQuorumPercent is the share of the tickets alive at the creation
+	// of a proposal which must vote for its result to count
+	ProposalQuorumPercent = 20
+)
+
+// proposal states
+const (
+	ProposalActive   = "active"
+	ProposalPassed   = "passed"
+	ProposalRejected = "rejected"
+)
+
+// Proposal is a decision voted on by the ticket holders, every ticket of a
+// voter live at the creation of the proposal is one vote.
+type Proposal struct {
+	ID           Hash
+	Proposer     Address
+	Title        string
+	Description  string
+	StartHeight  uint64
+	StartTime    uint64 // block time of the creation, the snapshot of the vote weights
+	EndHeight    uint64
+	TotalTickets uint64 // live tickets at the creation, the base of the quorum
+	Approvals    uint64 // tickets voting for
+	Rejections   uint64 // tickets voting against
+	Voters       uint64
+}
+
+// ProposalVote is the vote of an address on a proposal.
+type ProposalVote struct {
+	Approve bool
+	Tickets uint64
+}
+
+// Quorum returns the number of tickets which must vote on the proposal.
+func (p *Proposal) Quorum() uint64 {
+	return (p.TotalTickets*ProposalQuorumPercent + 99) / 100
+}
+
+// Status returns the state of the proposal in block number. A proposal
+// passes when the quorum voted and more tickets voted for than against.
+func (p *Proposal) Status(number uint64) string {
+	if number <= p.EndHeight {
+		return ProposalActive
+	}
+	if p.Approvals+p.Rejections >= p.Quorum() && p.Approvals > p.Rejections {
+		return ProposalPassed
+	}
+	return ProposalRejected
+}
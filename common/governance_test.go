@@ -0,0 +1,27 @@
+package common
+
+import "testing"
+
+func TestProposalStatus(t *testing.T) {
+	tests := []struct {
+		approvals, rejections uint64
+		number                uint64
+		want                  string
+	}{
+		{0, 0, 100, ProposalActive},
+		{30, 0, 100, ProposalActive},
+		{30, 0, 101, ProposalPassed},
+		{19, 0, 101, ProposalRejected}, // below the quorum of 20 tickets
+		{10, 10, 101, ProposalRejected},
+		{15, 10, 101, ProposalPassed},
+	}
+	for i, test := range tests {
+		p := &Proposal{EndHeight: 100, TotalTickets: 100, Approvals: test.approvals, Rejections: test.rejections}
+		if have := p.Status(test.number); have != test.want {
+			t.Errorf("test %d: status mismatch: have %v, want %v", i, have, test.want)
+		}
+	}
+	if quorum := (&Proposal{TotalTickets: 101}).Quorum(); quorum != 21 {
+		t.Errorf("quorum mismatch: have %d, want 21", quorum)
+	}
+}
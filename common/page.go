@@ -0,0 +1,95 @@
+package common
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+)
+
+// List RPCs return their items in the ascending order of a unique key, and a
+// page resumes after the key of the last item of the previous page. Unlike
+// offsets, cursors neither skip nor repeat items when the list changes
+// between the pages.
+const (
+	// DefaultPageLimit is the number of items of a page without a limit
+	DefaultPageLimit = 100
+	// MaxPageLimit is the maximum number of items of a page
+	MaxPageLimit = 1000
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// PageRequest selects a page of a list RPC, Cursor is the NextCursor of the
+// previous page and empty for the first page
+type PageRequest struct {
+	Cursor string          `json:"cursor"`
+	Limit  *hexutil.Uint64 `json:"limit"`
+}
+
+// Pager walks a list ordered by key to build one page
+type Pager struct {
+	kind  string
+	after []byte // key of the last item of the previous page, nil for the first page
+	limit int
+	last  []byte
+	count int
+	more  bool
+}
+
+// NewPager checks the page request of a list of the given kind, cursors of
+// another kind of list are rejected. A nil request selects the first page.
+func NewPager(kind string, req *PageRequest) (*Pager, error) {
+	p := &Pager{kind: kind, limit: DefaultPageLimit}
+	if req == nil {
+		return p, nil
+	}
+	if req.Limit != nil {
+		if *req.Limit == 0 || *req.Limit > MaxPageLimit {
+			return nil, fmt.Errorf("page limit must be between 1 and %d", MaxPageLimit)
+		}
+		p.limit = int(*req.Limit)
+	}
+	if req.Cursor != "" {
+		blob, err := base64.RawURLEncoding.DecodeString(req.Cursor)
+		if err != nil || !bytes.HasPrefix(blob, []byte(kind+":")) {
+			return nil, errInvalidCursor
+		}
+		p.after = blob[len(kind)+1:]
+	}
+	return p, nil
+}
+
+// After returns the key of the last item of the previous page, nil for the
+// first page
+func (p *Pager) After() []byte {
+	return p.after
+}
+
+// Skip reports whether the item with the given key was on a previous page
+func (p *Pager) Skip(key []byte) bool {
+	return p.after != nil && bytes.Compare(key, p.after) <= 0
+}
+
+// Add accounts for an item with the given key, which must not be skipped. It
+// returns false if the page is full, the item then belongs to the next page
+// and the walk should stop.
+func (p *Pager) Add(key []byte) bool {
+	if p.count == p.limit {
+		p.more = true
+		return false
+	}
+	p.count++
+	p.last = CopyBytes(key)
+	return true
+}
+
+// NextCursor returns the cursor of the next page, empty after the last page
+func (p *Pager) NextCursor() string {
+	if !p.more {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(append([]byte(p.kind+":"), p.last...))
+}
@@ -0,0 +1,50 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+)
+
+func TestPager(t *testing.T) {
+	keys := [][]byte{{1}, {2}, {3}, {4}, {5}}
+	limit := hexutil.Uint64(2)
+	walk := func(req *PageRequest, keys [][]byte) ([]byte, string) {
+		pager, err := NewPager("test", req)
+		if err != nil {
+			t.Fatalf("invalid page request: %v", err)
+		}
+		var page []byte
+		for _, key := range keys {
+			if pager.Skip(key) {
+				continue
+			}
+			if !pager.Add(key) {
+				break
+			}
+			page = append(page, key[0])
+		}
+		return page, pager.NextCursor()
+	}
+	page, cursor := walk(&PageRequest{Limit: &limit}, keys)
+	if string(page) != "\x01\x02" || cursor == "" {
+		t.Fatalf("first page mismatch: %v, cursor %q", page, cursor)
+	}
+	// an item inserted before the cursor is neither repeated nor shifts the page
+	keys = append([][]byte{{0}}, keys...)
+	page, cursor = walk(&PageRequest{Cursor: cursor, Limit: &limit}, keys)
+	if string(page) != "\x03\x04" || cursor == "" {
+		t.Fatalf("second page mismatch: %v, cursor %q", page, cursor)
+	}
+	if page, cursor = walk(&PageRequest{Cursor: cursor, Limit: &limit}, keys); string(page) != "\x05" || cursor != "" {
+		t.Fatalf("last page mismatch: %v, cursor %q", page, cursor)
+	}
+
+	if _, err := NewPager("other", &PageRequest{Cursor: "dGVzdDoB"}); err == nil {
+		t.Errorf("cursor of another list accepted")
+	}
+	zero := hexutil.Uint64(0)
+	if _, err := NewPager("test", &PageRequest{Limit: &zero}); err == nil {
+		t.Errorf("zero limit accepted")
+	}
+}
@@ -8,6 +8,19 @@ import (
 	"golang.org/x/crypto/sha3"
 )
 
+// The receiveAsset interface lets an EOA send a Fusion asset or time lock to a
+// contract within a plain call. A contract accepting assets implements
+//
+//	function receiveAsset(bytes32 assetID, uint64 startTime, uint64 endTime, uint8 flag, uint256[] extraData) external payable;
+//
+// The call value is the amount of assetID that is moved from the sender to the
+// contract as with an FSN contract sendAsset of the same time range and flag,
+// before the contract code runs. extraData holds at most 20 words. Calls whose
+// input does not match this layout exactly are ordinary calls.
+//
+// Since the receive asset guard fork the contract can not call the FSN contract
+// while it runs for a receiveAsset call, except to read the received asset with
+// getReceivedAsset.
 var (
 	// If a contract want to receive Fusion Asset and TimeLock from an EOA,
 	// the contract must impl the following 'receiveAsset' interface.
@@ -68,7 +81,7 @@ func GetUint64(data []byte, start uint64, size uint64) (uint64, bool) {
 type FcSendAssetFlag uint8
 
 const (
-	FcUseAny                FcSendAssetFlag = iota // 0
+	FcUseAny                FcSendAssetFlag = iota // 0, as the SmartTransfer time lock of accounts
 	FcUseAnyToTimeLock                             // 1
 	FcUseTimeLock                                  // 2
 	FcUseTimeLockToTimeLock                        // 3
@@ -126,6 +139,21 @@ type TransferTimeLockParam struct {
 	IsReceive   bool
 }
 
+// ParseReceiveAssetPayableTx returns the time lock transfer of a receiveAsset
+// call of value at blockNumber, nil if input is not a receiveAsset call
+func ParseReceiveAssetPayableTx(blockNumber *big.Int, input []byte, value *big.Int, timestamp uint64) (*TransferTimeLockParam, error) {
+	if !IsReceiveAssetPayableTx(blockNumber, input) {
+		return nil, nil
+	}
+	p := &TransferTimeLockParam{}
+	if err := ParseReceiveAssetPayableTxInput(p, input, timestamp); err != nil {
+		return nil, err
+	}
+	p.Value = value
+	p.BlockNumber = blockNumber
+	return p, nil
+}
+
 func ParseReceiveAssetPayableTxInput(p *TransferTimeLockParam, input []byte, timestamp uint64) error {
 	p.IsReceive = true
 	p.Timestamp = timestamp
@@ -14,12 +14,24 @@ func TicketPrice(blocknumber *big.Int) *big.Int {
 	return new(big.Int).Mul(big.NewInt(5000), oneFSN)
 }
 
+// TicketDenominations are the multiples of the ticket price a ticket can be
+// bought for since the ticket denomination fork
+var TicketDenominations = []uint64{1, 5, 10}
+
+// TicketRevokePenaltyPercent is the share of the value of a ticket kept when
+// its owner revokes it before its expiry
+const TicketRevokePenaltyPercent = 1
+
 // Ticket wacom
+// Units is empty for tickets of one ticket price, so legacy encodings are
+// unchanged. A ticket of several units is selected with the weight of as
+// many tickets and each selection spends one unit.
 type TicketBody struct {
 	ID         Hash
 	Height     uint64
 	StartTime  uint64
 	ExpireTime uint64
+	Units      []uint64 `rlp:"tail"`
 }
 
 type TicketBodySlice []TicketBody
@@ -38,6 +50,41 @@ type TicketDisplay struct {
 	StartTime  uint64
 	ExpireTime uint64
 	Value      *big.Int
+	Weight     uint64
+}
+
+// TicketExpiryWindow counts the tickets expiring in [Start, End)
+type TicketExpiryWindow struct {
+	Start   uint64
+	End     uint64
+	Tickets uint64
+	Weight  uint64
+	Value   *big.Int
+}
+
+// TicketRetreatTopic is the topic of the logs of the tickets retreated by a
+// block, followed by the topics of the owner and the ticket ID
+var TicketRetreatTopic = Keccak256Hash([]byte("TicketRetreat(address,bytes32)"))
+
+// TicketRetreatTxHash is the transaction hash of the synthetic receipt holding
+// the ticket retreat logs of a block
+var TicketRetreatTxHash = Keccak256Hash([]byte("TicketRetreatReceipt"))
+
+// TicketRetreat is the data of the log of a ticket retreated by a block. The
+// tickets ranked before the selected ticket retreat and spend one unit, the
+// unit of the first one is not returned to its owner.
+type TicketRetreat struct {
+	TicketID Hash
+	Owner    Address
+	Value    *big.Int `json:",string"` // value of the spent unit
+	Returned bool     // the unit was returned as time locked FSN
+}
+
+// TicketRetreatRecord is the cumulative retreat accounting of an address
+type TicketRetreatRecord struct {
+	Retreats  uint64   // tickets retreated
+	Lost      uint64   // units of retreated tickets not returned
+	LostValue *big.Int `json:",string"` // value of the units not returned
 }
 
 type TicketsData struct {
@@ -56,9 +103,38 @@ func (t *TicketBody) BlockHeight() *big.Int {
 }
 
 func (t *TicketBody) Value() *big.Int {
+	return new(big.Int).Mul(t.UnitValue(), new(big.Int).SetUint64(t.Weight()))
+}
+
+// RevokeRefund is the value returned to the owner revoking the ticket
+func (t *TicketBody) RevokeRefund() *big.Int {
+	value := t.Value()
+	penalty := new(big.Int).Div(new(big.Int).Mul(value, big.NewInt(TicketRevokePenaltyPercent)), big.NewInt(100))
+	return value.Sub(value, penalty)
+}
+
+// UnitValue returns the value of one unit of the ticket
+func (t *TicketBody) UnitValue() *big.Int {
 	return TicketPrice(new(big.Int).SetUint64(t.Height))
 }
 
+// Weight returns the number of ticket prices the ticket is worth
+func (t *TicketBody) Weight() uint64 {
+	if len(t.Units) == 0 || t.Units[0] == 0 {
+		return 1
+	}
+	return t.Units[0]
+}
+
+// SetWeight wacom
+func (t *TicketBody) SetWeight(weight uint64) {
+	if weight <= 1 {
+		t.Units = nil
+	} else {
+		t.Units = []uint64{weight}
+	}
+}
+
 func (t *Ticket) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
 		ID         Hash
@@ -67,6 +143,7 @@ func (t *Ticket) MarshalJSON() ([]byte, error) {
 		StartTime  uint64
 		ExpireTime uint64
 		Value      string
+		Weight     uint64
 	}{
 		ID:         t.ID,
 		Owner:      t.Owner,
@@ -74,6 +151,7 @@ func (t *Ticket) MarshalJSON() ([]byte, error) {
 		StartTime:  t.StartTime,
 		ExpireTime: t.ExpireTime,
 		Value:      t.Value().String(),
+		Weight:     t.Weight(),
 	})
 }
 
@@ -99,6 +177,7 @@ func (t *Ticket) ToDisplay() TicketDisplay {
 		StartTime:  t.StartTime,
 		ExpireTime: t.ExpireTime,
 		Value:      t.Value(),
+		Weight:     t.Weight(),
 	}
 }
 
@@ -177,6 +256,40 @@ func (s TicketsDataSlice) NumberOfTicketsByAddress(addr Address) uint64 {
 	return 0
 }
 
+// NumberOfLiveTickets counts the tickets of addr and of all owners which
+// have not expired at timestamp
+func (s TicketsDataSlice) NumberOfLiveTickets(addr Address, timestamp uint64) (owned, total uint64) {
+	for _, v := range s {
+		for _, t := range v.Tickets {
+			if t.ExpireTime <= timestamp {
+				continue
+			}
+			total++
+			if v.Owner == addr {
+				owned++
+			}
+		}
+	}
+	return owned, total
+}
+
+// NumberOfLiveTicketsAt returns the number of tickets of addr which were
+// bought at or before block height and live at timestamp.
+func (s TicketsDataSlice) NumberOfLiveTicketsAt(addr Address, height, timestamp uint64) uint64 {
+	var owned uint64
+	for _, v := range s {
+		if v.Owner != addr {
+			continue
+		}
+		for _, t := range v.Tickets {
+			if t.Height <= height && t.ExpireTime > timestamp {
+				owned++
+			}
+		}
+	}
+	return owned
+}
+
 func (s TicketsDataSlice) NumberOfTickets() uint64 {
 	numTickets := 0
 	for _, v := range s {
@@ -185,6 +298,18 @@ func (s TicketsDataSlice) NumberOfTickets() uint64 {
 	return uint64(numTickets)
 }
 
+// TotalWeight returns the selection weight of all tickets, the number of
+// tickets unless some are worth several ticket prices
+func (s TicketsDataSlice) TotalWeight() uint64 {
+	weight := uint64(0)
+	for _, v := range s {
+		for i := range v.Tickets {
+			weight += v.Tickets[i].Weight()
+		}
+	}
+	return weight
+}
+
 func (s TicketsDataSlice) NumberOfOwners() uint64 {
 	return uint64(len(s))
 }
@@ -204,57 +329,156 @@ func (s TicketsDataSlice) Get(id Hash) (*Ticket, error) {
 	return nil, fmt.Errorf("%v ticket not fount", id.String())
 }
 
+// TicketsDataSlice values are shared between states and the ticket cache, so
+// they are never modified in place. AddTicket, RemoveTicket and
+// ClearExpiredTickets return a new slice which shares the tickets of all
+// unmodified owners with the original one.
+
 func (s TicketsDataSlice) AddTicket(ticket *Ticket) (TicketsDataSlice, error) {
-	var tickets TicketBodySlice
-	row := 0
+	row := -1
 	for i, v := range s {
 		if v.Owner == ticket.Owner {
-			tickets = v.Tickets
 			row = i
 			break
 		}
 	}
-	if tickets == nil {
-		s = append(s, TicketsData{
+	if row < 0 || s[row].Tickets == nil {
+		res := make(TicketsDataSlice, len(s), len(s)+1)
+		copy(res, s)
+		res = append(res, TicketsData{
 			Owner:   ticket.Owner,
 			Tickets: TicketBodySlice{ticket.TicketBody},
 		})
-		return s, nil
+		return res, nil
 	}
 
-	if ticket.IsInGenesis() {
-		tickets = append(tickets, ticket.TicketBody)
-	} else {
+	tickets := s[row].Tickets
+	if !ticket.IsInGenesis() {
 		for _, t := range tickets {
 			if t.ID == ticket.ID {
 				log.Info("AddTicket: ticket exist", "id", ticket.ID.String())
 				return s, fmt.Errorf("AddTicket: %v ticket exist", ticket.ID.String())
 			}
 		}
-		tickets = append(tickets, ticket.TicketBody)
 	}
-	s[row].Tickets = tickets
-	return s, nil
+	res := s.copyOwners()
+	res[row].Tickets = append(tickets[:len(tickets):len(tickets)], ticket.TicketBody)
+	return res, nil
 }
 
 func (s TicketsDataSlice) RemoveTicket(id Hash) (TicketsDataSlice, error) {
+	res := s.copyOwners()
+	if res.removeTicket(id, make(map[Address]bool)) {
+		return res, nil
+	}
+	log.Info("RemoveTicket: ticket not found", "id", id.String())
+	return s, fmt.Errorf("RemoveTicket: %v ticket not found", id.String())
+}
+
+// SpendTicket removes one unit of the ticket with the given id, removing
+// the ticket when it was its last unit
+func (s TicketsDataSlice) SpendTicket(id Hash) (TicketsDataSlice, error) {
 	for i, v := range s {
+		for j, t := range v.Tickets {
+			if t.ID != id {
+				continue
+			}
+			if t.Weight() <= 1 {
+				return s.RemoveTicket(id)
+			}
+			res := s.copyOwners()
+			res[i].Tickets = v.Tickets.DeepCopy()
+			res[i].Tickets[j].SetWeight(t.Weight() - 1)
+			return res, nil
+		}
+	}
+	log.Info("SpendTicket: ticket not found", "id", id.String())
+	return s, fmt.Errorf("SpendTicket: %v ticket not found", id.String())
+}
+
+// copyOwners returns a copy of the slice sharing the tickets of every owner
+func (s TicketsDataSlice) copyOwners() TicketsDataSlice {
+	res := make(TicketsDataSlice, len(s))
+	copy(res, s)
+	return res
+}
+
+// removeTicket removes the first ticket with the given id in place. The owner
+// slice must belong to the caller, the tickets of an owner are copied before
+// they are modified unless the owner is already recorded in copied.
+func (s *TicketsDataSlice) removeTicket(id Hash, copied map[Address]bool) bool {
+	for i, v := range *s {
 		tickets := v.Tickets
 		for j, t := range tickets {
 			if t.ID != id {
 				continue
 			}
 			if len(tickets) == 1 {
-				s = append(s[:i], s[i+1:]...)
+				*s = append((*s)[:i], (*s)[i+1:]...)
 			} else {
-				tickets = append(tickets[:j], tickets[j+1:]...)
-				s[i].Tickets = tickets
+				if !copied[v.Owner] {
+					tickets = tickets.DeepCopy()
+					copied[v.Owner] = true
+				}
+				(*s)[i].Tickets = append(tickets[:j], tickets[j+1:]...)
 			}
-			return s, nil
+			return true
 		}
 	}
-	log.Info("RemoveTicket: ticket not found", "id", id.String())
-	return s, fmt.Errorf("RemoveTicket: %v ticket not found", id.String())
+	return false
+}
+
+// OwnerRows maps every owner to the rows holding its tickets, in order. An
+// owner normally has a single row.
+func (s TicketsDataSlice) OwnerRows() map[Address][]int {
+	rows := make(map[Address][]int, len(s))
+	for i, v := range s {
+		rows[v.Owner] = append(rows[v.Owner], i)
+	}
+	return rows
+}
+
+// RemoveRows returns the slice without the given rows, which must be sorted
+// in ascending order.
+func (s TicketsDataSlice) RemoveRows(rows []int) TicketsDataSlice {
+	res := make(TicketsDataSlice, 0, len(s))
+	next := 0
+	for i, v := range s {
+		if next < len(rows) && rows[next] == i {
+			next++
+			continue
+		}
+		res = append(res, v)
+	}
+	return res
+}
+
+// ExpirySchedule groups the tickets of owner, or all tickets if owner is nil,
+// expiring after from and before to in windows of window seconds.
+func (s TicketsDataSlice) ExpirySchedule(owner *Address, from, to, window uint64) []TicketExpiryWindow {
+	if window == 0 || to <= from {
+		return nil
+	}
+	res := make([]TicketExpiryWindow, (to-from+window-1)/window)
+	for i := range res {
+		start := from + uint64(i)*window
+		res[i] = TicketExpiryWindow{Start: start, End: MinUint64(start+window, to), Value: new(big.Int)}
+	}
+	for _, v := range s {
+		if owner != nil && v.Owner != *owner {
+			continue
+		}
+		for _, t := range v.Tickets {
+			if t.ExpireTime <= from || t.ExpireTime >= to {
+				continue
+			}
+			w := &res[(t.ExpireTime-from)/window]
+			w.Tickets++
+			w.Weight += t.Weight()
+			w.Value.Add(w.Value, t.Value())
+		}
+	}
+	return res
 }
 
 func (s TicketsDataSlice) ClearExpiredTickets(timestamp uint64) (TicketsDataSlice, error) {
@@ -275,8 +499,10 @@ func (s TicketsDataSlice) ClearExpiredTickets(timestamp uint64) (TicketsDataSlic
 	if len(expiredIds) == 0 {
 		return s, nil
 	}
+	res := s.copyOwners()
+	copied := make(map[Address]bool)
 	for _, id := range expiredIds {
-		s, _ = s.RemoveTicket(id)
+		res.removeTicket(id, copied)
 	}
-	return s, nil
+	return res, nil
 }
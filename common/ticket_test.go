@@ -0,0 +1,200 @@
+package common
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+func randomTickets(rnd *rand.Rand, owners, perOwner int) TicketsDataSlice {
+	var tickets TicketsDataSlice
+	for i := 0; i < owners; i++ {
+		var owner Address
+		rnd.Read(owner[:])
+		data := TicketsData{Owner: owner}
+		for j := 0; j < rnd.Intn(perOwner)+1; j++ {
+			var id Hash
+			rnd.Read(id[:])
+			data.Tickets = append(data.Tickets, TicketBody{
+				ID:         id,
+				Height:     uint64(rnd.Intn(1000) + 1),
+				StartTime:  uint64(rnd.Intn(1000)),
+				ExpireTime: uint64(rnd.Intn(1000) + 1000),
+			})
+		}
+		tickets = append(tickets, data)
+	}
+	return tickets
+}
+
+func ticketsRLP(t *testing.T, tickets TicketsDataSlice) string {
+	blob, err := rlp.EncodeToBytes(&tickets)
+	if err != nil {
+		t.Fatalf("failed to encode tickets: %v", err)
+	}
+	return string(blob)
+}
+
+// Modifications must leave the original slice untouched, as it is shared with
+// the ticket cache and other states.
+func TestTicketsCopyOnWrite(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		tickets := randomTickets(rnd, 10, 5)
+		orig := ticketsRLP(t, tickets)
+
+		all := tickets.ToTicketSlice()
+		victim := all[rnd.Intn(len(all))]
+		removed, err := tickets.RemoveTicket(victim.ID)
+		if err != nil {
+			t.Fatalf("failed to remove ticket: %v", err)
+		}
+		if _, err := removed.Get(victim.ID); err == nil {
+			t.Fatalf("removed ticket still present")
+		}
+		if removed.NumberOfTickets() != tickets.NumberOfTickets()-1 {
+			t.Fatalf("ticket count mismatch: have %d, want %d", removed.NumberOfTickets(), tickets.NumberOfTickets()-1)
+		}
+
+		added, err := removed.AddTicket(&Ticket{Owner: victim.Owner, TicketBody: TicketBody{ID: HexToHash("0x01"), ExpireTime: 2000}})
+		if err != nil {
+			t.Fatalf("failed to add ticket: %v", err)
+		}
+		if _, err := added.Get(HexToHash("0x01")); err != nil {
+			t.Fatalf("added ticket missing: %v", err)
+		}
+		if _, err := removed.Get(HexToHash("0x01")); err == nil {
+			t.Fatalf("ticket added to the original slice")
+		}
+
+		cleared, err := added.ClearExpiredTickets(uint64(rnd.Intn(1000) + 1000))
+		if err != nil {
+			t.Fatalf("failed to clear tickets: %v", err)
+		}
+		for _, v := range cleared {
+			if len(v.Tickets) == 0 {
+				t.Fatalf("owner without tickets kept")
+			}
+		}
+		if have := ticketsRLP(t, tickets); have != orig {
+			t.Fatalf("original tickets modified")
+		}
+	}
+}
+
+func BenchmarkTicketsRemove(b *testing.B) {
+	tickets := randomTickets(rand.New(rand.NewSource(1)), 500, 40)
+	all := tickets.ToTicketSlice()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// a block removes the selected and retreated tickets
+		res := tickets
+		for j := 0; j < 3; j++ {
+			res, _ = res.RemoveTicket(all[(i+j*97)%len(all)].ID)
+		}
+	}
+}
+
+func TestTicketsRemoveOwnerRows(t *testing.T) {
+	tickets := randomTickets(rand.New(rand.NewSource(2)), 6, 3)
+	owner := tickets[1].Owner
+	// a second row of the same owner
+	tickets = append(tickets, TicketsData{Owner: owner, Tickets: TicketBodySlice{{ID: HexToHash("0x02"), ExpireTime: 2000}}})
+	orig := ticketsRLP(t, tickets)
+
+	rows := tickets.OwnerRows()[owner]
+	if len(rows) != 2 || rows[0] != 1 || rows[1] != len(tickets)-1 {
+		t.Fatalf("owner rows mismatch: have %v", rows)
+	}
+	removed := tickets.RemoveRows(rows)
+	if len(removed) != len(tickets)-2 {
+		t.Fatalf("owner count mismatch: have %d, want %d", len(removed), len(tickets)-2)
+	}
+	if n := removed.NumberOfTicketsByAddress(owner); n != 0 {
+		t.Fatalf("%d tickets of the removed owner left", n)
+	}
+	if removed[0].Owner != tickets[0].Owner || removed[1].Owner != tickets[2].Owner {
+		t.Fatalf("owner order not kept")
+	}
+	if have := ticketsRLP(t, tickets); have != orig {
+		t.Fatalf("original tickets modified")
+	}
+}
+
+// Tickets of one ticket price keep their legacy encoding, and a ticket of
+// several units is spent one unit at a time.
+func TestTicketUnits(t *testing.T) {
+	legacy := struct {
+		ID         Hash
+		Height     uint64
+		StartTime  uint64
+		ExpireTime uint64
+	}{HexToHash("0x01"), 10, 20, 30}
+	body := TicketBody{ID: legacy.ID, Height: 10, StartTime: 20, ExpireTime: 30}
+	want, _ := rlp.EncodeToBytes(&legacy)
+	if have, _ := rlp.EncodeToBytes(&body); string(have) != string(want) {
+		t.Fatalf("legacy ticket encoding changed: have %x, want %x", have, want)
+	}
+
+	body.SetWeight(5)
+	blob, _ := rlp.EncodeToBytes(&body)
+	var dec TicketBody
+	if err := rlp.DecodeBytes(blob, &dec); err != nil || dec.Weight() != 5 {
+		t.Fatalf("weighted ticket decoding mismatch: weight %d, err %v", dec.Weight(), err)
+	}
+	if dec.Value().Cmp(new(big.Int).Mul(TicketPrice(nil), big.NewInt(5))) != 0 {
+		t.Errorf("weighted ticket value mismatch: %v", dec.Value())
+	}
+
+	owner := HexToAddress("0x02")
+	tickets := TicketsDataSlice{{Owner: owner, Tickets: TicketBodySlice{dec, {ID: HexToHash("0x03")}}}}
+	orig := ticketsRLP(t, tickets)
+	if tickets.TotalWeight() != 6 || tickets.NumberOfTickets() != 2 {
+		t.Fatalf("weight mismatch: have %d weight %d tickets", tickets.TotalWeight(), tickets.NumberOfTickets())
+	}
+	spent := tickets
+	for i := 0; i < 4; i++ {
+		var err error
+		if spent, err = spent.SpendTicket(dec.ID); err != nil {
+			t.Fatalf("failed to spend ticket: %v", err)
+		}
+	}
+	if ticket, err := spent.Get(dec.ID); err != nil || ticket.Weight() != 1 || len(ticket.Units) != 0 {
+		t.Fatalf("spent ticket mismatch: %v, %v", ticket, err)
+	}
+	if spent, _ = spent.SpendTicket(dec.ID); spent.NumberOfTickets() != 1 {
+		t.Fatalf("last unit did not remove the ticket")
+	}
+	if ticketsRLP(t, tickets) != orig {
+		t.Fatalf("spending modified the original tickets")
+	}
+}
+
+func TestTicketExpirySchedule(t *testing.T) {
+	a, b := HexToAddress("0x01"), HexToAddress("0x02")
+	weighted := TicketBody{ID: HexToHash("0x13"), ExpireTime: 250}
+	weighted.SetWeight(5)
+	tickets := TicketsDataSlice{
+		{Owner: a, Tickets: TicketBodySlice{{ID: HexToHash("0x11"), ExpireTime: 100}, {ID: HexToHash("0x12"), ExpireTime: 150}, weighted}},
+		{Owner: b, Tickets: TicketBodySlice{{ID: HexToHash("0x21"), ExpireTime: 120}, {ID: HexToHash("0x22"), ExpireTime: 400}}},
+	}
+	windows := tickets.ExpirySchedule(nil, 100, 350, 100)
+	if len(windows) != 3 || windows[2].End != 350 {
+		t.Fatalf("window mismatch: %+v", windows)
+	}
+	// the ticket expiring at 100 is already expired, the one at 400 is past the horizon
+	for i, want := range []struct{ tickets, weight uint64 }{{2, 2}, {1, 5}, {0, 0}} {
+		if windows[i].Tickets != want.tickets || windows[i].Weight != want.weight {
+			t.Errorf("window %d mismatch: have %d tickets weight %d, want %d weight %d", i, windows[i].Tickets, windows[i].Weight, want.tickets, want.weight)
+		}
+	}
+	if windows[1].Value.Cmp(weighted.Value()) != 0 {
+		t.Errorf("window value mismatch: have %v, want %v", windows[1].Value, weighted.Value())
+	}
+	if windows = tickets.ExpirySchedule(&b, 100, 350, 100); windows[0].Tickets != 1 || windows[1].Tickets != 0 {
+		t.Errorf("owner schedule mismatch: %+v", windows)
+	}
+}
@@ -2,7 +2,6 @@ package common
 
 import (
 	"encoding/json"
-	"fmt"
 	"math/big"
 	"sort"
 
@@ -36,10 +35,10 @@ type TimeLockItem struct {
 
 func (z *TimeLockItem) IsValid() error {
 	if z.StartTime > z.EndTime {
-		return fmt.Errorf("TimeLockItem time is invalid, StartTime:%v > EndTime:%v", z.StartTime, z.EndTime)
+		return NewFsnError(FsnErrInvalidParam, "TimeLockItem time is invalid, StartTime:%v > EndTime:%v", z.StartTime, z.EndTime)
 	}
 	if z.Value.Sign() <= 0 {
-		return fmt.Errorf("TimeLockItem value is invalid, Value:%v", z.Value)
+		return NewFsnError(FsnErrInvalidParam, "TimeLockItem value is invalid, Value:%v", z.Value)
 	}
 	return nil
 }
@@ -48,7 +47,7 @@ func (z *TimeLockItem) Clone() *TimeLockItem {
 	return &TimeLockItem{
 		StartTime: z.StartTime,
 		EndTime:   z.EndTime,
-		Value:     new(big.Int).SetBytes(z.Value.Bytes()),
+		Value:     new(big.Int).Abs(z.Value),
 	}
 }
 
@@ -69,7 +68,7 @@ func (z *TimeLockItem) AdjustStartEnd(startTime uint64, endTime uint64) *TimeLoc
 	return &TimeLockItem{
 		StartTime: startTime,
 		EndTime:   endTime,
-		Value:     new(big.Int).SetBytes(z.Value.Bytes()),
+		Value:     new(big.Int).Abs(z.Value),
 	}
 }
 
@@ -215,6 +214,11 @@ func intersectionSub(x, y *TimeLockItem) *TimeLockItem {
 
 /////////////////////////////// TimeLock ///////////////////////////
 // TimeLock wacom
+// The items are sorted and never overlap, so the item holding a time is found
+// by a binary search on the end times as an interval tree would find it, and
+// a range is the run of items following it. Cmp, CanSub and ClearExpired
+// search this way instead of scanning, Add and Sub rebuild the items in one
+// pass as the stored balance is a copy of them anyway.
 type TimeLock struct {
 	Items []*TimeLockItem
 }
@@ -264,7 +268,7 @@ func (z *TimeLock) Add(x, y *TimeLock) *TimeLock {
 		z.Set(x)
 		return z
 	}
-	items := make([]*TimeLockItem, 0)
+	items := make([]*TimeLockItem, 0, len(x.Items)+2*len(y.Items))
 	i, j := 0, 0
 	var xV, yV *TimeLockItem
 	for i < len(x.Items) && j < len(y.Items) {
@@ -319,7 +323,8 @@ func (z *TimeLock) Sub(x, y *TimeLock) *TimeLock {
 		log.Info("TimeLock::Sub failed", "x", x.RawString(), "y", y.RawString())
 		panic("Sub TimeLock not enough")
 	}
-	items := make([]*TimeLockItem, 0)
+	// each item of y splits at most one item of x in three
+	items := make([]*TimeLockItem, 0, len(x.Items)+2*len(y.Items))
 	i, j := 0, 0
 	var xV, yV *TimeLockItem
 	for i < len(x.Items) && j < len(y.Items) {
@@ -329,6 +334,17 @@ func (z *TimeLock) Sub(x, y *TimeLock) *TimeLock {
 		if yV == nil {
 			yV = y.Items[j]
 		}
+		if xV == x.Items[i] && xV.EndTime < yV.StartTime {
+			// skip to the items reaching the subtracted range at once, the
+			// ones before are merged one by one as the linear scan did
+			k := x.search(yV.StartTime, i)
+			for _, item := range x.Items[i:k] {
+				items = appendAndMergeItem(items, item)
+			}
+			i = k
+			xV = nil
+			continue
+		}
 		res, missing := xV.Sub(yV)
 		if xV.EndTime <= yV.EndTime {
 			i++
@@ -362,8 +378,12 @@ func (z *TimeLock) CanSub(x *TimeLock) bool {
 	if err := x.IsValid(); err != nil {
 		return false
 	}
+	// the items of x are sorted, so the search for each one continues from
+	// where the search for the previous one ended
+	from := 0
 	for _, item := range x.Items {
-		value := z.GetSpendableValue(item.StartTime, item.EndTime)
+		from = z.search(item.StartTime, from)
+		value := z.spendableValue(from, item.StartTime, item.EndTime)
 		cmp := value.Cmp(item.Value)
 		if cmp < 0 {
 			return false
@@ -384,6 +404,25 @@ func (z *TimeLock) Cmp(x *TimeLock) int {
 }
 
 func (z *TimeLock) GetSpendableValue(start, end uint64) *big.Int {
+	if start > end || z.IsEmpty() {
+		return big.NewInt(0)
+	}
+	return z.spendableValue(z.search(start, 0), start, end)
+}
+
+// search returns the index of the first item at or after from which ends at or
+// after timestamp. Items are sorted and do not overlap, so their end times are
+// ascending too and a binary search skips all items ending earlier.
+func (z *TimeLock) search(timestamp uint64, from int) int {
+	return from + sort.Search(len(z.Items)-from, func(i int) bool {
+		return z.Items[from+i].EndTime >= timestamp
+	})
+}
+
+// spendableValue returns the value spendable over the whole range [start, end],
+// starting the scan at the item with index from, which must be the first item
+// not ending before start
+func (z *TimeLock) spendableValue(from int, start, end uint64) *big.Int {
 	if start > end || z.IsEmpty() {
 		return big.NewInt(0)
 	}
@@ -392,11 +431,13 @@ func (z *TimeLock) GetSpendableValue(start, end uint64) *big.Int {
 	}
 	result := big.NewInt(0)
 	var tempEnd uint64
-	for _, item := range z.Items {
-		if item.EndTime < start {
-			continue
-		}
-		if tempEnd == 0 {
+	for i, item := range z.Items[from:] {
+		// the first item is found by its index, not by tempEnd == 0, which
+		// is also the end of an item ending at time 0. Stored balances are
+		// cleared of the items expired at the block time on every change,
+		// so no balance on chain holds such an item and only the time locks
+		// built for a query or a check, e.g. by the RPC APIs, see it.
+		if i == 0 {
 			if item.StartTime > start {
 				return big.NewInt(0) // has head gap
 			}
@@ -418,12 +459,9 @@ func (z *TimeLock) GetSpendableValue(start, end uint64) *big.Int {
 }
 
 func (z *TimeLock) ClearExpired(timestamp uint64) *TimeLock {
-	for i := 0; i < len(z.Items); i++ {
-		t := z.Items[i]
-		if t.EndTime >= timestamp {
-			z.Items = z.Items[i:]
-			return z
-		}
+	if i := z.search(timestamp, 0); i < len(z.Items) {
+		z.Items = z.Items[i:]
+		return z
 	}
 	z.Items = []*TimeLockItem{}
 	return z
@@ -437,11 +475,11 @@ func (z *TimeLock) IsValid() error {
 	for i := 0; i < len(z.Items); i++ {
 		next = z.Items[i]
 		if err := next.IsValid(); err != nil {
-			return fmt.Errorf("TimeLock is invalid, index:%v err:%v", i, err)
+			return NewFsnError(FsnErrInvalidParam, "TimeLock is invalid, index:%v err:%v", i, err)
 		}
 		if prev != nil {
 			if prev.EndTime >= next.StartTime || prev.CanMerge(next) {
-				return fmt.Errorf("TimeLock is invalid, index:%v, prev:%v, next:%v", i, prev, next)
+				return NewFsnError(FsnErrInvalidParam, "TimeLock is invalid, index:%v, prev:%v, next:%v", i, prev, next)
 			}
 		}
 		prev = next
@@ -482,10 +520,28 @@ func (z *TimeLock) Set(x *TimeLock) *TimeLock {
 }
 
 // SetItems wacom
+// The clones of the items share one array for the items, one for their values
+// and one for the words of the values, every Add, Sub and Set of a balance
+// copies all its items and three allocations per item dominated their cost.
 func (z *TimeLock) SetItems(items []*TimeLockItem) {
+	words := 0
+	for _, item := range items {
+		words += len(item.Value.Bits())
+	}
+	var (
+		clones = make([]TimeLockItem, len(items))
+		values = make([]big.Int, len(items))
+		bits   = make([]big.Word, words)
+	)
 	z.Items = make([]*TimeLockItem, len(items))
-	for i := 0; i < len(items); i++ {
-		z.Items[i] = items[i].Clone()
+	for i, item := range items {
+		n := copy(bits, item.Value.Bits())
+		// the capacity is capped so that a value growing never writes over
+		// the words of the next one
+		values[i].SetBits(bits[:n:n])
+		bits = bits[n:]
+		clones[i] = TimeLockItem{StartTime: item.StartTime, EndTime: item.EndTime, Value: &values[i]}
+		z.Items[i] = &clones[i]
 	}
 }
 
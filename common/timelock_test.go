@@ -0,0 +1,300 @@
+package common
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// linearSpendableValue is the linear scan GetSpendableValue used before the
+// binary search, kept as a reference for the consensus critical results.
+func linearSpendableValue(z *TimeLock, start, end uint64) *big.Int {
+	if start > end || z.IsEmpty() {
+		return big.NewInt(0)
+	}
+	if z.Items[len(z.Items)-1].EndTime < end {
+		return big.NewInt(0)
+	}
+	result := big.NewInt(0)
+	var tempEnd uint64
+	first := true
+	for _, item := range z.Items {
+		if item.EndTime < start {
+			continue
+		}
+		if first {
+			first = false
+			if item.StartTime > start {
+				return big.NewInt(0)
+			}
+			result = item.Value
+		} else {
+			if item.StartTime != tempEnd+1 {
+				return big.NewInt(0)
+			}
+			if item.Value.Cmp(result) < 0 {
+				result = item.Value
+			}
+		}
+		tempEnd = item.EndTime
+		if tempEnd >= end {
+			break
+		}
+	}
+	return result
+}
+
+func linearCanSub(z, x *TimeLock) bool {
+	if x.IsEmpty() {
+		return true
+	}
+	if err := x.IsValid(); err != nil {
+		return false
+	}
+	for _, item := range x.Items {
+		if linearSpendableValue(z, item.StartTime, item.EndTime).Cmp(item.Value) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// linearSub is the Sub used before the binary search, kept as a reference
+// for the consensus critical results.
+func linearSub(x, y *TimeLock) *TimeLock {
+	items := make([]*TimeLockItem, 0)
+	i, j := 0, 0
+	var xV, yV *TimeLockItem
+	for i < len(x.Items) && j < len(y.Items) {
+		if xV == nil {
+			xV = x.Items[i]
+		}
+		if yV == nil {
+			yV = y.Items[j]
+		}
+		res, missing := xV.Sub(yV)
+		if xV.EndTime <= yV.EndTime {
+			i++
+			xV = nil
+			items = appendAndMergeItems(items, res)
+		} else {
+			items = appendAndMergeItems(items, res[:len(res)-1])
+			xV = res[len(res)-1]
+		}
+		if missing == nil {
+			j++
+			if j == len(y.Items) && xV != nil {
+				items = appendAndMergeItem(items, xV)
+				i++
+			}
+		}
+		yV = missing
+	}
+	if i < len(x.Items) {
+		items = appendAndMergeItems(items, x.Items[i:])
+	}
+	return &TimeLock{Items: items}
+}
+
+// unmergedTimeLock builds a time lock of adjacent items which often have the
+// same value, without merging them as Add would.
+func unmergedTimeLock(rnd *rand.Rand, items int) *TimeLock {
+	tl := &TimeLock{}
+	start := uint64(rnd.Int63n(100))
+	for i := 0; i < items; i++ {
+		end := start + uint64(rnd.Int63n(50))
+		tl.Items = append(tl.Items, &TimeLockItem{StartTime: start, EndTime: end, Value: big.NewInt(rnd.Int63n(3) + 10)})
+		start = end + 1 + uint64(rnd.Int63n(2)) // leave a gap now and then
+	}
+	return tl
+}
+
+// randomTimeLock builds a time lock by adding random items, the way balances
+// grow by repeated time lock transfers.
+func randomTimeLock(rnd *rand.Rand, items int, span uint64) *TimeLock {
+	tl := NewTimeLock()
+	for i := 0; i < items; i++ {
+		start := uint64(rnd.Int63n(int64(span)))
+		end := start + uint64(rnd.Int63n(int64(span/4)+1))
+		if rnd.Intn(8) == 0 {
+			end = TimeLockForever
+		}
+		item := &TimeLockItem{StartTime: start, EndTime: end, Value: big.NewInt(rnd.Int63n(1000) + 1)}
+		tl = new(TimeLock).Add(tl, NewTimeLock(item))
+	}
+	return tl
+}
+
+func TestTimeLockSearchMatchesLinearScan(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		z := randomTimeLock(rnd, rnd.Intn(20)+1, 1000)
+		x := randomTimeLock(rnd, rnd.Intn(4)+1, 1000)
+
+		for j := 0; j < 20; j++ {
+			start := uint64(rnd.Int63n(1200))
+			end := start + uint64(rnd.Int63n(400))
+			have, want := z.GetSpendableValue(start, end), linearSpendableValue(z, start, end)
+			if have.Cmp(want) != 0 {
+				t.Fatalf("spendable value mismatch for [%d, %d] of %v: have %v, want %v", start, end, z.RawString(), have, want)
+			}
+		}
+		canSub := z.CanSub(x)
+		if want := linearCanSub(z, x); canSub != want {
+			t.Fatalf("CanSub mismatch of %v - %v: have %v, want %v", z.RawString(), x.RawString(), canSub, want)
+		}
+		if canSub {
+			diff := new(TimeLock).Sub(z, x)
+			if err := diff.IsValid(); err != nil {
+				t.Fatalf("invalid difference of %v - %v: %v", z.RawString(), x.RawString(), err)
+			}
+			if sum := new(TimeLock).Add(diff, x); !sum.EqualTo(z) {
+				t.Fatalf("difference does not add up: %v - %v = %v", z.RawString(), x.RawString(), diff.RawString())
+			}
+		}
+		timestamp := uint64(rnd.Int63n(1200))
+		cleared := z.Clone().ClearExpired(timestamp)
+		for k, item := range z.Items {
+			if item.EndTime >= timestamp {
+				if !cleared.EqualTo(&TimeLock{Items: z.Items[k:]}) {
+					t.Fatalf("ClearExpired(%d) of %v: have %v", timestamp, z.RawString(), cleared.RawString())
+				}
+				break
+			}
+		}
+	}
+}
+
+// mainnetLikeTimeLock builds a time lock with the given number of daily
+// segments, the shape of miner balances which receive a time locked reward
+// for every block and a ticket refund every day.
+func mainnetLikeTimeLock(segments int) *TimeLock {
+	const day = 24 * 3600
+	items := make([]*TimeLockItem, segments)
+	base := uint64(1560000000)
+	for i := range items {
+		end := base + uint64(i+1)*day - 1
+		if i == segments-1 {
+			end = TimeLockForever
+		}
+		items[i] = &TimeLockItem{
+			StartTime: base + uint64(i)*day,
+			EndTime:   end,
+			Value:     new(big.Int).Mul(big.NewInt(int64(5000+i%7)), big.NewInt(1e18)),
+		}
+	}
+	return NewTimeLock(items...)
+}
+
+// ticketNeedValue is the time lock a BuyTicket call requires, one month from
+// the middle of the balance.
+func ticketNeedValue(balance *TimeLock) *TimeLock {
+	start := balance.Items[len(balance.Items)/2].StartTime
+	return NewTimeLock(&TimeLockItem{
+		StartTime: start,
+		EndTime:   start + 30*24*3600,
+		Value:     new(big.Int).Mul(big.NewInt(5000), big.NewInt(1e18)),
+	})
+}
+
+// timeLockShapes are the numbers of segments of mainnet balances: most hold a
+// single time lock, ticket buyers a few, and miners receiving time locked
+// rewards and ticket refunds every day thousands.
+var timeLockShapes = []int{1, 4, 32, 256, 2000}
+
+func benchmarkTimeLockShapes(b *testing.B, run func(b *testing.B, balance *TimeLock)) {
+	for _, segments := range timeLockShapes {
+		balance := mainnetLikeTimeLock(segments)
+		b.Run(fmt.Sprintf("segments-%d", segments), func(b *testing.B) {
+			b.ReportAllocs()
+			run(b, balance)
+		})
+	}
+}
+
+func BenchmarkTimeLockCmp(b *testing.B) {
+	benchmarkTimeLockShapes(b, func(b *testing.B, balance *TimeLock) {
+		need := ticketNeedValue(balance)
+		for i := 0; i < b.N; i++ {
+			balance.Cmp(need)
+		}
+	})
+}
+
+func BenchmarkTimeLockSub(b *testing.B) {
+	benchmarkTimeLockShapes(b, func(b *testing.B, balance *TimeLock) {
+		need := ticketNeedValue(balance)
+		for i := 0; i < b.N; i++ {
+			new(TimeLock).Sub(balance, need)
+		}
+	})
+}
+
+func BenchmarkTimeLockAdd(b *testing.B) {
+	benchmarkTimeLockShapes(b, func(b *testing.B, balance *TimeLock) {
+		refund := ticketNeedValue(balance)
+		for i := 0; i < b.N; i++ {
+			new(TimeLock).Add(balance, refund)
+		}
+	})
+}
+
+func BenchmarkTimeLockClearExpired(b *testing.B) {
+	benchmarkTimeLockShapes(b, func(b *testing.B, balance *TimeLock) {
+		timestamp := balance.Items[len(balance.Items)*3/4].StartTime
+		for i := 0; i < b.N; i++ {
+			tl := &TimeLock{Items: balance.Items}
+			tl.ClearExpired(timestamp)
+		}
+	})
+}
+
+func TestTimeLockSpendableValueFromTimeZero(t *testing.T) {
+	z := NewTimeLock(
+		&TimeLockItem{StartTime: 0, EndTime: 0, Value: big.NewInt(4)},
+		&TimeLockItem{StartTime: 1, EndTime: 5, Value: big.NewInt(6)},
+	)
+	if have := z.GetSpendableValue(0, 5); have.Cmp(big.NewInt(4)) != 0 {
+		t.Errorf("spendable value of %v over [0, 5]: have %v, want 4", z.RawString(), have)
+	}
+	x := NewTimeLock(&TimeLockItem{StartTime: 0, EndTime: 5, Value: big.NewInt(4)})
+	if !z.CanSub(x) {
+		t.Errorf("%v can not subtract %v", z.RawString(), x.RawString())
+	}
+}
+
+func TestTimeLockSubMatchesLinearScan(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	for i := 0; i < 2000; i++ {
+		var z *TimeLock
+		if i%2 == 0 {
+			z = unmergedTimeLock(rnd, rnd.Intn(12)+1)
+		} else {
+			z = randomTimeLock(rnd, rnd.Intn(20)+1, 1000)
+		}
+		x := randomTimeLock(rnd, rnd.Intn(4)+1, 1000)
+		if !z.CanSub(x) {
+			// subtract a part of the spendable value of a random range
+			start := uint64(rnd.Int63n(1000))
+			end := start + uint64(rnd.Int63n(200))
+			value := z.GetSpendableValue(start, end)
+			if value.Sign() == 0 {
+				continue
+			}
+			x = NewTimeLock(&TimeLockItem{StartTime: start, EndTime: end, Value: new(big.Int).Rand(rnd, value)})
+			if x.Items[0].Value.Sign() == 0 || !z.CanSub(x) {
+				continue
+			}
+		}
+		have, want := new(TimeLock).Sub(z, x), linearSub(z, x)
+		if len(have.Items) != len(want.Items) {
+			t.Fatalf("Sub of %v - %v: have %v, want %v", z.RawString(), x.RawString(), have.RawString(), want.RawString())
+		}
+		for k := range have.Items {
+			if h, w := have.Items[k], want.Items[k]; h.StartTime != w.StartTime || h.EndTime != w.EndTime || h.Value.Cmp(w.Value) != 0 {
+				t.Fatalf("Sub of %v - %v: have %v, want %v", z.RawString(), x.RawString(), have.RawString(), want.RawString())
+			}
+		}
+	}
+}
@@ -0,0 +1,397 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package timelockprop checks the time lock arithmetic against its invariants
+// with random sequences of operations.
+//
+// A time lock is a step function giving the value locked at every second. The
+// operations must agree with that model: Add and Sub add and subtract the
+// functions pointwise, ClearExpired keeps the function from the given time,
+// GetSpendableValue is its minimum over a range and CanSub tells whether the
+// subtracted function stays below it. Every result must also be in the
+// canonical form, with sorted, disjoint, unmergeable and positive items.
+package timelockprop
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sort"
+
+	"github.com/FusionFoundation/go-fusion/common"
+)
+
+// Op is an operation of a random sequence.
+type Op int
+
+const (
+	OpAdd Op = iota
+	OpSub
+	OpClearExpired
+	OpGetSpendableValue
+	numOps
+)
+
+func (op Op) String() string {
+	switch op {
+	case OpAdd:
+		return "Add"
+	case OpSub:
+		return "Sub"
+	case OpClearExpired:
+		return "ClearExpired"
+	case OpGetSpendableValue:
+		return "GetSpendableValue"
+	}
+	return fmt.Sprintf("Op(%d)", int(op))
+}
+
+// Violation is an operation which broke an invariant.
+type Violation struct {
+	Step   int    // index of the operation in the sequence
+	Op     Op     // the operation
+	Before string // the time lock the operation ran on
+	Arg    string // the argument of the operation
+	Reason string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("step %d: %v(%s) on %s: %s", v.Step, v.Op, v.Arg, v.Before, v.Reason)
+}
+
+// Generator creates the random operands of a sequence. Its times are drawn
+// around a set of anchor times, so that the items often touch, overlap and
+// merge, and its values are small for the same reason.
+type Generator struct {
+	rand    *rand.Rand
+	anchors []uint64
+	spread  uint64
+}
+
+// NewGenerator returns a generator of times around the anchors, at most spread
+// seconds away from one. Without anchors the times are taken in [0, spread].
+func NewGenerator(r *rand.Rand, anchors []uint64, spread uint64) *Generator {
+	if len(anchors) == 0 {
+		anchors = []uint64{0}
+	}
+	if spread == 0 {
+		spread = 1
+	}
+	return &Generator{rand: r, anchors: anchors, spread: spread}
+}
+
+// Time returns a random time, common.TimeLockForever now and then.
+func (g *Generator) Time() uint64 {
+	if g.rand.Intn(16) == 0 {
+		return common.TimeLockForever
+	}
+	anchor := g.anchors[g.rand.Intn(len(g.anchors))]
+	offset := uint64(g.rand.Int63n(int64(g.spread) + 1))
+	if g.rand.Intn(2) == 0 {
+		if offset > anchor {
+			return 0
+		}
+		return anchor - offset
+	}
+	if anchor > common.TimeLockForever-offset {
+		return common.TimeLockForever
+	}
+	return anchor + offset
+}
+
+// Range returns a random time range.
+func (g *Generator) Range() (uint64, uint64) {
+	start, end := g.Time(), g.Time()
+	if start > end {
+		start, end = end, start
+	}
+	return start, end
+}
+
+// TimeLock returns a random valid time lock of up to maxItems items.
+func (g *Generator) TimeLock(maxItems int) *common.TimeLock {
+	result := new(common.TimeLock)
+	for n := g.rand.Intn(maxItems + 1); n > 0; n-- {
+		start, end := g.Range()
+		item := common.NewTimeLock(&common.TimeLockItem{
+			StartTime: start,
+			EndTime:   end,
+			Value:     big.NewInt(1 + g.rand.Int63n(4)),
+		})
+		result = new(common.TimeLock).Add(result, item)
+	}
+	return result
+}
+
+// Run applies steps random operations to a time lock starting from initial,
+// checking every one against the model. It returns the first violation, nil
+// if there is none.
+func Run(g *Generator, initial *common.TimeLock, steps int) error {
+	current := new(common.TimeLock).Set(initial)
+	if err := checkCanonical(current); err != nil {
+		return &Violation{Step: -1, Before: current.RawString(), Reason: "initial time lock: " + err.Error()}
+	}
+	for step := 0; step < steps; step++ {
+		var (
+			op   = Op(g.rand.Intn(int(numOps)))
+			next *common.TimeLock
+			arg  string
+			err  error
+		)
+		switch op {
+		case OpAdd:
+			y := g.TimeLock(3)
+			arg = y.RawString()
+			next, err = CheckAdd(current, y)
+		case OpSub:
+			y := g.subtrahend(current)
+			arg = y.RawString()
+			next, err = CheckSub(current, y)
+		case OpClearExpired:
+			timestamp := g.Time()
+			arg = fmt.Sprint(timestamp)
+			next, err = CheckClearExpired(current, timestamp)
+		case OpGetSpendableValue:
+			start, end := g.Range()
+			arg = fmt.Sprintf("%d, %d", start, end)
+			next, err = current, CheckGetSpendableValue(current, start, end)
+		}
+		if err != nil {
+			return &Violation{Step: step, Op: op, Before: current.RawString(), Arg: arg, Reason: err.Error()}
+		}
+		current = next
+	}
+	return nil
+}
+
+// subtrahend returns a random time lock to subtract from x, which can be
+// subtracted most of the time.
+func (g *Generator) subtrahend(x *common.TimeLock) *common.TimeLock {
+	if x.IsEmpty() || g.rand.Intn(4) == 0 {
+		return g.TimeLock(2)
+	}
+	item := x.Items[g.rand.Intn(len(x.Items))]
+	start, end := item.StartTime, item.EndTime
+	if span := end - start; span > 0 && g.rand.Intn(2) == 0 {
+		start += uint64(g.rand.Int63n(int64(span/4) + 1))
+		end -= uint64(g.rand.Int63n(int64(span/4) + 1))
+	}
+	value := new(big.Int).Rand(g.rand, item.Value)
+	value.Add(value, common.Big1)
+	return common.NewTimeLock(&common.TimeLockItem{StartTime: start, EndTime: end, Value: value})
+}
+
+// guard turns a panic of an operation into an error.
+func guard(op func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	op()
+	return nil
+}
+
+// CheckAdd adds y to x and checks the sum, which it returns.
+func CheckAdd(x, y *common.TimeLock) (*common.TimeLock, error) {
+	x, y = x.Clone(), y.Clone()
+	var sum *common.TimeLock
+	if err := guard(func() { sum = new(common.TimeLock).Add(x, y) }); err != nil {
+		return nil, err
+	}
+	if err := checkCanonical(sum); err != nil {
+		return nil, err
+	}
+	for _, t := range samples(x, y) {
+		want := new(big.Int).Add(valueAt(x, t), valueAt(y, t))
+		if got := valueAt(sum, t); got.Cmp(want) != 0 {
+			return nil, fmt.Errorf("sum is %v at %d, want %v", got, t, want)
+		}
+	}
+	if sum.Cmp(x) < 0 || (!y.IsEmpty() && sum.Cmp(x) == 0) {
+		return nil, fmt.Errorf("sum %s does not exceed %s", sum.RawString(), x.RawString())
+	}
+	return sum, nil
+}
+
+// CheckSub subtracts y from x if CanSub allows it and checks the difference,
+// which it returns. It returns x if y can not be subtracted.
+func CheckSub(x, y *common.TimeLock) (*common.TimeLock, error) {
+	x, y = x.Clone(), y.Clone()
+	canSub, covered := x.CanSub(y), true
+	for _, t := range samples(x, y) {
+		if inside(y, t) && valueAt(x, t).Cmp(valueAt(y, t)) < 0 {
+			covered = false
+			break
+		}
+	}
+	if canSub != covered {
+		return nil, fmt.Errorf("CanSub is %v, the model says %v", canSub, covered)
+	}
+	if !canSub {
+		return x, nil
+	}
+	var diff *common.TimeLock
+	if err := guard(func() { diff = new(common.TimeLock).Sub(x, y) }); err != nil {
+		return nil, err
+	}
+	if err := checkCanonical(diff); err != nil {
+		return nil, err
+	}
+	for _, t := range samples(x, y) {
+		want := new(big.Int).Sub(valueAt(x, t), valueAt(y, t))
+		if got := valueAt(diff, t); got.Cmp(want) != 0 {
+			return nil, fmt.Errorf("difference is %v at %d, want %v", got, t, want)
+		}
+	}
+	if sum := new(common.TimeLock).Add(diff, y); !sum.EqualTo(x) {
+		return nil, fmt.Errorf("difference plus %s is %s, not %s", y.RawString(), sum.RawString(), x.RawString())
+	}
+	return diff, nil
+}
+
+// CheckClearExpired clears the items of x expired at timestamp and checks the
+// result, which it returns.
+func CheckClearExpired(x *common.TimeLock, timestamp uint64) (*common.TimeLock, error) {
+	x = x.Clone()
+	var cleared *common.TimeLock
+	if err := guard(func() { cleared = x.Clone().ClearExpired(timestamp) }); err != nil {
+		return nil, err
+	}
+	if err := checkCanonical(cleared); err != nil {
+		return nil, err
+	}
+	for _, item := range cleared.Items {
+		if item.EndTime < timestamp {
+			return nil, fmt.Errorf("item %v expired at %d is kept", item, timestamp)
+		}
+	}
+	for _, t := range samples(x) {
+		if t < timestamp {
+			continue
+		}
+		if got, want := valueAt(cleared, t), valueAt(x, t); got.Cmp(want) != 0 {
+			return nil, fmt.Errorf("value at %d is %v after clearing, want %v", t, got, want)
+		}
+	}
+	return cleared, nil
+}
+
+// CheckGetSpendableValue checks the value of x spendable over [start, end].
+func CheckGetSpendableValue(x *common.TimeLock, start, end uint64) error {
+	var got *big.Int
+	if err := guard(func() { got = x.GetSpendableValue(start, end) }); err != nil {
+		return err
+	}
+	want := new(big.Int)
+	if start <= end {
+		// the minimum of a step function is at the range start or a step
+		points := []uint64{start}
+		for _, t := range samples(x) {
+			if t > start && t <= end {
+				points = append(points, t)
+			}
+		}
+		for i, t := range points {
+			if v := valueAt(x, t); i == 0 || v.Cmp(want) < 0 {
+				want = v
+			}
+		}
+	}
+	if got.Cmp(want) != 0 {
+		return fmt.Errorf("spendable value is %v, want %v", got, want)
+	}
+	return nil
+}
+
+// checkCanonical checks that the items of x are valid, sorted, disjoint and
+// can not be merged.
+func checkCanonical(x *common.TimeLock) error {
+	if x == nil {
+		return fmt.Errorf("nil time lock")
+	}
+	return x.IsValid()
+}
+
+// valueAt returns the value locked by x at time t.
+func valueAt(x *common.TimeLock, t uint64) *big.Int {
+	value := new(big.Int)
+	if x.IsEmpty() {
+		return value
+	}
+	for _, item := range x.Items {
+		if item.StartTime <= t && t <= item.EndTime {
+			value.Add(value, item.Value)
+		}
+	}
+	return value
+}
+
+// inside reports whether an item of x covers t.
+func inside(x *common.TimeLock, t uint64) bool {
+	if x.IsEmpty() {
+		return false
+	}
+	for _, item := range x.Items {
+		if item.StartTime <= t && t <= item.EndTime {
+			return true
+		}
+	}
+	return false
+}
+
+// samples returns the times at which the step functions of the time locks
+// change, along with the times next to them, in ascending order.
+func samples(locks ...*common.TimeLock) []uint64 {
+	set := map[uint64]bool{0: true, common.TimeLockForever: true}
+	for _, x := range locks {
+		if x.IsEmpty() {
+			continue
+		}
+		for _, item := range x.Items {
+			for _, t := range []uint64{item.StartTime, item.EndTime} {
+				set[t] = true
+				if t > 0 {
+					set[t-1] = true
+				}
+				if t < common.TimeLockForever {
+					set[t+1] = true
+				}
+			}
+		}
+	}
+	times := make([]uint64, 0, len(set))
+	for t := range set {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	return times
+}
+
+// Anchors returns the start and end times of the items of the time locks, to
+// generate operations around them.
+func Anchors(locks ...*common.TimeLock) []uint64 {
+	var anchors []uint64
+	for _, x := range locks {
+		if x.IsEmpty() {
+			continue
+		}
+		for _, item := range x.Items {
+			anchors = append(anchors, item.StartTime, item.EndTime)
+		}
+	}
+	return anchors
+}
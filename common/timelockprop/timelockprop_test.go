@@ -0,0 +1,63 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package timelockprop
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+)
+
+func TestRandomSequences(t *testing.T) {
+	for seed := int64(0); seed < 200; seed++ {
+		g := NewGenerator(rand.New(rand.NewSource(seed)), []uint64{10, 20, 30}, 12)
+		if err := Run(g, g.TimeLock(4), 200); err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+	}
+}
+
+func TestLiveSample(t *testing.T) {
+	sample := common.NewTimeLock(
+		&common.TimeLockItem{StartTime: 1561000000, EndTime: 1592000000, Value: big.NewInt(5000)},
+		&common.TimeLockItem{StartTime: 1592000001, EndTime: common.TimeLockForever, Value: big.NewInt(20)},
+	)
+	for seed := int64(0); seed < 50; seed++ {
+		g := NewGenerator(rand.New(rand.NewSource(seed)), Anchors(sample), 3600)
+		if err := Run(g, sample, 200); err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+	}
+}
+
+func TestViolation(t *testing.T) {
+	// an invalid initial time lock is reported before any operation
+	invalid := &common.TimeLock{Items: []*common.TimeLockItem{
+		{StartTime: 10, EndTime: 20, Value: big.NewInt(1)},
+		{StartTime: 15, EndTime: 30, Value: big.NewInt(1)},
+	}}
+	g := NewGenerator(rand.New(rand.NewSource(1)), nil, 10)
+	err := Run(g, invalid, 10)
+	if v, ok := err.(*Violation); !ok || v.Step != -1 {
+		t.Fatalf("got %v, want a violation of the initial time lock", err)
+	}
+	if err := CheckGetSpendableValue(invalid, 10, 30); err == nil {
+		t.Error("spendable value of overlapping items agrees with the model")
+	}
+}
@@ -14,14 +14,36 @@ var (
 	VOTE1_FREEZE_TX_START uint64 = 739500
 	VOTE1_FREEZE_TX_END   uint64 = 786000
 
-	Vote1RefundAddress = HexToAddress("0xff948d492c31814dEde4CAA0af8824eF02Eb48D2")
-	Vote1DrainList     = []Address{
-		HexToAddress("0xb66cce16736feb5a50a9883675708027d3427c3c"),
-		HexToAddress("0x782da6fb0562074ec21942a7829064a8c2bb05c4"),
-		HexToAddress("0x6deed6878d062cd8754b3284306be814b215e332"),
-	}
+	// the accounts drained at the end of the freeze are listed in the asset
+	// recoveries of the chain config
 )
 
-func IsVote1ForkBlock(blockNumber *big.Int) bool {
-	return blockNumber.Uint64() == VOTE1_FREEZE_TX_END
+var (
+	// HardForkTransferTopic is the topic of the logs of the assets moved by
+	// a hard fork, followed by the topics of the from and to addresses
+	HardForkTransferTopic = Keccak256Hash([]byte("HardForkTransfer(address,address)"))
+
+	// HardForkTxHash is the transaction hash of the synthetic receipt holding
+	// the hard fork transfer logs of a block
+	HardForkTxHash = Keccak256Hash([]byte("HardForkReceipt"))
+)
+
+// hard fork transfer types
+const (
+	HardForkTransferBalance  = "Balance"
+	HardForkTransferTimeLock = "TimeLock"
+	HardForkTransferTicket   = "Ticket"
+	HardForkTransferNotation = "Notation"
+)
+
+// HardForkTransfer is the data of the log of an asset moved by a hard fork.
+// Live tickets are returned to the receiver as time locks, notations are
+// burned.
+type HardForkTransfer struct {
+	Type     string
+	AssetID  *Hash     `json:",omitempty"`
+	Value    *big.Int  `json:",omitempty,string"`
+	TimeLock *TimeLock `json:",omitempty"`
+	TicketID *Hash     `json:",omitempty"`
+	Notation uint64    `json:",omitempty"`
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/FusionFoundation/go-fusion/common"
 	"github.com/FusionFoundation/go-fusion/consensus"
 	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/log"
 	"github.com/FusionFoundation/go-fusion/rlp"
 	"github.com/FusionFoundation/go-fusion/rpc"
 )
@@ -16,6 +17,13 @@ import (
 // API wacom
 type API struct {
 	chain consensus.ChainReader
+	dt    *DaTong
+}
+
+// SnapshotCache keeps the snapshots of blocks by block hash
+type SnapshotCache interface {
+	Snapshot(hash common.Hash) (*Snapshot, bool)
+	AddSnapshot(hash common.Hash, snap *Snapshot)
 }
 
 func getSnapshotByHeader(header *types.Header) (*Snapshot, error) {
@@ -34,6 +42,61 @@ func getSnapshotByHeader(header *types.Header) (*Snapshot, error) {
 	return snap, nil
 }
 
+// snapshotByHeader returns the snapshot of header through the snapshot cache
+func (api *API) snapshotByHeader(header *types.Header) (*Snapshot, error) {
+	api.dt.lock.RLock()
+	cache := api.dt.snapCache
+	api.dt.lock.RUnlock()
+
+	if cache == nil || header == nil {
+		snap, err := getSnapshotByHeader(header)
+		if err != nil {
+			return nil, err
+		}
+		api.addSnapshotTickets(header, snap)
+		return snap, nil
+	}
+	hash := header.Hash()
+	if snap, ok := cache.Snapshot(hash); ok {
+		return snap, nil
+	}
+	snap, err := getSnapshotByHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	// a snapshot missing its ticket details is served but not cached, the
+	// details may be available on the next request
+	if api.addSnapshotTickets(header, snap) {
+		cache.AddSnapshot(hash, snap)
+	}
+	return snap, nil
+}
+
+// addSnapshotTickets fills in the details of the selected and retreat tickets
+// of the snapshot, which are deleted from the state of the block itself. It
+// reports whether the snapshot is complete.
+func (api *API) addSnapshotTickets(header *types.Header, snap *Snapshot) bool {
+	if header.Number.Sign() == 0 || api.dt == nil {
+		return true
+	}
+	parent := api.chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		log.Debug("Failed to get snapshot tickets", "number", header.Number, "err", "unknown parent")
+		return false
+	}
+	selected, retreat, err := api.dt.getSelectedAndRetreatedTickets(api.chain, header, parent)
+	if err != nil {
+		log.Debug("Failed to get snapshot tickets", "number", header.Number, "err", err)
+		return false
+	}
+	snap.SelectedTicket = newSnapshotTicket(selected, header.Time)
+	snap.RetreatTickets = make([]*SnapshotTicket, len(retreat))
+	for i, ticket := range retreat {
+		snap.RetreatTickets[i] = newSnapshotTicket(ticket, header.Time)
+	}
+	return true
+}
+
 // GetSnapshot wacom
 func (api *API) GetSnapshot(number *rpc.BlockNumber) (*Snapshot, error) {
 	var header *types.Header
@@ -42,17 +105,17 @@ func (api *API) GetSnapshot(number *rpc.BlockNumber) (*Snapshot, error) {
 	} else {
 		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
 	}
-	return getSnapshotByHeader(header)
+	return api.snapshotByHeader(header)
 }
 
 // GetSnapshotAtHash wacom
 func (api *API) GetSnapshotAtHash(hash common.Hash) (*Snapshot, error) {
 	header := api.chain.GetHeaderByHash(hash)
-	return getSnapshotByHeader(header)
+	return api.snapshotByHeader(header)
 }
 
-// DecodeLogData decode log data
-func DecodeLogData(data []byte) (interface{}, error) {
+// DecodeLogData decode log data of block number
+func DecodeLogData(data []byte, number *big.Int) (interface{}, error) {
 	maps := make(map[string]interface{})
 	if err := json.Unmarshal(data, &maps); err != nil {
 		return nil, fmt.Errorf("json unmarshal err: %v", err)
@@ -78,6 +141,9 @@ func DecodeLogData(data []byte) (interface{}, error) {
 			delete(maps, "Base")
 			maps["StartTime"] = buyTicketParam.Start
 			maps["ExpireTime"] = buyTicketParam.End
+			if len(buyTicketParam.Denomination) != 0 && common.IsTicketDenominationEnabled(number) {
+				maps["Weight"] = buyTicketParam.Weight(number)
+			}
 		}
 	}
 	return maps, nil
@@ -147,6 +147,12 @@ func CheckPointsInHeaderChain(chainID *big.Int, chain []*types.Header) (int, err
 		if !exist {
 			continue
 		}
+		if cp := signedCheckpoint; cp != nil && cp.Number == blockHeight {
+			if err := cp.VerifyHeader(header); err != nil {
+				log.Info("signed checkpoint failed", "number", blockHeight, "err", err)
+				return i, err
+			}
+		}
 		blockHash := header.Hash()
 		if blockHash != hash {
 			log.Info("check point failed, block hash mismatch", "number", blockHeight, "have", blockHash, "want", hash)
@@ -3,6 +3,7 @@ package datong
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -19,6 +20,7 @@ import (
 	"github.com/FusionFoundation/go-fusion/core/rawdb"
 	"github.com/FusionFoundation/go-fusion/core/state"
 	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/core/vm"
 	"github.com/FusionFoundation/go-fusion/crypto"
 	"github.com/FusionFoundation/go-fusion/ethdb"
 	"github.com/FusionFoundation/go-fusion/log"
@@ -73,6 +75,10 @@ type DaTong struct {
 	signer common.Address
 	signFn SignerFn
 	lock   sync.RWMutex
+
+	snapCache SnapshotCache // optional cache of the snapshots served by the API
+
+	selections selectionCache // ticket selection of the latest parent
 }
 
 // New wacom
@@ -93,6 +99,13 @@ func (dt *DaTong) Authorize(signer common.Address, signFn SignerFn) {
 	dt.signFn = signFn
 }
 
+// SetSnapshotCache sets the cache of the snapshots served by the API
+func (dt *DaTong) SetSnapshotCache(cache SnapshotCache) {
+	dt.lock.Lock()
+	defer dt.lock.Unlock()
+	dt.snapCache = cache
+}
+
 // Author retrieves the Ethereum address of the account that minted the given
 // block, which may be different from the header's coinbase if a consensus
 // engine is based on signatures.
@@ -329,6 +342,10 @@ func (dt *DaTong) Prepare(chain consensus.ChainReader, header *types.Header) err
 			}
 		}
 	}
+	// a low ticket pool slows down to its own block period
+	if minTime := parent.Time + dt.lowTicketPeriod(parent); header.Time < minTime {
+		header.Time = minTime
+	}
 	return nil
 }
 
@@ -351,6 +368,16 @@ func (s DistanceSlice) Swap(i, j int) {
 }
 
 func (dt *DaTong) Finalize(chain consensus.ChainReader, header *types.Header, statedb *state.StateDB, txs []*types.Transaction, uncles []*types.Header) error {
+	return dt.finalize(chain, header, statedb, nil)
+}
+
+// FinalizeAndTrace runs Finalize on a block already in the chain, passing the
+// value it returns to the owners of the spent tickets to the tracer
+func (dt *DaTong) FinalizeAndTrace(chain consensus.ChainReader, header *types.Header, statedb *state.StateDB, tracer vm.FsnTransferTracer) error {
+	return dt.finalize(chain, header, statedb, tracer)
+}
+
+func (dt *DaTong) finalize(chain consensus.ChainReader, header *types.Header, statedb *state.StateDB, tracer vm.FsnTransferTracer) error {
 	parent, err := getParent(chain, header, glb_parents)
 	if err != nil {
 		return err
@@ -378,11 +405,13 @@ func (dt *DaTong) Finalize(chain consensus.ChainReader, header *types.Header, st
 		return err
 	}
 	numTickets := tickets.NumberOfTickets()
-	if numTickets <= 1 {
+	keepTickets := dt.config.IsLowTicketPool(header.Number, numTickets)
+	if numTickets <= 1 && !keepTickets {
 		log.Warn("Next block doesn't have ticket, wait buy ticket")
 		return errors.New("Next block doesn't have ticket, wait buy ticket")
 	}
 
+	// a selected or retreat ticket spends one unit, which is returned
 	returnTicket := func(ticket *common.Ticket) {
 		if ticket.ExpireTime <= header.Time {
 			return
@@ -390,18 +419,38 @@ func (dt *DaTong) Finalize(chain consensus.ChainReader, header *types.Header, st
 		value := common.NewTimeLock(&common.TimeLockItem{
 			StartTime: ticket.StartTime,
 			EndTime:   ticket.ExpireTime,
-			Value:     ticket.Value(),
+			Value:     ticket.UnitValue(),
 		})
 		headerState.AddTimeLockBalance(ticket.Owner, common.SystemAssetID, value, header.Number, header.Time)
+		if tracer != nil {
+			tracer.CaptureFsnTransfer(&common.InternalTransfer{
+				Type:      common.TransferTicketReturn,
+				From:      common.FSNCallAddress,
+				To:        ticket.Owner,
+				AssetID:   common.SystemAssetID,
+				Value:     ticket.UnitValue(),
+				StartTime: ticket.StartTime,
+				EndTime:   ticket.ExpireTime,
+				Ref:       ticket.ID,
+			})
+		}
+	}
+
+	// a ticket revoked by its owner in the block has already been refunded
+	revoked := func(ticket *common.Ticket) bool {
+		return common.IsTicketRevokeEnabled(header.Number) && !headerState.IsTicketExist(ticket.ID)
 	}
 
 	deleteTicket := func(ticket *common.Ticket, logType ticketLogType, returnBack bool) {
 		id := ticket.ID
-		headerState.RemoveTicket(id)
 		snap.AddLog(&ticketLog{
 			TicketID: id,
 			Type:     logType,
 		})
+		if keepTickets || revoked(ticket) {
+			return
+		}
+		headerState.SpendTicket(id)
 		if returnBack {
 			returnTicket(ticket)
 		}
@@ -414,11 +463,40 @@ func (dt *DaTong) Finalize(chain consensus.ChainReader, header *types.Header, st
 		if !isInMining && i == 0 {
 			common.DebugInfo("retreat ticket", "nonce", header.Nonce.Uint64(), "id", retreat[0].ID.String(), "owner", retreat[0].Owner, "blockHeight", header.Number, "ticketHeight", retreat[0].Height)
 		}
-		deleteTicket(t, ticketRetreat, !(t.IsInGenesis() || i == 0))
+		returnBack := !(t.IsInGenesis() || i == 0)
+		if common.IsTicketRetreatRecordEnabled(header.Number) && !keepTickets && !revoked(t) {
+			headerState.AddTicketRetreat(&common.TicketRetreat{
+				TicketID: t.ID,
+				Owner:    t.Owner,
+				Value:    t.UnitValue(),
+				Returned: returnBack && t.ExpireTime > header.Time,
+			})
+		}
+		deleteTicket(t, ticketRetreat, returnBack)
+	}
+
+	if dt.config != nil {
+		if recoveries := dt.config.RecoveriesAt(header.Number.Uint64()); len(recoveries) > 0 {
+			ApplyAssetRecoveries(headerState, recoveries, header.Number, parent.Time)
+		}
+	}
+
+	if common.IsSwapDeletionEnabled(header.Number) {
+		headerState.DeleteScheduledSwaps(header.Number.Uint64())
 	}
 
-	if common.IsVote1ForkBlock(header.Number) {
-		ApplyVote1HardFork(headerState, header.Number, parent.Time)
+	if common.IsReportExpiryEnabled(header.Number) {
+		number := header.Number.Uint64()
+		if number == common.GetForkHeight(3) {
+			if err := migrateReports(chain, headerState, parent); err != nil {
+				return err
+			}
+		}
+		if number%common.ReportEpochLength == 0 {
+			if _, err := headerState.DeleteExpiredReports(number / common.ReportEpochLength); err != nil {
+				return err
+			}
+		}
 	}
 
 	hash, err := headerState.UpdateTickets(header.Number, parent.Time)
@@ -426,6 +504,20 @@ func (dt *DaTong) Finalize(chain consensus.ChainReader, header *types.Header, st
 		return errors.New("UpdateTickets failed: " + err.Error())
 	}
 
+	// the block reward is credited before the epoch summary is committed,
+	// which checks the rewards credited in the epoch
+	reward := CalcRewards(header.Number)
+	headerState.AddBalance(header.Coinbase, common.SystemAssetID, reward)
+	if common.IsEpochSummaryEnabled(header.Number) {
+		headerState.AddEpochRewards(common.EpochOf(header.Number.Uint64()), reward)
+	}
+
+	if common.IsEpochSummaryEnabled(header.Number) && common.IsEpochEnd(header.Number.Uint64()) {
+		if err := commitEpochSummary(headerState, header.Number.Uint64(), hash, headerState.TotalNumberOfTickets()); err != nil {
+			return err
+		}
+	}
+
 	snap.SetTicketNumber(int(headerState.TotalNumberOfTickets()))
 	snapBytes := snap.Bytes()
 
@@ -445,7 +537,6 @@ func (dt *DaTong) Finalize(chain consensus.ChainReader, header *types.Header, st
 		}
 	}
 
-	headerState.AddBalance(header.Coinbase, common.SystemAssetID, CalcRewards(header.Number))
 	header.Root = headerState.IntermediateRoot(chain.Config().IsEIP158(header.Number))
 	return nil
 }
@@ -472,6 +563,11 @@ func (dt *DaTong) Seal(chain consensus.ChainReader, block *types.Block, results
 	if number == 0 {
 		return errUnknownBlock
 	}
+	// For 0-period chains, refuse to seal empty blocks (no reward but would spin sealing)
+	if dt.config.Period == 0 && len(block.Transactions()) == 0 {
+		log.Info("Sealing paused, waiting for transactions")
+		return nil
+	}
 	dt.lock.RLock()
 	signer, signFn := dt.signer, dt.signFn
 	dt.lock.RUnlock()
@@ -485,6 +581,9 @@ func (dt *DaTong) Seal(chain consensus.ChainReader, block *types.Block, results
 	if errc != nil {
 		return errc
 	}
+	if lowDelay := dt.lowTicketDelay(chain, header); lowDelay > delay {
+		delay = lowDelay
+	}
 
 	sighash, err := signFn(accounts.Account{Address: signer}, accounts.MimetypeDatong, DatongRLP(header))
 	if err != nil {
@@ -543,7 +642,7 @@ func (dt *DaTong) APIs(chain consensus.ChainReader) []rpc.API {
 	return []rpc.API{{
 		Namespace: "fsn",
 		Version:   "1.0",
-		Service:   &API{chain: chain},
+		Service:   &API{chain: chain, dt: dt},
 		Public:    false,
 	}}
 }
@@ -554,7 +653,7 @@ func (dt *DaTong) Close() error {
 }
 
 func (dt *DaTong) getAllTickets(chain consensus.ChainReader, header *types.Header) (common.TicketsDataSlice, error) {
-	if ts := state.GetCachedTickets(header.MixDigest); ts != nil {
+	if ts := dt.stateCache.TicketCache().Get(header.MixDigest); ts != nil {
 		return ts, nil
 	}
 	statedb, err := state.New(header.Root, header.MixDigest, dt.stateCache)
@@ -593,8 +692,9 @@ func (dt *DaTong) getAllTickets(chain consensus.ChainReader, header *types.Heade
 		switch l.Address {
 		case common.FSNCallAddress:
 			if len(l.Topics) > 0 {
-				topic := l.Topics[0]
-				return topic[common.HashLength-1]
+				if fn, ok := common.FsnCallFuncOfTopic(l.Topics[0]); ok {
+					return uint8(fn)
+				}
 			}
 		}
 		return 0xff
@@ -634,6 +734,7 @@ func (dt *DaTong) getAllTickets(chain consensus.ChainReader, header *types.Heade
 				ExpireTime: buyTicketParam.End,
 			},
 		}
+		ticket.SetWeight(buyTicketParam.Weight(new(big.Int).SetUint64(l.BlockNumber)))
 		tickets, err = tickets.AddTicket(ticket)
 		return err
 	}
@@ -663,7 +764,7 @@ func (dt *DaTong) getAllTickets(chain consensus.ChainReader, header *types.Heade
 		}
 
 		for _, id := range delTickets {
-			tickets, err = tickets.RemoveTicket(id)
+			tickets, err = tickets.SpendTicket(id)
 			if err != nil {
 				return err
 			}
@@ -671,6 +772,24 @@ func (dt *DaTong) getAllTickets(chain consensus.ChainReader, header *types.Heade
 
 		return nil
 	}
+	processRevokeTicketLog := func(l *types.Log) error {
+		maps := make(map[string]interface{})
+		err := json.Unmarshal(l.Data, &maps)
+		if err != nil {
+			return err
+		}
+
+		if _, hasError := maps["Error"]; hasError {
+			return nil
+		}
+
+		idstr, idok := maps["TicketID"].(string)
+		if !idok {
+			return errors.New("revoke ticket log has wrong data")
+		}
+		tickets, err = tickets.RemoveTicket(common.HexToHash(idstr))
+		return err
+	}
 	processLog := func(l *types.Log) error {
 		funcType := getFuncType(l)
 		switch funcType {
@@ -682,6 +801,10 @@ func (dt *DaTong) getAllTickets(chain consensus.ChainReader, header *types.Heade
 			if err := processReportLog(l); err != nil {
 				return err
 			}
+		case common.RevokeTicketFunc:
+			if err := processRevokeTicketLog(l); err != nil {
+				return err
+			}
 		}
 		return nil
 	}
@@ -690,12 +813,12 @@ func (dt *DaTong) getAllTickets(chain consensus.ChainReader, header *types.Heade
 		if err != nil {
 			return err
 		}
-		tickets, err = tickets.RemoveTicket(snap.Selected)
-		if err != nil {
-			return err
-		}
-		for _, id := range snap.Retreat {
-			tickets, err = tickets.RemoveTicket(id)
+		revokable := common.IsTicketRevokeEnabled(h.Number)
+		for _, id := range append([]common.Hash{snap.Selected}, snap.Retreat...) {
+			if _, err := tickets.Get(id); err != nil && revokable {
+				continue // revoked in the block
+			}
+			tickets, err = tickets.SpendTicket(id)
 			if err != nil {
 				return err
 			}
@@ -724,7 +847,7 @@ func (dt *DaTong) getAllTickets(chain consensus.ChainReader, header *types.Heade
 	if err != nil {
 		return nil, err
 	}
-	if err := state.AddCachedTickets(header.MixDigest, tickets); err != nil {
+	if err := dt.stateCache.TicketCache().AddVerified(header.MixDigest, tickets); err != nil {
 		return nil, err
 	}
 	return tickets, nil
@@ -775,13 +898,17 @@ func getSnapData(data []byte) []byte {
 	return data[extraVanity:extraSuffix]
 }
 
+// rewardHalvingBlocks is the number of blocks after which the block reward
+// is divided by 2
+const rewardHalvingBlocks = 4915200
+
 func CalcRewards(height *big.Int) *big.Int {
 	var i int64
 	div2 := big.NewInt(2)
 	// initial reward 2.5
 	var reward = new(big.Int).Mul(big.NewInt(25), big.NewInt(100000000000000000))
-	// every 4915200 blocks divide reward by 2
-	segment := new(big.Int).Div(height, new(big.Int).SetUint64(4915200))
+	// every rewardHalvingBlocks blocks divide reward by 2
+	segment := new(big.Int).Div(height, new(big.Int).SetUint64(rewardHalvingBlocks))
 	for i = 0; i < segment.Int64(); i++ {
 		reward = new(big.Int).Div(reward, div2)
 	}
@@ -845,19 +972,29 @@ func calcDisInfo(ind int, tickets common.TicketsData, parent *types.Header, ch c
 	posHash := posHash(parent)
 	owner := tickets.Owner
 
+	denomination := common.IsTicketDenominationEnabled(new(big.Int).Add(parent.Number, big.NewInt(1)))
+
 	var minTicket common.TicketBody
 	var minDist *big.Int
 	for _, t := range tickets.Tickets {
+		units := uint64(1)
+		if denomination {
+			units = t.Weight()
+		}
 		w := new(big.Int).SetUint64(parent.Number.Uint64() - t.Height + 1)
 		w2 := new(big.Int).Mul(w, w)
 
-		id := new(big.Int).SetBytes(crypto.Keccak256(posHash[:], t.ID[:], []byte(owner.Hex())))
-		id2 := new(big.Int).Mul(id, id)
-		s := new(big.Int).Add(w2, id2)
+		// every unit of a ticket draws its own id, so that a ticket of
+		// several ticket prices is selected like as many tickets
+		for unit := uint64(0); unit < units; unit++ {
+			id := new(big.Int).SetBytes(ticketUnitHash(posHash, t.ID, owner, unit))
+			id2 := new(big.Int).Mul(id, id)
+			s := new(big.Int).Add(w2, id2)
 
-		if minDist == nil || s.Cmp(minDist) < 0 {
-			minTicket = t
-			minDist = s
+			if minDist == nil || s.Cmp(minDist) < 0 {
+				minTicket = t
+				minDist = s
+			}
 		}
 	}
 	ticket := &common.Ticket{
@@ -868,11 +1005,22 @@ func calcDisInfo(ind int, tickets common.TicketsData, parent *types.Header, ch c
 	ch <- result
 }
 
+// ticketUnitHash returns the selection id of one unit of a ticket, the first
+// unit has the id of a ticket of one ticket price
+func ticketUnitHash(posHash, ticketID common.Hash, owner common.Address, unit uint64) []byte {
+	if unit == 0 {
+		return crypto.Keccak256(posHash[:], ticketID[:], []byte(owner.Hex()))
+	}
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, unit)
+	return crypto.Keccak256(posHash[:], ticketID[:], []byte(owner.Hex()), enc)
+}
+
 func (dt *DaTong) calcBlockDifficulty(chain consensus.ChainReader, header *types.Header, parent *types.Header) (*big.Int, *common.Ticket, uint64, common.TicketPtrSlice, error) {
 	if header.GetSelectedTicket() != nil {
 		return header.Difficulty, header.GetSelectedTicket(), header.Nonce.Uint64(), header.GetRetreatTickets(), nil
 	}
-	parentTickets, err := dt.getAllTickets(chain, parent)
+	parentTickets, list, err := dt.parentSelection(chain, parent)
 	if err != nil {
 		return nil, nil, 0, nil, err
 	}
@@ -886,7 +1034,8 @@ func (dt *DaTong) calcBlockDifficulty(chain consensus.ChainReader, header *types
 	if !haveTicket {
 		return nil, nil, 0, nil, ErrNoTicket
 	}
-	ticketsTotalAmount, numberOfticketOwners := parentTickets.NumberOfTicketsAndOwners()
+	// tickets of several ticket prices weigh as many tickets
+	ticketsTotalAmount, numberOfticketOwners := parentTickets.TotalWeight(), parentTickets.NumberOfOwners()
 
 	// calc balance before selected ticket from stored tickets list
 	var (
@@ -894,18 +1043,6 @@ func (dt *DaTong) calcBlockDifficulty(chain consensus.ChainReader, header *types
 		retreat  common.TicketPtrSlice
 	)
 
-	// make consensus by tickets sequence(selectedTime) with: parentHash, weigth, ticketID, coinbase
-	ch := make(chan *DisInfoWithIndex, numberOfticketOwners)
-	list := make(DistanceSlice, numberOfticketOwners)
-	for k, v := range parentTickets {
-		go calcDisInfo(k, v, parent, ch)
-	}
-	for i := 0; i < int(numberOfticketOwners); i++ {
-		v := <-ch
-		list[v.index] = v.info
-	}
-	close(ch)
-	sort.Sort(list)
 	selectedTime := uint64(0)
 	for i, t := range list {
 		owner := t.tk.Owner
@@ -923,6 +1060,36 @@ func (dt *DaTong) calcBlockDifficulty(chain consensus.ChainReader, header *types
 		return nil, nil, 0, nil, errors.New("myself tickets not selected in maxBlockTime")
 	}
 
+	difficulty := calcDifficulty(ticketsTotalAmount, numberOfticketOwners, selectedTime)
+
+	header.SetSelectedTicket(selected)
+	header.SetRetreatTickets(retreat)
+
+	return difficulty, selected, selectedTime, retreat, nil
+}
+
+// rankTickets orders the owners by the distance of their best ticket, the
+// first owner is allowed to mine the block after parent first.
+func rankTickets(parentTickets common.TicketsDataSlice, parent *types.Header) DistanceSlice {
+	// make consensus by tickets sequence(selectedTime) with: parentHash, weigth, ticketID, coinbase
+	numberOfticketOwners := len(parentTickets)
+	ch := make(chan *DisInfoWithIndex, numberOfticketOwners)
+	list := make(DistanceSlice, numberOfticketOwners)
+	for k, v := range parentTickets {
+		go calcDisInfo(k, v, parent, ch)
+	}
+	for i := 0; i < numberOfticketOwners; i++ {
+		v := <-ch
+		list[v.index] = v.info
+	}
+	close(ch)
+	sort.Sort(list)
+	return list
+}
+
+// calcDifficulty returns the difficulty of a block mined by the owner at
+// position selectedTime of the ranking.
+func calcDifficulty(ticketsTotalAmount, numberOfticketOwners, selectedTime uint64) *big.Int {
 	// cacl difficulty
 	difficulty := new(big.Int).SetUint64(ticketsTotalAmount - selectedTime)
 	if selectedTime > 0 {
@@ -942,12 +1109,7 @@ func (dt *DaTong) calcBlockDifficulty(chain consensus.ChainReader, header *types
 		}
 	}
 	adjust := new(big.Int).SetUint64(numberOfticketOwners - selectedTime)
-	difficulty = new(big.Int).Add(difficulty, adjust)
-
-	header.SetSelectedTicket(selected)
-	header.SetRetreatTickets(retreat)
-
-	return difficulty, selected, selectedTime, retreat, nil
+	return new(big.Int).Add(difficulty, adjust)
 }
 
 // PreProcess update state if needed from various block info
@@ -980,6 +1142,34 @@ func (dt *DaTong) calcDelayTime(chain consensus.ChainReader, header *types.Heade
 	return delayTime, nil
 }
 
+// lowTicketDelay returns the time to wait before sealing header while the
+// ticket pool of its parent is below the minimum, 0 if the pool is not low
+func (dt *DaTong) lowTicketDelay(chain consensus.ChainReader, header *types.Header) time.Duration {
+	parent := chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return 0
+	}
+	period := dt.lowTicketPeriod(parent)
+	if period == 0 {
+		return 0
+	}
+	return time.Unix(int64(parent.Time+period), 0).Sub(time.Now())
+}
+
+// lowTicketPeriod returns the minimum time between parent and its child while
+// the ticket pool of parent is below the minimum, 0 if the pool is not low
+func (dt *DaTong) lowTicketPeriod(parent *types.Header) uint64 {
+	if dt.config == nil || dt.config.LowTicketPeriod == 0 {
+		return 0
+	}
+	snap, err := NewSnapshotFromHeader(parent)
+	number := new(big.Int).Add(parent.Number, big.NewInt(1))
+	if err != nil || !dt.config.IsLowTicketPool(number, uint64(snap.TicketNumber)) {
+		return 0
+	}
+	return dt.config.LowTicketPeriod
+}
+
 // check ticket info
 func (dt *DaTong) checkTicketInfo(header *types.Header, ticket *common.Ticket) error {
 	// check height
@@ -997,6 +1187,9 @@ func (dt *DaTong) checkTicketInfo(header *types.Header, ticket *common.Ticket) e
 
 // check block time
 func (dt *DaTong) checkBlockTime(chain consensus.ChainReader, header *types.Header, parent *types.Header) error {
+	if period := dt.lowTicketPeriod(parent); header.Time < parent.Time+period {
+		return fmt.Errorf("block time mismatch: low ticket pool, receive: %v, expect: %v.", header.Time-parent.Time, period)
+	}
 	list := header.Nonce.Uint64()
 	if list <= 0 { // No.1 pass, check others
 		return nil
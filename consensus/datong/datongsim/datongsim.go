@@ -0,0 +1,180 @@
+// Package datongsim simulates the ticket selection of the datong consensus
+// offline, to measure how often the owners of a ticket set mine first.
+package datongsim
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"math/rand"
+	"sort"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/consensus/datong"
+	"github.com/FusionFoundation/go-fusion/core/types"
+)
+
+// extraLength is the length of the extra data of the simulated parents, the
+// vanity and seal of a datong header around an empty snapshot
+const extraLength = 32 + 65
+
+// Config is the configuration of a simulation
+type Config struct {
+	Rounds int    // number of selections
+	Seed   int64  // seed of the parents of the selections
+	Number uint64 // number of the parent block, the ticket weights are relative to it
+}
+
+// OwnerStats is the selection statistics of a ticket owner
+type OwnerStats struct {
+	Owner    common.Address `json:"owner"`
+	Tickets  uint64         `json:"tickets"`  // weight of the tickets of the owner
+	Share    float64        `json:"share"`    // share of the total weight
+	Wins     uint64         `json:"wins"`     // rounds the owner ranked first
+	Expected float64        `json:"expected"` // wins expected from the share
+	Ratio    float64        `json:"ratio"`    // wins to expected wins
+	AvgOrder float64        `json:"avgOrder"` // mean position in the ranking
+}
+
+// Result is the outcome of a simulation
+type Result struct {
+	Rounds    int           `json:"rounds"`
+	Seed      int64         `json:"seed"`
+	Number    uint64        `json:"number"`
+	Tickets   uint64        `json:"tickets"` // total ticket weight
+	Owners    []*OwnerStats `json:"owners"`  // by wins, most first
+	ChiSquare float64       `json:"chiSquare"`
+	DoF       int           `json:"dof"` // degrees of freedom of the chi-square statistic
+}
+
+// LoadTickets reads a ticket set in the format of fsn_allTickets, a map of
+// the ticket IDs to the tickets
+func LoadTickets(r io.Reader) (common.TicketsDataSlice, error) {
+	var display map[common.Hash]common.TicketDisplay
+	if err := json.NewDecoder(r).Decode(&display); err != nil {
+		return nil, err
+	}
+	ids := make([]common.Hash, 0, len(display))
+	for id := range display {
+		ids = append(ids, id)
+	}
+	// add the tickets in a fixed order, the ranking does not depend on it
+	sort.Slice(ids, func(i, j int) bool { return ids[i].Big().Cmp(ids[j].Big()) < 0 })
+
+	var tickets common.TicketsDataSlice
+	for _, id := range ids {
+		t := display[id]
+		ticket := &common.Ticket{
+			Owner: t.Owner,
+			TicketBody: common.TicketBody{
+				ID:         id,
+				Height:     t.Height,
+				StartTime:  t.StartTime,
+				ExpireTime: t.ExpireTime,
+			},
+		}
+		ticket.SetWeight(t.Weight)
+		var err error
+		if tickets, err = tickets.AddTicket(ticket); err != nil {
+			return nil, err
+		}
+	}
+	return tickets, nil
+}
+
+// Simulate ranks the tickets after cfg.Rounds random parents and counts how
+// often every owner ranks first
+func Simulate(tickets common.TicketsDataSlice, cfg Config) (*Result, error) {
+	if len(tickets) == 0 {
+		return nil, errors.New("no tickets")
+	}
+	if cfg.Rounds <= 0 {
+		return nil, errors.New("no rounds")
+	}
+	for _, v := range tickets {
+		for _, t := range v.Tickets {
+			if t.Height > cfg.Number {
+				return nil, errors.New("ticket bought after the parent block")
+			}
+		}
+	}
+	total := tickets.TotalWeight()
+	stats := make(map[common.Address]*OwnerStats, len(tickets))
+	orders := make(map[common.Address]uint64, len(tickets))
+	result := &Result{Rounds: cfg.Rounds, Seed: cfg.Seed, Number: cfg.Number, Tickets: total}
+	for _, v := range tickets {
+		weight := uint64(0)
+		for i := range v.Tickets {
+			weight += v.Tickets[i].Weight()
+		}
+		share := float64(weight) / float64(total)
+		s := &OwnerStats{
+			Owner:    v.Owner,
+			Tickets:  weight,
+			Share:    share,
+			Expected: share * float64(cfg.Rounds),
+		}
+		stats[v.Owner] = s
+		result.Owners = append(result.Owners, s)
+	}
+
+	rnd := rand.New(rand.NewSource(cfg.Seed))
+	for round := 0; round < cfg.Rounds; round++ {
+		ranking := datong.RankTickets(tickets, randomParent(rnd, cfg.Number))
+		stats[ranking[0].Owner].Wins++
+		for order, c := range ranking {
+			orders[c.Owner] += uint64(order)
+		}
+	}
+
+	for _, s := range result.Owners {
+		if s.Expected > 0 {
+			s.Ratio = float64(s.Wins) / s.Expected
+			d := float64(s.Wins) - s.Expected
+			result.ChiSquare += d * d / s.Expected
+		}
+		s.AvgOrder = float64(orders[s.Owner]) / float64(cfg.Rounds)
+	}
+	result.DoF = len(result.Owners) - 1
+	sort.SliceStable(result.Owners, func(i, j int) bool {
+		if result.Owners[i].Wins != result.Owners[j].Wins {
+			return result.Owners[i].Wins > result.Owners[j].Wins
+		}
+		return result.Owners[i].Share > result.Owners[j].Share
+	})
+	return result, nil
+}
+
+// MaxDeviation returns the largest deviation of the wins of an owner from its
+// expected wins, in standard deviations of the binomial distribution
+func (r *Result) MaxDeviation() float64 {
+	max := 0.0
+	for _, s := range r.Owners {
+		sd := math.Sqrt(s.Expected * (1 - s.Share))
+		if sd == 0 {
+			continue
+		}
+		if d := math.Abs(float64(s.Wins)-s.Expected) / sd; d > max {
+			max = d
+		}
+	}
+	return max
+}
+
+// randomParent returns a parent header at number whose selection seed is
+// drawn from rnd
+func randomParent(rnd *rand.Rand, number uint64) *types.Header {
+	parent := &types.Header{
+		Number:     new(big.Int).SetUint64(number),
+		Difficulty: new(big.Int).SetUint64(rnd.Uint64()),
+		Time:       rnd.Uint64() >> 1,
+		Extra:      make([]byte, extraLength),
+	}
+	rnd.Read(parent.ParentHash[:])
+	rnd.Read(parent.UncleHash[:])
+	rnd.Read(parent.Coinbase[:])
+	rnd.Read(parent.MixDigest[:])
+	return parent
+}
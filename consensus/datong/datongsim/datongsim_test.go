@@ -0,0 +1,53 @@
+package datongsim
+
+import (
+	"strings"
+	"testing"
+)
+
+const testTickets = `{
+  "0x1000000000000000000000000000000000000000000000000000000000000001": {"Owner": "0x0000000000000000000000000000000000000001", "Height": 10, "StartTime": 1, "ExpireTime": 4000000000, "Value": 5000000000000000000000, "Weight": 1},
+  "0x1000000000000000000000000000000000000000000000000000000000000002": {"Owner": "0x0000000000000000000000000000000000000002", "Height": 20, "StartTime": 1, "ExpireTime": 4000000000, "Value": 5000000000000000000000, "Weight": 1},
+  "0x1000000000000000000000000000000000000000000000000000000000000003": {"Owner": "0x0000000000000000000000000000000000000002", "Height": 30, "StartTime": 1, "ExpireTime": 4000000000, "Value": 5000000000000000000000, "Weight": 1},
+  "0x1000000000000000000000000000000000000000000000000000000000000004": {"Owner": "0x0000000000000000000000000000000000000003", "Height": 40, "StartTime": 1, "ExpireTime": 4000000000, "Value": 5000000000000000000000, "Weight": 1}
+}`
+
+func TestSimulate(t *testing.T) {
+	tickets, err := LoadTickets(strings.NewReader(testTickets))
+	if err != nil {
+		t.Fatalf("failed to load tickets: %v", err)
+	}
+	if n := tickets.NumberOfTickets(); n != 4 {
+		t.Fatalf("loaded %d tickets, want 4", n)
+	}
+
+	cfg := Config{Rounds: 2000, Seed: 7, Number: 100}
+	result, err := Simulate(tickets, cfg)
+	if err != nil {
+		t.Fatalf("failed to simulate: %v", err)
+	}
+	if len(result.Owners) != 3 {
+		t.Fatalf("got %d owners, want 3", len(result.Owners))
+	}
+	wins := uint64(0)
+	for _, s := range result.Owners {
+		wins += s.Wins
+	}
+	if wins != uint64(cfg.Rounds) {
+		t.Errorf("wins sum to %d, want %d", wins, cfg.Rounds)
+	}
+
+	again, _ := Simulate(tickets, cfg)
+	for i, s := range result.Owners {
+		if *again.Owners[i] != *s {
+			t.Fatalf("simulation is not deterministic: %+v != %+v", again.Owners[i], s)
+		}
+	}
+
+	if _, err := Simulate(tickets, Config{Rounds: 1, Number: 30}); err == nil {
+		t.Error("simulated with a ticket bought after the parent block")
+	}
+	if _, err := Simulate(tickets, Config{Number: 100}); err == nil {
+		t.Error("simulated without rounds")
+	}
+}
@@ -0,0 +1,150 @@
+package datong
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/rpc"
+)
+
+// TotalRewards returns the sum of the rewards of the blocks 1 to number
+func TotalRewards(number uint64) *big.Int {
+	total := new(big.Int)
+	for start := uint64(1); start <= number; {
+		end := (start/rewardHalvingBlocks+1)*rewardHalvingBlocks - 1
+		if end > number {
+			end = number
+		}
+		reward := CalcRewards(new(big.Int).SetUint64(start))
+		total.Add(total, reward.Mul(reward, new(big.Int).SetUint64(end-start+1)))
+		start = end + 1
+	}
+	return total
+}
+
+// newEpochSummary returns the summary of the epoch ending with block number,
+// whose tickets are hashed to ticketsHash
+func newEpochSummary(number uint64, ticketsHash common.Hash, tickets uint64, prev common.Hash) *common.EpochSummary {
+	total := TotalRewards(number)
+	return &common.EpochSummary{
+		Epoch:       common.EpochOf(number),
+		Number:      number,
+		TicketsHash: ticketsHash,
+		Tickets:     tickets,
+		Rewards:     new(big.Int).Sub(total, TotalRewards(number-common.EpochSummaryLength)),
+		FsnSupply:   new(big.Int).Add(common.SystemAsset.Total, total),
+		Prev:        prev,
+	}
+}
+
+// commitEpochSummary stores the summary of the epoch ending with block number,
+// chained to the summary of the previous epoch if there is one
+func commitEpochSummary(statedb *state.StateDB, number uint64, ticketsHash common.Hash, tickets uint64) error {
+	var prev common.Hash
+	if summary, err := statedb.GetEpochSummary(common.EpochOf(number) - 1); err == nil {
+		prev = summary.Digest()
+	}
+	return statedb.SetEpochSummary(newEpochSummary(number, ticketsHash, tickets, prev))
+}
+
+// EpochSummaryResult is an epoch summary with its digest
+type EpochSummaryResult struct {
+	*common.EpochSummary
+	Digest common.Hash `json:"digest"`
+}
+
+// EpochSummaryProof compares an epoch summary with the chain it summarizes
+type EpochSummaryProof struct {
+	EpochSummaryResult
+	RewardsCredited *big.Int `json:"rewardsCredited"` // rewards credited by Finalize in the epoch, nil if not all were recorded
+	Valid           bool     `json:"valid"`
+	Mismatches      []string `json:"mismatches"` // differences to the chain
+}
+
+// GetEpochSummary returns the summary of an epoch in the state of the given
+// block
+func (api *API) GetEpochSummary(epoch uint64, number *rpc.BlockNumber) (*EpochSummaryResult, error) {
+	statedb, _, err := api.stateAt(number)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := statedb.GetEpochSummary(epoch)
+	if err != nil {
+		return nil, err
+	}
+	return &EpochSummaryResult{EpochSummary: summary, Digest: summary.Digest()}, nil
+}
+
+// VerifyEpochSummary checks the summary of an epoch against the header of its
+// last block, the block rewards credited by Finalize in the epoch and the
+// summary of the previous epoch
+func (api *API) VerifyEpochSummary(epoch uint64) (*EpochSummaryProof, error) {
+	statedb, _, err := api.stateAt(nil)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := statedb.GetEpochSummary(epoch)
+	if err != nil {
+		return nil, err
+	}
+	header := api.chain.GetHeaderByNumber(summary.Number)
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	var prev *common.EpochSummary
+	if epoch > 0 {
+		prev, _ = statedb.GetEpochSummary(epoch - 1)
+	}
+	// the rewards of an epoch starting before the fork are only partly recorded
+	var credited *big.Int
+	if first := summary.Number - common.EpochSummaryLength + 1; common.IsEpochSummaryEnabled(new(big.Int).SetUint64(first)) {
+		credited = statedb.GetEpochRewards(epoch)
+	}
+	return newEpochSummaryProof(summary, header, prev, credited), nil
+}
+
+// newEpochSummaryProof checks the summary against the header of its last
+// block and the rewards credited in its epoch. Without the credited rewards
+// the rewards are checked against the reward schedule.
+func newEpochSummaryProof(summary *common.EpochSummary, header *types.Header, prev *common.EpochSummary, credited *big.Int) *EpochSummaryProof {
+	proof := &EpochSummaryProof{
+		EpochSummaryResult: EpochSummaryResult{EpochSummary: summary, Digest: summary.Digest()},
+		RewardsCredited:    credited,
+		Mismatches:         []string{},
+	}
+	number := header.Number.Uint64()
+	if summary.Number != number || summary.Epoch != common.EpochOf(number) || !common.IsEpochEnd(number) {
+		proof.Mismatches = append(proof.Mismatches, fmt.Sprintf("block: summary %d of epoch %d, header %d", summary.Number, summary.Epoch, number))
+	}
+	if summary.TicketsHash != header.MixDigest {
+		proof.Mismatches = append(proof.Mismatches, fmt.Sprintf("tickets hash: summary %x, header %x", summary.TicketsHash, header.MixDigest))
+	}
+	if snap, err := NewSnapshotFromHeader(header); err != nil {
+		proof.Mismatches = append(proof.Mismatches, fmt.Sprintf("snapshot: %v", err))
+	} else if summary.Tickets != uint64(snap.TicketNumber) {
+		proof.Mismatches = append(proof.Mismatches, fmt.Sprintf("tickets: summary %d, header %d", summary.Tickets, snap.TicketNumber))
+	}
+	want := newEpochSummary(number, summary.TicketsHash, summary.Tickets, summary.Prev)
+	rewards := want.Rewards
+	if credited != nil {
+		rewards = credited
+	}
+	if summary.Rewards == nil || summary.Rewards.Cmp(rewards) != 0 {
+		proof.Mismatches = append(proof.Mismatches, fmt.Sprintf("rewards: summary %v, credited %v", summary.Rewards, rewards))
+	}
+	supply := want.FsnSupply
+	if prev != nil && prev.FsnSupply != nil {
+		supply = new(big.Int).Add(prev.FsnSupply, rewards)
+	}
+	if summary.FsnSupply == nil || summary.FsnSupply.Cmp(supply) != 0 {
+		proof.Mismatches = append(proof.Mismatches, fmt.Sprintf("FSN supply: summary %v, computed %v", summary.FsnSupply, supply))
+	}
+	if prev != nil && summary.Prev != prev.Digest() {
+		proof.Mismatches = append(proof.Mismatches, fmt.Sprintf("previous digest: summary %x, computed %x", summary.Prev, prev.Digest()))
+	}
+	proof.Valid = len(proof.Mismatches) == 0
+	return proof
+}
@@ -0,0 +1,118 @@
+package datong
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/params"
+)
+
+func TestTotalRewards(t *testing.T) {
+	sum := func(from, to uint64) *big.Int {
+		total := new(big.Int)
+		for n := from; n <= to; n++ {
+			total.Add(total, CalcRewards(new(big.Int).SetUint64(n)))
+		}
+		return total
+	}
+	if have := TotalRewards(0); have.Sign() != 0 {
+		t.Errorf("rewards of the genesis block: have %v, want 0", have)
+	}
+	if have, want := TotalRewards(1000), sum(1, 1000); have.Cmp(want) != 0 {
+		t.Errorf("rewards of the first blocks: have %v, want %v", have, want)
+	}
+	// across the first halving
+	from, to := uint64(rewardHalvingBlocks-100), uint64(rewardHalvingBlocks+100)
+	have := new(big.Int).Sub(TotalRewards(to), TotalRewards(from-1))
+	if want := sum(from, to); have.Cmp(want) != 0 {
+		t.Errorf("rewards across the halving: have %v, want %v", have, want)
+	}
+}
+
+func TestEpochSummaryProof(t *testing.T) {
+	snap := newSnapshot()
+	snap.SetTicketNumber(42)
+	extra := append(make([]byte, extraVanity), snap.Bytes()...)
+	number := 2 * common.EpochSummaryLength
+	header := &types.Header{
+		Number:    new(big.Int).SetUint64(number),
+		MixDigest: common.HexToHash("0x1234"),
+		Extra:     append(extra, make([]byte, extraSeal)...),
+	}
+	prev := newEpochSummary(common.EpochSummaryLength, common.HexToHash("0x01"), 40, common.Hash{})
+	summary := newEpochSummary(number, header.MixDigest, 42, prev.Digest())
+	if summary.Epoch != 2 || summary.Rewards.Cmp(new(big.Int).Sub(TotalRewards(number), TotalRewards(common.EpochSummaryLength))) != 0 {
+		t.Fatalf("summary mismatch: %+v", summary)
+	}
+	if proof := newEpochSummaryProof(summary, header, prev, nil); !proof.Valid {
+		t.Fatalf("valid summary rejected: %v", proof.Mismatches)
+	}
+
+	forged := *summary
+	forged.FsnSupply = new(big.Int).Add(summary.FsnSupply, big.NewInt(1))
+	forged.Tickets = 41
+	if proof := newEpochSummaryProof(&forged, header, prev, nil); proof.Valid || len(proof.Mismatches) != 2 {
+		t.Errorf("forged summary: valid %v, mismatches %v", proof.Valid, proof.Mismatches)
+	}
+	if proof := newEpochSummaryProof(summary, header, summary, nil); proof.Valid {
+		t.Errorf("summary chained to the wrong epoch accepted")
+	}
+}
+
+func TestEpochSummaryProofCreditedRewards(t *testing.T) {
+	snap := newSnapshot()
+	snap.SetTicketNumber(42)
+	extra := append(make([]byte, extraVanity), snap.Bytes()...)
+	number := 2 * common.EpochSummaryLength
+	header := &types.Header{
+		Number:    new(big.Int).SetUint64(number),
+		MixDigest: common.HexToHash("0x1234"),
+		Extra:     append(extra, make([]byte, extraSeal)...),
+	}
+	prev := newEpochSummary(common.EpochSummaryLength, common.HexToHash("0x01"), 40, common.Hash{})
+	summary := newEpochSummary(number, header.MixDigest, 42, prev.Digest())
+
+	if proof := newEpochSummaryProof(summary, header, prev, new(big.Int).Set(summary.Rewards)); !proof.Valid {
+		t.Fatalf("summary matching the credited rewards rejected: %v", proof.Mismatches)
+	}
+	// Finalize credited less than the schedule the summary was computed from
+	credited := new(big.Int).Sub(summary.Rewards, CalcRewards(header.Number))
+	if proof := newEpochSummaryProof(summary, header, prev, credited); proof.Valid || len(proof.Mismatches) != 2 {
+		t.Errorf("summary not matching the credited rewards: valid %v, mismatches %v", proof.Valid, proof.Mismatches)
+	}
+}
+
+func TestFinalizeCreditsEpochRewards(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	for i := 0; i < 2; i++ {
+		statedb.AddTicket(common.Ticket{
+			Owner:      common.BytesToAddress([]byte{byte(i + 1)}),
+			TicketBody: common.TicketBody{ID: common.BytesToHash([]byte{byte(i + 1)}), Height: 1, ExpireTime: 1 << 40},
+		})
+	}
+	tickets, _ := statedb.AllTickets()
+	selected, _ := tickets.Get(common.BytesToHash([]byte{1}))
+	parent := &types.Header{Number: big.NewInt(1), Time: 100, Extra: make([]byte, extraVanity+extraSeal)}
+	header := &types.Header{ParentHash: parent.Hash(), Number: big.NewInt(2), Time: 115, Coinbase: common.HexToAddress("0x0c"), Extra: make([]byte, extraVanity+extraSeal)}
+	header.SetSelectedTicket(selected)
+	chain := &headerChain{headers: map[common.Hash]*types.Header{parent.Hash(): parent}}
+
+	dt := New(&params.DaTongConfig{Period: 15}, rawdb.NewMemoryDatabase())
+	if err := dt.Finalize(chain, header, statedb, nil, nil); err != nil {
+		t.Fatalf("finalize failed: %v", err)
+	}
+	reward := CalcRewards(header.Number)
+	if have := statedb.GetBalance(common.SystemAssetID, header.Coinbase); have.Cmp(reward) != 0 {
+		t.Errorf("coinbase balance: have %v, want %v", have, reward)
+	}
+	if have := statedb.GetEpochRewards(common.EpochOf(2)); have == nil || have.Cmp(reward) != 0 {
+		t.Errorf("credited epoch rewards: have %v, want %v", have, reward)
+	}
+}
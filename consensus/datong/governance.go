@@ -0,0 +1,102 @@
+package datong
+
+import (
+	"errors"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/rpc"
+)
+
+// maxProposalsPerQuery is the maximum number of proposals GetProposals returns
+const maxProposalsPerQuery = 100
+
+// ProposalResult is a proposal with its state at a given block
+type ProposalResult struct {
+	common.Proposal
+	Quorum      uint64 `json:"quorum"`
+	Status      string `json:"status"`
+	BlockNumber uint64 `json:"blockNumber"`
+}
+
+func (api *API) headerByNumber(number *rpc.BlockNumber) (*types.Header, error) {
+	var header *types.Header
+	if number == nil || *number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return header, nil
+}
+
+// stateAt returns the state of the block with the given number
+func (api *API) stateAt(number *rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	if api.dt == nil || api.dt.stateCache == nil {
+		return nil, nil, errors.New("state is not available")
+	}
+	header, err := api.headerByNumber(number)
+	if err != nil {
+		return nil, nil, err
+	}
+	statedb, err := state.New(header.Root, header.MixDigest, api.dt.stateCache)
+	if err != nil {
+		return nil, nil, err
+	}
+	return statedb, header, nil
+}
+
+func newProposalResult(proposal common.Proposal, header *types.Header) *ProposalResult {
+	number := header.Number.Uint64()
+	return &ProposalResult{
+		Proposal:    proposal,
+		Quorum:      proposal.Quorum(),
+		Status:      proposal.Status(number),
+		BlockNumber: number,
+	}
+}
+
+// GetProposal returns a proposal and its result at the given block
+func (api *API) GetProposal(id common.Hash, number *rpc.BlockNumber) (*ProposalResult, error) {
+	statedb, header, err := api.stateAt(number)
+	if err != nil {
+		return nil, err
+	}
+	proposal, err := statedb.GetProposal(id)
+	if err != nil {
+		return nil, err
+	}
+	return newProposalResult(proposal, header), nil
+}
+
+// GetProposals returns the latest proposals at the given block, newest first
+func (api *API) GetProposals(number *rpc.BlockNumber) ([]*ProposalResult, error) {
+	statedb, header, err := api.stateAt(number)
+	if err != nil {
+		return nil, err
+	}
+	results := []*ProposalResult{}
+	for i := statedb.GetProposalCount(); i > 0 && len(results) < maxProposalsPerQuery; i-- {
+		proposal, err := statedb.GetProposal(statedb.GetProposalID(i - 1))
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, newProposalResult(proposal, header))
+	}
+	return results, nil
+}
+
+// GetProposalVote returns the vote of addr on a proposal, nil if it did not vote
+func (api *API) GetProposalVote(id common.Hash, addr common.Address, number *rpc.BlockNumber) (*common.ProposalVote, error) {
+	statedb, _, err := api.stateAt(number)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := statedb.GetProposal(id); err != nil {
+		return nil, err
+	}
+	return statedb.GetProposalVote(id, addr), nil
+}
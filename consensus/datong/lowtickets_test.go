@@ -0,0 +1,172 @@
+package datong
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/core/vm"
+	"github.com/FusionFoundation/go-fusion/params"
+)
+
+// headerChain is a chain reader serving a fixed set of headers and blocks,
+// and the canonical headers by number
+type headerChain struct {
+	headers map[common.Hash]*types.Header
+	blocks  map[common.Hash]*types.Block
+	numbers map[uint64]*types.Header
+}
+
+func (c *headerChain) Config() *params.ChainConfig  { return params.TestChainConfig }
+func (c *headerChain) CurrentHeader() *types.Header { return nil }
+func (c *headerChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	return c.headers[hash]
+}
+func (c *headerChain) GetHeaderByNumber(number uint64) *types.Header {
+	return c.numbers[number]
+}
+func (c *headerChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	return c.headers[hash]
+}
+func (c *headerChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	return c.blocks[hash]
+}
+
+// finalizeWithTickets finalizes a block selecting the first of n tickets and
+// returns the number of tickets left
+func finalizeWithTickets(t *testing.T, config *params.DaTongConfig, n int) (uint64, error) {
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	var tickets []*common.Ticket
+	for i := 0; i < n; i++ {
+		ticket := &common.Ticket{
+			Owner:      common.BytesToAddress([]byte{byte(i + 1)}),
+			TicketBody: common.TicketBody{ID: common.BytesToHash([]byte{byte(i + 1)}), Height: 1, StartTime: 0, ExpireTime: 1 << 40},
+		}
+		if err := statedb.AddTicket(*ticket); err != nil {
+			t.Fatalf("failed to add ticket: %v", err)
+		}
+		tickets = append(tickets, ticket)
+	}
+	parent := &types.Header{Number: big.NewInt(1), Time: 100, Extra: make([]byte, extraVanity+extraSeal)}
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     big.NewInt(2),
+		Time:       115,
+		Extra:      make([]byte, extraVanity+extraSeal),
+	}
+	header.SetSelectedTicket(tickets[0])
+	chain := &headerChain{headers: map[common.Hash]*types.Header{parent.Hash(): parent}}
+
+	dt := New(config, rawdb.NewMemoryDatabase())
+	if err := dt.Finalize(chain, header, statedb, nil, nil); err != nil {
+		return 0, err
+	}
+	return statedb.TotalNumberOfTickets(), nil
+}
+
+func TestLowTicketPool(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	// without the guard the selected ticket is spent and the last ticket
+	// can not produce a block
+	if left, err := finalizeWithTickets(t, &params.DaTongConfig{Period: 15}, 2); err != nil || left != 1 {
+		t.Fatalf("unguarded pool: have %d tickets left, err %v, want 1", left, err)
+	}
+	if _, err := finalizeWithTickets(t, &params.DaTongConfig{Period: 15}, 1); err == nil {
+		t.Fatalf("unguarded block with the last ticket accepted")
+	}
+
+	// below the minimum the tickets are kept and the pool does not run out
+	guarded := &params.DaTongConfig{Period: 15, MinTickets: 3}
+	for n := 1; n <= 2; n++ {
+		if left, err := finalizeWithTickets(t, guarded, n); err != nil || left != uint64(n) {
+			t.Errorf("guarded pool of %d: have %d tickets left, err %v", n, left, err)
+		}
+	}
+	// at the minimum the selected ticket is spent again
+	if left, err := finalizeWithTickets(t, guarded, 3); err != nil || left != 2 {
+		t.Errorf("pool at the minimum: have %d tickets left, err %v, want 2", left, err)
+	}
+}
+
+func TestLowTicketDelay(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	snapPool := func(tickets int) []byte {
+		snap := newSnapshot()
+		snap.SetTicketNumber(tickets)
+		extra := append(make([]byte, extraVanity), snap.Bytes()...)
+		return append(extra, make([]byte, extraSeal)...)
+	}
+	dt := New(&params.DaTongConfig{Period: 15, MinTickets: 3, LowTicketPeriod: 60}, rawdb.NewMemoryDatabase())
+	for _, tt := range []struct {
+		tickets int
+		low     bool
+	}{{2, true}, {3, false}} {
+		parent := &types.Header{Number: big.NewInt(1), Time: uint64(time.Now().Unix()), Extra: snapPool(tt.tickets)}
+		header := &types.Header{ParentHash: parent.Hash(), Number: big.NewInt(2)}
+		chain := &headerChain{headers: map[common.Hash]*types.Header{parent.Hash(): parent}}
+		if delay := dt.lowTicketDelay(chain, header); (delay > 0) != tt.low {
+			t.Errorf("pool of %d tickets: delay %v, want low %v", tt.tickets, delay, tt.low)
+		}
+		// the delay is a consensus rule, not only a sealing policy
+		header.Time = parent.Time + 15
+		if err := dt.checkBlockTime(chain, header, parent); (err != nil) != tt.low {
+			t.Errorf("pool of %d tickets: block after 15s: err %v, want low %v", tt.tickets, err, tt.low)
+		}
+		header.Time = parent.Time + 60
+		if err := dt.checkBlockTime(chain, header, parent); err != nil {
+			t.Errorf("pool of %d tickets: block after the low ticket period rejected: %v", tt.tickets, err)
+		}
+	}
+}
+
+// transferRecorder records the internal transfers passed to a tracer
+type transferRecorder struct {
+	vm.Tracer
+	transfers []*common.InternalTransfer
+}
+
+func (r *transferRecorder) CaptureFsnTransfer(transfer *common.InternalTransfer) {
+	r.transfers = append(r.transfers, transfer)
+}
+
+func TestFinalizeTicketReturnTransfer(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	var tickets []*common.Ticket
+	for i := 0; i < 2; i++ {
+		ticket := &common.Ticket{
+			Owner:      common.BytesToAddress([]byte{byte(i + 1)}),
+			TicketBody: common.TicketBody{ID: common.BytesToHash([]byte{byte(i + 1)}), Height: 1, StartTime: 0, ExpireTime: 1 << 40},
+		}
+		statedb.AddTicket(*ticket)
+		tickets = append(tickets, ticket)
+	}
+	parent := &types.Header{Number: big.NewInt(1), Time: 100, Extra: make([]byte, extraVanity+extraSeal)}
+	header := &types.Header{ParentHash: parent.Hash(), Number: big.NewInt(2), Time: 115, Extra: make([]byte, extraVanity+extraSeal)}
+	header.SetSelectedTicket(tickets[0])
+	chain := &headerChain{headers: map[common.Hash]*types.Header{parent.Hash(): parent}}
+
+	recorder := new(transferRecorder)
+	dt := New(&params.DaTongConfig{Period: 15}, rawdb.NewMemoryDatabase())
+	if err := dt.FinalizeAndTrace(chain, header, statedb, recorder); err != nil {
+		t.Fatalf("finalize failed: %v", err)
+	}
+	if len(recorder.transfers) != 1 {
+		t.Fatalf("have %d transfers, want the return of the selected ticket", len(recorder.transfers))
+	}
+	transfer := recorder.transfers[0]
+	if transfer.Type != common.TransferTicketReturn || transfer.To != tickets[0].Owner || transfer.Ref != tickets[0].ID ||
+		transfer.Value.Cmp(tickets[0].UnitValue()) != 0 {
+		t.Errorf("unexpected transfer %+v", transfer)
+	}
+}
@@ -0,0 +1,98 @@
+package datong
+
+import (
+	"sync"
+	"time"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/consensus"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/metrics"
+)
+
+var (
+	selectionPrecomputeTimer = metrics.NewRegisteredTimer("datong/selection/precompute", nil)
+	selectionInlineTimer     = metrics.NewRegisteredTimer("datong/selection/inline", nil)
+	selectionWaitTimer       = metrics.NewRegisteredTimer("datong/selection/wait", nil)
+	selectionHitMeter        = metrics.NewRegisteredMeter("datong/selection/hit", nil)
+	selectionMissMeter       = metrics.NewRegisteredMeter("datong/selection/miss", nil)
+)
+
+// selection is the ticket selection input of the blocks after parent: its
+// decompressed tickets and their ranking, which do not depend on the miner.
+// The tickets and ranking are shared and must not be modified.
+type selection struct {
+	parent  common.Hash
+	done    chan struct{} // closed once the fields below are set
+	tickets common.TicketsDataSlice
+	ranking DistanceSlice
+	err     error
+}
+
+// selectionCache keeps the selection of the latest parent asked for, so the
+// selection computed in the background on a new head is reused by Prepare,
+// the resubmitted work and the verification of the sealed block.
+type selectionCache struct {
+	lock   sync.Mutex
+	latest *selection
+}
+
+// get returns the selection after parent, computing it unless it is cached or
+// being computed, in which case it waits for it. The duration is reported to
+// timer if the selection is computed.
+func (sc *selectionCache) get(dt *DaTong, chain consensus.ChainReader, parent *types.Header, timer metrics.Timer) (*selection, bool) {
+	hash := parent.Hash()
+
+	sc.lock.Lock()
+	if s := sc.latest; s != nil && s.parent == hash {
+		sc.lock.Unlock()
+		select {
+		case <-s.done:
+		default:
+			defer selectionWaitTimer.UpdateSince(time.Now())
+			<-s.done
+		}
+		return s, true
+	}
+	s := &selection{parent: hash, done: make(chan struct{})}
+	sc.latest = s
+	sc.lock.Unlock()
+
+	start := time.Now()
+	s.tickets, s.err = dt.getAllTickets(chain, parent)
+	if s.err == nil {
+		s.ranking = rankTickets(s.tickets, parent)
+	}
+	timer.UpdateSince(start)
+	close(s.done)
+
+	if s.err != nil {
+		// do not keep the failure, the state may become available
+		sc.lock.Lock()
+		if sc.latest == s {
+			sc.latest = nil
+		}
+		sc.lock.Unlock()
+	}
+	return s, false
+}
+
+// PrecomputeSelection computes the ticket selection of the blocks after
+// parent, so that preparing the next block does not wait for the tickets to be
+// decompressed and ranked. It is meant to run in the background on a new head.
+func (dt *DaTong) PrecomputeSelection(chain consensus.ChainReader, parent *types.Header) error {
+	s, _ := dt.selections.get(dt, chain, parent, selectionPrecomputeTimer)
+	return s.err
+}
+
+// parentSelection returns the tickets of parent and their ranking, from the
+// cache if they have been computed already.
+func (dt *DaTong) parentSelection(chain consensus.ChainReader, parent *types.Header) (common.TicketsDataSlice, DistanceSlice, error) {
+	s, cached := dt.selections.get(dt, chain, parent, selectionInlineTimer)
+	if cached {
+		selectionHitMeter.Mark(1)
+	} else {
+		selectionMissMeter.Mark(1)
+	}
+	return s.tickets, s.ranking, s.err
+}
@@ -0,0 +1,57 @@
+package datong
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/core/types"
+)
+
+func TestPrecomputeSelection(t *testing.T) {
+	dt := New(nil, nil)
+	dt.SetStateCache(state.NewDatabase(rawdb.NewMemoryDatabase()))
+
+	owner := common.HexToAddress("0x01")
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, dt.stateCache)
+	for i := 1; i <= 3; i++ {
+		statedb.AddTicket(common.Ticket{Owner: owner, TicketBody: common.TicketBody{ID: common.BigToHash(big.NewInt(int64(i))), Height: 1, ExpireTime: common.TimeLockForever}})
+	}
+	ticketsHash, err := statedb.UpdateTickets(big.NewInt(10), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent := &types.Header{
+		Number:    big.NewInt(10),
+		MixDigest: ticketsHash,
+		Extra:     make([]byte, extraVanity+extraSeal),
+	}
+	if err := dt.PrecomputeSelection(nil, parent); err != nil {
+		t.Fatalf("precompute failed: %v", err)
+	}
+	precomputed := dt.selections.latest
+	if precomputed == nil || precomputed.parent != parent.Hash() || len(precomputed.ranking) != 1 {
+		t.Fatalf("selection not precomputed: %+v", precomputed)
+	}
+
+	// preparing the next block uses the precomputed ranking
+	header := &types.Header{ParentHash: parent.Hash(), Number: big.NewInt(11), Coinbase: owner}
+	_, selected, order, _, err := dt.calcBlockDifficulty(nil, header, parent)
+	if err != nil {
+		t.Fatalf("difficulty failed: %v", err)
+	}
+	if selected != precomputed.ranking[0].tk || order != 0 {
+		t.Errorf("selected %v at %d, want the precomputed %v", selected, order, precomputed.ranking[0].tk)
+	}
+
+	// a failed selection is not kept
+	unknown := &types.Header{Number: big.NewInt(0), Root: common.HexToHash("0x02"), MixDigest: common.HexToHash("0x03")}
+	if err := dt.PrecomputeSelection(nil, unknown); err == nil {
+		t.Fatal("precomputed the selection of an unknown state")
+	}
+	if dt.selections.latest != nil {
+		t.Errorf("failed selection kept")
+	}
+}
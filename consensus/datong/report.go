@@ -8,6 +8,8 @@ import (
 
 	"github.com/FusionFoundation/go-fusion/common"
 	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/consensus"
+	"github.com/FusionFoundation/go-fusion/core/state"
 	"github.com/FusionFoundation/go-fusion/core/types"
 	"github.com/FusionFoundation/go-fusion/core/vm"
 	"github.com/FusionFoundation/go-fusion/rlp"
@@ -63,7 +65,7 @@ func DecodeReport(report []byte) (*types.Header, *types.Header, error) {
 		return nil, nil, fmt.Errorf("wrong report length")
 	}
 	data1len := common.BytesToInt(report[:4])
-	if len(report) < 4+data1len {
+	if data1len < 0 || len(report) < 4+data1len {
 		return nil, nil, fmt.Errorf("wrong report length")
 	}
 	data1 := report[4 : data1len+4]
@@ -86,7 +88,8 @@ func DecodeReport(report []byte) (*types.Header, *types.Header, error) {
 }
 
 func CheckAddingReport(state vm.StateDB, report []byte, blockNumber *big.Int) (*types.Header, *types.Header, error) {
-	if state.IsReportExist(report) {
+	expiryEnabled := common.IsReportExpiryEnabled(blockNumber)
+	if !expiryEnabled && state.IsReportExist(report) {
 		return nil, nil, fmt.Errorf("CheckAddingReport: report exist")
 	}
 
@@ -94,6 +97,9 @@ func CheckAddingReport(state vm.StateDB, report []byte, blockNumber *big.Int) (*
 	if err != nil {
 		return nil, nil, err
 	}
+	if expiryEnabled && isReported(state, report, header1) {
+		return nil, nil, fmt.Errorf("CheckAddingReport: report exist")
+	}
 
 	if blockNumber != nil && blockNumber.Uint64()-header1.Number.Uint64() > maxReportDepth {
 		return nil, nil, fmt.Errorf("report error: too long ago")
@@ -120,6 +126,60 @@ func CheckAddingReport(state vm.StateDB, report []byte, blockNumber *big.Int) (*
 	return header1, header2, nil
 }
 
+// reportExpiry returns the epoch from which reports of the given height are
+// too old to be accepted and are deleted
+func reportExpiry(number uint64) uint64 {
+	return (number+maxReportDepth)/common.ReportEpochLength + 1
+}
+
+// isReported tells whether the double mining proven by report was reported,
+// reports of heights before the report expiry fork are also looked up by hash
+func isReported(state vm.StateDB, report []byte, header *types.Header) bool {
+	if state.IsReported(header.Coinbase, header.Number.Uint64()) {
+		return true
+	}
+	return !common.IsReportExpiryEnabled(header.Number) && state.IsReportExist(report)
+}
+
+// AddReport stores a checked report, since the report expiry fork by the
+// reported miner and height until the report expires
+func AddReport(state vm.StateDB, report []byte, header *types.Header, blockNumber *big.Int) error {
+	if !common.IsReportExpiryEnabled(blockNumber) {
+		return state.AddReport(report)
+	}
+	number := header.Number.Uint64()
+	return state.AddReportAt(header.Coinbase, number, reportExpiry(number))
+}
+
+// migrateReports moves the reports of the last maxReportDepth blocks before
+// the report expiry fork to the height index, so that they expire and still
+// count as duplicates. Older reports can no longer be added and are left alone.
+func migrateReports(chain consensus.ChainReader, statedb *state.StateDB, parent *types.Header) error {
+	hash, number := parent.Hash(), parent.Number.Uint64()
+	for i := 0; i < maxReportDepth && number > 0; i++ {
+		block := chain.GetBlock(hash, number)
+		if block == nil {
+			return fmt.Errorf("migrate reports: block %d (%x) not found", number, hash)
+		}
+		for _, tx := range block.Transactions() {
+			param := common.FSNCallParam{}
+			if rlp.DecodeBytes(tx.Data(), &param) != nil || param.Func != common.ReportIllegalFunc {
+				continue
+			}
+			header1, _, err := DecodeReport(param.Data)
+			if err != nil {
+				continue
+			}
+			reported := header1.Number.Uint64()
+			if _, err := statedb.MigrateReport(param.Data, header1.Coinbase, reported, reportExpiry(reported)); err != nil {
+				return fmt.Errorf("migrate report of block %d: %v", number, err)
+			}
+		}
+		hash, number = block.ParentHash(), number-1
+	}
+	return nil
+}
+
 // punish miner and reward reporter
 func ProcessReport(heade1, header2 *types.Header, reporter common.Address, state vm.StateDB, height *big.Int, timestamp uint64) []common.Hash {
 	miner := heade1.Coinbase
@@ -134,17 +194,10 @@ func ProcessReport(heade1, header2 *types.Header, reporter common.Address, state
 
 func punishTicket(state vm.StateDB, miner common.Address) []common.Hash {
 	// delete tickets from the miner
-	allTickets, err := state.AllTickets()
+	tickets, err := state.TicketsByOwner(miner)
 	if err != nil {
 		return nil
 	}
-	var tickets common.TicketSlice
-	for _, v := range allTickets {
-		if v.Owner == miner {
-			tickets = v.ToTicketSlice()
-			break
-		}
-	}
 
 	leftTickets := len(tickets)
 	if leftTickets == 0 {
@@ -161,7 +214,7 @@ func punishTicket(state vm.StateDB, miner common.Address) []common.Hash {
 		ids[i] = tickets[len(tickets)-1-i].ID
 	}
 	for _, id := range ids {
-		state.RemoveTicket(id)
+		state.SpendTicket(id)
 	}
 	return ids
 }
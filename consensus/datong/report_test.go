@@ -0,0 +1,121 @@
+package datong
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/crypto"
+)
+
+func signedHeader(key *ecdsa.PrivateKey, number int64, time uint64) *types.Header {
+	header := &types.Header{
+		ParentHash: common.HexToHash("0x01"),
+		Number:     big.NewInt(number),
+		Coinbase:   crypto.PubkeyToAddress(key.PublicKey),
+		Time:       time,
+		Extra:      make([]byte, extraVanity+extraSeal),
+	}
+	sig, _ := crypto.Sign(sigHash(header).Bytes(), key)
+	copy(header.Extra[extraVanity:], sig)
+	return header
+}
+
+func TestReportExpiry(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	key, _ := crypto.GenerateKey()
+	miner := crypto.PubkeyToAddress(key.PublicKey)
+	height := big.NewInt(2950)
+
+	report, _ := buildReportData(signedHeader(key, 2900, 1), signedHeader(key, 2900, 2))
+	header1, _, err := CheckAddingReport(statedb, report, height)
+	if err != nil {
+		t.Fatalf("report rejected: %v", err)
+	}
+	if err := AddReport(statedb, report, header1, height); err != nil {
+		t.Fatalf("failed to add report: %v", err)
+	}
+	if statedb.IsReportExist(report) || !statedb.IsReported(miner, 2900) {
+		t.Fatalf("report not stored by miner and height")
+	}
+
+	// another pair of the same slot is a duplicate
+	other, _ := buildReportData(signedHeader(key, 2900, 1), signedHeader(key, 2900, 3))
+	if _, _, err := CheckAddingReport(statedb, other, height); err == nil {
+		t.Fatalf("duplicate report accepted")
+	}
+
+	// the report is deleted when it can no longer be added
+	expiry := reportExpiry(2900)
+	if expiry*common.ReportEpochLength <= 2900+maxReportDepth {
+		t.Fatalf("report expires at epoch %d while it can be added", expiry)
+	}
+	if cleared, _ := statedb.DeleteExpiredReports(expiry - 1); cleared != 0 {
+		t.Fatalf("report deleted before expiry")
+	}
+	if cleared, err := statedb.DeleteExpiredReports(expiry); err != nil || cleared == 0 || statedb.IsReported(miner, 2900) {
+		t.Fatalf("expired report not deleted")
+	}
+}
+
+func TestMigrateReport(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	key, _ := crypto.GenerateKey()
+	miner := crypto.PubkeyToAddress(key.PublicKey)
+
+	report, _ := buildReportData(signedHeader(key, 100, 1), signedHeader(key, 100, 2))
+	if migrated, _ := statedb.MigrateReport(report, miner, 100, reportExpiry(100)); migrated {
+		t.Fatalf("migrated a missing report")
+	}
+	statedb.AddReport(report)
+	if migrated, err := statedb.MigrateReport(report, miner, 100, reportExpiry(100)); err != nil || !migrated {
+		t.Fatalf("report not migrated")
+	}
+	if statedb.IsReportExist(report) || !statedb.IsReported(miner, 100) {
+		t.Fatalf("report not moved to the height index")
+	}
+}
+
+func TestMigrateReportsMissingBlock(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	block1 := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)})
+	block2 := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2), ParentHash: block1.Hash()})
+	chain := &headerChain{blocks: map[common.Hash]*types.Block{block2.Hash(): block2}}
+
+	// a missing block must not leave the reports before it unmigrated
+	if err := migrateReports(chain, statedb, block2.Header()); err == nil {
+		t.Fatal("migration with a missing block succeeded")
+	}
+	chain.blocks[block1.Hash()] = block1
+	if err := migrateReports(chain, statedb, block2.Header()); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+}
+
+func TestDecodeReportLength(t *testing.T) {
+	// the length prefix decodes to a negative int
+	if _, _, err := DecodeReport([]byte{0x84, 0x30, 0x30, 0x30}); err == nil {
+		t.Fatal("report with a negative length accepted")
+	}
+}
+
+func TestCorruptReportExpiryList(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	miner := common.HexToAddress("0x01")
+	expiry := reportExpiry(100)
+	statedb.SetStructData(common.ReportKeyAddress, append([]byte("reportExpiry"), common.Uint64ToBytes(expiry)...), []byte{0xff})
+
+	if err := statedb.AddReportAt(miner, 100, expiry); err == nil {
+		t.Errorf("added a report to a corrupt expiry list")
+	}
+	if _, err := statedb.DeleteExpiredReports(expiry); err == nil {
+		t.Errorf("deleted the reports of a corrupt expiry list")
+	}
+}
@@ -0,0 +1,72 @@
+package datong
+
+import (
+	"sync"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/log"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// maxWatchedHeaders is the number of recent (parent, miner) pairs the double
+// mining watcher remembers
+const maxWatchedHeaders = 4096
+
+// minedKey identifies a block slot, a miner may sign only one block per parent
+type minedKey struct {
+	parent common.Hash
+	miner  common.Address
+}
+
+var (
+	minedHeaders, _    = lru.New(maxWatchedHeaders) // minedKey -> *types.Header
+	reportedMinings, _ = lru.New(maxWatchedHeaders) // minedKey -> struct{}
+	minedHeadersLock   sync.Mutex
+)
+
+// WatchHeader remembers the miner of a synced or gossiped header and reports
+// the miner when it already signed a different block on the same parent.
+// It does nothing unless auto reporting is enabled.
+func WatchHeader(header *types.Header) {
+	if !common.AutoReportIllegal || header.Number == nil || len(header.Extra) < extraVanity+extraSeal {
+		return
+	}
+	if !common.IsMultipleMiningCheckingEnabled(header.Number) {
+		return
+	}
+	if first := watchHeader(header); first != nil {
+		log.Info("Double mining detected", "number", header.Number, "miner", header.Coinbase, "hash1", first.Hash(), "hash2", header.Hash())
+		ReportIllegal(first, header)
+	}
+}
+
+// watchHeader records the header and returns the previously seen header of the
+// same slot if both are validly signed by the miner and not yet reported
+func watchHeader(header *types.Header) *types.Header {
+	key := minedKey{parent: header.ParentHash, miner: header.Coinbase}
+
+	minedHeadersLock.Lock()
+	defer minedHeadersLock.Unlock()
+
+	cached, ok := minedHeaders.Get(key)
+	if !ok {
+		minedHeaders.Add(key, header)
+		return nil
+	}
+	first := cached.(*types.Header)
+	if first.Hash() == header.Hash() || reportedMinings.Contains(key) {
+		return nil
+	}
+	// signatures are only checked on conflicts, a forged header must not
+	// replace or hide the genuine one
+	if VerifySignature(header) != nil {
+		return nil
+	}
+	if VerifySignature(first) != nil {
+		minedHeaders.Add(key, header)
+		return nil
+	}
+	reportedMinings.Add(key, struct{}{})
+	return first
+}
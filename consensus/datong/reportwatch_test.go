@@ -0,0 +1,48 @@
+package datong
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/crypto"
+)
+
+func TestWatchHeader(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	miner := crypto.PubkeyToAddress(key.PublicKey)
+	parent := common.HexToHash("0x01")
+
+	newHeader := func(time uint64, signed bool) *types.Header {
+		header := &types.Header{
+			ParentHash: parent,
+			Number:     big.NewInt(10),
+			Coinbase:   miner,
+			Time:       time,
+			Extra:      make([]byte, extraVanity+extraSeal),
+		}
+		if signed {
+			sig, _ := crypto.Sign(sigHash(header).Bytes(), key)
+			copy(header.Extra[extraVanity:], sig)
+		}
+		return header
+	}
+	first, second := newHeader(1, true), newHeader(2, true)
+
+	if conflict := watchHeader(first); conflict != nil {
+		t.Fatalf("first header reported")
+	}
+	if conflict := watchHeader(first); conflict != nil {
+		t.Fatalf("same header reported")
+	}
+	if conflict := watchHeader(newHeader(3, false)); conflict != nil {
+		t.Fatalf("forged header reported")
+	}
+	if conflict := watchHeader(second); conflict == nil || conflict.Hash() != first.Hash() {
+		t.Fatalf("double mining not detected")
+	}
+	if conflict := watchHeader(newHeader(4, true)); conflict != nil {
+		t.Fatalf("double mining reported twice")
+	}
+}
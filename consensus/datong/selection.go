@@ -0,0 +1,155 @@
+package datong
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/rpc"
+)
+
+// SelectionCandidate is the best ticket of an owner in the selection ranking
+// of a block. The distance of a ticket is weight^2 + id^2, with weight the
+// number of blocks since the ticket was bought and id the hash of the parent
+// pos hash, the ticket ID and the owner, the owner of the lowest distance
+// mines first.
+type SelectionCandidate struct {
+	Owner    common.Address `json:"owner"`
+	TicketID common.Hash    `json:"ticketID"`
+	Height   uint64         `json:"height"`
+	Weight   uint64         `json:"weight"`
+	Distance *hexutil.Big   `json:"distance"`
+}
+
+// SelectionProof reconstructs the ticket selection of a block from the tickets
+// of its parent, so that the selection of the miner can be verified.
+type SelectionProof struct {
+	Number      uint64                `json:"number"`
+	Hash        common.Hash           `json:"hash"`
+	Miner       common.Address        `json:"miner"`
+	ParentHash  common.Hash           `json:"parentHash"`
+	PosHash     common.Hash           `json:"posHash"`     // selection seed hashed from the parent header
+	TicketsHash common.Hash           `json:"ticketsHash"` // hash of the parent ticket blob
+	Tickets     uint64                `json:"tickets"`
+	Owners      uint64                `json:"owners"`
+	Order       uint64                `json:"order"` // position of the miner in the ranking
+	Selected    common.Hash           `json:"selected"`
+	Retreat     []common.Hash         `json:"retreat"`
+	Difficulty  *hexutil.Big          `json:"difficulty"`
+	Candidates  []*SelectionCandidate `json:"candidates"` // ranking up to the miner
+	Valid       bool                  `json:"valid"`
+	Mismatches  []string              `json:"mismatches"` // differences to the header
+}
+
+// GetTicketSelectionProof recomputes the ticket selection of a block and
+// compares it with the selected ticket, the retreat tickets, the order and
+// the difficulty recorded in its header.
+func (api *API) GetTicketSelectionProof(number *rpc.BlockNumber) (*SelectionProof, error) {
+	header, err := api.headerByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+	if header.Number.Sign() == 0 {
+		return nil, fmt.Errorf("genesis block is not mined")
+	}
+	parent := api.chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return nil, errUnknownBlock
+	}
+	snap, err := NewSnapshotFromHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	parentTickets, err := api.dt.getAllTickets(api.chain, parent)
+	if err != nil {
+		return nil, err
+	}
+	return newSelectionProof(header, parent, parentTickets, snap), nil
+}
+
+// RankTickets orders the owners of the tickets of parent by the distance of
+// their best ticket, the first owner mines the block after parent first.
+func RankTickets(parentTickets common.TicketsDataSlice, parent *types.Header) []*SelectionCandidate {
+	ranking := rankTickets(parentTickets, parent)
+	candidates := make([]*SelectionCandidate, len(ranking))
+	for i, t := range ranking {
+		candidates[i] = newSelectionCandidate(t, parent)
+	}
+	return candidates
+}
+
+func newSelectionCandidate(t *DisInfo, parent *types.Header) *SelectionCandidate {
+	return &SelectionCandidate{
+		Owner:    t.tk.Owner,
+		TicketID: t.tk.ID,
+		Height:   t.tk.Height,
+		Weight:   parent.Number.Uint64() - t.tk.Height + 1,
+		Distance: (*hexutil.Big)(t.res),
+	}
+}
+
+func newSelectionProof(header, parent *types.Header, parentTickets common.TicketsDataSlice, snap *Snapshot) *SelectionProof {
+	total, owners := parentTickets.TotalWeight(), parentTickets.NumberOfOwners()
+	proof := &SelectionProof{
+		Number:      header.Number.Uint64(),
+		Hash:        header.Hash(),
+		Miner:       header.Coinbase,
+		ParentHash:  parent.Hash(),
+		PosHash:     posHash(parent),
+		TicketsHash: parent.MixDigest,
+		Tickets:     total,
+		Owners:      owners,
+		Retreat:     []common.Hash{},
+		Mismatches:  []string{},
+	}
+	found := false
+	for i, t := range rankTickets(parentTickets, parent) {
+		proof.Candidates = append(proof.Candidates, newSelectionCandidate(t, parent))
+		if t.tk.Owner == header.Coinbase {
+			proof.Selected = t.tk.ID
+			found = true
+			break
+		}
+		proof.Order++
+		if i < maxNumberOfDeletedTickets {
+			proof.Retreat = append(proof.Retreat, t.tk.ID)
+		}
+	}
+	if !found {
+		proof.Mismatches = append(proof.Mismatches, "miner has no ticket in the parent tickets")
+		return proof
+	}
+	proof.Difficulty = (*hexutil.Big)(calcDifficulty(total, owners, proof.Order))
+
+	if proof.Selected != snap.Selected {
+		proof.Mismatches = append(proof.Mismatches, fmt.Sprintf("selected ticket: header %x, computed %x", snap.Selected, proof.Selected))
+	}
+	if !hashesEqual(proof.Retreat, snap.Retreat) {
+		proof.Mismatches = append(proof.Mismatches, fmt.Sprintf("retreat tickets: header %x, computed %x", snap.Retreat, proof.Retreat))
+	}
+	if header.Nonce.Uint64() != proof.Order {
+		proof.Mismatches = append(proof.Mismatches, fmt.Sprintf("order: header %d, computed %d", header.Nonce.Uint64(), proof.Order))
+	}
+	if (*big.Int)(proof.Difficulty).Cmp(header.Difficulty) != 0 {
+		proof.Mismatches = append(proof.Mismatches, fmt.Sprintf("difficulty: header %v, computed %v", header.Difficulty, proof.Difficulty.ToInt()))
+	}
+	if err := VerifySignature(header); err != nil {
+		proof.Mismatches = append(proof.Mismatches, fmt.Sprintf("signature: %v", err))
+	}
+	proof.Valid = len(proof.Mismatches) == 0
+	return proof
+}
+
+func hashesEqual(a, b []common.Hash) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package datong
+
+import (
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/crypto"
+)
+
+func TestSelectionProof(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var tickets common.TicketsDataSlice
+	for i := 0; i < 5; i++ {
+		var owner common.Address
+		rnd.Read(owner[:])
+		data := common.TicketsData{Owner: owner}
+		for j := 0; j < 3; j++ {
+			var id common.Hash
+			rnd.Read(id[:])
+			data.Tickets = append(data.Tickets, common.TicketBody{ID: id, Height: uint64(rnd.Intn(90) + 1), ExpireTime: 1 << 40})
+		}
+		tickets = append(tickets, data)
+	}
+	parent := &types.Header{
+		Number: big.NewInt(100),
+		Extra:  make([]byte, extraVanity+extraSeal),
+	}
+	ranking := rankTickets(tickets, parent)
+
+	// the third owner of the ranking mines the block, signed by a random key
+	key, _ := crypto.GenerateKey()
+	miner := ranking[2].tk
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     big.NewInt(101),
+		Coinbase:   miner.Owner,
+		Nonce:      types.EncodeNonce(2),
+		Difficulty: calcDifficulty(15, 5, 2),
+		Extra:      make([]byte, extraVanity+extraSeal),
+	}
+	sig, _ := crypto.Sign(sigHash(header).Bytes(), key)
+	copy(header.Extra[extraVanity:], sig)
+	snap := &Snapshot{
+		Selected: miner.ID,
+		Retreat:  []common.Hash{ranking[0].tk.ID, ranking[1].tk.ID},
+	}
+
+	proof := newSelectionProof(header, parent, tickets, snap)
+	if proof.Order != 2 || proof.Selected != miner.ID || len(proof.Candidates) != 3 {
+		t.Fatalf("selection mismatch: order %d, selected %x, %d candidates", proof.Order, proof.Selected, len(proof.Candidates))
+	}
+	for i := 1; i < len(proof.Candidates); i++ {
+		if proof.Candidates[i-1].Distance.ToInt().Cmp(proof.Candidates[i].Distance.ToInt()) > 0 {
+			t.Fatalf("candidates not ranked by distance")
+		}
+	}
+	// everything matches except the signer, which is not the miner
+	if proof.Valid || len(proof.Mismatches) != 1 || !strings.HasPrefix(proof.Mismatches[0], "signature") {
+		t.Fatalf("unexpected mismatches: %v", proof.Mismatches)
+	}
+
+	snap.Selected = ranking[0].tk.ID
+	if proof := newSelectionProof(header, parent, tickets, snap); len(proof.Mismatches) != 2 {
+		t.Fatalf("wrong selected ticket not reported: %v", proof.Mismatches)
+	}
+}
+
+// A ticket of several ticket prices draws an id per unit, its first unit
+// draws the id of a legacy ticket. Before the denomination fork only the
+// first unit draws.
+func TestWeightedTicketSelection(t *testing.T) {
+	owner := common.HexToAddress("0x01")
+	parent := &types.Header{
+		Number: big.NewInt(100),
+		Extra:  make([]byte, extraVanity+extraSeal),
+	}
+	pos := posHash(parent)
+	single := common.TicketBody{ID: common.HexToHash("0x02"), Height: 50, ExpireTime: 1 << 40}
+	if have, want := ticketUnitHash(pos, single.ID, owner, 0), crypto.Keccak256(pos[:], single.ID[:], []byte(owner.Hex())); string(have) != string(want) {
+		t.Fatalf("first unit id mismatch")
+	}
+	weighted := single
+	weighted.SetWeight(10)
+
+	distance := func(body common.TicketBody) *big.Int {
+		list := rankTickets(common.TicketsDataSlice{{Owner: owner, Tickets: common.TicketBodySlice{body}}}, parent)
+		return list[0].res
+	}
+	if distance(weighted).Cmp(distance(single)) != 0 {
+		t.Fatalf("weighted ticket draws several units before the fork")
+	}
+
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+	if distance(weighted).Cmp(distance(single)) > 0 {
+		t.Fatalf("weighted ticket is farther than its first unit")
+	}
+	pos = posHash(parent)
+	min := distance(single)
+	w := big.NewInt(parent.Number.Int64() - 50 + 1)
+	for unit := uint64(1); unit < 10; unit++ {
+		id := new(big.Int).SetBytes(ticketUnitHash(pos, single.ID, owner, unit))
+		if d := new(big.Int).Add(new(big.Int).Mul(w, w), new(big.Int).Mul(id, id)); d.Cmp(min) < 0 {
+			min = d
+		}
+	}
+	if distance(weighted).Cmp(min) != 0 {
+		t.Fatalf("weighted ticket distance is not the minimum of its units")
+	}
+}
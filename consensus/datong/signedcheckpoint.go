@@ -0,0 +1,253 @@
+package datong
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"strings"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/crypto"
+	"github.com/FusionFoundation/go-fusion/ethdb"
+	"github.com/FusionFoundation/go-fusion/log"
+	"github.com/FusionFoundation/go-fusion/params"
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+// signedCheckpointTXTPrefix prefixes the DNS TXT record of a signed checkpoint,
+// followed by the base64 (URL encoding, no padding) RLP of the checkpoint.
+const signedCheckpointTXTPrefix = "fsncp1:"
+
+var (
+	// trusted signers of the signed checkpoints, the mainnet and testnet
+	// signers are given with --checkpoint.signers until their keys are
+	// published, the devnet signers are its genesis accounts
+	mainnetCheckpointSigners = []common.Address{}
+	testnetCheckpointSigners = []common.Address{}
+	devnetCheckpointSigners  = []common.Address{
+		common.HexToAddress("0x0122bf3930c1201a21133937ad5c83eb4ded1b08"),
+		common.HexToAddress("0x37a200388caa75edcc53a2bd329f7e9563c6acb6"),
+		common.HexToAddress("0x07f35aba9555a532c0edc2bd6350c891b6f2c8d0"),
+	}
+
+	// signedCheckpoint is the checkpoint loaded by InitSignedCheckpoint
+	signedCheckpoint *SignedCheckpoint
+)
+
+// SignedCheckpoint is a recent block of the chain vouched for by the trusted
+// checkpoint signers. New nodes fast sync anchored to it: it is added to the
+// check points, so peers on another chain are dropped and the headers up to
+// it skip the ticket verification, and the ticket set and the state root of
+// the block are checked against the signed hashes.
+type SignedCheckpoint struct {
+	Number      uint64          `json:"number"`
+	Hash        common.Hash     `json:"hash"`
+	TicketsHash common.Hash     `json:"ticketsHash"` // MixDigest of the block, the hash of its ticket blob
+	StateRoot   common.Hash     `json:"stateRoot"`
+	Signatures  []hexutil.Bytes `json:"signatures"`
+}
+
+// SigningHash returns the hash signed by the checkpoint signers on the chain
+// of chainID, so that a signature is not valid on another network.
+func (c *SignedCheckpoint) SigningHash(chainID *big.Int) common.Hash {
+	enc, _ := rlp.EncodeToBytes([]interface{}{chainID, c.Number, c.Hash, c.TicketsHash, c.StateRoot})
+	return crypto.Keccak256Hash(enc)
+}
+
+// Verify checks that at least threshold distinct signers signed the checkpoint
+// of the chain of chainID.
+func (c *SignedCheckpoint) Verify(chainID *big.Int, signers []common.Address, threshold int) error {
+	if threshold <= 0 || len(signers) == 0 {
+		return errors.New("no trusted checkpoint signers")
+	}
+	trusted := make(map[common.Address]bool, len(signers))
+	for _, signer := range signers {
+		trusted[signer] = true
+	}
+	hash := c.SigningHash(chainID)
+	signed := make(map[common.Address]bool)
+	for _, sig := range c.Signatures {
+		pub, err := crypto.SigToPub(hash[:], sig)
+		if err != nil {
+			return fmt.Errorf("invalid checkpoint signature: %v", err)
+		}
+		signer := crypto.PubkeyToAddress(*pub)
+		if !trusted[signer] {
+			return fmt.Errorf("checkpoint signed by untrusted %v", signer.String())
+		}
+		signed[signer] = true
+	}
+	if len(signed) < threshold {
+		return fmt.Errorf("checkpoint has %d signers, %d required", len(signed), threshold)
+	}
+	return nil
+}
+
+// VerifyHeader checks that header is the checkpointed block.
+func (c *SignedCheckpoint) VerifyHeader(header *types.Header) error {
+	switch {
+	case header.Number.Uint64() != c.Number:
+		return fmt.Errorf("checkpoint number mismatch: have %v, want %v", header.Number, c.Number)
+	case header.MixDigest != c.TicketsHash:
+		return fmt.Errorf("checkpoint tickets hash mismatch: have %x, want %x", header.MixDigest, c.TicketsHash)
+	case header.Root != c.StateRoot:
+		return fmt.Errorf("checkpoint state root mismatch: have %x, want %x", header.Root, c.StateRoot)
+	case header.Hash() != c.Hash:
+		return fmt.Errorf("checkpoint hash mismatch: have %x, want %x", header.Hash(), c.Hash)
+	}
+	return nil
+}
+
+// CheckpointSigners returns the trusted checkpoint signers of the network
+// rules in use.
+func CheckpointSigners() []common.Address {
+	switch {
+	case common.UseTestnetRule:
+		return testnetCheckpointSigners
+	case common.UseDevnetRule:
+		return devnetCheckpointSigners
+	default:
+		return mainnetCheckpointSigners
+	}
+}
+
+// CheckpointChainID returns the chain ID of the network rules in use.
+func CheckpointChainID() *big.Int {
+	switch {
+	case common.UseTestnetRule:
+		return params.TestnetChainConfig.ChainID
+	case common.UseDevnetRule:
+		return params.DevnetChainConfig.ChainID
+	default:
+		return params.MainnetChainConfig.ChainID
+	}
+}
+
+// CheckpointThreshold returns the number of signers required for a signed
+// checkpoint, a majority of signers.
+func CheckpointThreshold(signers []common.Address) int {
+	return len(signers)/2 + 1
+}
+
+// LatestSignedCheckpoint returns the signed checkpoint in use, nil if none.
+func LatestSignedCheckpoint() *SignedCheckpoint {
+	return signedCheckpoint
+}
+
+// InitSignedCheckpoint loads a signed checkpoint from a JSON file or from the
+// TXT record of a DNS name, verifies it against the trusted signers and adds
+// it to the check points. The given signers replace the built-in ones. It
+// must be called after InitCheckPoints.
+func InitSignedCheckpoint(file, domain string, signers []common.Address) error {
+	var (
+		cp  *SignedCheckpoint
+		err error
+	)
+	switch {
+	case file != "":
+		cp, err = readSignedCheckpoint(file)
+	case domain != "":
+		cp, err = resolveSignedCheckpoint(domain)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(signers) == 0 {
+		signers = CheckpointSigners()
+	}
+	if err := cp.Verify(CheckpointChainID(), signers, CheckpointThreshold(signers)); err != nil {
+		return err
+	}
+	if hash, exist := CheckPoints[cp.Number]; exist && hash != cp.Hash {
+		return fmt.Errorf("signed checkpoint %d conflicts with check point %x", cp.Number, hash)
+	}
+	CheckPoints[cp.Number] = cp.Hash
+	if cp.Number > LastCheckPoint {
+		LastCheckPoint = cp.Number
+	}
+	signedCheckpoint = cp
+	log.Info("Loaded signed checkpoint", "number", cp.Number, "hash", cp.Hash, "tickets", cp.TicketsHash, "root", cp.StateRoot)
+	return nil
+}
+
+func readSignedCheckpoint(file string) (*SignedCheckpoint, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	cp := new(SignedCheckpoint)
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("invalid signed checkpoint file: %v", err)
+	}
+	return cp, nil
+}
+
+// EncodeSignedCheckpointTXT returns the DNS TXT record publishing cp.
+func EncodeSignedCheckpointTXT(cp *SignedCheckpoint) (string, error) {
+	enc, err := rlp.EncodeToBytes(cp)
+	if err != nil {
+		return "", err
+	}
+	return signedCheckpointTXTPrefix + base64.RawURLEncoding.EncodeToString(enc), nil
+}
+
+// DecodeSignedCheckpointTXT parses a DNS TXT record publishing a checkpoint.
+func DecodeSignedCheckpointTXT(txt string) (*SignedCheckpoint, error) {
+	if !strings.HasPrefix(txt, signedCheckpointTXTPrefix) {
+		return nil, errors.New("not a signed checkpoint record")
+	}
+	enc, err := base64.RawURLEncoding.DecodeString(txt[len(signedCheckpointTXTPrefix):])
+	if err != nil {
+		return nil, err
+	}
+	cp := new(SignedCheckpoint)
+	if err := rlp.DecodeBytes(enc, cp); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+func resolveSignedCheckpoint(domain string) (*SignedCheckpoint, error) {
+	txts, err := net.LookupTXT(domain)
+	if err != nil {
+		return nil, err
+	}
+	var latest *SignedCheckpoint
+	for _, txt := range txts {
+		cp, err := DecodeSignedCheckpointTXT(txt)
+		if err != nil {
+			continue
+		}
+		if latest == nil || cp.Number > latest.Number {
+			latest = cp
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no signed checkpoint published at %s", domain)
+	}
+	return latest, nil
+}
+
+// VerifySyncedTickets checks the ticket blob of a fast synced block against
+// the MixDigest of its header, which is covered by the checkpointed chain.
+func VerifySyncedTickets(db ethdb.KeyValueReader, header *types.Header) error {
+	if header.MixDigest == (common.Hash{}) {
+		return nil
+	}
+	blob, err := db.Get(header.MixDigest[:])
+	if err != nil || len(blob) == 0 {
+		return fmt.Errorf("missing ticket blob %x of block #%v", header.MixDigest, header.Number)
+	}
+	if hash := crypto.Keccak256Hash(blob); hash != header.MixDigest {
+		return fmt.Errorf("ticket blob of block #%v hash mismatch: have %x, want %x", header.Number, hash, header.MixDigest)
+	}
+	return nil
+}
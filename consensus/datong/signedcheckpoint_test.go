@@ -0,0 +1,105 @@
+package datong
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/crypto"
+	"github.com/FusionFoundation/go-fusion/params"
+)
+
+func TestSignedCheckpoint(t *testing.T) {
+	header := &types.Header{
+		Number:    big.NewInt(2000000),
+		MixDigest: common.HexToHash("0x7111"),
+		Root:      common.HexToHash("0x5007"),
+	}
+	cp := &SignedCheckpoint{
+		Number:      header.Number.Uint64(),
+		Hash:        header.Hash(),
+		TicketsHash: header.MixDigest,
+		StateRoot:   header.Root,
+	}
+	var signers []common.Address
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		signers = append(signers, crypto.PubkeyToAddress(key.PublicKey))
+		if i < 2 {
+			hash := cp.SigningHash(CheckpointChainID())
+			sig, err := crypto.Sign(hash[:], key)
+			if err != nil {
+				t.Fatalf("failed to sign checkpoint: %v", err)
+			}
+			cp.Signatures = append(cp.Signatures, sig)
+		}
+	}
+	if err := cp.Verify(CheckpointChainID(), signers, CheckpointThreshold(signers)); err != nil {
+		t.Fatalf("failed to verify checkpoint: %v", err)
+	}
+	if err := cp.Verify(CheckpointChainID(), signers, 3); err == nil {
+		t.Fatalf("checkpoint accepted without enough signers")
+	}
+	if err := cp.Verify(CheckpointChainID(), signers[1:], 1); err == nil {
+		t.Fatalf("checkpoint accepted with an untrusted signer")
+	}
+	if err := cp.Verify(params.TestnetChainConfig.ChainID, signers, CheckpointThreshold(signers)); err == nil {
+		t.Fatalf("checkpoint accepted on another network")
+	}
+	if err := cp.VerifyHeader(header); err != nil {
+		t.Fatalf("failed to verify checkpoint header: %v", err)
+	}
+	forged := *header
+	forged.MixDigest = common.HexToHash("0x7112")
+	if err := cp.VerifyHeader(&forged); err == nil {
+		t.Fatalf("checkpoint accepted a header with other tickets")
+	}
+
+	txt, err := EncodeSignedCheckpointTXT(cp)
+	if err != nil {
+		t.Fatalf("failed to encode checkpoint record: %v", err)
+	}
+	decoded, err := DecodeSignedCheckpointTXT(txt)
+	if err != nil {
+		t.Fatalf("failed to decode checkpoint record: %v", err)
+	}
+	if decoded.SigningHash(CheckpointChainID()) != cp.SigningHash(CheckpointChainID()) || len(decoded.Signatures) != len(cp.Signatures) {
+		t.Fatalf("checkpoint record mismatch: have %+v, want %+v", decoded, cp)
+	}
+	if err := decoded.Verify(CheckpointChainID(), signers, CheckpointThreshold(signers)); err != nil {
+		t.Fatalf("failed to verify decoded checkpoint: %v", err)
+	}
+}
+
+func TestDevnetCheckpointSigners(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	if signers := CheckpointSigners(); len(signers) == 0 {
+		t.Fatalf("no devnet checkpoint signers")
+	}
+	if CheckpointChainID().Cmp(params.DevnetChainConfig.ChainID) != 0 {
+		t.Fatalf("devnet checkpoint chain ID %v", CheckpointChainID())
+	}
+}
+
+func TestVerifySyncedTickets(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	blob := []byte("tickets")
+	hash := crypto.Keccak256Hash(blob)
+	header := &types.Header{Number: big.NewInt(1), MixDigest: hash}
+
+	if err := VerifySyncedTickets(db, header); err == nil {
+		t.Fatalf("missing ticket blob accepted")
+	}
+	db.Put(hash[:], []byte("forged"))
+	if err := VerifySyncedTickets(db, header); err == nil {
+		t.Fatalf("mismatching ticket blob accepted")
+	}
+	db.Put(hash[:], blob)
+	if err := VerifySyncedTickets(db, header); err != nil {
+		t.Fatalf("ticket blob rejected: %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
 	"github.com/FusionFoundation/go-fusion/core/types"
 	"github.com/FusionFoundation/go-fusion/crypto"
 )
@@ -13,6 +14,36 @@ type Snapshot struct {
 	Selected     common.Hash   `json:"selected"`
 	Retreat      []common.Hash `json:"retreat"`
 	TicketNumber int           `json:"ticketNumber"`
+
+	// details of the selected and retreat tickets, filled in by the API
+	// from the tickets of the parent block
+	SelectedTicket *SnapshotTicket   `json:"selectedTicket,omitempty"`
+	RetreatTickets []*SnapshotTicket `json:"retreatTickets,omitempty"`
+}
+
+// SnapshotTicket is a ticket of a snapshot as it was in the parent block
+type SnapshotTicket struct {
+	ID            common.Hash    `json:"id"`
+	Owner         common.Address `json:"owner"`
+	Value         *hexutil.Big   `json:"value"`
+	Weight        uint64         `json:"weight"`
+	ExpireTime    uint64         `json:"expireTime"`
+	RemainingTime uint64         `json:"remainingTime"` // seconds from the block time to the expiry
+}
+
+func newSnapshotTicket(ticket *common.Ticket, timestamp uint64) *SnapshotTicket {
+	remaining := uint64(0)
+	if ticket.ExpireTime > timestamp {
+		remaining = ticket.ExpireTime - timestamp
+	}
+	return &SnapshotTicket{
+		ID:            ticket.ID,
+		Owner:         ticket.Owner,
+		Value:         (*hexutil.Big)(ticket.Value()),
+		Weight:        ticket.Weight(),
+		ExpireTime:    ticket.ExpireTime,
+		RemainingTime: remaining,
+	}
 }
 
 type ticketLogType byte
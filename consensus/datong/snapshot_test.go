@@ -0,0 +1,77 @@
+package datong
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/params"
+)
+
+func TestSnapshotTicket(t *testing.T) {
+	ticket := &common.Ticket{
+		Owner:      common.HexToAddress("0x01"),
+		TicketBody: common.TicketBody{ID: common.HexToHash("0x02"), Height: 10, ExpireTime: 1000},
+	}
+	ticket.SetWeight(3)
+
+	st := newSnapshotTicket(ticket, 400)
+	if st.ID != ticket.ID || st.Owner != ticket.Owner || st.Weight != 3 {
+		t.Fatalf("ticket mismatch: have %+v", st)
+	}
+	if st.Value.ToInt().Cmp(ticket.Value()) != 0 {
+		t.Errorf("value: have %v, want %v", st.Value.ToInt(), ticket.Value())
+	}
+	if st.RemainingTime != 600 {
+		t.Errorf("remaining time: have %d, want 600", st.RemainingTime)
+	}
+	if st := newSnapshotTicket(ticket, 1200); st.RemainingTime != 0 {
+		t.Errorf("remaining time of expired ticket: have %d, want 0", st.RemainingTime)
+	}
+}
+
+// mapSnapshotCache is a SnapshotCache in memory
+type mapSnapshotCache map[common.Hash]*Snapshot
+
+func (c mapSnapshotCache) Snapshot(hash common.Hash) (*Snapshot, bool) {
+	snap, ok := c[hash]
+	return snap, ok
+}
+
+func (c mapSnapshotCache) AddSnapshot(hash common.Hash, snap *Snapshot) { c[hash] = snap }
+
+func TestSnapshotNotCachedWithoutTickets(t *testing.T) {
+	snap := newSnapshot()
+	snap.AddLog(&ticketLog{TicketID: common.HexToHash("0x01"), Type: ticketSelect})
+	snap.SetTicketNumber(1)
+	extra := append(make([]byte, extraVanity), snap.Bytes()...)
+	header := &types.Header{Number: big.NewInt(2), Extra: append(extra, make([]byte, extraSeal)...)}
+
+	cache := make(mapSnapshotCache)
+	dt := New(&params.DaTongConfig{Period: 15}, rawdb.NewMemoryDatabase())
+	dt.SetSnapshotCache(cache)
+	api := &API{chain: &headerChain{headers: map[common.Hash]*types.Header{}}, dt: dt}
+
+	// the parent is unknown, the ticket details can not be filled in
+	have, err := api.snapshotByHeader(header)
+	if err != nil {
+		t.Fatalf("snapshot not served: %v", err)
+	}
+	if have.Selected != common.HexToHash("0x01") || have.SelectedTicket != nil {
+		t.Errorf("unexpected snapshot %+v", have)
+	}
+	if len(cache) != 0 {
+		t.Errorf("incomplete snapshot cached")
+	}
+
+	// the genesis snapshot has no ticket details and is complete
+	genesis := &types.Header{Number: big.NewInt(0), Extra: header.Extra}
+	if _, err := api.snapshotByHeader(genesis); err != nil {
+		t.Fatalf("genesis snapshot not served: %v", err)
+	}
+	if _, ok := cache[genesis.Hash()]; !ok {
+		t.Errorf("genesis snapshot not cached")
+	}
+}
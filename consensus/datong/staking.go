@@ -0,0 +1,230 @@
+package datong
+
+import (
+	"errors"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/consensus"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/rpc"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	// stakingHistoryBlocks is the number of recent blocks inspected for the
+	// average block time and the last block produced by a staker
+	stakingHistoryBlocks = 20000
+
+	// maxStakingHistories is the number of stakers whose history is kept
+	maxStakingHistories = 64
+
+	secondsPerDay = 24 * 3600
+)
+
+// stakingHistories keeps the last inspected history of the stakers, so that
+// polling the status of a staker only walks the blocks since the last request
+var stakingHistories, _ = lru.New(maxStakingHistories) // common.Address -> *stakingHistory
+
+// StakingStatus is the staking health of an address at a given block
+type StakingStatus struct {
+	Address            common.Address  `json:"address"`
+	BlockNumber        hexutil.Uint64  `json:"blockNumber"`
+	Selectable         bool            `json:"selectable"`
+	Tickets            uint64          `json:"tickets"`      // selectable tickets of the address
+	TotalTickets       uint64          `json:"totalTickets"` // selectable tickets of all stakers
+	Weight             float64         `json:"weight"`       // share of the selectable tickets
+	AverageBlockTime   float64         `json:"averageBlockTime"`
+	ExpectedBlocksDay  float64         `json:"expectedBlocksPerDay"`
+	ExpectedNextBlock  *hexutil.Uint64 `json:"expectedNextBlock"` // expected seconds until the next produced block
+	BlocksProduced     uint64          `json:"blocksProduced"`    // blocks produced in the inspected history
+	HistoryBlocks      uint64          `json:"historyBlocks"`
+	LastBlock          *hexutil.Uint64 `json:"lastBlock"`
+	LastBlockTimestamp *hexutil.Uint64 `json:"lastBlockTimestamp"`
+}
+
+// selectableTickets counts the tickets of the owner and of all owners which can
+// be selected for the block after the one with the given timestamp, the ones
+// which started and did not expire
+func selectableTickets(tickets common.TicketsDataSlice, owner common.Address, timestamp uint64) (owned, total uint64) {
+	for _, v := range tickets {
+		for _, t := range v.Tickets {
+			if t.StartTime > timestamp || t.ExpireTime <= timestamp {
+				continue
+			}
+			total++
+			if v.Owner == owner {
+				owned++
+			}
+		}
+	}
+	return owned, total
+}
+
+// expectedBlocksPerDay returns the number of blocks a staker with the given
+// share of the tickets is expected to produce per day. The best ticket of
+// every owner competes for the block, so the chance of producing it is about
+// proportional to the number of tickets.
+func expectedBlocksPerDay(owned, total uint64, blockTime float64) float64 {
+	if owned == 0 || total == 0 || blockTime <= 0 {
+		return 0
+	}
+	return float64(owned) / float64(total) * secondsPerDay / blockTime
+}
+
+// stakingHistory is the history of the blocks from oldest to head inspected
+// for a staker, at most window blocks
+type stakingHistory struct {
+	head     *types.Header
+	oldest   *types.Header
+	blocks   uint64
+	produced uint64        // blocks produced by the staker
+	last     *types.Header // last block produced by the staker
+}
+
+// add adds the block after the head of the history
+func (h *stakingHistory) add(staker common.Address, header *types.Header) {
+	h.blocks++
+	if header.Coinbase == staker && header.Number.Sign() > 0 {
+		h.produced++
+		h.last = header
+	}
+}
+
+// walkStakingHistory walks back window blocks from header
+func walkStakingHistory(chain consensus.ChainReader, staker common.Address, header *types.Header, window uint64) *stakingHistory {
+	var headers []*types.Header
+	for cur := header; cur != nil && uint64(len(headers)) < window; {
+		headers = append(headers, cur)
+		if cur.Number.Sign() == 0 {
+			break
+		}
+		cur = chain.GetHeader(cur.ParentHash, cur.Number.Uint64()-1)
+	}
+	h := &stakingHistory{head: header, oldest: headers[len(headers)-1]}
+	for i := len(headers) - 1; i >= 0; i-- {
+		h.add(staker, headers[i])
+	}
+	return h
+}
+
+// extend returns the history moved to header, which descends from its head,
+// walking only the new blocks and dropping the oldest ones beyond the window.
+// It returns nil if header is not a descendant of the head.
+func (h *stakingHistory) extend(chain consensus.ChainReader, staker common.Address, header *types.Header, window uint64) *stakingHistory {
+	head := h.head.Number.Uint64()
+	if header.Number.Uint64() < head || header.Number.Uint64()-head > window {
+		return nil
+	}
+	var headers []*types.Header
+	cur := header
+	for cur.Number.Uint64() > head {
+		headers = append(headers, cur)
+		if cur = chain.GetHeader(cur.ParentHash, cur.Number.Uint64()-1); cur == nil {
+			return nil
+		}
+	}
+	if cur.Hash() != h.head.Hash() {
+		return nil
+	}
+	extended := *h
+	extended.head = header
+	for i := len(headers) - 1; i >= 0; i-- {
+		extended.add(staker, headers[i])
+	}
+	// the blocks dropped from the window are far enough behind the head to
+	// be canonical
+	for extended.blocks > window {
+		next := chain.GetHeaderByNumber(extended.oldest.Number.Uint64() + 1)
+		if next == nil || next.ParentHash != extended.oldest.Hash() {
+			return nil
+		}
+		if extended.oldest.Coinbase == staker && extended.oldest.Number.Sign() > 0 {
+			extended.produced--
+		}
+		extended.oldest = next
+		extended.blocks--
+	}
+	if extended.produced == 0 {
+		extended.last = nil
+	}
+	return &extended
+}
+
+// getStakingHistory returns the history of the staker ending at header,
+// extending the one of the last request when header descends from it
+func getStakingHistory(chain consensus.ChainReader, staker common.Address, header *types.Header) *stakingHistory {
+	if cached, ok := stakingHistories.Get(staker); ok {
+		if h := cached.(*stakingHistory).extend(chain, staker, header, stakingHistoryBlocks); h != nil {
+			stakingHistories.Add(staker, h)
+			return h
+		}
+	}
+	h := walkStakingHistory(chain, staker, header, stakingHistoryBlocks)
+	stakingHistories.Add(staker, h)
+	return h
+}
+
+// stakingStatus computes the staking status of addr at the given header
+func (api *API) stakingStatus(addr common.Address, header *types.Header) (*StakingStatus, error) {
+	if api.dt == nil {
+		return nil, errors.New("staking status is not available")
+	}
+	tickets, err := api.dt.getAllTickets(api.chain, header)
+	if err != nil {
+		return nil, err
+	}
+	status := &StakingStatus{
+		Address:     addr,
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+	}
+	status.Tickets, status.TotalTickets = selectableTickets(tickets, addr, header.Time)
+	status.Selectable = status.Tickets > 0
+	if status.TotalTickets > 0 {
+		status.Weight = float64(status.Tickets) / float64(status.TotalTickets)
+	}
+
+	// the recent history gives the block time and the produced blocks
+	history := getStakingHistory(api.chain, addr, header)
+	status.HistoryBlocks, status.BlocksProduced = history.blocks, history.produced
+	if history.last != nil {
+		number, time := hexutil.Uint64(history.last.Number.Uint64()), hexutil.Uint64(history.last.Time)
+		status.LastBlock, status.LastBlockTimestamp = &number, &time
+	}
+	if blocks := header.Number.Uint64() - history.oldest.Number.Uint64(); blocks > 0 {
+		status.AverageBlockTime = float64(header.Time-history.oldest.Time) / float64(blocks)
+	}
+	status.ExpectedBlocksDay = expectedBlocksPerDay(status.Tickets, status.TotalTickets, status.AverageBlockTime)
+	if status.ExpectedBlocksDay > 0 {
+		eta := hexutil.Uint64(secondsPerDay / status.ExpectedBlocksDay)
+		status.ExpectedNextBlock = &eta
+	}
+	return status, nil
+}
+
+// GetStakingStatus returns whether addr, or the mining account of the node if
+// not given, owns selectable tickets, its share of all tickets, the expected
+// number of blocks it produces per day and the last block it produced
+func (api *API) GetStakingStatus(addr *common.Address, number *rpc.BlockNumber) (*StakingStatus, error) {
+	var header *types.Header
+	if number == nil || *number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	var owner common.Address
+	if addr != nil {
+		owner = *addr
+	} else if api.dt != nil {
+		api.dt.lock.RLock()
+		owner = api.dt.signer
+		api.dt.lock.RUnlock()
+	}
+	if owner == (common.Address{}) {
+		return nil, errors.New("no address given and no mining account set")
+	}
+	return api.stakingStatus(owner, header)
+}
@@ -0,0 +1,65 @@
+package datong
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/types"
+)
+
+func TestSelectableTickets(t *testing.T) {
+	owner, other := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	tickets := common.TicketsDataSlice{
+		{Owner: owner, Tickets: common.TicketBodySlice{
+			{ID: common.HexToHash("0x10"), StartTime: 100, ExpireTime: 1000},
+			{ID: common.HexToHash("0x11"), StartTime: 600, ExpireTime: 2000}, // not started
+			{ID: common.HexToHash("0x12"), StartTime: 100, ExpireTime: 500},  // expired
+		}},
+		{Owner: other, Tickets: common.TicketBodySlice{
+			{ID: common.HexToHash("0x20"), StartTime: 500, ExpireTime: 1000},
+		}},
+	}
+	if owned, total := selectableTickets(tickets, owner, 500); owned != 1 || total != 2 {
+		t.Errorf("have %d of %d selectable tickets, want 1 of 2", owned, total)
+	}
+}
+
+func TestStakingHistoryExtend(t *testing.T) {
+	staker := common.HexToAddress("0x01")
+	chain := &headerChain{headers: map[common.Hash]*types.Header{}, numbers: map[uint64]*types.Header{}}
+	var headers []*types.Header
+	for i := 0; i < 60; i++ {
+		header := &types.Header{Number: big.NewInt(int64(i)), Time: uint64(1000 + 13*i)}
+		if i > 0 {
+			header.ParentHash = headers[i-1].Hash()
+		}
+		if i%7 == 0 || i%11 == 0 {
+			header.Coinbase = staker
+		}
+		chain.headers[header.Hash()] = header
+		chain.numbers[uint64(i)] = header
+		headers = append(headers, header)
+	}
+
+	const window = 10
+	history := walkStakingHistory(chain, staker, headers[5], window)
+	for _, n := range []int{5, 6, 9, 16, 17, 26, 35, 44, 53, 59} {
+		if history = history.extend(chain, staker, headers[n], window); history == nil {
+			t.Fatalf("history not extended to block %d", n)
+		}
+		want := walkStakingHistory(chain, staker, headers[n], window)
+		if history.blocks != want.blocks || history.produced != want.produced || history.oldest != want.oldest || history.last != want.last {
+			t.Fatalf("block %d: have %d blocks from %v with %d produced, last %v, want %d from %v with %d, last %v", n,
+				history.blocks, history.oldest.Number, history.produced, history.last, want.blocks, want.oldest.Number, want.produced, want.last)
+		}
+	}
+	if history.extend(chain, staker, headers[40], window) != nil {
+		t.Error("history extended to an ancestor of its head")
+	}
+	fork := &types.Header{ParentHash: headers[58].Hash(), Number: big.NewInt(59), Extra: []byte("fork")}
+	chain.headers[fork.Hash()] = fork
+	if history.extend(chain, staker, &types.Header{ParentHash: fork.Hash(), Number: big.NewInt(60)}, window) != nil {
+		t.Error("history extended to a block of another branch")
+	}
+}
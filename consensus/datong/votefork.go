@@ -5,11 +5,18 @@ import (
 
 	"github.com/FusionFoundation/go-fusion/common"
 	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/log"
+	"github.com/FusionFoundation/go-fusion/params"
 )
 
-//-------------------------- vote1 fork -------------------------
-func ApplyVote1HardFork(statedb *state.StateDB, blockNumber *big.Int, timestamp uint64) {
-	for _, addr := range common.Vote1DrainList {
-		statedb.TransferAll(addr, common.Vote1RefundAddress, blockNumber, timestamp)
+//-------------------------- asset recovery -------------------------
+// ApplyAssetRecoveries executes the hard fork asset recoveries of a block,
+// the vote1 fork among them, moving everything owned by each From to its To.
+func ApplyAssetRecoveries(statedb *state.StateDB, recoveries []*params.AssetRecovery, blockNumber *big.Int, timestamp uint64) {
+	for _, r := range recoveries {
+		tickets, _ := statedb.TicketsByOwner(r.From)
+		log.Info("Applying asset recovery", "number", blockNumber, "from", r.From, "to", r.To,
+			"fsn", statedb.GetBalance(common.SystemAssetID, r.From), "tickets", len(tickets), "notation", statedb.GetNotation(r.From))
+		statedb.TransferAll(r.From, r.To, blockNumber, timestamp)
 	}
 }
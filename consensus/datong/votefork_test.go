@@ -0,0 +1,77 @@
+package datong
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/params"
+)
+
+func TestApplyAssetRecoveries(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+
+	var (
+		from      = common.HexToAddress("0x01")
+		to        = common.HexToAddress("0x02")
+		other     = common.HexToAddress("0x03")
+		timestamp = uint64(1000)
+		number    = big.NewInt(786000)
+	)
+	statedb.SetBalance(from, common.SystemAssetID, big.NewInt(42))
+	tickets := []common.Ticket{
+		{Owner: from, TicketBody: common.TicketBody{ID: common.HexToHash("0x11"), StartTime: 0, ExpireTime: 500}},
+		{Owner: other, TicketBody: common.TicketBody{ID: common.HexToHash("0x31"), StartTime: 0, ExpireTime: 5000}},
+		{Owner: from, TicketBody: common.TicketBody{ID: common.HexToHash("0x12"), StartTime: 0, ExpireTime: 5000}},
+		{Owner: from, TicketBody: common.TicketBody{ID: common.HexToHash("0x13"), StartTime: 0, ExpireTime: 6000}},
+	}
+	for _, ticket := range tickets {
+		if err := statedb.AddTicket(ticket); err != nil {
+			t.Fatalf("failed to add ticket: %v", err)
+		}
+	}
+
+	// the transfer is journaled like any other state change
+	snapshot := statedb.Snapshot()
+	statedb.ClearTickets(from, to, number, timestamp)
+	statedb.RevertToSnapshot(snapshot)
+	if owned, _ := statedb.TicketsByOwner(from); len(owned) != 3 {
+		t.Fatalf("reverted ticket count mismatch: have %d, want 3", len(owned))
+	}
+
+	ApplyAssetRecoveries(statedb, []*params.AssetRecovery{{Height: number.Uint64(), From: from, To: to}}, number, timestamp)
+
+	if owned, _ := statedb.TicketsByOwner(from); len(owned) != 0 {
+		t.Fatalf("%d tickets of the recovered address left", len(owned))
+	}
+	if owned, _ := statedb.TicketsByOwner(other); len(owned) != 1 {
+		t.Fatalf("tickets of other owners modified: have %d, want 1", len(owned))
+	}
+	if balance := statedb.GetBalance(common.SystemAssetID, to); balance.Cmp(big.NewInt(42)) != 0 {
+		t.Fatalf("balance mismatch: have %v, want 42", balance)
+	}
+	// the live tickets are converted back, the expired one is dropped
+	want := new(big.Int).Mul(common.TicketPrice(number), big.NewInt(2))
+	if value := statedb.GetTimeLockBalance(common.SystemAssetID, to).GetSpendableValue(timestamp, 5000); value.Cmp(want) != 0 {
+		t.Fatalf("time lock mismatch: have %v, want %v", value, want)
+	}
+	var ticketLogs int
+	for _, l := range statedb.GetLogs(common.HardForkTxHash) {
+		if l.Topics[0] != common.HardForkTransferTopic || l.Topics[1] != from.Hash() || l.Topics[2] != to.Hash() {
+			t.Fatalf("unexpected log topics: %v", l.Topics)
+		}
+		var transfer struct{ Type string }
+		if err := json.Unmarshal(l.Data, &transfer); err != nil {
+			t.Fatalf("invalid log data %s: %v", l.Data, err)
+		}
+		if transfer.Type == common.HardForkTransferTicket {
+			ticketLogs++
+		}
+	}
+	if ticketLogs != 2 {
+		t.Fatalf("ticket log count mismatch: have %d, want 2", ticketLogs)
+	}
+}
@@ -0,0 +1,72 @@
+// Code generated by efsn fsnabigen - DO NOT EDIT.
+// SPDX-License-Identifier: LGPL-3.0-or-later
+pragma solidity ^0.8.4;
+
+/// @title FSNContract calls the FSN contract of the Fusion chain
+/// @notice The FSN contract is not called by selector, its input is the
+/// function type as a word followed by the ABI encoding of the arguments.
+/// Failed calls revert with the errors below, since the FSN contract ABI fork.
+library FSNContract {
+    address internal constant ADDRESS = 0x9999999999999999999999999999999999999999;
+
+    error UnknownFunc();
+    error NotEnoughBalance();
+    error WrongTimeRange();
+    error ValueOverflow();
+    error WrongLenOfInput();
+    error InvalidSendAssetFlag();
+    error MustCallByContract();
+    error ReentrantCall();
+    error NotReceivingAsset();
+
+    event SendAssetTimeLock(address indexed from, address indexed to, bytes32 indexed asset, uint256 value, uint64 start, uint64 end);
+
+    struct SendAssetItem {
+        bytes32 asset;
+        address to;
+        uint256 value;
+        uint64 start;
+        uint64 end;
+        uint8 flag;
+    }
+
+    function sendAsset(bytes32 asset, address to, uint256 value, uint64 start, uint64 end, uint8 flag) internal returns (bool ok) {
+        bytes memory ret = _call(abi.encodePacked(uint256(1), abi.encode(asset, to, value, start, end, flag)));
+        (ok) = abi.decode(ret, (bool));
+    }
+
+    function batchSendAsset(SendAssetItem[] memory items) internal returns (uint256 count) {
+        bytes memory ret = _call(abi.encodePacked(uint256(2), abi.encode(items)));
+        (count) = abi.decode(ret, (uint256));
+    }
+
+    function getReceivedAsset() internal view returns (bytes32 asset, address from, uint256 value, uint64 start, uint64 end, uint8 flag) {
+        bytes memory ret = _staticcall(abi.encodePacked(uint256(3), abi.encode()));
+        (asset, from, value, start, end, flag) = abi.decode(ret, (bytes32, address, uint256, uint64, uint64, uint8));
+    }
+
+    function _call(bytes memory input) private returns (bytes memory ret) {
+        bool ok;
+        (ok, ret) = ADDRESS.call(input);
+        _check(ok, ret);
+    }
+
+    function _staticcall(bytes memory input) private view returns (bytes memory ret) {
+        bool ok;
+        (ok, ret) = ADDRESS.staticcall(input);
+        _check(ok, ret);
+    }
+
+    function _check(bool ok, bytes memory ret) private pure {
+        if (!ok) {
+            assembly {
+                revert(add(ret, 32), mload(ret))
+            }
+        }
+    }
+}
+
+/// @notice Called with the sent amount as value when an account sends an asset to the contract
+interface IReceiveAsset {
+    function receiveAsset(bytes32 assetID, uint64 startTime, uint64 endTime, uint8 flag, uint256[] calldata extraData) external payable;
+}
@@ -0,0 +1,5 @@
+// Package fsncontract contains the Go bindings of the FSN contract and of the
+// FSN call logs, FSNContract.sol is the matching Solidity library.
+package fsncontract
+
+//go:generate go run ../../cmd/efsn fsnabigen --sol FSNContract.sol --go fsncontract.go --pkg fsncontract
@@ -0,0 +1,310 @@
+// Code generated by efsn fsnabigen - DO NOT EDIT.
+
+package fsncontract
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/FusionFoundation/go-fusion/accounts/abi"
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core/types"
+)
+
+// FSNContractABI is the JSON ABI of the FSN contract functions, errors,
+// events and callbacks. The functions are not called by selector, their input
+// is the function type as a word followed by the ABI encoding of the inputs.
+const FSNContractABI = "[{\"type\":\"function\",\"name\":\"sendAsset\",\"stateMutability\":\"nonpayable\",\"inputs\":[{\"name\":\"asset\",\"type\":\"bytes32\"},{\"name\":\"to\",\"type\":\"address\"},{\"name\":\"value\",\"type\":\"uint256\"},{\"name\":\"start\",\"type\":\"uint64\"},{\"name\":\"end\",\"type\":\"uint64\"},{\"name\":\"flag\",\"type\":\"uint8\"}],\"outputs\":[{\"name\":\"ok\",\"type\":\"bool\"}]},{\"type\":\"function\",\"name\":\"batchSendAsset\",\"stateMutability\":\"nonpayable\",\"inputs\":[{\"name\":\"items\",\"type\":\"tuple[]\",\"components\":[{\"name\":\"asset\",\"type\":\"bytes32\"},{\"name\":\"to\",\"type\":\"address\"},{\"name\":\"value\",\"type\":\"uint256\"},{\"name\":\"start\",\"type\":\"uint64\"},{\"name\":\"end\",\"type\":\"uint64\"},{\"name\":\"flag\",\"type\":\"uint8\"}]}],\"outputs\":[{\"name\":\"count\",\"type\":\"uint256\"}]},{\"type\":\"function\",\"name\":\"getReceivedAsset\",\"stateMutability\":\"view\",\"inputs\":[],\"outputs\":[{\"name\":\"asset\",\"type\":\"bytes32\"},{\"name\":\"from\",\"type\":\"address\"},{\"name\":\"value\",\"type\":\"uint256\"},{\"name\":\"start\",\"type\":\"uint64\"},{\"name\":\"end\",\"type\":\"uint64\"},{\"name\":\"flag\",\"type\":\"uint8\"}]},{\"type\":\"error\",\"name\":\"UnknownFunc\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"NotEnoughBalance\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"WrongTimeRange\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"ValueOverflow\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"WrongLenOfInput\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"InvalidSendAssetFlag\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"MustCallByContract\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"ReentrantCall\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"NotReceivingAsset\",\"inputs\":[]},{\"type\":\"event\",\"name\":\"SendAssetTimeLock\",\"inputs\":[{\"name\":\"from\",\"type\":\"address\",\"indexed\":true},{\"name\":\"to\",\"type\":\"address\",\"indexed\":true},{\"name\":\"asset\",\"type\":\"bytes32\",\"indexed\":true},{\"name\":\"value\",\"type\":\"uint256\"},{\"name\":\"start\",\"type\":\"uint64\"},{\"name\":\"end\",\"type\":\"uint64\"}]},{\"type\":\"function\",\"name\":\"receiveAsset\",\"stateMutability\":\"payable\",\"inputs\":[{\"name\":\"assetID\",\"type\":\"bytes32\"},{\"name\":\"startTime\",\"type\":\"uint64\"},{\"name\":\"endTime\",\"type\":\"uint64\"},{\"name\":\"flag\",\"type\":\"uint8\"},{\"name\":\"extraData\",\"type\":\"uint256[]\"}]}]"
+
+// FSNContractAddress is the address of the FSN contract
+var FSNContractAddress = common.HexToAddress("0x9999999999999999999999999999999999999999")
+
+var fsnContractABI, _ = abi.JSON(strings.NewReader(FSNContractABI))
+
+// Function types of the FSN contract
+const (
+	FuncSendAsset        = 1
+	FuncBatchSendAsset   = 2
+	FuncGetReceivedAsset = 3
+)
+
+// SendAssetItem is the Go representation of the SendAssetItem tuple
+type SendAssetItem struct {
+	Asset common.Hash
+	To    common.Address
+	Value *big.Int
+	Start uint64
+	End   uint64
+	Flag  uint8
+}
+
+// PackSendAsset returns the input of a sendAsset call
+func PackSendAsset(asset common.Hash, to common.Address, value *big.Int, start uint64, end uint64, flag uint8) ([]byte, error) {
+	data, err := fsnContractABI.Methods["sendAsset"].Inputs.Pack(asset, to, value, start, end, flag)
+	if err != nil {
+		return nil, err
+	}
+	return append(common.LeftPadBytes([]byte{FuncSendAsset}, 32), data...), nil
+}
+
+// UnpackSendAsset decodes the result of a sendAsset call
+func UnpackSendAsset(ret []byte) (bool, error) {
+	var out bool
+	err := fsnContractABI.Unpack(&out, "sendAsset", ret)
+	return out, err
+}
+
+// PackBatchSendAsset returns the input of a batchSendAsset call
+func PackBatchSendAsset(items []SendAssetItem) ([]byte, error) {
+	data, err := fsnContractABI.Methods["batchSendAsset"].Inputs.Pack(items)
+	if err != nil {
+		return nil, err
+	}
+	return append(common.LeftPadBytes([]byte{FuncBatchSendAsset}, 32), data...), nil
+}
+
+// UnpackBatchSendAsset decodes the result of a batchSendAsset call
+func UnpackBatchSendAsset(ret []byte) (*big.Int, error) {
+	var out *big.Int
+	err := fsnContractABI.Unpack(&out, "batchSendAsset", ret)
+	return out, err
+}
+
+// PackGetReceivedAsset returns the input of a getReceivedAsset call
+func PackGetReceivedAsset() ([]byte, error) {
+	data, err := fsnContractABI.Methods["getReceivedAsset"].Inputs.Pack()
+	if err != nil {
+		return nil, err
+	}
+	return append(common.LeftPadBytes([]byte{FuncGetReceivedAsset}, 32), data...), nil
+}
+
+// GetReceivedAssetResult is the result of a getReceivedAsset call
+type GetReceivedAssetResult struct {
+	Asset common.Hash
+	From  common.Address
+	Value *big.Int
+	Start uint64
+	End   uint64
+	Flag  uint8
+}
+
+// UnpackGetReceivedAsset decodes the result of a getReceivedAsset call
+func UnpackGetReceivedAsset(ret []byte) (*GetReceivedAssetResult, error) {
+	out := new(GetReceivedAssetResult)
+	err := fsnContractABI.Unpack(out, "getReceivedAsset", ret)
+	return out, err
+}
+
+// Errors the FSN contract reverts with
+var (
+	ErrUnknownFunc          = errors.New("UnknownFunc()")
+	ErrNotEnoughBalance     = errors.New("NotEnoughBalance()")
+	ErrWrongTimeRange       = errors.New("WrongTimeRange()")
+	ErrValueOverflow        = errors.New("ValueOverflow()")
+	ErrWrongLenOfInput      = errors.New("WrongLenOfInput()")
+	ErrInvalidSendAssetFlag = errors.New("InvalidSendAssetFlag()")
+	ErrMustCallByContract   = errors.New("MustCallByContract()")
+	ErrReentrantCall        = errors.New("ReentrantCall()")
+	ErrNotReceivingAsset    = errors.New("NotReceivingAsset()")
+)
+
+var fsnContractErrors = map[string]error{
+	"0xfcb083f7": ErrUnknownFunc,
+	"0xad3a8b9e": ErrNotEnoughBalance,
+	"0x8bee41f4": ErrWrongTimeRange,
+	"0xf20577e5": ErrValueOverflow,
+	"0x9ca18845": ErrWrongLenOfInput,
+	"0xfcf630e5": ErrInvalidSendAssetFlag,
+	"0x8266140a": ErrMustCallByContract,
+	"0x37ed32e8": ErrReentrantCall,
+	"0x83c1f7f4": ErrNotReceivingAsset,
+}
+
+// errorStringSelector is the selector of the Solidity Error(string) revert
+const errorStringSelector = "0x08c379a0"
+
+// UnpackError returns the error of the revert data of an FSN contract call
+func UnpackError(ret []byte) error {
+	if len(ret) < 4 {
+		return errors.New("FSN contract call reverted")
+	}
+	selector := hexutil.Encode(ret[:4])
+	if err, ok := fsnContractErrors[selector]; ok {
+		return err
+	}
+	if selector == errorStringSelector {
+		stringType, _ := abi.NewType("string", "", nil)
+		var reason string
+		if err := (abi.Arguments{{Type: stringType}}).Unpack(&reason, ret[4:]); err == nil {
+			return errors.New(reason)
+		}
+	}
+	return fmt.Errorf("FSN contract call reverted with %x", ret)
+}
+
+// SendAssetTimeLockTopic is the first topic of the SendAssetTimeLock logs
+var SendAssetTimeLockTopic = common.HexToHash("0x181f2c4bc2b3e11b05ea1a6e3d03657949d81b123f6b21d713b5b52ae62dce1b")
+
+// SendAssetTimeLock is a SendAssetTimeLock log of the FSN contract
+type SendAssetTimeLock struct {
+	From  common.Address
+	To    common.Address
+	Asset common.Hash
+	Value *big.Int
+	Start uint64
+	End   uint64
+	Raw   *types.Log
+}
+
+// ParseSendAssetTimeLock decodes a SendAssetTimeLock log of the FSN contract
+func ParseSendAssetTimeLock(log *types.Log) (*SendAssetTimeLock, error) {
+	if log.Address != FSNContractAddress || len(log.Topics) == 0 || log.Topics[0] != SendAssetTimeLockTopic {
+		return nil, errors.New("not a SendAssetTimeLock log")
+	}
+	event := &SendAssetTimeLock{Raw: log}
+	if err := fsnContractABI.Unpack(event, "SendAssetTimeLock", log.Data); err != nil {
+		return nil, err
+	}
+	topics := log.Topics[1:]
+	if len(topics) == 0 {
+		return nil, errors.New("missing from topic")
+	}
+	event.From = common.BytesToAddress(topics[0].Bytes())
+	topics = topics[1:]
+	if len(topics) == 0 {
+		return nil, errors.New("missing to topic")
+	}
+	event.To = common.BytesToAddress(topics[0].Bytes())
+	topics = topics[1:]
+	if len(topics) == 0 {
+		return nil, errors.New("missing asset topic")
+	}
+	event.Asset = topics[0]
+	topics = topics[1:]
+	return event, nil
+}
+
+// PackReceiveAsset returns the input of a receiveAsset call of a contract.
+// Called with the sent amount as value when an account sends an asset to the contract.
+func PackReceiveAsset(assetID common.Hash, startTime uint64, endTime uint64, flag uint8, extraData []*big.Int) ([]byte, error) {
+	data, err := fsnContractABI.Methods["receiveAsset"].Inputs.Pack(assetID, startTime, endTime, flag, extraData)
+	if err != nil {
+		return nil, err
+	}
+	return append(hexutil.MustDecode("0xda28283a"), data...), nil
+}
+
+// FSNCallAddress is the address FSN calls are sent to and logged from
+var FSNCallAddress = common.HexToAddress("0xFFfFfFffFFfffFFfFFfFFFFFffFFFffffFfFFFfF")
+
+// FSN call functions, the last byte of the topic of their logs
+const (
+	FSNCallGenNotationFunc         = 0
+	FSNCallGenAssetFunc            = 1
+	FSNCallSendAssetFunc           = 2
+	FSNCallTimeLockFunc            = 3
+	FSNCallBuyTicketFunc           = 4
+	FSNCallOldAssetValueChangeFunc = 5
+	FSNCallMakeSwapFunc            = 6
+	FSNCallRecallSwapFunc          = 7
+	FSNCallTakeSwapFunc            = 8
+	FSNCallEmptyFunc               = 9
+	FSNCallMakeSwapFuncExt         = 10
+	FSNCallTakeSwapFuncExt         = 11
+	FSNCallAssetValueChangeFunc    = 12
+	FSNCallMakeMultiSwapFunc       = 13
+	FSNCallRecallMultiSwapFunc     = 14
+	FSNCallTakeMultiSwapFunc       = 15
+	FSNCallReportIllegalFunc       = 16
+	FSNCallTypedCallFunc           = 17
+	FSNCallStakingKeyFunc          = 18
+	FSNCallStakingBuyTicketFunc    = 19
+	FSNCallGenRestrictedAssetFunc  = 20
+	FSNCallAssetTransferListFunc   = 21
+	FSNCallSetFsnCallFeeFunc       = 22
+	FSNCallCreateProposalFunc      = 23
+	FSNCallVoteProposalFunc        = 24
+	FSNCallRevokeTicketFunc        = 25
+	FSNCallGenMultiOwnerAssetFunc  = 26
+	FSNCallEscrowAssetFunc         = 27
+	FSNCallClaimEscrowFunc         = 28
+	FSNCallCreateStreamFunc        = 29
+	FSNCallWithdrawStreamFunc      = 30
+	FSNCallCreateConditionFunc     = 31
+	FSNCallResolveConditionFunc    = 32
+	FSNCallConditionalTransferFunc = 33
+	FSNCallSettleConditionalFunc   = 34
+	FSNCallAttestDepositFunc       = 35
+	FSNCallBridgeWithdrawFunc      = 36
+)
+
+var fsnCallNames = map[uint8]string{
+	FSNCallGenNotationFunc:         "GenNotationFunc",
+	FSNCallGenAssetFunc:            "GenAssetFunc",
+	FSNCallSendAssetFunc:           "SendAssetFunc",
+	FSNCallTimeLockFunc:            "TimeLockFunc",
+	FSNCallBuyTicketFunc:           "BuyTicketFunc",
+	FSNCallOldAssetValueChangeFunc: "OldAssetValueChangeFunc",
+	FSNCallMakeSwapFunc:            "MakeSwapFunc",
+	FSNCallRecallSwapFunc:          "RecallSwapFunc",
+	FSNCallTakeSwapFunc:            "TakeSwapFunc",
+	FSNCallEmptyFunc:               "EmptyFunc",
+	FSNCallMakeSwapFuncExt:         "MakeSwapFuncExt",
+	FSNCallTakeSwapFuncExt:         "TakeSwapFuncExt",
+	FSNCallAssetValueChangeFunc:    "AssetValueChangeFunc",
+	FSNCallMakeMultiSwapFunc:       "MakeMultiSwapFunc",
+	FSNCallRecallMultiSwapFunc:     "RecallMultiSwapFunc",
+	FSNCallTakeMultiSwapFunc:       "TakeMultiSwapFunc",
+	FSNCallReportIllegalFunc:       "ReportIllegalFunc",
+	FSNCallTypedCallFunc:           "TypedCallFunc",
+	FSNCallStakingKeyFunc:          "StakingKeyFunc",
+	FSNCallStakingBuyTicketFunc:    "StakingBuyTicketFunc",
+	FSNCallGenRestrictedAssetFunc:  "GenRestrictedAssetFunc",
+	FSNCallAssetTransferListFunc:   "AssetTransferListFunc",
+	FSNCallSetFsnCallFeeFunc:       "SetFsnCallFeeFunc",
+	FSNCallCreateProposalFunc:      "CreateProposalFunc",
+	FSNCallVoteProposalFunc:        "VoteProposalFunc",
+	FSNCallRevokeTicketFunc:        "RevokeTicketFunc",
+	FSNCallGenMultiOwnerAssetFunc:  "GenMultiOwnerAssetFunc",
+	FSNCallEscrowAssetFunc:         "EscrowAssetFunc",
+	FSNCallClaimEscrowFunc:         "ClaimEscrowFunc",
+	FSNCallCreateStreamFunc:        "CreateStreamFunc",
+	FSNCallWithdrawStreamFunc:      "WithdrawStreamFunc",
+	FSNCallCreateConditionFunc:     "CreateConditionFunc",
+	FSNCallResolveConditionFunc:    "ResolveConditionFunc",
+	FSNCallConditionalTransferFunc: "ConditionalTransferFunc",
+	FSNCallSettleConditionalFunc:   "SettleConditionalFunc",
+	FSNCallAttestDepositFunc:       "AttestDepositFunc",
+	FSNCallBridgeWithdrawFunc:      "BridgeWithdrawFunc",
+}
+
+// FSNCallLog is the log of an FSN call, its data is a JSON object of the call
+// parameters and results
+type FSNCallLog struct {
+	Func uint8
+	Name string
+	Data map[string]interface{}
+	Raw  *types.Log
+}
+
+// ParseFSNCallLog decodes the log of an FSN call
+func ParseFSNCallLog(log *types.Log) (*FSNCallLog, error) {
+	if log.Address != FSNCallAddress || len(log.Topics) == 0 {
+		return nil, errors.New("not an FSN call log")
+	}
+	fn := log.Topics[0][common.HashLength-1]
+	name, ok := fsnCallNames[fn]
+	if !ok || log.Topics[0] != common.BytesToHash([]byte{fn}) {
+		return nil, fmt.Errorf("unknown FSN call topic %v", log.Topics[0].Hex())
+	}
+	event := &FSNCallLog{Func: fn, Name: name, Raw: log}
+	if err := json.Unmarshal(log.Data, &event.Data); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
@@ -119,6 +119,7 @@ type CacheConfig struct {
 	TrieDirtyLimit      int           // Memory limit (MB) at which to start flushing dirty trie nodes to disk
 	TrieDirtyDisabled   bool          // Whether to disable trie write caching and GC altogether (archive node)
 	TrieTimeLimit       time.Duration // Time limit after which to flush the current in-memory trie to disk
+	TicketCache         int           // Number of ticket sets kept in the ticket cache (0 = state.DefaultTicketCacheSize)
 }
 
 // BlockChain represents the canonical chain given a database with a genesis
@@ -202,13 +203,17 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	txLookupCache, _ := lru.New(txLookupCacheLimit)
 	futureBlocks, _ := lru.New(maxFutureBlocks)
 	badBlocks, _ := lru.New(badBlockLimit)
+	ticketCache := cacheConfig.TicketCache
+	if ticketCache <= 0 {
+		ticketCache = state.DefaultTicketCacheSize
+	}
 
 	bc := &BlockChain{
 		chainConfig:    chainConfig,
 		cacheConfig:    cacheConfig,
 		db:             db,
 		triegc:         prque.New(nil),
-		stateCache:     state.NewDatabaseWithCache(db, cacheConfig.TrieCleanLimit),
+		stateCache:     state.NewDatabaseWithTicketCache(db, cacheConfig.TrieCleanLimit, state.NewTicketCache(ticketCache)),
 		quit:           make(chan struct{}),
 		shouldPreserve: shouldPreserve,
 		bodyCache:      bodyCache,
@@ -487,6 +492,8 @@ func (bc *BlockChain) SetHead(head uint64) error {
 			// removed in the hc.SetHead function.
 			rawdb.DeleteBody(db, hash, num)
 			rawdb.DeleteReceipts(db, hash, num)
+			rawdb.DeleteHardForkReceipt(db, hash, num)
+			rawdb.DeleteTicketRetreatReceipt(db, hash, num)
 		}
 		// Todo(rjl493456442) txlookup, bloombits, etc
 	}
@@ -1294,15 +1301,16 @@ func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain [
 	return 0, nil
 }
 
+// CheckAndReportMultipleMining hands the block and the canonical block of the
+// same height to the double mining watcher
 func (bc *BlockChain) CheckAndReportMultipleMining(block *types.Block) {
-	savedBlock := bc.GetBlockByNumber(block.NumberU64())
-	if savedBlock != nil &&
-		savedBlock.Hash() != block.Hash() &&
-		savedBlock.ParentHash() == block.ParentHash() &&
-		savedBlock.Coinbase() == block.Coinbase() {
-		log.Info("multiple block mined", "number", block.NumberU64(), "order", block.Nonce(), "miner", block.Coinbase(), "parentHash", block.ParentHash().String(), "hash1", savedBlock.Hash().String(), "hash2", block.Hash().String())
-		datong.ReportIllegal(savedBlock.Header(), block.Header())
+	if !common.AutoReportIllegal {
+		return
+	}
+	if savedBlock := bc.GetBlockByNumber(block.NumberU64()); savedBlock != nil {
+		datong.WatchHeader(savedBlock.Header())
 	}
+	datong.WatchHeader(block.Header())
 }
 
 var lastWrite uint64
@@ -1372,6 +1380,12 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	rawdb.WriteTd(blockBatch, block.Hash(), block.NumberU64(), externTd)
 	rawdb.WriteBlock(blockBatch, block)
 	rawdb.WriteReceipts(blockBatch, block.Hash(), block.NumberU64(), receipts)
+	if logs := state.GetLogs(common.HardForkTxHash); len(logs) > 0 {
+		rawdb.WriteHardForkReceipt(blockBatch, block.Hash(), block.NumberU64(), logs)
+	}
+	if logs := state.GetLogs(common.TicketRetreatTxHash); len(logs) > 0 {
+		rawdb.WriteTicketRetreatReceipt(blockBatch, block.Hash(), block.NumberU64(), logs)
+	}
 	rawdb.WritePreimages(blockBatch, state.Preimages())
 	if err := blockBatch.Write(); err != nil {
 		log.Crit("Failed to write block into disk", "err", err)
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/FusionFoundation/go-fusion/common"
 	"github.com/FusionFoundation/go-fusion/consensus/ethash"
 	"github.com/FusionFoundation/go-fusion/core/rawdb"
 	"github.com/FusionFoundation/go-fusion/core/types"
@@ -89,9 +90,9 @@ func ExampleGenerateChain() {
 
 	state, _ := blockchain.State()
 	fmt.Printf("last block: #%d\n", blockchain.CurrentBlock().Number())
-	fmt.Println("balance of addr1:", state.GetBalance(addr1))
-	fmt.Println("balance of addr2:", state.GetBalance(addr2))
-	fmt.Println("balance of addr3:", state.GetBalance(addr3))
+	fmt.Println("balance of addr1:", state.GetBalance(common.SystemAssetID, addr1))
+	fmt.Println("balance of addr2:", state.GetBalance(common.SystemAssetID, addr2))
+	fmt.Println("balance of addr3:", state.GetBalance(common.SystemAssetID, addr3))
 	// Output:
 	// last block: #5
 	// balance of addr1: 989000
@@ -35,4 +35,8 @@ var (
 
 	// ErrNoGenesis is returned when there is no Genesis Block.
 	ErrNoGenesis = errors.New("genesis not found in chain")
+
+	// ErrSponsoredTx is returned if a sponsored transaction is not an FSN call
+	// or is sent before sponsored transactions are enabled.
+	ErrSponsoredTx = errors.New("sponsored transaction is not an enabled FSN call")
 )
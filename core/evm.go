@@ -154,6 +154,34 @@ func CanTransferTimeLock(db vm.StateDB, addr common.Address, p *common.TransferT
 	return true
 }
 
+// subSmartTransfer spends value from start to end from the time lock balance
+// of the sender, or else the spendable part of it topped up from the asset
+// balance. It returns false without changes if the balances are not enough.
+func subSmartTransfer(db vm.StateDB, sender common.Address, assetID common.Hash, value *big.Int, start, end uint64, blockNumber *big.Int, timestamp uint64) bool {
+	timelock := common.GetTimeLock(value, start, end)
+	timeLockBalance := db.GetTimeLockBalance(assetID, sender)
+	if timeLockBalance.Cmp(timelock) >= 0 {
+		db.SubTimeLockBalance(sender, assetID, timelock, blockNumber, timestamp)
+		return true
+	}
+	timeLockValue := timeLockBalance.GetSpendableValue(start, end)
+	assetBalance := db.GetBalance(assetID, sender)
+	if new(big.Int).Add(timeLockValue, assetBalance).Cmp(value) < 0 {
+		return false
+	}
+	if timeLockValue.Sign() > 0 {
+		subTimeLock := common.GetTimeLock(timeLockValue, start, end)
+		db.SubTimeLockBalance(sender, assetID, subTimeLock, blockNumber, timestamp)
+	}
+	useAssetAmount := new(big.Int).Sub(value, timeLockValue)
+	db.SubBalance(sender, assetID, useAssetAmount)
+	surplus := common.GetSurplusTimeLock(useAssetAmount, start, end, timestamp)
+	if !surplus.IsEmpty() {
+		db.AddTimeLockBalance(sender, assetID, surplus, blockNumber, timestamp)
+	}
+	return true
+}
+
 func TransferTimeLock(db vm.StateDB, sender, recipient common.Address, p *common.TransferTimeLockParam) {
 	if p.Value.Sign() <= 0 {
 		return
@@ -173,28 +201,11 @@ func TransferTimeLock(db vm.StateDB, sender, recipient common.Address, p *common
 			db.AddTimeLockBalance(sender, p.AssetID, surplus, p.BlockNumber, p.Timestamp)
 		}
 	} else {
-		timeLockBalance := db.GetTimeLockBalance(p.AssetID, sender)
-		if timeLockBalance.Cmp(timelock) < 0 {
-			if p.Flag.IsUseTimeLock() {
-				return
-			}
-			timeLockValue := timeLockBalance.GetSpendableValue(p.StartTime, p.EndTime)
-			assetBalance := db.GetBalance(p.AssetID, sender)
-			if new(big.Int).Add(timeLockValue, assetBalance).Cmp(p.Value) < 0 {
-				return
-			}
-			if timeLockValue.Sign() > 0 {
-				subTimeLock := common.GetTimeLock(timeLockValue, p.StartTime, p.EndTime)
-				db.SubTimeLockBalance(sender, p.AssetID, subTimeLock, p.BlockNumber, p.Timestamp)
-			}
-			useAssetAmount := new(big.Int).Sub(p.Value, timeLockValue)
-			db.SubBalance(sender, p.AssetID, useAssetAmount)
-			surplus := common.GetSurplusTimeLock(useAssetAmount, p.StartTime, p.EndTime, p.Timestamp)
-			if !surplus.IsEmpty() {
-				db.AddTimeLockBalance(sender, p.AssetID, surplus, p.BlockNumber, p.Timestamp)
-			}
-		} else {
-			db.SubTimeLockBalance(sender, p.AssetID, timelock, p.BlockNumber, p.Timestamp)
+		if p.Flag.IsUseTimeLock() && db.GetTimeLockBalance(p.AssetID, sender).Cmp(timelock) < 0 {
+			return
+		}
+		if !subSmartTransfer(db, sender, p.AssetID, p.Value, p.StartTime, p.EndTime, p.BlockNumber, p.Timestamp) {
+			return
 		}
 	}
 
@@ -0,0 +1,90 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package forkid
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/params"
+)
+
+// ErrFsnForkMismatch is returned by CheckFsnForks if the FSN hard forks of a
+// remote node differ from the local ones in a fork already reached.
+var ErrFsnForkMismatch = errors.New("fsn hard fork mismatch")
+
+// FsnForks returns the heights of the FSN hard forks, which are not part of
+// the EIP-2124 fork ID: the numbered hard forks of the network rules and the
+// asset recoveries of the chain config, sorted and deduplicated.
+func FsnForks(config *params.ChainConfig) []uint64 {
+	forks := common.ForkHeights()
+	if config != nil && config.DaTong != nil {
+		for _, r := range config.DaTong.Recoveries {
+			forks = append(forks, r.Height)
+		}
+	}
+	sort.Slice(forks, func(i, j int) bool { return forks[i] < forks[j] })
+	for i := 1; i < len(forks); i++ {
+		if forks[i] == forks[i-1] {
+			forks = append(forks[:i], forks[i+1:]...)
+			i--
+		}
+	}
+	return forks
+}
+
+// CheckFsnForks validates the FSN hard forks of a remote node against the
+// local ones. The fork sets may only differ in forks which neither chain
+// has reached yet, as nodes might be updated to match before they trigger.
+func CheckFsnForks(local, remote []uint64, localHead, remoteHead uint64) error {
+	head := localHead
+	if remoteHead > head {
+		head = remoteHead
+	}
+	n := len(local)
+	if len(remote) > n {
+		n = len(remote)
+	}
+	for i := 0; i < n; i++ {
+		l, r := uint64(math.MaxUint64), uint64(math.MaxUint64)
+		if i < len(local) {
+			l = local[i]
+		}
+		if i < len(remote) {
+			r = remote[i]
+		}
+		if l == r {
+			continue
+		}
+		if l <= head || r <= head {
+			return fmt.Errorf("%v: fork #%d at %s, remote at %s", ErrFsnForkMismatch, i+1, forkHeight(l), forkHeight(r))
+		}
+		// both forks are in the future
+		break
+	}
+	return nil
+}
+
+func forkHeight(n uint64) string {
+	if n == math.MaxUint64 {
+		return "none"
+	}
+	return fmt.Sprint(n)
+}
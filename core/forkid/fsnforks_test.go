@@ -0,0 +1,59 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package forkid
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/params"
+)
+
+func TestFsnForks(t *testing.T) {
+	// the vote1 recovery is scheduled between fork 1 and 2
+	want := []uint64{739500, 786000, 1818300}
+	if have := FsnForks(params.MainnetChainConfig); !reflect.DeepEqual(have, want) {
+		t.Fatalf("mainnet fsn forks mismatch: have %v, want %v", have, want)
+	}
+}
+
+func TestCheckFsnForks(t *testing.T) {
+	tests := []struct {
+		local, remote         []uint64
+		localHead, remoteHead uint64
+		err                   bool
+	}{
+		// same schedule, any heads
+		{[]uint64{100, 200}, []uint64{100, 200}, 50, 300, false},
+		// remote knows a future fork which no chain has reached
+		{[]uint64{100, 200}, []uint64{100, 200, 500}, 300, 300, false},
+		// remote is missing a fork the local chain has passed
+		{[]uint64{100, 200}, []uint64{100}, 250, 150, true},
+		// remote head passed a fork scheduled differently locally
+		{[]uint64{100, 300}, []uint64{100, 200}, 150, 250, true},
+		// forks scheduled differently, neither reached yet
+		{[]uint64{100, 300}, []uint64{100, 200}, 150, 150, false},
+		// different first fork, already passed by the local chain
+		{[]uint64{100}, []uint64{120}, 110, 0, true},
+	}
+	for i, tt := range tests {
+		err := CheckFsnForks(tt.local, tt.remote, tt.localHead, tt.remoteHead)
+		if (err != nil) != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want error %v", i, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/math"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/core/types"
+)
+
+// FsnAccount is the FSN state of one account in a portable form, exported
+// from a block to be imported in the genesis of a devnet as a fixture
+type FsnAccount struct {
+	Address     common.Address `json:"address"`
+	BlockNumber uint64         `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	Nonce       uint64         `json:"nonce"`
+	// Notation is the notation at the exported block, the imported account
+	// gets the next free notation instead
+	Notation  uint64                                   `json:"notation,omitempty"`
+	Balances  map[common.Hash]*math.HexOrDecimal256    `json:"balances"`
+	TimeLocks map[common.Hash][]FsnAccountTimeLockItem `json:"timeLocks"`
+	Assets    []FsnAccountAsset                        `json:"assets"`  // the assets the balances are of, FSN excluded
+	Tickets   []FsnAccountTicket                       `json:"tickets"` // sorted by ID
+	Swaps     []common.Swap                            `json:"swaps"`   // sorted by ID
+}
+
+// FsnAccountTimeLockItem is a time lock segment of an exported account
+type FsnAccountTimeLockItem struct {
+	StartTime math.HexOrDecimal64   `json:"startTime"`
+	EndTime   math.HexOrDecimal64   `json:"endTime"`
+	Value     *math.HexOrDecimal256 `json:"value"`
+}
+
+// FsnAccountAsset is the definition of an asset held by an exported account
+type FsnAccountAsset struct {
+	ID          common.Hash           `json:"id"`
+	Owner       common.Address        `json:"owner"`
+	Name        string                `json:"name"`
+	Symbol      string                `json:"symbol"`
+	Decimals    uint8                 `json:"decimals"`
+	Total       *math.HexOrDecimal256 `json:"total"`
+	CanChange   bool                  `json:"canChange"`
+	Description string                `json:"description"`
+}
+
+// FsnAccountTicket is a ticket of an exported account
+type FsnAccountTicket struct {
+	ID         common.Hash         `json:"id"`
+	Height     math.HexOrDecimal64 `json:"height"`
+	StartTime  math.HexOrDecimal64 `json:"startTime"`
+	ExpireTime math.HexOrDecimal64 `json:"expireTime"`
+	Weight     math.HexOrDecimal64 `json:"weight"`
+}
+
+// ExportFsnAccount returns the FSN state of addr at the given block. The open
+// swaps can not be listed from the state, the IDs of the candidate swaps are
+// given by the caller and the ones not open and owned by addr are skipped.
+func ExportFsnAccount(statedb *state.StateDB, header *types.Header, addr common.Address, swapIDs []common.Hash) (*FsnAccount, error) {
+	account := &FsnAccount{
+		Address:     addr,
+		BlockNumber: header.Number.Uint64(),
+		BlockHash:   header.Hash(),
+		Nonce:       statedb.GetNonce(addr),
+		Notation:    statedb.GetNotation(addr),
+		Balances:    make(map[common.Hash]*math.HexOrDecimal256),
+		TimeLocks:   make(map[common.Hash][]FsnAccountTimeLockItem),
+		Assets:      []FsnAccountAsset{},
+		Tickets:     []FsnAccountTicket{},
+		Swaps:       []common.Swap{},
+	}
+	held := make(map[common.Hash]bool)
+	var assetIDs []common.Hash
+	hold := func(assetID common.Hash) {
+		if assetID != common.SystemAssetID && !held[assetID] {
+			held[assetID] = true
+			assetIDs = append(assetIDs, assetID)
+		}
+	}
+	for _, balance := range statedb.GetSortedBalances(addr) {
+		account.Balances[balance.AssetID] = (*math.HexOrDecimal256)(balance.Balance)
+		hold(balance.AssetID)
+	}
+	for _, timelock := range statedb.GetSortedTimeLockBalances(addr) {
+		items := make([]FsnAccountTimeLockItem, 0, len(timelock.TimeLock.Items))
+		for _, item := range timelock.TimeLock.Items {
+			items = append(items, FsnAccountTimeLockItem{
+				StartTime: math.HexOrDecimal64(item.StartTime),
+				EndTime:   math.HexOrDecimal64(item.EndTime),
+				Value:     (*math.HexOrDecimal256)(new(big.Int).Set(item.Value)),
+			})
+		}
+		account.TimeLocks[timelock.AssetID] = items
+		hold(timelock.AssetID)
+	}
+
+	swapIDs = append([]common.Hash{}, swapIDs...)
+	sort.Slice(swapIDs, func(i, j int) bool { return bytes.Compare(swapIDs[i][:], swapIDs[j][:]) < 0 })
+	for i, id := range swapIDs {
+		if i > 0 && id == swapIDs[i-1] {
+			continue
+		}
+		if swap, err := statedb.GetSwap(id); err == nil && swap.Owner == addr {
+			account.Swaps = append(account.Swaps, swap)
+			hold(swap.FromAssetID)
+		}
+	}
+	for _, assetID := range assetIDs {
+		asset, err := statedb.GetAsset(assetID)
+		if err != nil {
+			return nil, fmt.Errorf("asset %x: %v", assetID, err)
+		}
+		account.Assets = append(account.Assets, FsnAccountAsset{
+			ID:          asset.ID,
+			Owner:       asset.Owner,
+			Name:        asset.Name,
+			Symbol:      asset.Symbol,
+			Decimals:    asset.Decimals,
+			Total:       (*math.HexOrDecimal256)(asset.Total),
+			CanChange:   asset.CanChange,
+			Description: asset.Description,
+		})
+	}
+
+	tickets, err := statedb.AllTickets()
+	if err != nil {
+		return nil, err
+	}
+	for _, ticket := range tickets.ToTicketSlice() {
+		if ticket.Owner == addr {
+			account.Tickets = append(account.Tickets, FsnAccountTicket{
+				ID:         ticket.ID,
+				Height:     math.HexOrDecimal64(ticket.Height),
+				StartTime:  math.HexOrDecimal64(ticket.StartTime),
+				ExpireTime: math.HexOrDecimal64(ticket.ExpireTime),
+				Weight:     math.HexOrDecimal64(ticket.Weight()),
+			})
+		}
+	}
+	sort.Slice(account.Tickets, func(i, j int) bool {
+		return bytes.Compare(account.Tickets[i].ID[:], account.Tickets[j].ID[:]) < 0
+	})
+	return account, statedb.Error()
+}
+
+// Apply writes the exported account to statedb, replacing its balances and
+// nonce. The assets already defined in statedb are kept as they are.
+func (account *FsnAccount) Apply(statedb *state.StateDB, blockNumber *big.Int) error {
+	addr := account.Address
+	for _, a := range account.Assets {
+		if _, err := statedb.GetAsset(a.ID); err == nil {
+			continue
+		}
+		asset := common.Asset{
+			ID:          a.ID,
+			Owner:       a.Owner,
+			Name:        a.Name,
+			Symbol:      a.Symbol,
+			Decimals:    a.Decimals,
+			Total:       new(big.Int),
+			CanChange:   a.CanChange,
+			Description: a.Description,
+		}
+		if a.Total != nil {
+			asset.Total.Set((*big.Int)(a.Total))
+		}
+		if err := statedb.GenAsset(asset); err != nil {
+			return err
+		}
+	}
+	statedb.SetNonce(addr, account.Nonce)
+	// the account keeps its assets in the order they are touched, which is
+	// part of the state root
+	for _, assetID := range sortedAssetIDs(account.Balances) {
+		balance := account.Balances[assetID]
+		if balance == nil {
+			return fmt.Errorf("balance of asset %x missing", assetID)
+		}
+		statedb.SetBalance(addr, assetID, new(big.Int).Set((*big.Int)(balance)))
+	}
+	for _, assetID := range sortedAssetIDs(account.TimeLocks) {
+		items := account.TimeLocks[assetID]
+		segments := make([]*common.TimeLockItem, 0, len(items))
+		for _, item := range items {
+			if item.Value == nil {
+				return fmt.Errorf("time lock value of asset %x missing", assetID)
+			}
+			segments = append(segments, &common.TimeLockItem{
+				StartTime: uint64(item.StartTime),
+				EndTime:   uint64(item.EndTime),
+				Value:     new(big.Int).Set((*big.Int)(item.Value)),
+			})
+		}
+		statedb.SetTimeLockBalance(addr, assetID, common.NewTimeLock(segments...))
+	}
+	if account.Notation != 0 && statedb.GetNotation(addr) == 0 {
+		if err := statedb.GenNotation(addr, blockNumber); err != nil {
+			return err
+		}
+	}
+	for _, t := range account.Tickets {
+		ticket := common.Ticket{
+			Owner: addr,
+			TicketBody: common.TicketBody{
+				ID:         t.ID,
+				Height:     uint64(t.Height),
+				StartTime:  uint64(t.StartTime),
+				ExpireTime: uint64(t.ExpireTime),
+			},
+		}
+		ticket.SetWeight(uint64(t.Weight))
+		if err := statedb.AddTicket(ticket); err != nil {
+			return err
+		}
+	}
+	for _, swap := range account.Swaps {
+		if swap.Owner != addr {
+			return fmt.Errorf("swap %x is not owned by %x", swap.ID, addr)
+		}
+		if err := statedb.AddSwap(swap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedAssetIDs returns the keys of a map by asset ID in ascending order
+func sortedAssetIDs(m interface{}) []common.Hash {
+	keys := reflect.ValueOf(m).MapKeys()
+	ids := make([]common.Hash, 0, len(keys))
+	for _, key := range keys {
+		ids = append(ids, key.Interface().(common.Hash))
+	}
+	sort.Slice(ids, func(i, j int) bool { return bytes.Compare(ids[i][:], ids[j][:]) < 0 })
+	return ids
+}
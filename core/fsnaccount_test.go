@@ -0,0 +1,118 @@
+package core
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/math"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/params"
+)
+
+func TestFsnAccountExportImport(t *testing.T) {
+	var (
+		addr   = common.HexToAddress("0x01")
+		other  = common.HexToAddress("0x02")
+		usd    = common.Asset{ID: common.HexToHash("0x11"), Owner: other, Name: "USD", Symbol: "USD", Decimals: 6, Total: big.NewInt(1000000)}
+		eur    = common.Asset{ID: common.HexToHash("0x12"), Owner: other, Name: "EUR", Symbol: "EUR", Decimals: 6, Total: big.NewInt(1000000)}
+		header = &types.Header{Number: big.NewInt(10), Time: 100}
+	)
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	statedb.GenAsset(usd)
+	statedb.GenAsset(eur)
+	statedb.SetNonce(addr, 5)
+	statedb.AddBalance(addr, usd.ID, big.NewInt(30))
+	statedb.AddBalance(addr, common.SystemAssetID, big.NewInt(40))
+	statedb.AddTimeLockBalance(addr, common.SystemAssetID, common.NewTimeLock(&common.TimeLockItem{StartTime: 200, EndTime: 300, Value: big.NewInt(7)}), header.Number, header.Time)
+	statedb.GenNotation(addr, header.Number)
+	statedb.AddTicket(common.Ticket{Owner: addr, TicketBody: common.TicketBody{ID: common.HexToHash("0x21"), Height: 1, StartTime: 100, ExpireTime: 1000}})
+	statedb.AddTicket(common.Ticket{Owner: other, TicketBody: common.TicketBody{ID: common.HexToHash("0x22"), Height: 1, StartTime: 100, ExpireTime: 1000}})
+	swap := common.Swap{
+		ID: common.HexToHash("0x31"), Owner: addr, FromAssetID: eur.ID, MinFromAmount: big.NewInt(1), ToAssetID: usd.ID,
+		MinToAmount: big.NewInt(2), SwapSize: big.NewInt(3), Time: big.NewInt(100), FromEndTime: common.TimeLockForever, ToEndTime: common.TimeLockForever,
+	}
+	statedb.AddSwap(swap)
+
+	exported, err := ExportFsnAccount(statedb, header, addr, []common.Hash{swap.ID, swap.ID, common.HexToHash("0x32")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exported.Tickets) != 1 || len(exported.Swaps) != 1 || len(exported.Assets) != 2 || exported.Notation == 0 {
+		t.Fatalf("got %d tickets, %d swaps, %d assets and notation %d, want 1, 1, 2 and a notation",
+			len(exported.Tickets), len(exported.Swaps), len(exported.Assets), exported.Notation)
+	}
+	blob, err := json.Marshal(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := new(FsnAccount)
+	if err := json.Unmarshal(blob, account); err != nil {
+		t.Fatal(err)
+	}
+
+	// Importing in a genesis gives the same account and a deterministic root
+	genesis := &Genesis{Config: params.DevnetChainConfig, FsnAccounts: []*FsnAccount{account}}
+	root := genesis.ToBlock(nil).Root()
+	if again := genesis.ToBlock(nil).Root(); again != root {
+		t.Fatalf("genesis root changed: %x != %x", again, root)
+	}
+	db := rawdb.NewMemoryDatabase()
+	block := genesis.ToBlock(db)
+	imported, _ := state.New(block.Root(), block.MixDigest(), state.NewDatabase(db))
+	reexported, err := ExportFsnAccount(imported, header, addr, []common.Hash{swap.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reexported.Notation = exported.Notation
+	if !reflect.DeepEqual(reexported, exported) {
+		t.Errorf("imported account differs\ngot  %+v\nwant %+v", reexported, exported)
+	}
+}
+
+func TestDeveloperFsnGenesisBlock(t *testing.T) {
+	faucet := common.HexToAddress("0x01")
+	db := rawdb.NewMemoryDatabase()
+	block := DeveloperFsnGenesisBlock(faucet).ToBlock(db)
+	statedb, err := state.New(block.Root(), block.MixDigest(), state.NewDatabase(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tickets, err := statedb.AllTickets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := tickets.NumberOfTicketsByAddress(faucet); n != 10 {
+		t.Errorf("got %d faucet tickets, want 10", n)
+	}
+	if statedb.GetBalance(common.SystemAssetID, faucet).Sign() <= 0 {
+		t.Error("faucet has no FSN")
+	}
+	if timelock := statedb.GetTimeLockBalance(common.SystemAssetID, faucet); timelock.IsEmpty() {
+		t.Error("faucet has no time locked FSN")
+	}
+	if _, err := statedb.GetAsset(common.SystemAssetID); err != nil {
+		t.Errorf("FSN asset missing: %v", err)
+	}
+}
+
+func TestGenesisInvalidFsnAccount(t *testing.T) {
+	account := &FsnAccount{
+		Address:  common.HexToAddress("0x01"),
+		Balances: map[common.Hash]*math.HexOrDecimal256{common.SystemAssetID: nil},
+	}
+	genesis := &Genesis{Config: params.DevnetChainConfig, FsnAccounts: []*FsnAccount{account}}
+	if _, err := genesis.Commit(rawdb.NewMemoryDatabase()); err == nil {
+		t.Fatal("genesis with an invalid FSN account committed")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("genesis block built with an invalid FSN account")
+		}
+	}()
+	genesis.ToBlock(nil)
+}
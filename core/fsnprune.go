@@ -0,0 +1,121 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/ethdb"
+	"github.com/FusionFoundation/go-fusion/log"
+)
+
+// MinFsnPruneKeepTickets is the lowest number of recent blocks whose ticket
+// blobs are kept, covering the states a restarted node may rewind to.
+const MinFsnPruneKeepTickets = 2 * TriesInMemory
+
+// FsnPrunePolicy is the retention policy of the FSN data stored under the
+// special key addresses.
+//
+// The tickets are rewritten as one compressed blob under TicketKeyAddress in
+// every block and stored as code keyed by its hash (the header MixDigest), so
+// every block leaves a blob behind which is never released by the trie
+// garbage collector. Only the blobs of the latest KeepTicketBlocks blocks are
+// kept, older states answer ticket queries with a missing code error.
+//
+// Assets (AssetKeyAddress) and notations (NotationKeyAddress) are kept
+// forever, they are referenced by balances and addresses at any height.
+// Swaps (SwapKeyAddress) are removed from the state by consensus once the
+// retention period after their removal has passed (IsSwapDeletionEnabled),
+// so they are left to the ordinary state pruning.
+type FsnPrunePolicy struct {
+	KeepTicketBlocks uint64 // Number of recent blocks whose ticket blobs are kept
+}
+
+// FsnPruneStats is the result of PruneFsnData.
+type FsnPruneStats struct {
+	Head         uint64 // Head block the retention was counted from
+	Blocks       uint64 // Number of canonical blocks inspected
+	TicketBlobs  uint64 // Number of deleted ticket blobs
+	TicketBytes  uint64 // Size of the deleted ticket blobs
+	MissingBlobs uint64 // Number of ticket blobs already missing
+}
+
+// PruneFsnData deletes the FSN data of the canonical chain in db which is no
+// longer retained according to policy. The database must not be in use by a
+// running node.
+func PruneFsnData(db ethdb.Database, policy FsnPrunePolicy) (*FsnPruneStats, error) {
+	if policy.KeepTicketBlocks < MinFsnPruneKeepTickets {
+		return nil, fmt.Errorf("too few ticket blocks kept: %d < %d", policy.KeepTicketBlocks, MinFsnPruneKeepTickets)
+	}
+	headHash := rawdb.ReadHeadHeaderHash(db)
+	if headHash == (common.Hash{}) {
+		return nil, errors.New("empty database")
+	}
+	number := rawdb.ReadHeaderNumber(db, headHash)
+	if number == nil {
+		return nil, fmt.Errorf("missing head header number: %x", headHash)
+	}
+	stats := &FsnPruneStats{Head: *number}
+	if stats.Head < policy.KeepTicketBlocks {
+		return stats, nil
+	}
+	limit := stats.Head - policy.KeepTicketBlocks
+
+	// The blobs are content addressed, never delete one still used by a
+	// retained block.
+	keep := make(map[common.Hash]struct{})
+	for n := limit + 1; n <= stats.Head; n++ {
+		header := rawdb.ReadHeader(db, rawdb.ReadCanonicalHash(db, n), n)
+		if header == nil {
+			return nil, fmt.Errorf("missing canonical header #%d", n)
+		}
+		keep[header.MixDigest] = struct{}{}
+	}
+
+	var (
+		batch  = db.NewBatch()
+		start  = time.Now()
+		logged = time.Now()
+	)
+	for n := uint64(1); n <= limit; n++ {
+		header := rawdb.ReadHeader(db, rawdb.ReadCanonicalHash(db, n), n)
+		if header == nil {
+			return nil, fmt.Errorf("missing canonical header #%d", n)
+		}
+		stats.Blocks++
+		if header.MixDigest == (common.Hash{}) {
+			continue
+		}
+		if _, ok := keep[header.MixDigest]; ok {
+			continue
+		}
+		blob, err := db.Get(header.MixDigest[:])
+		if err != nil || len(blob) == 0 {
+			stats.MissingBlobs++
+			continue
+		}
+		if err := batch.Delete(header.MixDigest[:]); err != nil {
+			return nil, err
+		}
+		stats.TicketBlobs++
+		stats.TicketBytes += uint64(len(blob))
+
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return nil, err
+			}
+			batch.Reset()
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Pruning FSN ticket blobs", "number", n, "limit", limit, "deleted", stats.TicketBlobs,
+				"size", common.StorageSize(stats.TicketBytes), "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
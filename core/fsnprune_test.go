@@ -0,0 +1,73 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/types"
+)
+
+func TestPruneFsnData(t *testing.T) {
+	const (
+		head = MinFsnPruneKeepTickets + 10
+		keep = MinFsnPruneKeepTickets
+	)
+	db := rawdb.NewMemoryDatabase()
+	if _, err := PruneFsnData(db, FsnPrunePolicy{KeepTicketBlocks: keep}); err == nil {
+		t.Fatal("empty database pruned")
+	}
+
+	// block n stores the ticket blob n, except for block 3 which reuses the
+	// blob of the head, block 4 whose blob is missing and block 5 without one
+	blobHash := func(n uint64) common.Hash {
+		return common.BigToHash(new(big.Int).SetUint64(n + 1000))
+	}
+	mixDigest := func(n uint64) common.Hash {
+		switch n {
+		case 3:
+			return blobHash(head)
+		case 5:
+			return common.Hash{}
+		}
+		return blobHash(n)
+	}
+	var headHash common.Hash
+	for n := uint64(0); n <= head; n++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(n), MixDigest: mixDigest(n), Difficulty: big.NewInt(1)}
+		rawdb.WriteHeader(db, header)
+		rawdb.WriteCanonicalHash(db, header.Hash(), n)
+		if n != 3 && n != 4 && n != 5 {
+			db.Put(header.MixDigest[:], []byte{byte(n), 1, 2})
+		}
+		headHash = header.Hash()
+	}
+	rawdb.WriteHeadHeaderHash(db, headHash)
+
+	if _, err := PruneFsnData(db, FsnPrunePolicy{KeepTicketBlocks: keep - 1}); err == nil {
+		t.Fatal("too few kept ticket blocks accepted")
+	}
+	stats, err := PruneFsnData(db, FsnPrunePolicy{KeepTicketBlocks: keep})
+	if err != nil {
+		t.Fatalf("failed to prune: %v", err)
+	}
+	limit := uint64(head - keep)
+	if stats.Head != head || stats.Blocks != limit || stats.MissingBlobs != 1 || stats.TicketBlobs != limit-3 || stats.TicketBytes != 3*(limit-3) {
+		t.Errorf("have stats %+v, want %d blocks with %d pruned blobs and 1 missing", stats, limit, limit-3)
+	}
+	for n := uint64(1); n <= head; n++ {
+		mix := mixDigest(n)
+		if mix == (common.Hash{}) {
+			continue
+		}
+		has, _ := db.Has(mix[:])
+		if want := n > limit || n == 3; n != 4 && has != want {
+			t.Errorf("ticket blob of block %d: have %v, want %v", n, has, want)
+		}
+	}
+	// pruning again finds nothing left to delete
+	if stats, err = PruneFsnData(db, FsnPrunePolicy{KeepTicketBlocks: keep}); err != nil || stats.TicketBlobs != 0 {
+		t.Errorf("second pruning deleted %d blobs, err %v", stats.TicketBlobs, err)
+	}
+}
@@ -2,7 +2,6 @@ package core
 
 import (
 	"encoding/json"
-	"fmt"
 	"math/big"
 	"reflect"
 	"time"
@@ -11,34 +10,51 @@ import (
 	"github.com/FusionFoundation/go-fusion/common/hexutil"
 	"github.com/FusionFoundation/go-fusion/consensus/datong"
 	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/core/vm"
 	"github.com/FusionFoundation/go-fusion/crypto"
 	"github.com/FusionFoundation/go-fusion/rlp"
-	"golang.org/x/crypto/sha3"
 )
 
-func rlpHash(x interface{}) (h common.Hash) {
-	hw := sha3.NewLegacyKeccak256()
-	rlp.Encode(hw, x)
-	hw.Sum(h[:0])
-	return h
-}
-
+// GetUniqueHashFromTransaction returns the unsalted unique hash of tx. IDs
+// created before IsSaltedUniqueHashEnabled are derived from it.
 func GetUniqueHashFromTransaction(tx *types.Transaction) common.Hash {
-	return rlpHash(types.NewTransaction(tx.Nonce(), *tx.To(), tx.Value(), tx.Gas(), tx.GasPrice(), tx.Data()))
+	return tx.UniqueHash()
 }
 
+// GetUniqueHashFromMessage returns the unsalted unique hash of m, see
+// GetUniqueHashFromTransaction
 func GetUniqueHashFromMessage(m Message) common.Hash {
-	return rlpHash(types.NewTransaction(m.Nonce(), *m.To(), m.Value(), m.Gas(), m.GasPrice(), m.Data()))
+	return types.NewTransaction(m.Nonce(), *m.To(), m.Value(), m.Gas(), m.GasPrice(), m.Data()).UniqueHash()
+}
+
+// GetUniqueHashAt returns the hash the IDs created by tx sent by from are
+// derived from when it is included in the block of the given number
+func GetUniqueHashAt(tx *types.Transaction, from common.Address, number *big.Int) common.Hash {
+	if common.IsSaltedUniqueHashEnabled(number) {
+		return tx.SaltedUniqueHash(from)
+	}
+	return tx.UniqueHash()
+}
+
+// GetUniqueHashFromMessageAt returns the hash the IDs created by m are
+// derived from in the block of the given number, see GetUniqueHashAt
+func GetUniqueHashFromMessageAt(m Message, number *big.Int) common.Hash {
+	tx := types.NewTransaction(m.Nonce(), *m.To(), m.Value(), m.Gas(), m.GasPrice(), m.Data())
+	return GetUniqueHashAt(tx, m.From(), number)
 }
 
 func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 	height := st.evm.Context.BlockNumber
 	timestamp := st.evm.Context.ParentTime.Uint64()
 
+	if !common.IsFsnCallFuncEnabled(param.Func, height) {
+		return common.ErrUnsupported
+	}
+
 	switch param.Func {
 	case common.GenNotationFunc:
-		if err := st.state.GenNotation(st.msg.From()); err != nil {
-			st.addLog(common.GenNotationFunc, param, common.NewKeyValue("Error", err.Error()))
+		if err := st.state.GenNotation(st.msg.From(), height); err != nil {
+			st.addErrorLog(common.GenNotationFunc, param, err)
 			return err
 		}
 		st.addLog(common.GenNotationFunc, param, common.NewKeyValue("notation", st.state.GetNotation(st.msg.From())))
@@ -47,32 +63,74 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 		genAssetParam := common.GenAssetParam{}
 		rlp.DecodeBytes(param.Data, &genAssetParam)
 		if err := genAssetParam.Check(height); err != nil {
-			st.addLog(common.GenAssetFunc, genAssetParam, common.NewKeyValue("Error", err.Error()))
+			st.addErrorLog(common.GenAssetFunc, genAssetParam, err)
+			return err
+		}
+		return st.genAsset(genAssetParam, common.AssetTransferUnrestricted, nil)
+	case common.GenRestrictedAssetFunc:
+		genRestrictedAssetParam := common.GenRestrictedAssetParam{}
+		rlp.DecodeBytes(param.Data, &genRestrictedAssetParam)
+		if err := genRestrictedAssetParam.Check(height); err != nil {
+			st.addErrorLog(common.GenRestrictedAssetFunc, genRestrictedAssetParam, err)
+			return err
+		}
+		return st.genAsset(genRestrictedAssetParam.Asset, genRestrictedAssetParam.Restriction, nil)
+	case common.GenMultiOwnerAssetFunc:
+		genMultiOwnerAssetParam := common.GenMultiOwnerAssetParam{}
+		rlp.DecodeBytes(param.Data, &genMultiOwnerAssetParam)
+		if err := genMultiOwnerAssetParam.Check(height); err != nil {
+			st.addErrorLog(common.GenMultiOwnerAssetFunc, genMultiOwnerAssetParam, err)
 			return err
 		}
-		asset := genAssetParam.ToAsset()
-		asset.ID = GetUniqueHashFromMessage(st.msg)
-		asset.Owner = st.msg.From()
-		if err := st.state.GenAsset(asset); err != nil {
-			st.addLog(common.GenAssetFunc, genAssetParam, common.NewKeyValue("Error", "unable to gen asset"))
+		return st.genAsset(genMultiOwnerAssetParam.Asset, common.AssetTransferUnrestricted, &genMultiOwnerAssetParam.Owners)
+	case common.AssetTransferListFunc:
+		assetTransferListParam := common.AssetTransferListParam{}
+		rlp.DecodeBytes(param.Data, &assetTransferListParam)
+		if err := assetTransferListParam.Check(height); err != nil {
+			st.addErrorLog(common.AssetTransferListFunc, assetTransferListParam, err)
 			return err
 		}
-		st.state.AddBalance(st.msg.From(), asset.ID, asset.Total)
-		st.addLog(common.GenAssetFunc, genAssetParam, common.NewKeyValue("AssetID", asset.ID))
+		asset, err := st.state.GetAsset(assetTransferListParam.AssetID)
+		if err != nil {
+			st.addErrorLog(common.AssetTransferListFunc, assetTransferListParam, common.ErrAssetNotFound)
+			return common.ErrAssetNotFound
+		}
+		if err := checkAssetOwner(st.state, &asset, param, st.msg.From(), height); err != nil {
+			st.addErrorLog(common.AssetTransferListFunc, assetTransferListParam, err)
+			return err
+		}
+		if st.state.GetAssetTransferRestriction(asset.ID) == common.AssetTransferUnrestricted {
+			err := common.NewFsnError(common.FsnErrInvalidParam, "asset transfers are not restricted")
+			st.addErrorLog(common.AssetTransferListFunc, assetTransferListParam, err)
+			return err
+		}
+		if approved, approvals := st.approveAssetOwnerCall(&asset, param); !approved {
+			st.addLog(common.AssetTransferListFunc, assetTransferListParam, common.NewKeyValue("AssetID", asset.ID), common.NewKeyValue("Approvals", approvals))
+			return nil
+		}
+		for _, addr := range assetTransferListParam.Addresses {
+			st.state.SetAssetTransferListed(asset.ID, addr, assetTransferListParam.Listed)
+		}
+		st.addLog(common.AssetTransferListFunc, assetTransferListParam, common.NewKeyValue("AssetID", asset.ID))
 		return nil
 	case common.SendAssetFunc:
 		sendAssetParam := common.SendAssetParam{}
 		rlp.DecodeBytes(param.Data, &sendAssetParam)
 		if err := sendAssetParam.Check(height); err != nil {
-			st.addLog(common.SendAssetFunc, sendAssetParam, common.NewKeyValue("Error", err.Error()))
+			st.addErrorLog(common.SendAssetFunc, sendAssetParam, err)
+			return err
+		}
+		if err := st.checkAssetTransfer(sendAssetParam.AssetID, st.msg.From(), sendAssetParam.To); err != nil {
+			st.addErrorLog(common.SendAssetFunc, sendAssetParam, err)
 			return err
 		}
 		if st.state.GetBalance(sendAssetParam.AssetID, st.msg.From()).Cmp(sendAssetParam.Value) < 0 {
-			st.addLog(common.SendAssetFunc, sendAssetParam, common.NewKeyValue("Error", "not enough asset"))
-			return fmt.Errorf("not enough asset")
+			st.addErrorLog(common.SendAssetFunc, sendAssetParam, common.ErrNotEnoughAsset)
+			return common.ErrNotEnoughAsset
 		}
 		st.state.SubBalance(st.msg.From(), sendAssetParam.AssetID, sendAssetParam.Value)
 		st.state.AddBalance(sendAssetParam.To, sendAssetParam.AssetID, sendAssetParam.Value)
+		st.addTransfer(common.TransferSendAsset, st.msg.From(), sendAssetParam.To, sendAssetParam.AssetID, sendAssetParam.Value, 0, 0, common.Hash{})
 		st.addLog(common.SendAssetFunc, sendAssetParam, common.NewKeyValue("AssetID", sendAssetParam.AssetID))
 		return nil
 	case common.TimeLockFunc:
@@ -82,13 +140,14 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 		// adjust param
 		if timeLockParam.Type == common.TimeLockToAsset {
 			if timeLockParam.StartTime > uint64(time.Now().Unix()) {
-				st.addLog(common.TimeLockFunc, timeLockParam, common.NewKeyValue("LockType", "TimeLockToAsset"), common.NewKeyValue("Error", "Start time must be less than now"))
-				return fmt.Errorf("Start time must be less than now")
+				err := common.NewFsnError(common.FsnErrInvalidParam, "Start time must be less than now")
+				st.addErrorLog(common.TimeLockFunc, timeLockParam, err, common.NewKeyValue("LockType", "TimeLockToAsset"))
+				return err
 			}
 			timeLockParam.EndTime = common.TimeLockForever
 		}
 		if err := timeLockParam.Check(height, timestamp); err != nil {
-			st.addLog(common.TimeLockFunc, timeLockParam, common.NewKeyValue("Error", err.Error()))
+			st.addErrorLog(common.TimeLockFunc, timeLockParam, err)
 			return err
 		}
 
@@ -105,15 +164,19 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 		})
 
 		if err := needValue.IsValid(); err != nil {
-			st.addLog(common.TimeLockFunc, timeLockParam, common.NewKeyValue("Error", err.Error()))
-			return fmt.Errorf(err.Error())
+			st.addErrorLog(common.TimeLockFunc, timeLockParam, err)
+			return err
+		}
+		if err := st.checkAssetTransfer(timeLockParam.AssetID, st.msg.From(), timeLockParam.To); err != nil {
+			st.addErrorLog(common.TimeLockFunc, timeLockParam, err)
+			return err
 		}
 
 		switch timeLockParam.Type {
 		case common.AssetToTimeLock:
 			if st.state.GetBalance(timeLockParam.AssetID, st.msg.From()).Cmp(timeLockParam.Value) < 0 {
-				st.addLog(common.TimeLockFunc, timeLockParam, common.NewKeyValue("LockType", "AssetToTimeLock"), common.NewKeyValue("Error", "not enough asset"))
-				return fmt.Errorf("not enough asset")
+				st.addErrorLog(common.TimeLockFunc, timeLockParam, common.ErrNotEnoughAsset, common.NewKeyValue("LockType", "AssetToTimeLock"))
+				return common.ErrNotEnoughAsset
 			}
 			st.state.SubBalance(st.msg.From(), timeLockParam.AssetID, timeLockParam.Value)
 
@@ -131,209 +194,139 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 				}
 				st.state.AddTimeLockBalance(timeLockParam.To, timeLockParam.AssetID, needValue, height, timestamp)
 			}
+			st.addTransfer(common.TransferTimeLock, st.msg.From(), timeLockParam.To, timeLockParam.AssetID, timeLockParam.Value, start, end, common.Hash{})
 
 			st.addLog(common.TimeLockFunc, timeLockParam, common.NewKeyValue("LockType", "AssetToTimeLock"), common.NewKeyValue("AssetID", timeLockParam.AssetID))
 			return nil
 		case common.TimeLockToTimeLock:
 			if st.state.GetTimeLockBalance(timeLockParam.AssetID, st.msg.From()).Cmp(needValue) < 0 {
-				st.addLog(common.TimeLockFunc, timeLockParam, common.NewKeyValue("LockType", "TimeLockToTimeLock"), common.NewKeyValue("Error", "not enough time lock balance"))
-				return fmt.Errorf("not enough time lock balance")
+				st.addErrorLog(common.TimeLockFunc, timeLockParam, common.ErrNotEnoughTimeLock, common.NewKeyValue("LockType", "TimeLockToTimeLock"))
+				return common.ErrNotEnoughTimeLock
 			}
 			st.state.SubTimeLockBalance(st.msg.From(), timeLockParam.AssetID, needValue, height, timestamp)
 			st.state.AddTimeLockBalance(timeLockParam.To, timeLockParam.AssetID, needValue, height, timestamp)
+			st.addTransfer(common.TransferTimeLock, st.msg.From(), timeLockParam.To, timeLockParam.AssetID, timeLockParam.Value, start, end, common.Hash{})
 			st.addLog(common.TimeLockFunc, timeLockParam, common.NewKeyValue("LockType", "TimeLockToTimeLock"), common.NewKeyValue("AssetID", timeLockParam.AssetID))
 			return nil
 		case common.TimeLockToAsset:
 			if st.state.GetTimeLockBalance(timeLockParam.AssetID, st.msg.From()).Cmp(needValue) < 0 {
-				st.addLog(common.TimeLockFunc, timeLockParam, common.NewKeyValue("LockType", "TimeLockToAsset"), common.NewKeyValue("Error", "not enough time lock balance"))
-				return fmt.Errorf("not enough time lock balance")
+				st.addErrorLog(common.TimeLockFunc, timeLockParam, common.ErrNotEnoughTimeLock, common.NewKeyValue("LockType", "TimeLockToAsset"))
+				return common.ErrNotEnoughTimeLock
 			}
 			st.state.SubTimeLockBalance(st.msg.From(), timeLockParam.AssetID, needValue, height, timestamp)
 			st.state.AddBalance(timeLockParam.To, timeLockParam.AssetID, timeLockParam.Value)
+			st.addTransfer(common.TransferTimeLock, st.msg.From(), timeLockParam.To, timeLockParam.AssetID, timeLockParam.Value, 0, 0, common.Hash{})
 			st.addLog(common.TimeLockFunc, timeLockParam, common.NewKeyValue("LockType", "TimeLockToAsset"), common.NewKeyValue("AssetID", timeLockParam.AssetID))
 			return nil
 		case common.SmartTransfer:
 			if !common.IsSmartTransferEnabled(height) {
-				st.addLog(common.TimeLockFunc, timeLockParam, common.NewKeyValue("LockType", "SmartTransfer"), common.NewKeyValue("Error", "not enabled"))
-				return fmt.Errorf("SendTimeLock not enabled")
+				st.addErrorLog(common.TimeLockFunc, timeLockParam, common.NewFsnError(common.FsnErrNotEnabled, "not enabled"), common.NewKeyValue("LockType", "SmartTransfer"))
+				return common.NewFsnError(common.FsnErrNotEnabled, "SendTimeLock not enabled")
 			}
-			timeLockBalance := st.state.GetTimeLockBalance(timeLockParam.AssetID, st.msg.From())
-			if timeLockBalance.Cmp(needValue) < 0 {
-				timeLockValue := timeLockBalance.GetSpendableValue(start, end)
-				assetBalance := st.state.GetBalance(timeLockParam.AssetID, st.msg.From())
-				if new(big.Int).Add(timeLockValue, assetBalance).Cmp(timeLockParam.Value) < 0 {
-					st.addLog(common.TimeLockFunc, timeLockParam, common.NewKeyValue("LockType", "SmartTransfer"), common.NewKeyValue("Error", "not enough balance"))
-					return fmt.Errorf("not enough balance")
-				}
-				if timeLockValue.Sign() > 0 {
-					subTimeLock := common.GetTimeLock(timeLockValue, start, end)
-					st.state.SubTimeLockBalance(st.msg.From(), timeLockParam.AssetID, subTimeLock, height, timestamp)
-				}
-				useAssetAmount := new(big.Int).Sub(timeLockParam.Value, timeLockValue)
-				st.state.SubBalance(st.msg.From(), timeLockParam.AssetID, useAssetAmount)
-				surplus := common.GetSurplusTimeLock(useAssetAmount, start, end, timestamp)
-				if !surplus.IsEmpty() {
-					st.state.AddTimeLockBalance(st.msg.From(), timeLockParam.AssetID, surplus, height, timestamp)
-				}
-			} else {
-				st.state.SubTimeLockBalance(st.msg.From(), timeLockParam.AssetID, needValue, height, timestamp)
+			if !subSmartTransfer(st.state, st.msg.From(), timeLockParam.AssetID, timeLockParam.Value, start, end, height, timestamp) {
+				st.addErrorLog(common.TimeLockFunc, timeLockParam, common.ErrNotEnoughBalance, common.NewKeyValue("LockType", "SmartTransfer"))
+				return common.ErrNotEnoughBalance
 			}
 
 			if !common.IsWholeAsset(start, end, timestamp) {
 				st.state.AddTimeLockBalance(timeLockParam.To, timeLockParam.AssetID, needValue, height, timestamp)
+				st.addTransfer(common.TransferTimeLock, st.msg.From(), timeLockParam.To, timeLockParam.AssetID, timeLockParam.Value, start, end, common.Hash{})
 			} else {
 				st.state.AddBalance(timeLockParam.To, timeLockParam.AssetID, timeLockParam.Value)
+				st.addTransfer(common.TransferTimeLock, st.msg.From(), timeLockParam.To, timeLockParam.AssetID, timeLockParam.Value, 0, 0, common.Hash{})
 			}
 			st.addLog(common.TimeLockFunc, timeLockParam, common.NewKeyValue("LockType", "SmartTransfer"), common.NewKeyValue("AssetID", timeLockParam.AssetID))
 			return nil
 		}
 	case common.BuyTicketFunc:
-		from := st.msg.From()
-		hash := st.evm.GetHash(height.Uint64() - 1)
-		id := crypto.Keccak256Hash(from[:], hash[:])
-
-		if st.state.IsTicketExist(id) {
-			st.addLog(common.BuyTicketFunc, param.Data, common.NewKeyValue("Error", "Ticket already exist"))
-			return fmt.Errorf(id.String() + " Ticket already exist")
-		}
-
-		buyTicketParam := common.BuyTicketParam{}
-		rlp.DecodeBytes(param.Data, &buyTicketParam)
-
-		// check buy ticket param
-		if common.IsHardFork(2, height) {
-			if err := buyTicketParam.Check(height, timestamp); err != nil {
-				st.addLog(common.BuyTicketFunc, param.Data, common.NewKeyValue("Error", err.Error()))
-				return err
-			}
-		} else {
-			if err := buyTicketParam.Check(height, 0); err != nil {
-				st.addLog(common.BuyTicketFunc, param.Data, common.NewKeyValue("Error", err.Error()))
-				return err
-			}
-		}
-
-		start := buyTicketParam.Start
-		end := buyTicketParam.End
-		value := common.TicketPrice(height)
-		var needValue *common.TimeLock
-
-		needValue = common.NewTimeLock(&common.TimeLockItem{
-			StartTime: common.MaxUint64(start, timestamp),
-			EndTime:   end,
-			Value:     value,
-		})
-		if err := needValue.IsValid(); err != nil {
-			st.addLog(common.BuyTicketFunc, param.Data, common.NewKeyValue("Error", err.Error()))
-			return fmt.Errorf(err.Error())
-		}
-
-		ticket := common.Ticket{
-			Owner: from,
-			TicketBody: common.TicketBody{
-				ID:         id,
-				Height:     height.Uint64(),
-				StartTime:  start,
-				ExpireTime: end,
-			},
-		}
-
-		useAsset := false
-		if st.state.GetTimeLockBalance(common.SystemAssetID, from).Cmp(needValue) < 0 {
-			if st.state.GetBalance(common.SystemAssetID, from).Cmp(value) < 0 {
-				st.addLog(common.BuyTicketFunc, param.Data, common.NewKeyValue("Error", "not enough time lock or asset balance"))
-				return fmt.Errorf("not enough time lock or asset balance")
-			}
-			useAsset = true
-		}
-
-		if useAsset {
-			st.state.SubBalance(from, common.SystemAssetID, value)
-
-			totalValue := common.NewTimeLock(&common.TimeLockItem{
-				StartTime: timestamp,
-				EndTime:   common.TimeLockForever,
-				Value:     value,
-			})
-			surplusValue := new(common.TimeLock).Sub(totalValue, needValue)
-			if !surplusValue.IsEmpty() {
-				st.state.AddTimeLockBalance(from, common.SystemAssetID, surplusValue, height, timestamp)
-			}
-
-		} else {
-			st.state.SubTimeLockBalance(from, common.SystemAssetID, needValue, height, timestamp)
-		}
-
-		if err := st.state.AddTicket(ticket); err != nil {
-			st.addLog(common.BuyTicketFunc, param.Data, common.NewKeyValue("Error", "unable to add ticket"))
-			return err
-		}
-		st.addLog(common.BuyTicketFunc, param.Data, common.NewKeyValue("TicketID", ticket.ID), common.NewKeyValue("TicketOwner", ticket.Owner))
-		return nil
+		return st.buyTicket(st.msg.From(), param.Data, false)
 	case common.AssetValueChangeFunc:
 		assetValueChangeParamEx := common.AssetValueChangeExParam{}
 		rlp.DecodeBytes(param.Data, &assetValueChangeParamEx)
 
 		if err := assetValueChangeParamEx.Check(height); err != nil {
-			st.addLog(common.AssetValueChangeFunc, assetValueChangeParamEx, common.NewKeyValue("Error", err.Error()))
+			st.addErrorLog(common.AssetValueChangeFunc, assetValueChangeParamEx, err)
 			return err
 		}
 
 		asset, err := st.state.GetAsset(assetValueChangeParamEx.AssetID)
 		if err != nil {
-			st.addLog(common.AssetValueChangeFunc, assetValueChangeParamEx, common.NewKeyValue("Error", "asset not found"))
-			return fmt.Errorf("asset not found")
+			st.addErrorLog(common.AssetValueChangeFunc, assetValueChangeParamEx, common.ErrAssetNotFound)
+			return common.ErrAssetNotFound
 		}
 
 		if !asset.CanChange {
-			st.addLog(common.AssetValueChangeFunc, assetValueChangeParamEx, common.NewKeyValue("Error", "asset can't inc or dec"))
-			return fmt.Errorf("asset can't inc or dec")
+			st.addErrorLog(common.AssetValueChangeFunc, assetValueChangeParamEx, common.ErrAssetNotChangeable)
+			return common.ErrAssetNotChangeable
 		}
 
-		if asset.Owner != st.msg.From() {
-			st.addLog(common.AssetValueChangeFunc, assetValueChangeParamEx, common.NewKeyValue("Error", "can only be changed by owner"))
-			return fmt.Errorf("can only be changed by owner")
+		if err := checkAssetOwner(st.state, &asset, param, st.msg.From(), height); err != nil {
+			st.addErrorLog(common.AssetValueChangeFunc, assetValueChangeParamEx, err)
+			return err
 		}
 
 		if asset.Owner != assetValueChangeParamEx.To && !assetValueChangeParamEx.IsInc {
-			err := fmt.Errorf("decrement can only happen to asset's own account")
-			st.addLog(common.AssetValueChangeFunc, assetValueChangeParamEx, common.NewKeyValue("Error", err.Error()))
+			err := common.NewFsnError(common.FsnErrNotAllowed, "decrement can only happen to asset's own account")
+			st.addErrorLog(common.AssetValueChangeFunc, assetValueChangeParamEx, err)
 			return err
 		}
 
+		if !assetValueChangeParamEx.IsInc && st.state.GetBalance(assetValueChangeParamEx.AssetID, assetValueChangeParamEx.To).Cmp(assetValueChangeParamEx.Value) < 0 {
+			st.addErrorLog(common.AssetValueChangeFunc, assetValueChangeParamEx, common.ErrNotEnoughAsset)
+			return common.ErrNotEnoughAsset
+		}
+
+		if approved, approvals := st.approveAssetOwnerCall(&asset, param); !approved {
+			st.addLog(common.AssetValueChangeFunc, assetValueChangeParamEx, common.NewKeyValue("AssetID", assetValueChangeParamEx.AssetID), common.NewKeyValue("Approvals", approvals))
+			return nil
+		}
+
 		if assetValueChangeParamEx.IsInc {
 			st.state.AddBalance(assetValueChangeParamEx.To, assetValueChangeParamEx.AssetID, assetValueChangeParamEx.Value)
 			asset.Total = asset.Total.Add(asset.Total, assetValueChangeParamEx.Value)
+			st.addTransfer(common.TransferMint, common.Address{}, assetValueChangeParamEx.To, asset.ID, assetValueChangeParamEx.Value, 0, 0, common.Hash{})
 		} else {
-			if st.state.GetBalance(assetValueChangeParamEx.AssetID, assetValueChangeParamEx.To).Cmp(assetValueChangeParamEx.Value) < 0 {
-				st.addLog(common.AssetValueChangeFunc, assetValueChangeParamEx, common.NewKeyValue("Error", "not enough asset"))
-				return fmt.Errorf("not enough asset")
-			}
 			st.state.SubBalance(assetValueChangeParamEx.To, assetValueChangeParamEx.AssetID, assetValueChangeParamEx.Value)
 			asset.Total = asset.Total.Sub(asset.Total, assetValueChangeParamEx.Value)
+			st.addTransfer(common.TransferBurn, assetValueChangeParamEx.To, common.Address{}, asset.ID, assetValueChangeParamEx.Value, 0, 0, common.Hash{})
 		}
 		err = st.state.UpdateAsset(asset)
 		if err == nil {
+			if common.IsAssetSupplyHistoryEnabled(height) {
+				st.state.AddAssetSupplyChange(asset.ID, &common.AssetSupplyChange{
+					Height: height.Uint64(),
+					Time:   st.evm.Context.Time.Uint64(),
+					Sender: st.msg.From(),
+					To:     assetValueChangeParamEx.To,
+					IsInc:  assetValueChangeParamEx.IsInc,
+					Value:  assetValueChangeParamEx.Value,
+					Total:  asset.Total,
+				})
+			}
 			st.addLog(common.AssetValueChangeFunc, assetValueChangeParamEx, common.NewKeyValue("AssetID", assetValueChangeParamEx.AssetID))
 		} else {
-			st.addLog(common.AssetValueChangeFunc, assetValueChangeParamEx, common.NewKeyValue("Error", "error update asset"))
+			st.addErrorLog(common.AssetValueChangeFunc, assetValueChangeParamEx, common.NewFsnError(common.FsnErrStateUpdate, "error update asset"))
 		}
-		return err
+		return common.WrapFsnError(common.FsnErrStateUpdate, err)
 	case common.EmptyFunc:
 	case common.MakeSwapFunc, common.MakeSwapFuncExt:
 		notation := st.state.GetNotation(st.msg.From())
 		makeSwapParam := common.MakeSwapParam{}
 		rlp.DecodeBytes(param.Data, &makeSwapParam)
-		swapId := GetUniqueHashFromMessage(st.msg)
+		swapId := GetUniqueHashFromMessageAt(st.msg, st.evm.BlockNumber)
 
 		_, err := st.state.GetSwap(swapId)
 		if err == nil {
-			st.addLog(common.MakeSwapFunc, makeSwapParam, common.NewKeyValue("Error", "Swap already exist"))
-			return fmt.Errorf("Swap already exist")
+			st.addErrorLog(common.MakeSwapFunc, makeSwapParam, common.ErrSwapExists)
+			return common.ErrSwapExists
 		}
 
 		if err := makeSwapParam.Check(height, timestamp); err != nil {
-			st.addLog(common.MakeSwapFunc, makeSwapParam, common.NewKeyValue("Error", err.Error()))
+			st.addErrorLog(common.MakeSwapFunc, makeSwapParam, err)
+			return err
+		}
+		if err := st.checkSwapTransfers([]common.Hash{makeSwapParam.FromAssetID, makeSwapParam.ToAssetID}, st.msg.From()); err != nil {
+			st.addErrorLog(common.MakeSwapFunc, makeSwapParam, err)
 			return err
 		}
 
@@ -342,15 +335,15 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 		var needValue *common.TimeLock
 
 		if _, err := st.state.GetAsset(makeSwapParam.ToAssetID); err != nil {
-			err := fmt.Errorf("ToAssetID's asset not found")
-			st.addLog(common.MakeSwapFunc, makeSwapParam, common.NewKeyValue("Error", err.Error()))
+			err := common.ErrToAssetNotFound
+			st.addErrorLog(common.MakeSwapFunc, makeSwapParam, err)
 			return err
 		}
 
 		if makeSwapParam.FromAssetID == common.OwnerUSANAssetID {
 			if notation == 0 {
-				err := fmt.Errorf("the from address does not have a notation")
-				st.addLog(common.MakeSwapFunc, makeSwapParam, common.NewKeyValue("Error", err.Error()))
+				err := common.ErrNoNotation
+				st.addErrorLog(common.MakeSwapFunc, makeSwapParam, err)
 				return err
 			}
 			makeSwapParam.MinFromAmount = big.NewInt(1)
@@ -371,8 +364,8 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 					Value:     total,
 				})
 				if err := needValue.IsValid(); err != nil {
-					st.addLog(common.MakeSwapFunc, makeSwapParam, common.NewKeyValue("Error", err.Error()))
-					return fmt.Errorf(err.Error())
+					st.addErrorLog(common.MakeSwapFunc, makeSwapParam, err)
+					return err
 				}
 			}
 		}
@@ -396,14 +389,14 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 
 		if makeSwapParam.FromAssetID == common.OwnerUSANAssetID {
 			if err := st.state.AddSwap(swap); err != nil {
-				st.addLog(common.MakeSwapFunc, makeSwapParam, common.NewKeyValue("Error", "System error can't add swap"))
-				return err
+				st.addErrorLog(common.MakeSwapFunc, makeSwapParam, common.NewFsnError(common.FsnErrStateUpdate, "System error can't add swap"))
+				return common.WrapFsnError(common.FsnErrStateUpdate, err)
 			}
 		} else {
 			if useAsset == true {
 				if st.state.GetBalance(makeSwapParam.FromAssetID, st.msg.From()).Cmp(total) < 0 {
-					st.addLog(common.MakeSwapFunc, makeSwapParam, common.NewKeyValue("Error", "not enough from asset"))
-					return fmt.Errorf("not enough from asset")
+					st.addErrorLog(common.MakeSwapFunc, makeSwapParam, common.ErrNotEnoughFromAsset)
+					return common.ErrNotEnoughFromAsset
 				}
 			} else {
 				available := st.state.GetTimeLockBalance(makeSwapParam.FromAssetID, st.msg.From())
@@ -411,13 +404,13 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 					if param.Func == common.MakeSwapFunc {
 						// this was the legacy swap do not do
 						// time lock and just return an error
-						st.addLog(common.MakeSwapFunc, makeSwapParam, common.NewKeyValue("Error", "not enough time lock or asset balance"))
-						return fmt.Errorf("not enough time lock balance")
+						st.addErrorLog(common.MakeSwapFunc, makeSwapParam, common.ErrNotEnoughTimeLockOrAsset)
+						return common.ErrNotEnoughTimeLock
 					}
 
 					if st.state.GetBalance(makeSwapParam.FromAssetID, st.msg.From()).Cmp(total) < 0 {
-						st.addLog(common.MakeSwapFunc, makeSwapParam, common.NewKeyValue("Error", "not enough time lock or asset balance"))
-						return fmt.Errorf("not enough time lock or asset balance")
+						st.addErrorLog(common.MakeSwapFunc, makeSwapParam, common.ErrNotEnoughTimeLockOrAsset)
+						return common.ErrNotEnoughTimeLockOrAsset
 					}
 
 					// subtract the asset from the balance
@@ -434,15 +427,17 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 			}
 
 			if err := st.state.AddSwap(swap); err != nil {
-				st.addLog(common.MakeSwapFunc, makeSwapParam, common.NewKeyValue("Error", "System error can't add swap"))
-				return err
+				st.addErrorLog(common.MakeSwapFunc, makeSwapParam, common.NewFsnError(common.FsnErrStateUpdate, "System error can't add swap"))
+				return common.WrapFsnError(common.FsnErrStateUpdate, err)
 			}
 
 			// take from the owner the asset
 			if useAsset == true {
 				st.state.SubBalance(st.msg.From(), makeSwapParam.FromAssetID, total)
+				st.addTransfer(common.TransferSwapLock, st.msg.From(), common.FSNCallAddress, makeSwapParam.FromAssetID, total, 0, 0, swap.ID)
 			} else {
 				st.state.SubTimeLockBalance(st.msg.From(), makeSwapParam.FromAssetID, needValue, height, timestamp)
+				st.addTransfer(common.TransferSwapLock, st.msg.From(), common.FSNCallAddress, makeSwapParam.FromAssetID, total, common.MaxUint64(makeSwapParam.FromStartTime, timestamp), makeSwapParam.FromEndTime, swap.ID)
 			}
 		}
 		st.addLog(common.MakeSwapFunc, makeSwapParam, common.NewKeyValue("SwapID", swap.ID))
@@ -453,23 +448,23 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 
 		swap, err := st.state.GetSwap(recallSwapParam.SwapID)
 		if err != nil {
-			st.addLog(common.RecallSwapFunc, recallSwapParam, common.NewKeyValue("Error", "Swap not found"))
-			return fmt.Errorf("Swap not found")
+			st.addErrorLog(common.RecallSwapFunc, recallSwapParam, common.ErrSwapNotFound)
+			return common.ErrSwapNotFound
 		}
 
 		if swap.Owner != st.msg.From() {
-			st.addLog(common.RecallSwapFunc, recallSwapParam, common.NewKeyValue("Error", "Must be swap onwer can recall"))
-			return fmt.Errorf("Must be swap onwer can recall")
+			st.addErrorLog(common.RecallSwapFunc, recallSwapParam, common.ErrNotSwapOwner)
+			return common.ErrNotSwapOwner
 		}
 
 		if err := recallSwapParam.Check(height, &swap); err != nil {
-			st.addLog(common.RecallSwapFunc, recallSwapParam, common.NewKeyValue("Error", err.Error()))
+			st.addErrorLog(common.RecallSwapFunc, recallSwapParam, err)
 			return err
 		}
 
-		if err := st.state.RemoveSwap(swap.ID); err != nil {
-			st.addLog(common.RecallSwapFunc, recallSwapParam, common.NewKeyValue("Error", "Unable to remove swap"))
-			return err
+		if err := st.removeSwap(common.SwapKeyAddress, swap.ID); err != nil {
+			st.addErrorLog(common.RecallSwapFunc, recallSwapParam, common.NewFsnError(common.FsnErrStateUpdate, "Unable to remove swap"))
+			return common.WrapFsnError(common.FsnErrStateUpdate, err)
 		}
 
 		if swap.FromAssetID != common.OwnerUSANAssetID {
@@ -481,6 +476,7 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 			// return to the owner the balance
 			if useAsset == true {
 				st.state.AddBalance(st.msg.From(), swap.FromAssetID, total)
+				st.addTransfer(common.TransferSwapRefund, common.FSNCallAddress, st.msg.From(), swap.FromAssetID, total, 0, 0, swap.ID)
 			} else {
 				needValue := common.NewTimeLock(&common.TimeLockItem{
 					StartTime: common.MaxUint64(start, timestamp),
@@ -489,6 +485,7 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 				})
 				if err := needValue.IsValid(); err == nil {
 					st.state.AddTimeLockBalance(st.msg.From(), swap.FromAssetID, needValue, height, timestamp)
+					st.addTransfer(common.TransferSwapRefund, common.FSNCallAddress, st.msg.From(), swap.FromAssetID, total, common.MaxUint64(start, timestamp), end, swap.ID)
 				}
 			}
 		}
@@ -500,18 +497,23 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 
 		swap, err := st.state.GetSwap(takeSwapParam.SwapID)
 		if err != nil {
-			st.addLog(common.TakeSwapFunc, takeSwapParam, common.NewKeyValue("Error", "swap not found"))
-			return fmt.Errorf("Swap not found")
+			st.addErrorLog(common.TakeSwapFunc, takeSwapParam, common.NewFsnError(common.FsnErrSwapNotFound, "swap not found"))
+			return common.ErrSwapNotFound
 		}
 
 		if err := takeSwapParam.Check(height, &swap, timestamp); err != nil {
-			st.addLog(common.TakeSwapFunc, takeSwapParam, common.NewKeyValue("Error", err.Error()))
+			st.addErrorLog(common.TakeSwapFunc, takeSwapParam, err)
+			return err
+		}
+		takeSwapParam.Size = takeSwapParam.FillSize(height, &swap)
+		if err := st.checkSwapTransfers([]common.Hash{swap.FromAssetID, swap.ToAssetID}, st.msg.From(), swap.Owner); err != nil {
+			st.addErrorLog(common.TakeSwapFunc, takeSwapParam, err)
 			return err
 		}
 
 		if common.IsPrivateSwapCheckingEnabled(height) {
 			if err := common.CheckSwapTargets(swap.Targes, st.msg.From()); err != nil {
-				st.addLog(common.TakeSwapFunc, takeSwapParam, common.NewKeyValue("Error", err.Error()))
+				st.addErrorLog(common.TakeSwapFunc, takeSwapParam, err)
 				return err
 			}
 		}
@@ -520,8 +522,8 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 		if swap.FromAssetID == common.OwnerUSANAssetID {
 			notation := st.state.GetNotation(swap.Owner)
 			if notation == 0 || notation != swap.Notation {
-				err := fmt.Errorf("notation in swap is no longer valid")
-				st.addLog(common.TakeSwapFunc, takeSwapParam, common.NewKeyValue("Error", err.Error()))
+				err := common.ErrSwapNotationInvalid
+				st.addErrorLog(common.TakeSwapFunc, takeSwapParam, err)
 				return err
 			}
 			usanSwap = true
@@ -559,8 +561,8 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 
 		if toUseAsset == true {
 			if st.state.GetBalance(swap.ToAssetID, st.msg.From()).Cmp(toTotal) < 0 {
-				st.addLog(common.TakeSwapFunc, takeSwapParam, common.NewKeyValue("Error", "not enough from asset"))
-				return fmt.Errorf("not enough from asset")
+				st.addErrorLog(common.TakeSwapFunc, takeSwapParam, common.ErrNotEnoughFromAsset)
+				return common.ErrNotEnoughFromAsset
 			}
 		} else {
 			isValid := true
@@ -572,13 +574,13 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 				if param.Func == common.TakeSwapFunc {
 					// this was the legacy swap do not do
 					// time lock and just return an error
-					st.addLog(common.TakeSwapFunc, takeSwapParam, common.NewKeyValue("Error", "not enough time lock balance"))
-					return fmt.Errorf("not enough time lock balance")
+					st.addErrorLog(common.TakeSwapFunc, takeSwapParam, common.ErrNotEnoughTimeLock)
+					return common.ErrNotEnoughTimeLock
 				}
 
 				if st.state.GetBalance(swap.ToAssetID, st.msg.From()).Cmp(toTotal) < 0 {
-					st.addLog(common.TakeSwapFunc, takeSwapParam, common.NewKeyValue("Error", "not enough time lock balance"))
-					return fmt.Errorf("not enough time lock or asset balance")
+					st.addErrorLog(common.TakeSwapFunc, takeSwapParam, common.ErrNotEnoughTimeLock)
+					return common.ErrNotEnoughTimeLockOrAsset
 				}
 
 				// subtract the asset from the balance
@@ -597,39 +599,42 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 		swapDeleted := "false"
 
 		if swap.SwapSize.Cmp(takeSwapParam.Size) == 0 {
-			if err := st.state.RemoveSwap(swap.ID); err != nil {
-				st.addLog(common.TakeSwapFunc, takeSwapParam, common.NewKeyValue("Error", "System Error"))
-				return err
+			if err := st.removeSwap(common.SwapKeyAddress, swap.ID); err != nil {
+				st.addErrorLog(common.TakeSwapFunc, takeSwapParam, common.NewFsnError(common.FsnErrStateUpdate, "System Error"))
+				return common.WrapFsnError(common.FsnErrStateUpdate, err)
 			}
 			swapDeleted = "true"
 		} else {
 			swap.SwapSize = swap.SwapSize.Sub(swap.SwapSize, takeSwapParam.Size)
 			if err := st.state.UpdateSwap(swap); err != nil {
-				st.addLog(common.TakeSwapFunc, takeSwapParam, common.NewKeyValue("Error", "System Error"))
-				return err
+				st.addErrorLog(common.TakeSwapFunc, takeSwapParam, common.NewFsnError(common.FsnErrStateUpdate, "System Error"))
+				return common.WrapFsnError(common.FsnErrStateUpdate, err)
 			}
 		}
 
 		if toUseAsset == true {
 			st.state.AddBalance(swap.Owner, swap.ToAssetID, toTotal)
 			st.state.SubBalance(st.msg.From(), swap.ToAssetID, toTotal)
+			st.addTransfer(common.TransferSwapSettlement, st.msg.From(), swap.Owner, swap.ToAssetID, toTotal, 0, 0, swap.ID)
 		} else {
 			if err := toNeedValue.IsValid(); err == nil {
 				st.state.AddTimeLockBalance(swap.Owner, swap.ToAssetID, toNeedValue, height, timestamp)
 				st.state.SubTimeLockBalance(st.msg.From(), swap.ToAssetID, toNeedValue, height, timestamp)
+				st.addTransfer(common.TransferSwapSettlement, st.msg.From(), swap.Owner, swap.ToAssetID, toTotal, common.MaxUint64(toStart, timestamp), toEnd, swap.ID)
 			}
 		}
 
 		// credit the taker
 		if usanSwap {
-			err := st.state.TransferNotation(swap.Notation, swap.Owner, st.msg.From())
+			err := st.state.TransferNotation(swap.Notation, swap.Owner, st.msg.From(), height)
 			if err != nil {
-				st.addLog(common.TakeSwapFunc, takeSwapParam, common.NewKeyValue("Error", "System Error"))
-				return err
+				st.addErrorLog(common.TakeSwapFunc, takeSwapParam, common.NewFsnError(common.FsnErrStateUpdate, "System Error"))
+				return common.WrapFsnError(common.FsnErrStateUpdate, err)
 			}
 		} else {
 			if fromUseAsset == true {
 				st.state.AddBalance(st.msg.From(), swap.FromAssetID, fromTotal)
+				st.addTransfer(common.TransferSwapSettlement, common.FSNCallAddress, st.msg.From(), swap.FromAssetID, fromTotal, 0, 0, swap.ID)
 				// the owner of the swap already had their balance taken away
 				// in MakeSwapFunc
 				// there is no need to subtract this balance again
@@ -637,6 +642,7 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 			} else {
 				if err := fromNeedValue.IsValid(); err == nil {
 					st.state.AddTimeLockBalance(st.msg.From(), swap.FromAssetID, fromNeedValue, height, timestamp)
+					st.addTransfer(common.TransferSwapSettlement, common.FSNCallAddress, st.msg.From(), swap.FromAssetID, fromTotal, common.MaxUint64(fromStart, timestamp), fromEnd, swap.ID)
 				}
 				// the owner of the swap already had their timelock balance taken away
 				// in MakeSwapFunc
@@ -652,23 +658,23 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 
 		swap, err := st.state.GetMultiSwap(recallSwapParam.SwapID)
 		if err != nil {
-			st.addLog(common.RecallMultiSwapFunc, recallSwapParam, common.NewKeyValue("Error", "Swap not found"))
-			return fmt.Errorf("Swap not found")
+			st.addErrorLog(common.RecallMultiSwapFunc, recallSwapParam, common.ErrSwapNotFound)
+			return common.ErrSwapNotFound
 		}
 
 		if swap.Owner != st.msg.From() {
-			st.addLog(common.RecallMultiSwapFunc, recallSwapParam, common.NewKeyValue("Error", "Must be swap onwer can recall"))
-			return fmt.Errorf("Must be swap onwer can recall")
+			st.addErrorLog(common.RecallMultiSwapFunc, recallSwapParam, common.ErrNotSwapOwner)
+			return common.ErrNotSwapOwner
 		}
 
 		if err := recallSwapParam.Check(height, &swap); err != nil {
-			st.addLog(common.RecallMultiSwapFunc, recallSwapParam, common.NewKeyValue("Error", err.Error()))
+			st.addErrorLog(common.RecallMultiSwapFunc, recallSwapParam, err)
 			return err
 		}
 
-		if err := st.state.RemoveMultiSwap(swap.ID); err != nil {
-			st.addLog(common.RecallMultiSwapFunc, recallSwapParam, common.NewKeyValue("Error", "Unable to remove swap"))
-			return err
+		if err := st.removeSwap(common.MultiSwapKeyAddress, swap.ID); err != nil {
+			st.addErrorLog(common.RecallMultiSwapFunc, recallSwapParam, common.NewFsnError(common.FsnErrStateUpdate, "Unable to remove swap"))
+			return common.WrapFsnError(common.FsnErrStateUpdate, err)
 		}
 
 		ln := len(swap.FromAssetID)
@@ -681,6 +687,7 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 			// return to the owner the balance
 			if useAsset == true {
 				st.state.AddBalance(st.msg.From(), swap.FromAssetID[i], total)
+				st.addTransfer(common.TransferSwapRefund, common.FSNCallAddress, st.msg.From(), swap.FromAssetID[i], total, 0, 0, swap.ID)
 			} else {
 				needValue := common.NewTimeLock(&common.TimeLockItem{
 					StartTime: common.MaxUint64(start, timestamp),
@@ -690,6 +697,7 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 
 				if err := needValue.IsValid(); err == nil {
 					st.state.AddTimeLockBalance(st.msg.From(), swap.FromAssetID[i], needValue, height, timestamp)
+					st.addTransfer(common.TransferSwapRefund, common.FSNCallAddress, st.msg.From(), swap.FromAssetID[i], total, common.MaxUint64(start, timestamp), end, swap.ID)
 				}
 			}
 		}
@@ -699,23 +707,27 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 		notation := st.state.GetNotation(st.msg.From())
 		makeSwapParam := common.MakeMultiSwapParam{}
 		rlp.DecodeBytes(param.Data, &makeSwapParam)
-		swapID := GetUniqueHashFromMessage(st.msg)
+		swapID := GetUniqueHashFromMessageAt(st.msg, st.evm.BlockNumber)
 
 		_, err := st.state.GetSwap(swapID)
 		if err == nil {
-			st.addLog(common.MakeMultiSwapFunc, makeSwapParam, common.NewKeyValue("Error", "Swap already exist"))
-			return fmt.Errorf("Swap already exist")
+			st.addErrorLog(common.MakeMultiSwapFunc, makeSwapParam, common.ErrSwapExists)
+			return common.ErrSwapExists
 		}
 
 		if err := makeSwapParam.Check(height, timestamp); err != nil {
-			st.addLog(common.MakeMultiSwapFunc, makeSwapParam, common.NewKeyValue("Error", err.Error()))
+			st.addErrorLog(common.MakeMultiSwapFunc, makeSwapParam, err)
+			return err
+		}
+		if err := st.checkSwapTransfers(append(append([]common.Hash{}, makeSwapParam.FromAssetID...), makeSwapParam.ToAssetID...), st.msg.From()); err != nil {
+			st.addErrorLog(common.MakeMultiSwapFunc, makeSwapParam, err)
 			return err
 		}
 
 		for _, toAssetID := range makeSwapParam.ToAssetID {
 			if _, err := st.state.GetAsset(toAssetID); err != nil {
-				err := fmt.Errorf("ToAssetID's asset not found")
-				st.addLog(common.MakeMultiSwapFunc, makeSwapParam, common.NewKeyValue("Error", err.Error()))
+				err := common.ErrToAssetNotFound
+				st.addErrorLog(common.MakeMultiSwapFunc, makeSwapParam, err)
 				return err
 			}
 		}
@@ -748,8 +760,8 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 					Value:     total[i],
 				})
 				if err := needValue[i].IsValid(); err != nil {
-					st.addLog(common.MakeMultiSwapFunc, makeSwapParam, common.NewKeyValue("Error", err.Error()))
-					return fmt.Errorf(err.Error())
+					st.addErrorLog(common.MakeMultiSwapFunc, makeSwapParam, err)
+					return err
 				}
 			}
 
@@ -778,16 +790,16 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 			timeLockBalance := accountTimeLockBalances[makeSwapParam.FromAssetID[i]]
 			if useAsset[i] == true {
 				if balance.Cmp(total[i]) < 0 {
-					err = fmt.Errorf("not enough from asset")
-					st.addLog(common.MakeMultiSwapFunc, makeSwapParam, common.NewKeyValue("Error", err.Error()))
+					err = common.ErrNotEnoughFromAsset
+					st.addErrorLog(common.MakeMultiSwapFunc, makeSwapParam, err)
 					return err
 				}
 				balance.Sub(balance, total[i])
 			} else {
 				if timeLockBalance.Cmp(needValue[i]) < 0 {
 					if balance.Cmp(total[i]) < 0 {
-						err = fmt.Errorf("not enough time lock or asset balance")
-						st.addLog(common.MakeMultiSwapFunc, makeSwapParam, common.NewKeyValue("Error", err.Error()))
+						err = common.ErrNotEnoughTimeLockOrAsset
+						st.addErrorLog(common.MakeMultiSwapFunc, makeSwapParam, err)
 						return err
 					}
 
@@ -808,7 +820,7 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 		for i := 0; i < ln; i++ {
 			if useAsset[i] == true {
 				if st.state.GetBalance(makeSwapParam.FromAssetID[i], st.msg.From()).Cmp(total[i]) < 0 {
-					deductErr = fmt.Errorf("not enough from asset")
+					deductErr = common.ErrNotEnoughFromAsset
 					break
 				}
 			} else {
@@ -816,7 +828,7 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 				if available.Cmp(needValue[i]) < 0 {
 
 					if st.state.GetBalance(makeSwapParam.FromAssetID[i], st.msg.From()).Cmp(total[i]) < 0 {
-						deductErr = fmt.Errorf("not enough time lock or asset balance")
+						deductErr = common.ErrNotEnoughTimeLockOrAsset
 						break
 					}
 
@@ -842,13 +854,20 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 
 		if deductErr != nil {
 			common.DebugInfo("MakeMultiSwapFunc deduct error, why check balance before have no effect?")
-			st.addLog(common.MakeMultiSwapFunc, makeSwapParam, common.NewKeyValue("Error", deductErr.Error()))
+			st.addErrorLog(common.MakeMultiSwapFunc, makeSwapParam, deductErr)
 			return deductErr
 		}
 
 		if err := st.state.AddMultiSwap(swap); err != nil {
-			st.addLog(common.MakeMultiSwapFunc, makeSwapParam, common.NewKeyValue("Error", "System error can't add swap"))
-			return err
+			st.addErrorLog(common.MakeMultiSwapFunc, makeSwapParam, common.NewFsnError(common.FsnErrStateUpdate, "System error can't add swap"))
+			return common.WrapFsnError(common.FsnErrStateUpdate, err)
+		}
+		for i := 0; i < ln; i++ {
+			if useAsset[i] == true {
+				st.addTransfer(common.TransferSwapLock, st.msg.From(), common.FSNCallAddress, makeSwapParam.FromAssetID[i], total[i], 0, 0, swap.ID)
+			} else {
+				st.addTransfer(common.TransferSwapLock, st.msg.From(), common.FSNCallAddress, makeSwapParam.FromAssetID[i], total[i], common.MaxUint64(makeSwapParam.FromStartTime[i], timestamp), makeSwapParam.FromEndTime[i], swap.ID)
+			}
 		}
 
 		st.addLog(common.MakeMultiSwapFunc, makeSwapParam, common.NewKeyValue("SwapID", swap.ID))
@@ -859,18 +878,22 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 
 		swap, err := st.state.GetMultiSwap(takeSwapParam.SwapID)
 		if err != nil {
-			st.addLog(common.TakeMultiSwapFunc, takeSwapParam, common.NewKeyValue("Error", "swap not found"))
-			return fmt.Errorf("Swap not found")
+			st.addErrorLog(common.TakeMultiSwapFunc, takeSwapParam, common.NewFsnError(common.FsnErrSwapNotFound, "swap not found"))
+			return common.ErrSwapNotFound
 		}
 
 		if err := takeSwapParam.Check(height, &swap, timestamp); err != nil {
-			st.addLog(common.TakeMultiSwapFunc, takeSwapParam, common.NewKeyValue("Error", err.Error()))
+			st.addErrorLog(common.TakeMultiSwapFunc, takeSwapParam, err)
+			return err
+		}
+		if err := st.checkSwapTransfers(append(append([]common.Hash{}, swap.FromAssetID...), swap.ToAssetID...), st.msg.From(), swap.Owner); err != nil {
+			st.addErrorLog(common.TakeMultiSwapFunc, takeSwapParam, err)
 			return err
 		}
 
 		if common.IsPrivateSwapCheckingEnabled(height) {
 			if err := common.CheckSwapTargets(swap.Targes, st.msg.From()); err != nil {
-				st.addLog(common.TakeMultiSwapFunc, takeSwapParam, common.NewKeyValue("Error", err.Error()))
+				st.addErrorLog(common.TakeMultiSwapFunc, takeSwapParam, err)
 				return err
 			}
 		}
@@ -935,8 +958,8 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 			timeLockBalance := accountTimeLockBalances[swap.ToAssetID[i]]
 			if toUseAsset[i] == true {
 				if balance.Cmp(toTotal[i]) < 0 {
-					err = fmt.Errorf("not enough from asset")
-					st.addLog(common.TakeMultiSwapFunc, takeSwapParam, common.NewKeyValue("Error", err.Error()))
+					err = common.ErrNotEnoughFromAsset
+					st.addErrorLog(common.TakeMultiSwapFunc, takeSwapParam, err)
 					return err
 				}
 				balance.Sub(balance, toTotal[i])
@@ -946,8 +969,8 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 				}
 				if timeLockBalance.Cmp(toNeedValue[i]) < 0 {
 					if balance.Cmp(toTotal[i]) < 0 {
-						err = fmt.Errorf("not enough time lock or asset balance")
-						st.addLog(common.TakeMultiSwapFunc, takeSwapParam, common.NewKeyValue("Error", err.Error()))
+						err = common.ErrNotEnoughTimeLockOrAsset
+						st.addErrorLog(common.TakeMultiSwapFunc, takeSwapParam, err)
 						return err
 					}
 
@@ -968,7 +991,7 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 		for i := 0; i < lnTo; i++ {
 			if toUseAsset[i] == true {
 				if st.state.GetBalance(swap.ToAssetID[i], st.msg.From()).Cmp(toTotal[i]) < 0 {
-					deductErr = fmt.Errorf("not enough from asset")
+					deductErr = common.ErrNotEnoughFromAsset
 					break
 				}
 				st.state.SubBalance(st.msg.From(), swap.ToAssetID[i], toTotal[i])
@@ -980,7 +1003,7 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 				if available.Cmp(toNeedValue[i]) < 0 {
 
 					if st.state.GetBalance(swap.ToAssetID[i], st.msg.From()).Cmp(toTotal[i]) < 0 {
-						deductErr = fmt.Errorf("not enough time lock or asset balance")
+						deductErr = common.ErrNotEnoughTimeLockOrAsset
 						break
 					}
 
@@ -1000,23 +1023,23 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 
 		if deductErr != nil {
 			common.DebugInfo("TakeMultiSwapFunc deduct error, why check balance before have no effect?")
-			st.addLog(common.TakeMultiSwapFunc, takeSwapParam, common.NewKeyValue("Error", deductErr.Error()))
+			st.addErrorLog(common.TakeMultiSwapFunc, takeSwapParam, deductErr)
 			return deductErr
 		}
 
 		swapDeleted := "false"
 
 		if swap.SwapSize.Cmp(takeSwapParam.Size) == 0 {
-			if err := st.state.RemoveMultiSwap(swap.ID); err != nil {
-				st.addLog(common.TakeMultiSwapFunc, takeSwapParam, common.NewKeyValue("Error", "System Error"))
-				return err
+			if err := st.removeSwap(common.MultiSwapKeyAddress, swap.ID); err != nil {
+				st.addErrorLog(common.TakeMultiSwapFunc, takeSwapParam, common.NewFsnError(common.FsnErrStateUpdate, "System Error"))
+				return common.WrapFsnError(common.FsnErrStateUpdate, err)
 			}
 			swapDeleted = "true"
 		} else {
 			swap.SwapSize = swap.SwapSize.Sub(swap.SwapSize, takeSwapParam.Size)
 			if err := st.state.UpdateMultiSwap(swap); err != nil {
-				st.addLog(common.TakeMultiSwapFunc, takeSwapParam, common.NewKeyValue("Error", "System Error"))
-				return err
+				st.addErrorLog(common.TakeMultiSwapFunc, takeSwapParam, common.NewFsnError(common.FsnErrStateUpdate, "System Error"))
+				return common.WrapFsnError(common.FsnErrStateUpdate, err)
 			}
 		}
 
@@ -1024,9 +1047,11 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 		for i := 0; i < lnTo; i++ {
 			if toUseAsset[i] == true {
 				st.state.AddBalance(swap.Owner, swap.ToAssetID[i], toTotal[i])
+				st.addTransfer(common.TransferSwapSettlement, st.msg.From(), swap.Owner, swap.ToAssetID[i], toTotal[i], 0, 0, swap.ID)
 			} else {
 				if err := toNeedValue[i].IsValid(); err == nil {
 					st.state.AddTimeLockBalance(swap.Owner, swap.ToAssetID[i], toNeedValue[i], height, timestamp)
+					st.addTransfer(common.TransferSwapSettlement, st.msg.From(), swap.Owner, swap.ToAssetID[i], toTotal[i], common.MaxUint64(toStart[i], timestamp), toEnd[i], swap.ID)
 				}
 			}
 		}
@@ -1035,6 +1060,7 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 		for i := 0; i < lnFrom; i++ {
 			if fromUseAsset[i] == true {
 				st.state.AddBalance(st.msg.From(), swap.FromAssetID[i], fromTotal[i])
+				st.addTransfer(common.TransferSwapSettlement, common.FSNCallAddress, st.msg.From(), swap.FromAssetID[i], fromTotal[i], 0, 0, swap.ID)
 				// the owner of the swap already had their balance taken away
 				// in MakeMultiSwapFunc
 				// there is no need to subtract this balance again
@@ -1042,6 +1068,7 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 			} else {
 				if err := fromNeedValue[i].IsValid(); err == nil {
 					st.state.AddTimeLockBalance(st.msg.From(), swap.FromAssetID[i], fromNeedValue[i], height, timestamp)
+					st.addTransfer(common.TransferSwapSettlement, common.FSNCallAddress, st.msg.From(), swap.FromAssetID[i], fromTotal[i], common.MaxUint64(fromStart[i], timestamp), fromEnd[i], swap.ID)
 				}
 				// the owner of the swap already had their timelock balance taken away
 				// in MakeMultiSwapFunc
@@ -1053,14 +1080,14 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 		return nil
 	case common.ReportIllegalFunc:
 		if !common.IsMultipleMiningCheckingEnabled(height) {
-			return fmt.Errorf("report not enabled")
+			return common.NewFsnError(common.FsnErrNotEnabled, "report not enabled")
 		}
 		report := param.Data
 		header1, header2, err := datong.CheckAddingReport(st.state, report, height)
 		if err != nil {
 			return err
 		}
-		if err := st.state.AddReport(report); err != nil {
+		if err := datong.AddReport(st.state, report, header1, height); err != nil {
 			return err
 		}
 		delTickets := datong.ProcessReport(header1, header2, st.msg.From(), st.state, height, timestamp)
@@ -1069,8 +1096,1005 @@ func (st *StateTransition) handleFsnCall(param *common.FSNCallParam) error {
 		st.addLog(common.ReportIllegalFunc, "", common.NewKeyValue("DeleteTickets", str))
 		common.DebugInfo("ReportIllegal", "reporter", st.msg.From(), "double-miner", header1.Coinbase, "current-block-height", height, "double-mining-height", header1.Number, "DeleteTickets", delTickets)
 		return nil
+	case common.StakingKeyFunc:
+		stakingKeyParam := common.StakingKeyParam{}
+		rlp.DecodeBytes(param.Data, &stakingKeyParam)
+		if err := stakingKeyParam.Check(height, st.msg.From()); err != nil {
+			st.addErrorLog(common.StakingKeyFunc, stakingKeyParam, err)
+			return err
+		}
+		st.state.SetStakingKey(st.msg.From(), stakingKeyParam.Key)
+		st.addLog(common.StakingKeyFunc, stakingKeyParam, common.NewKeyValue("Owner", st.msg.From()))
+		return nil
+	case common.StakingBuyTicketFunc:
+		stakingBuyTicketParam := common.StakingBuyTicketParam{}
+		rlp.DecodeBytes(param.Data, &stakingBuyTicketParam)
+		if err := stakingBuyTicketParam.Check(height, timestamp); err != nil {
+			st.addErrorLog(common.StakingBuyTicketFunc, stakingBuyTicketParam, err)
+			return err
+		}
+		owner := stakingBuyTicketParam.Owner
+		if key := st.state.GetStakingKey(owner); key != st.msg.From() {
+			st.addErrorLog(common.StakingBuyTicketFunc, stakingBuyTicketParam, common.NewFsnError(common.FsnErrNotAllowed, "sender is not the staking key of owner"))
+			return common.NewFsnError(common.FsnErrNotAllowed, "%v is not the staking key of %v", st.msg.From().Hex(), owner.Hex())
+		}
+		// the ticket is owned by and paid from the time lock balance of the owner,
+		// logged as a plain BuyTicket so that ticket tracking needs no changes
+		data, _ := stakingBuyTicketParam.ToBuyTicketParam().ToBytes()
+		return st.buyTicket(owner, data, true, common.NewKeyValue("StakingKey", st.msg.From()))
+	case common.TypedCallFunc:
+		typedCallParam := common.TypedCallParam{}
+		rlp.DecodeBytes(param.Data, &typedCallParam)
+		logParam := struct {
+			Func     common.FSNCallFunc
+			Nonce    uint64
+			Deadline uint64
+		}{typedCallParam.Call.Func, typedCallParam.Nonce, typedCallParam.Deadline}
+
+		signer, err := st.typedCallSigner(&typedCallParam, height, timestamp)
+		if err != nil {
+			st.addErrorLog(common.TypedCallFunc, logParam, err)
+			return err
+		}
+		st.state.SetTypedCallNonce(signer, typedCallParam.Nonce+1)
+		st.addLog(common.TypedCallFunc, logParam, common.NewKeyValue("Signer", signer))
+
+		// execute the call as sent by the signer
+		msg := st.msg
+		st.msg = &typedCallMessage{Message: msg, from: signer}
+		defer func() { st.msg = msg }()
+		return st.handleFsnCall(&typedCallParam.Call)
+	case common.SetFsnCallFeeFunc:
+		setFsnCallFeeParam := common.SetFsnCallFeeParam{}
+		rlp.DecodeBytes(param.Data, &setFsnCallFeeParam)
+		if err := checkSetFsnCallFee(st.state, &setFsnCallFeeParam, st.msg.From(), height); err != nil {
+			st.addErrorLog(common.SetFsnCallFeeFunc, setFsnCallFeeParam, err)
+			return err
+		}
+		hash := setFsnCallFeeParam.Hash()
+		approvals := append(st.state.GetFsnCallFeeApprovals(hash), st.msg.From())
+		if len(approvals) < common.FeeGovernanceThreshold() {
+			st.state.SetFsnCallFeeApprovals(hash, approvals)
+			st.addLog(common.SetFsnCallFeeFunc, setFsnCallFeeParam, common.NewKeyValue("Approvals", len(approvals)))
+			return nil
+		}
+		// approved by the majority of the governors, the fee changes at
+		// the given height and stays unchanged until then
+		st.state.SetFsnCallFeeEntry(setFsnCallFeeParam.Func, &common.FsnCallFeeEntry{
+			Prev:   common.GetFsnCallFeeAt(&common.FSNCallAddress, setFsnCallFeeParam.Func, height, st.state),
+			Fee:    setFsnCallFeeParam.Fee,
+			Height: setFsnCallFeeParam.Height,
+		})
+		st.state.SetFsnCallFeeApprovals(hash, nil)
+		st.addLog(common.SetFsnCallFeeFunc, setFsnCallFeeParam, common.NewKeyValue("Approvals", len(approvals)), common.NewKeyValue("Applied", true))
+		return nil
+	case common.CreateProposalFunc:
+		createProposalParam := common.CreateProposalParam{}
+		rlp.DecodeBytes(param.Data, &createProposalParam)
+		total, err := checkCreateProposal(st.state, &createProposalParam, st.msg.From(), height, timestamp)
+		if err != nil {
+			st.addErrorLog(common.CreateProposalFunc, createProposalParam, err)
+			return err
+		}
+		proposal := common.Proposal{
+			ID:           GetUniqueHashFromMessageAt(st.msg, st.evm.BlockNumber),
+			Proposer:     st.msg.From(),
+			Title:        createProposalParam.Title,
+			Description:  createProposalParam.Description,
+			StartHeight:  height.Uint64(),
+			StartTime:    timestamp,
+			EndHeight:    createProposalParam.EndHeight,
+			TotalTickets: total,
+		}
+		if err := st.state.AddProposal(proposal); err != nil {
+			st.addErrorLog(common.CreateProposalFunc, createProposalParam, common.NewFsnError(common.FsnErrStateUpdate, "unable to create proposal"))
+			return common.WrapFsnError(common.FsnErrStateUpdate, err)
+		}
+		st.addLog(common.CreateProposalFunc, createProposalParam, common.NewKeyValue("ProposalID", proposal.ID))
+		return nil
+	case common.VoteProposalFunc:
+		voteProposalParam := common.VoteProposalParam{}
+		rlp.DecodeBytes(param.Data, &voteProposalParam)
+		proposal, tickets, err := checkVoteProposal(st.state, &voteProposalParam, st.msg.From(), height, timestamp)
+		if err != nil {
+			st.addErrorLog(common.VoteProposalFunc, voteProposalParam, err)
+			return err
+		}
+		// a new vote replaces the previous one of the voter
+		if prev := st.state.GetProposalVote(proposal.ID, st.msg.From()); prev != nil {
+			if prev.Approve {
+				proposal.Approvals -= prev.Tickets
+			} else {
+				proposal.Rejections -= prev.Tickets
+			}
+		} else {
+			proposal.Voters++
+		}
+		if voteProposalParam.Approve {
+			proposal.Approvals += tickets
+		} else {
+			proposal.Rejections += tickets
+		}
+		st.state.SetProposalVote(proposal.ID, st.msg.From(), &common.ProposalVote{Approve: voteProposalParam.Approve, Tickets: tickets})
+		if err := st.state.UpdateProposal(proposal); err != nil {
+			st.addErrorLog(common.VoteProposalFunc, voteProposalParam, common.NewFsnError(common.FsnErrStateUpdate, "unable to update proposal"))
+			return common.WrapFsnError(common.FsnErrStateUpdate, err)
+		}
+		st.addLog(common.VoteProposalFunc, voteProposalParam, common.NewKeyValue("Tickets", tickets))
+		return nil
+	case common.RevokeTicketFunc:
+		revokeTicketParam := common.RevokeTicketParam{}
+		rlp.DecodeBytes(param.Data, &revokeTicketParam)
+		ticket, err := checkRevokeTicket(st.state, &revokeTicketParam, st.msg.From(), height, timestamp)
+		if err != nil {
+			st.addErrorLog(common.RevokeTicketFunc, revokeTicketParam, err)
+			return err
+		}
+		if err := st.state.RemoveTicket(ticket.ID); err != nil {
+			st.addErrorLog(common.RevokeTicketFunc, revokeTicketParam, common.NewFsnError(common.FsnErrStateUpdate, "unable to remove ticket"))
+			return common.WrapFsnError(common.FsnErrStateUpdate, err)
+		}
+		// the refund is locked as long as the ticket was, less the penalty
+		refund := ticket.RevokeRefund()
+		st.state.AddTimeLockBalance(ticket.Owner, common.SystemAssetID, common.NewTimeLock(&common.TimeLockItem{
+			StartTime: common.MaxUint64(ticket.StartTime, timestamp),
+			EndTime:   ticket.ExpireTime,
+			Value:     refund,
+		}), height, timestamp)
+		st.addTransfer(common.TransferTicketRefund, common.FSNCallAddress, ticket.Owner, common.SystemAssetID, refund, common.MaxUint64(ticket.StartTime, timestamp), ticket.ExpireTime, ticket.ID)
+		st.addLog(common.RevokeTicketFunc, revokeTicketParam, common.NewKeyValue("TicketOwner", ticket.Owner), common.NewKeyValue("Refund", refund.String()))
+		return nil
+	case common.EscrowAssetFunc:
+		escrowAssetParam := common.EscrowAssetParam{}
+		rlp.DecodeBytes(param.Data, &escrowAssetParam)
+		if err := checkEscrowAsset(st.state, &escrowAssetParam, st.msg.From(), height, timestamp); err != nil {
+			st.addErrorLog(common.EscrowAssetFunc, escrowAssetParam, err)
+			return err
+		}
+		if err := st.checkAssetTransfer(escrowAssetParam.AssetID, st.msg.From(), escrowAssetParam.To); err != nil {
+			st.addErrorLog(common.EscrowAssetFunc, escrowAssetParam, err)
+			return err
+		}
+		escrow := common.Escrow{
+			ID:       GetUniqueHashFromMessageAt(st.msg, st.evm.BlockNumber),
+			Sender:   st.msg.From(),
+			To:       escrowAssetParam.To,
+			AssetID:  escrowAssetParam.AssetID,
+			Value:    escrowAssetParam.Value,
+			Deadline: escrowAssetParam.Deadline,
+			Time:     st.evm.Context.Time.Uint64(),
+		}
+		if err := st.state.AddEscrow(escrow); err != nil {
+			st.addErrorLog(common.EscrowAssetFunc, escrowAssetParam, common.NewFsnError(common.FsnErrStateUpdate, "unable to add escrow"))
+			return common.WrapFsnError(common.FsnErrStateUpdate, err)
+		}
+		st.state.SubBalance(st.msg.From(), escrow.AssetID, escrow.Value)
+		st.addTransfer(common.TransferEscrowLock, st.msg.From(), common.FSNCallAddress, escrow.AssetID, escrow.Value, 0, 0, escrow.ID)
+		st.addLog(common.EscrowAssetFunc, escrowAssetParam, common.NewKeyValue("EscrowID", escrow.ID))
+		return nil
+	case common.ClaimEscrowFunc:
+		claimEscrowParam := common.ClaimEscrowParam{}
+		rlp.DecodeBytes(param.Data, &claimEscrowParam)
+		escrow, err := checkClaimEscrow(st.state, &claimEscrowParam, st.msg.From(), height, timestamp)
+		if err != nil {
+			st.addErrorLog(common.ClaimEscrowFunc, claimEscrowParam, err)
+			return err
+		}
+		refund := st.msg.From() != escrow.To
+		if !refund {
+			if err := st.checkAssetTransfer(escrow.AssetID, escrow.To); err != nil {
+				st.addErrorLog(common.ClaimEscrowFunc, claimEscrowParam, err)
+				return err
+			}
+		}
+		if err := st.state.RemoveEscrow(escrow.ID); err != nil {
+			st.addErrorLog(common.ClaimEscrowFunc, claimEscrowParam, common.NewFsnError(common.FsnErrStateUpdate, "unable to remove escrow"))
+			return common.WrapFsnError(common.FsnErrStateUpdate, err)
+		}
+		st.state.AddBalance(st.msg.From(), escrow.AssetID, escrow.Value)
+		st.addTransfer(common.TransferEscrowRelease, common.FSNCallAddress, st.msg.From(), escrow.AssetID, escrow.Value, 0, 0, escrow.ID)
+		st.addLog(common.ClaimEscrowFunc, claimEscrowParam, common.NewKeyValue("AssetID", escrow.AssetID), common.NewKeyValue("Refund", refund))
+		return nil
+	case common.CreateStreamFunc:
+		createStreamParam := common.CreateStreamParam{}
+		rlp.DecodeBytes(param.Data, &createStreamParam)
+		if err := checkCreateStream(st.state, &createStreamParam, st.msg.From(), height, timestamp); err != nil {
+			st.addErrorLog(common.CreateStreamFunc, createStreamParam, err)
+			return err
+		}
+		if err := st.checkAssetTransfer(createStreamParam.AssetID, st.msg.From(), createStreamParam.To); err != nil {
+			st.addErrorLog(common.CreateStreamFunc, createStreamParam, err)
+			return err
+		}
+		stream := common.Stream{
+			ID:        GetUniqueHashFromMessageAt(st.msg, st.evm.BlockNumber),
+			Sender:    st.msg.From(),
+			To:        createStreamParam.To,
+			AssetID:   createStreamParam.AssetID,
+			Value:     createStreamParam.Value,
+			StartTime: createStreamParam.StartTime,
+			EndTime:   createStreamParam.EndTime,
+			Withdrawn: new(big.Int),
+		}
+		if err := st.state.AddStream(stream); err != nil {
+			st.addErrorLog(common.CreateStreamFunc, createStreamParam, common.NewFsnError(common.FsnErrStateUpdate, "unable to add stream"))
+			return common.WrapFsnError(common.FsnErrStateUpdate, err)
+		}
+		st.state.SubBalance(st.msg.From(), stream.AssetID, stream.Value)
+		st.addTransfer(common.TransferStreamLock, st.msg.From(), common.FSNCallAddress, stream.AssetID, stream.Value, 0, 0, stream.ID)
+		st.addLog(common.CreateStreamFunc, createStreamParam, common.NewKeyValue("StreamID", stream.ID))
+		return nil
+	case common.WithdrawStreamFunc:
+		withdrawStreamParam := common.WithdrawStreamParam{}
+		rlp.DecodeBytes(param.Data, &withdrawStreamParam)
+		stream, amount, err := checkWithdrawStream(st.state, &withdrawStreamParam, st.msg.From(), height, timestamp)
+		if err != nil {
+			st.addErrorLog(common.WithdrawStreamFunc, withdrawStreamParam, err)
+			return err
+		}
+		if err := st.checkAssetTransfer(stream.AssetID, stream.To); err != nil {
+			st.addErrorLog(common.WithdrawStreamFunc, withdrawStreamParam, err)
+			return err
+		}
+		stream.Withdrawn = new(big.Int).Add(stream.Withdrawn, amount)
+		if stream.Withdrawn.Cmp(stream.Value) >= 0 {
+			err = st.state.RemoveStream(stream.ID)
+		} else {
+			err = st.state.UpdateStream(*stream)
+		}
+		if err != nil {
+			st.addErrorLog(common.WithdrawStreamFunc, withdrawStreamParam, common.NewFsnError(common.FsnErrStateUpdate, "unable to update stream"))
+			return common.WrapFsnError(common.FsnErrStateUpdate, err)
+		}
+		st.state.AddBalance(stream.To, stream.AssetID, amount)
+		st.addTransfer(common.TransferStreamWithdraw, common.FSNCallAddress, stream.To, stream.AssetID, amount, 0, 0, stream.ID)
+		st.addLog(common.WithdrawStreamFunc, withdrawStreamParam, common.NewKeyValue("AssetID", stream.AssetID), common.NewKeyValue("Value", amount.String()))
+		return nil
+	case common.CreateConditionFunc:
+		createConditionParam := common.CreateConditionParam{}
+		rlp.DecodeBytes(param.Data, &createConditionParam)
+		if err := createConditionParam.Check(height); err != nil {
+			st.addErrorLog(common.CreateConditionFunc, createConditionParam, err)
+			return err
+		}
+		condition := common.Condition{
+			ID:      GetUniqueHashFromMessageAt(st.msg, st.evm.BlockNumber),
+			Creator: st.msg.From(),
+			Kind:    createConditionParam.Kind,
+			Hash:    createConditionParam.Hash,
+			Oracle:  createConditionParam.Oracle,
+		}
+		if err := st.state.AddCondition(condition); err != nil {
+			st.addErrorLog(common.CreateConditionFunc, createConditionParam, common.NewFsnError(common.FsnErrStateUpdate, "unable to add condition"))
+			return common.WrapFsnError(common.FsnErrStateUpdate, err)
+		}
+		st.addLog(common.CreateConditionFunc, createConditionParam, common.NewKeyValue("ConditionID", condition.ID))
+		return nil
+	case common.ResolveConditionFunc:
+		resolveConditionParam := common.ResolveConditionParam{}
+		rlp.DecodeBytes(param.Data, &resolveConditionParam)
+		condition, err := checkResolveCondition(st.state, &resolveConditionParam, st.msg.From(), height)
+		if err != nil {
+			st.addErrorLog(common.ResolveConditionFunc, resolveConditionParam, err)
+			return err
+		}
+		condition.ResolvedTime = timestamp
+		if err := st.state.UpdateCondition(*condition); err != nil {
+			st.addErrorLog(common.ResolveConditionFunc, resolveConditionParam, common.NewFsnError(common.FsnErrStateUpdate, "unable to update condition"))
+			return common.WrapFsnError(common.FsnErrStateUpdate, err)
+		}
+		st.addLog(common.ResolveConditionFunc, resolveConditionParam)
+		return nil
+	case common.ConditionalTransferFunc:
+		conditionalTransferParam := common.ConditionalTransferParam{}
+		rlp.DecodeBytes(param.Data, &conditionalTransferParam)
+		if err := checkConditionalTransfer(st.state, &conditionalTransferParam, st.msg.From(), height, timestamp); err != nil {
+			st.addErrorLog(common.ConditionalTransferFunc, conditionalTransferParam, err)
+			return err
+		}
+		if err := st.checkAssetTransfer(conditionalTransferParam.AssetID, st.msg.From(), conditionalTransferParam.To); err != nil {
+			st.addErrorLog(common.ConditionalTransferFunc, conditionalTransferParam, err)
+			return err
+		}
+		transfer := common.ConditionalTransfer{
+			ID:          GetUniqueHashFromMessageAt(st.msg, st.evm.BlockNumber),
+			Sender:      st.msg.From(),
+			To:          conditionalTransferParam.To,
+			AssetID:     conditionalTransferParam.AssetID,
+			Value:       conditionalTransferParam.Value,
+			ConditionID: conditionalTransferParam.ConditionID,
+			Deadline:    conditionalTransferParam.Deadline,
+		}
+		if err := st.state.AddConditionalTransfer(transfer); err != nil {
+			st.addErrorLog(common.ConditionalTransferFunc, conditionalTransferParam, common.NewFsnError(common.FsnErrStateUpdate, "unable to add conditional transfer"))
+			return common.WrapFsnError(common.FsnErrStateUpdate, err)
+		}
+		st.state.SubBalance(st.msg.From(), transfer.AssetID, transfer.Value)
+		st.addTransfer(common.TransferCondLock, st.msg.From(), common.FSNCallAddress, transfer.AssetID, transfer.Value, 0, 0, transfer.ID)
+		st.addLog(common.ConditionalTransferFunc, conditionalTransferParam, common.NewKeyValue("TransferID", transfer.ID))
+		return nil
+	case common.SettleConditionalFunc:
+		settleConditionalParam := common.SettleConditionalParam{}
+		rlp.DecodeBytes(param.Data, &settleConditionalParam)
+		transfer, receiver, err := checkSettleConditional(st.state, &settleConditionalParam, height, timestamp)
+		if err != nil {
+			st.addErrorLog(common.SettleConditionalFunc, settleConditionalParam, err)
+			return err
+		}
+		refund := receiver != transfer.To
+		if !refund {
+			if err := st.checkAssetTransfer(transfer.AssetID, transfer.To); err != nil {
+				st.addErrorLog(common.SettleConditionalFunc, settleConditionalParam, err)
+				return err
+			}
+		}
+		if err := st.state.RemoveConditionalTransfer(transfer.ID); err != nil {
+			st.addErrorLog(common.SettleConditionalFunc, settleConditionalParam, common.NewFsnError(common.FsnErrStateUpdate, "unable to remove conditional transfer"))
+			return common.WrapFsnError(common.FsnErrStateUpdate, err)
+		}
+		st.state.AddBalance(receiver, transfer.AssetID, transfer.Value)
+		st.addTransfer(common.TransferCondSettle, common.FSNCallAddress, receiver, transfer.AssetID, transfer.Value, 0, 0, transfer.ID)
+		st.addLog(common.SettleConditionalFunc, settleConditionalParam, common.NewKeyValue("AssetID", transfer.AssetID), common.NewKeyValue("Receiver", receiver), common.NewKeyValue("Refund", refund))
+		return nil
+	case common.AttestDepositFunc:
+		attestDepositParam := common.AttestDepositParam{}
+		rlp.DecodeBytes(param.Data, &attestDepositParam)
+		deposit, err := checkAttestDeposit(st.state, &attestDepositParam, st.msg.From(), height)
+		if err != nil {
+			st.addErrorLog(common.AttestDepositFunc, attestDepositParam, err)
+			return err
+		}
+		id := attestDepositParam.DepositID()
+		deposit.Attestations = append(deposit.Attestations, st.msg.From())
+		if len(deposit.Attestations) < common.BridgeAttestationThreshold() {
+			st.state.SetBridgeDeposit(id, deposit)
+			st.addLog(common.AttestDepositFunc, attestDepositParam, common.NewKeyValue("DepositID", id), common.NewKeyValue("Attestations", len(deposit.Attestations)))
+			return nil
+		}
+		// attested by the majority of the committee, mint the deposit
+		asset, _ := st.state.GetAsset(attestDepositParam.AssetID)
+		asset.Total = new(big.Int).Add(asset.Total, attestDepositParam.Value)
+		if err := st.state.UpdateAsset(asset); err != nil {
+			st.addErrorLog(common.AttestDepositFunc, attestDepositParam, common.NewFsnError(common.FsnErrStateUpdate, "error update asset"))
+			return common.WrapFsnError(common.FsnErrStateUpdate, err)
+		}
+		st.state.AddBalance(attestDepositParam.To, asset.ID, attestDepositParam.Value)
+		st.addTransfer(common.TransferMint, common.Address{}, attestDepositParam.To, asset.ID, attestDepositParam.Value, 0, 0, id)
+		st.addBridgeSupplyChange(&asset, attestDepositParam.To, true, attestDepositParam.Value)
+		deposit.Minted = height.Uint64()
+		st.state.SetBridgeDeposit(id, deposit)
+		st.addLog(common.AttestDepositFunc, attestDepositParam, common.NewKeyValue("DepositID", id), common.NewKeyValue("Attestations", len(deposit.Attestations)), common.NewKeyValue("Minted", true))
+		return nil
+	case common.BridgeWithdrawFunc:
+		bridgeWithdrawParam := common.BridgeWithdrawParam{}
+		rlp.DecodeBytes(param.Data, &bridgeWithdrawParam)
+		asset, err := checkBridgeWithdraw(st.state, &bridgeWithdrawParam, st.msg.From(), height)
+		if err != nil {
+			st.addErrorLog(common.BridgeWithdrawFunc, bridgeWithdrawParam, err)
+			return err
+		}
+		if err := st.checkAssetTransfer(asset.ID, st.msg.From()); err != nil {
+			st.addErrorLog(common.BridgeWithdrawFunc, bridgeWithdrawParam, err)
+			return err
+		}
+		asset.Total = new(big.Int).Sub(asset.Total, bridgeWithdrawParam.Value)
+		if err := st.state.UpdateAsset(*asset); err != nil {
+			st.addErrorLog(common.BridgeWithdrawFunc, bridgeWithdrawParam, common.NewFsnError(common.FsnErrStateUpdate, "error update asset"))
+			return common.WrapFsnError(common.FsnErrStateUpdate, err)
+		}
+		withdrawalID := GetUniqueHashFromMessageAt(st.msg, st.evm.BlockNumber)
+		st.state.SubBalance(st.msg.From(), asset.ID, bridgeWithdrawParam.Value)
+		st.addTransfer(common.TransferBurn, st.msg.From(), common.Address{}, asset.ID, bridgeWithdrawParam.Value, 0, 0, withdrawalID)
+		st.addBridgeSupplyChange(asset, st.msg.From(), false, bridgeWithdrawParam.Value)
+		st.addLog(common.BridgeWithdrawFunc, bridgeWithdrawParam, common.NewKeyValue("WithdrawalID", withdrawalID))
+		return nil
 	}
-	return fmt.Errorf("Unsupported")
+	return common.ErrUnsupported
+}
+
+// checkEscrowAsset checks that from can send the asset into the escrow in
+// the block number at the given time, shared by the pool and the state
+// transition. The transfer list of the asset is checked separately.
+func checkEscrowAsset(statedb vm.StateDB, param *common.EscrowAssetParam, from common.Address, number *big.Int, timestamp uint64) error {
+	if err := param.Check(number, timestamp); err != nil {
+		return err
+	}
+	if statedb.GetBalance(param.AssetID, from).Cmp(param.Value) < 0 {
+		return common.ErrNotEnoughAsset
+	}
+	return nil
+}
+
+// checkClaimEscrow checks that from can claim the escrow and returns it,
+// shared by the pool and the state transition. The recipient claims the
+// escrow before its deadline and the sender from the deadline on.
+func checkClaimEscrow(statedb vm.StateDB, param *common.ClaimEscrowParam, from common.Address, number *big.Int, timestamp uint64) (*common.Escrow, error) {
+	if err := param.Check(number); err != nil {
+		return nil, err
+	}
+	escrow, err := statedb.GetEscrow(param.EscrowID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case from == escrow.To && timestamp < escrow.Deadline:
+	case from == escrow.Sender && timestamp >= escrow.Deadline:
+	case from == escrow.To:
+		return nil, common.NewFsnError(common.FsnErrExpired, "escrow %v expired at %d", escrow.ID.Hex(), escrow.Deadline)
+	case from == escrow.Sender:
+		return nil, common.NewFsnError(common.FsnErrPending, "escrow %v can not be refunded before %d", escrow.ID.Hex(), escrow.Deadline)
+	default:
+		return nil, common.NewFsnError(common.FsnErrNotAllowed, "%v can not claim escrow %v", from.Hex(), escrow.ID.Hex())
+	}
+	return &escrow, nil
+}
+
+// checkCreateStream checks that from can stream the asset in the block number
+// at the given time, shared by the pool and the state transition. The transfer
+// list of the asset is checked separately.
+func checkCreateStream(statedb vm.StateDB, param *common.CreateStreamParam, from common.Address, number *big.Int, timestamp uint64) error {
+	if err := param.Check(number, timestamp); err != nil {
+		return err
+	}
+	if statedb.GetBalance(param.AssetID, from).Cmp(param.Value) < 0 {
+		return common.ErrNotEnoughAsset
+	}
+	return nil
+}
+
+// checkWithdrawStream checks that from is the recipient of the stream and
+// returns the stream with the value it can withdraw at the given time, shared
+// by the pool and the state transition.
+func checkWithdrawStream(statedb vm.StateDB, param *common.WithdrawStreamParam, from common.Address, number *big.Int, timestamp uint64) (*common.Stream, *big.Int, error) {
+	if err := param.Check(number); err != nil {
+		return nil, nil, err
+	}
+	stream, err := statedb.GetStream(param.StreamID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if from != stream.To {
+		return nil, nil, common.NewFsnError(common.FsnErrNotAllowed, "%v is not the recipient of stream %v", from.Hex(), stream.ID.Hex())
+	}
+	amount := stream.Withdrawable(timestamp)
+	if amount.Sign() <= 0 {
+		return nil, nil, common.NewFsnError(common.FsnErrNotEnoughBalance, "nothing to withdraw from stream %v", stream.ID.Hex())
+	}
+	return &stream, amount, nil
+}
+
+// checkResolveCondition checks that from can resolve the condition with the
+// given preimage or oracle signature and returns it, shared by the pool and the
+// state transition.
+func checkResolveCondition(statedb vm.StateDB, param *common.ResolveConditionParam, from common.Address, number *big.Int) (*common.Condition, error) {
+	if err := param.Check(number); err != nil {
+		return nil, err
+	}
+	condition, err := statedb.GetCondition(param.ConditionID)
+	if err != nil {
+		return nil, err
+	}
+	if condition.ResolvedTime != 0 {
+		return nil, common.NewFsnError(common.FsnErrAlreadySettled, "condition %v is already resolved", condition.ID.Hex())
+	}
+	switch condition.Kind {
+	case common.HashLockCondition:
+		if crypto.Keccak256Hash(param.Preimage) != condition.Hash {
+			return nil, common.NewFsnError(common.FsnErrMismatch, "preimage does not match condition %v", condition.ID.Hex())
+		}
+	case common.OracleCondition:
+		if from == condition.Oracle {
+			break
+		}
+		if len(param.Signature) == 0 {
+			return nil, common.NewFsnError(common.FsnErrNotAllowed, "condition %v can only be resolved by its oracle", condition.ID.Hex())
+		}
+		pub, err := crypto.SigToPub(condition.StatementHash().Bytes(), param.Signature)
+		if err != nil || crypto.PubkeyToAddress(*pub) != condition.Oracle {
+			return nil, common.NewFsnError(common.FsnErrMismatch, "statement of condition %v is not signed by its oracle", condition.ID.Hex())
+		}
+	default:
+		return nil, common.NewFsnError(common.FsnErrInvalidParam, "unknown condition kind %v", condition.Kind)
+	}
+	return &condition, nil
+}
+
+// checkConditionalTransfer checks that from can send the asset on the
+// condition in the block number at the given time, shared by the pool and the
+// state transition. The transfer list of the asset is checked separately.
+func checkConditionalTransfer(statedb vm.StateDB, param *common.ConditionalTransferParam, from common.Address, number *big.Int, timestamp uint64) error {
+	if err := param.Check(number, timestamp); err != nil {
+		return err
+	}
+	if _, err := statedb.GetCondition(param.ConditionID); err != nil {
+		return err
+	}
+	if statedb.GetBalance(param.AssetID, from).Cmp(param.Value) < 0 {
+		return common.ErrNotEnoughAsset
+	}
+	return nil
+}
+
+// checkSettleConditional checks that the conditional transfer can be settled
+// at the given time and returns it with the address it is paid to, shared by
+// the pool and the state transition. Anyone can settle a transfer, it is paid
+// to its recipient once its condition was resolved before the deadline and
+// refunded to its sender from the deadline on otherwise.
+func checkSettleConditional(statedb vm.StateDB, param *common.SettleConditionalParam, number *big.Int, timestamp uint64) (*common.ConditionalTransfer, common.Address, error) {
+	if err := param.Check(number); err != nil {
+		return nil, common.Address{}, err
+	}
+	transfer, err := statedb.GetConditionalTransfer(param.TransferID)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	condition, err := statedb.GetCondition(transfer.ConditionID)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	switch {
+	case condition.ResolvedBefore(transfer.Deadline):
+		return &transfer, transfer.To, nil
+	case timestamp >= transfer.Deadline:
+		return &transfer, transfer.Sender, nil
+	}
+	return nil, common.Address{}, common.NewFsnError(common.FsnErrPending, "condition of transfer %v is unresolved", transfer.ID.Hex())
+}
+
+// checkAttestDeposit checks that from is a member of the bridge committee
+// which did not attest the deposit yet and returns the deposit, shared by the
+// pool and the state transition.
+func checkAttestDeposit(statedb vm.StateDB, param *common.AttestDepositParam, from common.Address, number *big.Int) (*common.BridgeDeposit, error) {
+	if err := param.Check(number); err != nil {
+		return nil, err
+	}
+	if !common.IsBridgeCommitteeMember(from) {
+		return nil, common.NewFsnError(common.FsnErrNotAllowed, "%v is not a member of the bridge committee", from.Hex())
+	}
+	if _, err := statedb.GetAsset(param.AssetID); err != nil {
+		return nil, common.ErrAssetNotFound
+	}
+	deposit := statedb.GetBridgeDeposit(param.DepositID())
+	if deposit == nil {
+		return common.NewBridgeDeposit(param), nil
+	}
+	if deposit.Minted != 0 {
+		return nil, common.NewFsnError(common.FsnErrAlreadySettled, "deposit %v is already minted", param.DepositID().Hex())
+	}
+	if !deposit.Matches(param) {
+		return nil, common.NewFsnError(common.FsnErrMismatch, "deposit %v was attested with another asset, receiver or value", param.DepositID().Hex())
+	}
+	for _, member := range deposit.Attestations {
+		if member == from {
+			return nil, common.NewFsnError(common.FsnErrAlreadyApproved, "%v already attested deposit %v", from.Hex(), param.DepositID().Hex())
+		}
+	}
+	return deposit, nil
+}
+
+// checkBridgeWithdraw checks that from can burn the value of the bridge asset
+// and returns the asset, shared by the pool and the state transition. The
+// transfer list of the asset is checked separately.
+func checkBridgeWithdraw(statedb vm.StateDB, param *common.BridgeWithdrawParam, from common.Address, number *big.Int) (*common.Asset, error) {
+	if err := param.Check(number); err != nil {
+		return nil, err
+	}
+	asset, err := statedb.GetAsset(param.AssetID)
+	if err != nil {
+		return nil, common.ErrAssetNotFound
+	}
+	if statedb.GetBalance(param.AssetID, from).Cmp(param.Value) < 0 {
+		return nil, common.ErrNotEnoughAsset
+	}
+	return &asset, nil
+}
+
+// addBridgeSupplyChange records a mint or burn of the bridge in the supply
+// history of the asset
+func (st *StateTransition) addBridgeSupplyChange(asset *common.Asset, to common.Address, isInc bool, value *big.Int) {
+	if !common.IsAssetSupplyHistoryEnabled(st.evm.Context.BlockNumber) {
+		return
+	}
+	st.state.AddAssetSupplyChange(asset.ID, &common.AssetSupplyChange{
+		Height: st.evm.Context.BlockNumber.Uint64(),
+		Time:   st.evm.Context.Time.Uint64(),
+		Sender: st.msg.From(),
+		To:     to,
+		IsInc:  isInc,
+		Value:  value,
+		Total:  asset.Total,
+	})
+}
+
+// checkRevokeTicket checks that from can revoke the ticket and returns it,
+// shared by the pool and the state transition.
+func checkRevokeTicket(statedb vm.StateDB, param *common.RevokeTicketParam, from common.Address, number *big.Int, timestamp uint64) (*common.Ticket, error) {
+	if err := param.Check(number); err != nil {
+		return nil, err
+	}
+	ticket, err := statedb.GetTicket(param.TicketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.Owner != from {
+		return nil, common.NewFsnError(common.FsnErrNotAllowed, "%v is not the owner of ticket %v", from.Hex(), param.TicketID.Hex())
+	}
+	if ticket.IsInGenesis() {
+		return nil, common.ErrGenesisTicketRevoke
+	}
+	if ticket.ExpireTime <= timestamp {
+		return nil, common.NewFsnError(common.FsnErrExpired, "ticket %v is expired", param.TicketID.Hex())
+	}
+	return ticket, nil
+}
+
+// checkCreateProposal checks that from can create the proposal, which needs
+// a live ticket, and returns the number of live tickets.
+func checkCreateProposal(statedb vm.StateDB, param *common.CreateProposalParam, from common.Address, number *big.Int, timestamp uint64) (uint64, error) {
+	if err := param.Check(number); err != nil {
+		return 0, err
+	}
+	tickets, err := statedb.AllTickets()
+	if err != nil {
+		return 0, err
+	}
+	owned, total := tickets.NumberOfLiveTickets(from, timestamp)
+	if owned == 0 {
+		return 0, common.NewFsnError(common.FsnErrNotTicketHolder, "only ticket holders can create proposals")
+	}
+	return total, nil
+}
+
+// checkVoteProposal checks that from can vote on the proposal and returns it
+// with the weight of the vote, the tickets of from which were live at the
+// creation of the proposal. Tickets bought since then do not vote.
+func checkVoteProposal(statedb vm.StateDB, param *common.VoteProposalParam, from common.Address, number *big.Int, timestamp uint64) (common.Proposal, uint64, error) {
+	if err := param.Check(number); err != nil {
+		return common.Proposal{}, 0, err
+	}
+	proposal, err := statedb.GetProposal(param.ProposalID)
+	if err != nil {
+		return common.Proposal{}, 0, err
+	}
+	if number.Uint64() > proposal.EndHeight {
+		return common.Proposal{}, 0, common.NewFsnError(common.FsnErrExpired, "proposal voting ended at block %d", proposal.EndHeight)
+	}
+	tickets, err := statedb.AllTickets()
+	if err != nil {
+		return common.Proposal{}, 0, err
+	}
+	owned := tickets.NumberOfLiveTicketsAt(from, proposal.StartHeight, proposal.StartTime)
+	if owned == 0 {
+		return common.Proposal{}, 0, common.NewFsnError(common.FsnErrNotTicketHolder, "only holders of tickets live at the proposal creation can vote")
+	}
+	return proposal, owned, nil
+}
+
+// checkAssetOwner checks that from can act as the owner of the asset in the
+// call, shared by the pool and the state transition. The calls on an asset
+// owned by a key set are sent by each of the keys approving them.
+func checkAssetOwner(statedb vm.StateDB, asset *common.Asset, param *common.FSNCallParam, from common.Address, number *big.Int) error {
+	owners := statedb.GetAssetOwnerSet(asset.ID)
+	if owners == nil {
+		if asset.Owner != from {
+			return common.ErrNotAssetOwner
+		}
+		return nil
+	}
+	if !owners.IsOwner(from) {
+		return common.ErrNotAssetOwner
+	}
+	approvals := statedb.GetAssetOwnerApprovals(assetOwnerCallHash(asset.ID, param))
+	if approvals == nil || approvals.IsExpired(number.Uint64()) {
+		return nil
+	}
+	for _, addr := range approvals.Keys {
+		if addr == from {
+			return common.NewFsnError(common.FsnErrAlreadyApproved, "call already approved by %v", from.Hex())
+		}
+	}
+	return nil
+}
+
+// assetOwnerCallHash identifies an owner call on an asset
+func assetOwnerCallHash(assetID common.Hash, param *common.FSNCallParam) common.Hash {
+	return common.Keccak256Hash(assetID[:], []byte{byte(param.Func)}, param.Data)
+}
+
+// approveAssetOwnerCall records the approval of the owner call on the asset
+// by the sender and reports whether the owner approved the call, together
+// with the number of approvals. The approvals are deleted once it did, and
+// start over when they expired before reaching the threshold.
+func (st *StateTransition) approveAssetOwnerCall(asset *common.Asset, param *common.FSNCallParam) (bool, int) {
+	owners := st.state.GetAssetOwnerSet(asset.ID)
+	if owners == nil {
+		return true, 1
+	}
+	number := st.evm.BlockNumber.Uint64()
+	hash := assetOwnerCallHash(asset.ID, param)
+	approvals := st.state.GetAssetOwnerApprovals(hash)
+	if approvals == nil || approvals.IsExpired(number) {
+		approvals = &common.AssetOwnerApprovals{Height: number}
+	}
+	approvals.Keys = append(approvals.Keys, st.msg.From())
+	if uint64(len(approvals.Keys)) < owners.Threshold {
+		st.state.SetAssetOwnerApprovals(hash, approvals)
+		return false, len(approvals.Keys)
+	}
+	st.state.SetAssetOwnerApprovals(hash, nil)
+	return true, len(approvals.Keys)
+}
+
+// checkSetFsnCallFee checks that from can approve the fee change in the block
+// number, shared by the pool and the state transition.
+func checkSetFsnCallFee(statedb vm.StateDB, param *common.SetFsnCallFeeParam, from common.Address, number *big.Int) error {
+	if err := param.Check(number); err != nil {
+		return err
+	}
+	if !common.IsFeeGovernor(from) {
+		return common.NewFsnError(common.FsnErrNotAllowed, "%v is not a fee governor", from.Hex())
+	}
+	if entry := statedb.GetFsnCallFeeEntry(param.Func); entry != nil && entry.Height > number.Uint64() {
+		return common.NewFsnError(common.FsnErrPending, "fee change of %v pending until block %d", param.Func.Name(), entry.Height)
+	}
+	for _, addr := range statedb.GetFsnCallFeeApprovals(param.Hash()) {
+		if addr == from {
+			return common.NewFsnError(common.FsnErrAlreadyApproved, "fee change already approved by %v", from.Hex())
+		}
+	}
+	return nil
+}
+
+// genAsset generates the asset described by param, owned by the sender. The
+// asset is logged as a plain GenAsset so that asset tracking needs no changes.
+func (st *StateTransition) genAsset(param common.GenAssetParam, restriction common.AssetTransferRestriction, owners *common.AssetOwnerSet) error {
+	height := st.evm.Context.BlockNumber
+
+	asset := param.ToAsset()
+	asset.ID = GetUniqueHashFromMessageAt(st.msg, st.evm.BlockNumber)
+	asset.Owner = st.msg.From()
+	if owners != nil {
+		asset.Owner = owners.Address()
+	}
+	registerSymbol := common.IsAssetSymbolRegistryEnabled(height)
+	if registerSymbol {
+		if id, ok := st.state.GetAssetIDBySymbol(asset.Symbol); ok {
+			err := common.NewFsnError(common.FsnErrAssetExists, "asset symbol already registered by %s", id.String())
+			st.addErrorLog(common.GenAssetFunc, param, err)
+			return err
+		}
+	}
+	if err := st.state.GenAsset(asset); err != nil {
+		st.addErrorLog(common.GenAssetFunc, param, common.NewFsnError(common.FsnErrStateUpdate, "unable to gen asset"))
+		return common.WrapFsnError(common.FsnErrStateUpdate, err)
+	}
+	if registerSymbol {
+		st.state.RegisterAssetSymbol(asset.Symbol, asset.ID)
+	}
+	st.state.AddBalance(st.msg.From(), asset.ID, asset.Total)
+	st.addTransfer(common.TransferMint, common.Address{}, st.msg.From(), asset.ID, asset.Total, 0, 0, asset.ID)
+	if owners != nil {
+		st.state.SetAssetOwnerSet(asset.ID, owners)
+		st.addLog(common.GenAssetFunc, param, common.NewKeyValue("AssetID", asset.ID), common.NewKeyValue("Owner", asset.Owner), common.NewKeyValue("Threshold", owners.Threshold))
+		return nil
+	}
+	if restriction != common.AssetTransferUnrestricted {
+		st.state.SetAssetTransferRestriction(asset.ID, restriction)
+		st.addLog(common.GenAssetFunc, param, common.NewKeyValue("AssetID", asset.ID), common.NewKeyValue("TransferRestriction", restriction.String()))
+		return nil
+	}
+	st.addLog(common.GenAssetFunc, param, common.NewKeyValue("AssetID", asset.ID))
+	return nil
+}
+
+// checkAssetTransfer returns an error if the transfer list of the asset does
+// not allow one of the addresses to send or receive it
+func (st *StateTransition) checkAssetTransfer(assetID common.Hash, addrs ...common.Address) error {
+	if !common.IsAssetTransferRestrictionEnabled(st.evm.Context.BlockNumber) {
+		return nil
+	}
+	if assetID == common.SystemAssetID || assetID == common.OwnerUSANAssetID {
+		return nil
+	}
+	for _, addr := range addrs {
+		if !st.state.IsAssetTransferAllowed(assetID, addr) {
+			return common.NewFsnError(common.FsnErrNotAllowed, "asset %v transfer not allowed for %v", assetID.Hex(), addr.Hex())
+		}
+	}
+	return nil
+}
+
+// checkSwapTransfers checks that the swap participants may send and receive
+// all assets of a swap
+func (st *StateTransition) checkSwapTransfers(assetIDs []common.Hash, addrs ...common.Address) error {
+	for _, assetID := range assetIDs {
+		if err := st.checkAssetTransfer(assetID, addrs...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buyTicket buys a ticket for from, paid from its time lock balance or, unless
+// timeLockOnly is set, from its asset balance. data is the encoded BuyTicketParam.
+func (st *StateTransition) buyTicket(from common.Address, data []byte, timeLockOnly bool, keyValues ...*common.KeyValue) error {
+	height := st.evm.Context.BlockNumber
+	timestamp := st.evm.Context.ParentTime.Uint64()
+
+	hash := st.evm.GetHash(height.Uint64() - 1)
+	id := crypto.Keccak256Hash(from[:], hash[:])
+
+	if st.state.IsTicketExist(id) {
+		st.addErrorLog(common.BuyTicketFunc, data, common.ErrTicketExists)
+		return common.NewFsnError(common.FsnErrTicketExists, "%s Ticket already exist", id.String())
+	}
+
+	buyTicketParam := common.BuyTicketParam{}
+	rlp.DecodeBytes(data, &buyTicketParam)
+
+	// check buy ticket param
+	if common.IsHardFork(2, height) {
+		if err := buyTicketParam.Check(height, timestamp); err != nil {
+			st.addErrorLog(common.BuyTicketFunc, data, err)
+			return err
+		}
+	} else {
+		if err := buyTicketParam.Check(height, 0); err != nil {
+			st.addErrorLog(common.BuyTicketFunc, data, err)
+			return err
+		}
+	}
+
+	// tickets of several ticket prices count as as many tickets
+	weight := buyTicketParam.Weight(height)
+	limit := st.evm.ChainConfig().TicketRateLimitAt(height)
+	if limit != nil && st.state.GetTicketPurchases(from, limit.WindowOf(height.Uint64()))+weight > limit.MaxTickets {
+		st.addErrorLog(common.BuyTicketFunc, data, common.NewFsnError(common.FsnErrLimitReached, "ticket purchase limit reached"))
+		return common.NewFsnError(common.FsnErrLimitReached, "%v can not buy more than %d tickets in the current window of %d blocks", from.Hex(), limit.MaxTickets, limit.Window)
+	}
+
+	start := buyTicketParam.Start
+	end := buyTicketParam.End
+	value := new(big.Int).Mul(common.TicketPrice(height), new(big.Int).SetUint64(weight))
+	var needValue *common.TimeLock
+
+	needValue = common.NewTimeLock(&common.TimeLockItem{
+		StartTime: common.MaxUint64(start, timestamp),
+		EndTime:   end,
+		Value:     value,
+	})
+	if err := needValue.IsValid(); err != nil {
+		st.addErrorLog(common.BuyTicketFunc, data, err)
+		return err
+	}
+
+	ticket := common.Ticket{
+		Owner: from,
+		TicketBody: common.TicketBody{
+			ID:         id,
+			Height:     height.Uint64(),
+			StartTime:  start,
+			ExpireTime: end,
+		},
+	}
+	ticket.SetWeight(weight)
+
+	useAsset := false
+	if st.state.GetTimeLockBalance(common.SystemAssetID, from).Cmp(needValue) < 0 {
+		if timeLockOnly {
+			st.addErrorLog(common.BuyTicketFunc, data, common.ErrNotEnoughTimeLock)
+			return common.ErrNotEnoughTimeLock
+		}
+		if st.state.GetBalance(common.SystemAssetID, from).Cmp(value) < 0 {
+			st.addErrorLog(common.BuyTicketFunc, data, common.ErrNotEnoughTimeLockOrAsset)
+			return common.ErrNotEnoughTimeLockOrAsset
+		}
+		useAsset = true
+	}
+
+	if useAsset {
+		st.state.SubBalance(from, common.SystemAssetID, value)
+
+		totalValue := common.NewTimeLock(&common.TimeLockItem{
+			StartTime: timestamp,
+			EndTime:   common.TimeLockForever,
+			Value:     value,
+		})
+		surplusValue := new(common.TimeLock).Sub(totalValue, needValue)
+		if !surplusValue.IsEmpty() {
+			st.state.AddTimeLockBalance(from, common.SystemAssetID, surplusValue, height, timestamp)
+		}
+
+	} else {
+		st.state.SubTimeLockBalance(from, common.SystemAssetID, needValue, height, timestamp)
+	}
+
+	if err := st.state.AddTicket(ticket); err != nil {
+		st.addErrorLog(common.BuyTicketFunc, data, common.NewFsnError(common.FsnErrStateUpdate, "unable to add ticket"))
+		return common.WrapFsnError(common.FsnErrStateUpdate, err)
+	}
+	if limit != nil {
+		window := limit.WindowOf(height.Uint64())
+		st.state.SetTicketPurchases(from, window, st.state.GetTicketPurchases(from, window)+weight)
+	}
+	st.addTransfer(common.TransferTicketLock, from, common.FSNCallAddress, common.SystemAssetID, value, common.MaxUint64(start, timestamp), end, ticket.ID)
+	keyValues = append([]*common.KeyValue{common.NewKeyValue("TicketID", ticket.ID), common.NewKeyValue("TicketOwner", ticket.Owner)}, keyValues...)
+	st.addLog(common.BuyTicketFunc, data, keyValues...)
+	return nil
+}
+
+// typedCallMessage is the message of a typed call, sent by the signer of the
+// typed data instead of the sender of the transaction
+type typedCallMessage struct {
+	Message
+	from common.Address
+}
+
+func (m *typedCallMessage) From() common.Address { return m.from }
+
+// typedCallSigner checks the typed call and returns the address which signed it
+func (st *StateTransition) typedCallSigner(param *common.TypedCallParam, height *big.Int, timestamp uint64) (common.Address, error) {
+	if err := param.Check(height, timestamp); err != nil {
+		return common.Address{}, err
+	}
+	typedData, err := types.NewFSNTypedData(st.evm.ChainConfig().ChainID, &param.Call, param.Nonce, param.Deadline)
+	if err != nil {
+		return common.Address{}, err
+	}
+	signer, err := typedData.Signer(param.Signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if nonce := st.state.GetTypedCallNonce(signer); nonce != param.Nonce {
+		return common.Address{}, common.NewFsnError(common.FsnErrMismatch, "typed call nonce mismatch: have %d, want %d", param.Nonce, nonce)
+	}
+	return signer, nil
+}
+
+// removeSwap marks the swap (or multi swap if addr is the multi swap key address)
+// as removed and, since the swap deletion fork, schedules its deletion from the
+// state after the retention period
+func (st *StateTransition) removeSwap(addr common.Address, id common.Hash) error {
+	var err error
+	if addr == common.MultiSwapKeyAddress {
+		err = st.state.RemoveMultiSwap(id)
+	} else {
+		err = st.state.RemoveSwap(id)
+	}
+	if err != nil {
+		return err
+	}
+	if height := st.evm.Context.BlockNumber; common.IsSwapDeletionEnabled(height) {
+		st.state.ScheduleSwapDeletion(addr, id, height.Uint64()+common.SwapRetentionBlocks)
+	}
+	return nil
+}
+
+// addTransfer passes an internal transfer of the FSN call to the tracer
+func (st *StateTransition) addTransfer(typ string, from, to common.Address, assetID common.Hash, value *big.Int, start, end uint64, ref common.Hash) {
+	if st.evm == nil {
+		return
+	}
+	st.evm.CaptureFsnTransfer(&common.InternalTransfer{
+		Type:      typ,
+		From:      from,
+		To:        to,
+		AssetID:   assetID,
+		Value:     new(big.Int).Set(value),
+		StartTime: start,
+		EndTime:   end,
+		Ref:       ref,
+	})
+}
+
+// addErrorLog logs the failure of an FSN call, with the code of the failure
+// once the logs carry it
+func (st *StateTransition) addErrorLog(typ common.FSNCallFunc, value interface{}, err error, keyValues ...*common.KeyValue) {
+	keyValues = append(keyValues, common.NewKeyValue("Error", err.Error()))
+	if common.IsFsnErrorCodeLogEnabled(st.evm.BlockNumber) {
+		keyValues = append(keyValues, common.NewKeyValue("ErrorCode", int(common.FsnErrorCodeOf(err))))
+	}
+	st.addLog(typ, value, keyValues...)
 }
 
 func (st *StateTransition) addLog(typ common.FSNCallFunc, value interface{}, keyValues ...*common.KeyValue) {
@@ -1095,13 +2119,36 @@ func (st *StateTransition) addLog(typ common.FSNCallFunc, value interface{}, key
 
 	data, _ := json.Marshal(maps)
 
-	topic := common.Hash{}
-	topic[common.HashLength-1] = (uint8)(typ)
-
 	st.evm.StateDB.AddLog(&types.Log{
 		Address:     common.FSNCallAddress,
-		Topics:      []common.Hash{topic},
+		Topics:      []common.Hash{typ.Topic()},
 		Data:        data,
 		BlockNumber: st.evm.BlockNumber.Uint64(),
 	})
+	if common.IsIndexedFsnLogEnabled(st.evm.BlockNumber) {
+		// the call log keeps its single topic shape for the consumers
+		// reading it, the index log following it lets bloom filters find
+		// the calls of a sender or of an object
+		st.evm.StateDB.AddLog(&types.Log{
+			Address:     common.FSNCallAddress,
+			Topics:      []common.Hash{common.FsnCallIndexTopic, typ.Topic(), st.msg.From().Hash(), fsnLogObjectID(maps)},
+			BlockNumber: st.evm.BlockNumber.Uint64(),
+		})
+	}
+}
+
+// fsnLogObjectIDKeys are the log fields naming the object an FSN call acts
+// on, in the order they are preferred as the indexed object topic
+var fsnLogObjectIDKeys = []string{
+	"AssetID", "SwapID", "TicketID", "EscrowID", "StreamID", "ConditionID",
+	"TransferID", "DepositID", "WithdrawalID", "ProposalID",
+}
+
+func fsnLogObjectID(maps map[string]interface{}) common.Hash {
+	for _, key := range fsnLogObjectIDKeys {
+		if id, ok := maps[key].(common.Hash); ok {
+			return id
+		}
+	}
+	return common.Hash{}
 }
@@ -0,0 +1,462 @@
+package core
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/core/vm"
+	"github.com/FusionFoundation/go-fusion/params"
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+func TestCheckAttestDeposit(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+	defer func(committee []common.Address, assets []common.Hash) {
+		common.DEVNET_BRIDGE_COMMITTEE, common.DEVNET_BRIDGE_ASSETS = committee, assets
+	}(common.DEVNET_BRIDGE_COMMITTEE, common.DEVNET_BRIDGE_ASSETS)
+
+	var (
+		first, second = common.HexToAddress("0x01"), common.HexToAddress("0x02")
+		asset         = common.Asset{ID: common.HexToHash("0x10"), Owner: first, Name: "Bridged", Symbol: "BRG", Total: new(big.Int)}
+		number        = big.NewInt(1)
+	)
+	common.DEVNET_BRIDGE_COMMITTEE = []common.Address{first, second, common.HexToAddress("0x03")}
+	common.DEVNET_BRIDGE_ASSETS = []common.Hash{asset.ID}
+
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	statedb.GenAsset(asset)
+
+	param := common.AttestDepositParam{AssetID: asset.ID, ChainID: 1, TxHash: common.HexToHash("0x20"), To: common.HexToAddress("0x30"), Value: big.NewInt(100)}
+	deposit, err := checkAttestDeposit(statedb, &param, first, number)
+	if err != nil {
+		t.Fatalf("first attestation rejected: %v", err)
+	}
+	deposit.Attestations = append(deposit.Attestations, first)
+	statedb.SetBridgeDeposit(param.DepositID(), deposit)
+
+	// the second member attests another receiver for the same deposit
+	other := param
+	other.To = common.HexToAddress("0x31")
+	if _, err := checkAttestDeposit(statedb, &other, second, number); err == nil {
+		t.Fatal("attestation of another receiver accepted")
+	}
+	other = param
+	other.Value = big.NewInt(101)
+	if _, err := checkAttestDeposit(statedb, &other, second, number); err == nil {
+		t.Fatal("attestation of another value accepted")
+	}
+	if _, err := checkAttestDeposit(statedb, &param, first, number); err == nil {
+		t.Fatal("second attestation of the same member accepted")
+	}
+	if deposit, err := checkAttestDeposit(statedb, &param, second, number); err != nil || len(deposit.Attestations) != 1 {
+		t.Fatalf("matching attestation rejected: %v", err)
+	}
+}
+
+func TestFsnCallBeforeFork(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	evm := vm.NewEVM(vm.Context{BlockNumber: big.NewInt(1), Time: big.NewInt(1000), ParentTime: big.NewInt(990)}, statedb, params.TestChainConfig, vm.Config{})
+	msg := types.NewMessage(common.HexToAddress("0x01"), &common.FSNCallAddress, 0, new(big.Int), 100000, big.NewInt(1), nil, false)
+	st := NewStateTransition(evm, msg, new(GasPool).AddGas(100000))
+
+	for _, fn := range []common.FSNCallFunc{common.TypedCallFunc, common.GenRestrictedAssetFunc, common.CreateProposalFunc, common.EscrowAssetFunc, common.CreateStreamFunc, common.ConditionalTransferFunc} {
+		param := &common.FSNCallParam{Func: fn}
+		if err := st.handleFsnCall(param); err == nil || err.Error() != "Unsupported" {
+			t.Errorf("%v before its fork: have error %v, want Unsupported", fn.Name(), err)
+		}
+		if fee := fsnCallFee(param, evm.BlockNumber, statedb); fee.Sign() != 0 {
+			t.Errorf("%v pays %v before its fork", fn.Name(), fee)
+		}
+	}
+	if logs := statedb.Logs(); len(logs) != 0 {
+		t.Errorf("unsupported calls logged %d times", len(logs))
+	}
+}
+
+func TestSetFsnCallFee(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	governors := common.GetFeeGovernors()
+	if len(governors) < 2 {
+		t.Fatalf("devnet has %d fee governors", len(governors))
+	}
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	number := big.NewInt(10)
+	param := common.SetFsnCallFeeParam{Func: common.GenAssetFunc, Fee: big.NewInt(5), Height: 20}
+	data, _ := rlp.EncodeToBytes(&param)
+
+	call := func(from common.Address) error {
+		evm := vm.NewEVM(vm.Context{BlockNumber: number, Time: big.NewInt(1000), ParentTime: big.NewInt(990)}, statedb, params.TestChainConfig, vm.Config{})
+		msg := types.NewMessage(from, &common.FSNCallAddress, 0, new(big.Int), 100000, big.NewInt(1), nil, false)
+		st := NewStateTransition(evm, msg, new(GasPool).AddGas(100000))
+		return st.handleFsnCall(&common.FSNCallParam{Func: common.SetFsnCallFeeFunc, Data: data})
+	}
+	if err := call(common.HexToAddress("0x01")); common.FsnErrorCodeOf(err) != common.FsnErrNotAllowed {
+		t.Fatalf("fee change of a non governor: have %v, want NotAllowed", err)
+	}
+	for i := 0; i < common.FeeGovernanceThreshold()-1; i++ {
+		if err := call(governors[i]); err != nil {
+			t.Fatalf("approval of governor %d rejected: %v", i, err)
+		}
+		if err := call(governors[i]); err == nil {
+			t.Fatalf("second approval of governor %d accepted", i)
+		}
+		if entry := statedb.GetFsnCallFeeEntry(param.Func); entry != nil {
+			t.Fatalf("fee changed with %d approvals", i+1)
+		}
+	}
+	if err := call(governors[common.FeeGovernanceThreshold()-1]); err != nil {
+		t.Fatalf("last approval rejected: %v", err)
+	}
+	if approvals := statedb.GetFsnCallFeeApprovals(param.Hash()); len(approvals) != 0 {
+		t.Errorf("approvals of an applied change are kept: %v", approvals)
+	}
+	before := common.GetFsnCallFee(&common.FSNCallAddress, param.Func)
+	if fee := common.GetFsnCallFeeAt(&common.FSNCallAddress, param.Func, big.NewInt(19), statedb); fee.Cmp(before) != 0 {
+		t.Errorf("fee before the change height: have %v, want %v", fee, before)
+	}
+	if fee := common.GetFsnCallFeeAt(&common.FSNCallAddress, param.Func, big.NewInt(20), statedb); fee.Cmp(param.Fee) != 0 {
+		t.Errorf("fee at the change height: have %v, want %v", fee, param.Fee)
+	}
+}
+
+func TestFsnCallIndexLog(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	evm := vm.NewEVM(vm.Context{BlockNumber: big.NewInt(1), Time: big.NewInt(1000), ParentTime: big.NewInt(990)}, statedb, params.TestChainConfig, vm.Config{})
+	from, swapID := common.HexToAddress("0x01"), common.HexToHash("0x10")
+	fn := common.FSNCallFunc(common.RecallSwapFunc)
+	msg := types.NewMessage(from, &common.FSNCallAddress, 0, new(big.Int), 100000, big.NewInt(1), nil, false)
+	st := NewStateTransition(evm, msg, new(GasPool).AddGas(100000))
+
+	st.addLog(fn, common.RecallSwapParam{SwapID: swapID})
+	logs := statedb.Logs()
+	if len(logs) != 2 {
+		t.Fatalf("have %d logs, want the call log and its index log", len(logs))
+	}
+	if len(logs[0].Topics) != 1 || logs[0].Topics[0] != fn.Topic() {
+		t.Errorf("call log topics changed: %v", logs[0].Topics)
+	}
+	want := []common.Hash{common.FsnCallIndexTopic, fn.Topic(), from.Hash(), swapID}
+	if len(logs[1].Topics) != len(want) {
+		t.Fatalf("index log topics: have %v, want %v", logs[1].Topics, want)
+	}
+	for i := range want {
+		if logs[1].Topics[i] != want[i] {
+			t.Errorf("index log topic %d: have %x, want %x", i, logs[1].Topics[i], want[i])
+		}
+	}
+	if _, ok := common.FsnCallFuncOfTopic(logs[1].Topics[0]); ok {
+		t.Errorf("index log read as an FSN call log")
+	}
+	if have, ok := common.FsnCallFuncOfTopic(logs[0].Topics[0]); !ok || have != fn {
+		t.Errorf("call log read as %v, ok %v", have, ok)
+	}
+}
+
+func TestFsnCallErrorCode(t *testing.T) {
+	param := common.SendAssetParam{AssetID: common.SystemAssetID, To: common.HexToAddress("0x02"), Value: big.NewInt(1)}
+	data, _ := rlp.EncodeToBytes(&param)
+
+	for _, devnet := range []bool{false, true} {
+		common.UseDevnetRule = devnet
+		statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		evm := vm.NewEVM(vm.Context{BlockNumber: big.NewInt(1), Time: big.NewInt(1000), ParentTime: big.NewInt(990)}, statedb, params.TestChainConfig, vm.Config{})
+		msg := types.NewMessage(common.HexToAddress("0x01"), &common.FSNCallAddress, 0, new(big.Int), 100000, big.NewInt(1), nil, false)
+		st := NewStateTransition(evm, msg, new(GasPool).AddGas(100000))
+
+		err := st.handleFsnCall(&common.FSNCallParam{Func: common.SendAssetFunc, Data: data})
+		if code := common.FsnErrorCodeOf(err); code != common.FsnErrNotEnoughAsset {
+			t.Fatalf("devnet %v: have error %v (%s), want NotEnoughAsset", devnet, err, code.Name())
+		}
+		logs := statedb.Logs()
+		if len(logs) == 0 {
+			t.Fatalf("devnet %v: failed call not logged", devnet)
+		}
+		fields := make(map[string]interface{})
+		if err := json.Unmarshal(logs[0].Data, &fields); err != nil {
+			t.Fatal(err)
+		}
+		if fields["Error"] != common.ErrNotEnoughAsset.Message {
+			t.Errorf("devnet %v: logged error %v, want %q", devnet, fields["Error"], common.ErrNotEnoughAsset.Message)
+		}
+		code, ok := fields["ErrorCode"].(float64)
+		if devnet && (!ok || common.FsnErrorCode(code) != common.FsnErrNotEnoughAsset) {
+			t.Errorf("logged error code %v, want %d", fields["ErrorCode"], common.FsnErrNotEnoughAsset)
+		}
+		if !devnet && ok {
+			t.Errorf("error code logged before the fork")
+		}
+	}
+	common.UseDevnetRule = false
+}
+
+func TestVoteProposalWeight(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	proposer, late := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	addTicket := func(owner common.Address, id byte, height uint64) {
+		ticket := common.Ticket{Owner: owner, TicketBody: common.TicketBody{ID: common.BytesToHash([]byte{id}), Height: height, StartTime: 900, ExpireTime: 100000}}
+		if err := statedb.AddTicket(ticket); err != nil {
+			t.Fatal(err)
+		}
+	}
+	call := func(from common.Address, number int64, fn common.FSNCallFunc, param interface{}) (types.Message, error) {
+		data, _ := rlp.EncodeToBytes(param)
+		evm := vm.NewEVM(vm.Context{BlockNumber: big.NewInt(number), Time: big.NewInt(1000 + number), ParentTime: big.NewInt(990 + number)}, statedb, params.TestChainConfig, vm.Config{})
+		msg := types.NewMessage(from, &common.FSNCallAddress, 0, new(big.Int), 100000, big.NewInt(1), nil, false)
+		st := NewStateTransition(evm, msg, new(GasPool).AddGas(100000))
+		return msg, st.handleFsnCall(&common.FSNCallParam{Func: fn, Data: data})
+	}
+
+	addTicket(proposer, 1, 5)
+	msg, err := call(proposer, 10, common.CreateProposalFunc, &common.CreateProposalParam{Title: "test", EndHeight: 10 + common.MinProposalVotingBlocks})
+	if err != nil {
+		t.Fatalf("proposal rejected: %v", err)
+	}
+	id := GetUniqueHashFromMessageAt(msg, big.NewInt(10))
+
+	// tickets bought after the creation do not weigh on the vote
+	addTicket(proposer, 2, 11)
+	addTicket(late, 3, 11)
+	if _, err := call(late, 12, common.VoteProposalFunc, &common.VoteProposalParam{ProposalID: id, Approve: true}); common.FsnErrorCodeOf(err) != common.FsnErrNotTicketHolder {
+		t.Fatalf("vote with a ticket bought after the creation: have %v, want NotTicketHolder", err)
+	}
+	if _, err := call(proposer, 12, common.VoteProposalFunc, &common.VoteProposalParam{ProposalID: id, Approve: true}); err != nil {
+		t.Fatalf("vote rejected: %v", err)
+	}
+	proposal, err := statedb.GetProposal(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proposal.TotalTickets != 1 || proposal.Approvals != 1 || proposal.Voters != 1 {
+		t.Errorf("have %d of %d tickets approving from %d voters, want 1 of 1 from 1", proposal.Approvals, proposal.TotalTickets, proposal.Voters)
+	}
+}
+
+func TestAssetOwnerApprovalsExpire(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	keys := []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02"), common.HexToAddress("0x03")}
+	owners := &common.AssetOwnerSet{Threshold: 2, Owners: keys}
+	asset := common.Asset{ID: common.HexToHash("0x10"), Owner: owners.Address(), Name: "Owned", Symbol: "OWN", Total: new(big.Int), CanChange: true}
+	statedb.GenAsset(asset)
+	statedb.SetAssetOwnerSet(asset.ID, owners)
+
+	change := common.AssetValueChangeExParam{AssetID: asset.ID, To: keys[0], Value: big.NewInt(10), IsInc: true}
+	data, _ := rlp.EncodeToBytes(&change)
+	param := &common.FSNCallParam{Func: common.AssetValueChangeFunc, Data: data}
+	hash := assetOwnerCallHash(asset.ID, param)
+	call := func(from common.Address, number uint64) error {
+		evm := vm.NewEVM(vm.Context{BlockNumber: new(big.Int).SetUint64(number), Time: big.NewInt(1000), ParentTime: big.NewInt(990)}, statedb, params.TestChainConfig, vm.Config{})
+		msg := types.NewMessage(from, &common.FSNCallAddress, 0, new(big.Int), 100000, big.NewInt(1), nil, false)
+		st := NewStateTransition(evm, msg, new(GasPool).AddGas(100000))
+		return st.handleFsnCall(param)
+	}
+
+	if err := call(keys[0], 10); err != nil {
+		t.Fatalf("first approval rejected: %v", err)
+	}
+	if err := call(keys[0], 11); common.FsnErrorCodeOf(err) != common.FsnErrAlreadyApproved {
+		t.Fatalf("second approval of the same key: have %v, want AlreadyApproved", err)
+	}
+	// the approval expired, the call starts over
+	expired := uint64(10 + common.AssetOwnerApprovalBlocks + 1)
+	if err := call(keys[1], expired); err != nil {
+		t.Fatalf("approval after the expiry rejected: %v", err)
+	}
+	if balance := statedb.GetBalance(asset.ID, keys[0]); balance.Sign() != 0 {
+		t.Fatalf("call executed with an expired approval, balance %v", balance)
+	}
+	if approvals := statedb.GetAssetOwnerApprovals(hash); approvals == nil || approvals.Height != expired || len(approvals.Keys) != 1 {
+		t.Fatalf("approvals after the expiry: %+v", approvals)
+	}
+	if err := call(keys[0], expired+1); err != nil {
+		t.Fatalf("approval reaching the threshold rejected: %v", err)
+	}
+	if balance := statedb.GetBalance(asset.ID, keys[0]); balance.Cmp(change.Value) != 0 {
+		t.Fatalf("balance after the call: have %v, want %v", balance, change.Value)
+	}
+	if approvals := statedb.GetAssetOwnerApprovals(hash); approvals != nil {
+		t.Errorf("approvals of the executed call are kept: %+v", approvals)
+	}
+}
+
+// transferRecorder records the internal transfers of FSN calls
+type transferRecorder struct {
+	vm.Tracer
+	transfers []*common.InternalTransfer
+}
+
+func (r *transferRecorder) CaptureFsnTransfer(transfer *common.InternalTransfer) {
+	r.transfers = append(r.transfers, transfer)
+}
+
+func TestEscrowTransfers(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	sender, receiver := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	statedb.AddBalance(sender, common.SystemAssetID, big.NewInt(100))
+	recorder := new(transferRecorder)
+
+	call := func(from common.Address, nonce uint64, fn common.FSNCallFunc, param interface{}) common.Hash {
+		data, _ := rlp.EncodeToBytes(param)
+		evm := vm.NewEVM(vm.Context{BlockNumber: big.NewInt(10), Time: big.NewInt(1000), ParentTime: big.NewInt(990)}, statedb, params.TestChainConfig, vm.Config{Debug: true, Tracer: recorder})
+		msg := types.NewMessage(from, &common.FSNCallAddress, nonce, new(big.Int), 100000, big.NewInt(1), nil, false)
+		st := NewStateTransition(evm, msg, new(GasPool).AddGas(100000))
+		if err := st.handleFsnCall(&common.FSNCallParam{Func: fn, Data: data}); err != nil {
+			t.Fatalf("%v failed: %v", fn.Name(), err)
+		}
+		return GetUniqueHashFromMessageAt(msg, evm.BlockNumber)
+	}
+	id := call(sender, 0, common.EscrowAssetFunc, &common.EscrowAssetParam{AssetID: common.SystemAssetID, To: receiver, Value: big.NewInt(10), Deadline: 2000})
+	call(receiver, 0, common.ClaimEscrowFunc, &common.ClaimEscrowParam{EscrowID: id})
+
+	want := []common.InternalTransfer{
+		{Type: common.TransferEscrowLock, From: sender, To: common.FSNCallAddress},
+		{Type: common.TransferEscrowRelease, From: common.FSNCallAddress, To: receiver},
+	}
+	if len(recorder.transfers) != len(want) {
+		t.Fatalf("have %d transfers, want %d", len(recorder.transfers), len(want))
+	}
+	for i, w := range want {
+		have := recorder.transfers[i]
+		if have.Type != w.Type || have.From != w.From || have.To != w.To || have.Value.Int64() != 10 || have.Ref != id {
+			t.Errorf("transfer %d: have %+v, want %s of 10 from %x to %x", i, have, w.Type, w.From, w.To)
+		}
+	}
+}
+
+func TestStakingBuyTicket(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	owner, key := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	number := big.NewInt(10)
+	statedb.AddTimeLockBalance(owner, common.SystemAssetID, common.NewTimeLock(&common.TimeLockItem{
+		StartTime: 0,
+		EndTime:   common.TimeLockForever,
+		Value:     common.TicketPrice(number),
+	}), number, 990)
+
+	call := func(from common.Address, fn common.FSNCallFunc, param interface{}) error {
+		data, _ := rlp.EncodeToBytes(param)
+		ctx := vm.Context{BlockNumber: number, Time: big.NewInt(1000), ParentTime: big.NewInt(990), GetHash: func(uint64) common.Hash { return common.Hash{} }}
+		evm := vm.NewEVM(ctx, statedb, params.TestChainConfig, vm.Config{})
+		msg := types.NewMessage(from, &common.FSNCallAddress, 0, new(big.Int), 100000, big.NewInt(1), nil, false)
+		st := NewStateTransition(evm, msg, new(GasPool).AddGas(100000))
+		return st.handleFsnCall(&common.FSNCallParam{Func: fn, Data: data})
+	}
+	buy := &common.StakingBuyTicketParam{Owner: owner, Start: 990, End: 990 + 40*24*3600}
+
+	if err := call(key, common.StakingBuyTicketFunc, buy); common.FsnErrorCodeOf(err) != common.FsnErrNotAllowed {
+		t.Fatalf("ticket of an unauthorized key: have %v, want NotAllowed", err)
+	}
+	if err := call(owner, common.StakingKeyFunc, &common.StakingKeyParam{Key: key}); err != nil {
+		t.Fatalf("staking key rejected: %v", err)
+	}
+	if have := statedb.GetStakingKey(owner); have != key {
+		t.Fatalf("staking key of owner: have %v, want %v", have.Hex(), key.Hex())
+	}
+	if err := call(key, common.StakingBuyTicketFunc, buy); err != nil {
+		t.Fatalf("ticket of the staking key rejected: %v", err)
+	}
+	if tickets, _ := statedb.TicketsByOwner(owner); len(tickets) != 1 {
+		t.Errorf("owner has %d tickets, want 1", len(tickets))
+	}
+	if tickets, _ := statedb.TicketsByOwner(key); len(tickets) != 0 {
+		t.Errorf("staking key has %d tickets, want 0", len(tickets))
+	}
+	if !statedb.GetTimeLockBalance(common.SystemAssetID, key).IsEmpty() {
+		t.Errorf("ticket paid by the staking key")
+	}
+}
+
+func TestTransferTimeLockUseAny(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	sender, recipient := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	number := big.NewInt(10)
+	statedb.AddBalance(sender, common.SystemAssetID, big.NewInt(10))
+	statedb.AddTimeLockBalance(sender, common.SystemAssetID, common.GetTimeLock(big.NewInt(4), 1000, common.TimeLockForever), number, 1000)
+
+	// the time lock is spent first and topped up from the asset balance, the
+	// whole asset is delivered as the window covers forever
+	TransferTimeLock(statedb, sender, recipient, &common.TransferTimeLockParam{
+		AssetID:     common.SystemAssetID,
+		StartTime:   1000,
+		EndTime:     common.TimeLockForever,
+		Timestamp:   1000,
+		Flag:        common.FcUseAny,
+		Value:       big.NewInt(10),
+		BlockNumber: number,
+	})
+	if have := statedb.GetBalance(common.SystemAssetID, sender); have.Int64() != 4 {
+		t.Errorf("sender balance: have %v, want 4", have)
+	}
+	if !statedb.GetTimeLockBalance(common.SystemAssetID, sender).IsEmpty() {
+		t.Errorf("sender time lock not spent")
+	}
+	if have := statedb.GetBalance(common.SystemAssetID, recipient); have.Int64() != 10 {
+		t.Errorf("recipient balance: have %v, want 10", have)
+	}
+	if !statedb.GetTimeLockBalance(common.SystemAssetID, recipient).IsEmpty() {
+		t.Errorf("recipient received a time lock")
+	}
+}
+
+func TestRemovedSwapDeletion(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	swap := common.Swap{ID: common.HexToHash("0x01"), MinFromAmount: big.NewInt(1), MinToAmount: big.NewInt(1), SwapSize: big.NewInt(1), Time: big.NewInt(0)}
+	multiSwap := common.MultiSwap{ID: common.HexToHash("0x02"), SwapSize: big.NewInt(1), Time: big.NewInt(0)}
+	oldSwap := common.Swap{ID: common.HexToHash("0x03"), MinFromAmount: big.NewInt(1), MinToAmount: big.NewInt(1), SwapSize: big.NewInt(1), Time: big.NewInt(0)}
+	statedb.AddSwap(swap)
+	statedb.AddMultiSwap(multiSwap)
+	statedb.AddSwap(oldSwap)
+
+	remove := func(addr common.Address, id common.Hash) {
+		evm := vm.NewEVM(vm.Context{BlockNumber: big.NewInt(10), Time: big.NewInt(1000), ParentTime: big.NewInt(990)}, statedb, params.TestChainConfig, vm.Config{})
+		msg := types.NewMessage(common.HexToAddress("0x01"), &common.FSNCallAddress, 0, new(big.Int), 100000, big.NewInt(1), nil, false)
+		if err := NewStateTransition(evm, msg, new(GasPool).AddGas(100000)).removeSwap(addr, id); err != nil {
+			t.Fatalf("failed to remove %x: %v", id, err)
+		}
+	}
+	// swaps removed before the fork are kept as tombstones forever
+	remove(common.SwapKeyAddress, oldSwap.ID)
+
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+	remove(common.SwapKeyAddress, swap.ID)
+	remove(common.MultiSwapKeyAddress, multiSwap.ID)
+
+	stored := func(addr common.Address, id common.Hash) bool {
+		return len(statedb.GetStructData(addr, id.Bytes())) != 0
+	}
+	deleteAt := 10 + common.SwapRetentionBlocks
+	statedb.DeleteScheduledSwaps(deleteAt - 1)
+	if _, err := statedb.GetSwap(swap.ID); err == nil || !stored(common.SwapKeyAddress, swap.ID) || !stored(common.MultiSwapKeyAddress, multiSwap.ID) {
+		t.Fatalf("removed swaps not kept as tombstones until the retention period ends")
+	}
+	statedb.DeleteScheduledSwaps(deleteAt)
+	if stored(common.SwapKeyAddress, swap.ID) || stored(common.MultiSwapKeyAddress, multiSwap.ID) {
+		t.Errorf("removed swaps not deleted after the retention period")
+	}
+	if !stored(common.SwapKeyAddress, oldSwap.ID) {
+		t.Errorf("swap removed before the fork deleted")
+	}
+}
@@ -5,11 +5,16 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"sort"
 	"time"
 
 	"github.com/FusionFoundation/go-fusion/common"
 	"github.com/FusionFoundation/go-fusion/consensus/datong"
+	"github.com/FusionFoundation/go-fusion/core/state"
 	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/core/vm"
+	"github.com/FusionFoundation/go-fusion/log"
+	"github.com/FusionFoundation/go-fusion/params"
 	"github.com/FusionFoundation/go-fusion/rlp"
 )
 
@@ -47,60 +52,160 @@ func (l *txList) FilterInvalid(filter func(*types.Transaction) bool) (types.Tran
 	return removed, invalids
 }
 
+// addJournaled adds the transactions of the local journal in account and
+// nonce order, so that the FSN calls are validated again with their FSN
+// checks in the order they were sent. The FSN calls which no longer pass them
+// are dropped and reported.
+func (pool *TxPool) addJournaled(txs []*types.Transaction) []error {
+	txs = sortByNonce(pool.signer, txs)
+	errs := pool.AddLocals(txs)
+	for i, err := range errs {
+		if err == nil || err == ErrAlreadyKnown || !common.IsFsnCall(txs[i].To()) {
+			continue
+		}
+		param := common.FSNCallParam{}
+		rlp.DecodeBytes(txs[i].Data(), &param)
+		from, _ := types.Sender(pool.signer, txs[i])
+		log.Info("Dropped journaled FSN call", "hash", txs[i].Hash(), "from", from, "nonce", txs[i].Nonce(), "func", param.Func.Name(), "err", err)
+	}
+	return errs
+}
+
+// sortByNonce returns the transactions ordered by sender and nonce, keeping
+// the order of the transactions of the same sender and nonce.
+func sortByNonce(signer types.Signer, txs []*types.Transaction) []*types.Transaction {
+	sorted := make([]*types.Transaction, len(txs))
+	senders := make(map[*types.Transaction]common.Address, len(txs))
+	for i, tx := range txs {
+		sorted[i] = tx
+		senders[tx], _ = types.Sender(signer, tx)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		si, sj := senders[sorted[i]], senders[sorted[j]]
+		if si != sj {
+			return bytes.Compare(si[:], sj[:]) < 0
+		}
+		return sorted[i].Nonce() < sorted[j].Nonce()
+	})
+	return sorted
+}
+
+// validateAddFsnCallTx checks a transaction entering the pool against the
+// FSN call rules and the replacement rules of the pooled FSN calls:
+//
+//   - a transaction replacing a pooled one of the same account and nonce is
+//     validated as any new FSN call and must pay the price bump, otherwise
+//     nothing is replaced
+//   - an account has at most one BuyTicket transaction in the pool, a newer
+//     one replaces it, also under another nonce. The nonce of the replaced
+//     transaction is then left for the account to fill.
 func (pool *TxPool) validateAddFsnCallTx(tx *types.Transaction) error {
 	if err := pool.validateFsnCallTx(tx); err != nil {
 		return err
 	}
+	from, _ := types.Sender(pool.signer, tx) // already validated
+	var ticketTx *types.Transaction
 	if tx.IsBuyTicketTx() {
-		from, _ := types.Sender(pool.signer, tx) // already validated
-		found := false
-		var oldTxHash common.Hash
 		pool.all.Range(func(hash common.Hash, tx1 *types.Transaction) bool {
-			if hash == tx.Hash() {
-				found = true
-				return false
-			} else if tx1.IsBuyTicketTx() {
-				sender, _ := types.Sender(pool.signer, tx1)
-				if from == sender {
-					// always choose latest buy ticket tx
-					oldTxHash = hash
+			if tx1.IsBuyTicketTx() {
+				if sender, _ := types.Sender(pool.signer, tx1); sender == from {
+					ticketTx = tx1
 					return false
 				}
 			}
 			return true
 		})
-		if found == true {
-			return fmt.Errorf("%v has already bought a ticket in txpool", from.String())
-		}
-		if oldTxHash != (common.Hash{}) {
-			pool.removeTx(oldTxHash, true)
+	}
+	evict, err := checkFsnCallReplacement(tx, pool.pooledTx(from, tx.Nonce()), ticketTx, pool.config.PriceBump)
+	if err != nil {
+		return err
+	}
+	if evict != nil {
+		pool.removeTx(evict.Hash(), true)
+	}
+	return nil
+}
+
+// checkFsnCallReplacement applies the replacement rules of validateAddFsnCallTx
+// to tx, given the pooled transaction of its account and nonce and the pooled
+// BuyTicket transaction of its account, if any. It returns the pooled
+// BuyTicket transaction tx replaces under another nonce, if any.
+func checkFsnCallReplacement(tx, old, ticketTx *types.Transaction, priceBump uint64) (*types.Transaction, error) {
+	if old != nil && !replacementPriced(old, tx, priceBump) {
+		return nil, ErrReplaceUnderpriced
+	}
+	if tx.IsBuyTicketTx() && ticketTx != nil && ticketTx.Nonce() != tx.Nonce() {
+		return ticketTx, nil
+	}
+	return nil, nil
+}
+
+// pooledTx returns the pending or queued transaction of the account with the
+// given nonce, nil if there is none.
+func (pool *TxPool) pooledTx(from common.Address, nonce uint64) *types.Transaction {
+	if list := pool.pending[from]; list != nil {
+		if tx := list.txs.Get(nonce); tx != nil {
+			return tx
 		}
 	}
+	if list := pool.queue[from]; list != nil {
+		return list.txs.Get(nonce)
+	}
 	return nil
 }
 
 func (pool *TxPool) validateReceiveAssetPayableTx(tx *types.Transaction, from common.Address) error {
 	header := pool.chain.CurrentBlock().Header()
 	height := new(big.Int).Add(header.Number, big.NewInt(1))
-	input := tx.Data()
-	if !common.IsReceiveAssetPayableTx(height, input) {
+	timestamp := uint64(time.Now().Unix())
+	// use `timestamp+600` here to ensure timelock tx with minimum lifetime of 10 minutes,
+	// that is endtime of timelock must be greater than or equal to `now + 600 seconds`.
+	p, err := common.ParseReceiveAssetPayableTx(height, tx.Data(), tx.Value(), timestamp+600)
+	if p == nil && err == nil {
 		return nil
 	}
 	if pool.currentState.GetCodeSize(*tx.To()) == 0 {
 		return fmt.Errorf("receiveAsset tx receiver must be contract")
 	}
-	timestamp := uint64(time.Now().Unix())
-	p := &common.TransferTimeLockParam{}
-	// use `timestamp+600` here to ensure timelock tx with minimum lifetime of 10 minutes,
-	// that is endtime of timelock must be greater than or equal to `now + 600 seconds`.
-	if err := common.ParseReceiveAssetPayableTxInput(p, input, timestamp+600); err != nil {
+	if err != nil {
 		return err
 	}
-	p.Value = tx.Value()
 	p.GasValue = new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasPrice())
 	if !CanTransferTimeLock(pool.currentState, from, p) {
 		return ErrInsufficientFunds
 	}
+	return checkAssetTransfer(pool.currentState, height, []common.Hash{p.AssetID}, from, *tx.To())
+}
+
+// checkAssetTransfer returns an error if the transfer list of one of the
+// assets does not allow one of the addresses to send or receive it
+func checkAssetTransfer(statedb *state.StateDB, number *big.Int, assetIDs []common.Hash, addrs ...common.Address) error {
+	if !common.IsAssetTransferRestrictionEnabled(number) {
+		return nil
+	}
+	for _, assetID := range assetIDs {
+		if assetID == common.SystemAssetID || assetID == common.OwnerUSANAssetID {
+			continue
+		}
+		for _, addr := range addrs {
+			if !statedb.IsAssetTransferAllowed(assetID, addr) {
+				return fmt.Errorf("asset %v transfer not allowed for %v", assetID.Hex(), addr.Hex())
+			}
+		}
+	}
+	return nil
+}
+
+// checkTicketRateLimit returns an error if owner can not buy a ticket of the
+// given weight without exceeding the rate limit in the window of block number
+func checkTicketRateLimit(config *params.ChainConfig, statedb *state.StateDB, number *big.Int, owner common.Address, weight uint64) error {
+	limit := config.TicketRateLimitAt(number)
+	if limit == nil {
+		return nil
+	}
+	if statedb.GetTicketPurchases(owner, limit.WindowOf(number.Uint64()))+weight > limit.MaxTickets {
+		return fmt.Errorf("%v can not buy more than %d tickets in the current window of %d blocks", owner.Hex(), limit.MaxTickets, limit.Window)
+	}
 	return nil
 }
 
@@ -121,7 +226,7 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 	currBlockHeader := pool.chain.CurrentBlock().Header()
 	nextBlockNumber := new(big.Int).Add(currBlockHeader.Number, big.NewInt(1))
 
-	state := pool.currentState
+	state := pool.fsnValidationState(from, tx.Nonce())
 	height := common.BigMaxUint64
 	timestamp := uint64(time.Now().Unix())
 
@@ -129,8 +234,11 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 	if err := rlp.DecodeBytes(tx.Data(), &param); err != nil {
 		return fmt.Errorf("decode FSNCallParam error")
 	}
+	if !common.IsFsnCallFuncEnabled(param.Func, nextBlockNumber) {
+		return fmt.Errorf("Unsupported FsnCall func '%v'", param.Func.Name())
+	}
 
-	fee := common.GetFsnCallFee(to, param.Func)
+	fee := common.GetFsnCallFeeAt(to, param.Func, nextBlockNumber, state)
 	fsnValue := big.NewInt(0)
 
 	switch param.Func {
@@ -142,12 +250,188 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 	case common.GenAssetFunc:
 		genAssetParam := common.GenAssetParam{}
 		rlp.DecodeBytes(param.Data, &genAssetParam)
-		if err := genAssetParam.Check(height); err != nil {
+		if err := genAssetParam.Check(nextBlockNumber); err != nil {
+			return err
+		}
+		assetID := GetUniqueHashAt(tx, from, nextBlockNumber)
+		if _, err := state.GetAsset(assetID); err == nil {
+			return common.NewFsnError(common.FsnErrAssetExists, "%s asset exists", assetID.String())
+		}
+		if common.IsAssetSymbolRegistryEnabled(height) {
+			if id, ok := state.GetAssetIDBySymbol(genAssetParam.Symbol); ok {
+				return fmt.Errorf("asset symbol already registered by %s", id.String())
+			}
+		}
+
+	case common.GenRestrictedAssetFunc:
+		genRestrictedAssetParam := common.GenRestrictedAssetParam{}
+		rlp.DecodeBytes(param.Data, &genRestrictedAssetParam)
+		if err := genRestrictedAssetParam.Check(nextBlockNumber); err != nil {
+			return err
+		}
+		assetID := GetUniqueHashAt(tx, from, nextBlockNumber)
+		if _, err := state.GetAsset(assetID); err == nil {
+			return common.NewFsnError(common.FsnErrAssetExists, "%s asset exists", assetID.String())
+		}
+		if id, ok := state.GetAssetIDBySymbol(genRestrictedAssetParam.Asset.Symbol); ok {
+			return fmt.Errorf("asset symbol already registered by %s", id.String())
+		}
+
+	case common.GenMultiOwnerAssetFunc:
+		genMultiOwnerAssetParam := common.GenMultiOwnerAssetParam{}
+		rlp.DecodeBytes(param.Data, &genMultiOwnerAssetParam)
+		if err := genMultiOwnerAssetParam.Check(nextBlockNumber); err != nil {
 			return err
 		}
-		assetID := GetUniqueHashFromTransaction(tx)
+		assetID := GetUniqueHashAt(tx, from, nextBlockNumber)
 		if _, err := state.GetAsset(assetID); err == nil {
-			return fmt.Errorf("%s asset exists", assetID.String())
+			return common.NewFsnError(common.FsnErrAssetExists, "%s asset exists", assetID.String())
+		}
+		if id, ok := state.GetAssetIDBySymbol(genMultiOwnerAssetParam.Asset.Symbol); ok {
+			return fmt.Errorf("asset symbol already registered by %s", id.String())
+		}
+
+	case common.AssetTransferListFunc:
+		assetTransferListParam := common.AssetTransferListParam{}
+		rlp.DecodeBytes(param.Data, &assetTransferListParam)
+		if err := assetTransferListParam.Check(nextBlockNumber); err != nil {
+			return err
+		}
+		asset, err := state.GetAsset(assetTransferListParam.AssetID)
+		if err != nil {
+			return common.ErrAssetNotFound
+		}
+		if err := checkAssetOwner(state, &asset, &param, from, nextBlockNumber); err != nil {
+			return err
+		}
+		if state.GetAssetTransferRestriction(asset.ID) == common.AssetTransferUnrestricted {
+			return fmt.Errorf("asset transfers are not restricted")
+		}
+
+	case common.SetFsnCallFeeFunc:
+		setFsnCallFeeParam := common.SetFsnCallFeeParam{}
+		rlp.DecodeBytes(param.Data, &setFsnCallFeeParam)
+		if err := checkSetFsnCallFee(state, &setFsnCallFeeParam, from, nextBlockNumber); err != nil {
+			return err
+		}
+
+	case common.CreateProposalFunc:
+		createProposalParam := common.CreateProposalParam{}
+		rlp.DecodeBytes(param.Data, &createProposalParam)
+		if _, err := checkCreateProposal(state, &createProposalParam, from, nextBlockNumber, currBlockHeader.Time); err != nil {
+			return err
+		}
+
+	case common.VoteProposalFunc:
+		voteProposalParam := common.VoteProposalParam{}
+		rlp.DecodeBytes(param.Data, &voteProposalParam)
+		if _, _, err := checkVoteProposal(state, &voteProposalParam, from, nextBlockNumber, currBlockHeader.Time); err != nil {
+			return err
+		}
+
+	case common.EscrowAssetFunc:
+		escrowAssetParam := common.EscrowAssetParam{}
+		rlp.DecodeBytes(param.Data, &escrowAssetParam)
+		if err := checkEscrowAsset(state, &escrowAssetParam, from, nextBlockNumber, currBlockHeader.Time); err != nil {
+			return err
+		}
+		if err := checkAssetTransfer(state, nextBlockNumber, []common.Hash{escrowAssetParam.AssetID}, from, escrowAssetParam.To); err != nil {
+			return err
+		}
+
+	case common.ClaimEscrowFunc:
+		claimEscrowParam := common.ClaimEscrowParam{}
+		rlp.DecodeBytes(param.Data, &claimEscrowParam)
+		escrow, err := checkClaimEscrow(state, &claimEscrowParam, from, nextBlockNumber, currBlockHeader.Time)
+		if err != nil {
+			return err
+		}
+		if from == escrow.To {
+			if err := checkAssetTransfer(state, nextBlockNumber, []common.Hash{escrow.AssetID}, from); err != nil {
+				return err
+			}
+		}
+
+	case common.CreateStreamFunc:
+		createStreamParam := common.CreateStreamParam{}
+		rlp.DecodeBytes(param.Data, &createStreamParam)
+		if err := checkCreateStream(state, &createStreamParam, from, nextBlockNumber, currBlockHeader.Time); err != nil {
+			return err
+		}
+		if err := checkAssetTransfer(state, nextBlockNumber, []common.Hash{createStreamParam.AssetID}, from, createStreamParam.To); err != nil {
+			return err
+		}
+
+	case common.WithdrawStreamFunc:
+		withdrawStreamParam := common.WithdrawStreamParam{}
+		rlp.DecodeBytes(param.Data, &withdrawStreamParam)
+		stream, _, err := checkWithdrawStream(state, &withdrawStreamParam, from, nextBlockNumber, currBlockHeader.Time)
+		if err != nil {
+			return err
+		}
+		if err := checkAssetTransfer(state, nextBlockNumber, []common.Hash{stream.AssetID}, from); err != nil {
+			return err
+		}
+
+	case common.CreateConditionFunc:
+		createConditionParam := common.CreateConditionParam{}
+		rlp.DecodeBytes(param.Data, &createConditionParam)
+		if err := createConditionParam.Check(nextBlockNumber); err != nil {
+			return err
+		}
+
+	case common.ResolveConditionFunc:
+		resolveConditionParam := common.ResolveConditionParam{}
+		rlp.DecodeBytes(param.Data, &resolveConditionParam)
+		if _, err := checkResolveCondition(state, &resolveConditionParam, from, nextBlockNumber); err != nil {
+			return err
+		}
+
+	case common.ConditionalTransferFunc:
+		conditionalTransferParam := common.ConditionalTransferParam{}
+		rlp.DecodeBytes(param.Data, &conditionalTransferParam)
+		if err := checkConditionalTransfer(state, &conditionalTransferParam, from, nextBlockNumber, currBlockHeader.Time); err != nil {
+			return err
+		}
+		if err := checkAssetTransfer(state, nextBlockNumber, []common.Hash{conditionalTransferParam.AssetID}, from, conditionalTransferParam.To); err != nil {
+			return err
+		}
+
+	case common.SettleConditionalFunc:
+		settleConditionalParam := common.SettleConditionalParam{}
+		rlp.DecodeBytes(param.Data, &settleConditionalParam)
+		transfer, receiver, err := checkSettleConditional(state, &settleConditionalParam, nextBlockNumber, currBlockHeader.Time)
+		if err != nil {
+			return err
+		}
+		if receiver == transfer.To {
+			if err := checkAssetTransfer(state, nextBlockNumber, []common.Hash{transfer.AssetID}, receiver); err != nil {
+				return err
+			}
+		}
+
+	case common.AttestDepositFunc:
+		attestDepositParam := common.AttestDepositParam{}
+		rlp.DecodeBytes(param.Data, &attestDepositParam)
+		if _, err := checkAttestDeposit(state, &attestDepositParam, from, nextBlockNumber); err != nil {
+			return err
+		}
+
+	case common.BridgeWithdrawFunc:
+		bridgeWithdrawParam := common.BridgeWithdrawParam{}
+		rlp.DecodeBytes(param.Data, &bridgeWithdrawParam)
+		if _, err := checkBridgeWithdraw(state, &bridgeWithdrawParam, from, nextBlockNumber); err != nil {
+			return err
+		}
+		if err := checkAssetTransfer(state, nextBlockNumber, []common.Hash{bridgeWithdrawParam.AssetID}, from); err != nil {
+			return err
+		}
+
+	case common.RevokeTicketFunc:
+		revokeTicketParam := common.RevokeTicketParam{}
+		rlp.DecodeBytes(param.Data, &revokeTicketParam)
+		if _, err := checkRevokeTicket(state, &revokeTicketParam, from, nextBlockNumber, currBlockHeader.Time); err != nil {
+			return err
 		}
 
 	case common.SendAssetFunc:
@@ -156,10 +440,13 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 		if err := sendAssetParam.Check(height); err != nil {
 			return err
 		}
+		if err := checkAssetTransfer(state, nextBlockNumber, []common.Hash{sendAssetParam.AssetID}, from, sendAssetParam.To); err != nil {
+			return err
+		}
 		if sendAssetParam.AssetID == common.SystemAssetID {
 			fsnValue = sendAssetParam.Value
 		} else if state.GetBalance(sendAssetParam.AssetID, from).Cmp(sendAssetParam.Value) < 0 {
-			return fmt.Errorf("not enough asset")
+			return common.ErrNotEnoughAsset
 		}
 
 	case common.TimeLockFunc:
@@ -191,31 +478,34 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 		if err := needValue.IsValid(); err != nil {
 			return err
 		}
+		if err := checkAssetTransfer(state, nextBlockNumber, []common.Hash{timeLockParam.AssetID}, from, timeLockParam.To); err != nil {
+			return err
+		}
 		switch timeLockParam.Type {
 		case common.AssetToTimeLock:
 			if timeLockParam.AssetID == common.SystemAssetID {
 				fsnValue = timeLockParam.Value
 			} else if state.GetBalance(timeLockParam.AssetID, from).Cmp(timeLockParam.Value) < 0 {
-				return fmt.Errorf("AssetToTimeLock: not enough asset")
+				return common.NewFsnError(common.FsnErrNotEnoughAsset, "AssetToTimeLock: not enough asset")
 			}
 		case common.TimeLockToTimeLock:
 			if state.GetTimeLockBalance(timeLockParam.AssetID, from).Cmp(needValue) < 0 {
-				return fmt.Errorf("TimeLockToTimeLock: not enough time lock balance")
+				return common.NewFsnError(common.FsnErrNotEnoughTimeLock, "TimeLockToTimeLock: not enough time lock balance")
 			}
 		case common.TimeLockToAsset:
 			if state.GetTimeLockBalance(timeLockParam.AssetID, from).Cmp(needValue) < 0 {
-				return fmt.Errorf("TimeLockToAsset: not enough time lock balance")
+				return common.NewFsnError(common.FsnErrNotEnoughTimeLock, "TimeLockToAsset: not enough time lock balance")
 			}
 		case common.SmartTransfer:
 			if !common.IsSmartTransferEnabled(nextBlockNumber) {
-				return fmt.Errorf("SendTimeLock not enabled")
+				return common.NewFsnError(common.FsnErrNotEnabled, "SendTimeLock not enabled")
 			}
 			timeLockBalance := state.GetTimeLockBalance(timeLockParam.AssetID, from)
 			if timeLockBalance.Cmp(needValue) < 0 {
 				timeLockValue := timeLockBalance.GetSpendableValue(start, end)
 				assetBalance := state.GetBalance(timeLockParam.AssetID, from)
 				if new(big.Int).Add(timeLockValue, assetBalance).Cmp(timeLockParam.Value) < 0 {
-					return fmt.Errorf("SendTimeLock: not enough balance")
+					return common.NewFsnError(common.FsnErrNotEnoughBalance, "SendTimeLock: not enough balance")
 				}
 				fsnValue = new(big.Int).Sub(timeLockParam.Value, timeLockValue)
 			}
@@ -227,10 +517,13 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 		if err := buyTicketParam.Check(height, currBlockHeader.Time); err != nil {
 			return err
 		}
+		if err := checkTicketRateLimit(pool.chainconfig, state, nextBlockNumber, from, buyTicketParam.Weight(nextBlockNumber)); err != nil {
+			return err
+		}
 
 		start := buyTicketParam.Start
 		end := buyTicketParam.End
-		value := common.TicketPrice(height)
+		value := new(big.Int).Mul(common.TicketPrice(height), new(big.Int).SetUint64(buyTicketParam.Weight(nextBlockNumber)))
 		needValue := common.NewTimeLock(&common.TimeLockItem{
 			StartTime: common.MaxUint64(start, timestamp),
 			EndTime:   end,
@@ -254,15 +547,15 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 
 		asset, err := state.GetAsset(assetValueChangeParamEx.AssetID)
 		if err != nil {
-			return fmt.Errorf("asset not found")
+			return common.ErrAssetNotFound
 		}
 
 		if !asset.CanChange {
-			return fmt.Errorf("asset can't inc or dec")
+			return common.ErrAssetNotChangeable
 		}
 
-		if asset.Owner != from {
-			return fmt.Errorf("can only be changed by owner")
+		if err := checkAssetOwner(state, &asset, &param, from, nextBlockNumber); err != nil {
+			return err
 		}
 
 		if asset.Owner != assetValueChangeParamEx.To && !assetValueChangeParamEx.IsInc {
@@ -272,7 +565,7 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 
 		if !assetValueChangeParamEx.IsInc {
 			if state.GetBalance(assetValueChangeParamEx.AssetID, assetValueChangeParamEx.To).Cmp(assetValueChangeParamEx.Value) < 0 {
-				return fmt.Errorf("not enough asset")
+				return common.ErrNotEnoughAsset
 			}
 		}
 
@@ -281,7 +574,7 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 	case common.MakeSwapFunc, common.MakeSwapFuncExt:
 		makeSwapParam := common.MakeSwapParam{}
 		rlp.DecodeBytes(param.Data, &makeSwapParam)
-		swapId := GetUniqueHashFromTransaction(tx)
+		swapId := GetUniqueHashAt(tx, from, nextBlockNumber)
 
 		if _, err := state.GetSwap(swapId); err == nil {
 			return fmt.Errorf("MakeSwap: %v Swap already exist", swapId.String())
@@ -290,6 +583,9 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 		if err := makeSwapParam.Check(height, timestamp); err != nil {
 			return err
 		}
+		if err := checkAssetTransfer(state, nextBlockNumber, []common.Hash{makeSwapParam.FromAssetID, makeSwapParam.ToAssetID}, from); err != nil {
+			return err
+		}
 
 		if _, err := state.GetAsset(makeSwapParam.ToAssetID); err != nil {
 			return fmt.Errorf("ToAssetID asset %v not found", makeSwapParam.ToAssetID.String())
@@ -298,7 +594,7 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 		if makeSwapParam.FromAssetID == common.OwnerUSANAssetID {
 			notation := state.GetNotation(from)
 			if notation == 0 {
-				return fmt.Errorf("the from address does not have a notation")
+				return common.ErrNoNotation
 			}
 		} else {
 			total := new(big.Int).Mul(makeSwapParam.MinFromAmount, makeSwapParam.SwapSize)
@@ -310,7 +606,7 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 				if makeSwapParam.FromAssetID == common.SystemAssetID {
 					fsnValue = total
 				} else if state.GetBalance(makeSwapParam.FromAssetID, from).Cmp(total) < 0 {
-					return fmt.Errorf("not enough from asset")
+					return common.ErrNotEnoughFromAsset
 				}
 			} else {
 				needValue := common.NewTimeLock(&common.TimeLockItem{
@@ -326,13 +622,13 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 					if param.Func == common.MakeSwapFunc {
 						// this was the legacy swap do not do
 						// time lock and just return an error
-						return fmt.Errorf("not enough time lock balance")
+						return common.ErrNotEnoughTimeLock
 					}
 
 					if makeSwapParam.FromAssetID == common.SystemAssetID {
 						fsnValue = total
 					} else if state.GetBalance(makeSwapParam.FromAssetID, from).Cmp(total) < 0 {
-						return fmt.Errorf("not enough time lock or asset balance")
+						return common.ErrNotEnoughTimeLockOrAsset
 					}
 				}
 			}
@@ -348,7 +644,7 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 		}
 
 		if swap.Owner != from {
-			return fmt.Errorf("Must be swap onwer can recall")
+			return common.ErrNotSwapOwner
 		}
 
 		if err := recallSwapParam.Check(height, &swap); err != nil {
@@ -364,7 +660,11 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 			return fmt.Errorf("TakeSwap: %v Swap not found", takeSwapParam.SwapID.String())
 		}
 
-		if err := takeSwapParam.Check(height, &swap, timestamp); err != nil {
+		if err := takeSwapParam.Check(nextBlockNumber, &swap, timestamp); err != nil {
+			return err
+		}
+		takeSwapParam.Size = takeSwapParam.FillSize(nextBlockNumber, &swap)
+		if err := checkAssetTransfer(state, nextBlockNumber, []common.Hash{swap.FromAssetID, swap.ToAssetID}, from, swap.Owner); err != nil {
 			return err
 		}
 
@@ -375,7 +675,7 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 		if swap.FromAssetID == common.OwnerUSANAssetID {
 			notation := state.GetNotation(swap.Owner)
 			if notation == 0 || notation != swap.Notation {
-				return fmt.Errorf("notation in swap is no longer valid")
+				return common.ErrSwapNotationInvalid
 			}
 		}
 
@@ -388,7 +688,7 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 			if swap.ToAssetID == common.SystemAssetID {
 				fsnValue = toTotal
 			} else if state.GetBalance(swap.ToAssetID, from).Cmp(toTotal) < 0 {
-				return fmt.Errorf("not enough from asset")
+				return common.ErrNotEnoughFromAsset
 			}
 		} else {
 			toNeedValue := common.NewTimeLock(&common.TimeLockItem{
@@ -404,13 +704,13 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 				if param.Func == common.TakeSwapFunc {
 					// this was the legacy swap do not do
 					// time lock and just return an error
-					return fmt.Errorf("not enough time lock balance")
+					return common.ErrNotEnoughTimeLock
 				}
 
 				if swap.ToAssetID == common.SystemAssetID {
 					fsnValue = toTotal
 				} else if state.GetBalance(swap.ToAssetID, from).Cmp(toTotal) < 0 {
-					return fmt.Errorf("not enough time lock or asset balance")
+					return common.ErrNotEnoughTimeLockOrAsset
 				}
 			}
 		}
@@ -421,11 +721,11 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 
 		swap, err := state.GetMultiSwap(recallSwapParam.SwapID)
 		if err != nil {
-			return fmt.Errorf("Swap not found")
+			return common.ErrSwapNotFound
 		}
 
 		if swap.Owner != from {
-			return fmt.Errorf("Must be swap onwer can recall")
+			return common.ErrNotSwapOwner
 		}
 
 		if err := recallSwapParam.Check(height, &swap); err != nil {
@@ -435,16 +735,19 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 	case common.MakeMultiSwapFunc:
 		makeSwapParam := common.MakeMultiSwapParam{}
 		rlp.DecodeBytes(param.Data, &makeSwapParam)
-		swapID := GetUniqueHashFromTransaction(tx)
+		swapID := GetUniqueHashAt(tx, from, nextBlockNumber)
 
 		_, err := state.GetSwap(swapID)
 		if err == nil {
-			return fmt.Errorf("Swap already exist")
+			return common.ErrSwapExists
 		}
 
 		if err := makeSwapParam.Check(height, timestamp); err != nil {
 			return err
 		}
+		if err := checkAssetTransfer(state, nextBlockNumber, append(append([]common.Hash{}, makeSwapParam.FromAssetID...), makeSwapParam.ToAssetID...), from); err != nil {
+			return err
+		}
 
 		for _, toAssetID := range makeSwapParam.ToAssetID {
 			if _, err := state.GetAsset(toAssetID); err != nil {
@@ -492,7 +795,7 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 			timeLockBalance := accountTimeLockBalances[makeSwapParam.FromAssetID[i]]
 			if useAsset[i] == true {
 				if balance.Cmp(total[i]) < 0 {
-					return fmt.Errorf("not enough from asset")
+					return common.ErrNotEnoughFromAsset
 				}
 				balance.Sub(balance, total[i])
 				if makeSwapParam.FromAssetID[i] == common.SystemAssetID {
@@ -501,7 +804,7 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 			} else {
 				if timeLockBalance.Cmp(needValue[i]) < 0 {
 					if balance.Cmp(total[i]) < 0 {
-						return fmt.Errorf("not enough time lock or asset balance")
+						return common.ErrNotEnoughTimeLockOrAsset
 					}
 
 					balance.Sub(balance, total[i])
@@ -525,12 +828,15 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 
 		swap, err := state.GetMultiSwap(takeSwapParam.SwapID)
 		if err != nil {
-			return fmt.Errorf("Swap not found")
+			return common.ErrSwapNotFound
 		}
 
 		if err := takeSwapParam.Check(height, &swap, timestamp); err != nil {
 			return err
 		}
+		if err := checkAssetTransfer(state, nextBlockNumber, append(append([]common.Hash{}, swap.FromAssetID...), swap.ToAssetID...), from, swap.Owner); err != nil {
+			return err
+		}
 
 		if err := common.CheckSwapTargets(swap.Targes, from); err != nil {
 			return err
@@ -574,7 +880,7 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 			timeLockBalance := accountTimeLockBalances[swap.ToAssetID[i]]
 			if toUseAsset[i] == true {
 				if balance.Cmp(toTotal[i]) < 0 {
-					return fmt.Errorf("not enough from asset")
+					return common.ErrNotEnoughFromAsset
 				}
 				balance.Sub(balance, toTotal[i])
 				if swap.ToAssetID[i] == common.SystemAssetID {
@@ -586,7 +892,7 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 				}
 				if timeLockBalance.Cmp(toNeedValue[i]) < 0 {
 					if balance.Cmp(toTotal[i]) < 0 {
-						return fmt.Errorf("not enough time lock or asset balance")
+						return common.ErrNotEnoughTimeLockOrAsset
 					}
 
 					balance.Sub(balance, toTotal[i])
@@ -620,15 +926,296 @@ func (pool *TxPool) validateFsnCallTx(tx *types.Transaction) error {
 			return fmt.Errorf("already reported in pool")
 		}
 
+	case common.StakingKeyFunc:
+		stakingKeyParam := common.StakingKeyParam{}
+		rlp.DecodeBytes(param.Data, &stakingKeyParam)
+		if err := stakingKeyParam.Check(nextBlockNumber, from); err != nil {
+			return err
+		}
+
+	case common.StakingBuyTicketFunc:
+		stakingBuyTicketParam := common.StakingBuyTicketParam{}
+		rlp.DecodeBytes(param.Data, &stakingBuyTicketParam)
+		if err := stakingBuyTicketParam.Check(nextBlockNumber, currBlockHeader.Time); err != nil {
+			return err
+		}
+		owner := stakingBuyTicketParam.Owner
+		if key := state.GetStakingKey(owner); key != from {
+			return fmt.Errorf("%v is not the staking key of %v", from.Hex(), owner.Hex())
+		}
+		weight := stakingBuyTicketParam.ToBuyTicketParam().Weight(nextBlockNumber)
+		if err := checkTicketRateLimit(pool.chainconfig, state, nextBlockNumber, owner, weight); err != nil {
+			return err
+		}
+
+		// staking keys can only spend the time lock balance of the owner
+		needValue := common.NewTimeLock(&common.TimeLockItem{
+			StartTime: common.MaxUint64(stakingBuyTicketParam.Start, timestamp),
+			EndTime:   stakingBuyTicketParam.End,
+			Value:     new(big.Int).Mul(common.TicketPrice(height), new(big.Int).SetUint64(weight)),
+		})
+		if err := needValue.IsValid(); err != nil {
+			return err
+		}
+		if state.GetTimeLockBalance(common.SystemAssetID, owner).Cmp(needValue) < 0 {
+			return fmt.Errorf("owner %v has not enough time lock balance", owner.Hex())
+		}
+
+	case common.TypedCallFunc:
+		typedCallParam := common.TypedCallParam{}
+		rlp.DecodeBytes(param.Data, &typedCallParam)
+		if err := typedCallParam.Check(nextBlockNumber, timestamp); err != nil {
+			return err
+		}
+		typedData, err := types.NewFSNTypedData(pool.chainconfig.ChainID, &typedCallParam.Call, typedCallParam.Nonce, typedCallParam.Deadline)
+		if err != nil {
+			return err
+		}
+		signer, err := typedData.Signer(typedCallParam.Signature)
+		if err != nil {
+			return err
+		}
+		if nonce := state.GetTypedCallNonce(signer); nonce != typedCallParam.Nonce {
+			return fmt.Errorf("typed call nonce mismatch: have %d, want %d", typedCallParam.Nonce, nonce)
+		}
+		fee = common.GetFsnCallFeeAt(to, typedCallParam.Call.Func, nextBlockNumber, state)
+
 	default:
 		return fmt.Errorf("Unsupported FsnCall func '%v'", param.Func.Name())
 	}
 	// check gas, fee and value
 	mgval := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasPrice())
 	mgval.Add(mgval, fee)
+	if tx.Sponsored() {
+		// the sponsor pays the gas and the fee, the sender the value
+		// on top of what its pending transactions cost it
+		sponsor, _ := types.Sponsor(pool.signer, tx) // already validated
+		spend := pool.sponsorSpend(sponsor, tx, nextBlockNumber)
+		if balance := state.GetBalance(common.SystemAssetID, sponsor); balance.Cmp(new(big.Int).Add(mgval, spend)) < 0 {
+			return fmt.Errorf("insufficient sponsor balance(%v), need %v = (gas:%v * price:%v + fee:%v + pending:%v)", balance, new(big.Int).Add(mgval, spend), tx.Gas(), tx.GasPrice(), fee, spend)
+		}
+		if balance := state.GetBalance(common.SystemAssetID, from); balance.Cmp(fsnValue) < 0 {
+			return fmt.Errorf("insufficient balance(%v), need value:%v", balance, fsnValue)
+		}
+		return nil
+	}
 	mgval.Add(mgval, fsnValue)
 	if balance := state.GetBalance(common.SystemAssetID, from); balance.Cmp(mgval) < 0 {
 		return fmt.Errorf("insufficient balance(%v), need %v = (gas:%v * price:%v + value:%v + fee:%v)", balance, mgval, tx.Gas(), tx.GasPrice(), fsnValue, fee)
 	}
 	return nil
 }
+
+// sponsorSpend returns what the pending transactions cost sponsor before tx:
+// the ones it sends, and the gas and fees of the ones it sponsors. A pending tx
+// only comes after the sponsored ones of a higher gas price, or of the same
+// price and a lower hash, while a new or queued tx comes after all of them.
+func (pool *TxPool) sponsorSpend(sponsor common.Address, tx *types.Transaction, number *big.Int) *big.Int {
+	spend := new(big.Int)
+	if list := pool.pending[sponsor]; list != nil {
+		for _, own := range list.Flatten() {
+			if own.Hash() == tx.Hash() {
+				continue
+			}
+			spend.Add(spend, own.Cost())
+			if !own.Sponsored() {
+				spend.Add(spend, FsnCallFee(own, number, pool.currentState))
+			}
+		}
+	}
+	pending := pool.isPending(tx)
+	for hash := range pool.sponsored[sponsor] {
+		other := pool.all.Get(hash)
+		if other == nil {
+			delete(pool.sponsored[sponsor], hash)
+			continue
+		}
+		if hash == tx.Hash() || !pool.isPending(other) || (pending && !sponsoredBefore(other, tx)) {
+			continue
+		}
+		spend.Add(spend, new(big.Int).Mul(new(big.Int).SetUint64(other.Gas()), other.GasPrice()))
+		spend.Add(spend, FsnCallFee(other, number, pool.currentState))
+	}
+	if len(pool.sponsored[sponsor]) == 0 {
+		delete(pool.sponsored, sponsor)
+	}
+	return spend
+}
+
+// isPending reports whether tx is in the pending transactions.
+func (pool *TxPool) isPending(tx *types.Transaction) bool {
+	from, _ := types.Sender(pool.signer, tx) // already validated
+	list := pool.pending[from]
+	if list == nil {
+		return false
+	}
+	pending := list.txs.Get(tx.Nonce())
+	return pending != nil && pending.Hash() == tx.Hash()
+}
+
+// sponsoredBefore reports whether the sponsor of a and b pays for a first.
+func sponsoredBefore(a, b *types.Transaction) bool {
+	if c := a.GasPrice().Cmp(b.GasPrice()); c != 0 {
+		return c > 0
+	}
+	ha, hb := a.Hash(), b.Hash()
+	return bytes.Compare(ha[:], hb[:]) < 0
+}
+
+// evictStaleTickets removes the pooled buy ticket transactions, locals
+// included, which can no longer be valid for the next block: the ones pooled
+// for longer than the ticket lifetime and the ones whose ticket fails its
+// checks against the current head.
+func (pool *TxPool) evictStaleTickets(now time.Time) {
+	header := pool.chain.CurrentBlock().Header()
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	for hash, added := range pool.tickets {
+		tx := pool.all.Get(hash)
+		if tx == nil {
+			delete(pool.tickets, hash)
+			continue
+		}
+		if stale, reason := staleTicketTx(tx, added, now, pool.config.TicketTxLifetime, nextBlockNumber, header.Time); stale {
+			log.Debug("Evicting stale buy ticket transaction", "hash", hash, "reason", reason)
+			pool.removeTx(hash, true)
+			delete(pool.tickets, hash)
+		}
+	}
+}
+
+// staleTicketTx reports whether the buy ticket transaction tx, pooled at
+// added, has passed its selection window at now, and why.
+func staleTicketTx(tx *types.Transaction, added, now time.Time, lifetime time.Duration, nextBlockNumber *big.Int, timestamp uint64) (bool, string) {
+	if now.Sub(added) > lifetime {
+		return true, "lifetime"
+	}
+	param := common.FSNCallParam{}
+	if err := rlp.DecodeBytes(tx.Data(), &param); err != nil {
+		return true, err.Error()
+	}
+	var err error
+	switch param.Func {
+	case common.BuyTicketFunc:
+		buyTicketParam := common.BuyTicketParam{}
+		if err = rlp.DecodeBytes(param.Data, &buyTicketParam); err == nil {
+			err = buyTicketParam.Check(nextBlockNumber, timestamp)
+		}
+	case common.StakingBuyTicketFunc:
+		stakingBuyTicketParam := common.StakingBuyTicketParam{}
+		if err = rlp.DecodeBytes(param.Data, &stakingBuyTicketParam); err == nil {
+			err = stakingBuyTicketParam.Check(nextBlockNumber, timestamp)
+		}
+	}
+	if err != nil {
+		return true, err.Error()
+	}
+	return false, ""
+}
+
+const (
+	// maxFsnReplayDepth is the number of pooled transactions of an account
+	// at most replayed to validate an FSN call, the calls of higher nonces
+	// are validated against the state with the first of them applied
+	maxFsnReplayDepth = 64
+
+	// maxFsnReplays is the number of accounts whose replayed state is kept
+	maxFsnReplays = 256
+)
+
+// fsnReplay is the current state with pooled transactions of an account
+// applied, from the nonce of the account in the current state on
+type fsnReplay struct {
+	txs   []common.Hash // applied transactions in nonce order
+	state *state.StateDB
+}
+
+// fsnValidationState returns the state the FSN call of from with the given
+// nonce is validated against. It is the current state, with the pooled
+// transactions of from of lower nonces applied in order if PendingFsnState
+// is set, so that a call can depend on an earlier one not mined yet.
+//
+// At most maxFsnReplayDepth transactions are applied, and the state is kept
+// per account until the next reset, so that adding the transactions of an
+// account one nonce after the other applies each of them once.
+func (pool *TxPool) fsnValidationState(from common.Address, nonce uint64) *state.StateDB {
+	if !pool.config.PendingFsnState {
+		return pool.currentState
+	}
+	base := pool.currentState.GetNonce(from)
+	if nonce > base+maxFsnReplayDepth {
+		nonce = base + maxFsnReplayDepth
+	}
+	var txs types.Transactions
+	for n := base; n < nonce; n++ {
+		tx := pool.pooledTx(from, n)
+		if tx == nil {
+			break
+		}
+		txs = append(txs, tx)
+	}
+	if len(txs) == 0 {
+		return pool.currentState
+	}
+	// continue the replay of the account if its transactions are still pooled
+	statedb, applied := pool.currentState.Copy(), 0
+	if replay := pool.fsnStates[from]; replay != nil && len(replay.txs) <= len(txs) {
+		applied = len(replay.txs)
+		for i, hash := range replay.txs {
+			if txs[i].Hash() != hash {
+				applied = 0
+				break
+			}
+		}
+		if applied > 0 {
+			statedb = replay.state.Copy()
+		}
+	}
+	applied += ApplyPooledTxs(pool.chainconfig, pool.signer, statedb, pool.chain.CurrentBlock().Header(), txs[applied:])
+
+	if _, ok := pool.fsnStates[from]; !ok && len(pool.fsnStates) >= maxFsnReplays {
+		for addr := range pool.fsnStates {
+			delete(pool.fsnStates, addr)
+			break
+		}
+	}
+	hashes := make([]common.Hash, applied)
+	for i := range hashes {
+		hashes[i] = txs[i].Hash()
+	}
+	pool.fsnStates[from] = &fsnReplay{txs: hashes, state: statedb.Copy()}
+	return statedb
+}
+
+// ApplyPooledTxs applies the pooled transactions on statedb as if they were
+// included in the block following parent, stopping at the first one which
+// can not be applied. It returns the number of applied transactions.
+func ApplyPooledTxs(config *params.ChainConfig, signer types.Signer, statedb *state.StateDB, parent *types.Header, txs types.Transactions) int {
+	gp := new(GasPool).AddGas(math.MaxUint64)
+	for i, tx := range txs {
+		msg, err := tx.AsMessage(signer)
+		if err != nil {
+			return i
+		}
+		context := vm.Context{
+			CanTransfer: CanTransfer,
+			Transfer:    Transfer,
+			GetHash:     func(uint64) common.Hash { return common.Hash{} },
+			Origin:      msg.From(),
+			BlockNumber: new(big.Int).Add(parent.Number, big.NewInt(1)),
+			Time:        new(big.Int).SetUint64(uint64(time.Now().Unix())),
+			Difficulty:  new(big.Int).Set(parent.Difficulty),
+			GasLimit:    parent.GasLimit,
+			GasPrice:    new(big.Int).Set(msg.GasPrice()),
+			ParentTime:  new(big.Int).SetUint64(parent.Time),
+
+			CanTransferTimeLock: CanTransferTimeLock,
+			TransferTimeLock:    TransferTimeLock,
+		}
+		statedb.Prepare(tx.Hash(), common.Hash{}, i)
+		if _, _, _, err := ApplyMessage(vm.NewEVM(context, statedb, config, vm.Config{}), msg, gp); err != nil {
+			return i
+		}
+		statedb.Finalise(true)
+	}
+	return len(txs)
+}
@@ -0,0 +1,310 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/crypto"
+	"github.com/FusionFoundation/go-fusion/event"
+	"github.com/FusionFoundation/go-fusion/params"
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+func fsnCallTx(nonce uint64, gasPrice int64, fn common.FSNCallFunc) *types.Transaction {
+	data, _ := rlp.EncodeToBytes(&common.FSNCallParam{Func: fn})
+	return types.NewTransaction(nonce, common.FSNCallAddress, new(big.Int), 100000, big.NewInt(gasPrice), data)
+}
+
+func TestFsnCallReplacement(t *testing.T) {
+	const priceBump = 10
+	var (
+		send   = fsnCallTx(0, 100, common.SendAssetFunc)
+		ticket = fsnCallTx(1, 100, common.BuyTicketFunc)
+	)
+	tests := []struct {
+		name          string
+		tx, old, pool *types.Transaction
+		evict         *types.Transaction
+		err           error
+	}{
+		{"new nonce", fsnCallTx(2, 1, common.SendAssetFunc), nil, ticket, nil, nil},
+		{"underpriced replacement", fsnCallTx(0, 109, common.SendAssetFunc), send, nil, nil, ErrReplaceUnderpriced},
+		{"priced replacement", fsnCallTx(0, 110, common.TimeLockFunc), send, nil, nil, nil},
+		{"first ticket", fsnCallTx(0, 110, common.BuyTicketFunc), send, nil, nil, nil},
+		{"second ticket", fsnCallTx(2, 100, common.BuyTicketFunc), nil, ticket, ticket, nil},
+		{"second ticket replacing", fsnCallTx(0, 110, common.BuyTicketFunc), send, ticket, ticket, nil},
+		{"underpriced second ticket replacing", fsnCallTx(0, 109, common.BuyTicketFunc), send, ticket, nil, ErrReplaceUnderpriced},
+		{"ticket replacement", fsnCallTx(1, 110, common.BuyTicketFunc), ticket, ticket, nil, nil},
+		{"underpriced ticket replacement", fsnCallTx(1, 100, common.BuyTicketFunc), ticket, ticket, nil, ErrReplaceUnderpriced},
+		{"ticket replaced by another call", fsnCallTx(1, 110, common.SendAssetFunc), ticket, nil, nil, nil},
+	}
+	for _, test := range tests {
+		evict, err := checkFsnCallReplacement(test.tx, test.old, test.pool, priceBump)
+		if err != test.err {
+			t.Errorf("%s: error mismatch: have %v, want %v", test.name, err, test.err)
+		}
+		if evict != test.evict {
+			t.Errorf("%s: evicted transaction mismatch: have %v, want %v", test.name, evict, test.evict)
+		}
+	}
+}
+
+func TestSortByNonce(t *testing.T) {
+	signer := types.NewEIP155Signer(big.NewInt(1))
+	var txs []*types.Transaction
+	for _, nonces := range [][]uint64{{2, 0}, {1, 0, 1}} {
+		key, _ := crypto.GenerateKey()
+		for i, nonce := range nonces {
+			tx, _ := types.SignTx(fsnCallTx(nonce, int64(100+i), common.BuyTicketFunc), signer, key)
+			txs = append(txs, tx)
+		}
+	}
+	sorted := sortByNonce(signer, txs)
+	if len(sorted) != len(txs) {
+		t.Fatalf("sorted length mismatch: have %d, want %d", len(sorted), len(txs))
+	}
+	for i := 1; i < len(sorted); i++ {
+		prev, _ := types.Sender(signer, sorted[i-1])
+		from, _ := types.Sender(signer, sorted[i])
+		if prev != from {
+			continue
+		}
+		if sorted[i-1].Nonce() > sorted[i].Nonce() {
+			t.Errorf("transaction %d out of nonce order: %d after %d", i, sorted[i].Nonce(), sorted[i-1].Nonce())
+		}
+		// transactions of the same nonce keep the journal order
+		if sorted[i-1].Nonce() == sorted[i].Nonce() && sorted[i-1].GasPrice().Cmp(sorted[i].GasPrice()) > 0 {
+			t.Errorf("replacement %d moved before the replaced transaction", i)
+		}
+	}
+}
+
+func buyTicketTx(start, end uint64) *types.Transaction {
+	buy, _ := rlp.EncodeToBytes(&common.BuyTicketParam{Start: start, End: end})
+	data, _ := rlp.EncodeToBytes(&common.FSNCallParam{Func: common.BuyTicketFunc, Data: buy})
+	return types.NewTransaction(0, common.FSNCallAddress, new(big.Int), 100000, big.NewInt(100), data)
+}
+
+func TestStaleTicketTx(t *testing.T) {
+	const (
+		lifetime  = 10 * time.Minute
+		timestamp = uint64(1600000000)
+		month     = 30 * 24 * 3600
+	)
+	var (
+		now    = time.Now()
+		number = big.NewInt(1)
+	)
+	tests := []struct {
+		name  string
+		tx    *types.Transaction
+		added time.Time
+		stale bool
+	}{
+		{"fresh", buyTicketTx(timestamp, timestamp+month), now, false},
+		{"within lifetime", buyTicketTx(timestamp, timestamp+month), now.Add(-lifetime), false},
+		{"lifetime passed", buyTicketTx(timestamp, timestamp+month), now.Add(-lifetime - time.Second), true},
+		{"start too far", buyTicketTx(timestamp+4*3600, timestamp+4*3600+month), now, true},
+		{"end passed", buyTicketTx(timestamp-month, timestamp), now, true},
+	}
+	for _, test := range tests {
+		if stale, reason := staleTicketTx(test.tx, test.added, now, lifetime, number, timestamp); stale != test.stale {
+			t.Errorf("%s: stale mismatch: have %v (%s), want %v", test.name, stale, reason, test.stale)
+		}
+	}
+}
+
+func TestApplyPooledTxs(t *testing.T) {
+	var (
+		signer = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+		key, _ = crypto.GenerateKey()
+		from   = crypto.PubkeyToAddress(key.PublicKey)
+		parent = &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), GasLimit: 8000000, Time: 1600000000}
+	)
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	statedb.AddBalance(from, common.SystemAssetID, new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether)))
+
+	gen, _ := rlp.EncodeToBytes(&common.GenAssetParam{Name: "Test", Symbol: "TST", Decimals: 0, Total: big.NewInt(1000)})
+	data, _ := rlp.EncodeToBytes(&common.FSNCallParam{Func: common.GenAssetFunc, Data: gen})
+	genTx, _ := types.SignTx(types.NewTransaction(0, common.FSNCallAddress, new(big.Int), 100000, big.NewInt(1), data), signer, key)
+	// a transaction with a nonce gap is not applied
+	gapTx, _ := types.SignTx(fsnCallTx(2, 1, common.GenNotationFunc), signer, key)
+
+	ApplyPooledTxs(params.TestChainConfig, signer, statedb, parent, types.Transactions{genTx, gapTx})
+
+	assetID := GetUniqueHashFromTransaction(genTx)
+	if _, err := statedb.GetAsset(assetID); err != nil {
+		t.Fatalf("pooled asset not generated: %v", err)
+	}
+	if have := statedb.GetBalance(assetID, from); have.Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("asset balance mismatch: have %v, want 1000", have)
+	}
+	if have := statedb.GetNonce(from); have != 1 {
+		t.Errorf("nonce mismatch: have %d, want 1", have)
+	}
+}
+
+func TestApplySponsoredTx(t *testing.T) {
+	var (
+		signer        = types.NewEIP155Signer(params.TestChainConfig.ChainID)
+		key, _        = crypto.GenerateKey()
+		sponsorKey, _ = crypto.GenerateKey()
+		from          = crypto.PubkeyToAddress(key.PublicKey)
+		sponsor       = crypto.PubkeyToAddress(sponsorKey.PublicKey)
+		parent        = &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1), GasLimit: 8000000, Time: 1600000000}
+		funds         = new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))
+	)
+	tx, _ := types.SignTx(fsnCallTx(0, 1, common.GenNotationFunc), signer, key)
+	tx, _ = types.SignSponsor(tx, sponsorKey)
+
+	apply := func() *state.StateDB {
+		statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		statedb.AddBalance(sponsor, common.SystemAssetID, funds)
+		ApplyPooledTxs(params.TestChainConfig, signer, statedb, parent, types.Transactions{tx})
+		return statedb
+	}
+	// sponsored transactions are not applied before they are enabled
+	if statedb := apply(); statedb.GetNonce(from) != 0 {
+		t.Fatal("sponsored transaction applied before the fork")
+	}
+
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	statedb := apply()
+	if have := statedb.GetNonce(from); have != 1 {
+		t.Fatalf("nonce mismatch: have %d, want 1", have)
+	}
+	if statedb.GetNotation(from) == 0 {
+		t.Error("notation not generated for the sender")
+	}
+	if have := statedb.GetBalance(common.SystemAssetID, from); have.Sign() != 0 {
+		t.Errorf("sender charged: balance %v", have)
+	}
+	fee := FsnCallFee(tx, big.NewInt(2), statedb)
+	if spent := new(big.Int).Sub(funds, statedb.GetBalance(common.SystemAssetID, sponsor)); spent.Cmp(fee) <= 0 {
+		t.Errorf("sponsor spent %v, want the gas and the fee %v", spent, fee)
+	}
+}
+
+// fsnTestChain is a testBlockChain whose head is the genesis block, as the
+// FSN calls are validated against the number of the head.
+type fsnTestChain struct {
+	*testBlockChain
+}
+
+func (bc *fsnTestChain) CurrentBlock() *types.Block {
+	return types.NewBlock(&types.Header{
+		Number:     new(big.Int),
+		Difficulty: big.NewInt(1),
+		GasLimit:   bc.gasLimit,
+	}, nil, nil, nil)
+}
+
+func (bc *fsnTestChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	return bc.CurrentBlock()
+}
+
+func TestPooledSponsorSpend(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	blockchain := &fsnTestChain{&testBlockChain{statedb, 10000000, new(event.Feed)}}
+	pool := NewTxPool(testTxPoolConfig, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	sponsorKey, _ := crypto.GenerateKey()
+	sponsor := crypto.PubkeyToAddress(sponsorKey.PublicKey)
+	sponsored := func(gasPrice int64) *types.Transaction {
+		key, _ := crypto.GenerateKey()
+		tx, _ := types.SignTx(fsnCallTx(0, gasPrice, common.GenNotationFunc), pool.signer, key)
+		tx, _ = types.SignSponsor(tx, sponsorKey)
+		return tx
+	}
+	cost := func(tx *types.Transaction) *big.Int {
+		gas := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasPrice())
+		return gas.Add(gas, FsnCallFee(tx, big.NewInt(1), statedb))
+	}
+	high, low, third := sponsored(2), sponsored(1), sponsored(1)
+
+	// the sponsor pays for the two first transactions only
+	statedb.AddBalance(sponsor, common.SystemAssetID, new(big.Int).Add(cost(high), cost(low)))
+	if err := pool.addRemoteSync(high); err != nil {
+		t.Fatalf("failed to add the first sponsored transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(low); err != nil {
+		t.Fatalf("failed to add the second sponsored transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(third); err == nil {
+		t.Fatal("sponsored transaction added beyond the sponsor balance")
+	}
+	if pending, _ := pool.Stats(); pending != 2 {
+		t.Fatalf("pending transactions mismatch: have %d, want 2", pending)
+	}
+
+	// the lowest priced transaction is dropped once the sponsor pays for one
+	statedb.SetBalance(sponsor, common.SystemAssetID, cost(high))
+	<-pool.requestReset(nil, nil)
+	if pending, _ := pool.Stats(); pending != 1 {
+		t.Fatalf("pending transactions mismatch: have %d, want 1", pending)
+	}
+	if pool.Get(high.Hash()) == nil {
+		t.Error("highest priced sponsored transaction dropped")
+	}
+}
+
+func TestFsnValidationStateReplay(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	blockchain := &fsnTestChain{&testBlockChain{statedb, 10000000, new(event.Feed)}}
+	config := testTxPoolConfig
+	config.PendingFsnState = true
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	statedb.AddBalance(from, common.SystemAssetID, new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether)))
+
+	var txs []*types.Transaction
+	for i := uint64(0); i < maxFsnReplayDepth+6; i++ {
+		txs = append(txs, transaction(i, 100000, key))
+	}
+	pool.AddRemotesSync(txs[:3])
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if have := pool.fsnValidationState(from, 3).GetNonce(from); have != 3 {
+		t.Fatalf("replayed nonce mismatch: have %d, want 3", have)
+	}
+	// a later call continues the kept replay instead of applying the
+	// transactions again
+	marker := common.HexToAddress("0xbeef")
+	pool.fsnStates[from].state.AddBalance(marker, common.SystemAssetID, big.NewInt(1))
+	pool.addTxsLocked(txs[3:4], false)
+	replayed := pool.fsnValidationState(from, 4)
+	if have := replayed.GetNonce(from); have != 4 {
+		t.Fatalf("continued replay nonce mismatch: have %d, want 4", have)
+	}
+	if replayed.GetBalance(common.SystemAssetID, marker).Sign() == 0 {
+		t.Fatalf("kept replay not reused")
+	}
+	// a replaced transaction invalidates the kept replay
+	replacement := pricedTransaction(1, 100000, big.NewInt(2), key)
+	pool.addTxsLocked([]*types.Transaction{replacement}, false)
+	replayed = pool.fsnValidationState(from, 4)
+	if replayed.GetBalance(common.SystemAssetID, marker).Sign() != 0 {
+		t.Fatalf("replay of replaced transactions reused")
+	}
+	// the replay depth is bounded
+	pool.addTxsLocked(txs[4:], false)
+	if have := pool.fsnValidationState(from, uint64(len(txs))).GetNonce(from); have != maxFsnReplayDepth {
+		t.Fatalf("replay depth: have %d transactions applied, want %d", have, maxFsnReplayDepth)
+	}
+}
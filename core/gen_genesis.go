@@ -27,6 +27,7 @@ func (g Genesis) MarshalJSON() ([]byte, error) {
 		Coinbase         common.Address                              `json:"coinbase"`
 		Alloc            map[common.UnprefixedAddress]GenesisAccount `json:"alloc"      gencodec:"required"`
 		TicketCreateInfo *TicketsCreate                              `json:"ticketsCreate"`
+		FsnAccounts      []*FsnAccount                               `json:"fsnAccounts,omitempty"`
 		Number           math.HexOrDecimal64                         `json:"number"`
 		GasUsed          math.HexOrDecimal64                         `json:"gasUsed"`
 		ParentHash       common.Hash                                 `json:"parentHash"`
@@ -47,6 +48,7 @@ func (g Genesis) MarshalJSON() ([]byte, error) {
 		}
 	}
 	enc.TicketCreateInfo = g.TicketCreateInfo
+	enc.FsnAccounts = g.FsnAccounts
 	enc.Number = math.HexOrDecimal64(g.Number)
 	enc.GasUsed = math.HexOrDecimal64(g.GasUsed)
 	enc.ParentHash = g.ParentHash
@@ -65,6 +67,7 @@ func (g *Genesis) UnmarshalJSON(input []byte) error {
 		Coinbase         *common.Address                             `json:"coinbase"`
 		Alloc            map[common.UnprefixedAddress]GenesisAccount `json:"alloc"      gencodec:"required"`
 		TicketCreateInfo *TicketsCreate                              `json:"ticketsCreate"`
+		FsnAccounts      []*FsnAccount                               `json:"fsnAccounts,omitempty"`
 		Number           *math.HexOrDecimal64                        `json:"number"`
 		GasUsed          *math.HexOrDecimal64                        `json:"gasUsed"`
 		ParentHash       *common.Hash                                `json:"parentHash"`
@@ -109,6 +112,9 @@ func (g *Genesis) UnmarshalJSON(input []byte) error {
 	if dec.TicketCreateInfo != nil {
 		g.TicketCreateInfo = dec.TicketCreateInfo
 	}
+	if dec.FsnAccounts != nil {
+		g.FsnAccounts = dec.FsnAccounts
+	}
 	if dec.Number != nil {
 		g.Number = uint64(*dec.Number)
 	}
@@ -58,6 +58,7 @@ type Genesis struct {
 	Alloc      GenesisAlloc        `json:"alloc"      gencodec:"required"`
 
 	TicketCreateInfo *TicketsCreate `json:"ticketsCreate"`
+	FsnAccounts      []*FsnAccount  `json:"fsnAccounts,omitempty"` // devnet fixtures, see efsn fsn import-account
 
 	// These fields are used for consensus tests. Please don't use them
 	// in actual genesis blocks.
@@ -197,11 +198,15 @@ func SetupGenesisBlockWithOverride(db ethdb.Database, genesis *Genesis, override
 			genesis = DefaultGenesisBlock()
 		}
 		// Ensure the stored genesis matches with the given one.
-		hash := genesis.ToBlock(nil).Hash()
+		block, err := genesis.toBlock(nil)
+		if err != nil {
+			return genesis.Config, common.Hash{}, err
+		}
+		hash := block.Hash()
 		if hash != stored {
 			return genesis.Config, hash, &GenesisMismatchError{stored, hash}
 		}
-		block, err := genesis.Commit(db)
+		block, err = genesis.Commit(db)
 		if err != nil {
 			return genesis.Config, hash, err
 		}
@@ -210,7 +215,11 @@ func SetupGenesisBlockWithOverride(db ethdb.Database, genesis *Genesis, override
 
 	// Check whether the genesis block is already written.
 	if genesis != nil {
-		hash := genesis.ToBlock(nil).Hash()
+		block, err := genesis.toBlock(nil)
+		if err != nil {
+			return genesis.Config, common.Hash{}, err
+		}
+		hash := block.Hash()
 		if hash != stored {
 			return genesis.Config, hash, &GenesisMismatchError{stored, hash}
 		}
@@ -268,8 +277,17 @@ func (g *Genesis) configOrDefault(ghash common.Hash) *params.ChainConfig {
 }
 
 // ToBlock creates the genesis block and writes state of a genesis specification
-// to the given database (or discards it if nil).
+// to the given database (or discards it if nil). It panics if an FSN account
+// of the genesis cannot be imported, Commit returns the error instead.
 func (g *Genesis) ToBlock(db ethdb.Database) *types.Block {
+	block, err := g.toBlock(db)
+	if err != nil {
+		panic(err)
+	}
+	return block
+}
+
+func (g *Genesis) toBlock(db ethdb.Database) (*types.Block, error) {
 	if db == nil {
 		db = rawdb.NewMemoryDatabase()
 	}
@@ -283,6 +301,14 @@ func (g *Genesis) ToBlock(db ethdb.Database) *types.Block {
 		}
 	}
 
+	var fixtureTickets int
+	for _, account := range g.FsnAccounts {
+		if err := account.Apply(statedb, new(big.Int).SetUint64(g.Number)); err != nil {
+			return nil, fmt.Errorf("failed to import genesis FSN account %x: %v", account.Address, err)
+		}
+		fixtureTickets += len(account.Tickets)
+	}
+
 	if g.TicketCreateInfo != nil {
 		expireTime := g.TicketCreateInfo.Time + 30*24*3600
 		if g.Config.ChainID.Cmp(params.DevnetChainConfig.ChainID) == 0 {
@@ -305,6 +331,8 @@ func (g *Genesis) ToBlock(db ethdb.Database) *types.Block {
 		}
 		g.Mixhash, _ = statedb.UpdateTickets(common.Big0, g.Timestamp)
 		g.ExtraData = datong.GenerateGenesisExtraData(g.ExtraData, g.TicketCreateInfo.Count)
+	} else if fixtureTickets > 0 {
+		g.Mixhash, _ = statedb.UpdateTickets(common.Big0, g.Timestamp)
 	}
 
 	statedb.GenAsset(common.SystemAsset)
@@ -333,13 +361,16 @@ func (g *Genesis) ToBlock(db ethdb.Database) *types.Block {
 	statedb.Commit(false)
 	statedb.Database().TrieDB().Commit(root, true)
 
-	return types.NewBlock(head, nil, nil, nil)
+	return types.NewBlock(head, nil, nil, nil), nil
 }
 
 // Commit writes the block and state of a genesis specification to the database.
 // The block is committed as the canonical head block.
 func (g *Genesis) Commit(db ethdb.Database) (*types.Block, error) {
-	block := g.ToBlock(db)
+	block, err := g.toBlock(db)
+	if err != nil {
+		return nil, err
+	}
 	if block.Number().Sign() != 0 {
 		return nil, fmt.Errorf("can't commit genesis block with number > 0")
 	}
@@ -455,6 +486,39 @@ func DefaultDevnetGenesisBlock() *Genesis {
 	}
 }
 
+// DeveloperFsnGenesisBlock returns the 'efsn --dev.fsn' genesis block: the
+// devnet rules with blocks sealed on demand, and the faucet holding FSN, time
+// locked FSN and the genesis tickets so it can mine alone.
+func DeveloperFsnGenesisBlock(faucet common.Address) *Genesis {
+	config := *params.DevnetChainConfig
+	datongConfig := *config.DaTong
+	datongConfig.Period = 0
+	config.DaTong = &datongConfig
+
+	fsn := new(big.Int).Mul(big.NewInt(1e8), big.NewInt(1e18))
+	locked := new(big.Int).Mul(big.NewInt(1e6), big.NewInt(1e18))
+	return &Genesis{
+		Config:     &config,
+		Nonce:      1,
+		GasLimit:   params.GenesisGasLimit,
+		Difficulty: big.NewInt(1),
+		Alloc:      GenesisAlloc{},
+		TicketCreateInfo: &TicketsCreate{
+			Owner: faucet,
+			Count: 10,
+		},
+		FsnAccounts: []*FsnAccount{{
+			Address:  faucet,
+			Balances: map[common.Hash]*math.HexOrDecimal256{common.SystemAssetID: (*math.HexOrDecimal256)(fsn)},
+			TimeLocks: map[common.Hash][]FsnAccountTimeLockItem{common.SystemAssetID: {{
+				StartTime: 0,
+				EndTime:   math.HexOrDecimal64(common.TimeLockForever),
+				Value:     (*math.HexOrDecimal256)(locked),
+			}}},
+		}},
+	}
+}
+
 // DeveloperGenesisBlock returns the 'geth --dev' genesis block.
 func DeveloperGenesisBlock(period uint64, faucet common.Address) *Genesis {
 	// Override the default period to the user requested one
@@ -0,0 +1,105 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"math/big"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/ethdb"
+	"github.com/FusionFoundation/go-fusion/log"
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+// ReadHardForkReceipt retrieves the synthetic receipt of the assets moved by
+// the hard fork transfers of a block, nil if the block has none. The receipt
+// is not part of the block receipts and their root.
+func ReadHardForkReceipt(db ethdb.KeyValueReader, hash common.Hash, number uint64) *types.Receipt {
+	return readSyntheticReceipt(db, hardForkReceiptKey(number, hash), common.HardForkTxHash, hash, number)
+}
+
+// WriteHardForkReceipt stores the synthetic receipt of the hard fork transfer
+// logs of a block.
+func WriteHardForkReceipt(db ethdb.KeyValueWriter, hash common.Hash, number uint64, logs []*types.Log) {
+	writeSyntheticReceipt(db, hardForkReceiptKey(number, hash), logs)
+}
+
+// DeleteHardForkReceipt removes the hard fork receipt of a block.
+func DeleteHardForkReceipt(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(hardForkReceiptKey(number, hash)); err != nil {
+		log.Crit("Failed to delete hard fork receipt", "err", err)
+	}
+}
+
+// ReadTicketRetreatReceipt retrieves the synthetic receipt of the tickets
+// retreated by a block, nil if the block has none. Like the hard fork receipt
+// it is not part of the block receipts and their root.
+func ReadTicketRetreatReceipt(db ethdb.KeyValueReader, hash common.Hash, number uint64) *types.Receipt {
+	return readSyntheticReceipt(db, ticketRetreatReceiptKey(number, hash), common.TicketRetreatTxHash, hash, number)
+}
+
+// WriteTicketRetreatReceipt stores the synthetic receipt of the ticket retreat
+// logs of a block.
+func WriteTicketRetreatReceipt(db ethdb.KeyValueWriter, hash common.Hash, number uint64, logs []*types.Log) {
+	writeSyntheticReceipt(db, ticketRetreatReceiptKey(number, hash), logs)
+}
+
+// DeleteTicketRetreatReceipt removes the ticket retreat receipt of a block.
+func DeleteTicketRetreatReceipt(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(ticketRetreatReceiptKey(number, hash)); err != nil {
+		log.Crit("Failed to delete ticket retreat receipt", "err", err)
+	}
+}
+
+func readSyntheticReceipt(db ethdb.KeyValueReader, key []byte, txHash, hash common.Hash, number uint64) *types.Receipt {
+	data, _ := db.Get(key)
+	if len(data) == 0 {
+		return nil
+	}
+	var stored types.ReceiptForStorage
+	if err := rlp.DecodeBytes(data, &stored); err != nil {
+		log.Error("Invalid synthetic receipt RLP", "hash", hash, "tx", txHash, "err", err)
+		return nil
+	}
+	receipt := (*types.Receipt)(&stored)
+	receipt.TxHash = txHash
+	receipt.BlockHash = hash
+	receipt.BlockNumber = new(big.Int).SetUint64(number)
+	for i, l := range receipt.Logs {
+		l.TxHash = txHash
+		l.BlockHash = hash
+		l.BlockNumber = number
+		l.Index = uint(i)
+	}
+	return receipt
+}
+
+func writeSyntheticReceipt(db ethdb.KeyValueWriter, key []byte, logs []*types.Log) {
+	receipt := &types.Receipt{
+		Status: types.ReceiptStatusSuccessful,
+		Logs:   logs,
+	}
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	data, err := rlp.EncodeToBytes((*types.ReceiptForStorage)(receipt))
+	if err != nil {
+		log.Crit("Failed to encode synthetic receipt", "err", err)
+	}
+	if err := db.Put(key, data); err != nil {
+		log.Crit("Failed to store synthetic receipt", "err", err)
+	}
+}
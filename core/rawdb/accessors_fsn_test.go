@@ -0,0 +1,85 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/types"
+)
+
+// Tests hard fork receipt storage and retrieval operations.
+func TestHardForkReceiptStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	hash := common.BytesToHash([]byte{0x03, 0x14})
+	if receipt := ReadHardForkReceipt(db, hash, 786000); receipt != nil {
+		t.Fatalf("non existent receipt returned: %v", receipt)
+	}
+	logs := []*types.Log{
+		{Address: common.FSNCallAddress, Topics: []common.Hash{common.HardForkTransferTopic}, Data: []byte(`{"Type":"Balance"}`)},
+		{Address: common.FSNCallAddress, Topics: []common.Hash{common.HardForkTransferTopic}, Data: []byte(`{"Type":"Notation"}`)},
+	}
+	WriteHardForkReceipt(db, hash, 786000, logs)
+
+	receipt := ReadHardForkReceipt(db, hash, 786000)
+	if receipt == nil {
+		t.Fatalf("stored receipt not found")
+	}
+	if receipt.TxHash != common.HardForkTxHash || receipt.BlockHash != hash || receipt.BlockNumber.Uint64() != 786000 {
+		t.Fatalf("receipt location mismatch: tx %x, block %x #%v", receipt.TxHash, receipt.BlockHash, receipt.BlockNumber)
+	}
+	if len(receipt.Logs) != len(logs) {
+		t.Fatalf("log count mismatch: have %d, want %d", len(receipt.Logs), len(logs))
+	}
+	for i, l := range receipt.Logs {
+		if l.Index != uint(i) || !bytes.Equal(l.Data, logs[i].Data) {
+			t.Fatalf("log %d mismatch: index %d, data %s", i, l.Index, l.Data)
+		}
+	}
+	DeleteHardForkReceipt(db, hash, 786000)
+	if receipt := ReadHardForkReceipt(db, hash, 786000); receipt != nil {
+		t.Fatalf("deleted receipt returned: %v", receipt)
+	}
+}
+
+// Tests ticket retreat receipt storage apart from the hard fork receipt.
+func TestTicketRetreatReceiptStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	hash := common.BytesToHash([]byte{0x03, 0x14})
+	logs := []*types.Log{
+		{Address: common.FSNCallAddress, Topics: []common.Hash{common.TicketRetreatTopic}, Data: []byte(`{"Returned":false}`)},
+	}
+	WriteTicketRetreatReceipt(db, hash, 100, logs)
+	if receipt := ReadHardForkReceipt(db, hash, 100); receipt != nil {
+		t.Fatalf("retreat receipt returned as hard fork receipt: %v", receipt)
+	}
+	receipt := ReadTicketRetreatReceipt(db, hash, 100)
+	if receipt == nil {
+		t.Fatalf("stored receipt not found")
+	}
+	if receipt.TxHash != common.TicketRetreatTxHash || len(receipt.Logs) != 1 || receipt.Logs[0].TxHash != common.TicketRetreatTxHash {
+		t.Fatalf("receipt mismatch: tx %x, logs %d", receipt.TxHash, len(receipt.Logs))
+	}
+	DeleteTicketRetreatReceipt(db, hash, 100)
+	if receipt := ReadTicketRetreatReceipt(db, hash, 100); receipt != nil {
+		t.Fatalf("deleted receipt returned: %v", receipt)
+	}
+}
@@ -50,6 +50,9 @@ var (
 	blockBodyPrefix     = []byte("b") // blockBodyPrefix + num (uint64 big endian) + hash -> block body
 	blockReceiptsPrefix = []byte("r") // blockReceiptsPrefix + num (uint64 big endian) + hash -> block receipts
 
+	hardForkReceiptPrefix      = []byte("fsn-hardfork-r") // hardForkReceiptPrefix + num (uint64 big endian) + hash -> hard fork transfer receipt
+	ticketRetreatReceiptPrefix = []byte("fsn-retreat-r")  // ticketRetreatReceiptPrefix + num (uint64 big endian) + hash -> ticket retreat receipt
+
 	txLookupPrefix  = []byte("l") // txLookupPrefix + hash -> transaction/receipt lookup metadata
 	bloomBitsPrefix = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
 
@@ -140,6 +143,16 @@ func blockReceiptsKey(number uint64, hash common.Hash) []byte {
 	return append(append(blockReceiptsPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
 }
 
+// hardForkReceiptKey = hardForkReceiptPrefix + num (uint64 big endian) + hash
+func hardForkReceiptKey(number uint64, hash common.Hash) []byte {
+	return append(append(hardForkReceiptPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// ticketRetreatReceiptKey = ticketRetreatReceiptPrefix + num (uint64 big endian) + hash
+func ticketRetreatReceiptKey(number uint64, hash common.Hash) []byte {
+	return append(append(ticketRetreatReceiptPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
 // txLookupKey = txLookupPrefix + hash
 func txLookupKey(hash common.Hash) []byte {
 	return append(txLookupPrefix, hash.Bytes()...)
@@ -49,6 +49,10 @@ type Database interface {
 
 	// TrieDB retrieves the low level trie database used for data storage.
 	TrieDB() *trie.Database
+
+	// TicketCache retrieves the cache of the ticket sets shared by the states
+	// opened on this database.
+	TicketCache() *TicketCache
 }
 
 // Trie is a Ethereum Merkle Patricia trie.
@@ -107,16 +111,25 @@ func NewDatabase(db ethdb.Database) Database {
 // is safe for concurrent use and retains a lot of collapsed RLP trie nodes in a
 // large memory cache.
 func NewDatabaseWithCache(db ethdb.Database, cache int) Database {
+	return NewDatabaseWithTicketCache(db, cache, NewTicketCache(DefaultTicketCacheSize))
+}
+
+// NewDatabaseWithTicketCache creates a backing store for state like
+// NewDatabaseWithCache, caching the ticket sets in the given ticket cache. The
+// ticket cache should not be shared with the database of another chain.
+func NewDatabaseWithTicketCache(db ethdb.Database, cache int, tickets *TicketCache) Database {
 	csc, _ := lru.New(codeSizeCacheSize)
 	return &cachingDB{
 		db:            trie.NewDatabaseWithCache(db, cache),
 		codeSizeCache: csc,
+		tickets:       tickets,
 	}
 }
 
 type cachingDB struct {
 	db            *trie.Database
 	codeSizeCache *lru.Cache
+	tickets       *TicketCache
 }
 
 // OpenTrie opens the main account trie at a specific root hash.
@@ -161,3 +174,8 @@ func (db *cachingDB) ContractCodeSize(addrHash, codeHash common.Hash) (int, erro
 func (db *cachingDB) TrieDB() *trie.Database {
 	return db.db
 }
+
+// TicketCache retrieves the ticket cache of the database.
+func (db *cachingDB) TicketCache() *TicketCache {
+	return db.tickets
+}
@@ -35,11 +35,19 @@ type DumpAccount struct {
 	CodeHash  string                 `json:"codeHash"`
 	Code      string                 `json:"code,omitempty"`
 	Storage   map[common.Hash]string `json:"storage,omitempty"`
-	Address   *common.Address        `json:"address,omitempty"` // Address only present in iterative (line-by-line) mode
-	SecureKey hexutil.Bytes          `json:"key,omitempty"`     // If we don't have address, we can output the key
+	Assets    []DumpAsset            `json:"assets,omitempty"`    // by ascending asset ID
+	TimeLocks []AssetTimeLock        `json:"timeLocks,omitempty"` // by ascending asset ID
+	Address   *common.Address        `json:"address,omitempty"`   // Address only present in iterative (line-by-line) mode
+	SecureKey hexutil.Bytes          `json:"key,omitempty"`       // If we don't have address, we can output the key
 
 }
 
+// DumpAsset represents the balance of one asset of an account in the state
+type DumpAsset struct {
+	AssetID common.Hash `json:"assetID"`
+	Balance string      `json:"balance"`
+}
+
 // Dump represents the full dump in a collected format, as one large map
 type Dump struct {
 	Root     string                         `json:"root"`
@@ -73,6 +81,8 @@ func (d iterativeDump) onAccount(addr common.Address, account DumpAccount) {
 		CodeHash:  account.CodeHash,
 		Code:      account.Code,
 		Storage:   account.Storage,
+		Assets:    account.Assets,
+		TimeLocks: account.TimeLocks,
 		SecureKey: account.SecureKey,
 		Address:   nil,
 	}
@@ -106,6 +116,12 @@ func (s *StateDB) dump(c collector, excludeCode, excludeStorage, excludeMissingP
 			Root:     common.Bytes2Hex(data.Root[:]),
 			CodeHash: common.Bytes2Hex(data.CodeHash),
 		}
+		for _, balance := range obj.SortedBalances() {
+			account.Assets = append(account.Assets, DumpAsset{AssetID: balance.AssetID, Balance: balance.Balance.String()})
+		}
+		if timelocks := obj.SortedTimeLockBalances(); len(timelocks) > 0 {
+			account.TimeLocks = timelocks
+		}
 		if emptyAddress == addr {
 			// Preimage missing
 			missingPreimages++
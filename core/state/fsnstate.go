@@ -4,80 +4,23 @@ import (
 	"bytes"
 	"compress/gzip"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math/big"
-	"sync"
+	"sort"
 
 	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/types"
 	"github.com/FusionFoundation/go-fusion/crypto"
 	"github.com/FusionFoundation/go-fusion/log"
 	"github.com/FusionFoundation/go-fusion/rlp"
+	"github.com/FusionFoundation/go-fusion/trie"
+	lru "github.com/hashicorp/golang-lru"
 )
 
 //------------------------ StateDB -------------------------------------
 
-type CachedTickets struct {
-	hash    common.Hash
-	tickets common.TicketsDataSlice
-}
-
-const maxCachedTicketsCount = 101
-
-type CachedTicketSlice struct {
-	tickets [maxCachedTicketsCount]CachedTickets
-	start   int64
-	end     int64
-	rwlock  sync.RWMutex
-}
-
-var cachedTicketSlice = CachedTicketSlice{
-	tickets: [maxCachedTicketsCount]CachedTickets{},
-	start:   0,
-	end:     0,
-}
-
-func (cts *CachedTicketSlice) Add(hash common.Hash, tickets common.TicketsDataSlice) {
-	if cts.Get(hash) != nil {
-		return
-	}
-
-	elem := CachedTickets{
-		hash:    hash,
-		tickets: tickets.DeepCopy(),
-	}
-
-	cts.rwlock.Lock()
-	defer cts.rwlock.Unlock()
-
-	cts.tickets[cts.end] = elem
-	cts.end = (cts.end + 1) % maxCachedTicketsCount
-	if cts.end == cts.start {
-		cts.start = (cts.start + 1) % maxCachedTicketsCount
-	}
-}
-
-func (cts *CachedTicketSlice) Get(hash common.Hash) common.TicketsDataSlice {
-	if hash == (common.Hash{}) {
-		return common.TicketsDataSlice{}
-	}
-
-	cts.rwlock.RLock()
-	defer cts.rwlock.RUnlock()
-
-	for i := cts.start; i != cts.end; i = (i + 1) % maxCachedTicketsCount {
-		v := cts.tickets[i]
-		if v.hash == hash {
-			return v.tickets
-		}
-	}
-	return nil
-}
-
-func GetCachedTickets(hash common.Hash) common.TicketsDataSlice {
-	return cachedTicketSlice.Get(hash)
-}
-
 func calcTicketsStorageData(tickets common.TicketsDataSlice) ([]byte, error) {
 	blob, err := rlp.EncodeToBytes(&tickets)
 	if err != nil {
@@ -96,18 +39,6 @@ func calcTicketsStorageData(tickets common.TicketsDataSlice) ([]byte, error) {
 	return data, nil
 }
 
-func AddCachedTickets(hash common.Hash, tickets common.TicketsDataSlice) error {
-	data, err := calcTicketsStorageData(tickets)
-	if err != nil {
-		return fmt.Errorf("AddCachedTickets: %v", err)
-	}
-	if hash != crypto.Keccak256Hash(data) {
-		return fmt.Errorf("AddCachedTickets: hash mismatch")
-	}
-	cachedTicketSlice.Add(hash, tickets)
-	return nil
-}
-
 // CreateAccount explicitly creates a state object. If a state object with the address
 // already exists the balance is carried over to the new account.
 //
@@ -161,6 +92,28 @@ func (s *StateDB) GetAllBalances(addr common.Address) map[common.Hash]string {
 	return make(map[common.Hash]string)
 }
 
+// AssetBalance is the balance of one asset of an account
+type AssetBalance struct {
+	AssetID common.Hash `json:"assetID"`
+	Balance *big.Int    `json:"balance"`
+}
+
+// AssetTimeLock is the time lock balance of one asset of an account
+type AssetTimeLock struct {
+	AssetID  common.Hash      `json:"assetID"`
+	TimeLock *common.TimeLock `json:"timeLock"`
+}
+
+// GetSortedBalances returns the non zero balances of an address by ascending
+// asset ID, unlike GetAllBalances the result has a canonical order
+func (s *StateDB) GetSortedBalances(addr common.Address) []AssetBalance {
+	stateObject := s.getStateObject(addr)
+	if stateObject != nil {
+		return stateObject.SortedBalances()
+	}
+	return []AssetBalance{}
+}
+
 // Retrieve the balance from the given address or 0 if object not found
 func (s *StateDB) GetBalance(assetID common.Hash, addr common.Address) *big.Int {
 	stateObject := s.getStateObject(addr)
@@ -207,6 +160,16 @@ func (s *StateDB) GetAllTimeLockBalances(addr common.Address) map[common.Hash]*c
 	return make(map[common.Hash]*common.TimeLock)
 }
 
+// GetSortedTimeLockBalances returns the non empty time lock balances of an
+// address by ascending asset ID
+func (s *StateDB) GetSortedTimeLockBalances(addr common.Address) []AssetTimeLock {
+	stateObject := s.getStateObject(addr)
+	if stateObject != nil {
+		return stateObject.SortedTimeLockBalances()
+	}
+	return []AssetTimeLock{}
+}
+
 func (s *StateDB) SetData(addr common.Address, value []byte) common.Hash {
 	stateObject := s.GetOrNewStateObject(addr)
 	if stateObject != nil {
@@ -262,9 +225,10 @@ func (s *StateDB) AllTickets() (common.TicketsDataSlice, error) {
 	}
 
 	key := s.ticketsHash
-	ts := cachedTicketSlice.Get(key)
+	ts := s.db.TicketCache().Get(key)
 	if ts != nil {
-		s.tickets = ts.DeepCopy()
+		// ticket slices are copy-on-write, so the cached one can be shared
+		s.tickets = ts
 		return s.tickets, nil
 	}
 
@@ -295,7 +259,7 @@ func (s *StateDB) AllTickets() (common.TicketsDataSlice, error) {
 		return nil, fmt.Errorf("Unable to decode tickets, err: %v", err)
 	}
 	s.tickets = tickets
-	cachedTicketSlice.Add(key, s.tickets)
+	s.db.TicketCache().Add(key, s.tickets)
 	return s.tickets, nil
 }
 
@@ -309,7 +273,7 @@ func (s *StateDB) AddTicket(ticket common.Ticket) error {
 	if err != nil {
 		return fmt.Errorf("AddTicket error: %v", err)
 	}
-	s.tickets = tickets
+	s.setTickets(tickets)
 	return nil
 }
 
@@ -323,10 +287,57 @@ func (s *StateDB) RemoveTicket(id common.Hash) error {
 	if err != nil {
 		return fmt.Errorf("RemoveTicket error: %v", err)
 	}
-	s.tickets = tickets
+	s.setTickets(tickets)
 	return nil
 }
 
+// SpendTicket removes one unit of a ticket, and the ticket with its last unit
+func (s *StateDB) SpendTicket(id common.Hash) error {
+	tickets, err := s.AllTickets()
+	if err != nil {
+		return fmt.Errorf("SpendTicket error: %v", err)
+	}
+	tickets, err = tickets.SpendTicket(id)
+	if err != nil {
+		return fmt.Errorf("SpendTicket error: %v", err)
+	}
+	s.setTickets(tickets)
+	return nil
+}
+
+// setTickets replaces the tickets of the state. The ticket slices are never
+// modified in place, so journaling the previous slice is enough to revert.
+func (s *StateDB) setTickets(tickets common.TicketsDataSlice) {
+	s.journal.append(ticketsChange{prev: s.tickets})
+	s.tickets = tickets
+	s.ticketOwners = nil
+}
+
+// ticketRows returns the tickets of the state and the rows of owner in them.
+func (s *StateDB) ticketRows(owner common.Address) (common.TicketsDataSlice, []int, error) {
+	tickets, err := s.AllTickets()
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.ticketOwners == nil {
+		s.ticketOwners = tickets.OwnerRows()
+	}
+	return tickets, s.ticketOwners[owner], nil
+}
+
+// TicketsByOwner returns the tickets of owner
+func (s *StateDB) TicketsByOwner(owner common.Address) (common.TicketSlice, error) {
+	tickets, rows, err := s.ticketRows(owner)
+	if err != nil {
+		return nil, err
+	}
+	var res common.TicketSlice
+	for _, row := range rows {
+		res = append(res, tickets[row].ToTicketSlice()...)
+	}
+	return res, nil
+}
+
 func (s *StateDB) TotalNumberOfTickets() uint64 {
 	s.rwlock.RLock()
 	defer s.rwlock.RUnlock()
@@ -343,7 +354,7 @@ func (s *StateDB) UpdateTickets(blockNumber *big.Int, timestamp uint64) (common.
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("UpdateTickets: %v", err)
 	}
-	s.tickets = tickets
+	s.setTickets(tickets)
 
 	data, err := calcTicketsStorageData(s.tickets)
 	if err != nil {
@@ -351,20 +362,19 @@ func (s *StateDB) UpdateTickets(blockNumber *big.Int, timestamp uint64) (common.
 	}
 
 	hash := s.SetData(common.TicketKeyAddress, data)
-	cachedTicketSlice.Add(hash, s.tickets)
+	s.db.TicketCache().Add(hash, s.tickets)
 	return hash, nil
 }
 
+// ClearTickets removes every ticket of from, the ones not expired at
+// timestamp are converted back to time locked FSN of to.
 func (s *StateDB) ClearTickets(from, to common.Address, blockNumber *big.Int, timestamp uint64) {
-	tickets, err := s.AllTickets()
-	if err != nil {
+	tickets, rows, err := s.ticketRows(from)
+	if err != nil || len(rows) == 0 {
 		return
 	}
-	for i, v := range tickets {
-		if v.Owner != from {
-			continue
-		}
-		for _, ticket := range v.Tickets {
+	for _, row := range rows {
+		for _, ticket := range tickets[row].Tickets {
 			if ticket.ExpireTime <= timestamp {
 				continue
 			}
@@ -374,11 +384,15 @@ func (s *StateDB) ClearTickets(from, to common.Address, blockNumber *big.Int, ti
 				Value:     ticket.Value(),
 			})
 			s.AddTimeLockBalance(to, common.SystemAssetID, value, blockNumber, timestamp)
+			id := ticket.ID
+			s.addHardForkTransferLog(from, to, &common.HardForkTransfer{
+				Type:     common.HardForkTransferTicket,
+				TicketID: &id,
+				TimeLock: value,
+			})
 		}
-		tickets = append(tickets[:i], tickets[i+1:]...)
-		s.tickets = tickets
-		break
 	}
+	s.setTickets(tickets.RemoveRows(rows))
 }
 
 func (s *StateDB) TransferAll(from, to common.Address, blockNumber *big.Int, timestamp uint64) {
@@ -391,13 +405,26 @@ func (s *StateDB) TransferAll(from, to common.Address, blockNumber *big.Int, tim
 	s.ClearTickets(from, to, blockNumber, timestamp)
 
 	// burn notation
-	s.BurnNotation(from)
+	if notation := s.GetNotation(from); notation != 0 {
+		s.addHardForkTransferLog(from, to, &common.HardForkTransfer{
+			Type:     common.HardForkTransferNotation,
+			Notation: notation,
+		})
+	}
+	s.BurnNotation(from, blockNumber)
 
 	// transfer all balances
 	for i, v := range fromObject.data.BalancesVal {
 		k := fromObject.data.BalancesHash[i]
 		fromObject.SetBalance(k, new(big.Int))
 		s.AddBalance(to, k, v)
+		if v.Sign() > 0 {
+			s.addHardForkTransferLog(from, to, &common.HardForkTransfer{
+				Type:    common.HardForkTransferBalance,
+				AssetID: &k,
+				Value:   v,
+			})
+		}
 	}
 
 	// transfer all timelock balances
@@ -405,9 +432,35 @@ func (s *StateDB) TransferAll(from, to common.Address, blockNumber *big.Int, tim
 		k := fromObject.data.TimeLockBalancesHash[i]
 		fromObject.SetTimeLockBalance(k, new(common.TimeLock))
 		s.AddTimeLockBalance(to, k, v, blockNumber, timestamp)
+		if !v.IsEmpty() {
+			s.addHardForkTransferLog(from, to, &common.HardForkTransfer{
+				Type:     common.HardForkTransferTimeLock,
+				AssetID:  &k,
+				TimeLock: v,
+			})
+		}
 	}
 }
 
+// addHardForkTransferLog logs an asset moved by a hard fork. The logs belong to
+// no transaction, they are kept under HardForkTxHash and stored apart from the
+// receipts of the block (see rawdb.WriteHardForkReceipt).
+func (s *StateDB) addHardForkTransferLog(from, to common.Address, transfer *common.HardForkTransfer) {
+	data, err := json.Marshal(transfer)
+	if err != nil {
+		log.Error("Failed to encode hard fork transfer", "from", from, "to", to, "err", err)
+		return
+	}
+	thash, txIndex := s.thash, s.txIndex
+	s.Prepare(common.HardForkTxHash, s.bhash, txIndex)
+	s.AddLog(&types.Log{
+		Address: common.FSNCallAddress,
+		Topics:  []common.Hash{common.HardForkTransferTopic, from.Hash(), to.Hash()},
+		Data:    data,
+	})
+	s.Prepare(thash, s.bhash, txIndex)
+}
+
 // GetNotation wacom
 func (s *StateDB) GetNotation(addr common.Address) uint64 {
 	stateObject := s.getStateObject(addr)
@@ -423,7 +476,7 @@ func (s *StateDB) AllNotation() ([]common.Address, error) {
 }
 
 // GenNotation wacom
-func (s *StateDB) GenNotation(addr common.Address) error {
+func (s *StateDB) GenNotation(addr common.Address, blockNumber *big.Int) error {
 	stateObject := s.GetOrNewStateObject(addr)
 	if stateObject != nil {
 		if n := s.GetNotation(addr); n != 0 {
@@ -440,18 +493,20 @@ func (s *StateDB) GenNotation(addr common.Address) error {
 		s.setNotationCount(nextNotation)
 		s.setNotationToAddressLookup(newNotation, addr)
 		stateObject.SetNotation(newNotation)
+		s.addNotationRecord(newNotation, common.NotationGen, common.Address{}, addr, blockNumber)
 		return nil
 	}
 	return nil
 }
 
-func (s *StateDB) BurnNotation(addr common.Address) {
+func (s *StateDB) BurnNotation(addr common.Address, blockNumber *big.Int) {
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		notation := stateObject.Notation()
 		if notation != 0 {
 			s.setNotationToAddressLookup(notation, common.Address{})
 			stateObject.SetNotation(0)
+			s.addNotationRecord(notation, common.NotationBurn, addr, common.Address{}, blockNumber)
 		}
 	}
 }
@@ -501,14 +556,7 @@ func (s *StateDB) setNotationToAddressLookup(notation uint64, address common.Add
 
 // GetAddressByNotation wacom
 func (s *StateDB) GetAddressByNotation(notation uint64) (common.Address, error) {
-	buf := make([]byte, binary.MaxVarintLen64)
-	binary.PutUvarint(buf, notation)
-	data := s.GetStructData(common.NotationKeyAddress, buf)
-	if len(data) == 0 || data == nil {
-		return common.Address{}, fmt.Errorf("notation %v does not exist", notation)
-	}
-	var np notationPersist
-	err := rlp.DecodeBytes(data, &np)
+	np, err := s.notationLookup(notation)
 	if err != nil {
 		return common.Address{}, err
 	}
@@ -518,8 +566,57 @@ func (s *StateDB) GetAddressByNotation(notation uint64) (common.Address, error)
 	return np.Address, nil
 }
 
+// notationCacheSize is the number of notation lookups cached for all states
+const notationCacheSize = 8192
+
+// notationCacheKey identifies a notation lookup of a committed notation
+// storage trie.
+type notationCacheKey struct {
+	root     common.Hash
+	notation uint64
+}
+
+// cachedNotations keeps the decoded notation lookups of committed states, so
+// that batched resolutions against recent blocks do not read the struct data
+// of every notation again.
+var cachedNotations, _ = lru.New(notationCacheSize)
+
+// notationRoot returns the storage root of the notation lookups if it is
+// committed, only then the lookups can be shared with other states.
+func (s *StateDB) notationRoot() (common.Hash, bool) {
+	obj := s.getStateObject(common.NotationKeyAddress)
+	if obj == nil || len(obj.dirtyStorage) != 0 || len(obj.pendingStorage) != 0 {
+		return common.Hash{}, false
+	}
+	return obj.data.Root, true
+}
+
+func (s *StateDB) notationLookup(notation uint64) (*notationPersist, error) {
+	root, committed := s.notationRoot()
+	key := notationCacheKey{root, notation}
+	if committed {
+		if np, ok := cachedNotations.Get(key); ok {
+			return np.(*notationPersist), nil
+		}
+	}
+	buf := make([]byte, binary.MaxVarintLen64)
+	binary.PutUvarint(buf, notation)
+	data := s.GetStructData(common.NotationKeyAddress, buf)
+	if len(data) == 0 || data == nil {
+		return nil, fmt.Errorf("notation %v does not exist", notation)
+	}
+	np := new(notationPersist)
+	if err := rlp.DecodeBytes(data, np); err != nil {
+		return nil, err
+	}
+	if committed && s.Error() == nil {
+		cachedNotations.Add(key, np)
+	}
+	return np, nil
+}
+
 // TransferNotation wacom
-func (s *StateDB) TransferNotation(notation uint64, from common.Address, to common.Address) error {
+func (s *StateDB) TransferNotation(notation uint64, from common.Address, to common.Address, blockNumber *big.Int) error {
 	stateObjectFrom := s.GetOrNewStateObject(from)
 	if stateObjectFrom == nil {
 		return fmt.Errorf("Unable to get from address")
@@ -541,13 +638,74 @@ func (s *StateDB) TransferNotation(notation uint64, from common.Address, to comm
 		// need to clear notation to address
 		// user should transfer an old notation or can burn it like this
 		s.setNotationToAddressLookup(oldNotationTo, common.Address{})
+		s.addNotationRecord(oldNotationTo, common.NotationBurn, to, common.Address{}, blockNumber)
 	}
 	s.setNotationToAddressLookup(notation, to)
 	stateObjectTo.SetNotation(notation)
 	stateObjectFrom.SetNotation(0)
+	s.addNotationRecord(notation, common.NotationTransfer, from, to, blockNumber)
 	return nil
 }
 
+// the ownership history of a notation is an append-only log in struct data,
+// the record count is kept under the key without index
+func notationHistoryKey(notation uint64, index *uint64) []byte {
+	key := make([]byte, 0, 32)
+	key = append(key, []byte("NotationHistory")...)
+	key = append(key, common.Uint64ToBytes(notation)...)
+	if index != nil {
+		key = append(key, common.Uint64ToBytes(*index)...)
+	}
+	return key
+}
+
+func (s *StateDB) getNotationHistoryCount(notation uint64) uint64 {
+	data := s.GetStructData(common.NotationKeyAddress, notationHistoryKey(notation, nil))
+	if len(data) == 0 {
+		return 0
+	}
+	var count uint64
+	rlp.DecodeBytes(data, &count)
+	return count
+}
+
+func (s *StateDB) addNotationRecord(notation uint64, action common.NotationAction, from, to common.Address, blockNumber *big.Int) {
+	if blockNumber == nil || !common.IsNotationHistoryEnabled(blockNumber) {
+		return
+	}
+	record := common.NotationRecord{
+		Action:      action,
+		From:        from,
+		To:          to,
+		BlockNumber: blockNumber.Uint64(),
+	}
+	data, err := rlp.EncodeToBytes(&record)
+	if err != nil {
+		log.Error("addNotationRecord: unable to encode record", "err", err)
+		return
+	}
+	count := s.getNotationHistoryCount(notation)
+	s.SetStructData(common.NotationKeyAddress, notationHistoryKey(notation, &count), data)
+	count++
+	countData, _ := rlp.EncodeToBytes(count)
+	s.SetStructData(common.NotationKeyAddress, notationHistoryKey(notation, nil), countData)
+}
+
+// GetNotationHistory returns the ownership change records of a notation in order
+func (s *StateDB) GetNotationHistory(notation uint64) ([]common.NotationRecord, error) {
+	count := s.getNotationHistoryCount(notation)
+	records := make([]common.NotationRecord, 0, count)
+	for i := uint64(0); i < count; i++ {
+		data := s.GetStructData(common.NotationKeyAddress, notationHistoryKey(notation, &i))
+		var record common.NotationRecord
+		if err := rlp.DecodeBytes(data, &record); err != nil {
+			return nil, fmt.Errorf("GetNotationHistory: unable to decode record %d: %v", i, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
 // CalcNotationDisplay wacom
 func (s *StateDB) CalcNotationDisplay(notation uint64) uint64 {
 	if notation == 0 {
@@ -599,6 +757,201 @@ func (s *StateDB) GenAsset(asset common.Asset) error {
 	return nil
 }
 
+// assetSymbolKey is the struct data key of the asset registered with the
+// normalized symbol
+func assetSymbolKey(normalized string) []byte {
+	return append([]byte("symbol:"), normalized...)
+}
+
+// GetAssetIDBySymbol returns the ID of the asset registered with a symbol
+// which normalizes to the same form as symbol
+func (s *StateDB) GetAssetIDBySymbol(symbol string) (common.Hash, bool) {
+	normalized := common.NormalizeAssetSymbol(symbol)
+	if normalized == common.SystemAssetSymbol {
+		return common.SystemAssetID, true
+	}
+	data := s.GetStructData(common.AssetKeyAddress, assetSymbolKey(normalized))
+	if len(data) != common.HashLength {
+		return common.Hash{}, false
+	}
+	return common.BytesToHash(data), true
+}
+
+// RegisterAssetSymbol registers the normalized symbol of an asset, failing if
+// it collides with the symbol of another asset
+func (s *StateDB) RegisterAssetSymbol(symbol string, assetID common.Hash) error {
+	if id, ok := s.GetAssetIDBySymbol(symbol); ok {
+		return fmt.Errorf("asset symbol %s already registered by %s", symbol, id.String())
+	}
+	s.SetStructData(common.AssetKeyAddress, assetSymbolKey(common.NormalizeAssetSymbol(symbol)), assetID.Bytes())
+	return nil
+}
+
+// assetRestrictionKey is the struct data key of the transfer restriction
+// mode of an asset
+func assetRestrictionKey(assetID common.Hash) []byte {
+	return append([]byte("restriction:"), assetID[:]...)
+}
+
+// assetTransferListKey is the struct data key of an address entry in the
+// transfer list of an asset
+func assetTransferListKey(assetID common.Hash, addr common.Address) []byte {
+	key := append([]byte("transferList:"), assetID[:]...)
+	return append(key, addr[:]...)
+}
+
+// GetAssetTransferRestriction wacom
+func (s *StateDB) GetAssetTransferRestriction(assetID common.Hash) common.AssetTransferRestriction {
+	data := s.GetStructData(common.AssetKeyAddress, assetRestrictionKey(assetID))
+	if len(data) != 1 {
+		return common.AssetTransferUnrestricted
+	}
+	return common.AssetTransferRestriction(data[0])
+}
+
+// SetAssetTransferRestriction wacom
+func (s *StateDB) SetAssetTransferRestriction(assetID common.Hash, restriction common.AssetTransferRestriction) {
+	s.SetStructData(common.AssetKeyAddress, assetRestrictionKey(assetID), []byte{byte(restriction)})
+}
+
+// IsAssetTransferListed wacom
+func (s *StateDB) IsAssetTransferListed(assetID common.Hash, addr common.Address) bool {
+	return len(s.GetStructData(common.AssetKeyAddress, assetTransferListKey(assetID, addr))) > 0
+}
+
+// SetAssetTransferListed adds addr to or removes it from the transfer list
+// of an asset
+func (s *StateDB) SetAssetTransferListed(assetID common.Hash, addr common.Address, listed bool) {
+	data := []byte{} // empty data removes the entry
+	if listed {
+		data = []byte{1}
+	}
+	s.SetStructData(common.AssetKeyAddress, assetTransferListKey(assetID, addr), data)
+}
+
+// IsAssetTransferAllowed reports whether addr may send and receive the asset.
+// The owner of a restricted asset is always allowed.
+func (s *StateDB) IsAssetTransferAllowed(assetID common.Hash, addr common.Address) bool {
+	restriction := s.GetAssetTransferRestriction(assetID)
+	if restriction == common.AssetTransferUnrestricted {
+		return true
+	}
+	if asset, err := s.GetAsset(assetID); err == nil && asset.Owner == addr {
+		return true
+	}
+	listed := s.IsAssetTransferListed(assetID, addr)
+	if restriction == common.AssetTransferWhitelist {
+		return listed
+	}
+	return !listed
+}
+
+// assetOwnersKey is the struct data key of the key set owning an asset
+func assetOwnersKey(assetID common.Hash) []byte {
+	return append([]byte("owners:"), assetID[:]...)
+}
+
+// assetOwnerApprovalsKey is the struct data key of the keys which approved
+// the owner call with the given hash
+func assetOwnerApprovalsKey(hash common.Hash) []byte {
+	return append([]byte("ownerApprovals:"), hash[:]...)
+}
+
+// GetAssetOwnerSet returns the key set owning an asset, nil if the asset is
+// owned by a single account
+func (s *StateDB) GetAssetOwnerSet(assetID common.Hash) *common.AssetOwnerSet {
+	data := s.GetStructData(common.AssetKeyAddress, assetOwnersKey(assetID))
+	if len(data) == 0 {
+		return nil
+	}
+	owners := new(common.AssetOwnerSet)
+	if err := rlp.DecodeBytes(data, owners); err != nil {
+		return nil
+	}
+	return owners
+}
+
+// SetAssetOwnerSet wacom
+func (s *StateDB) SetAssetOwnerSet(assetID common.Hash, owners *common.AssetOwnerSet) {
+	data := []byte{} // empty data removes the set
+	if owners != nil {
+		data, _ = rlp.EncodeToBytes(owners)
+	}
+	s.SetStructData(common.AssetKeyAddress, assetOwnersKey(assetID), data)
+}
+
+// GetAssetOwnerApprovals returns the approvals of the owner call with the
+// given hash, nil if there are none
+func (s *StateDB) GetAssetOwnerApprovals(hash common.Hash) *common.AssetOwnerApprovals {
+	data := s.GetStructData(common.AssetKeyAddress, assetOwnerApprovalsKey(hash))
+	if len(data) == 0 {
+		return nil
+	}
+	var approvals common.AssetOwnerApprovals
+	if err := rlp.DecodeBytes(data, &approvals); err != nil {
+		return nil
+	}
+	return &approvals
+}
+
+// SetAssetOwnerApprovals wacom
+func (s *StateDB) SetAssetOwnerApprovals(hash common.Hash, approvals *common.AssetOwnerApprovals) {
+	data := []byte{} // empty data clears the approvals
+	if approvals != nil {
+		data, _ = rlp.EncodeToBytes(approvals)
+	}
+	s.SetStructData(common.AssetKeyAddress, assetOwnerApprovalsKey(hash), data)
+}
+
+// assetSupplyCountKey is the struct data key of the length of the supply
+// history of an asset
+func assetSupplyCountKey(assetID common.Hash) []byte {
+	return append([]byte("supplyCount:"), assetID[:]...)
+}
+
+// assetSupplyChangeKey is the struct data key of the index-th entry of the
+// supply history of an asset
+func assetSupplyChangeKey(assetID common.Hash, index uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, index)
+	return append(append([]byte("supply:"), assetID[:]...), key...)
+}
+
+// AddAssetSupplyChange appends a change to the supply history of an asset
+func (s *StateDB) AddAssetSupplyChange(assetID common.Hash, change *common.AssetSupplyChange) {
+	count := s.GetAssetSupplyChangeCount(assetID)
+	data, _ := rlp.EncodeToBytes(change)
+	s.SetStructData(common.AssetKeyAddress, assetSupplyChangeKey(assetID, count), data)
+	data, _ = rlp.EncodeToBytes(count + 1)
+	s.SetStructData(common.AssetKeyAddress, assetSupplyCountKey(assetID), data)
+}
+
+// GetAssetSupplyChangeCount returns the length of the supply history of an
+// asset
+func (s *StateDB) GetAssetSupplyChangeCount(assetID common.Hash) uint64 {
+	data := s.GetStructData(common.AssetKeyAddress, assetSupplyCountKey(assetID))
+	if len(data) == 0 {
+		return 0
+	}
+	var count uint64
+	rlp.DecodeBytes(data, &count)
+	return count
+}
+
+// GetAssetSupplyChange returns the index-th entry of the supply history of
+// an asset
+func (s *StateDB) GetAssetSupplyChange(assetID common.Hash, index uint64) (*common.AssetSupplyChange, error) {
+	data := s.GetStructData(common.AssetKeyAddress, assetSupplyChangeKey(assetID, index))
+	if len(data) == 0 {
+		return nil, fmt.Errorf("supply change %d not found", index)
+	}
+	change := new(common.AssetSupplyChange)
+	if err := rlp.DecodeBytes(data, change); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
 // UpdateAsset wacom
 func (s *StateDB) UpdateAsset(asset common.Asset) error {
 	/** to update a asset we just overwrite it
@@ -695,6 +1048,171 @@ func (s *StateDB) RemoveSwap(id common.Hash) error {
 	return nil
 }
 
+// GetEscrow wacom
+func (s *StateDB) GetEscrow(id common.Hash) (common.Escrow, error) {
+	data := s.GetStructData(common.EscrowKeyAddress, id.Bytes())
+	if len(data) == 0 {
+		return common.Escrow{}, fmt.Errorf("escrow not found")
+	}
+	var escrow common.Escrow
+	if err := rlp.DecodeBytes(data, &escrow); err != nil {
+		return common.Escrow{}, err
+	}
+	return escrow, nil
+}
+
+// AddEscrow wacom
+func (s *StateDB) AddEscrow(escrow common.Escrow) error {
+	if _, err := s.GetEscrow(escrow.ID); err == nil {
+		return fmt.Errorf("%s escrow exists", escrow.ID.String())
+	}
+	data, err := rlp.EncodeToBytes(&escrow)
+	if err != nil {
+		return err
+	}
+	s.SetStructData(common.EscrowKeyAddress, escrow.ID.Bytes(), data)
+	return nil
+}
+
+// RemoveEscrow deletes a claimed escrow
+func (s *StateDB) RemoveEscrow(id common.Hash) error {
+	if _, err := s.GetEscrow(id); err != nil {
+		return err
+	}
+	s.SetStructData(common.EscrowKeyAddress, id.Bytes(), []byte{})
+	return nil
+}
+
+// GetStream wacom
+func (s *StateDB) GetStream(id common.Hash) (common.Stream, error) {
+	data := s.GetStructData(common.StreamKeyAddress, id.Bytes())
+	if len(data) == 0 {
+		return common.Stream{}, fmt.Errorf("stream not found")
+	}
+	var stream common.Stream
+	if err := rlp.DecodeBytes(data, &stream); err != nil {
+		return common.Stream{}, err
+	}
+	return stream, nil
+}
+
+// AddStream wacom
+func (s *StateDB) AddStream(stream common.Stream) error {
+	if _, err := s.GetStream(stream.ID); err == nil {
+		return fmt.Errorf("%s stream exists", stream.ID.String())
+	}
+	return s.putStream(&stream)
+}
+
+// UpdateStream wacom
+func (s *StateDB) UpdateStream(stream common.Stream) error {
+	if _, err := s.GetStream(stream.ID); err != nil {
+		return err
+	}
+	return s.putStream(&stream)
+}
+
+func (s *StateDB) putStream(stream *common.Stream) error {
+	data, err := rlp.EncodeToBytes(stream)
+	if err != nil {
+		return err
+	}
+	s.SetStructData(common.StreamKeyAddress, stream.ID.Bytes(), data)
+	return nil
+}
+
+// RemoveStream deletes a fully withdrawn stream
+func (s *StateDB) RemoveStream(id common.Hash) error {
+	if _, err := s.GetStream(id); err != nil {
+		return err
+	}
+	s.SetStructData(common.StreamKeyAddress, id.Bytes(), []byte{})
+	return nil
+}
+
+// conditionKey is the struct data key of a condition of the condition registry
+func conditionKey(id common.Hash) []byte {
+	return append([]byte("condition:"), id[:]...)
+}
+
+// conditionalTransferKey is the struct data key of a conditional transfer
+func conditionalTransferKey(id common.Hash) []byte {
+	return append([]byte("transfer:"), id[:]...)
+}
+
+// GetCondition wacom
+func (s *StateDB) GetCondition(id common.Hash) (common.Condition, error) {
+	data := s.GetStructData(common.ConditionKeyAddress, conditionKey(id))
+	if len(data) == 0 {
+		return common.Condition{}, fmt.Errorf("condition not found")
+	}
+	var condition common.Condition
+	if err := rlp.DecodeBytes(data, &condition); err != nil {
+		return common.Condition{}, err
+	}
+	return condition, nil
+}
+
+// AddCondition wacom
+func (s *StateDB) AddCondition(condition common.Condition) error {
+	if _, err := s.GetCondition(condition.ID); err == nil {
+		return fmt.Errorf("%s condition exists", condition.ID.String())
+	}
+	return s.putCondition(&condition)
+}
+
+// UpdateCondition wacom
+func (s *StateDB) UpdateCondition(condition common.Condition) error {
+	if _, err := s.GetCondition(condition.ID); err != nil {
+		return err
+	}
+	return s.putCondition(&condition)
+}
+
+func (s *StateDB) putCondition(condition *common.Condition) error {
+	data, err := rlp.EncodeToBytes(condition)
+	if err != nil {
+		return err
+	}
+	s.SetStructData(common.ConditionKeyAddress, conditionKey(condition.ID), data)
+	return nil
+}
+
+// GetConditionalTransfer wacom
+func (s *StateDB) GetConditionalTransfer(id common.Hash) (common.ConditionalTransfer, error) {
+	data := s.GetStructData(common.ConditionKeyAddress, conditionalTransferKey(id))
+	if len(data) == 0 {
+		return common.ConditionalTransfer{}, fmt.Errorf("conditional transfer not found")
+	}
+	var transfer common.ConditionalTransfer
+	if err := rlp.DecodeBytes(data, &transfer); err != nil {
+		return common.ConditionalTransfer{}, err
+	}
+	return transfer, nil
+}
+
+// AddConditionalTransfer wacom
+func (s *StateDB) AddConditionalTransfer(transfer common.ConditionalTransfer) error {
+	if _, err := s.GetConditionalTransfer(transfer.ID); err == nil {
+		return fmt.Errorf("%s conditional transfer exists", transfer.ID.String())
+	}
+	data, err := rlp.EncodeToBytes(&transfer)
+	if err != nil {
+		return err
+	}
+	s.SetStructData(common.ConditionKeyAddress, conditionalTransferKey(transfer.ID), data)
+	return nil
+}
+
+// RemoveConditionalTransfer deletes a settled conditional transfer
+func (s *StateDB) RemoveConditionalTransfer(id common.Hash) error {
+	if _, err := s.GetConditionalTransfer(id); err != nil {
+		return err
+	}
+	s.SetStructData(common.ConditionKeyAddress, conditionalTransferKey(id), []byte{})
+	return nil
+}
+
 /** swaps
 *
  */
@@ -770,6 +1288,48 @@ func (s *StateDB) RemoveMultiSwap(id common.Hash) error {
 	return nil
 }
 
+// swapDeletionKey is the struct data key of the list of recalled or taken swaps
+// to delete at the given block
+func swapDeletionKey(number uint64) []byte {
+	key := []byte("deleteAt")
+	return append(key, common.Uint64ToBytes(number)...)
+}
+
+// ScheduleSwapDeletion records that the removed swap (or multi swap if addr is
+// the multi swap key address) with the given id is deleted at the given block
+func (s *StateDB) ScheduleSwapDeletion(addr common.Address, id common.Hash, number uint64) {
+	key := swapDeletionKey(number)
+	var ids []common.Hash
+	if data := s.GetStructData(addr, key); len(data) > 0 {
+		rlp.DecodeBytes(data, &ids)
+	}
+	ids = append(ids, id)
+	data, err := rlp.EncodeToBytes(ids)
+	if err != nil {
+		return
+	}
+	s.SetStructData(addr, key, data)
+}
+
+// DeleteScheduledSwaps deletes the removed swaps and multi swaps scheduled for
+// deletion at the given block. The deletion is part of the block finalization,
+// no transaction pays for it nor is refunded.
+func (s *StateDB) DeleteScheduledSwaps(number uint64) {
+	key := swapDeletionKey(number)
+	for _, addr := range []common.Address{common.SwapKeyAddress, common.MultiSwapKeyAddress} {
+		data := s.GetStructData(addr, key)
+		if len(data) == 0 {
+			continue
+		}
+		var ids []common.Hash
+		rlp.DecodeBytes(data, &ids)
+		for _, id := range ids {
+			s.DeleteStructData(addr, id.Bytes())
+		}
+		s.DeleteStructData(addr, key)
+	}
+}
+
 /** ReportIllegal
  */
 
@@ -790,6 +1350,459 @@ func (s *StateDB) AddReport(report []byte) error {
 	return nil
 }
 
+// reportRecord is a report stored by the reported miner and height. The raw
+// report is not kept, the slot alone identifies duplicates.
+type reportRecord struct {
+	Miner  common.Address
+	Number uint64
+	Expiry uint64 // epoch at whose start the record is deleted
+}
+
+// reportKey is the struct data key of the report of miner at number
+func reportKey(miner common.Address, number uint64) []byte {
+	key := append([]byte("report"), miner.Bytes()...)
+	return append(key, common.Uint64ToBytes(number)...)
+}
+
+// reportExpiryKey is the struct data key of the list of reports expiring at
+// the given epoch
+func reportExpiryKey(epoch uint64) []byte {
+	return append([]byte("reportExpiry"), common.Uint64ToBytes(epoch)...)
+}
+
+// IsReported tells whether the double mining of miner at number was reported
+func (s *StateDB) IsReported(miner common.Address, number uint64) bool {
+	return len(s.GetStructData(common.ReportKeyAddress, reportKey(miner, number))) > 0
+}
+
+// AddReportAt records the report of miner at number until the expiry epoch
+func (s *StateDB) AddReportAt(miner common.Address, number uint64, expiry uint64) error {
+	if s.IsReported(miner, number) {
+		return fmt.Errorf("AddReport error: report exists")
+	}
+	record := reportRecord{Miner: miner, Number: number, Expiry: expiry}
+	data, err := rlp.EncodeToBytes(&record)
+	if err != nil {
+		return err
+	}
+	key := reportExpiryKey(expiry)
+	var records []reportRecord
+	if list := s.GetStructData(common.ReportKeyAddress, key); len(list) > 0 {
+		if err := rlp.DecodeBytes(list, &records); err != nil {
+			return fmt.Errorf("AddReport error: decode expiry list of epoch %d: %v", expiry, err)
+		}
+	}
+	list, err := rlp.EncodeToBytes(append(records, record))
+	if err != nil {
+		return err
+	}
+	s.SetStructData(common.ReportKeyAddress, reportKey(miner, number), data)
+	s.SetStructData(common.ReportKeyAddress, key, list)
+	return nil
+}
+
+// DeleteExpiredReports deletes the reports expiring at epoch and returns the
+// number of cleared storage slots
+func (s *StateDB) DeleteExpiredReports(epoch uint64) (int, error) {
+	key := reportExpiryKey(epoch)
+	data := s.GetStructData(common.ReportKeyAddress, key)
+	if len(data) == 0 {
+		return 0, nil
+	}
+	var records []reportRecord
+	if err := rlp.DecodeBytes(data, &records); err != nil {
+		return 0, fmt.Errorf("DeleteExpiredReports error: decode expiry list of epoch %d: %v", epoch, err)
+	}
+	cleared := 0
+	for _, r := range records {
+		cleared += s.DeleteStructData(common.ReportKeyAddress, reportKey(r.Miner, r.Number))
+	}
+	return cleared + s.DeleteStructData(common.ReportKeyAddress, key), nil
+}
+
+// MigrateReport moves a report stored by the hash of its raw data to the
+// record of miner at number, it returns false if there is no such report. A
+// second report of the same miner and number is deleted without a record.
+func (s *StateDB) MigrateReport(report []byte, miner common.Address, number uint64, expiry uint64) (bool, error) {
+	if !s.IsReportExist(report) {
+		return false, nil
+	}
+	s.DeleteStructData(common.ReportKeyAddress, crypto.Keccak256(report))
+	if s.IsReported(miner, number) {
+		return true, nil
+	}
+	if err := s.AddReportAt(miner, number, expiry); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+/** TypedCall
+ */
+
+// GetTypedCallNonce returns the nonce of the next typed call signed by addr
+func (s *StateDB) GetTypedCallNonce(addr common.Address) uint64 {
+	data := s.GetStructData(common.TypedCallKeyAddress, addr.Bytes())
+	if len(data) == 0 {
+		return 0
+	}
+	var nonce uint64
+	rlp.DecodeBytes(data, &nonce)
+	return nonce
+}
+
+// SetTypedCallNonce wacom
+func (s *StateDB) SetTypedCallNonce(addr common.Address, nonce uint64) {
+	data, _ := rlp.EncodeToBytes(nonce)
+	s.SetStructData(common.TypedCallKeyAddress, addr.Bytes(), data)
+}
+
+/** StakingKey
+ */
+
+// GetStakingKey returns the key authorized to buy tickets for owner
+func (s *StateDB) GetStakingKey(owner common.Address) common.Address {
+	return common.BytesToAddress(s.GetStructData(common.StakingKeyAddress, owner.Bytes()))
+}
+
+// SetStakingKey wacom
+func (s *StateDB) SetStakingKey(owner common.Address, key common.Address) {
+	data := []byte{} // empty data clears the key
+	if key != (common.Address{}) {
+		data = key.Bytes()
+	}
+	s.SetStructData(common.StakingKeyAddress, owner.Bytes(), data)
+}
+
+/** TicketPurchases
+ */
+
+// ticketPurchasesKey is the struct data key of the tickets bought by owner in
+// the current rate limit window
+func ticketPurchasesKey(owner common.Address) []byte {
+	return append([]byte("purchases-"), owner[:]...)
+}
+
+// GetTicketPurchases returns the number of tickets owner bought in the given
+// rate limit window
+func (s *StateDB) GetTicketPurchases(owner common.Address, window uint64) uint64 {
+	data := s.GetStructData(common.TicketKeyAddress, ticketPurchasesKey(owner))
+	if len(data) != 16 || binary.BigEndian.Uint64(data[:8]) != window {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data[8:])
+}
+
+// SetTicketPurchases wacom
+func (s *StateDB) SetTicketPurchases(owner common.Address, window uint64, count uint64) {
+	data := make([]byte, 16)
+	binary.BigEndian.PutUint64(data[:8], window)
+	binary.BigEndian.PutUint64(data[8:], count)
+	s.SetStructData(common.TicketKeyAddress, ticketPurchasesKey(owner), data)
+}
+
+/** TicketRetreats
+ */
+
+// ticketRetreatsKey is the struct data key of the retreat record of owner
+func ticketRetreatsKey(owner common.Address) []byte {
+	return append([]byte("retreats-"), owner[:]...)
+}
+
+// GetTicketRetreatRecord returns the cumulative retreat accounting of owner
+func (s *StateDB) GetTicketRetreatRecord(owner common.Address) *common.TicketRetreatRecord {
+	record := &common.TicketRetreatRecord{LostValue: new(big.Int)}
+	data := s.GetStructData(common.TicketKeyAddress, ticketRetreatsKey(owner))
+	if len(data) != 0 {
+		rlp.DecodeBytes(data, record)
+	}
+	return record
+}
+
+// AddTicketRetreat adds a retreated ticket to the record of its owner and
+// logs it in the ticket retreat receipt of the block
+func (s *StateDB) AddTicketRetreat(retreat *common.TicketRetreat) {
+	record := s.GetTicketRetreatRecord(retreat.Owner)
+	record.Retreats++
+	if !retreat.Returned {
+		record.Lost++
+		record.LostValue.Add(record.LostValue, retreat.Value)
+	}
+	data, _ := rlp.EncodeToBytes(record)
+	s.SetStructData(common.TicketKeyAddress, ticketRetreatsKey(retreat.Owner), data)
+	s.addTicketRetreatLog(retreat)
+}
+
+// addTicketRetreatLog logs a retreated ticket. Like the hard fork transfer
+// logs, the logs are kept under TicketRetreatTxHash and stored apart from the
+// receipts of the block (see rawdb.WriteTicketRetreatReceipt).
+func (s *StateDB) addTicketRetreatLog(retreat *common.TicketRetreat) {
+	data, err := json.Marshal(retreat)
+	if err != nil {
+		log.Error("Failed to encode ticket retreat", "ticket", retreat.TicketID, "err", err)
+		return
+	}
+	thash, txIndex := s.thash, s.txIndex
+	s.Prepare(common.TicketRetreatTxHash, s.bhash, txIndex)
+	s.AddLog(&types.Log{
+		Address: common.FSNCallAddress,
+		Topics:  []common.Hash{common.TicketRetreatTopic, retreat.Owner.Hash(), retreat.TicketID},
+		Data:    data,
+	})
+	s.Prepare(thash, s.bhash, txIndex)
+}
+
+/** EpochSummary
+ */
+
+// epochSummaryKey is the struct data key of the summary of epoch
+func epochSummaryKey(epoch uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, epoch)
+	return key
+}
+
+// GetEpochSummary returns the summary committed by the last block of epoch
+func (s *StateDB) GetEpochSummary(epoch uint64) (*common.EpochSummary, error) {
+	data := s.GetStructData(common.EpochKeyAddress, epochSummaryKey(epoch))
+	if len(data) == 0 {
+		return nil, fmt.Errorf("epoch summary %d not found", epoch)
+	}
+	summary := new(common.EpochSummary)
+	if err := rlp.DecodeBytes(data, summary); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// SetEpochSummary wacom
+func (s *StateDB) SetEpochSummary(summary *common.EpochSummary) error {
+	data, err := rlp.EncodeToBytes(summary)
+	if err != nil {
+		return err
+	}
+	s.SetStructData(common.EpochKeyAddress, epochSummaryKey(summary.Epoch), data)
+	return nil
+}
+
+// epochRewardsKey is the struct data key of the block rewards credited in
+// epoch
+func epochRewardsKey(epoch uint64) []byte {
+	return append([]byte("rewards"), epochSummaryKey(epoch)...)
+}
+
+// GetEpochRewards returns the block rewards credited in epoch since the epoch
+// summary fork, nil if none were recorded
+func (s *StateDB) GetEpochRewards(epoch uint64) *big.Int {
+	data := s.GetStructData(common.EpochKeyAddress, epochRewardsKey(epoch))
+	if len(data) == 0 {
+		return nil
+	}
+	return new(big.Int).SetBytes(data)
+}
+
+// AddEpochRewards records a block reward credited in epoch
+func (s *StateDB) AddEpochRewards(epoch uint64, reward *big.Int) {
+	total := s.GetEpochRewards(epoch)
+	if total == nil {
+		total = new(big.Int)
+	}
+	s.SetStructData(common.EpochKeyAddress, epochRewardsKey(epoch), total.Add(total, reward).Bytes())
+}
+
+/** FsnCallFee
+ */
+
+// fsnCallFeeKey is the struct data key of the fee entry of an FSN call
+func fsnCallFeeKey(funcType common.FSNCallFunc) []byte {
+	return append([]byte("fee-"), byte(funcType))
+}
+
+// fsnCallFeeApprovalsKey is the struct data key of the governors which
+// approved a fee change
+func fsnCallFeeApprovalsKey(hash common.Hash) []byte {
+	return append([]byte("approvals-"), hash.Bytes()...)
+}
+
+// GetFsnCallFeeEntry returns the fee set by the governors, nil if the static
+// fee applies
+func (s *StateDB) GetFsnCallFeeEntry(funcType common.FSNCallFunc) *common.FsnCallFeeEntry {
+	data := s.GetStructData(common.FeeScheduleKeyAddress, fsnCallFeeKey(funcType))
+	if len(data) == 0 {
+		return nil
+	}
+	var entry common.FsnCallFeeEntry
+	if err := rlp.DecodeBytes(data, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+// SetFsnCallFeeEntry wacom
+func (s *StateDB) SetFsnCallFeeEntry(funcType common.FSNCallFunc, entry *common.FsnCallFeeEntry) {
+	data, _ := rlp.EncodeToBytes(entry)
+	s.SetStructData(common.FeeScheduleKeyAddress, fsnCallFeeKey(funcType), data)
+}
+
+// GetFsnCallFeeApprovals returns the governors which approved the fee change
+// with the given hash
+func (s *StateDB) GetFsnCallFeeApprovals(hash common.Hash) []common.Address {
+	data := s.GetStructData(common.FeeScheduleKeyAddress, fsnCallFeeApprovalsKey(hash))
+	if len(data) == 0 {
+		return nil
+	}
+	var approvals []common.Address
+	rlp.DecodeBytes(data, &approvals)
+	return approvals
+}
+
+// SetFsnCallFeeApprovals wacom
+func (s *StateDB) SetFsnCallFeeApprovals(hash common.Hash, approvals []common.Address) {
+	data := []byte{} // empty data clears the approvals
+	if len(approvals) != 0 {
+		data, _ = rlp.EncodeToBytes(approvals)
+	}
+	s.SetStructData(common.FeeScheduleKeyAddress, fsnCallFeeApprovalsKey(hash), data)
+}
+
+/** Bridge
+ */
+
+// GetBridgeDeposit returns the attestations of a deposit, nil if it was never
+// attested
+func (s *StateDB) GetBridgeDeposit(id common.Hash) *common.BridgeDeposit {
+	data := s.GetStructData(common.BridgeKeyAddress, id.Bytes())
+	if len(data) == 0 {
+		return nil
+	}
+	var deposit common.BridgeDeposit
+	if err := rlp.DecodeBytes(data, &deposit); err != nil {
+		return nil
+	}
+	return &deposit
+}
+
+// SetBridgeDeposit wacom
+func (s *StateDB) SetBridgeDeposit(id common.Hash, deposit *common.BridgeDeposit) {
+	data, _ := rlp.EncodeToBytes(deposit)
+	s.SetStructData(common.BridgeKeyAddress, id.Bytes(), data)
+}
+
+/** Governance
+ */
+
+var proposalCountKey = []byte("proposal-count")
+
+// proposalIndexKey is the struct data key of the ID of the index-th proposal
+func proposalIndexKey(index uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, index)
+	return append([]byte("proposal-index-"), key...)
+}
+
+// proposalVoteKey is the struct data key of the vote of addr on a proposal
+func proposalVoteKey(id common.Hash, addr common.Address) []byte {
+	return append(append([]byte("vote-"), id.Bytes()...), addr.Bytes()...)
+}
+
+// GetProposal wacom
+func (s *StateDB) GetProposal(id common.Hash) (common.Proposal, error) {
+	data := s.GetStructData(common.GovernanceKeyAddress, id.Bytes())
+	if len(data) == 0 {
+		return common.Proposal{}, fmt.Errorf("proposal not found")
+	}
+	var proposal common.Proposal
+	if err := rlp.DecodeBytes(data, &proposal); err != nil {
+		return common.Proposal{}, err
+	}
+	return proposal, nil
+}
+
+// AddProposal stores a new proposal and appends it to the proposal list
+func (s *StateDB) AddProposal(proposal common.Proposal) error {
+	if _, err := s.GetProposal(proposal.ID); err == nil {
+		return fmt.Errorf("%s proposal exists", proposal.ID.String())
+	}
+	count := s.GetProposalCount()
+	s.SetStructData(common.GovernanceKeyAddress, proposalIndexKey(count), proposal.ID.Bytes())
+	data, _ := rlp.EncodeToBytes(count + 1)
+	s.SetStructData(common.GovernanceKeyAddress, proposalCountKey, data)
+	return s.UpdateProposal(proposal)
+}
+
+// UpdateProposal wacom
+func (s *StateDB) UpdateProposal(proposal common.Proposal) error {
+	data, err := rlp.EncodeToBytes(&proposal)
+	if err != nil {
+		return err
+	}
+	s.SetStructData(common.GovernanceKeyAddress, proposal.ID.Bytes(), data)
+	return nil
+}
+
+// GetProposalCount returns the number of proposals ever created
+func (s *StateDB) GetProposalCount() uint64 {
+	data := s.GetStructData(common.GovernanceKeyAddress, proposalCountKey)
+	if len(data) == 0 {
+		return 0
+	}
+	var count uint64
+	rlp.DecodeBytes(data, &count)
+	return count
+}
+
+// GetProposalID returns the ID of the index-th proposal
+func (s *StateDB) GetProposalID(index uint64) common.Hash {
+	return common.BytesToHash(s.GetStructData(common.GovernanceKeyAddress, proposalIndexKey(index)))
+}
+
+// GetProposalVote returns the vote of addr on a proposal, nil if it did not vote
+func (s *StateDB) GetProposalVote(id common.Hash, addr common.Address) *common.ProposalVote {
+	data := s.GetStructData(common.GovernanceKeyAddress, proposalVoteKey(id, addr))
+	if len(data) == 0 {
+		return nil
+	}
+	var vote common.ProposalVote
+	if err := rlp.DecodeBytes(data, &vote); err != nil {
+		return nil
+	}
+	return &vote
+}
+
+// SetProposalVote wacom
+func (s *StateDB) SetProposalVote(id common.Hash, addr common.Address, vote *common.ProposalVote) {
+	data, _ := rlp.EncodeToBytes(vote)
+	s.SetStructData(common.GovernanceKeyAddress, proposalVoteKey(id, addr), data)
+}
+
+// structDataCacheSize is the number of decoded struct data values a state keeps
+const structDataCacheSize = 1024
+
+// structDataCacheKey identifies a struct data value. Every SetStructData bumps
+// the nonce of the key address, so the nonce versions the cached values.
+type structDataCacheKey struct {
+	addr    common.Address
+	keyHash common.Hash
+	nonce   uint64
+}
+
+func (s *StateDB) cachedStructData(key structDataCacheKey) ([]byte, bool) {
+	if s.structDataCache == nil {
+		return nil, false
+	}
+	if data, ok := s.structDataCache.Get(key); ok {
+		return common.CopyBytes(data.([]byte)), true
+	}
+	return nil, false
+}
+
+func (s *StateDB) cacheStructData(key structDataCacheKey, data []byte) {
+	if s.structDataCache == nil {
+		s.structDataCache, _ = lru.New(structDataCacheSize)
+	}
+	s.structDataCache.Add(key, common.CopyBytes(data))
+}
+
 // GetStructData wacom
 func (s *StateDB) GetStructData(addr common.Address, key []byte) []byte {
 	if key == nil {
@@ -798,6 +1811,10 @@ func (s *StateDB) GetStructData(addr common.Address, key []byte) []byte {
 	stateObject := s.GetOrNewStateObject(addr)
 	if stateObject != nil {
 		keyHash := crypto.Keccak256Hash(key)
+		cacheKey := structDataCacheKey{addr, keyHash, stateObject.Nonce()}
+		if data, ok := s.cachedStructData(cacheKey); ok {
+			return data
+		}
 		keyIndex := new(big.Int)
 		keyIndex.SetBytes(keyHash[:])
 		info := stateObject.GetState(s.db, keyHash)
@@ -815,6 +1832,7 @@ func (s *StateDB) GetStructData(addr common.Address, key []byte) []byte {
 			}
 			copy(data[start:end], tempData[common.HashLength-end+start:])
 		}
+		s.cacheStructData(cacheKey, data)
 		return data
 	}
 
@@ -853,9 +1871,61 @@ func (s *StateDB) SetStructData(addr common.Address, key, value []byte) {
 			stateObject.SetState(s.db, tempKey, tempData)
 		}
 		stateObject.SetNonce(stateObject.Nonce() + 1)
+		s.cacheStructData(structDataCacheKey{addr, keyHash, stateObject.Nonce()}, value)
 	}
 }
 
+// DeleteStructData clears all storage slots of the value stored under key, which
+// removes them from the storage trie. It returns the number of cleared slots.
+func (s *StateDB) DeleteStructData(addr common.Address, key []byte) int {
+	if key == nil {
+		return 0
+	}
+	stateObject := s.getStateObject(addr)
+	if stateObject == nil {
+		return 0
+	}
+	keyHash := crypto.Keccak256Hash(key)
+	info := stateObject.GetState(s.db, keyHash)
+	if info == (common.Hash{}) {
+		return 0
+	}
+	length := common.BytesToInt(info[common.HashLength/2 : common.HashLength/2+4])
+	keyIndex := new(big.Int)
+	keyIndex.SetBytes(keyHash[:])
+	stateObject.SetState(s.db, keyHash, common.Hash{})
+	for i := 0; i < length; i++ {
+		tempIndex := big.NewInt(int64(i))
+		tempKey := crypto.Keccak256Hash(tempIndex.Bytes(), keyIndex.Bytes())
+		stateObject.SetState(s.db, tempKey, common.Hash{})
+	}
+	stateObject.SetNonce(stateObject.Nonce() + 1)
+	s.cacheStructData(structDataCacheKey{addr, keyHash, stateObject.Nonce()}, []byte{})
+	return length + 1
+}
+
+// AssetHoldings sums the plain balances of assetID over all accounts and the
+// time lock balances usable at timestamp. It iterates the whole committed
+// state, uncommitted changes are not included.
+func (s *StateDB) AssetHoldings(assetID common.Hash, timestamp uint64) (balances, timeLocked *big.Int, err error) {
+	balances, timeLocked = new(big.Int), new(big.Int)
+	it := trie.NewIterator(s.trie.NodeIterator(nil))
+	for it.Next() {
+		var data Account
+		if err := rlp.DecodeBytes(it.Value, &data); err != nil {
+			return nil, nil, err
+		}
+		balances.Add(balances, data.GetBalance(assetID))
+		if timelock := data.GetTimeLockBalance(assetID); timelock != nil && !timelock.IsEmpty() {
+			timeLocked.Add(timeLocked, timelock.GetSpendableValue(timestamp, timestamp))
+		}
+	}
+	if it.Err != nil {
+		return nil, nil, it.Err
+	}
+	return balances, timeLocked, nil
+}
+
 //------------------------ stateObject ----------------------------------
 
 func (acc *Account) GetBalance(assetID common.Hash) *big.Int {
@@ -867,6 +1937,15 @@ func (acc *Account) GetBalance(assetID common.Hash) *big.Int {
 	return common.Big0
 }
 
+func (acc *Account) GetTimeLockBalance(assetID common.Hash) *common.TimeLock {
+	for i, v := range acc.TimeLockBalancesHash {
+		if v == assetID {
+			return acc.TimeLockBalancesVal[i]
+		}
+	}
+	return nil
+}
+
 func (s *stateObject) balanceAssetIndex(assetID common.Hash) int {
 	for i, v := range s.data.BalancesHash {
 		if v == assetID {
@@ -996,6 +2075,46 @@ func (s *stateObject) setNotation(notation uint64) {
 	s.data.Notaion = notation
 }
 
+// sortedAssetIndexes returns the indexes of the asset IDs of an account in
+// ascending ID order. The account keeps its assets in the order they were
+// first touched, which is part of the state root and can not change.
+func sortedAssetIndexes(ids []common.Hash) []int {
+	indexes := make([]int, len(ids))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return bytes.Compare(ids[indexes[i]][:], ids[indexes[j]][:]) < 0
+	})
+	return indexes
+}
+
+// SortedBalances returns the non zero balances of the account by ascending
+// asset ID
+func (s *stateObject) SortedBalances() []AssetBalance {
+	balances := make([]AssetBalance, 0, len(s.data.BalancesHash))
+	for _, i := range sortedAssetIndexes(s.data.BalancesHash) {
+		if s.data.BalancesVal[i].Sign() != 0 {
+			balances = append(balances, AssetBalance{AssetID: s.data.BalancesHash[i], Balance: new(big.Int).Set(s.data.BalancesVal[i])})
+		}
+	}
+	return balances
+}
+
+// SortedTimeLockBalances returns the non empty time lock balances of the
+// account by ascending asset ID
+func (s *stateObject) SortedTimeLockBalances() []AssetTimeLock {
+	balances := make([]AssetTimeLock, 0, len(s.data.TimeLockBalancesHash))
+	for _, i := range sortedAssetIndexes(s.data.TimeLockBalancesHash) {
+		if !s.data.TimeLockBalancesVal[i].IsEmpty() {
+			balances = append(balances, AssetTimeLock{AssetID: s.data.TimeLockBalancesHash[i], TimeLock: s.data.TimeLockBalancesVal[i].Clone()})
+		}
+	}
+	return balances
+}
+
+// CopyBalances returns the non zero balances of the account. Use
+// SortedBalances where the order matters.
 func (s *stateObject) CopyBalances() map[common.Hash]string {
 	retBalances := make(map[common.Hash]string)
 	for i, v := range s.data.BalancesHash {
@@ -1016,6 +2135,8 @@ func (s *stateObject) GetBalance(assetID common.Hash) *big.Int {
 	return s.data.GetBalance(assetID)
 }
 
+// CopyTimeLockBalances returns the non empty time lock balances of the
+// account. Use SortedTimeLockBalances where the order matters.
 func (s *stateObject) CopyTimeLockBalances() map[common.Hash]*common.TimeLock {
 	retBalances := make(map[common.Hash]*common.TimeLock)
 	for i, v := range s.data.TimeLockBalancesHash {
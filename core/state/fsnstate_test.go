@@ -0,0 +1,65 @@
+package state
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+)
+
+func TestStructDataCache(t *testing.T) {
+	db := NewDatabase(rawdb.NewMemoryDatabase())
+	statedb, _ := New(common.Hash{}, common.Hash{}, db)
+	addr, key := common.SwapKeyAddress, []byte("key")
+	long := bytes.Repeat([]byte{0xaa}, 3*common.HashLength+1)
+
+	statedb.SetStructData(addr, key, long)
+	data := statedb.GetStructData(addr, key)
+	if !bytes.Equal(data, long) {
+		t.Fatalf("have %x, want %x", data, long)
+	}
+	// the cached value is not shared with the callers
+	data[0] = 0
+	if data := statedb.GetStructData(addr, key); !bytes.Equal(data, long) {
+		t.Fatalf("cached value modified by a caller: %x", data)
+	}
+
+	// a reverted write is not served from the cache once the nonce it was
+	// cached under is reached again by a write of another key
+	snap := statedb.Snapshot()
+	statedb.SetStructData(addr, key, []byte("reverted"))
+	statedb.RevertToSnapshot(snap)
+	statedb.SetStructData(addr, []byte("other"), []byte("other"))
+	if data := statedb.GetStructData(addr, key); !bytes.Equal(data, long) {
+		t.Fatalf("after revert: have %x, want %x", data, long)
+	}
+	statedb.SetStructData(addr, key, []byte("redone"))
+	if data := statedb.GetStructData(addr, key); string(data) != "redone" {
+		t.Fatalf("after redo: have %q, want redone", data)
+	}
+
+	// writes to a copy are not seen by the original state
+	cpy := statedb.Copy()
+	cpy.SetStructData(addr, key, []byte("copy"))
+	if data := statedb.GetStructData(addr, key); string(data) != "redone" {
+		t.Errorf("write of a copy seen by the original: %q", data)
+	}
+	if data := cpy.GetStructData(addr, key); string(data) != "copy" {
+		t.Errorf("copy: have %q, want copy", data)
+	}
+
+	// deleted values are cached as empty
+	statedb.DeleteStructData(addr, key)
+	if data := statedb.GetStructData(addr, key); len(data) != 0 {
+		t.Errorf("deleted value returned: %q", data)
+	}
+
+	// the committed state is read without the cache
+	statedb.SetStructData(addr, key, long)
+	root, _ := statedb.Commit(false)
+	fresh, _ := New(root, common.Hash{}, db)
+	if data := fresh.GetStructData(addr, key); !bytes.Equal(data, long) {
+		t.Errorf("committed value: have %x, want %x", data, long)
+	}
+}
@@ -136,6 +136,9 @@ type (
 	addLogChange struct {
 		txhash common.Hash
 	}
+	ticketsChange struct {
+		prev common.TicketsDataSlice
+	}
 	addPreimageChange struct {
 		hash common.Hash
 	}
@@ -256,6 +259,15 @@ func (ch addLogChange) dirtied() *common.Address {
 	return nil
 }
 
+func (ch ticketsChange) revert(s *StateDB) {
+	s.tickets = ch.prev
+	s.ticketOwners = nil
+}
+
+func (ch ticketsChange) dirtied() *common.Address {
+	return nil
+}
+
 func (ch addPreimageChange) revert(s *StateDB) {
 	delete(s.preimages, ch.hash)
 }
@@ -18,6 +18,7 @@ package state
 
 import (
 	"bytes"
+	"math/big"
 	"testing"
 
 	"github.com/FusionFoundation/go-fusion/common"
@@ -44,3 +45,25 @@ func BenchmarkCutCustomTrim(b *testing.B) {
 		common.TrimLeftZeroes(value[:])
 	}
 }
+
+func TestSortedBalances(t *testing.T) {
+	var (
+		a, b, c = common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03")
+		locked  = common.NewTimeLock(&common.TimeLockItem{StartTime: 1, EndTime: common.TimeLockForever, Value: big.NewInt(7)})
+	)
+	// the assets are kept in the order they were touched
+	obj := newObject(nil, common.Address{}, Account{
+		BalancesHash:         []common.Hash{c, a, b},
+		BalancesVal:          []*big.Int{big.NewInt(3), big.NewInt(1), new(big.Int)},
+		TimeLockBalancesHash: []common.Hash{b, a},
+		TimeLockBalancesVal:  []*common.TimeLock{locked, new(common.TimeLock)},
+	})
+	balances := obj.SortedBalances()
+	if len(balances) != 2 || balances[0].AssetID != a || balances[1].AssetID != c || balances[1].Balance.Int64() != 3 {
+		t.Errorf("got balances %v, want 1 of %x and 3 of %x", balances, a, c)
+	}
+	timelocks := obj.SortedTimeLockBalances()
+	if len(timelocks) != 1 || timelocks[0].AssetID != b || timelocks[0].TimeLock.Items[0].Value.Int64() != 7 {
+		t.Errorf("got time locks %v, want 7 of %x", timelocks, b)
+	}
+}
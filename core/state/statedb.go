@@ -32,6 +32,7 @@ import (
 	"github.com/FusionFoundation/go-fusion/metrics"
 	"github.com/FusionFoundation/go-fusion/rlp"
 	"github.com/FusionFoundation/go-fusion/trie"
+	lru "github.com/hashicorp/golang-lru"
 )
 
 type revision struct {
@@ -95,9 +96,13 @@ type StateDB struct {
 	validRevisions []revision
 	nextRevisionId int
 
-	ticketsHash common.Hash
-	tickets     common.TicketsDataSlice
-	rwlock      sync.RWMutex
+	ticketsHash  common.Hash
+	tickets      common.TicketsDataSlice
+	ticketOwners map[common.Address][]int // rows of every owner in tickets, built on first use
+	rwlock       sync.RWMutex
+
+	// Decoded struct data values, dropped on revert (see GetStructData)
+	structDataCache *lru.Cache
 
 	// Measurements gathered during execution for debugging purposes
 	AccountReads   time.Duration
@@ -160,6 +165,8 @@ func (s *StateDB) Reset(root common.Hash) error {
 	s.clearJournalAndRefund()
 	s.ticketsHash = common.Hash{}
 	s.tickets = nil
+	s.ticketOwners = nil
+	s.structDataCache = nil
 	return nil
 }
 
@@ -617,6 +624,9 @@ func (s *StateDB) RevertToSnapshot(revid int) {
 	// Replay the journal to undo changes and remove invalidated snapshots
 	s.journal.revert(s, snapshot)
 	s.validRevisions = s.validRevisions[:idx]
+
+	// Reverted writes may be redone with the same nonces but other values
+	s.structDataCache = nil
 }
 
 // GetRefund returns the current value of the refund counter.
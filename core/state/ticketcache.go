@@ -0,0 +1,165 @@
+package state
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/crypto"
+)
+
+// DefaultTicketCacheSize is the default number of ticket sets kept in the ticket cache
+const DefaultTicketCacheSize = 101
+
+// TicketCacheStats wacom
+type TicketCacheStats struct {
+	Size   int    `json:"size"`
+	Len    int    `json:"len"`
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// TicketCache keeps the most recently used ticket sets by their hash (the
+// MixDigest of the block), shared by all states opened on the same Database
+// without copying as ticket slices are copy-on-write. Lookups are served
+// from a map under a read lock, so concurrent block validation and ticket
+// selection do not serialize; they only stamp the entry with an access tick
+// and the entry with the oldest tick is evicted when a set is added.
+type TicketCache struct {
+	hits    uint64 // accessed atomically, keep 64-bit aligned
+	misses  uint64
+	tick    uint64 // access counter, accessed atomically
+	size    int
+	entries map[common.Hash]*ticketCacheEntry
+	rwlock  sync.RWMutex
+}
+
+type ticketCacheEntry struct {
+	used    uint64 // tick of the last access, accessed atomically
+	tickets common.TicketsDataSlice
+}
+
+// NewTicketCache creates a ticket cache keeping size ticket sets.
+func NewTicketCache(size int) *TicketCache {
+	return &TicketCache{
+		size:    size,
+		entries: make(map[common.Hash]*ticketCacheEntry, size),
+	}
+}
+
+// touch marks the entry as the most recently used one
+func (tc *TicketCache) touch(entry *ticketCacheEntry) {
+	atomic.StoreUint64(&entry.used, atomic.AddUint64(&tc.tick, 1))
+}
+
+// evict drops the least recently used entries until at most size are left,
+// the caller must hold the write lock
+func (tc *TicketCache) evict(size int) {
+	for len(tc.entries) > size {
+		var (
+			oldest common.Hash
+			used   uint64
+			found  bool
+		)
+		for hash, entry := range tc.entries {
+			if !found || entry.used < used {
+				oldest, used, found = hash, entry.used, true
+			}
+		}
+		delete(tc.entries, oldest)
+	}
+}
+
+// Add caches a ticket set by its hash.
+func (tc *TicketCache) Add(hash common.Hash, tickets common.TicketsDataSlice) {
+	tc.rwlock.RLock()
+	entry, exist := tc.entries[hash]
+	if exist {
+		tc.touch(entry)
+	}
+	tc.rwlock.RUnlock()
+	if exist {
+		return
+	}
+
+	tc.rwlock.Lock()
+	defer tc.rwlock.Unlock()
+
+	if _, exist := tc.entries[hash]; exist {
+		return
+	}
+	tc.evict(tc.size - 1)
+	entry = &ticketCacheEntry{tickets: tickets}
+	tc.touch(entry)
+	tc.entries[hash] = entry
+}
+
+// Get returns the cached ticket set of hash, nil if it is not cached.
+func (tc *TicketCache) Get(hash common.Hash) common.TicketsDataSlice {
+	if hash == (common.Hash{}) {
+		return common.TicketsDataSlice{}
+	}
+
+	tc.rwlock.RLock()
+	entry, ok := tc.entries[hash]
+	if ok {
+		tc.touch(entry)
+	}
+	tc.rwlock.RUnlock()
+
+	if ok {
+		atomic.AddUint64(&tc.hits, 1)
+		return entry.tickets
+	}
+	atomic.AddUint64(&tc.misses, 1)
+	return nil
+}
+
+// Resize changes the number of cached ticket sets, keeping the most recently
+// used ones
+func (tc *TicketCache) Resize(size int) error {
+	if size <= 0 {
+		return fmt.Errorf("invalid ticket cache size %d", size)
+	}
+	tc.rwlock.Lock()
+	defer tc.rwlock.Unlock()
+
+	tc.evict(size)
+	tc.size = size
+	return nil
+}
+
+// Purge drops all cached ticket sets
+func (tc *TicketCache) Purge() {
+	tc.rwlock.Lock()
+	defer tc.rwlock.Unlock()
+
+	tc.entries = make(map[common.Hash]*ticketCacheEntry, tc.size)
+}
+
+// Stats returns the size and usage of the cache
+func (tc *TicketCache) Stats() TicketCacheStats {
+	tc.rwlock.RLock()
+	defer tc.rwlock.RUnlock()
+	return TicketCacheStats{
+		Size:   tc.size,
+		Len:    len(tc.entries),
+		Hits:   atomic.LoadUint64(&tc.hits),
+		Misses: atomic.LoadUint64(&tc.misses),
+	}
+}
+
+// AddVerified caches a ticket set after checking that hash is the hash of its
+// storage data, for the ticket sets not read from a state.
+func (tc *TicketCache) AddVerified(hash common.Hash, tickets common.TicketsDataSlice) error {
+	data, err := calcTicketsStorageData(tickets)
+	if err != nil {
+		return fmt.Errorf("AddVerified: %v", err)
+	}
+	if hash != crypto.Keccak256Hash(data) {
+		return fmt.Errorf("AddVerified: hash mismatch")
+	}
+	tc.Add(hash, tickets)
+	return nil
+}
@@ -0,0 +1,126 @@
+package state
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+)
+
+func ticketCacheHash(i int) common.Hash {
+	return common.BytesToHash(common.Uint64ToBytes(uint64(i + 1)))
+}
+
+func TestTicketCacheEviction(t *testing.T) {
+	tc := NewTicketCache(3)
+	for i := 0; i < 3; i++ {
+		tc.Add(ticketCacheHash(i), common.TicketsDataSlice{})
+	}
+	// the set read last is kept, the least recently used one is evicted
+	tc.Get(ticketCacheHash(0))
+	tc.Add(ticketCacheHash(3), common.TicketsDataSlice{})
+	tc.Add(ticketCacheHash(4), common.TicketsDataSlice{})
+	for i := 0; i < 5; i++ {
+		if cached := tc.Get(ticketCacheHash(i)) != nil; cached != (i == 0 || i >= 3) {
+			t.Errorf("ticket set %d: cached %v", i, cached)
+		}
+	}
+	// shrinking keeps the most recently used sets, growing keeps all
+	tc.Get(ticketCacheHash(0))
+	tc.Get(ticketCacheHash(3))
+	tc.Resize(2)
+	if tc.Get(ticketCacheHash(0)) == nil || tc.Get(ticketCacheHash(3)) == nil || tc.Get(ticketCacheHash(4)) != nil {
+		t.Errorf("shrinking evicted the wrong ticket sets")
+	}
+	tc.Resize(4)
+	tc.Add(ticketCacheHash(5), common.TicketsDataSlice{})
+	tc.Add(ticketCacheHash(6), common.TicketsDataSlice{})
+	for _, i := range []int{0, 3, 5, 6} {
+		if tc.Get(ticketCacheHash(i)) == nil {
+			t.Errorf("ticket set %d evicted after growing", i)
+		}
+	}
+	if stats := tc.Stats(); stats.Size != 4 || stats.Len != 4 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	tc.Purge()
+	if tc.Get(ticketCacheHash(6)) != nil {
+		t.Errorf("ticket set cached after purge")
+	}
+}
+
+func BenchmarkTicketCacheGet(b *testing.B) {
+	for _, readers := range []int{1, 8, 64} {
+		b.Run(fmt.Sprintf("readers-%d", readers), func(b *testing.B) {
+			tc := NewTicketCache(DefaultTicketCacheSize)
+			for i := 0; i < DefaultTicketCacheSize; i++ {
+				tc.Add(ticketCacheHash(i), common.TicketsDataSlice{})
+			}
+			// look up the oldest set, the worst case of the former linear scan
+			hash := ticketCacheHash(0)
+			b.SetParallelism(readers)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					tc.Get(hash)
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkTicketCacheGetWithWriter(b *testing.B) {
+	tc := NewTicketCache(DefaultTicketCacheSize)
+	for i := 0; i < DefaultTicketCacheSize; i++ {
+		tc.Add(ticketCacheHash(i), common.TicketsDataSlice{})
+	}
+	hash := ticketCacheHash(DefaultTicketCacheSize - 1)
+
+	// a writer adding a new ticket set per block contends with the readers
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := DefaultTicketCacheSize; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				tc.Add(ticketCacheHash(i), common.TicketsDataSlice{})
+			}
+		}
+	}()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			tc.Get(hash)
+		}
+	})
+	b.StopTimer()
+	close(stop)
+	wg.Wait()
+}
+
+func TestTicketCachePerDatabase(t *testing.T) {
+	db1 := NewDatabase(rawdb.NewMemoryDatabase())
+	db2 := NewDatabase(rawdb.NewMemoryDatabase())
+
+	statedb, _ := New(common.Hash{}, common.Hash{}, db1)
+	ticket := common.Ticket{Owner: common.HexToAddress("0x01"), TicketBody: common.TicketBody{ID: common.HexToHash("0x02"), StartTime: 1, ExpireTime: common.TimeLockForever}}
+	if err := statedb.AddTicket(ticket); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := statedb.UpdateTickets(common.Big1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db1.TicketCache().Get(hash) == nil {
+		t.Errorf("ticket set not cached by its database")
+	}
+	if db2.TicketCache().Get(hash) != nil {
+		t.Errorf("ticket set cached by another database")
+	}
+}
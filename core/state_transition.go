@@ -22,6 +22,7 @@ import (
 	"math/big"
 
 	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/types"
 	"github.com/FusionFoundation/go-fusion/core/vm"
 	"github.com/FusionFoundation/go-fusion/log"
 	"github.com/FusionFoundation/go-fusion/params"
@@ -77,6 +78,12 @@ type Message interface {
 	Data() []byte
 }
 
+// sponsoredMessage is a message whose gas and FSN call fee may be paid by a
+// sponsor instead of its sender.
+type sponsoredMessage interface {
+	Sponsor() *common.Address
+}
+
 // IntrinsicGas computes the 'intrinsic gas' for a message with the given data.
 func IntrinsicGas(data []byte, contractCreation, homestead bool) (uint64, error) {
 	// Set the starting gas for the raw transaction
@@ -152,10 +159,26 @@ func (st *StateTransition) useGas(amount uint64) error {
 	return nil
 }
 
+// sponsor returns the sponsor of the message, nil unless it is sponsored.
+func (st *StateTransition) sponsor() *common.Address {
+	if msg, ok := st.msg.(sponsoredMessage); ok {
+		return msg.Sponsor()
+	}
+	return nil
+}
+
+// payer returns the account paying the gas and the fee of the message.
+func (st *StateTransition) payer() common.Address {
+	if sponsor := st.sponsor(); sponsor != nil {
+		return *sponsor
+	}
+	return st.msg.From()
+}
+
 func (st *StateTransition) buyGas() error {
 	mgval := new(big.Int).Mul(new(big.Int).SetUint64(st.msg.Gas()), st.gasPrice)
 	mgval.Add(mgval, st.fee)
-	if st.state.GetBalance(common.SystemAssetID, st.msg.From()).Cmp(mgval) < 0 {
+	if st.state.GetBalance(common.SystemAssetID, st.payer()).Cmp(mgval) < 0 {
 		return errInsufficientBalanceForGas
 	}
 	if err := st.gp.SubGas(st.msg.Gas()); err != nil {
@@ -164,7 +187,7 @@ func (st *StateTransition) buyGas() error {
 	st.gas += st.msg.Gas()
 
 	st.initialGas = st.msg.Gas()
-	st.state.SubBalance(st.msg.From(), common.SystemAssetID, mgval)
+	st.state.SubBalance(st.payer(), common.SystemAssetID, mgval)
 	return nil
 }
 
@@ -178,9 +201,35 @@ func (st *StateTransition) preCheck() error {
 			return ErrNonceTooLow
 		}
 	}
+	// Only FSN calls can be sponsored
+	if st.sponsor() != nil && (!common.IsSponsoredTxEnabled(st.evm.BlockNumber) || !common.IsFsnCall(st.msg.To())) {
+		return ErrSponsoredTx
+	}
 	return st.buyGas()
 }
 
+// fsnCallFee returns the fee of an FSN call in block number, on top of its gas.
+func fsnCallFee(param *common.FSNCallParam, number *big.Int, schedule common.FsnCallFeeSchedule) *big.Int {
+	if param.Func == common.TypedCallFunc && common.IsTypedCallEnabled(number) {
+		// the relayer pays the fee of the typed call
+		typedCallParam := common.TypedCallParam{}
+		rlp.DecodeBytes(param.Data, &typedCallParam)
+		return common.GetFsnCallFeeAt(&common.FSNCallAddress, typedCallParam.Call.Func, number, schedule)
+	}
+	return common.GetFsnCallFeeAt(&common.FSNCallAddress, param.Func, number, schedule)
+}
+
+// FsnCallFee returns the fee a transaction pays to the miner of block number
+// on top of its gas, which is zero unless it is an FSN call.
+func FsnCallFee(tx *types.Transaction, number *big.Int, schedule common.FsnCallFeeSchedule) *big.Int {
+	if !common.IsFsnCall(tx.To()) {
+		return new(big.Int)
+	}
+	param := common.FSNCallParam{}
+	rlp.DecodeBytes(tx.Data(), &param)
+	return fsnCallFee(&param, number, schedule)
+}
+
 // TransitionDb will transition the state by applying the current message and
 // returning the result including the used gas. It returns an error if failed.
 // An error indicates a consensus issue.
@@ -190,7 +239,7 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 	if common.IsFsnCall(msg.To()) {
 		fsnCallParam = &common.FSNCallParam{}
 		rlp.DecodeBytes(msg.Data(), fsnCallParam)
-		st.fee = common.GetFsnCallFee(msg.To(), fsnCallParam.Func)
+		st.fee = fsnCallFee(fsnCallParam, st.evm.BlockNumber, st.state)
 	}
 	if err = st.preCheck(); err != nil {
 		return
@@ -265,7 +314,7 @@ func (st *StateTransition) refundGas() {
 
 	// Return ETH for remaining gas, exchanged at the original rate.
 	remaining := new(big.Int).Mul(new(big.Int).SetUint64(st.gas), st.gasPrice)
-	st.state.AddBalance(st.msg.From(), common.SystemAssetID, remaining)
+	st.state.AddBalance(st.payer(), common.SystemAssetID, remaining)
 
 	// Also return remaining gas to the block gas counter so it is
 	// available for the next transaction.
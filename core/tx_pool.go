@@ -87,6 +87,10 @@ var (
 	// transaction with a negative value.
 	ErrNegativeValue = errors.New("negative value")
 
+	// ErrInvalidSponsor is returned if a sponsored transaction contains an
+	// invalid sponsor signature.
+	ErrInvalidSponsor = errors.New("invalid sponsor")
+
 	// ErrOversizedData is returned if the input data of a transaction is greater
 	// than some meaningful limit a user might use. This is not a consensus error
 	// making the transaction invalid, rather a DOS protection.
@@ -159,7 +163,9 @@ type TxPoolConfig struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime         time.Duration // Maximum amount of time non-executable transaction are queued
-	TicketTxLifetime time.Duration // Maximum amount of time buy ticket transaction are queued
+	TicketTxLifetime time.Duration // Maximum amount of time buy ticket transaction are pooled
+
+	PendingFsnState bool // Whether FSN calls are validated with the earlier pooled transactions of their sender applied
 }
 
 // DefaultTxPoolConfig contains the default configurations for the transaction
@@ -216,6 +222,10 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultTxPoolConfig.Lifetime)
 		conf.Lifetime = DefaultTxPoolConfig.Lifetime
 	}
+	if conf.TicketTxLifetime < 1 {
+		log.Warn("Sanitizing invalid txpool ticket lifetime", "provided", conf.TicketTxLifetime, "updated", DefaultTxPoolConfig.TicketTxLifetime)
+		conf.TicketTxLifetime = DefaultTxPoolConfig.TicketTxLifetime
+	}
 	return conf
 }
 
@@ -245,11 +255,14 @@ type TxPool struct {
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *txJournal  // Journal of local transaction to back up to disk
 
-	pending map[common.Address]*txList   // All currently processable transactions
-	queue   map[common.Address]*txList   // Queued but non-processable transactions
-	beats   map[common.Address]time.Time // Last heartbeat from each known account
-	all     *txLookup                    // All transactions to allow lookups
-	priced  *txPricedList                // All transactions sorted by price
+	pending   map[common.Address]*txList                  // All currently processable transactions
+	queue     map[common.Address]*txList                  // Queued but non-processable transactions
+	beats     map[common.Address]time.Time                // Last heartbeat from each known account
+	tickets   map[common.Hash]time.Time                   // Arrival time of each pooled buy ticket transaction
+	sponsored map[common.Address]map[common.Hash]struct{} // Pooled transactions sponsored by each account
+	fsnStates map[common.Address]*fsnReplay               // Replayed pooled transactions of each account, see fsnValidationState
+	all       *txLookup                                   // All transactions to allow lookups
+	priced    *txPricedList                               // All transactions sorted by price
 
 	chainHeadCh     chan ChainHeadEvent
 	chainHeadSub    event.Subscription
@@ -280,6 +293,9 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		pending:         make(map[common.Address]*txList),
 		queue:           make(map[common.Address]*txList),
 		beats:           make(map[common.Address]time.Time),
+		tickets:         make(map[common.Hash]time.Time),
+		sponsored:       make(map[common.Address]map[common.Hash]struct{}),
+		fsnStates:       make(map[common.Address]*fsnReplay),
 		all:             newTxLookup(),
 		chainHeadCh:     make(chan ChainHeadEvent, chainHeadChanSize),
 		reqResetCh:      make(chan *txpoolResetRequest),
@@ -305,7 +321,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 	if !config.NoLocals && config.Journal != "" {
 		pool.journal = newTxJournal(config.Journal)
 
-		if err := pool.journal.load(pool.AddLocals); err != nil {
+		if err := pool.journal.load(pool.addJournaled); err != nil {
 			log.Warn("Failed to load transaction journal", "err", err)
 		}
 		if err := pool.journal.rotate(pool.local()); err != nil {
@@ -381,6 +397,8 @@ func (pool *TxPool) loop() {
 					}
 				}
 			}
+			// Any buy tickets which can no longer be valid should be removed
+			pool.evictStaleTickets(time.Now())
 			pool.mu.Unlock()
 
 		// Handle local transaction journal rotation
@@ -560,6 +578,17 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if pool.currentState.GetBalance(common.SystemAssetID, from).Cmp(tx.Cost()) < 0 {
 		return ErrInsufficientFunds
 	}
+	// Make sure a sponsored transaction is an FSN call signed by its sponsor,
+	// its balance is checked with the fee of the call
+	if tx.Sponsored() {
+		nextBlockNumber := new(big.Int).Add(pool.chain.CurrentBlock().Number(), big.NewInt(1))
+		if !common.IsSponsoredTxEnabled(nextBlockNumber) || !common.IsFsnCall(tx.To()) {
+			return ErrSponsoredTx
+		}
+		if _, err := types.Sponsor(pool.signer, tx); err != nil {
+			return ErrInvalidSponsor
+		}
+	}
 	// Ensure the transaction has more gas than the basic tx fee.
 	intrGas, err := IntrinsicGas(tx.Data(), tx.To() == nil, true)
 	if err != nil {
@@ -614,8 +643,23 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 			pool.removeTx(tx.Hash(), false)
 		}
 	}
-	// Try to replace an existing transaction in the pending pool
+	// Mark local addresses before journaling, local replacements included
 	from, _ := types.Sender(pool.signer, tx) // already validated
+	if local && !pool.locals.contains(from) {
+		log.Info("Setting new local account", "address", from)
+		pool.locals.add(from)
+	}
+	if tx.IsBuyTicketTx() {
+		pool.tickets[hash] = time.Now()
+	}
+	if tx.Sponsored() {
+		sponsor, _ := types.Sponsor(pool.signer, tx) // already validated
+		if pool.sponsored[sponsor] == nil {
+			pool.sponsored[sponsor] = make(map[common.Hash]struct{})
+		}
+		pool.sponsored[sponsor][hash] = struct{}{}
+	}
+	// Try to replace an existing transaction in the pending pool
 	if list := pool.pending[from]; list != nil && list.Overlaps(tx) {
 		// Nonce already pending, check if required price bump is met
 		inserted, old := list.Add(tx, pool.config.PriceBump)
@@ -641,13 +685,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 	if err != nil {
 		return false, err
 	}
-	// Mark local addresses and journal local transactions
-	if local {
-		if !pool.locals.contains(from) {
-			log.Info("Setting new local account", "address", from)
-			pool.locals.add(from)
-		}
-	}
+	// Count and journal local transactions
 	if local || pool.locals.contains(from) {
 		localGauge.Inc(1)
 	}
@@ -1170,6 +1208,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	}
 	pool.currentState = statedb
 	pool.pendingNonces = newTxNoncer(statedb)
+	pool.fsnStates = make(map[common.Address]*fsnReplay)
 	pool.currentMaxGas = newHead.GasLimit
 
 	// Inject any transactions discarded due to reorgs
@@ -0,0 +1,328 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/math"
+	"github.com/FusionFoundation/go-fusion/crypto"
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+// Domain of the EIP-712 typed data of the FSN calls.
+const (
+	FSNTypedDataName    = "Fusion"
+	FSNTypedDataVersion = "1"
+)
+
+var (
+	fsnTypedDataDomainType = "EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"
+
+	hashType    = reflect.TypeOf(common.Hash{})
+	addressType = reflect.TypeOf(common.Address{})
+	bigIntType  = reflect.TypeOf(new(big.Int))
+
+	errInvalidTypedSig = errors.New("invalid typed data signature")
+)
+
+// FSNTypedDataField is a member of an EIP-712 struct type.
+type FSNTypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// FSNTypedDataDomain is the EIP-712 domain of the FSN calls.
+type FSNTypedDataDomain struct {
+	Name              string                `json:"name"`
+	Version           string                `json:"version"`
+	ChainId           *math.HexOrDecimal256 `json:"chainId"`
+	VerifyingContract common.Address        `json:"verifyingContract"`
+}
+
+// FSNTypedData is an FSN call in the eth_signTypedData format, so that
+// wallets can display its fields instead of the RLP encoded call data.
+//
+// The primary type is named after the call function and has the fields of
+// the call parameters followed by the typed call nonce and deadline.
+type FSNTypedData struct {
+	Types       map[string][]FSNTypedDataField `json:"types"`
+	PrimaryType string                         `json:"primaryType"`
+	Domain      FSNTypedDataDomain             `json:"domain"`
+	Message     map[string]interface{}         `json:"message"`
+
+	domainHash  common.Hash
+	messageHash common.Hash
+}
+
+// typedCallParam returns the EIP-712 type name and an empty parameter of the
+// FSN call functions which can be executed through typed calls.
+func typedCallParam(fn common.FSNCallFunc) (string, interface{}) {
+	switch fn {
+	case common.GenNotationFunc:
+		return "GenNotation", &common.EmptyParam{}
+	case common.GenAssetFunc:
+		return "GenAsset", &common.GenAssetParam{}
+	case common.SendAssetFunc:
+		return "SendAsset", &common.SendAssetParam{}
+	case common.TimeLockFunc:
+		return "TimeLock", &common.TimeLockParam{}
+	case common.AssetValueChangeFunc:
+		return "AssetValueChange", &common.AssetValueChangeExParam{}
+	case common.MakeSwapFunc:
+		return "MakeSwap", &common.MakeSwapParam{}
+	case common.MakeSwapFuncExt:
+		return "MakeSwapExt", &common.MakeSwapParam{}
+	case common.RecallSwapFunc:
+		return "RecallSwap", &common.RecallSwapParam{}
+	case common.TakeSwapFunc:
+		return "TakeSwap", &common.TakeSwapParam{}
+	case common.TakeSwapFuncExt:
+		return "TakeSwapExt", &common.TakeSwapParam{}
+	case common.MakeMultiSwapFunc:
+		return "MakeMultiSwap", &common.MakeMultiSwapParam{}
+	case common.RecallMultiSwapFunc:
+		return "RecallMultiSwap", &common.RecallMultiSwapParam{}
+	case common.TakeMultiSwapFunc:
+		return "TakeMultiSwap", &common.TakeMultiSwapParam{}
+	}
+	return "", nil
+}
+
+// NewFSNTypedData returns the typed data whose signature authorizes the
+// execution of call through a TypedCallFunc with the given nonce and
+// deadline.
+func NewFSNTypedData(chainID *big.Int, call *common.FSNCallParam, nonce, deadline uint64) (*FSNTypedData, error) {
+	name, param := typedCallParam(call.Func)
+	if param == nil {
+		return nil, fmt.Errorf("%v can not be a typed call", call.Func.Name())
+	}
+	if len(call.Data) != 0 {
+		if err := rlp.DecodeBytes(call.Data, param); err != nil {
+			return nil, fmt.Errorf("decode %v param err %v", call.Func.Name(), err)
+		}
+	}
+	td := &FSNTypedData{
+		Types: map[string][]FSNTypedDataField{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+		},
+		PrimaryType: name,
+		Domain: FSNTypedDataDomain{
+			Name:              FSNTypedDataName,
+			Version:           FSNTypedDataVersion,
+			ChainId:           (*math.HexOrDecimal256)(new(big.Int).Set(chainID)),
+			VerifyingContract: common.FSNCallAddress,
+		},
+		Message: make(map[string]interface{}),
+	}
+	// Encode the domain
+	enc := crypto.Keccak256([]byte(fsnTypedDataDomainType))
+	enc = append(enc, crypto.Keccak256([]byte(FSNTypedDataName))...)
+	enc = append(enc, crypto.Keccak256([]byte(FSNTypedDataVersion))...)
+	enc = append(enc, math.PaddedBigBytes(chainID, 32)...)
+	enc = append(enc, common.LeftPadBytes(common.FSNCallAddress.Bytes(), 32)...)
+	td.domainHash = crypto.Keccak256Hash(enc)
+
+	// Encode the call parameters, followed by the nonce and deadline
+	var (
+		fields []FSNTypedDataField
+		data   []byte
+	)
+	v := reflect.ValueOf(param).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Tag.Get("rlp") == "tail" {
+			// optional fields extend the parameters without changing their type
+			if v.Field(i).Len() != 0 {
+				return nil, fmt.Errorf("%v.%v is not supported in typed calls", name, field.Name)
+			}
+			continue
+		}
+		typ, err := typedDataType(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%v.%v: %v", name, field.Name, err)
+		}
+		key := typedDataFieldName(field.Name)
+		fields = append(fields, FSNTypedDataField{Name: key, Type: typ})
+		value, encoded, err := typedDataValue(v.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf("%v.%v: %v", name, field.Name, err)
+		}
+		td.Message[key] = value
+		data = append(data, encoded...)
+	}
+	for _, extra := range []struct {
+		key   string
+		value uint64
+	}{{"nonce", nonce}, {"deadline", deadline}} {
+		fields = append(fields, FSNTypedDataField{Name: extra.key, Type: "uint64"})
+		value, encoded, _ := typedDataValue(reflect.ValueOf(extra.value))
+		td.Message[extra.key] = value
+		data = append(data, encoded...)
+	}
+	td.Types[name] = fields
+
+	var sig []string
+	for _, field := range fields {
+		sig = append(sig, field.Type+" "+field.Name)
+	}
+	typeHash := crypto.Keccak256([]byte(name + "(" + strings.Join(sig, ",") + ")"))
+	td.messageHash = crypto.Keccak256Hash(typeHash, data)
+	return td, nil
+}
+
+// RawData returns the bytes whose keccak256 hash is signed, this is what
+// accounts.Wallet.SignData expects for the data/typed mime type.
+func (td *FSNTypedData) RawData() []byte {
+	return append(append([]byte{0x19, 0x01}, td.domainHash[:]...), td.messageHash[:]...)
+}
+
+// SigHash returns the hash signed to authorize the typed call.
+func (td *FSNTypedData) SigHash() common.Hash {
+	return crypto.Keccak256Hash(td.RawData())
+}
+
+// Signer returns the address which produced the signature. The recovery id
+// may be given as 0/1 or as 27/28.
+func (td *FSNTypedData) Signer(sig []byte) (common.Address, error) {
+	if len(sig) != 65 {
+		return common.Address{}, errInvalidTypedSig
+	}
+	s := common.CopyBytes(sig)
+	if s[64] >= 27 {
+		s[64] -= 27
+	}
+	if s[64] > 1 {
+		return common.Address{}, errInvalidTypedSig
+	}
+	r, sv := new(big.Int).SetBytes(s[:32]), new(big.Int).SetBytes(s[32:64])
+	if !crypto.ValidateSignatureValues(s[64], r, sv, true) {
+		return common.Address{}, errInvalidTypedSig
+	}
+	pub, err := crypto.SigToPub(td.SigHash().Bytes(), s)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// SignFSNTypedData signs the typed data with the private key, the returned
+// signature has a 27/28 recovery id as produced by wallets.
+func SignFSNTypedData(td *FSNTypedData, prv *ecdsa.PrivateKey) ([]byte, error) {
+	sig, err := crypto.Sign(td.SigHash().Bytes(), prv)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	return sig, nil
+}
+
+// typedDataFieldName converts a parameter field name to the EIP-712 member
+// name, e.g. FromAssetID to fromAssetID.
+func typedDataFieldName(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+// typedDataType returns the EIP-712 type of a parameter field.
+func typedDataType(t reflect.Type) (string, error) {
+	switch {
+	case t == hashType:
+		return "bytes32", nil
+	case t == addressType:
+		return "address", nil
+	case t == bigIntType:
+		return "uint256", nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string", nil
+	case reflect.Bool:
+		return "bool", nil
+	case reflect.Uint8:
+		return "uint8", nil
+	case reflect.Uint64:
+		return "uint64", nil
+	case reflect.Uint:
+		return "uint256", nil
+	case reflect.Slice:
+		elem, err := typedDataType(t.Elem())
+		if err != nil || strings.HasSuffix(elem, "[]") {
+			return "", fmt.Errorf("unsupported type %v", t)
+		}
+		return elem + "[]", nil
+	}
+	return "", fmt.Errorf("unsupported type %v", t)
+}
+
+// typedDataValue returns the JSON message value and the EIP-712 encoding of
+// a parameter field. Integers are given as decimal strings as they may not
+// fit into a javascript number.
+func typedDataValue(v reflect.Value) (interface{}, []byte, error) {
+	switch v.Type() {
+	case hashType:
+		hash := v.Interface().(common.Hash)
+		return hash, hash.Bytes(), nil
+	case addressType:
+		address := v.Interface().(common.Address)
+		return address, common.LeftPadBytes(address.Bytes(), 32), nil
+	case bigIntType:
+		n := v.Interface().(*big.Int)
+		if n == nil {
+			n = new(big.Int)
+		}
+		return n.String(), math.PaddedBigBytes(n, 32), nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), crypto.Keccak256([]byte(v.String())), nil
+	case reflect.Bool:
+		if v.Bool() {
+			return true, math.PaddedBigBytes(common.Big1, 32), nil
+		}
+		return false, make([]byte, 32), nil
+	case reflect.Uint8, reflect.Uint64, reflect.Uint:
+		n := new(big.Int).SetUint64(v.Uint())
+		return n.String(), math.PaddedBigBytes(n, 32), nil
+	case reflect.Slice:
+		values := make([]interface{}, v.Len())
+		var data []byte
+		for i := 0; i < v.Len(); i++ {
+			value, encoded, err := typedDataValue(v.Index(i))
+			if err != nil {
+				return nil, nil, err
+			}
+			values[i] = value
+			data = append(data, encoded...)
+		}
+		return values, crypto.Keccak256(data), nil
+	}
+	return nil, nil, fmt.Errorf("unsupported typed data value %v", v.Type())
+}
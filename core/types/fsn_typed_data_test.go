@@ -0,0 +1,88 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/crypto"
+)
+
+func TestFSNTypedDataSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	data, _ := (&common.SendAssetParam{
+		AssetID: common.SystemAssetID,
+		To:      common.HexToAddress("0x10"),
+		Value:   big.NewInt(1000),
+	}).ToBytes()
+	call := &common.FSNCallParam{Func: common.SendAssetFunc, Data: data}
+
+	td, err := NewFSNTypedData(big.NewInt(32659), call, 3, 1600000000)
+	if err != nil {
+		t.Fatalf("failed to create typed data: %v", err)
+	}
+	if td.PrimaryType != "SendAsset" || len(td.Types["SendAsset"]) != 5 {
+		t.Fatalf("unexpected type: %s %v", td.PrimaryType, td.Types["SendAsset"])
+	}
+	if td.Message["value"] != "1000" || td.Message["nonce"] != "3" {
+		t.Errorf("unexpected message: %v", td.Message)
+	}
+	sig, err := SignFSNTypedData(td, key)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	if signer, err := td.Signer(sig); err != nil || signer != addr {
+		t.Errorf("signer mismatch: have %x (%v), want %x", signer, err, addr)
+	}
+	// The signature must not be valid for another nonce, chain or function
+	for i, other := range []struct {
+		chainID *big.Int
+		fn      common.FSNCallFunc
+		nonce   uint64
+	}{
+		{big.NewInt(32659), common.SendAssetFunc, 4},
+		{big.NewInt(46688), common.SendAssetFunc, 3},
+		{big.NewInt(32659), common.TimeLockFunc, 3},
+	} {
+		otherTd, err := NewFSNTypedData(other.chainID, &common.FSNCallParam{Func: other.fn, Data: data}, other.nonce, 1600000000)
+		if err != nil {
+			continue
+		}
+		if signer, _ := otherTd.Signer(sig); signer == addr {
+			t.Errorf("test %d: signature accepted for modified typed data", i)
+		}
+	}
+	if _, err := NewFSNTypedData(big.NewInt(1), &common.FSNCallParam{Func: common.BuyTicketFunc}, 0, 0); err == nil {
+		t.Error("expected error for a function which can not be a typed call")
+	}
+}
+
+func TestTypedDataValueUnsupported(t *testing.T) {
+	for _, v := range []interface{}{int64(1), struct{}{}, []int64{1}} {
+		if _, _, err := typedDataValue(reflect.ValueOf(v)); err == nil {
+			t.Errorf("typed data value of %T encoded", v)
+		}
+	}
+	if _, _, err := typedDataValue(reflect.ValueOf([]uint64{1, 2})); err != nil {
+		t.Errorf("failed to encode a uint64 slice: %v", err)
+	}
+}
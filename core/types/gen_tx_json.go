@@ -26,6 +26,7 @@ func (t txdata) MarshalJSON() ([]byte, error) {
 		R            *hexutil.Big    `json:"r" gencodec:"required"`
 		S            *hexutil.Big    `json:"s" gencodec:"required"`
 		Hash         *common.Hash    `json:"hash" rlp:"-"`
+		SponsorSig   []*hexutil.Big  `json:"sponsorSig,omitempty" rlp:"tail"`
 	}
 	var enc txdata
 	enc.AccountNonce = hexutil.Uint64(t.AccountNonce)
@@ -38,6 +39,12 @@ func (t txdata) MarshalJSON() ([]byte, error) {
 	enc.R = (*hexutil.Big)(t.R)
 	enc.S = (*hexutil.Big)(t.S)
 	enc.Hash = t.Hash
+	if t.SponsorSig != nil {
+		enc.SponsorSig = make([]*hexutil.Big, len(t.SponsorSig))
+		for k, v := range t.SponsorSig {
+			enc.SponsorSig[k] = (*hexutil.Big)(v)
+		}
+	}
 	return json.Marshal(&enc)
 }
 
@@ -54,6 +61,7 @@ func (t *txdata) UnmarshalJSON(input []byte) error {
 		R            *hexutil.Big    `json:"r" gencodec:"required"`
 		S            *hexutil.Big    `json:"s" gencodec:"required"`
 		Hash         *common.Hash    `json:"hash" rlp:"-"`
+		SponsorSig   []*hexutil.Big  `json:"sponsorSig,omitempty" rlp:"tail"`
 	}
 	var dec txdata
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -97,5 +105,11 @@ func (t *txdata) UnmarshalJSON(input []byte) error {
 	if dec.Hash != nil {
 		t.Hash = dec.Hash
 	}
+	if dec.SponsorSig != nil {
+		t.SponsorSig = make([]*big.Int, len(dec.SponsorSig))
+		for k, v := range dec.SponsorSig {
+			t.SponsorSig[k] = (*big.Int)(v)
+		}
+	}
 	return nil
 }
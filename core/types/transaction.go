@@ -32,15 +32,18 @@ import (
 //go:generate gencodec -type txdata -field-override txdataMarshaling -out gen_tx_json.go
 
 var (
-	ErrInvalidSig = errors.New("invalid transaction v, r, s values")
+	ErrInvalidSig        = errors.New("invalid transaction v, r, s values")
+	ErrInvalidSponsorSig = errors.New("invalid transaction sponsor v, r, s values")
+	ErrNotSponsored      = errors.New("transaction is not sponsored")
 )
 
 type Transaction struct {
 	data txdata
 	// caches
-	hash atomic.Value
-	size atomic.Value
-	from atomic.Value
+	hash    atomic.Value
+	size    atomic.Value
+	from    atomic.Value
+	sponsor atomic.Value
 }
 
 type txdata struct {
@@ -58,6 +61,11 @@ type txdata struct {
 
 	// This is only used when marshaling to JSON.
 	Hash *common.Hash `json:"hash" rlp:"-"`
+
+	// Sponsor signature values V, R and S, empty unless the gas and the fee
+	// are paid by a sponsor, so the encoding of the other transactions is
+	// unchanged
+	SponsorSig []*big.Int `json:"sponsorSig,omitempty" rlp:"tail"`
 }
 
 type txdataMarshaling struct {
@@ -69,6 +77,7 @@ type txdataMarshaling struct {
 	V            *hexutil.Big
 	R            *hexutil.Big
 	S            *hexutil.Big
+	SponsorSig   []*hexutil.Big
 }
 
 func NewTransaction(nonce uint64, to common.Address, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte) *Transaction {
@@ -132,6 +141,9 @@ func (tx *Transaction) EncodeRLP(w io.Writer) error {
 func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
 	_, size, _ := s.Kind()
 	err := s.Decode(&tx.data)
+	if err == nil && !validSponsorSigLen(tx.data.SponsorSig) {
+		err = ErrInvalidSponsorSig
+	}
 	if err == nil {
 		tx.size.Store(common.StorageSize(rlp.ListSize(size)))
 	}
@@ -139,6 +151,10 @@ func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
 	return err
 }
 
+func validSponsorSigLen(sig []*big.Int) bool {
+	return len(sig) == 0 || len(sig) == 3
+}
+
 // MarshalJSON encodes the web3 RPC transaction format.
 func (tx *Transaction) MarshalJSON() ([]byte, error) {
 	hash := tx.Hash()
@@ -167,6 +183,15 @@ func (tx *Transaction) UnmarshalJSON(input []byte) error {
 			return ErrInvalidSig
 		}
 	}
+	if !validSponsorSigLen(dec.SponsorSig) {
+		return ErrInvalidSponsorSig
+	}
+	if len(dec.SponsorSig) != 0 {
+		V, R, S := dec.SponsorSig[0], dec.SponsorSig[1], dec.SponsorSig[2]
+		if V == nil || R == nil || S == nil || V.BitLen() > 8 || !crypto.ValidateSignatureValues(byte(V.Uint64()-27), R, S, false) {
+			return ErrInvalidSponsorSig
+		}
+	}
 
 	*tx = Transaction{data: dec}
 	return nil
@@ -200,6 +225,20 @@ func (tx *Transaction) Hash() common.Hash {
 	return v
 }
 
+// UniqueHash returns the hash of tx without its signature. The FSN calls
+// derive the IDs of the assets and swaps they create from it, so it is known
+// before the transaction is signed and sent.
+func (tx *Transaction) UniqueHash() common.Hash {
+	d := tx.data
+	return rlpHash(newTransaction(d.AccountNonce, d.Recipient, d.Amount, d.GasLimit, d.Price, d.Payload))
+}
+
+// SaltedUniqueHash returns UniqueHash salted with the sender of tx, so that
+// identical transactions of different accounts derive different IDs.
+func (tx *Transaction) SaltedUniqueHash(from common.Address) common.Hash {
+	return rlpHash([]interface{}{tx.UniqueHash(), from})
+}
+
 // Size returns the true RLP encoded storage size of the transaction, either by
 // encoding and returning it, or returning a previsouly cached value.
 func (tx *Transaction) Size() common.StorageSize {
@@ -230,6 +269,11 @@ func (tx *Transaction) AsMessage(s Signer) (Message, error) {
 
 	var err error
 	msg.from, err = Sender(s, tx)
+	if err == nil && tx.Sponsored() {
+		var sponsor common.Address
+		sponsor, err = Sponsor(s, tx)
+		msg.sponsor = &sponsor
+	}
 	return msg, err
 }
 
@@ -245,9 +289,40 @@ func (tx *Transaction) WithSignature(signer Signer, sig []byte) (*Transaction, e
 	return cpy, nil
 }
 
-// Cost returns amount + gasprice * gaslimit.
+// Sponsored reports whether the gas and the fee of tx are paid by a sponsor
+// instead of its sender.
+func (tx *Transaction) Sponsored() bool {
+	return len(tx.data.SponsorSig) != 0
+}
+
+// SponsorHash returns the hash signed by the sponsor of tx, which is the hash
+// of tx signed by its sender, without the sponsor signature.
+func (tx *Transaction) SponsorHash() common.Hash {
+	d := tx.data
+	d.SponsorSig = nil
+	return rlpHash(&d)
+}
+
+// WithSponsorSignature returns a new transaction with the given sponsor
+// signature. This signature needs to be in the [R || S || V] format where V
+// is 0 or 1.
+func (tx *Transaction) WithSponsorSignature(sig []byte) (*Transaction, error) {
+	r, s, v, err := HomesteadSigner{}.SignatureValues(tx, sig)
+	if err != nil {
+		return nil, err
+	}
+	cpy := &Transaction{data: tx.data}
+	cpy.data.SponsorSig = []*big.Int{v, r, s}
+	return cpy, nil
+}
+
+// Cost returns amount + gasprice * gaslimit. The gas of a sponsored
+// transaction is paid by its sponsor and left out of its cost.
 func (tx *Transaction) Cost() *big.Int {
-	total := new(big.Int).Mul(tx.data.Price, new(big.Int).SetUint64(tx.data.GasLimit))
+	total := new(big.Int)
+	if !tx.Sponsored() {
+		total.Mul(tx.data.Price, new(big.Int).SetUint64(tx.data.GasLimit))
+	}
 	if common.IsReceiveAssetPayableTx(nil, tx.data.Payload) {
 		// in this situation, tx.data.Amount may be timelock value,
 		// we'll use `CanTransferTimeLock` to judge wether the balance is enough instead.
@@ -263,6 +338,16 @@ func (tx *Transaction) RawSignatureValues() (v, r, s *big.Int) {
 	return tx.data.V, tx.data.R, tx.data.S
 }
 
+// RawSponsorSignatureValues returns the V, R, S sponsor signature values of
+// the transaction, which are nil unless it is sponsored.
+// The return values should not be modified by the caller.
+func (tx *Transaction) RawSponsorSignatureValues() (v, r, s *big.Int) {
+	if !tx.Sponsored() {
+		return nil, nil, nil
+	}
+	return tx.data.SponsorSig[0], tx.data.SponsorSig[1], tx.data.SponsorSig[2]
+}
+
 func (tx *Transaction) IsTimeLockTx() bool {
 	param := common.FSNCallParam{}
 	rlp.DecodeBytes(tx.Data(), &param)
@@ -272,7 +357,7 @@ func (tx *Transaction) IsTimeLockTx() bool {
 func (tx *Transaction) IsBuyTicketTx() bool {
 	param := common.FSNCallParam{}
 	rlp.DecodeBytes(tx.Data(), &param)
-	return param.Func == common.BuyTicketFunc
+	return param.Func == common.BuyTicketFunc || param.Func == common.StakingBuyTicketFunc
 }
 
 func (tx *Transaction) GetOrder() int {
@@ -281,7 +366,7 @@ func (tx *Transaction) GetOrder() int {
 	switch param.Func {
 	case common.ReportIllegalFunc:
 		return 1000
-	case common.BuyTicketFunc:
+	case common.BuyTicketFunc, common.StakingBuyTicketFunc:
 		return 900
 	}
 	return 0
@@ -356,13 +441,50 @@ func (s *TxByPrice) Pop() interface{} {
 	return x
 }
 
+// TxTipFunc returns the revenue of a transaction per unit of gas, which orders
+// the transactions of the same order in a TransactionsByPriceAndNonce.
+type TxTipFunc func(tx *Transaction) *big.Int
+
+// txHead is the next transaction of an account with its tip.
+type txHead struct {
+	tx  *Transaction
+	tip *big.Int
+}
+
+// txHeadsByTip is a TxByPrice comparing the tips instead of the gas prices.
+type txHeadsByTip []txHead
+
+func (s txHeadsByTip) Len() int { return len(s) }
+func (s txHeadsByTip) Less(i, j int) bool {
+	order1 := s[i].tx.GetOrder()
+	order2 := s[j].tx.GetOrder()
+	if order1 != order2 { // higher order first
+		return order1 > order2
+	}
+	return s[i].tip.Cmp(s[j].tip) > 0
+}
+func (s txHeadsByTip) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+func (s *txHeadsByTip) Push(x interface{}) {
+	*s = append(*s, x.(txHead))
+}
+
+func (s *txHeadsByTip) Pop() interface{} {
+	old := *s
+	n := len(old)
+	x := old[n-1]
+	*s = old[0 : n-1]
+	return x
+}
+
 // TransactionsByPriceAndNonce represents a set of transactions that can return
 // transactions in a profit-maximizing sorted order, while supporting removing
 // entire batches of transactions for non-executable accounts.
 type TransactionsByPriceAndNonce struct {
 	txs    map[common.Address]Transactions // Per account nonce-sorted list of transactions
-	heads  TxByPrice                       // Next transaction for each unique account (price heap)
+	heads  txHeadsByTip                    // Next transaction for each unique account (tip heap)
 	signer Signer                          // Signer for the set of transactions
+	tip    TxTipFunc                       // Revenue per gas of the transactions
 }
 
 // NewTransactionsByPriceAndNonce creates a transaction set that can retrieve
@@ -371,10 +493,17 @@ type TransactionsByPriceAndNonce struct {
 // Note, the input map is reowned so the caller should not interact any more with
 // if after providing it to the constructor.
 func NewTransactionsByPriceAndNonce(signer Signer, txs map[common.Address]Transactions) *TransactionsByPriceAndNonce {
-	// Initialize a price based heap with the head transactions
-	heads := make(TxByPrice, 0, len(txs))
+	return NewTransactionsByTipAndNonce(signer, txs, (*Transaction).GasPrice)
+}
+
+// NewTransactionsByTipAndNonce creates a transaction set like
+// NewTransactionsByPriceAndNonce, ordering the transactions by tip instead of
+// gas price.
+func NewTransactionsByTipAndNonce(signer Signer, txs map[common.Address]Transactions, tip TxTipFunc) *TransactionsByPriceAndNonce {
+	// Initialize a tip based heap with the head transactions
+	heads := make(txHeadsByTip, 0, len(txs))
 	for from, accTxs := range txs {
-		heads = append(heads, accTxs[0])
+		heads = append(heads, txHead{accTxs[0], tip(accTxs[0])})
 		// Ensure the sender address is from the signer
 		acc, _ := Sender(signer, accTxs[0])
 		txs[acc] = accTxs[1:]
@@ -389,6 +518,7 @@ func NewTransactionsByPriceAndNonce(signer Signer, txs map[common.Address]Transa
 		txs:    txs,
 		heads:  heads,
 		signer: signer,
+		tip:    tip,
 	}
 }
 
@@ -397,14 +527,14 @@ func (t *TransactionsByPriceAndNonce) Peek() *Transaction {
 	if len(t.heads) == 0 {
 		return nil
 	}
-	return t.heads[0]
+	return t.heads[0].tx
 }
 
 // Shift replaces the current best head with the next one from the same account.
 func (t *TransactionsByPriceAndNonce) Shift() {
-	acc, _ := Sender(t.signer, t.heads[0])
+	acc, _ := Sender(t.signer, t.heads[0].tx)
 	if txs, ok := t.txs[acc]; ok && len(txs) > 0 {
-		t.heads[0], t.txs[acc] = txs[0], txs[1:]
+		t.heads[0], t.txs[acc] = txHead{txs[0], t.tip(txs[0])}, txs[1:]
 		heap.Fix(&t.heads, 0)
 	} else {
 		heap.Pop(&t.heads)
@@ -430,6 +560,7 @@ type Message struct {
 	gasPrice   *big.Int
 	data       []byte
 	checkNonce bool
+	sponsor    *common.Address
 }
 
 func NewMessage(from common.Address, to *common.Address, nonce uint64, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, checkNonce bool) Message {
@@ -453,3 +584,7 @@ func (m Message) Gas() uint64          { return m.gasLimit }
 func (m Message) Nonce() uint64        { return m.nonce }
 func (m Message) Data() []byte         { return m.data }
 func (m Message) CheckNonce() bool     { return m.checkNonce }
+
+// Sponsor returns the account paying the gas and the fee of the message, nil
+// unless it is sponsored.
+func (m Message) Sponsor() *common.Address { return m.sponsor }
@@ -88,6 +88,48 @@ func Sender(signer Signer, tx *Transaction) (common.Address, error) {
 	return addr, nil
 }
 
+// SignSponsor signs tx as its sponsor, which pays its gas and its fee. The
+// transaction must have been signed by its sender already.
+func SignSponsor(tx *Transaction, prv *ecdsa.PrivateKey) (*Transaction, error) {
+	h := tx.SponsorHash()
+	sig, err := crypto.Sign(h[:], prv)
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSponsorSignature(sig)
+}
+
+// Sponsor returns the address derived from the sponsor signature of tx. The
+// sender signature must be valid for signer and replay protected, the sponsor
+// signature covers it and is bound to the same chain.
+//
+// Sponsor may cache the address, allowing it to be used regardless of
+// signing method.
+func Sponsor(signer Signer, tx *Transaction) (common.Address, error) {
+	if !tx.Sponsored() {
+		return common.Address{}, ErrNotSponsored
+	}
+	if sc := tx.sponsor.Load(); sc != nil {
+		sigCache := sc.(sigCache)
+		if sigCache.signer.Equal(signer) {
+			return sigCache.from, nil
+		}
+	}
+	if !tx.Protected() {
+		return common.Address{}, ErrInvalidSponsorSig
+	}
+	if _, err := Sender(signer, tx); err != nil {
+		return common.Address{}, err
+	}
+	v, r, s := tx.RawSponsorSignatureValues()
+	addr, err := recoverPlain(tx.SponsorHash(), r, s, v, true)
+	if err != nil {
+		return common.Address{}, err
+	}
+	tx.sponsor.Store(sigCache{signer: signer, from: addr})
+	return addr, nil
+}
+
 // Signer encapsulates transaction signature handling. Note that this interface is not a
 // stable API and may change at any time to accommodate new protocol rules.
 type Signer interface {
@@ -118,6 +118,28 @@ func TestRecipientNormal(t *testing.T) {
 	}
 }
 
+// Tests that the unique hash of a transaction is the hash of it unsigned, and
+// that signing it does not change the unique hash.
+func TestTransactionUniqueHash(t *testing.T) {
+	unsigned := NewTransaction(
+		3,
+		common.HexToAddress("b94f5374fce5edbc8e2a8697c15331677e6ebf0b"),
+		big.NewInt(10),
+		2000,
+		big.NewInt(1),
+		common.FromHex("5544"),
+	)
+	if unsigned.UniqueHash() != unsigned.Hash() {
+		t.Errorf("unique hash mismatch: have %x, want %x", unsigned.UniqueHash(), unsigned.Hash())
+	}
+	if rightvrsTx.UniqueHash() != unsigned.Hash() {
+		t.Errorf("signed unique hash mismatch: have %x, want %x", rightvrsTx.UniqueHash(), unsigned.Hash())
+	}
+	if rightvrsTx.Hash() == unsigned.Hash() {
+		t.Error("signed transaction hash equals the unsigned one")
+	}
+}
+
 // Tests that transactions can be correctly sorted according to their price in
 // decreasing order, but at the same time with increasing nonces when issued by
 // the same account.
@@ -173,6 +195,33 @@ func TestTransactionPriceNonceSort(t *testing.T) {
 }
 
 // TestTransactionJSON tests serializing/de-serializing to/from JSON.
+func TestTransactionTipNonceSort(t *testing.T) {
+	signer := HomesteadSigner{}
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	// the FSN call pays a lower gas price but a higher tip
+	call, _ := SignTx(NewTransaction(0, common.FSNCallAddress, big.NewInt(0), 100, big.NewInt(1), nil), signer, key1)
+	transfer, _ := SignTx(NewTransaction(0, common.Address{}, big.NewInt(0), 100, big.NewInt(2), nil), signer, key2)
+	groups := map[common.Address]Transactions{
+		crypto.PubkeyToAddress(key1.PublicKey): {call},
+		crypto.PubkeyToAddress(key2.PublicKey): {transfer},
+	}
+	tip := func(tx *Transaction) *big.Int {
+		if common.IsFsnCall(tx.To()) {
+			return new(big.Int).Add(tx.GasPrice(), big.NewInt(10))
+		}
+		return tx.GasPrice()
+	}
+	txset := NewTransactionsByTipAndNonce(signer, groups, tip)
+	if tx := txset.Peek(); tx != call {
+		t.Fatalf("first transaction %x, want the FSN call", tx.Hash())
+	}
+	txset.Shift()
+	if tx := txset.Peek(); tx != transfer {
+		t.Fatalf("second transaction %x, want the transfer", tx.Hash())
+	}
+}
+
 func TestTransactionJSON(t *testing.T) {
 	key, err := crypto.GenerateKey()
 	if err != nil {
@@ -219,3 +268,104 @@ func TestTransactionJSON(t *testing.T) {
 		}
 	}
 }
+
+// Tests that identical transactions of different senders have distinct salted
+// unique hashes, which signing does not change.
+func TestSaltedUniqueHash(t *testing.T) {
+	unsigned := NewTransaction(
+		3,
+		common.HexToAddress("b94f5374fce5edbc8e2a8697c15331677e6ebf0b"),
+		big.NewInt(10),
+		2000,
+		big.NewInt(1),
+		common.FromHex("5544"),
+	)
+	a, b := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	if unsigned.SaltedUniqueHash(a) == unsigned.SaltedUniqueHash(b) {
+		t.Error("identical transactions of different senders share their salted unique hash")
+	}
+	if unsigned.SaltedUniqueHash(a) == unsigned.UniqueHash() {
+		t.Error("salted unique hash equals the unique hash")
+	}
+	if rightvrsTx.SaltedUniqueHash(a) != unsigned.SaltedUniqueHash(a) {
+		t.Errorf("signed salted unique hash mismatch: have %x, want %x", rightvrsTx.SaltedUniqueHash(a), unsigned.SaltedUniqueHash(a))
+	}
+}
+
+func TestSponsoredTransaction(t *testing.T) {
+	userKey, _ := crypto.GenerateKey()
+	sponsorKey, _ := crypto.GenerateKey()
+	signer := NewEIP155Signer(big.NewInt(32659))
+
+	tx, _ := SignTx(NewTransaction(1, common.FSNCallAddress, big.NewInt(0), 80000, big.NewInt(1), common.FromHex("5544")), signer, userKey)
+	sponsored, err := SignSponsor(tx, sponsorKey)
+	if err != nil {
+		t.Fatalf("failed to sign sponsor: %v", err)
+	}
+	if tx.Sponsored() || !sponsored.Sponsored() {
+		t.Fatal("sponsor signature not set on a copy")
+	}
+	if sponsored.SponsorHash() != tx.Hash() {
+		t.Errorf("sponsor hash mismatch: have %x, want %x", sponsored.SponsorHash(), tx.Hash())
+	}
+	if signer.Hash(sponsored) != signer.Hash(tx) {
+		t.Error("sponsor signature changed the sender signature hash")
+	}
+	if sponsored.Cost().Sign() != 0 {
+		t.Errorf("gas counted in the cost of a sponsored transaction: %v", sponsored.Cost())
+	}
+
+	// the sponsor signature survives the RLP and JSON encodings
+	enc, _ := rlp.EncodeToBytes(sponsored)
+	decoded, err := decodeTx(enc)
+	if err != nil {
+		t.Fatalf("rlp decoding failed: %v", err)
+	}
+	data, _ := json.Marshal(sponsored)
+	var parsed Transaction
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("json decoding failed: %v", err)
+	}
+	for _, tx := range []*Transaction{sponsored, decoded, &parsed} {
+		if tx.Hash() != sponsored.Hash() {
+			t.Errorf("hash mismatch: have %x, want %x", tx.Hash(), sponsored.Hash())
+		}
+		msg, err := tx.AsMessage(signer)
+		if err != nil {
+			t.Fatalf("failed to derive message: %v", err)
+		}
+		if msg.From() != crypto.PubkeyToAddress(userKey.PublicKey) {
+			t.Errorf("sender mismatch: have %x", msg.From())
+		}
+		if msg.Sponsor() == nil || *msg.Sponsor() != crypto.PubkeyToAddress(sponsorKey.PublicKey) {
+			t.Errorf("sponsor mismatch: have %v", msg.Sponsor())
+		}
+	}
+
+	// the sponsor signature is bound to the sender signature
+	other, _ := SignTx(NewTransaction(1, common.FSNCallAddress, big.NewInt(0), 80000, big.NewInt(1), common.FromHex("5544")), signer, sponsorKey)
+	v, r, s := sponsored.RawSponsorSignatureValues()
+	moved := &Transaction{data: other.data}
+	moved.data.SponsorSig = []*big.Int{v, r, s}
+	if sponsor, err := Sponsor(signer, moved); err == nil && sponsor == crypto.PubkeyToAddress(sponsorKey.PublicKey) {
+		t.Error("sponsor signature accepted for another sender signature")
+	}
+
+	// the sender signature must be replay protected
+	unprotected, _ := SignTx(NewTransaction(1, common.FSNCallAddress, big.NewInt(0), 80000, big.NewInt(1), nil), HomesteadSigner{}, userKey)
+	unprotected, _ = SignSponsor(unprotected, sponsorKey)
+	if _, err := Sponsor(HomesteadSigner{}, unprotected); err != ErrInvalidSponsorSig {
+		t.Errorf("unprotected sponsored transaction error mismatch: have %v, want %v", err, ErrInvalidSponsorSig)
+	}
+	if _, err := Sponsor(signer, tx); err != ErrNotSponsored {
+		t.Errorf("unsponsored transaction error mismatch: have %v, want %v", err, ErrNotSponsored)
+	}
+
+	// an incomplete sponsor signature is rejected
+	partial := &Transaction{data: tx.data}
+	partial.data.SponsorSig = []*big.Int{v, r}
+	enc, _ = rlp.EncodeToBytes(partial)
+	if _, err := decodeTx(enc); err != ErrInvalidSponsorSig {
+		t.Errorf("partial sponsor signature error mismatch: have %v, want %v", err, ErrInvalidSponsorSig)
+	}
+}
@@ -0,0 +1,170 @@
+package vm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/params"
+)
+
+// BtcSpvContractAddress verifies the inclusion of Bitcoin transactions in a
+// chain of Bitcoin block headers given by the caller
+var BtcSpvContractAddress = common.HexToAddress("0x9999999999999999999999999999999999999997")
+
+const (
+	btcHeaderSize   = 80
+	btcMaxProofSize = 32 // depth of the Merkle tree of a block
+)
+
+var (
+	errBtcSpvInput      = errors.New("btc spv: invalid input")
+	errBtcSpvProofSize  = errors.New("btc spv: merkle proof too long")
+	errBtcSpvNoHeader   = errors.New("btc spv: no block header")
+	errBtcSpvTarget     = errors.New("btc spv: invalid header target")
+	errBtcSpvWork       = errors.New("btc spv: insufficient header work")
+	errBtcSpvChain      = errors.New("btc spv: headers do not form a chain")
+	errBtcSpvMerkleRoot = errors.New("btc spv: transaction not in block")
+)
+
+var btcMaxWork = new(big.Int).Lsh(common.Big1, 256)
+
+// btcSpv verifies an SPV proof of a Bitcoin transaction. Its input is
+//
+//	txid [32] | index [32] | n [32] | merkle proof [32*n] | headers [80*m]
+//
+// with the hashes in the internal byte order of Bitcoin and the integers big
+// endian. The transaction must be at the given index of the block of the first
+// header, every header must meet the target of its bits and link to the
+// previous one. It returns the hash of the block of the transaction, the
+// number of headers and their total work, the caller checks these against the
+// difficulty it expects.
+//
+// A 64 byte transaction can be mistaken for an inner node of the Merkle tree,
+// callers verifying the raw transaction must reject that length.
+type btcSpv struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *btcSpv) RequiredGas(input []byte) uint64 {
+	proof := uint64(0)
+	if len(input) >= 96 {
+		if n := new(big.Int).SetBytes(input[64:96]); n.IsUint64() && n.Uint64() <= btcMaxProofSize {
+			proof = n.Uint64()
+		}
+	}
+	headers := uint64(len(input)) / btcHeaderSize
+	return params.BtcSpvBaseGas + headers*params.BtcSpvPerHeaderGas + proof*params.BtcSpvPerProofGas
+}
+
+func (c *btcSpv) Run(input []byte) ([]byte, error) {
+	if len(input) < 96 {
+		return nil, errBtcSpvInput
+	}
+	txid := input[:32]
+	index := new(big.Int).SetBytes(input[32:64])
+	n := new(big.Int).SetBytes(input[64:96])
+	if !n.IsUint64() || n.Uint64() > btcMaxProofSize {
+		return nil, errBtcSpvProofSize
+	}
+	proofEnd := 96 + 32*int(n.Uint64())
+	if len(input) < proofEnd || (len(input)-proofEnd)%btcHeaderSize != 0 {
+		return nil, errBtcSpvInput
+	}
+	if len(input) == proofEnd {
+		return nil, errBtcSpvNoHeader
+	}
+	if index.BitLen() > int(n.Uint64()) {
+		return nil, errBtcSpvMerkleRoot
+	}
+
+	var (
+		work    = new(big.Int)
+		prev    []byte
+		first   []byte
+		headers = input[proofEnd:]
+	)
+	for i := 0; i < len(headers); i += btcHeaderSize {
+		header := headers[i : i+btcHeaderSize]
+		hash := btcDoubleSha256(header)
+		if prev != nil && !bytes.Equal(header[4:36], prev) {
+			return nil, errBtcSpvChain
+		}
+		headerWork, err := btcHeaderWork(header, hash)
+		if err != nil {
+			return nil, err
+		}
+		work.Add(work, headerWork)
+		if first == nil {
+			first = hash
+		}
+		prev = hash
+	}
+
+	// fold the Merkle branch from the transaction up to the root
+	node := common.CopyBytes(txid)
+	for i := 0; i < int(n.Uint64()); i++ {
+		sibling := input[96+32*i : 128+32*i]
+		if index.Bit(i) == 0 {
+			node = btcDoubleSha256(node, sibling)
+		} else {
+			node = btcDoubleSha256(sibling, node)
+		}
+	}
+	if !bytes.Equal(node, headers[36:68]) {
+		return nil, errBtcSpvMerkleRoot
+	}
+	if work.BitLen() > 256 {
+		return nil, errBtcSpvWork
+	}
+
+	output := make([]byte, 96)
+	copy(output, first)
+	new(big.Int).SetUint64(uint64(len(headers) / btcHeaderSize)).FillBytes(output[32:64])
+	work.FillBytes(output[64:96])
+	return output, nil
+}
+
+// btcHeaderWork checks the proof of work of a header and returns its work
+func btcHeaderWork(header, hash []byte) (*big.Int, error) {
+	bits := uint32(header[72]) | uint32(header[73])<<8 | uint32(header[74])<<16 | uint32(header[75])<<24
+	target := btcCompactToBig(bits)
+	if target.Sign() <= 0 || target.BitLen() > 256 {
+		return nil, errBtcSpvTarget
+	}
+	// the hash is a little endian number
+	value := make([]byte, 32)
+	for i := range hash {
+		value[31-i] = hash[i]
+	}
+	if new(big.Int).SetBytes(value).Cmp(target) > 0 {
+		return nil, errBtcSpvWork
+	}
+	return new(big.Int).Div(btcMaxWork, target.Add(target, common.Big1)), nil
+}
+
+// btcCompactToBig decodes the compact target of a header, negative targets
+// decode to 0
+func btcCompactToBig(bits uint32) *big.Int {
+	mantissa := bits & 0x007fffff
+	exponent := uint(bits >> 24)
+	if bits&0x00800000 != 0 {
+		return new(big.Int)
+	}
+	target := new(big.Int).SetUint64(uint64(mantissa))
+	if exponent <= 3 {
+		return target.Rsh(target, 8*(3-exponent))
+	}
+	return target.Lsh(target, 8*(exponent-3))
+}
+
+func btcDoubleSha256(data ...[]byte) []byte {
+	h := sha256.New()
+	for _, b := range data {
+		h.Write(b)
+	}
+	first := h.Sum(nil)
+	second := sha256.Sum256(first)
+	return second[:]
+}
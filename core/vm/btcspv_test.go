@@ -0,0 +1,103 @@
+package vm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+)
+
+var (
+	// headers of the first two blocks of the Bitcoin main chain
+	btcGenesisHeader = common.Hex2Bytes("0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c")
+	btcBlock1Header  = common.Hex2Bytes("010000006fe28c0ab6f1b372c1a6a246ae63f74f931e8365e15a089c68d6190000000000982051fd1e4ba744bbbe680e1fee14677ba1a3c3540bf7b1cdb606e857233e0e61bc6649ffff001d01e36299")
+)
+
+func btcSpvInput(txid []byte, index uint64, proof [][]byte, headers ...[]byte) []byte {
+	input := append(common.CopyBytes(txid), common.LeftPadBytes(new(big.Int).SetUint64(index).Bytes(), 32)...)
+	input = append(input, common.LeftPadBytes(big.NewInt(int64(len(proof))).Bytes(), 32)...)
+	for _, hash := range proof {
+		input = append(input, hash...)
+	}
+	for _, header := range headers {
+		input = append(input, header...)
+	}
+	return input
+}
+
+// btcMineHeader returns a header with the given merkle root meeting the
+// easiest regtest target
+func btcMineHeader(prev, root []byte) []byte {
+	header := make([]byte, btcHeaderSize)
+	binary.LittleEndian.PutUint32(header, 1)
+	copy(header[4:36], prev)
+	copy(header[36:68], root)
+	binary.LittleEndian.PutUint32(header[72:], 0x207fffff)
+	for nonce := uint32(0); ; nonce++ {
+		binary.LittleEndian.PutUint32(header[76:], nonce)
+		if _, err := btcHeaderWork(header, btcDoubleSha256(header)); err == nil {
+			return header
+		}
+	}
+}
+
+func TestBtcSpvMainChain(t *testing.T) {
+	genesisHash := btcDoubleSha256(btcGenesisHeader)
+	// the coinbase of the genesis block is its only transaction
+	out, err := new(btcSpv).Run(btcSpvInput(btcGenesisHeader[36:68], 0, nil, btcGenesisHeader, btcBlock1Header))
+	if err != nil {
+		t.Fatalf("proof rejected: %v", err)
+	}
+	if !bytes.Equal(out[:32], genesisHash) {
+		t.Errorf("block hash mismatch: have %x, want %x", out[:32], genesisHash)
+	}
+	if confirmations := new(big.Int).SetBytes(out[32:64]); confirmations.Int64() != 2 {
+		t.Errorf("confirmations mismatch: have %v, want 2", confirmations)
+	}
+	// the work of a difficulty 1 block is 0x100010001
+	if work := new(big.Int).SetBytes(out[64:96]); work.Cmp(big.NewInt(2*0x100010001)) != 0 {
+		t.Errorf("work mismatch: have %#x", work)
+	}
+
+	if _, err := new(btcSpv).Run(btcSpvInput(btcGenesisHeader[36:68], 0, nil, btcBlock1Header, btcGenesisHeader)); err != errBtcSpvChain {
+		t.Errorf("unlinked headers: have %v, want %v", err, errBtcSpvChain)
+	}
+	tampered := common.CopyBytes(btcGenesisHeader)
+	tampered[76]++
+	if _, err := new(btcSpv).Run(btcSpvInput(tampered[36:68], 0, nil, tampered)); err != errBtcSpvWork {
+		t.Errorf("tampered nonce: have %v, want %v", err, errBtcSpvWork)
+	}
+	if _, err := new(btcSpv).Run(btcSpvInput(btcGenesisHeader[36:68], 0, nil)); err != errBtcSpvNoHeader {
+		t.Errorf("no header: have %v, want %v", err, errBtcSpvNoHeader)
+	}
+}
+
+func TestBtcSpvMerkleProof(t *testing.T) {
+	txs := [][]byte{
+		btcDoubleSha256([]byte("tx0")),
+		btcDoubleSha256([]byte("tx1")),
+		btcDoubleSha256([]byte("tx2")),
+		btcDoubleSha256([]byte("tx3")),
+	}
+	left := btcDoubleSha256(txs[0], txs[1])
+	right := btcDoubleSha256(txs[2], txs[3])
+	header := btcMineHeader(make([]byte, 32), btcDoubleSha256(left, right))
+
+	if _, err := new(btcSpv).Run(btcSpvInput(txs[2], 2, [][]byte{txs[3], left}, header)); err != nil {
+		t.Errorf("proof of tx 2 rejected: %v", err)
+	}
+	if _, err := new(btcSpv).Run(btcSpvInput(txs[1], 1, [][]byte{txs[0], right}, header)); err != nil {
+		t.Errorf("proof of tx 1 rejected: %v", err)
+	}
+	if _, err := new(btcSpv).Run(btcSpvInput(txs[2], 3, [][]byte{txs[3], left}, header)); err != errBtcSpvMerkleRoot {
+		t.Errorf("wrong index: have %v, want %v", err, errBtcSpvMerkleRoot)
+	}
+	if _, err := new(btcSpv).Run(btcSpvInput(txs[2], 6, [][]byte{txs[3], left}, header)); err != errBtcSpvMerkleRoot {
+		t.Errorf("index beyond the proof: have %v, want %v", err, errBtcSpvMerkleRoot)
+	}
+	if _, err := new(btcSpv).Run(btcSpvInput(txs[2], 2, [][]byte{txs[3], left}, header[:79])); err != errBtcSpvInput {
+		t.Errorf("truncated header: have %v, want %v", err, errBtcSpvInput)
+	}
+}
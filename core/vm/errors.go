@@ -32,4 +32,5 @@ var (
 	ErrForbidDelegateCall       = errors.New("forbid delegate call")
 	ErrDataError                = errors.New("data error")
 	ErrToAddressMustBeContract  = errors.New("receiver address must be contract")
+	ErrAssetTransferNotAllowed  = errors.New("asset transfer not allowed")
 )
@@ -0,0 +1,142 @@
+package vm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/crypto"
+	"github.com/FusionFoundation/go-fusion/ethdb/memorydb"
+	"github.com/FusionFoundation/go-fusion/params"
+	"github.com/FusionFoundation/go-fusion/rlp"
+	"github.com/FusionFoundation/go-fusion/trie"
+)
+
+// EthProofContractAddress verifies Ethereum account and storage proofs
+// against a state root given by the caller
+var EthProofContractAddress = common.HexToAddress("0x9999999999999999999999999999999999999996")
+
+var (
+	errEthProofInput   = errors.New("eth proof: invalid input")
+	errEthProofAccount = errors.New("eth proof: invalid account proof")
+	errEthProofStorage = errors.New("eth proof: invalid storage proof")
+)
+
+// ethAccount is an account of the Ethereum state trie
+type ethAccount struct {
+	Nonce    uint64
+	Balance  *big.Int
+	Root     common.Hash
+	CodeHash []byte
+}
+
+// ethProof verifies a Merkle-Patricia proof of an Ethereum account and
+// optionally of one of its storage slots. Its input is
+//
+//	stateRoot [32] | address [32] | account proof | slot [32] | storage proof
+//
+// where a proof is the number of its nodes [32] followed by every node as its
+// length [32] and its RLP encoding, the slot and storage proof are optional.
+// The integers are big endian. It returns
+//
+//	nonce [32] | balance [32] | storageRoot [32] | codeHash [32] | value [32]
+//
+// with the value of the storage slot if one is given. Accounts and slots
+// proven absent return zeros. The contract does not know which state roots
+// are valid, the caller checks the root against the roots it trusts, e.g.
+// roots attested by the bridge committee.
+type ethProof struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *ethProof) RequiredGas(input []byte) uint64 {
+	return uint64(len(input)+31)/32*params.EthProofPerWordGas + params.EthProofBaseGas
+}
+
+func (c *ethProof) Run(input []byte) ([]byte, error) {
+	if len(input) < 64 {
+		return nil, errEthProofInput
+	}
+	root := common.BytesToHash(input[:32])
+	address := common.BytesToAddress(input[32:64])
+	proof, rest, err := ethProofNodes(input[64:])
+	if err != nil {
+		return nil, err
+	}
+	data, _, err := trie.VerifyProof(root, crypto.Keccak256(address[:]), proof)
+	if err != nil {
+		return nil, errEthProofAccount
+	}
+	output := make([]byte, 160)
+	var account ethAccount
+	if data != nil {
+		if err := rlp.DecodeBytes(data, &account); err != nil {
+			return nil, errEthProofAccount
+		}
+		new(big.Int).SetUint64(account.Nonce).FillBytes(output[:32])
+		if account.Balance.BitLen() > 256 {
+			return nil, errEthProofAccount
+		}
+		account.Balance.FillBytes(output[32:64])
+		copy(output[64:96], account.Root[:])
+		copy(output[96:128], common.LeftPadBytes(account.CodeHash, 32))
+	}
+	if len(rest) == 0 {
+		return output[:128], nil
+	}
+
+	if len(rest) < 32 {
+		return nil, errEthProofInput
+	}
+	slot := rest[:32]
+	proof, rest, err = ethProofNodes(rest[32:])
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errEthProofInput
+	}
+	if data == nil {
+		return output, nil
+	}
+	data, _, err = trie.VerifyProof(account.Root, crypto.Keccak256(slot), proof)
+	if err != nil {
+		return nil, errEthProofStorage
+	}
+	if data != nil {
+		var value []byte
+		if err := rlp.DecodeBytes(data, &value); err != nil || len(value) > 32 {
+			return nil, errEthProofStorage
+		}
+		copy(output[128:], common.LeftPadBytes(value, 32))
+	}
+	return output, nil
+}
+
+// ethProofNodes reads the nodes of a proof into a database keyed by their
+// hashes and returns the input after the proof
+func ethProofNodes(input []byte) (*memorydb.Database, []byte, error) {
+	if len(input) < 32 {
+		return nil, nil, errEthProofInput
+	}
+	count := new(big.Int).SetBytes(input[:32])
+	input = input[32:]
+	// every node takes at least its 32 byte length
+	if !count.IsUint64() || count.Uint64() > uint64(len(input)/32) {
+		return nil, nil, errEthProofInput
+	}
+	db := memorydb.New()
+	for i := uint64(0); i < count.Uint64(); i++ {
+		if len(input) < 32 {
+			return nil, nil, errEthProofInput
+		}
+		size := new(big.Int).SetBytes(input[:32])
+		input = input[32:]
+		if !size.IsUint64() || size.Uint64() > uint64(len(input)) {
+			return nil, nil, errEthProofInput
+		}
+		node := input[:size.Uint64()]
+		input = input[size.Uint64():]
+		db.Put(crypto.Keccak256(node), node)
+	}
+	return db, input, nil
+}
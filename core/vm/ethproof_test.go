@@ -0,0 +1,110 @@
+package vm
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/crypto"
+	"github.com/FusionFoundation/go-fusion/ethdb/memorydb"
+	"github.com/FusionFoundation/go-fusion/rlp"
+	"github.com/FusionFoundation/go-fusion/trie"
+)
+
+// proofList collects the nodes of a proof
+type proofList [][]byte
+
+func (l *proofList) Put(key []byte, value []byte) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func (l *proofList) Delete(key []byte) error {
+	panic("not supported")
+}
+
+func (l proofList) encode() []byte {
+	out := common.LeftPadBytes(big.NewInt(int64(len(l))).Bytes(), 32)
+	for _, node := range l {
+		out = append(out, common.LeftPadBytes(big.NewInt(int64(len(node))).Bytes(), 32)...)
+		out = append(out, node...)
+	}
+	return out
+}
+
+func prove(t *testing.T, tr *trie.Trie, key []byte) proofList {
+	var proof proofList
+	if err := tr.Prove(crypto.Keccak256(key), 0, &proof); err != nil {
+		t.Fatal(err)
+	}
+	return proof
+}
+
+func TestEthProof(t *testing.T) {
+	db := trie.NewDatabase(memorydb.New())
+	storage, _ := trie.New(common.Hash{}, db)
+	slot := common.BigToHash(big.NewInt(3)).Bytes()
+	value, _ := rlp.EncodeToBytes(big.NewInt(1000).Bytes())
+	storage.Update(crypto.Keccak256(slot), value)
+	for i := int64(10); i < 50; i++ {
+		other, _ := rlp.EncodeToBytes(big.NewInt(i).Bytes())
+		storage.Update(crypto.Keccak256(common.BigToHash(big.NewInt(i)).Bytes()), other)
+	}
+
+	addr := common.HexToAddress("0x0102030405060708090a0b0c0d0e0f1011121314")
+	codeHash := crypto.Keccak256([]byte("code"))
+	account, _ := rlp.EncodeToBytes(&ethAccount{Nonce: 7, Balance: big.NewInt(12345), Root: storage.Hash(), CodeHash: codeHash})
+	state, _ := trie.New(common.Hash{}, db)
+	state.Update(crypto.Keccak256(addr[:]), account)
+	for i := byte(0); i < 20; i++ {
+		other, _ := rlp.EncodeToBytes(&ethAccount{Nonce: uint64(i), Balance: new(big.Int)})
+		state.Update(crypto.Keccak256(common.BytesToAddress([]byte{i}).Bytes()), other)
+	}
+	root := state.Hash()
+
+	input := append(root.Bytes(), common.LeftPadBytes(addr[:], 32)...)
+	input = append(input, prove(t, state, addr[:]).encode()...)
+	out, err := new(ethProof).Run(input)
+	if err != nil {
+		t.Fatalf("account proof rejected: %v", err)
+	}
+	if len(out) != 128 {
+		t.Fatalf("output length mismatch: have %d, want 128", len(out))
+	}
+	if new(big.Int).SetBytes(out[:32]).Int64() != 7 || new(big.Int).SetBytes(out[32:64]).Int64() != 12345 {
+		t.Errorf("account mismatch: %x", out)
+	}
+	if !bytes.Equal(out[64:96], storage.Hash().Bytes()) || !bytes.Equal(out[96:128], codeHash) {
+		t.Errorf("account roots mismatch: %x", out)
+	}
+
+	withSlot := append(common.CopyBytes(input), slot...)
+	withSlot = append(withSlot, prove(t, storage, slot).encode()...)
+	if out, err = new(ethProof).Run(withSlot); err != nil {
+		t.Fatalf("storage proof rejected: %v", err)
+	}
+	if new(big.Int).SetBytes(out[128:160]).Int64() != 1000 {
+		t.Errorf("storage value mismatch: %x", out[128:160])
+	}
+
+	// a slot missing from the storage trie is proven zero
+	missing := common.BigToHash(big.NewInt(99)).Bytes()
+	absent := append(common.CopyBytes(input), missing...)
+	absent = append(absent, prove(t, storage, missing).encode()...)
+	if out, err = new(ethProof).Run(absent); err != nil || new(big.Int).SetBytes(out[128:160]).Sign() != 0 {
+		t.Errorf("absent slot: have %x, err %v", out, err)
+	}
+
+	wrongProof := append(common.CopyBytes(input), slot...)
+	wrongProof = append(wrongProof, prove(t, storage, missing).encode()...)
+	if _, err := new(ethProof).Run(wrongProof); err != errEthProofStorage {
+		t.Errorf("proof of another slot: have %v, want %v", err, errEthProofStorage)
+	}
+	if _, err := new(ethProof).Run(append(crypto.Keccak256([]byte("root")), input[32:]...)); err != errEthProofAccount {
+		t.Errorf("unknown root: have %v, want %v", err, errEthProofAccount)
+	}
+	if _, err := new(ethProof).Run(input[:len(input)-1]); err != errEthProofInput {
+		t.Errorf("truncated proof: have %v, want %v", err, errEthProofInput)
+	}
+}
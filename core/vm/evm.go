@@ -44,7 +44,7 @@ type (
 	TransferTimeLockFunc func(db StateDB, sender, recipient common.Address, p *common.TransferTimeLockParam)
 )
 
-func getPrecompiledContracts(evm *EVM, codeAddr *common.Address, contract *Contract) PrecompiledContract {
+func getPrecompiledContracts(evm *EVM, codeAddr *common.Address, contract *Contract, readOnly bool) PrecompiledContract {
 	if codeAddr == nil {
 		return nil
 	}
@@ -53,6 +53,18 @@ func getPrecompiledContracts(evm *EVM, codeAddr *common.Address, contract *Contr
 			return NewFSNContract(evm, contract)
 		}
 	}
+	if *codeAddr == USANContractAddress && common.IsUsanContractEnabled(evm.BlockNumber) {
+		if in, ok := evm.interpreter.(*EVMInterpreter); ok && in.readOnly {
+			readOnly = true
+		}
+		return NewUSANContract(evm, contract, readOnly)
+	}
+	if *codeAddr == BtcSpvContractAddress && common.IsBtcSpvContractEnabled(evm.BlockNumber) {
+		return &btcSpv{}
+	}
+	if *codeAddr == EthProofContractAddress && common.IsEthProofContractEnabled(evm.BlockNumber) {
+		return &ethProof{}
+	}
 	precompiles := PrecompiledContractsHomestead
 	if evm.chainRules.IsByzantium {
 		precompiles = PrecompiledContractsByzantium
@@ -62,7 +74,7 @@ func getPrecompiledContracts(evm *EVM, codeAddr *common.Address, contract *Contr
 
 // run runs the given contract and takes care of running precompiles with a fallback to the byte code interpreter.
 func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, error) {
-	p := getPrecompiledContracts(evm, contract.CodeAddr, contract)
+	p := getPrecompiledContracts(evm, contract.CodeAddr, contract, readOnly)
 	if p != nil {
 		return RunPrecompiledContract(p, input, contract)
 	}
@@ -145,6 +157,15 @@ type EVM struct {
 	// available gas is calculated in gasCall* according to the 63/64 rule and later
 	// applied in opCall*.
 	callGasTemp uint64
+	// receivedAsset is the asset received by the running receiveAsset call
+	receivedAsset *receivedAsset
+}
+
+// receivedAsset is the time lock transfer of a receiveAsset call
+type receivedAsset struct {
+	from  common.Address
+	to    common.Address
+	param *common.TransferTimeLockParam
 }
 
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
@@ -189,6 +210,17 @@ func (evm *EVM) Cancel() {
 	atomic.StoreInt32(&evm.abort, 1)
 }
 
+// CaptureFsnTransfer passes an internal transfer of an FSN call to the
+// tracer, if it records them.
+func (evm *EVM) CaptureFsnTransfer(transfer *common.InternalTransfer) {
+	if !evm.vmConfig.Debug {
+		return
+	}
+	if tracer, ok := evm.vmConfig.Tracer.(FsnTransferTracer); ok {
+		tracer.CaptureFsnTransfer(transfer)
+	}
+}
+
 // Interpreter returns the current interpreter
 func (evm *EVM) Interpreter() Interpreter {
 	return evm.interpreter
@@ -209,8 +241,8 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	}
 
 	isTransferTimeLock := false
-	p := &common.TransferTimeLockParam{}
-	if common.IsReceiveAssetPayableTx(evm.BlockNumber, input) {
+	p, err := common.ParseReceiveAssetPayableTx(evm.BlockNumber, input, value, evm.Time.Uint64())
+	if p != nil || err != nil {
 		_, ok := caller.(*Contract)
 		if ok { // prvent input data from being modified
 			return nil, gas, ErrForbidCallByContract
@@ -218,19 +250,21 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 		if evm.StateDB.GetCodeSize(addr) == 0 {
 			return nil, gas, ErrToAddressMustBeContract
 		}
-		err = common.ParseReceiveAssetPayableTxInput(p, input, evm.Time.Uint64())
 		if err != nil {
 			return nil, gas, err
 		}
 		isTransferTimeLock = true
-		p.Value = value
-		p.BlockNumber = evm.BlockNumber
 	}
 
 	if isTransferTimeLock {
 		if !evm.Context.CanTransferTimeLock(evm.StateDB, caller.Address(), p) {
 			return nil, gas, ErrInsufficientBalance
 		}
+		// restricted assets are received by allowed contracts only
+		if common.IsAssetTransferRestrictionEnabled(evm.BlockNumber) &&
+			(!evm.StateDB.IsAssetTransferAllowed(p.AssetID, caller.Address()) || !evm.StateDB.IsAssetTransferAllowed(p.AssetID, addr)) {
+			return nil, gas, ErrAssetTransferNotAllowed
+		}
 	} else {
 		// Fail if we're trying to transfer more than the available balance
 		if !evm.Context.CanTransfer(evm.StateDB, caller.Address(), value) {
@@ -243,7 +277,7 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 		snapshot = evm.StateDB.Snapshot()
 	)
 	if !evm.StateDB.Exist(addr) {
-		p := getPrecompiledContracts(evm, &addr, nil)
+		p := getPrecompiledContracts(evm, &addr, nil, false)
 		if p == nil && evm.chainRules.IsEIP158 && value.Sign() == 0 {
 			// Calling a non existing account, don't do anything, but ping the tracer
 			if evm.vmConfig.Debug && evm.depth == 0 {
@@ -274,6 +308,11 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 			evm.vmConfig.Tracer.CaptureEnd(ret, gas-contract.Gas, time.Since(start), err)
 		}()
 	}
+	// guard the FSN contract while the receiver settles the received asset
+	if isTransferTimeLock && common.IsReceiveAssetGuardEnabled(evm.BlockNumber) {
+		evm.receivedAsset = &receivedAsset{from: caller.Address(), to: addr, param: p}
+		defer func() { evm.receivedAsset = nil }()
+	}
 	ret, err = run(evm, contract, input, false)
 
 	// When an error was returned by the EVM or when setting the creation code
@@ -342,8 +381,9 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth
 	}
-	// delegate call into FSNContractAddress is forbidden for security reason
-	if addr == FSNContractAddress {
+	// delegate call into FSNContractAddress and USANContractAddress is
+	// forbidden for security reason
+	if addr == FSNContractAddress || addr == USANContractAddress {
 		return nil, gas, ErrForbidDelegateCall
 	}
 
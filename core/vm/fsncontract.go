@@ -5,6 +5,8 @@ import (
 	"math/big"
 
 	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/crypto"
 	"github.com/FusionFoundation/go-fusion/params"
 )
 
@@ -17,19 +19,40 @@ var (
 	ErrValueOverflow          = errors.New("value overflow")
 	ErrWrongLenOfInput        = errors.New("wrong length of input")
 	ErrFcInvalidSendAssetFlag = errors.New("invalid send asset flag")
+	ErrFcReentrantCall        = errors.New("reentrant call while receiving asset")
+	ErrFcNotReceivingAsset    = errors.New("not receiving asset")
 )
 
+// FcSendAssetTimeLockTopic is the topic of the event the FSN contract logs for
+// every sent asset since the FSN contract log fork
+var FcSendAssetTimeLockTopic = fcSendAssetTimeLockEvent.Topic()
+
+// fcErrorStringSelector is the selector of the Solidity Error(string) revert
+var fcErrorStringSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+
 type FcFuncType uint8
 
 const (
-	FcUnknownFunc FcFuncType = iota
-	FcSendAsset              // 1
+	FcUnknownFunc      FcFuncType = iota
+	FcSendAsset                   // 1
+	FcBatchSendAsset              // 2
+	FcGetReceivedAsset            // 3
 )
 
+// maxBatchSendAssetItems is the maximum number of transfers of a batch send
+const maxBatchSendAssetItems = 256
+
+// fcSendAssetItemSize is the size of the ABI encoding of a send asset tuple
+const fcSendAssetItemSize = 6 * 32
+
 func (f FcFuncType) Name() string {
 	switch f {
 	case FcSendAsset:
 		return "sendAsset"
+	case FcBatchSendAsset:
+		return "batchSendAsset"
+	case FcGetReceivedAsset:
+		return "getReceivedAsset"
 	}
 	return "unknown"
 }
@@ -48,18 +71,69 @@ func NewFSNContract(evm *EVM, contract *Contract) *FSNContract {
 }
 
 func (c *FSNContract) RequiredGas(input []byte) uint64 {
+	funcType := FcUnknownFunc
+	if len(input) >= 32 {
+		funcType = FcFuncType(new(big.Int).SetBytes(input[:32]).Uint64())
+	}
+	scheduled := common.IsFsnContractGasScheduleEnabled(c.evm.BlockNumber)
+	switch {
+	case funcType == FcSendAsset && scheduled:
+		return params.FsnContractGas + c.timeLockGas(input, 32)
+	case funcType == FcBatchSendAsset && len(input) >= 96 && common.IsFcBatchSendAssetEnabled(c.evm.BlockNumber):
+		items := uint64(len(input)-96) / fcSendAssetItemSize
+		gas := params.FsnContractGas + items*params.FsnContractBatchItemGas
+		if scheduled && items <= maxBatchSendAssetItems {
+			for i := uint64(0); i < items; i++ {
+				gas += c.timeLockGas(input, 96+i*fcSendAssetItemSize)
+			}
+		}
+		return gas
+	}
 	return params.FsnContractGas
 }
 
+// timeLockGas prices the time lock segments of the sender and the receiver
+// the send asset tuple at pos may touch
+func (c *FSNContract) timeLockGas(input []byte, pos uint64) uint64 {
+	if c.contract == nil {
+		return 0
+	}
+	asset := common.BytesToHash(getData(input, pos, 32))
+	to := common.BytesToAddress(getData(input, pos+32, 32))
+	flag := common.FcSendAssetFlag(new(big.Int).SetBytes(getData(input, pos+160, 32)).Uint64())
+	if flag >= common.FcInvalidSendAssetFlag {
+		return 0
+	}
+	state := c.evm.StateDB
+	segments := 0
+	if !flag.IsUseAsset() {
+		segments += state.GetTimeLockBalance(asset, c.contract.Caller()).Len()
+	}
+	if !flag.IsUseAsset() || flag.IsToTimeLock() {
+		segments += state.GetTimeLockBalance(asset, to).Len()
+	}
+	return uint64(segments) * params.FsnContractTimeLockItemGas
+}
+
 func (c *FSNContract) Run(input []byte) (ret []byte, err error) {
 	c.input = input
 	err = ErrUnknownFunc
 	funcType := FcUnknownFunc
 	if len(c.input) >= 32 {
 		funcType = FcFuncType(c.getBigInt(0).Uint64())
-		switch funcType {
-		case FcSendAsset:
+		switch {
+		case funcType == FcGetReceivedAsset:
+			if common.IsReceiveAssetGuardEnabled(c.evm.BlockNumber) {
+				ret, err = c.getReceivedAsset()
+			}
+		case c.evm.receivedAsset != nil:
+			err = ErrFcReentrantCall
+		case funcType == FcSendAsset:
 			ret, err = c.sendAsset()
+		case funcType == FcBatchSendAsset:
+			if common.IsFcBatchSendAssetEnabled(c.evm.BlockNumber) {
+				ret, err = c.batchSendAsset()
+			}
 		}
 	}
 	if err != nil {
@@ -68,11 +142,27 @@ func (c *FSNContract) Run(input []byte) (ret []byte, err error) {
 			"input", input,
 			"err", err,
 		)
+		if common.IsFsnContractAbiEnabled(c.evm.BlockNumber) {
+			return toRevertData(err), errExecutionReverted
+		}
 		return toErrData(err), err
 	}
 	return ret, err
 }
 
+// okData returns the result of a successful call, the ABI encoding of the
+// values since the FSN contract ABI fork
+func (c *FSNContract) okData(name string, values ...*big.Int) []byte {
+	if !common.IsFsnContractAbiEnabled(c.evm.BlockNumber) {
+		return toOKData(name)
+	}
+	ret := make([]byte, 0, 32*len(values))
+	for _, v := range values {
+		ret = append(ret, common.LeftPadBytes(v.Bytes(), 32)...)
+	}
+	return ret
+}
+
 func (c *FSNContract) sendAsset() ([]byte, error) {
 	_, err := c.contract.GetParentCaller()
 	if err != nil {
@@ -82,6 +172,32 @@ func (c *FSNContract) sendAsset() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := c.transfer(p); err != nil {
+		return nil, err
+	}
+	return c.okData("sendAsset", common.Big1), nil
+}
+
+// batchSendAsset sends all items of the batch from the caller, it fails as a
+// whole if any item fails
+func (c *FSNContract) batchSendAsset() ([]byte, error) {
+	_, err := c.contract.GetParentCaller()
+	if err != nil {
+		return nil, err
+	}
+	items, err := c.parseBatchParams()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range items {
+		if err := c.transfer(p); err != nil {
+			return nil, err
+		}
+	}
+	return c.okData("batchSendAsset", big.NewInt(int64(len(items)))), nil
+}
+
+func (c *FSNContract) transfer(p *FcParams) error {
 	from := c.contract.Caller()
 	to := p.address
 
@@ -99,11 +215,44 @@ func (c *FSNContract) sendAsset() ([]byte, error) {
 
 	state := c.evm.StateDB
 	if !c.evm.CanTransferTimeLock(state, from, tranferTimeLockParam) {
-		return nil, ErrNotEnoughBalance
+		return ErrNotEnoughBalance
 	}
 	c.evm.TransferTimeLock(state, from, to, tranferTimeLockParam)
 
-	return toOKData("sendAsset"), nil
+	if common.IsFsnContractLogEnabled(c.evm.BlockNumber) {
+		data := common.LeftPadBytes(p.value.Bytes(), 32)
+		data = append(data, common.LeftPadBytes(new(big.Int).SetUint64(p.start).Bytes(), 32)...)
+		data = append(data, common.LeftPadBytes(new(big.Int).SetUint64(p.end).Bytes(), 32)...)
+		state.AddLog(&types.Log{
+			Address:     FSNContractAddress,
+			Topics:      []common.Hash{FcSendAssetTimeLockTopic, from.Hash(), to.Hash(), p.asset},
+			Data:        data,
+			BlockNumber: c.evm.BlockNumber.Uint64(),
+		})
+	}
+	return nil
+}
+
+// getReceivedAsset returns the ABI encoding of the asset the caller receives
+// in the running receiveAsset call as (bytes32 asset, address from, uint256
+// value, uint64 start, uint64 end, uint8 flag), the start and end times being
+// adjusted as for the transfer
+func (c *FSNContract) getReceivedAsset() ([]byte, error) {
+	received := c.evm.receivedAsset
+	if received == nil || c.contract == nil || c.contract.Caller() != received.to {
+		return nil, ErrFcNotReceivingAsset
+	}
+	if len(c.input) != 32 {
+		return nil, ErrWrongLenOfInput
+	}
+	p := received.param
+	ret := make([]byte, 0, 6*32)
+	ret = append(ret, p.AssetID.Bytes()...)
+	ret = append(ret, received.from.Hash().Bytes()...)
+	ret = append(ret, common.LeftPadBytes(p.Value.Bytes(), 32)...)
+	ret = append(ret, common.LeftPadBytes(new(big.Int).SetUint64(p.StartTime).Bytes(), 32)...)
+	ret = append(ret, common.LeftPadBytes(new(big.Int).SetUint64(p.EndTime).Bytes(), 32)...)
+	return append(ret, common.LeftPadBytes([]byte{byte(p.Flag)}, 32)...), nil
 }
 
 func (c *FSNContract) getBigInt(pos uint64) *big.Int {
@@ -124,10 +273,53 @@ type FcParams struct {
 }
 
 func (c *FSNContract) parseParams() (*FcParams, error) {
+	p, pos, err := c.parseItem(32)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(c.input)) != pos {
+		return nil, ErrWrongLenOfInput
+	}
+	if err := c.adjustItem(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// parseBatchParams parses the ABI encoded array of send asset tuples following
+// the function type
+func (c *FSNContract) parseBatchParams() ([]*FcParams, error) {
+	if offset, overflow := c.getUint64(32); overflow || offset != 32 {
+		return nil, ErrWrongLenOfInput
+	}
+	count, overflow := c.getUint64(64)
+	if overflow || count == 0 || count > maxBatchSendAssetItems {
+		return nil, ErrWrongLenOfInput
+	}
+	if uint64(len(c.input)) != 96+count*fcSendAssetItemSize {
+		return nil, ErrWrongLenOfInput
+	}
+	items := make([]*FcParams, 0, count)
+	for pos := uint64(96); pos < uint64(len(c.input)); {
+		p, next, err := c.parseItem(pos)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.adjustItem(p); err != nil {
+			return nil, err
+		}
+		items = append(items, p)
+		pos = next
+	}
+	return items, nil
+}
+
+// parseItem parses the send asset tuple at pos and returns the position
+// after it
+func (c *FSNContract) parseItem(pos uint64) (*FcParams, uint64, error) {
 	p := &FcParams{}
 	var overflow bool
 
-	pos := uint64(32)
 	p.asset = common.BytesToHash(getData(c.input, pos, 32))
 	pos += 32
 	p.address = common.BytesToAddress(getData(c.input, pos, 32))
@@ -135,24 +327,25 @@ func (c *FSNContract) parseParams() (*FcParams, error) {
 	p.value = c.getBigInt(pos)
 	pos += 32
 	if p.start, overflow = c.getUint64(pos); overflow {
-		return nil, ErrValueOverflow
+		return nil, 0, ErrValueOverflow
 	}
 	pos += 32
 	if p.end, overflow = c.getUint64(pos); overflow {
-		return nil, ErrValueOverflow
+		return nil, 0, ErrValueOverflow
 	}
 	pos += 32
 	biFlag := c.getBigInt(pos)
 	pos += 32
 	if biFlag.Cmp(big.NewInt(int64(common.FcInvalidSendAssetFlag))) >= 0 {
-		return nil, ErrFcInvalidSendAssetFlag
+		return nil, 0, ErrFcInvalidSendAssetFlag
 	}
 	p.flag = common.FcSendAssetFlag(biFlag.Uint64())
+	return p, pos, nil
+}
 
-	if uint64(len(c.input)) != pos {
-		return nil, ErrWrongLenOfInput
-	}
-
+// adjustItem applies the time range defaults of a send asset tuple and
+// checks the range
+func (c *FSNContract) adjustItem(p *FcParams) error {
 	// adjust
 	timestamp := c.evm.Context.Time.Uint64()
 	if p.start < timestamp {
@@ -164,9 +357,9 @@ func (c *FSNContract) parseParams() (*FcParams, error) {
 
 	// check
 	if p.start > p.end {
-		return nil, ErrWrongTimeRange
+		return ErrWrongTimeRange
 	}
-	return p, nil
+	return nil
 }
 
 func toOKData(str string) []byte {
@@ -176,3 +369,15 @@ func toOKData(str string) []byte {
 func toErrData(err error) []byte {
 	return []byte("Error: " + err.Error())
 }
+
+// toRevertData encodes err as a Solidity custom error, or as Error(string) if
+// it has no custom error
+func toRevertData(err error) []byte {
+	if selector, ok := FSNContractSchema.errorSelector(err); ok {
+		return selector
+	}
+	msg := []byte(err.Error())
+	ret := append(common.CopyBytes(fcErrorStringSelector), common.LeftPadBytes(big.NewInt(32).Bytes(), 32)...)
+	ret = append(ret, common.LeftPadBytes(big.NewInt(int64(len(msg))).Bytes(), 32)...)
+	return append(ret, common.RightPadBytes(msg, (len(msg)+31)/32*32)...)
+}
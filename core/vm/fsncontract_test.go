@@ -0,0 +1,288 @@
+package vm
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/crypto"
+	"github.com/FusionFoundation/go-fusion/params"
+)
+
+// batchInput encodes a batch send asset call of the given (value, start, end) items
+func batchInput(items [][3]uint64) []byte {
+	word := func(v uint64) []byte {
+		return common.LeftPadBytes(new(big.Int).SetUint64(v).Bytes(), 32)
+	}
+	input := append(word(uint64(FcBatchSendAsset)), word(32)...)
+	input = append(input, word(uint64(len(items)))...)
+	for i, item := range items {
+		input = append(input, common.SystemAssetID.Bytes()...)
+		input = append(input, common.LeftPadBytes(big.NewInt(int64(i+1)).Bytes(), 32)...)
+		input = append(input, word(item[0])...)
+		input = append(input, word(item[1])...)
+		input = append(input, word(item[2])...)
+		input = append(input, word(uint64(common.FcUseAny))...)
+	}
+	return input
+}
+
+func TestFcBatchSendAssetParams(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	evm := &EVM{Context: Context{BlockNumber: big.NewInt(1), Time: big.NewInt(1000)}}
+	c := NewFSNContract(evm, nil)
+
+	input := batchInput([][3]uint64{{10, 0, 0}, {20, 2000, 3000}})
+	if gas := c.RequiredGas(input); gas != params.FsnContractGas+2*params.FsnContractBatchItemGas {
+		t.Fatalf("wrong gas %d", gas)
+	}
+	c.input = input
+	items, err := c.parseBatchParams()
+	if err != nil {
+		t.Fatalf("failed to parse batch: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+	if items[0].start != 1000 || items[0].end != common.TimeLockForever || items[0].value.Uint64() != 10 {
+		t.Fatalf("first item not adjusted: %+v", items[0])
+	}
+	if items[1].address != common.BigToAddress(big.NewInt(2)) || items[1].start != 2000 || items[1].end != 3000 {
+		t.Fatalf("second item mismatch: %+v", items[1])
+	}
+
+	for name, input := range map[string][]byte{
+		"truncated":  input[:len(input)-1],
+		"empty":      batchInput(nil),
+		"bad range":  batchInput([][3]uint64{{10, 3000, 2000}}),
+		"bad offset": append(append([]byte{}, input[:32]...), append(make([]byte, 32), input[64:]...)...),
+	} {
+		c.input = input
+		if _, err := c.parseBatchParams(); err == nil {
+			t.Errorf("%s batch accepted", name)
+		}
+	}
+}
+
+func TestFsnContractAbiResults(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	evm := &EVM{Context: Context{BlockNumber: big.NewInt(1), Time: big.NewInt(1000)}}
+	c := NewFSNContract(evm, nil)
+
+	ret, err := c.Run(make([]byte, 32))
+	if err != errExecutionReverted {
+		t.Fatalf("unknown func did not revert: %v", err)
+	}
+	if !bytes.Equal(ret, crypto.Keccak256([]byte("UnknownFunc()"))[:4]) {
+		t.Fatalf("wrong custom error %x", ret)
+	}
+	if ret := c.okData("batchSendAsset", big.NewInt(3)); !bytes.Equal(ret, common.LeftPadBytes([]byte{3}, 32)) {
+		t.Fatalf("wrong result %x", ret)
+	}
+
+	ret = toRevertData(errors.New("boom"))
+	if !bytes.Equal(ret[:4], common.FromHex("0x08c379a0")) || len(ret) != 4+3*32 || string(ret[68:72]) != "boom" {
+		t.Fatalf("wrong Error(string) data %x", ret)
+	}
+}
+
+func TestFcBatchSendAssetLogs(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	var sent []common.Address
+	ctx := Context{
+		BlockNumber:         big.NewInt(1),
+		Time:                big.NewInt(1000),
+		CanTransferTimeLock: func(StateDB, common.Address, *common.TransferTimeLockParam) bool { return true },
+		TransferTimeLock: func(db StateDB, sender, recipient common.Address, p *common.TransferTimeLockParam) {
+			sent = append(sent, recipient)
+		},
+	}
+	evm := NewEVM(ctx, statedb, params.TestChainConfig, Config{})
+
+	origin, caller := AccountRef(common.HexToAddress("0xaa")), AccountRef(common.HexToAddress("0xbb"))
+	parent := NewContract(origin, caller, new(big.Int), 0)
+	c := NewFSNContract(evm, NewContract(parent, AccountRef(FSNContractAddress), new(big.Int), 0))
+
+	ret, err := c.Run(batchInput([][3]uint64{{10, 0, 0}, {20, 2000, 3000}}))
+	if err != nil {
+		t.Fatalf("batch send failed: %v", err)
+	}
+	if !bytes.Equal(ret, common.LeftPadBytes([]byte{2}, 32)) || len(sent) != 2 {
+		t.Fatalf("wrong result %x after %d transfers", ret, len(sent))
+	}
+	logs := statedb.Logs()
+	if len(logs) != 2 {
+		t.Fatalf("got %d logs, want 2", len(logs))
+	}
+	log := logs[1]
+	if log.Address != FSNContractAddress || len(log.Topics) != 4 || log.Topics[0] != FcSendAssetTimeLockTopic ||
+		log.Topics[1] != caller.Address().Hash() || log.Topics[2] != sent[1].Hash() || log.Topics[3] != common.SystemAssetID {
+		t.Fatalf("wrong log topics %v", log.Topics)
+	}
+	if want := append(common.LeftPadBytes([]byte{20}, 32), append(common.LeftPadBytes(big.NewInt(2000).Bytes(), 32), common.LeftPadBytes(big.NewInt(3000).Bytes(), 32)...)...); !bytes.Equal(log.Data, want) {
+		t.Fatalf("wrong log data %x", log.Data)
+	}
+}
+
+func TestFsnContractGasSchedule(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	evm := NewEVM(Context{BlockNumber: big.NewInt(1), Time: big.NewInt(1000)}, statedb, params.TestChainConfig, Config{})
+	caller := AccountRef(common.HexToAddress("0xbb"))
+	parent := NewContract(AccountRef(common.HexToAddress("0xaa")), caller, new(big.Int), 0)
+	c := NewFSNContract(evm, NewContract(parent, AccountRef(FSNContractAddress), new(big.Int), 0))
+
+	// the sender has three time lock segments, the first receiver one
+	statedb.SetTimeLockBalance(caller.Address(), common.SystemAssetID, common.NewTimeLock(
+		&common.TimeLockItem{StartTime: 1000, EndTime: 2000, Value: big.NewInt(1)},
+		&common.TimeLockItem{StartTime: 3000, EndTime: 4000, Value: big.NewInt(1)},
+		&common.TimeLockItem{StartTime: 5000, EndTime: 6000, Value: big.NewInt(1)},
+	))
+	statedb.SetTimeLockBalance(common.BigToAddress(big.NewInt(1)), common.SystemAssetID, common.NewTimeLock(
+		&common.TimeLockItem{StartTime: 1000, EndTime: 2000, Value: big.NewInt(1)},
+	))
+
+	input := batchInput([][3]uint64{{10, 0, 0}, {20, 2000, 3000}})
+	want := params.FsnContractGas + 2*params.FsnContractBatchItemGas + (3+1+3)*params.FsnContractTimeLockItemGas
+	if gas := c.RequiredGas(input); gas != want {
+		t.Fatalf("batch gas %d, want %d", gas, want)
+	}
+	// a plain asset send only touches the time locks of the receiver
+	single := append(common.LeftPadBytes([]byte{byte(FcSendAsset)}, 32), input[96:96+fcSendAssetItemSize]...)
+	single[len(single)-1] = byte(common.FcUseAsset)
+	if gas := c.RequiredGas(single); gas != params.FsnContractGas {
+		t.Fatalf("asset send gas %d, want %d", gas, params.FsnContractGas)
+	}
+	single[len(single)-1] = byte(common.FcUseAssetToTimeLock)
+	if gas := c.RequiredGas(single); gas != params.FsnContractGas+params.FsnContractTimeLockItemGas {
+		t.Fatalf("asset to time lock send gas %d", gas)
+	}
+}
+
+func TestFsnContractReceiveAssetGuard(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	receiver, sender := AccountRef(common.HexToAddress("0xbb")), common.HexToAddress("0xcc")
+	evm := &EVM{Context: Context{BlockNumber: big.NewInt(1), Time: big.NewInt(1000)}}
+	evm.receivedAsset = &receivedAsset{from: sender, to: receiver.Address(), param: &common.TransferTimeLockParam{
+		AssetID:   common.SystemAssetID,
+		StartTime: 1000,
+		EndTime:   2000,
+		Flag:      common.FcUseAnyToTimeLock,
+		Value:     big.NewInt(7),
+	}}
+	parent := NewContract(AccountRef(sender), receiver, new(big.Int), 0)
+	c := NewFSNContract(evm, NewContract(parent, AccountRef(FSNContractAddress), new(big.Int), 0))
+
+	if _, err := c.Run(batchInput([][3]uint64{{10, 0, 0}})); err != errExecutionReverted {
+		t.Fatalf("reentrant batch send not reverted: %v", err)
+	}
+	ret, err := c.Run(common.LeftPadBytes([]byte{byte(FcGetReceivedAsset)}, 32))
+	if err != nil {
+		t.Fatalf("failed to get received asset: %v", err)
+	}
+	if len(ret) != 6*32 || common.BytesToHash(ret[:32]) != common.SystemAssetID ||
+		common.BytesToAddress(ret[32:64]) != sender || ret[95] != 7 || ret[191] != byte(common.FcUseAnyToTimeLock) {
+		t.Fatalf("wrong received asset %x", ret)
+	}
+
+	// other contracts can not read it
+	other := NewContract(AccountRef(sender), AccountRef(common.HexToAddress("0xdd")), new(big.Int), 0)
+	c = NewFSNContract(evm, NewContract(other, AccountRef(FSNContractAddress), new(big.Int), 0))
+	ret, err = c.Run(common.LeftPadBytes([]byte{byte(FcGetReceivedAsset)}, 32))
+	if err != errExecutionReverted || !bytes.Equal(ret, crypto.Keccak256([]byte("NotReceivingAsset()"))[:4]) {
+		t.Fatalf("received asset readable by other contract: %x", ret)
+	}
+}
+
+func TestReceiveAssetTransferRestriction(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	var received int
+	ctx := Context{
+		BlockNumber:         big.NewInt(1),
+		Time:                big.NewInt(1000),
+		CanTransferTimeLock: func(StateDB, common.Address, *common.TransferTimeLockParam) bool { return true },
+		TransferTimeLock: func(StateDB, common.Address, common.Address, *common.TransferTimeLockParam) {
+			received++
+		},
+	}
+	evm := NewEVM(ctx, statedb, params.TestChainConfig, Config{})
+
+	sender, receiver := common.HexToAddress("0xaa"), common.HexToAddress("0xbb")
+	statedb.SetCode(receiver, []byte{0x00}) // STOP
+	assetID := common.HexToHash("0x01")
+	statedb.GenAsset(common.Asset{ID: assetID, Owner: common.HexToAddress("0xcc"), Total: big.NewInt(100)})
+	statedb.SetAssetTransferRestriction(assetID, common.AssetTransferWhitelist)
+
+	input := append(common.ReceiveAssetFuncHash[:4:4], assetID[:]...)
+	input = append(input, make([]byte, 64)...) // start and end times
+	input = append(input, common.LeftPadBytes([]byte{byte(common.FcUseAsset)}, 32)...)
+	input = append(input, common.LeftPadBytes([]byte{160}, 32)...)
+	input = append(input, make([]byte, 32)...) // empty extra data
+
+	call := func() error {
+		_, _, err := evm.Call(AccountRef(sender), receiver, input, 100000, big.NewInt(10))
+		return err
+	}
+	if err := call(); err != ErrAssetTransferNotAllowed {
+		t.Fatalf("restricted asset received by unlisted contract: %v", err)
+	}
+	statedb.SetAssetTransferListed(assetID, receiver, true)
+	if err := call(); err != ErrAssetTransferNotAllowed {
+		t.Fatalf("restricted asset sent by unlisted sender: %v", err)
+	}
+	statedb.SetAssetTransferListed(assetID, sender, true)
+	if err := call(); err != nil {
+		t.Fatalf("restricted asset not received by listed contract: %v", err)
+	}
+	if received != 1 {
+		t.Fatalf("got %d transfers, want 1", received)
+	}
+}
+
+func TestFcSendAssetFlag(t *testing.T) {
+	word := func(v uint64) []byte {
+		return common.LeftPadBytes(new(big.Int).SetUint64(v).Bytes(), 32)
+	}
+	parse := func(flag uint64) (*FcParams, error) {
+		input := append(word(uint64(FcSendAsset)), common.SystemAssetID.Bytes()...)
+		input = append(input, word(1)...)
+		input = append(input, word(10)...)
+		input = append(input, word(0)...)
+		input = append(input, word(0)...)
+		input = append(input, word(flag)...)
+
+		evm := &EVM{Context: Context{BlockNumber: big.NewInt(1), Time: big.NewInt(1000)}}
+		c := NewFSNContract(evm, nil)
+		c.input = input
+		return c.parseParams()
+	}
+	// FcUseAny already spends as the SmartTransfer time lock of accounts
+	p, err := parse(uint64(common.FcUseAny))
+	if err != nil {
+		t.Fatalf("failed to parse use any flag: %v", err)
+	}
+	if p.flag.IsUseAsset() || p.flag.IsUseTimeLock() || p.flag.IsToTimeLock() {
+		t.Fatalf("use any flag restricted to a balance: %v", p.flag)
+	}
+	if _, err := parse(uint64(common.FcInvalidSendAssetFlag)); err != ErrFcInvalidSendAssetFlag {
+		t.Fatalf("invalid flag accepted: %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package vm
+
+import (
+	"strings"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/crypto"
+)
+
+// FcArgument is a Solidity typed argument of the FSN contract schema. Tuple
+// arrays list the fields of the tuple as components.
+type FcArgument struct {
+	Name       string
+	Type       string
+	Indexed    bool         // event arguments only
+	Struct     string       // name of the tuple, "tuple[]" only
+	Components []FcArgument // "tuple[]" only
+}
+
+// FcFunction is a function of the FSN contract. The input is the function
+// type as a word followed by the ABI encoding of the inputs, the output is the
+// ABI encoding of the outputs since the FSN contract ABI fork.
+type FcFunction struct {
+	Type    FcFuncType
+	Inputs  []FcArgument
+	Outputs []FcArgument
+	View    bool
+}
+
+// FcError is a Solidity custom error the FSN contract reverts with
+type FcError struct {
+	Name string
+	Err  error
+}
+
+// FcEvent is a Solidity event the FSN contract logs
+type FcEvent struct {
+	Name   string
+	Inputs []FcArgument
+}
+
+// FcCallback is a function contracts implement to be called by the node
+type FcCallback struct {
+	Name   string
+	Inputs []FcArgument
+	Doc    string
+}
+
+// FcSchema describes the FSN contract for bindings, see fsnabigen
+type FcSchema struct {
+	Address   common.Address
+	Functions []FcFunction
+	Errors    []FcError
+	Events    []FcEvent
+	Callbacks []FcCallback
+}
+
+// fcSendAssetItem are the fields of a send asset tuple
+var fcSendAssetItem = []FcArgument{
+	{Name: "asset", Type: "bytes32"},
+	{Name: "to", Type: "address"},
+	{Name: "value", Type: "uint256"},
+	{Name: "start", Type: "uint64"},
+	{Name: "end", Type: "uint64"},
+	{Name: "flag", Type: "uint8"},
+}
+
+var fcSendAssetTimeLockEvent = FcEvent{
+	Name: "SendAssetTimeLock",
+	Inputs: []FcArgument{
+		{Name: "from", Type: "address", Indexed: true},
+		{Name: "to", Type: "address", Indexed: true},
+		{Name: "asset", Type: "bytes32", Indexed: true},
+		{Name: "value", Type: "uint256"},
+		{Name: "start", Type: "uint64"},
+		{Name: "end", Type: "uint64"},
+	},
+}
+
+// FSNContractSchema is the source of truth of the FSN contract interface, the
+// contract itself and the generated bindings are derived from it
+var FSNContractSchema = FcSchema{
+	Address: FSNContractAddress,
+	Functions: []FcFunction{
+		{
+			Type:    FcSendAsset,
+			Inputs:  fcSendAssetItem,
+			Outputs: []FcArgument{{Name: "ok", Type: "bool"}},
+		},
+		{
+			Type:    FcBatchSendAsset,
+			Inputs:  []FcArgument{{Name: "items", Type: "tuple[]", Struct: "SendAssetItem", Components: fcSendAssetItem}},
+			Outputs: []FcArgument{{Name: "count", Type: "uint256"}},
+		},
+		{
+			Type: FcGetReceivedAsset,
+			Outputs: []FcArgument{
+				{Name: "asset", Type: "bytes32"},
+				{Name: "from", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "start", Type: "uint64"},
+				{Name: "end", Type: "uint64"},
+				{Name: "flag", Type: "uint8"},
+			},
+			View: true,
+		},
+	},
+	Errors: []FcError{
+		{Name: "UnknownFunc", Err: ErrUnknownFunc},
+		{Name: "NotEnoughBalance", Err: ErrNotEnoughBalance},
+		{Name: "WrongTimeRange", Err: ErrWrongTimeRange},
+		{Name: "ValueOverflow", Err: ErrValueOverflow},
+		{Name: "WrongLenOfInput", Err: ErrWrongLenOfInput},
+		{Name: "InvalidSendAssetFlag", Err: ErrFcInvalidSendAssetFlag},
+		{Name: "MustCallByContract", Err: ErrMustCallByContract},
+		{Name: "ReentrantCall", Err: ErrFcReentrantCall},
+		{Name: "NotReceivingAsset", Err: ErrFcNotReceivingAsset},
+	},
+	Events: []FcEvent{fcSendAssetTimeLockEvent},
+	Callbacks: []FcCallback{
+		{
+			Name: "receiveAsset",
+			Inputs: []FcArgument{
+				{Name: "assetID", Type: "bytes32"},
+				{Name: "startTime", Type: "uint64"},
+				{Name: "endTime", Type: "uint64"},
+				{Name: "flag", Type: "uint8"},
+				{Name: "extraData", Type: "uint256[]"},
+			},
+			Doc: "Called with the sent amount as value when an account sends an asset to the contract",
+		},
+	},
+}
+
+// fcArgumentTypes returns the canonical types of args for signatures
+func fcArgumentTypes(args []FcArgument) string {
+	types := make([]string, len(args))
+	for i, arg := range args {
+		types[i] = arg.Type
+		if arg.Type == "tuple[]" {
+			types[i] = "(" + fcArgumentTypes(arg.Components) + ")[]"
+		}
+	}
+	return strings.Join(types, ",")
+}
+
+// Signature returns the canonical signature of the error
+func (e FcError) Signature() string {
+	return e.Name + "()"
+}
+
+// Selector returns the revert data of the error
+func (e FcError) Selector() []byte {
+	return crypto.Keccak256([]byte(e.Signature()))[:4]
+}
+
+// Signature returns the canonical signature of the event
+func (e FcEvent) Signature() string {
+	return e.Name + "(" + fcArgumentTypes(e.Inputs) + ")"
+}
+
+// Topic returns the first topic of the logs of the event
+func (e FcEvent) Topic() common.Hash {
+	return crypto.Keccak256Hash([]byte(e.Signature()))
+}
+
+// Signature returns the canonical signature of the callback
+func (c FcCallback) Signature() string {
+	return c.Name + "(" + fcArgumentTypes(c.Inputs) + ")"
+}
+
+// errorSelector returns the custom error selector of err
+func (s *FcSchema) errorSelector(err error) ([]byte, bool) {
+	for _, e := range s.Errors {
+		if e.Err == err {
+			return e.Selector(), true
+		}
+	}
+	return nil, false
+}
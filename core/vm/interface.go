@@ -34,7 +34,7 @@ type StateDB interface {
 	AddTimeLockBalance(common.Address, common.Hash, *common.TimeLock, *big.Int, uint64)
 	SetTimeLockBalance(common.Address, common.Hash, *common.TimeLock)
 	GetTimeLockBalance(common.Hash, common.Address) *common.TimeLock
-	TransferNotation(notation uint64, from common.Address, to common.Address) error
+	TransferNotation(notation uint64, from common.Address, to common.Address, blockNumber *big.Int) error
 
 	GetNonce(common.Address) uint64
 	SetNonce(common.Address, uint64)
@@ -70,15 +70,48 @@ type StateDB interface {
 
 	ForEachStorage(common.Address, func(common.Hash, common.Hash) bool)
 
-	GenNotation(common.Address) error
+	GenNotation(common.Address, *big.Int) error
 	GetNotation(common.Address) uint64
+	GetAddressByNotation(notation uint64) (common.Address, error)
 
 	GenAsset(common.Asset) error
 	UpdateAsset(common.Asset) error
+	GetAssetIDBySymbol(symbol string) (common.Hash, bool)
+	RegisterAssetSymbol(symbol string, assetID common.Hash) error
+	GetAssetTransferRestriction(assetID common.Hash) common.AssetTransferRestriction
+	SetAssetTransferRestriction(assetID common.Hash, restriction common.AssetTransferRestriction)
+	SetAssetTransferListed(assetID common.Hash, addr common.Address, listed bool)
+	IsAssetTransferAllowed(assetID common.Hash, addr common.Address) bool
+	GetAssetOwnerSet(assetID common.Hash) *common.AssetOwnerSet
+	SetAssetOwnerSet(assetID common.Hash, owners *common.AssetOwnerSet)
+	GetAssetOwnerApprovals(hash common.Hash) *common.AssetOwnerApprovals
+	SetAssetOwnerApprovals(hash common.Hash, approvals *common.AssetOwnerApprovals)
+	AddAssetSupplyChange(assetID common.Hash, change *common.AssetSupplyChange)
+
+	GetEscrow(id common.Hash) (common.Escrow, error)
+	AddEscrow(escrow common.Escrow) error
+	RemoveEscrow(id common.Hash) error
+
+	GetStream(id common.Hash) (common.Stream, error)
+	AddStream(stream common.Stream) error
+	UpdateStream(stream common.Stream) error
+	RemoveStream(id common.Hash) error
+
+	GetCondition(id common.Hash) (common.Condition, error)
+	AddCondition(condition common.Condition) error
+	UpdateCondition(condition common.Condition) error
+	GetConditionalTransfer(id common.Hash) (common.ConditionalTransfer, error)
+	AddConditionalTransfer(transfer common.ConditionalTransfer) error
+	RemoveConditionalTransfer(id common.Hash) error
+
+	GetBridgeDeposit(id common.Hash) *common.BridgeDeposit
+	SetBridgeDeposit(id common.Hash, deposit *common.BridgeDeposit)
 
 	AllTickets() (common.TicketsDataSlice, error)
+	TicketsByOwner(owner common.Address) (common.TicketSlice, error)
 	AddTicket(common.Ticket) error
 	RemoveTicket(id common.Hash) error
+	SpendTicket(id common.Hash) error
 	GetTicket(id common.Hash) (*common.Ticket, error)
 	IsTicketExist(id common.Hash) bool
 
@@ -90,10 +123,30 @@ type StateDB interface {
 	AddMultiSwap(swap common.MultiSwap) error
 	UpdateMultiSwap(swap common.MultiSwap) error
 	RemoveMultiSwap(id common.Hash) error
+	ScheduleSwapDeletion(addr common.Address, id common.Hash, number uint64)
 	GetMultiSwap(swapID common.Hash) (common.MultiSwap, error)
 
 	IsReportExist(report []byte) bool
 	AddReport(report []byte) error
+	IsReported(miner common.Address, number uint64) bool
+	AddReportAt(miner common.Address, number uint64, expiry uint64) error
+
+	GetTypedCallNonce(common.Address) uint64
+	SetTypedCallNonce(common.Address, uint64)
+	GetStakingKey(common.Address) common.Address
+	SetStakingKey(common.Address, common.Address)
+	GetTicketPurchases(common.Address, uint64) uint64
+	SetTicketPurchases(common.Address, uint64, uint64)
+	GetFsnCallFeeEntry(common.FSNCallFunc) *common.FsnCallFeeEntry
+	SetFsnCallFeeEntry(common.FSNCallFunc, *common.FsnCallFeeEntry)
+	GetFsnCallFeeApprovals(common.Hash) []common.Address
+	SetFsnCallFeeApprovals(common.Hash, []common.Address)
+
+	GetProposal(id common.Hash) (common.Proposal, error)
+	AddProposal(proposal common.Proposal) error
+	UpdateProposal(proposal common.Proposal) error
+	GetProposalVote(id common.Hash, addr common.Address) *common.ProposalVote
+	SetProposalVote(id common.Hash, addr common.Address, vote *common.ProposalVote)
 }
 
 // CallContext provides a basic interface for the EVM calling conventions. The EVM EVM
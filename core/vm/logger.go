@@ -103,6 +103,12 @@ type Tracer interface {
 	CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error
 }
 
+// FsnTransferTracer is implemented by the tracers which record the internal
+// transfers made by FSN calls.
+type FsnTransferTracer interface {
+	CaptureFsnTransfer(transfer *common.InternalTransfer)
+}
+
 // StructLogger is an EVM state logger and implements Tracer.
 //
 // StructLogger can capture state based on the given Log configuration and also keeps
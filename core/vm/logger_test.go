@@ -63,3 +63,28 @@ func TestStoreCapture(t *testing.T) {
 		t.Errorf("expected %x, got %x", exp, logger.changedValues[contract.Address()][index])
 	}
 }
+
+type fsnTransferLogger struct {
+	*StructLogger
+	transfers []*common.InternalTransfer
+}
+
+func (l *fsnTransferLogger) CaptureFsnTransfer(transfer *common.InternalTransfer) {
+	l.transfers = append(l.transfers, transfer)
+}
+
+func TestFsnTransferCapture(t *testing.T) {
+	transfer := &common.InternalTransfer{Type: common.TransferSendAsset, Value: big.NewInt(1)}
+
+	logger := &fsnTransferLogger{StructLogger: NewStructLogger(nil)}
+	NewEVM(Context{}, nil, params.TestChainConfig, Config{Tracer: logger}).CaptureFsnTransfer(transfer)
+	if len(logger.transfers) != 0 {
+		t.Fatalf("transfer captured without debugging")
+	}
+	NewEVM(Context{}, nil, params.TestChainConfig, Config{Debug: true, Tracer: logger}).CaptureFsnTransfer(transfer)
+	if len(logger.transfers) != 1 || logger.transfers[0] != transfer {
+		t.Fatalf("transfer not captured: %v", logger.transfers)
+	}
+	// tracers which do not record transfers are skipped
+	NewEVM(Context{}, nil, params.TestChainConfig, Config{Debug: true, Tracer: NewStructLogger(nil)}).CaptureFsnTransfer(transfer)
+}
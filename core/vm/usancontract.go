@@ -0,0 +1,303 @@
+package vm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/crypto"
+	"github.com/FusionFoundation/go-fusion/params"
+)
+
+// USANContractAddress is the ERC721 collection of the notations (USAN), the
+// token ID of a notation is its number
+var USANContractAddress = common.HexToAddress("0x9999999999999999999999999999999999999998")
+
+var (
+	ErrUsanNotOwner         = errors.New("ERC721: caller is not owner nor approved")
+	ErrUsanWrongFrom        = errors.New("ERC721: transfer from incorrect owner")
+	ErrUsanNonexistentToken = errors.New("ERC721: owner query for nonexistent token")
+	ErrUsanZeroAddress      = errors.New("ERC721: address zero is not a valid owner")
+	ErrUsanReceiverHasToken = errors.New("ERC721: receiver already owns a notation")
+	ErrUsanApproveToOwner   = errors.New("ERC721: approval to current owner")
+	ErrUsanUnsafeRecipient  = errors.New("ERC721: transfer to non ERC721Receiver implementer")
+	ErrUsanUnknownFunction  = errors.New("ERC721: unknown function")
+	ErrUsanWriteProtection  = errors.New("ERC721: state change in static call")
+)
+
+const (
+	usanName   = "Fusion USAN"
+	usanSymbol = "USAN"
+)
+
+var (
+	usanApprovalPrefix        = []byte("NotationApproval")
+	usanOperatorApprovalValue = common.BigToHash(common.Big1)
+)
+
+// ERC721 function selectors of the USAN contract
+var (
+	usanNameSelector              = usanSelector("name()")
+	usanSymbolSelector            = usanSelector("symbol()")
+	usanBalanceOfSelector         = usanSelector("balanceOf(address)")
+	usanOwnerOfSelector           = usanSelector("ownerOf(uint256)")
+	usanGetApprovedSelector       = usanSelector("getApproved(uint256)")
+	usanIsApprovedForAllSelector  = usanSelector("isApprovedForAll(address,address)")
+	usanSupportsInterfaceSelector = usanSelector("supportsInterface(bytes4)")
+	usanApproveSelector           = usanSelector("approve(address,uint256)")
+	usanSetApprovalForAllSelector = usanSelector("setApprovalForAll(address,bool)")
+	usanTransferFromSelector      = usanSelector("transferFrom(address,address,uint256)")
+	usanSafeTransferFromSelector  = usanSelector("safeTransferFrom(address,address,uint256)")
+	usanSafeTransferFromData      = usanSelector("safeTransferFrom(address,address,uint256,bytes)")
+)
+
+// ERC721 event topics of the USAN contract
+var (
+	UsanTransferTopic       = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	UsanApprovalTopic       = crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
+	UsanApprovalForAllTopic = crypto.Keccak256Hash([]byte("ApprovalForAll(address,address,bool)"))
+)
+
+// interface IDs the USAN contract supports, ERC165 and ERC721
+var usanInterfaces = map[uint32]bool{
+	0x01ffc9a7: true,
+	0x80ac58cd: true,
+}
+
+func usanSelector(signature string) uint32 {
+	h := crypto.Keccak256([]byte(signature))
+	return uint32(h[0])<<24 | uint32(h[1])<<16 | uint32(h[2])<<8 | uint32(h[3])
+}
+
+// USANContract exposes the notations as a virtual ERC721 collection. Safe
+// transfers to contracts are rejected as the receiver hook is not called.
+type USANContract struct {
+	evm      *EVM
+	contract *Contract
+	readOnly bool
+	input    []byte
+}
+
+func NewUSANContract(evm *EVM, contract *Contract, readOnly bool) *USANContract {
+	return &USANContract{
+		evm:      evm,
+		contract: contract,
+		readOnly: readOnly,
+	}
+}
+
+func (c *USANContract) selector(input []byte) uint32 {
+	if len(input) < 4 {
+		return 0
+	}
+	return uint32(input[0])<<24 | uint32(input[1])<<16 | uint32(input[2])<<8 | uint32(input[3])
+}
+
+func (c *USANContract) RequiredGas(input []byte) uint64 {
+	switch c.selector(input) {
+	case usanApproveSelector, usanSetApprovalForAllSelector, usanTransferFromSelector,
+		usanSafeTransferFromSelector, usanSafeTransferFromData:
+		return params.UsanContractWriteGas
+	}
+	return params.UsanContractReadGas
+}
+
+func (c *USANContract) Run(input []byte) (ret []byte, err error) {
+	c.input = input
+	switch c.selector(input) {
+	case usanNameSelector:
+		ret = usanString(usanName)
+	case usanSymbolSelector:
+		ret = usanString(usanSymbol)
+	case usanSupportsInterfaceSelector:
+		id := c.selector(getData(input, 4, 4))
+		ret = usanBool(usanInterfaces[id])
+	case usanBalanceOfSelector:
+		ret, err = c.balanceOf()
+	case usanOwnerOfSelector:
+		var owner common.Address
+		if owner, err = c.ownerOf(c.getBigInt(0)); err == nil {
+			ret = owner.Hash().Bytes()
+		}
+	case usanGetApprovedSelector:
+		ret, err = c.getApproved()
+	case usanIsApprovedForAllSelector:
+		ret = usanBool(c.isApprovedForAll(c.getAddress(0), c.getAddress(1)))
+	case usanApproveSelector:
+		err = c.approve()
+	case usanSetApprovalForAllSelector:
+		err = c.setApprovalForAll()
+	case usanTransferFromSelector:
+		err = c.transferFrom(false)
+	case usanSafeTransferFromSelector, usanSafeTransferFromData:
+		err = c.transferFrom(true)
+	default:
+		err = ErrUsanUnknownFunction
+	}
+	if err != nil {
+		return toRevertData(err), errExecutionReverted
+	}
+	return ret, nil
+}
+
+// getBigInt returns the argument word at index
+func (c *USANContract) getBigInt(index uint64) *big.Int {
+	return new(big.Int).SetBytes(getData(c.input, 4+index*32, 32))
+}
+
+func (c *USANContract) getAddress(index uint64) common.Address {
+	return common.BytesToAddress(getData(c.input, 4+index*32, 32))
+}
+
+func (c *USANContract) ownerOf(tokenID *big.Int) (common.Address, error) {
+	if !tokenID.IsUint64() || tokenID.Sign() == 0 {
+		return common.Address{}, ErrUsanNonexistentToken
+	}
+	owner, err := c.evm.StateDB.GetAddressByNotation(tokenID.Uint64())
+	if err != nil || c.evm.StateDB.GetNotation(owner) != tokenID.Uint64() {
+		return common.Address{}, ErrUsanNonexistentToken
+	}
+	return owner, nil
+}
+
+func (c *USANContract) balanceOf() ([]byte, error) {
+	owner := c.getAddress(0)
+	if owner == (common.Address{}) {
+		return nil, ErrUsanZeroAddress
+	}
+	if c.evm.StateDB.GetNotation(owner) == 0 {
+		return common.Hash{}.Bytes(), nil
+	}
+	return common.BigToHash(common.Big1).Bytes(), nil
+}
+
+// approvalKey is the storage slot of the approved address of the notation,
+// keyed by its owner so that approvals lapse when it changes hands
+func approvalKey(owner common.Address, notation uint64) common.Hash {
+	return crypto.Keccak256Hash(usanApprovalPrefix, owner.Bytes(), common.Uint64ToBytes(notation))
+}
+
+func operatorKey(owner, operator common.Address) common.Hash {
+	return crypto.Keccak256Hash(usanApprovalPrefix, owner.Bytes(), operator.Bytes())
+}
+
+func (c *USANContract) approved(owner common.Address, notation uint64) common.Address {
+	return common.BytesToAddress(c.evm.StateDB.GetState(common.NotationKeyAddress, approvalKey(owner, notation)).Bytes())
+}
+
+func (c *USANContract) isApprovedForAll(owner, operator common.Address) bool {
+	return c.evm.StateDB.GetState(common.NotationKeyAddress, operatorKey(owner, operator)) == usanOperatorApprovalValue
+}
+
+func (c *USANContract) getApproved() ([]byte, error) {
+	tokenID := c.getBigInt(0)
+	owner, err := c.ownerOf(tokenID)
+	if err != nil {
+		return nil, err
+	}
+	return c.approved(owner, tokenID.Uint64()).Hash().Bytes(), nil
+}
+
+func (c *USANContract) approve() error {
+	if c.readOnly {
+		return ErrUsanWriteProtection
+	}
+	to, tokenID := c.getAddress(0), c.getBigInt(1)
+	owner, err := c.ownerOf(tokenID)
+	if err != nil {
+		return err
+	}
+	if to == owner {
+		return ErrUsanApproveToOwner
+	}
+	caller := c.contract.Caller()
+	if caller != owner && !c.isApprovedForAll(owner, caller) {
+		return ErrUsanNotOwner
+	}
+	c.evm.StateDB.SetState(common.NotationKeyAddress, approvalKey(owner, tokenID.Uint64()), to.Hash())
+	c.addLog(UsanApprovalTopic, owner.Hash(), to.Hash(), common.BigToHash(tokenID))
+	return nil
+}
+
+func (c *USANContract) setApprovalForAll() error {
+	if c.readOnly {
+		return ErrUsanWriteProtection
+	}
+	owner, operator := c.contract.Caller(), c.getAddress(0)
+	if operator == owner {
+		return ErrUsanApproveToOwner
+	}
+	approved := c.getBigInt(1).Sign() != 0
+	value := common.Hash{}
+	if approved {
+		value = usanOperatorApprovalValue
+	}
+	c.evm.StateDB.SetState(common.NotationKeyAddress, operatorKey(owner, operator), value)
+	c.evm.StateDB.AddLog(&types.Log{
+		Address:     USANContractAddress,
+		Topics:      []common.Hash{UsanApprovalForAllTopic, owner.Hash(), operator.Hash()},
+		Data:        usanBool(approved),
+		BlockNumber: c.evm.BlockNumber.Uint64(),
+	})
+	return nil
+}
+
+// transferFrom moves the notation with TransferNotation. Unlike notation
+// transfers it fails instead of burning the notation of the receiver.
+func (c *USANContract) transferFrom(safe bool) error {
+	if c.readOnly {
+		return ErrUsanWriteProtection
+	}
+	from, to, tokenID := c.getAddress(0), c.getAddress(1), c.getBigInt(2)
+	owner, err := c.ownerOf(tokenID)
+	if err != nil {
+		return err
+	}
+	if owner != from {
+		return ErrUsanWrongFrom
+	}
+	if to == (common.Address{}) {
+		return ErrUsanZeroAddress
+	}
+	notation := tokenID.Uint64()
+	caller := c.contract.Caller()
+	if caller != owner && c.approved(owner, notation) != caller && !c.isApprovedForAll(owner, caller) {
+		return ErrUsanNotOwner
+	}
+	state := c.evm.StateDB
+	if state.GetNotation(to) != 0 {
+		return ErrUsanReceiverHasToken
+	}
+	if safe && state.GetCodeSize(to) > 0 {
+		return ErrUsanUnsafeRecipient
+	}
+	if err := state.TransferNotation(notation, from, to, c.evm.BlockNumber); err != nil {
+		return err
+	}
+	state.SetState(common.NotationKeyAddress, approvalKey(owner, notation), common.Hash{})
+	c.addLog(UsanTransferTopic, from.Hash(), to.Hash(), common.BigToHash(tokenID))
+	return nil
+}
+
+func (c *USANContract) addLog(topics ...common.Hash) {
+	c.evm.StateDB.AddLog(&types.Log{
+		Address:     USANContractAddress,
+		Topics:      topics,
+		BlockNumber: c.evm.BlockNumber.Uint64(),
+	})
+}
+
+func usanBool(b bool) []byte {
+	if b {
+		return common.BigToHash(common.Big1).Bytes()
+	}
+	return common.Hash{}.Bytes()
+}
+
+// usanString returns the ABI encoding of a string result
+func usanString(s string) []byte {
+	ret := common.BigToHash(big.NewInt(32)).Bytes()
+	ret = append(ret, common.BigToHash(big.NewInt(int64(len(s)))).Bytes()...)
+	return append(ret, common.RightPadBytes([]byte(s), (len(s)+31)/32*32)...)
+}
@@ -0,0 +1,89 @@
+package vm
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/params"
+)
+
+func usanInput(selector uint32, args ...common.Hash) []byte {
+	input := []byte{byte(selector >> 24), byte(selector >> 16), byte(selector >> 8), byte(selector)}
+	for _, arg := range args {
+		input = append(input, arg.Bytes()...)
+	}
+	return input
+}
+
+func TestUsanContract(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	evm := NewEVM(Context{BlockNumber: big.NewInt(1), Time: big.NewInt(1000)}, statedb, params.TestChainConfig, Config{})
+
+	alice, bob, carol := common.HexToAddress("0xaa"), common.HexToAddress("0xbb"), common.HexToAddress("0xcc")
+	statedb.GenNotation(alice, evm.BlockNumber)
+	statedb.GenNotation(carol, evm.BlockNumber)
+	notation := statedb.GetNotation(alice)
+	tokenID := common.BigToHash(new(big.Int).SetUint64(notation))
+
+	call := func(caller common.Address, readOnly bool, input []byte) ([]byte, error) {
+		c := NewUSANContract(evm, NewContract(AccountRef(caller), AccountRef(USANContractAddress), new(big.Int), 0), readOnly)
+		return c.Run(input)
+	}
+
+	ret, err := call(bob, true, usanInput(usanOwnerOfSelector, tokenID))
+	if err != nil || common.BytesToAddress(ret) != alice {
+		t.Fatalf("ownerOf returned %x, %v", ret, err)
+	}
+	if ret, _ := call(bob, true, usanInput(usanBalanceOfSelector, bob.Hash())); new(big.Int).SetBytes(ret).Sign() != 0 {
+		t.Fatalf("balanceOf bob is %x", ret)
+	}
+	if _, err := call(bob, true, usanInput(usanOwnerOfSelector, common.BigToHash(big.NewInt(1)))); err != errExecutionReverted {
+		t.Fatalf("ownerOf nonexistent token: %v", err)
+	}
+
+	transfer := usanInput(usanTransferFromSelector, alice.Hash(), bob.Hash(), tokenID)
+	if _, err := call(bob, false, transfer); err != errExecutionReverted {
+		t.Fatalf("unapproved transfer: %v", err)
+	}
+	if _, err := call(alice, true, usanInput(usanApproveSelector, bob.Hash(), tokenID)); err != errExecutionReverted {
+		t.Fatalf("approve in static call: %v", err)
+	}
+	if _, err := call(alice, false, usanInput(usanApproveSelector, bob.Hash(), tokenID)); err != nil {
+		t.Fatalf("approve failed: %v", err)
+	}
+	if ret, _ := call(bob, true, usanInput(usanGetApprovedSelector, tokenID)); common.BytesToAddress(ret) != bob {
+		t.Fatalf("getApproved returned %x", ret)
+	}
+	// the receiver's notation is not burnt
+	toCarol := usanInput(usanTransferFromSelector, alice.Hash(), carol.Hash(), tokenID)
+	if _, err := call(bob, false, toCarol); err != errExecutionReverted {
+		t.Fatalf("transfer to notation holder: %v", err)
+	}
+	if _, err := call(bob, false, transfer); err != nil {
+		t.Fatalf("approved transfer failed: %v", err)
+	}
+	if statedb.GetNotation(bob) != notation || statedb.GetNotation(alice) != 0 {
+		t.Fatalf("notation not transferred")
+	}
+	if ret, _ := call(bob, true, usanInput(usanGetApprovedSelector, tokenID)); common.BytesToAddress(ret) != (common.Address{}) {
+		t.Fatalf("approval kept after transfer: %x", ret)
+	}
+	logs := statedb.Logs()
+	log := logs[len(logs)-1]
+	if log.Address != USANContractAddress || len(log.Topics) != 4 || log.Topics[0] != UsanTransferTopic ||
+		log.Topics[1] != alice.Hash() || log.Topics[2] != bob.Hash() || log.Topics[3] != tokenID {
+		t.Fatalf("wrong transfer log %v", log.Topics)
+	}
+
+	ret, err = call(bob, true, usanInput(usanSupportsInterfaceSelector, common.BytesToHash(common.RightPadBytes([]byte{0x80, 0xac, 0x58, 0xcd}, 32))))
+	if err != nil || !bytes.Equal(ret, usanBool(true)) {
+		t.Fatalf("ERC721 interface not supported: %x %v", ret, err)
+	}
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/FusionFoundation/go-fusion/core/rawdb"
 	"github.com/FusionFoundation/go-fusion/core/state"
 	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/eth/fsnindex"
 	"github.com/FusionFoundation/go-fusion/internal/ethapi"
 	"github.com/FusionFoundation/go-fusion/rlp"
 	"github.com/FusionFoundation/go-fusion/rpc"
@@ -138,6 +139,13 @@ func (api *PrivateMinerAPI) StopAutoBuyTicket() {
 	common.AutoBuyTicket = false
 }
 
+// SetAutoBuyTicketTarget sets the number of tickets the auto ticket purchaser
+// maintains for the etherbase, 0 buys a ticket every block.
+func (api *PrivateMinerAPI) SetAutoBuyTicketTarget(target hexutil.Uint64) bool {
+	ethapi.SetAutoBuyTicketTarget(uint64(target))
+	return true
+}
+
 // SetExtra sets the extra data string that is included when this miner mines a block.
 func (api *PrivateMinerAPI) SetExtra(extra string) (bool, error) {
 	if err := api.e.Miner().SetExtra([]byte(extra)); err != nil {
@@ -282,6 +290,45 @@ func (api *PrivateAdminAPI) ImportChain(file string) (bool, error) {
 	return true, nil
 }
 
+// TicketCacheStats returns the size and usage of the ticket cache.
+func (api *PrivateAdminAPI) TicketCacheStats() state.TicketCacheStats {
+	return api.eth.BlockChain().StateCache().TicketCache().Stats()
+}
+
+// ResizeTicketCache changes the number of ticket sets kept in the ticket cache,
+// keeping the most recently added ones.
+func (api *PrivateAdminAPI) ResizeTicketCache(size int) (bool, error) {
+	if err := api.eth.BlockChain().StateCache().TicketCache().Resize(size); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// PurgeTicketCache drops all cached ticket sets.
+func (api *PrivateAdminAPI) PurgeTicketCache() bool {
+	api.eth.BlockChain().StateCache().TicketCache().Purge()
+	return true
+}
+
+// FsnResultCacheStats returns the size and usage of the historical FSN query
+// result cache.
+func (api *PrivateAdminAPI) FsnResultCacheStats() (*fsnindex.ResultCacheStats, error) {
+	if api.eth.resultCache == nil {
+		return nil, errors.New("fsn result cache is disabled")
+	}
+	stats := api.eth.resultCache.Stats()
+	return &stats, nil
+}
+
+// PurgeFsnResultCache drops all cached historical FSN query results.
+func (api *PrivateAdminAPI) PurgeFsnResultCache() (bool, error) {
+	if api.eth.resultCache == nil {
+		return false, errors.New("fsn result cache is disabled")
+	}
+	api.eth.resultCache.Purge()
+	return true, nil
+}
+
 // PublicDebugAPI is the collection of Ethereum full node APIs exposed
 // over the public debugging endpoint.
 type PublicDebugAPI struct {
@@ -241,6 +241,14 @@ func (b *EthAPIBackend) GetPoolTransactions() (types.Transactions, error) {
 	return txs, nil
 }
 
+// OpenSwaps returns the open swaps made by owner, from the swap history index
+func (b *EthAPIBackend) OpenSwaps(owner common.Address, now uint64) ([]common.Hash, error) {
+	if b.eth.swapIndexer == nil {
+		return nil, errors.New("swap history index is not enabled")
+	}
+	return b.eth.swapIndexer.OpenSwaps(owner, now)
+}
+
 func (b *EthAPIBackend) GetPoolTransaction(hash common.Hash) *types.Transaction {
 	return b.eth.txPool.Get(hash)
 }
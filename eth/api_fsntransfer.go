@@ -0,0 +1,132 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/consensus/datong"
+	"github.com/FusionFoundation/go-fusion/core"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/core/vm"
+)
+
+// PublicFsnTransferAPI provides the internal transfers of FSN calls in the
+// fsn namespace
+type PublicFsnTransferAPI struct {
+	eth *Ethereum
+}
+
+// NewPublicFsnTransferAPI creates a new FSN internal transfer api
+func NewPublicFsnTransferAPI(eth *Ethereum) *PublicFsnTransferAPI {
+	return &PublicFsnTransferAPI{eth: eth}
+}
+
+// GetInternalTransfers re-executes the transaction and returns the value
+// moved by its FSN call, the swap settlements, time lock deliveries and
+// ticket locks among others. Other transactions have no internal transfers.
+func (api *PublicFsnTransferAPI) GetInternalTransfers(ctx context.Context, hash common.Hash) ([]*common.InternalTransfer, error) {
+	tx, blockHash, _, index := rawdb.ReadTransaction(api.eth.ChainDb(), hash)
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %#x not found", hash)
+	}
+	if !common.IsFsnCall(tx.To()) {
+		return []*common.InternalTransfer{}, nil
+	}
+	msg, vmctx, statedb, err := NewPrivateDebugAPI(api.eth).computeTxEnv(blockHash, int(index), defaultTraceReexec)
+	if err != nil {
+		return nil, err
+	}
+	recorder := &fsnTransferRecorder{transfers: []*common.InternalTransfer{}}
+	vmenv := vm.NewEVM(vmctx, statedb, api.eth.blockchain.Config(), vm.Config{Debug: true, Tracer: recorder})
+	if _, _, _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas())); err != nil {
+		return nil, fmt.Errorf("tracing failed: %v", err)
+	}
+	return recorder.transfers, nil
+}
+
+// GetBlockInternalTransfers re-executes the block and returns the value moved
+// when it was finalized, the unit returned by each spent ticket to its owner.
+// The transfers of its transactions are returned by GetInternalTransfers.
+func (api *PublicFsnTransferAPI) GetBlockInternalTransfers(ctx context.Context, hash common.Hash) ([]*common.InternalTransfer, error) {
+	engine, ok := api.eth.engine.(*datong.DaTong)
+	if !ok {
+		return nil, fmt.Errorf("block transfers are only traced with the datong engine")
+	}
+	block := api.eth.blockchain.GetBlockByHash(hash)
+	if block == nil {
+		return nil, fmt.Errorf("block %#x not found", hash)
+	}
+	if block.NumberU64() == 0 {
+		return []*common.InternalTransfer{}, nil
+	}
+	parent := api.eth.blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent %#x not found", block.ParentHash())
+	}
+	statedb, err := NewPrivateDebugAPI(api.eth).computeStateDB(parent, defaultTraceReexec)
+	if err != nil {
+		return nil, err
+	}
+	config := api.eth.blockchain.Config()
+	signer := types.MakeSigner(config, block.Number())
+	for _, tx := range block.Transactions() {
+		msg, _ := tx.AsMessage(signer)
+		vmenv := vm.NewEVM(core.NewEVMContext(msg, block.Header(), api.eth.blockchain, nil), statedb, config, vm.Config{})
+		if _, _, _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(tx.Gas())); err != nil {
+			return nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
+		}
+		statedb.Finalise(config.IsEIP158(block.Number()))
+	}
+	recorder := &fsnTransferRecorder{transfers: []*common.InternalTransfer{}}
+	if err := engine.FinalizeAndTrace(api.eth.blockchain, types.CopyHeader(block.Header()), statedb, recorder); err != nil {
+		return nil, fmt.Errorf("tracing failed: %v", err)
+	}
+	return recorder.transfers, nil
+}
+
+// fsnTransferRecorder is a tracer recording only the internal transfers of
+// FSN calls.
+type fsnTransferRecorder struct {
+	transfers []*common.InternalTransfer
+}
+
+// CaptureFsnTransfer implements vm.FsnTransferTracer.
+func (r *fsnTransferRecorder) CaptureFsnTransfer(transfer *common.InternalTransfer) {
+	r.transfers = append(r.transfers, transfer)
+}
+
+func (r *fsnTransferRecorder) CaptureStart(from common.Address, to common.Address, call bool, input []byte, gas uint64, value *big.Int) error {
+	return nil
+}
+
+func (r *fsnTransferRecorder) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+func (r *fsnTransferRecorder) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+func (r *fsnTransferRecorder) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error {
+	return nil
+}
@@ -40,6 +40,8 @@ import (
 	"github.com/FusionFoundation/go-fusion/core/vm"
 	"github.com/FusionFoundation/go-fusion/eth/downloader"
 	"github.com/FusionFoundation/go-fusion/eth/filters"
+	"github.com/FusionFoundation/go-fusion/eth/fsnexport"
+	"github.com/FusionFoundation/go-fusion/eth/fsnindex"
 	"github.com/FusionFoundation/go-fusion/eth/gasprice"
 	"github.com/FusionFoundation/go-fusion/ethdb"
 	"github.com/FusionFoundation/go-fusion/event"
@@ -88,6 +90,13 @@ type Ethereum struct {
 	bloomRequests chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer  *core.ChainIndexer             // Bloom indexer operating during block imports
 
+	holdersIndexer *fsnindex.HoldersIndexer // Optional asset holders indexer
+	swapIndexer    *fsnindex.SwapIndexer    // Optional swap history indexer
+	historyIndexer *fsnindex.HistoryIndexer // Optional historical FSN stats indexer
+	funcIndexer    *fsnindex.FuncIndexer    // Optional FSN call transactions by function indexer
+	resultCache    *fsnindex.ResultCache    // Optional cache of historical FSN query results
+	exporter       *fsnexport.Exporter      // Optional FSN event exporter
+
 	APIBackend *EthAPIBackend
 
 	miner     *miner.Miner
@@ -185,6 +194,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 			TrieDirtyLimit:      config.TrieDirtyCache,
 			TrieDirtyDisabled:   config.NoPruning,
 			TrieTimeLimit:       config.TrieTimeout,
+			TicketCache:         config.TicketCache,
 		}
 	)
 	eth.blockchain, err = core.NewBlockChain(chainDb, cacheConfig, chainConfig, eth.engine, vmConfig, eth.shouldPreserve)
@@ -198,6 +208,32 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		rawdb.WriteChainConfig(chainDb, genesisHash, chainConfig)
 	}
 	eth.bloomIndexer.Start(eth.blockchain)
+	if config.AssetHoldersIndex {
+		eth.holdersIndexer = fsnindex.NewHoldersIndexer(chainDb, eth.blockchain)
+	}
+	if config.SwapHistoryIndex {
+		eth.swapIndexer = fsnindex.NewSwapIndexer(chainDb, chainConfig)
+		eth.swapIndexer.Start(eth.blockchain)
+	}
+	if config.FsnHistoryIndex {
+		eth.historyIndexer = fsnindex.NewHistoryIndexer(chainDb)
+		eth.historyIndexer.Start(eth.blockchain)
+	}
+	if config.FsnFuncIndex {
+		eth.funcIndexer = fsnindex.NewFuncIndexer(chainDb, chainConfig)
+		eth.funcIndexer.Start(eth.blockchain)
+	}
+	if config.FsnResultCache > 0 {
+		eth.resultCache = fsnindex.NewResultCache(chainDb, config.FsnResultCache)
+		if dt, ok := eth.engine.(*datong.DaTong); ok {
+			dt.SetSnapshotCache(eth.resultCache)
+		}
+	}
+	if config.FsnExport.URL != "" {
+		if eth.exporter, err = fsnexport.New(config.FsnExport, chainDb, eth.blockchain); err != nil {
+			return nil, err
+		}
+	}
 
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = ctx.ResolvePath(config.TxPool.Journal)
@@ -299,6 +335,53 @@ func (s *Ethereum) APIs() []rpc.API {
 		apis = append(apis, s.lesServer.APIs()...)
 	}
 
+	// Append the optional fusion indexes
+	if s.holdersIndexer != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "fsn",
+			Version:   "1.0",
+			Service:   fsnindex.NewPublicHoldersAPI(s.holdersIndexer, s.blockchain),
+			Public:    true,
+		})
+	}
+	if s.swapIndexer != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "fsn",
+			Version:   "1.0",
+			Service:   fsnindex.NewPublicSwapHistoryAPI(s.swapIndexer, s.blockchain),
+			Public:    true,
+		})
+		apis = append(apis, rpc.API{
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   fsnindex.NewPrivateDiffAPI(s.swapIndexer, s.resultCache, s.blockchain),
+		})
+	}
+	if s.historyIndexer != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "fsn",
+			Version:   "1.0",
+			Service:   fsnindex.NewPublicHistoryAPI(s.historyIndexer),
+			Public:    true,
+		})
+	}
+	if s.funcIndexer != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "fsn",
+			Version:   "1.0",
+			Service:   fsnindex.NewPublicFuncIndexAPI(s.funcIndexer),
+			Public:    true,
+		})
+	}
+	if s.resultCache != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "fsn",
+			Version:   "1.0",
+			Service:   fsnindex.NewPublicCacheAPI(s.resultCache, s.blockchain),
+			Public:    true,
+		})
+	}
+
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -306,6 +389,11 @@ func (s *Ethereum) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   NewPublicEthereumAPI(s),
 			Public:    true,
+		}, {
+			Namespace: "fsn",
+			Version:   "1.0",
+			Service:   NewPublicFsnTransferAPI(s),
+			Public:    true,
 		}, {
 			Namespace: "eth",
 			Version:   "1.0",
@@ -531,6 +619,7 @@ func (s *Ethereum) Protocols() []p2p.Protocol {
 		protos[i].Attributes = []enr.Entry{s.currentEthEntry()}
 		protos[i].DialCandidates = s.dialCandiates
 	}
+	protos = append(protos, s.protocolManager.makeFsnProtocol())
 	if s.lesServer != nil {
 		protos = append(protos, s.lesServer.Protocols()...)
 	}
@@ -545,6 +634,14 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 	// Start the bloom bits servicing goroutines
 	s.startBloomHandlers(params.BloomBitsBlocks)
 
+	// Start the optional fusion indexes
+	if s.holdersIndexer != nil {
+		s.holdersIndexer.Start()
+	}
+	if s.exporter != nil {
+		s.exporter.Start()
+	}
+
 	// Start the RPC service
 	s.netRPCService = ethapi.NewPublicNetAPI(srvr, s.NetVersion())
 
@@ -568,6 +665,21 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 // Ethereum protocol.
 func (s *Ethereum) Stop() error {
 	s.bloomIndexer.Close()
+	if s.swapIndexer != nil {
+		s.swapIndexer.Close()
+	}
+	if s.historyIndexer != nil {
+		s.historyIndexer.Close()
+	}
+	if s.funcIndexer != nil {
+		s.funcIndexer.Close()
+	}
+	if s.holdersIndexer != nil {
+		s.holdersIndexer.Stop()
+	}
+	if s.exporter != nil {
+		s.exporter.Stop()
+	}
 	s.blockchain.Stop()
 	s.engine.Close()
 	s.protocolManager.Stop()
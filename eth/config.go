@@ -27,7 +27,9 @@ import (
 	"github.com/FusionFoundation/go-fusion/common"
 	"github.com/FusionFoundation/go-fusion/consensus/ethash"
 	"github.com/FusionFoundation/go-fusion/core"
+	"github.com/FusionFoundation/go-fusion/core/state"
 	"github.com/FusionFoundation/go-fusion/eth/downloader"
+	"github.com/FusionFoundation/go-fusion/eth/fsnexport"
 	"github.com/FusionFoundation/go-fusion/eth/gasprice"
 	"github.com/FusionFoundation/go-fusion/miner"
 	"github.com/FusionFoundation/go-fusion/params"
@@ -50,17 +52,21 @@ var DefaultConfig = Config{
 	TrieCleanCache:     256,
 	TrieDirtyCache:     256,
 	TrieTimeout:        60 * time.Minute,
+	TicketCache:        state.DefaultTicketCacheSize,
 	Miner: miner.Config{
 		GasFloor: 8000000,
 		GasCeil:  8000000,
 		GasPrice: big.NewInt(params.GWei),
 		Recommit: 3 * time.Second,
+
+		FsnFeeWeight: 100,
 	},
 	TxPool: core.DefaultTxPoolConfig,
 	GPO: gasprice.Config{
 		Blocks:     20,
 		Percentile: 60,
 	},
+	FsnExport: fsnexport.DefaultConfig,
 }
 
 func init() {
@@ -102,6 +108,15 @@ type Config struct {
 	NoPruning  bool // Whether to disable pruning and flush everything to disk
 	NoPrefetch bool // Whether to disable prefetching and only load state on demand
 
+	AssetHoldersIndex bool // Whether to maintain the asset holders index
+	SwapHistoryIndex  bool // Whether to maintain the swap history index
+	FsnHistoryIndex   bool // Whether to maintain the historical FSN stats index
+	FsnFuncIndex      bool // Whether to maintain the index of the FSN call transactions by function
+	FsnResultCache    int  // Number of historical FSN query results cached on disk, 0 disables the cache
+
+	// FSN event export options, disabled if the url is empty
+	FsnExport fsnexport.Config
+
 	// Whitelist of required block number -> hash values to accept
 	Whitelist map[uint64]common.Hash `toml:"-"`
 
@@ -125,6 +140,7 @@ type Config struct {
 	TrieCleanCache int
 	TrieDirtyCache int
 	TrieTimeout    time.Duration
+	TicketCache    int // Number of ticket sets kept in the ticket cache
 
 	// Mining options
 	Miner miner.Config
@@ -36,6 +36,9 @@ import (
 
 var (
 	deadline = 5 * time.Minute // consider a filter inactive if it has not been polled for within deadline
+
+	errSwapsLightMode     = errors.New("swap events are not supported in light mode")
+	errFsnReorgsLightMode = errors.New("FSN reorg events are not supported in light mode")
 )
 
 // filter is a helper struct that holds meta information over the filter type
@@ -46,6 +49,7 @@ type filter struct {
 	hashes   []common.Hash
 	crit     FilterCriteria
 	logs     []*types.Log
+	swaps    []*SwapEvent
 	s        *Subscription // associated subscription in event system
 }
 
@@ -232,6 +236,115 @@ func (api *PublicFilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, er
 	return rpcSub, nil
 }
 
+// NewSwapFilter creates a filter that fetches the swap events matching the
+// given criteria, to check for changes call eth_getFilterChanges.
+func (api *PublicFilterAPI) NewSwapFilter(crit SwapCriteria) (rpc.ID, error) {
+	if api.events.lightMode {
+		return "", errSwapsLightMode
+	}
+	var (
+		swaps    = make(chan []*SwapEvent)
+		swapsSub = api.events.SubscribeSwaps(crit, swaps)
+	)
+
+	api.filtersMu.Lock()
+	api.filters[swapsSub.ID] = &filter{typ: SwapsSubscription, deadline: time.NewTimer(deadline), s: swapsSub}
+	api.filtersMu.Unlock()
+
+	go func() {
+		for {
+			select {
+			case s := <-swaps:
+				api.filtersMu.Lock()
+				if f, found := api.filters[swapsSub.ID]; found {
+					f.swaps = append(f.swaps, s...)
+				}
+				api.filtersMu.Unlock()
+			case <-swapsSub.Err():
+				api.filtersMu.Lock()
+				delete(api.filters, swapsSub.ID)
+				api.filtersMu.Unlock()
+				return
+			}
+		}
+	}()
+
+	return swapsSub.ID, nil
+}
+
+// Swaps sends a notification for each swap created, taken, filled, recalled
+// or about to expire that matches the given criteria.
+func (api *PublicFilterAPI) Swaps(ctx context.Context, crit SwapCriteria) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	if api.events.lightMode {
+		return &rpc.Subscription{}, errSwapsLightMode
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		swaps := make(chan []*SwapEvent)
+		swapsSub := api.events.SubscribeSwaps(crit, swaps)
+
+		for {
+			select {
+			case s := <-swaps:
+				for _, event := range s {
+					notifier.Notify(rpcSub.ID, event)
+				}
+			case <-rpcSub.Err():
+				swapsSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				swapsSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// FsnReorgs sends a notification for each asset creation, ticket purchase or
+// swap call of the canonical chain removed by a reorg, naming the object it
+// created or changed.
+func (api *PublicFilterAPI) FsnReorgs(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	if api.events.lightMode {
+		return &rpc.Subscription{}, errFsnReorgsLightMode
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		reorgs := make(chan []*FsnReorgEvent)
+		reorgsSub := api.events.SubscribeFsnReorgs(reorgs)
+
+		for {
+			select {
+			case r := <-reorgs:
+				for _, event := range r {
+					notifier.Notify(rpcSub.ID, event)
+				}
+			case <-rpcSub.Err():
+				reorgsSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				reorgsSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // Logs creates a subscription that fires for all new log that match the given filter criteria.
 func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
@@ -431,6 +544,13 @@ func (api *PublicFilterAPI) GetFilterChanges(id rpc.ID) (interface{}, error) {
 			logs := f.logs
 			f.logs = nil
 			return returnLogs(logs), nil
+		case SwapsSubscription:
+			swaps := f.swaps
+			f.swaps = nil
+			if swaps == nil {
+				return []*SwapEvent{}, nil
+			}
+			return swaps, nil
 		}
 	}
 
@@ -52,6 +52,10 @@ const (
 	PendingTransactionsSubscription
 	// BlocksSubscription queries hashes for blocks that are imported
 	BlocksSubscription
+	// SwapsSubscription queries for swap events of imported blocks
+	SwapsSubscription
+	// FsnReorgsSubscription queries for FSN calls removed by chain reorgs
+	FsnReorgsSubscription
 	// LastSubscription keeps track of the last index
 	LastIndexSubscription
 )
@@ -76,6 +80,9 @@ type subscription struct {
 	logs      chan []*types.Log
 	hashes    chan []common.Hash
 	headers   chan *types.Header
+	swapsCrit SwapCriteria
+	swaps     chan []*SwapEvent
+	reorgs    chan []*FsnReorgEvent
 	installed chan struct{} // closed when the filter is installed
 	err       chan error    // closed when the filter is uninstalled
 }
@@ -86,6 +93,9 @@ type EventSystem struct {
 	backend   Backend
 	lightMode bool
 	lastHead  *types.Header
+	swapCh    chan *swapJob                // blocks and removed logs for the swap loop
+	swaps     map[common.Hash]*trackedSwap // open swaps followed for the swap events, owned by the swap loop
+	swapHead  uint64                       // number of the last block handled by the swap loop
 
 	// Subscriptions
 	txsSub         event.Subscription // Subscription for new transaction event
@@ -114,6 +124,8 @@ func NewEventSystem(backend Backend, lightMode bool) *EventSystem {
 	m := &EventSystem{
 		backend:       backend,
 		lightMode:     lightMode,
+		swapCh:        make(chan *swapJob, chainEvChanSize),
+		swaps:         make(map[common.Hash]*trackedSwap),
 		install:       make(chan *subscription),
 		uninstall:     make(chan *subscription),
 		txsCh:         make(chan core.NewTxsEvent, txChanSize),
@@ -136,6 +148,7 @@ func NewEventSystem(backend Backend, lightMode bool) *EventSystem {
 	}
 
 	go m.eventLoop()
+	go m.swapLoop()
 	return m
 }
 
@@ -167,6 +180,8 @@ func (sub *Subscription) Unsubscribe() {
 			case <-sub.f.logs:
 			case <-sub.f.hashes:
 			case <-sub.f.headers:
+			case <-sub.f.swaps:
+			case <-sub.f.reorgs:
 			}
 		}
 
@@ -339,6 +354,14 @@ func (es *EventSystem) handleRemovedLogs(filters filterIndex, ev core.RemovedLog
 			f.logs <- matchedLogs
 		}
 	}
+	es.queueSwaps(filters, &swapJob{logs: ev.Logs, removed: true})
+	if len(filters[FsnReorgsSubscription]) > 0 {
+		if events := fsnReorgEvents(ev.Logs); len(events) > 0 {
+			for _, f := range filters[FsnReorgsSubscription] {
+				f.reorgs <- events
+			}
+		}
+	}
 }
 
 func (es *EventSystem) handleTxsEvent(filters filterIndex, ev core.NewTxsEvent) {
@@ -355,6 +378,7 @@ func (es *EventSystem) handleChainEvent(filters filterIndex, ev core.ChainEvent)
 	for _, f := range filters[BlocksSubscription] {
 		f.headers <- ev.Block.Header()
 	}
+	es.queueSwaps(filters, &swapJob{block: ev.Block, hash: ev.Hash, logs: ev.Logs})
 	if es.lightMode && len(filters[LogsSubscription]) > 0 {
 		es.lightFilterNewHead(ev.Block.Header(), func(header *types.Header, remove bool) {
 			for _, f := range filters[LogsSubscription] {
@@ -443,6 +467,7 @@ func (es *EventSystem) lightFilterLogs(header *types.Header, addresses []common.
 func (es *EventSystem) eventLoop() {
 	// Ensure all subscriptions get cleaned up
 	defer func() {
+		close(es.swapCh)
 		es.txsSub.Unsubscribe()
 		es.logsSub.Unsubscribe()
 		es.rmLogsSub.Unsubscribe()
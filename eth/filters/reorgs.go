@@ -0,0 +1,135 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/rpc"
+)
+
+// Kinds of the FSN objects a reorg event refers to
+const (
+	FsnObjectAsset  = "asset"
+	FsnObjectTicket = "ticket"
+	FsnObjectSwap   = "swap"
+)
+
+// fsnReorgObjects maps the FSN calls followed for reorgs to the kind of
+// object they create or change
+var fsnReorgObjects = map[common.FSNCallFunc]string{
+	common.GenAssetFunc:         FsnObjectAsset,
+	common.BuyTicketFunc:        FsnObjectTicket,
+	common.StakingBuyTicketFunc: FsnObjectTicket,
+	common.MakeSwapFunc:         FsnObjectSwap,
+	common.MakeMultiSwapFunc:    FsnObjectSwap,
+	common.TakeSwapFunc:         FsnObjectSwap,
+	common.TakeMultiSwapFunc:    FsnObjectSwap,
+	common.RecallSwapFunc:       FsnObjectSwap,
+	common.RecallMultiSwapFunc:  FsnObjectSwap,
+}
+
+// FsnReorgEvent is a notification about a confirmed FSN call removed from the
+// canonical chain by a reorg. The call may be included again by the new
+// chain, in which case a new log of it is delivered to the log subscriptions.
+type FsnReorgEvent struct {
+	Func        string          `json:"func"`
+	Object      string          `json:"object"`
+	ObjectID    common.Hash     `json:"objectID"`
+	Sender      *common.Address `json:"sender,omitempty"` // only for indexed FSN logs
+	TxHash      common.Hash     `json:"transactionHash"`
+	BlockNumber hexutil.Uint64  `json:"blockNumber"`
+	BlockHash   common.Hash     `json:"blockHash"`
+}
+
+// reorgLogData is the part of the FSN call logs naming their object
+type reorgLogData struct {
+	AssetID  *common.Hash
+	TicketID *common.Hash
+	SwapID   *common.Hash
+	Error    string
+}
+
+// SubscribeFsnReorgs creates a subscription that writes an event for each
+// successful asset creation, ticket purchase or swap call removed by a reorg.
+func (es *EventSystem) SubscribeFsnReorgs(reorgs chan []*FsnReorgEvent) *Subscription {
+	sub := &subscription{
+		id:        rpc.NewID(),
+		typ:       FsnReorgsSubscription,
+		created:   time.Now(),
+		logs:      make(chan []*types.Log),
+		hashes:    make(chan []common.Hash),
+		headers:   make(chan *types.Header),
+		reorgs:    reorgs,
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
+// fsnReorgEvents returns the reorg events of the removed logs, the failed
+// FSN calls changed nothing and are skipped.
+func fsnReorgEvents(logs []*types.Log) []*FsnReorgEvent {
+	var events []*FsnReorgEvent
+	for _, l := range logs {
+		if l.Address != common.FSNCallAddress || len(l.Topics) == 0 {
+			continue
+		}
+		fn, ok := common.FsnCallFuncOfTopic(l.Topics[0])
+		if !ok {
+			continue
+		}
+		object, ok := fsnReorgObjects[fn]
+		if !ok {
+			continue
+		}
+		var data reorgLogData
+		if err := json.Unmarshal(l.Data, &data); err != nil || data.Error != "" {
+			continue
+		}
+		var id *common.Hash
+		switch object {
+		case FsnObjectAsset:
+			id = data.AssetID
+		case FsnObjectTicket:
+			id = data.TicketID
+		case FsnObjectSwap:
+			id = data.SwapID
+		}
+		if id == nil {
+			continue
+		}
+		event := &FsnReorgEvent{
+			Func:        fn.Name(),
+			Object:      object,
+			ObjectID:    *id,
+			TxHash:      l.TxHash,
+			BlockNumber: hexutil.Uint64(l.BlockNumber),
+			BlockHash:   l.BlockHash,
+		}
+		if len(l.Topics) > 1 {
+			sender := common.BytesToAddress(l.Topics[1][:])
+			event.Sender = &sender
+		}
+		events = append(events, event)
+	}
+	return events
+}
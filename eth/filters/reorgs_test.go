@@ -0,0 +1,95 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/types"
+)
+
+// TestFsnReorgSubscription tests that the removed logs of successful asset,
+// ticket and swap calls are delivered with their object IDs.
+func TestFsnReorgSubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		backend = &testBackend{db: db}
+		es      = NewEventSystem(backend, false)
+
+		assetID  = common.HexToHash("0x01")
+		ticketID = common.HexToHash("0x02")
+		swapID   = common.HexToHash("0x03")
+		sender   = common.HexToAddress("0x04")
+	)
+
+	indexed := swapLog(t, common.TakeSwapFunc, map[string]interface{}{"SwapID": swapID, "Deleted": true})
+	indexed.Topics = append(indexed.Topics, sender.Hash(), swapID)
+	logs := []*types.Log{
+		swapLog(t, common.GenAssetFunc, map[string]interface{}{"AssetID": assetID}),
+		swapLog(t, common.BuyTicketFunc, map[string]interface{}{"TicketID": ticketID}),
+		swapLog(t, common.BuyTicketFunc, map[string]interface{}{"Error": "Ticket already exist"}),
+		swapLog(t, common.SendAssetFunc, map[string]interface{}{"AssetID": assetID}),
+		indexed,
+		{Address: common.HexToAddress("0x05"), Topics: []common.Hash{{}}},
+	}
+	for i, l := range logs {
+		l.BlockNumber, l.TxHash = 7, common.HexToHash("0x06")
+		l.TxIndex = uint(i)
+	}
+
+	reorgs := make(chan []*FsnReorgEvent)
+	sub := es.SubscribeFsnReorgs(reorgs)
+	defer sub.Unsubscribe()
+
+	time.Sleep(100 * time.Millisecond)
+	backend.rmLogsFeed.Send(core.RemovedLogsEvent{Logs: logs})
+
+	var got []*FsnReorgEvent
+	select {
+	case got = <-reorgs:
+	case <-time.After(time.Second):
+		t.Fatal("no FSN reorg events received")
+	}
+	want := []struct {
+		object string
+		id     common.Hash
+	}{
+		{FsnObjectAsset, assetID},
+		{FsnObjectTicket, ticketID},
+		{FsnObjectSwap, swapID},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d FSN reorg events, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].Object != w.object || got[i].ObjectID != w.id || got[i].BlockNumber != 7 {
+			t.Errorf("event %d: got %s %x in block %d, want %s %x in block 7", i, got[i].Object, got[i].ObjectID, got[i].BlockNumber, w.object, w.id)
+		}
+	}
+	if got[0].Sender != nil {
+		t.Errorf("event 0: got sender %x for a not indexed log", *got[0].Sender)
+	}
+	if got[2].Sender == nil || *got[2].Sender != sender {
+		t.Errorf("event 2: got sender %v, want %x", got[2].Sender, sender)
+	}
+}
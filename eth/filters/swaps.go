@@ -0,0 +1,586 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/log"
+	"github.com/FusionFoundation/go-fusion/rpc"
+)
+
+// Swap event kinds
+const (
+	SwapEventCreated  = "created"  // the swap was made
+	SwapEventTaken    = "taken"    // the swap was partially taken
+	SwapEventFilled   = "filled"   // the swap was fully taken
+	SwapEventRecalled = "recalled" // the swap was recalled by its owner
+	SwapEventExpiring = "expiring" // the swap expires within the requested window
+)
+
+// maxTrackedSwaps bounds the number of open swaps the event system remembers
+// to follow their remaining size and expiry.
+const maxTrackedSwaps = 100000
+
+// SwapCriteria selects the swap events delivered to a subscription. Empty
+// fields match every swap, the asset IDs match any asset of a multi swap.
+type SwapCriteria struct {
+	FromAssetID *common.Hash    `json:"fromAssetID"`
+	ToAssetID   *common.Hash    `json:"toAssetID"`
+	Owner       *common.Address `json:"owner"`
+	Target      *common.Address `json:"target"` // only swaps targeting the address
+	Events      []string        `json:"events"`
+	// ExpiresWithin enables the expiring event, fired once per swap when it
+	// expires within the given number of seconds of the head block time.
+	ExpiresWithin uint64 `json:"expiresWithin"`
+}
+
+// SwapEvent is a notification about a swap.
+type SwapEvent struct {
+	Event        string           `json:"event"`
+	SwapID       common.Hash      `json:"swapID"`
+	Multi        bool             `json:"multi,omitempty"` // the swap is a multi swap
+	Owner        common.Address   `json:"owner"`
+	Taker        *common.Address  `json:"taker,omitempty"`
+	FromAssetID  common.Hash      `json:"fromAssetID"` // first asset of a multi swap
+	ToAssetID    common.Hash      `json:"toAssetID"`   // first asset of a multi swap
+	FromAssetIDs []common.Hash    `json:"fromAssetIDs,omitempty"`
+	ToAssetIDs   []common.Hash    `json:"toAssetIDs,omitempty"`
+	Targes       []common.Address `json:"targes"`
+	Size         *hexutil.Big     `json:"size,omitempty"` // made or taken size
+	Remaining    *hexutil.Big     `json:"remaining"`
+	ExpiresAt    hexutil.Uint64   `json:"expiresAt"`
+	BlockNumber  hexutil.Uint64   `json:"blockNumber"`
+	BlockHash    common.Hash      `json:"blockHash"`
+	TxHash       *common.Hash     `json:"transactionHash,omitempty"` // nil for expiring events
+	Removed      bool             `json:"removed,omitempty"`         // the event was reverted by a chain reorg
+}
+
+// trackedSwap is what the event system remembers of an open swap.
+type trackedSwap struct {
+	owner     common.Address
+	multi     bool
+	from, to  []common.Hash
+	targes    []common.Address
+	remaining *big.Int
+	expiresAt uint64
+	notified  map[rpc.ID]struct{} // subscriptions the expiring event was sent to
+}
+
+// swapLogData is the part of the MakeSwap, TakeSwap and RecallSwap logs
+// needed for the swap events.
+type swapLogData struct {
+	SwapID      common.Hash
+	FromAssetID common.Hash
+	FromEndTime uint64
+	ToAssetID   common.Hash
+	ToEndTime   uint64
+	SwapSize    *big.Int
+	Targes      []common.Address
+	Size        *big.Int
+	Deleted     bool
+	Error       string
+}
+
+// multiSwapLogData is the part of the multi swap logs needed for the swap
+// events.
+type multiSwapLogData struct {
+	SwapID      common.Hash
+	FromAssetID []common.Hash
+	FromEndTime []uint64
+	ToAssetID   []common.Hash
+	ToEndTime   []uint64
+	SwapSize    *big.Int
+	Targes      []common.Address
+	Size        *big.Int
+	Deleted     bool
+	Error       string
+}
+
+// parsedSwap is a successful swap call read from its log.
+type parsedSwap struct {
+	fn        common.FSNCallFunc
+	multi     bool
+	swapID    common.Hash
+	from, to  []common.Hash
+	expiresAt uint64
+	swapSize  *big.Int
+	targes    []common.Address
+	size      *big.Int
+	deleted   bool
+}
+
+// swapJob is a block, or the logs removed by a reorg, for the swap loop.
+type swapJob struct {
+	block   *types.Block
+	hash    common.Hash
+	logs    []*types.Log
+	removed bool
+	subs    []*subscription // the swap subscriptions when the job was queued
+}
+
+// swapStateBackend is implemented by backends giving access to the state,
+// it is used to follow swaps made before the event system was started.
+type swapStateBackend interface {
+	StateAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*state.StateDB, *types.Header, error)
+}
+
+// SubscribeSwaps creates a subscription that writes the swap events matching
+// the given criteria. Only swaps made while the event system runs, or taken
+// or recalled while it runs if the backend gives access to the state, are
+// followed for their expiry.
+func (es *EventSystem) SubscribeSwaps(crit SwapCriteria, swaps chan []*SwapEvent) *Subscription {
+	sub := &subscription{
+		id:        rpc.NewID(),
+		typ:       SwapsSubscription,
+		created:   time.Now(),
+		swapsCrit: crit,
+		logs:      make(chan []*types.Log),
+		hashes:    make(chan []common.Hash),
+		headers:   make(chan *types.Header),
+		swaps:     swaps,
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
+// queueSwaps hands a block or removed logs to the swap loop, nothing is done
+// without swap subscriptions.
+func (es *EventSystem) queueSwaps(filters filterIndex, job *swapJob) {
+	if es.lightMode || len(filters[SwapsSubscription]) == 0 {
+		return
+	}
+	for _, f := range filters[SwapsSubscription] {
+		job.subs = append(job.subs, f)
+	}
+	es.swapCh <- job
+}
+
+// swapLoop follows the swaps and sends the swap events out of the event loop,
+// as following a swap made before may need to load its state.
+func (es *EventSystem) swapLoop() {
+	for job := range es.swapCh {
+		if job.removed {
+			es.handleRemovedSwaps(job.subs, job.logs)
+			continue
+		}
+		number := job.block.NumberU64()
+		if es.swapHead != 0 && number != es.swapHead+1 {
+			// blocks were skipped without subscriptions or replaced by a
+			// reorg, the tracked swaps are reloaded from the state
+			es.swaps = make(map[common.Hash]*trackedSwap)
+		}
+		es.swapHead = number
+		es.handleSwaps(job.subs, job.block, job.hash, job.logs)
+	}
+}
+
+// sendSwaps sends the events to a subscription unless it is uninstalled.
+func sendSwaps(f *subscription, events []*SwapEvent) {
+	select {
+	case f.swaps <- events:
+	case <-f.err:
+	}
+}
+
+// parseSwapLog returns the swap call of a log, nil if it is not a successful
+// swap call.
+func parseSwapLog(l *types.Log) *parsedSwap {
+	if l.Address != common.FSNCallAddress || len(l.Topics) == 0 {
+		return nil
+	}
+	fn, ok := common.FsnCallFuncOfTopic(l.Topics[0])
+	if !ok {
+		return nil
+	}
+	switch fn {
+	case common.MakeSwapFunc, common.TakeSwapFunc, common.RecallSwapFunc:
+		var data swapLogData
+		if err := json.Unmarshal(l.Data, &data); err != nil || data.Error != "" {
+			return nil
+		}
+		return &parsedSwap{
+			fn:        fn,
+			swapID:    data.SwapID,
+			from:      []common.Hash{data.FromAssetID},
+			to:        []common.Hash{data.ToAssetID},
+			expiresAt: swapExpiry([]uint64{data.FromEndTime}, []uint64{data.ToEndTime}),
+			swapSize:  data.SwapSize,
+			targes:    data.Targes,
+			size:      data.Size,
+			deleted:   data.Deleted,
+		}
+	case common.MakeMultiSwapFunc, common.TakeMultiSwapFunc, common.RecallMultiSwapFunc:
+		var data multiSwapLogData
+		if err := json.Unmarshal(l.Data, &data); err != nil || data.Error != "" {
+			return nil
+		}
+		return &parsedSwap{
+			fn:        fn,
+			multi:     true,
+			swapID:    data.SwapID,
+			from:      data.FromAssetID,
+			to:        data.ToAssetID,
+			expiresAt: swapExpiry(data.FromEndTime, data.ToEndTime),
+			swapSize:  data.SwapSize,
+			targes:    data.Targes,
+			size:      data.Size,
+			deleted:   data.Deleted,
+		}
+	}
+	return nil
+}
+
+// handleSwaps updates the tracked swaps with the swap logs of a new block and
+// sends the matching events to the swap subscriptions.
+func (es *EventSystem) handleSwaps(subs []*subscription, block *types.Block, hash common.Hash, logs []*types.Log) {
+	var (
+		header = block.Header()
+		number = header.Number.Uint64()
+		events []*SwapEvent
+	)
+	for _, l := range logs {
+		sl := parseSwapLog(l)
+		if sl == nil {
+			continue
+		}
+		txHash := l.TxHash
+		if event := es.applySwapLog(block, l.TxIndex, sl); event != nil {
+			event.BlockNumber, event.BlockHash, event.TxHash = hexutil.Uint64(number), hash, &txHash
+			events = append(events, event)
+		}
+	}
+
+	var expiring []*SwapEvent
+	for id, swap := range es.swaps {
+		if swap.expiresAt <= header.Time {
+			delete(es.swaps, id)
+			continue
+		}
+		for _, f := range subs {
+			if _, sent := swap.notified[f.id]; sent || f.swapsCrit.ExpiresWithin == 0 {
+				continue
+			}
+			if swap.expiresAt-header.Time <= f.swapsCrit.ExpiresWithin {
+				event := swap.event(SwapEventExpiring, id)
+				event.BlockNumber, event.BlockHash = hexutil.Uint64(number), hash
+				expiring = append(expiring, event)
+				break
+			}
+		}
+	}
+
+	for _, f := range subs {
+		var matched []*SwapEvent
+		for _, event := range events {
+			if f.swapsCrit.matches(event) {
+				matched = append(matched, event)
+			}
+		}
+		for _, event := range expiring {
+			swap := es.swaps[event.SwapID]
+			if _, sent := swap.notified[f.id]; sent || f.swapsCrit.ExpiresWithin == 0 {
+				continue
+			}
+			if uint64(event.ExpiresAt)-header.Time <= f.swapsCrit.ExpiresWithin && f.swapsCrit.matches(event) {
+				swap.notified[f.id] = struct{}{}
+				matched = append(matched, event)
+			}
+		}
+		if len(matched) > 0 {
+			sendSwaps(f, matched)
+		}
+	}
+
+	// Filled and recalled swaps are only kept until their events are sent
+	for _, event := range events {
+		if event.Event == SwapEventFilled || event.Event == SwapEventRecalled {
+			delete(es.swaps, event.SwapID)
+		}
+	}
+}
+
+// handleRemovedSwaps sends the events of the swap calls reverted by a reorg,
+// marked as removed, and forgets the swaps they changed so that they are
+// reloaded from the state of the new chain.
+func (es *EventSystem) handleRemovedSwaps(subs []*subscription, logs []*types.Log) {
+	var events []*SwapEvent
+	for _, l := range logs {
+		sl := parseSwapLog(l)
+		if sl == nil {
+			continue
+		}
+		swap := es.swaps[sl.swapID]
+		if swap == nil && (sl.fn == common.MakeSwapFunc || sl.fn == common.MakeMultiSwapFunc) {
+			swap = newTrackedSwap(sl)
+		}
+		if swap == nil {
+			continue
+		}
+		delete(es.swaps, sl.swapID)
+		event := swap.event(swapEventKind(sl, swap), sl.swapID)
+		if sl.size != nil {
+			event.Size = (*hexutil.Big)(sl.size)
+		}
+		txHash := l.TxHash
+		event.BlockNumber, event.BlockHash, event.TxHash, event.Removed = hexutil.Uint64(l.BlockNumber), l.BlockHash, &txHash, true
+		events = append(events, event)
+	}
+	if len(events) == 0 {
+		return
+	}
+	for _, f := range subs {
+		var matched []*SwapEvent
+		for _, event := range events {
+			if f.swapsCrit.matches(event) {
+				matched = append(matched, event)
+			}
+		}
+		if len(matched) > 0 {
+			sendSwaps(f, matched)
+		}
+	}
+}
+
+// swapEventKind returns the kind of event of a swap call.
+func swapEventKind(sl *parsedSwap, swap *trackedSwap) string {
+	switch sl.fn {
+	case common.MakeSwapFunc, common.MakeMultiSwapFunc:
+		return SwapEventCreated
+	case common.TakeSwapFunc, common.TakeMultiSwapFunc:
+		if sl.deleted || swap.remaining.Sign() <= 0 {
+			return SwapEventFilled
+		}
+		return SwapEventTaken
+	default:
+		return SwapEventRecalled
+	}
+}
+
+// newTrackedSwap returns the swap made by a make swap call.
+func newTrackedSwap(sl *parsedSwap) *trackedSwap {
+	return &trackedSwap{
+		multi:     sl.multi,
+		from:      sl.from,
+		to:        sl.to,
+		targes:    sl.targes,
+		remaining: sl.swapSize,
+		expiresAt: sl.expiresAt,
+		notified:  make(map[rpc.ID]struct{}),
+	}
+}
+
+// applySwapLog updates the tracked swap of a swap log, returning the event
+// describing the change or nil if the swap is unknown.
+func (es *EventSystem) applySwapLog(block *types.Block, txIndex uint, sl *parsedSwap) *SwapEvent {
+	if sl.fn == common.MakeSwapFunc || sl.fn == common.MakeMultiSwapFunc {
+		if sl.swapSize == nil || int(txIndex) >= len(block.Transactions()) {
+			return nil
+		}
+		owner, err := txSender(block.Transactions()[txIndex])
+		if err != nil {
+			return nil
+		}
+		swap := newTrackedSwap(sl)
+		swap.owner = owner
+		es.trackSwap(sl.swapID, swap)
+		event := swap.event(SwapEventCreated, sl.swapID)
+		event.Size = (*hexutil.Big)(sl.swapSize)
+		return event
+	}
+
+	swap := es.swaps[sl.swapID]
+	if swap == nil {
+		if swap = es.loadSwap(block, sl.swapID, sl.multi); swap == nil {
+			return nil
+		}
+	}
+	switch sl.fn {
+	case common.TakeSwapFunc, common.TakeMultiSwapFunc:
+		if sl.size == nil || int(txIndex) >= len(block.Transactions()) {
+			return nil
+		}
+		taker, err := txSender(block.Transactions()[txIndex])
+		if err != nil {
+			return nil
+		}
+		swap.remaining = new(big.Int).Sub(swap.remaining, sl.size)
+		event := swap.event(swapEventKind(sl, swap), sl.swapID)
+		event.Taker, event.Size = &taker, (*hexutil.Big)(sl.size)
+		return event
+	default:
+		return swap.event(SwapEventRecalled, sl.swapID)
+	}
+}
+
+// trackSwap starts following a swap if the number of tracked swaps allows.
+func (es *EventSystem) trackSwap(swapID common.Hash, swap *trackedSwap) {
+	if len(es.swaps) >= maxTrackedSwaps {
+		log.Debug("Too many tracked swaps, ignoring swap", "id", swapID)
+		return
+	}
+	es.swaps[swapID] = swap
+}
+
+// loadSwap reads a swap made before it was tracked from the state of the
+// parent of the block changing it.
+func (es *EventSystem) loadSwap(block *types.Block, swapID common.Hash, multi bool) *trackedSwap {
+	backend, ok := es.backend.(swapStateBackend)
+	if !ok || block.NumberU64() == 0 {
+		return nil
+	}
+	statedb, _, err := backend.StateAndHeaderByNumber(context.Background(), rpc.BlockNumber(block.NumberU64()-1))
+	if err != nil || statedb == nil {
+		return nil
+	}
+	var swap *trackedSwap
+	if multi {
+		s, err := statedb.GetMultiSwap(swapID)
+		if err != nil || s.SwapSize == nil {
+			return nil
+		}
+		swap = &trackedSwap{
+			owner:     s.Owner,
+			multi:     true,
+			from:      s.FromAssetID,
+			to:        s.ToAssetID,
+			targes:    s.Targes,
+			remaining: s.SwapSize,
+			expiresAt: swapExpiry(s.FromEndTime, s.ToEndTime),
+			notified:  make(map[rpc.ID]struct{}),
+		}
+	} else {
+		s, err := statedb.GetSwap(swapID)
+		if err != nil || s.SwapSize == nil {
+			return nil
+		}
+		swap = &trackedSwap{
+			owner:     s.Owner,
+			from:      []common.Hash{s.FromAssetID},
+			to:        []common.Hash{s.ToAssetID},
+			targes:    s.Targes,
+			remaining: s.SwapSize,
+			expiresAt: swapExpiry([]uint64{s.FromEndTime}, []uint64{s.ToEndTime}),
+			notified:  make(map[rpc.ID]struct{}),
+		}
+	}
+	es.trackSwap(swapID, swap)
+	return swap
+}
+
+// event returns an event about the swap, without block information.
+func (swap *trackedSwap) event(kind string, swapID common.Hash) *SwapEvent {
+	event := &SwapEvent{
+		Event:     kind,
+		SwapID:    swapID,
+		Multi:     swap.multi,
+		Owner:     swap.owner,
+		Targes:    swap.targes,
+		Remaining: (*hexutil.Big)(new(big.Int).Set(swap.remaining)),
+		ExpiresAt: hexutil.Uint64(swap.expiresAt),
+	}
+	if len(swap.from) > 0 {
+		event.FromAssetID = swap.from[0]
+	}
+	if len(swap.to) > 0 {
+		event.ToAssetID = swap.to[0]
+	}
+	if swap.multi {
+		event.FromAssetIDs, event.ToAssetIDs = swap.from, swap.to
+	}
+	return event
+}
+
+// matches reports whether the event satisfies the criteria.
+func (crit *SwapCriteria) matches(event *SwapEvent) bool {
+	from, to := []common.Hash{event.FromAssetID}, []common.Hash{event.ToAssetID}
+	if event.Multi {
+		from, to = event.FromAssetIDs, event.ToAssetIDs
+	}
+	if crit.FromAssetID != nil && !containsHash(from, *crit.FromAssetID) {
+		return false
+	}
+	if crit.ToAssetID != nil && !containsHash(to, *crit.ToAssetID) {
+		return false
+	}
+	if crit.Owner != nil && *crit.Owner != event.Owner {
+		return false
+	}
+	if crit.Target != nil && !containsAddress(event.Targes, *crit.Target) {
+		return false
+	}
+	if len(crit.Events) > 0 && !containsString(crit.Events, event.Event) {
+		return false
+	}
+	return true
+}
+
+// swapExpiry returns the time a swap with the given end times expires, the
+// earliest of them.
+func swapExpiry(fromEndTimes, toEndTimes []uint64) uint64 {
+	expiry := uint64(math.MaxUint64)
+	for _, end := range append(append([]uint64{}, fromEndTimes...), toEndTimes...) {
+		if end < expiry {
+			expiry = end
+		}
+	}
+	return expiry
+}
+
+// txSender returns the sender of a transaction of the chain.
+func txSender(tx *types.Transaction) (common.Address, error) {
+	if tx.Protected() {
+		return types.Sender(types.NewEIP155Signer(tx.ChainId()), tx)
+	}
+	return types.Sender(types.HomesteadSigner{}, tx)
+}
+
+func containsAddress(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+func containsHash(hashes []common.Hash, hash common.Hash) bool {
+	for _, h := range hashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/crypto"
+	"github.com/FusionFoundation/go-fusion/rpc"
+)
+
+func swapLog(t *testing.T, fn common.FSNCallFunc, fields map[string]interface{}) *types.Log {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic := common.Hash{}
+	topic[common.HashLength-1] = uint8(fn)
+	return &types.Log{Address: common.FSNCallAddress, Topics: []common.Hash{topic}, Data: data}
+}
+
+// TestSwapSubscription tests the swap events of a swap being made, taken,
+// about to expire and filled.
+func TestSwapSubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		backend = &testBackend{db: db}
+		api     = NewPublicFilterAPI(backend, false)
+
+		ownerKey, _ = crypto.GenerateKey()
+		takerKey, _ = crypto.GenerateKey()
+		owner       = crypto.PubkeyToAddress(ownerKey.PublicKey)
+		taker       = crypto.PubkeyToAddress(takerKey.PublicKey)
+		signer      = types.NewEIP155Signer(big.NewInt(1))
+
+		swapID  = common.HexToHash("0x01")
+		fromID  = common.HexToHash("0x02")
+		toID    = common.HexToHash("0x03")
+		otherID = common.HexToHash("0x04")
+	)
+
+	newEvent := func(number, time uint64, key interface{}, l *types.Log) core.ChainEvent {
+		tx := types.NewTransaction(number, common.FSNCallAddress, nil, 0, nil, nil)
+		if key == takerKey {
+			tx, _ = types.SignTx(tx, signer, takerKey)
+		} else {
+			tx, _ = types.SignTx(tx, signer, ownerKey)
+		}
+		header := &types.Header{Number: new(big.Int).SetUint64(number), Time: time}
+		block := types.NewBlock(header, []*types.Transaction{tx}, nil, nil)
+		var logs []*types.Log
+		if l != nil {
+			l.TxHash, l.BlockNumber = tx.Hash(), number
+			logs = append(logs, l)
+		}
+		return core.ChainEvent{Block: block, Hash: block.Hash(), Logs: logs}
+	}
+
+	pairID, err := api.NewSwapFilter(SwapCriteria{FromAssetID: &fromID, ToAssetID: &toID, ExpiresWithin: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherID2, err := api.NewSwapFilter(SwapCriteria{FromAssetID: &otherID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetID, err := api.NewSwapFilter(SwapCriteria{Target: &taker, Events: []string{SwapEventCreated}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := []core.ChainEvent{
+		newEvent(1, 100, ownerKey, swapLog(t, common.MakeSwapFunc, map[string]interface{}{
+			"SwapID": swapID, "FromAssetID": fromID, "ToAssetID": toID,
+			"FromEndTime": 1000, "ToEndTime": 500, "SwapSize": big.NewInt(10), "Targes": []common.Address{taker},
+		})),
+		newEvent(2, 200, takerKey, swapLog(t, common.TakeSwapFunc, map[string]interface{}{
+			"SwapID": swapID, "Size": big.NewInt(4), "Deleted": false,
+		})),
+		newEvent(3, 450, ownerKey, nil),
+		newEvent(4, 460, ownerKey, nil),
+		newEvent(5, 470, takerKey, swapLog(t, common.TakeSwapFunc, map[string]interface{}{
+			"SwapID": swapID, "Size": big.NewInt(6), "Deleted": true,
+		})),
+	}
+	time.Sleep(100 * time.Millisecond)
+	for _, ev := range events {
+		backend.chainFeed.Send(ev)
+	}
+
+	var got []*SwapEvent
+	for timeout := time.Now().Add(time.Second); len(got) < 4 && time.Now().Before(timeout); time.Sleep(10 * time.Millisecond) {
+		changes, err := api.GetFilterChanges(pairID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, changes.([]*SwapEvent)...)
+	}
+	want := []struct {
+		event     string
+		remaining int64
+		number    uint64
+	}{
+		{SwapEventCreated, 10, 1},
+		{SwapEventTaken, 6, 2},
+		{SwapEventExpiring, 6, 3},
+		{SwapEventFilled, 0, 5},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d swap events, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		e := got[i]
+		if e.Event != w.event || e.Remaining.ToInt().Int64() != w.remaining || uint64(e.BlockNumber) != w.number {
+			t.Errorf("event %d: got %s remaining %d in block %d, want %s remaining %d in block %d",
+				i, e.Event, e.Remaining.ToInt(), e.BlockNumber, w.event, w.remaining, w.number)
+		}
+		if e.SwapID != swapID || e.Owner != owner || uint64(e.ExpiresAt) != 500 {
+			t.Errorf("event %d: unexpected swap %x owner %x expiry %d", i, e.SwapID, e.Owner, e.ExpiresAt)
+		}
+	}
+	if got[1].Taker == nil || *got[1].Taker != taker {
+		t.Errorf("taken event has taker %v, want %x", got[1].Taker, taker)
+	}
+
+	changes, _ := api.GetFilterChanges(otherID2)
+	if n := len(changes.([]*SwapEvent)); n != 0 {
+		t.Errorf("other pair filter got %d events, want none", n)
+	}
+	changes, _ = api.GetFilterChanges(targetID)
+	if targeted := changes.([]*SwapEvent); len(targeted) != 1 || targeted[0].Event != SwapEventCreated {
+		t.Errorf("target filter got %v, want the created event", targeted)
+	}
+}
+
+// pollSwaps collects the swap events of a filter until n are received or a
+// second has passed.
+func pollSwaps(t *testing.T, api *PublicFilterAPI, id rpc.ID, n int) []*SwapEvent {
+	var got []*SwapEvent
+	for timeout := time.Now().Add(time.Second); len(got) < n && time.Now().Before(timeout); time.Sleep(10 * time.Millisecond) {
+		changes, err := api.GetFilterChanges(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, changes.([]*SwapEvent)...)
+	}
+	return got
+}
+
+// TestMultiSwapSubscription tests that multi swaps are followed and matched
+// on any of their assets, and that a reorg reports the reverted calls.
+func TestMultiSwapSubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		backend = &testBackend{db: db}
+		api     = NewPublicFilterAPI(backend, false)
+
+		ownerKey, _ = crypto.GenerateKey()
+		owner       = crypto.PubkeyToAddress(ownerKey.PublicKey)
+		signer      = types.NewEIP155Signer(big.NewInt(1))
+
+		swapID = common.HexToHash("0x01")
+		fromA  = common.HexToHash("0x02")
+		fromB  = common.HexToHash("0x03")
+		toID   = common.HexToHash("0x04")
+	)
+
+	tx, _ := types.SignTx(types.NewTransaction(0, common.FSNCallAddress, nil, 0, nil, nil), signer, ownerKey)
+	newEvent := func(number uint64, l *types.Log) core.ChainEvent {
+		header := &types.Header{Number: new(big.Int).SetUint64(number), Time: number * 10}
+		block := types.NewBlock(header, []*types.Transaction{tx}, nil, nil)
+		l.TxHash, l.BlockNumber, l.BlockHash = tx.Hash(), number, block.Hash()
+		return core.ChainEvent{Block: block, Hash: block.Hash(), Logs: []*types.Log{l}}
+	}
+
+	id, err := api.NewSwapFilter(SwapCriteria{FromAssetID: &fromB})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	made := newEvent(1, swapLog(t, common.MakeMultiSwapFunc, map[string]interface{}{
+		"SwapID": swapID, "FromAssetID": []common.Hash{fromA, fromB}, "FromEndTime": []uint64{1000, 800},
+		"ToAssetID": []common.Hash{toID}, "ToEndTime": []uint64{900}, "SwapSize": big.NewInt(10),
+	}))
+	taken := newEvent(2, swapLog(t, common.TakeMultiSwapFunc, map[string]interface{}{
+		"SwapID": swapID, "Size": big.NewInt(3), "Deleted": false,
+	}))
+	backend.chainFeed.Send(made)
+	backend.chainFeed.Send(taken)
+
+	got := pollSwaps(t, api, id, 2)
+	if len(got) != 2 {
+		t.Fatalf("got %d swap events, want 2", len(got))
+	}
+	if e := got[0]; e.Event != SwapEventCreated || !e.Multi || e.Owner != owner || uint64(e.ExpiresAt) != 800 ||
+		len(e.FromAssetIDs) != 2 || e.FromAssetID != fromA {
+		t.Errorf("unexpected created event %+v", e)
+	}
+	if e := got[1]; e.Event != SwapEventTaken || e.Remaining.ToInt().Int64() != 7 || e.Removed {
+		t.Errorf("unexpected taken event %+v", e)
+	}
+
+	// Revert the take, the event is sent again marked as removed
+	backend.rmLogsFeed.Send(core.RemovedLogsEvent{Logs: taken.Logs})
+	got = pollSwaps(t, api, id, 1)
+	if len(got) != 1 {
+		t.Fatalf("got %d removed swap events, want 1", len(got))
+	}
+	if e := got[0]; e.Event != SwapEventTaken || !e.Removed || uint64(e.BlockNumber) != 2 || *e.TxHash != tx.Hash() {
+		t.Errorf("unexpected removed event %+v", e)
+	}
+}
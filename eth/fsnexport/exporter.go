@@ -0,0 +1,308 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package fsnexport streams the fusion specific chain events to external
+// message brokers.
+package fsnexport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/ethdb"
+	"github.com/FusionFoundation/go-fusion/log"
+	"github.com/FusionFoundation/go-fusion/params"
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+const (
+	// chainHeadChanSize is the size of channel listening to ChainHeadEvent.
+	chainHeadChanSize = 10
+
+	// retryInterval is the time to wait before publishing a block again after
+	// the broker failed to acknowledge it.
+	retryInterval = 5 * time.Second
+)
+
+// Event types, each is published to its own topic.
+const (
+	EventFSNCall = "fsncall" // every FSNCall log, failed calls included
+	EventTicket  = "ticket"  // successfully bought tickets
+	EventSwap    = "swap"    // successful swap makes, takes and recalls
+)
+
+var (
+	exportHeadKey = []byte("fsnexport-head") // exportHeadKey -> rlp(exportHead)
+
+	errExporterStopped = errors.New("exporter stopped")
+)
+
+// Config are the settings of the event exporter.
+type Config struct {
+	URL       string // broker url, kafka://host:port[,host:port...] or nats://host:port
+	Topic     string // topic prefix, events are published to Topic + "." + type
+	FromBlock uint64 // first block to publish, 0 resumes after the last published block
+}
+
+// DefaultConfig contains the default export settings.
+var DefaultConfig = Config{
+	Topic: "fsn",
+}
+
+// Event is the JSON encoded message published for an FSNCall log.
+type Event struct {
+	Type        string          `json:"type"`
+	Func        string          `json:"func"`
+	Removed     bool            `json:"removed"` // set if the block was reverted by a reorg
+	BlockNumber uint64          `json:"blockNumber"`
+	BlockHash   common.Hash     `json:"blockHash"`
+	Timestamp   uint64          `json:"timestamp"`
+	TxHash      common.Hash     `json:"transactionHash"`
+	TxIndex     uint            `json:"transactionIndex"`
+	LogIndex    uint            `json:"logIndex"`
+	From        common.Address  `json:"from"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// exportHead is the last block whose events have been acknowledged.
+type exportHead struct {
+	Number uint64
+	Hash   common.Hash
+}
+
+// Exporter publishes the FSNCall events, the bought tickets and the swap
+// changes of every canonical block in order. Delivery is at-least-once: the
+// last acknowledged block is persisted and publishing resumes after it when
+// the node restarts, so consumers must deduplicate by block hash and log
+// index. The events of reverted blocks are published again flagged as
+// removed before the events of the new canonical blocks.
+type Exporter struct {
+	config    Config
+	publisher Publisher
+	chainDb   ethdb.Database
+	chain     *core.BlockChain
+
+	head exportHead
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates an exporter connected to the broker of the configuration.
+func New(config Config, chainDb ethdb.Database, chain *core.BlockChain) (*Exporter, error) {
+	publisher, err := NewPublisher(config.URL)
+	if err != nil {
+		return nil, err
+	}
+	e := &Exporter{
+		config:    config,
+		publisher: publisher,
+		chainDb:   chainDb,
+		chain:     chain,
+		quit:      make(chan struct{}),
+	}
+	if blob, err := chainDb.Get(exportHeadKey); err == nil {
+		if err := rlp.DecodeBytes(blob, &e.head); err != nil {
+			log.Error("Invalid event export head", "err", err)
+		}
+	}
+	if config.FromBlock > 0 {
+		number := config.FromBlock - 1
+		e.head = exportHead{Number: number, Hash: rawdb.ReadCanonicalHash(chainDb, number)}
+	}
+	return e, nil
+}
+
+// Start starts publishing in the background.
+func (e *Exporter) Start() {
+	e.wg.Add(1)
+	go e.loop()
+}
+
+// Stop terminates the exporter, waiting for the block being published.
+func (e *Exporter) Stop() {
+	close(e.quit)
+	e.wg.Wait()
+	e.publisher.Close()
+}
+
+func (e *Exporter) loop() {
+	defer e.wg.Done()
+
+	headCh := make(chan core.ChainHeadEvent, chainHeadChanSize)
+	sub := e.chain.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	log.Info("Exporting FSN events", "url", e.config.URL, "from", e.head.Number+1)
+	for {
+		if err := e.update(e.chain.CurrentBlock()); err == errExporterStopped {
+			return
+		}
+		select {
+		case <-headCh:
+		case <-sub.Err():
+			return
+		case <-e.quit:
+			return
+		}
+	}
+}
+
+// update publishes the events up to the given block, first reverting the
+// published blocks which are not canonical any more.
+func (e *Exporter) update(head *types.Block) error {
+	for e.head.Hash != (common.Hash{}) && rawdb.ReadCanonicalHash(e.chainDb, e.head.Number) != e.head.Hash {
+		block := rawdb.ReadBlock(e.chainDb, e.head.Hash, e.head.Number)
+		if block == nil {
+			return e.fail(fmt.Errorf("reverted block #%d [%x…] not found", e.head.Number, e.head.Hash[:4]))
+		}
+		if err := e.publish(block, true); err != nil {
+			return err
+		}
+		e.setHead(exportHead{Number: block.NumberU64() - 1, Hash: block.ParentHash()})
+	}
+	for number := e.head.Number + 1; number <= head.NumberU64(); number++ {
+		block := e.chain.GetBlockByNumber(number)
+		if block == nil {
+			return e.fail(fmt.Errorf("block #%d not found", number))
+		}
+		if err := e.publish(block, false); err != nil {
+			return err
+		}
+		e.setHead(exportHead{Number: number, Hash: block.Hash()})
+	}
+	return nil
+}
+
+// publish delivers the events of a block, retrying until the broker
+// acknowledged them or the exporter is stopped.
+func (e *Exporter) publish(block *types.Block, removed bool) error {
+	if len(block.Transactions()) == 0 {
+		return nil
+	}
+	receipts := rawdb.ReadRawReceipts(e.chainDb, block.Hash(), block.NumberU64())
+	if receipts == nil {
+		return e.fail(fmt.Errorf("receipts of block #%d [%x…] not found", block.NumberU64(), block.Hash().Bytes()[:4]))
+	}
+	msgs, err := blockMessages(e.chain.Config(), e.config.Topic, block, receipts, removed)
+	if err != nil {
+		return e.fail(err)
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	for {
+		err := e.publisher.Publish(msgs)
+		if err == nil {
+			return nil
+		}
+		log.Warn("Failed to publish FSN events", "number", block.NumberU64(), "hash", block.Hash(), "err", err)
+		select {
+		case <-time.After(retryInterval):
+		case <-e.quit:
+			return errExporterStopped
+		}
+	}
+}
+
+// fail logs an error which cannot be solved by retrying, the export stalls
+// until the next chain head event.
+func (e *Exporter) fail(err error) error {
+	log.Error("Failed to export FSN events", "err", err)
+	return err
+}
+
+func (e *Exporter) setHead(head exportHead) {
+	e.head = head
+	enc, _ := rlp.EncodeToBytes(&head)
+	if err := e.chainDb.Put(exportHeadKey, enc); err != nil {
+		log.Crit("Failed to store event export head", "err", err)
+	}
+}
+
+// blockMessages converts the FSNCall logs of a block into the messages of
+// their event topics.
+func blockMessages(config *params.ChainConfig, topic string, block *types.Block, receipts types.Receipts, removed bool) ([]*Message, error) {
+	if err := receipts.DeriveFields(config, block.Hash(), block.NumberU64(), block.Transactions()); err != nil {
+		return nil, err
+	}
+	signer := types.MakeSigner(config, block.Number())
+
+	var msgs []*Message
+	for _, receipt := range receipts {
+		for _, l := range receipt.Logs {
+			if l.Address != common.FSNCallAddress || len(l.Topics) == 0 {
+				continue
+			}
+			fn, ok := common.FsnCallFuncOfTopic(l.Topics[0])
+			if !ok {
+				continue
+			}
+			from, err := types.Sender(signer, block.Transactions()[l.TxIndex])
+			if err != nil {
+				return nil, err
+			}
+			event := &Event{
+				Type:        EventFSNCall,
+				Func:        fn.Name(),
+				Removed:     removed,
+				BlockNumber: l.BlockNumber,
+				BlockHash:   l.BlockHash,
+				Timestamp:   block.Time(),
+				TxHash:      l.TxHash,
+				TxIndex:     l.TxIndex,
+				LogIndex:    l.Index,
+				From:        from,
+				Data:        json.RawMessage(l.Data),
+			}
+			kinds := []string{EventFSNCall}
+			if !failedCall(l.Data) {
+				switch fn {
+				case common.BuyTicketFunc:
+					kinds = append(kinds, EventTicket)
+				case common.MakeSwapFunc, common.RecallSwapFunc, common.TakeSwapFunc,
+					common.MakeMultiSwapFunc, common.RecallMultiSwapFunc, common.TakeMultiSwapFunc:
+					kinds = append(kinds, EventSwap)
+				}
+			}
+			for _, typ := range kinds {
+				event.Type = typ
+				value, err := json.Marshal(event)
+				if err != nil {
+					return nil, err
+				}
+				msgs = append(msgs, &Message{Topic: topic + "." + typ, Key: from.Bytes(), Value: value})
+			}
+		}
+	}
+	return msgs, nil
+}
+
+// failedCall reports whether the log data records an error.
+func failedCall(data []byte) bool {
+	var result struct{ Error string }
+	if err := json.Unmarshal(data, &result); err != nil {
+		return true
+	}
+	return result.Error != ""
+}
@@ -0,0 +1,82 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnexport
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/crypto"
+	"github.com/FusionFoundation/go-fusion/params"
+)
+
+func TestBlockMessages(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	signer := types.MakeSigner(params.TestChainConfig, big.NewInt(1))
+	tx, err := types.SignTx(types.NewTransaction(0, common.FSNCallAddress, new(big.Int), 100000, new(big.Int), nil), signer, key)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	fsnLog := func(fn common.FSNCallFunc, data string) *types.Log {
+		topic := common.Hash{}
+		topic[common.HashLength-1] = uint8(fn)
+		return &types.Log{Address: common.FSNCallAddress, Topics: []common.Hash{topic}, Data: []byte(data)}
+	}
+	receipt := types.NewReceipt(nil, false, 0)
+	receipt.Logs = []*types.Log{
+		fsnLog(common.BuyTicketFunc, `{"TicketID":"0x01"}`),
+		fsnLog(common.MakeSwapFunc, `{"Error":"not enough from asset"}`),
+		fsnLog(common.TakeSwapFunc, `{"SwapID":"0x5a","Size":2}`),
+		{Address: common.HexToAddress("0x10"), Topics: []common.Hash{{}}},
+	}
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1), Time: 10}, []*types.Transaction{tx}, nil, []*types.Receipt{receipt})
+
+	msgs, err := blockMessages(params.TestChainConfig, "fsn", block, types.Receipts{receipt}, true)
+	if err != nil {
+		t.Fatalf("failed to convert block: %v", err)
+	}
+	want := []struct {
+		topic, fn string
+		index     uint
+	}{
+		{"fsn.fsncall", "BuyTicketFunc", 0},
+		{"fsn.ticket", "BuyTicketFunc", 0},
+		{"fsn.fsncall", "MakeSwapFunc", 1},
+		{"fsn.fsncall", "TakeSwapFunc", 2},
+		{"fsn.swap", "TakeSwapFunc", 2},
+	}
+	if len(msgs) != len(want) {
+		t.Fatalf("message count mismatch: have %d, want %d", len(msgs), len(want))
+	}
+	for i, msg := range msgs {
+		var event Event
+		if err := json.Unmarshal(msg.Value, &event); err != nil {
+			t.Fatalf("message %d: invalid event: %v", i, err)
+		}
+		if msg.Topic != want[i].topic || event.Func != want[i].fn || event.LogIndex != want[i].index {
+			t.Errorf("message %d: have %s %s #%d, want %s %s #%d", i, msg.Topic, event.Func, event.LogIndex, want[i].topic, want[i].fn, want[i].index)
+		}
+		if event.From != from || !event.Removed || event.BlockHash != block.Hash() || event.TxHash != tx.Hash() {
+			t.Errorf("message %d: event fields mismatch: %+v", i, event)
+		}
+	}
+}
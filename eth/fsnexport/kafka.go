@@ -0,0 +1,69 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnexport
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+func init() {
+	RegisterPublisher("kafka", newKafkaPublisher)
+}
+
+// kafkaPublisher publishes to a kafka cluster, waiting for all in-sync
+// replicas to acknowledge every message.
+type kafkaPublisher struct {
+	producer sarama.SyncProducer
+}
+
+// newKafkaPublisher connects to the comma separated brokers of a url like
+// kafka://broker1:9092,broker2:9092.
+func newKafkaPublisher(u *url.URL) (Publisher, error) {
+	config := sarama.NewConfig()
+	config.ClientID = "efsn"
+	config.Version = sarama.V1_0_0_0
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Return.Successes = true
+	config.Producer.Retry.Max = 10
+	config.Producer.Retry.Backoff = 500 * time.Millisecond
+
+	producer, err := sarama.NewSyncProducer(strings.Split(u.Host, ","), config)
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaPublisher{producer: producer}, nil
+}
+
+func (p *kafkaPublisher) Publish(msgs []*Message) error {
+	batch := make([]*sarama.ProducerMessage, len(msgs))
+	for i, msg := range msgs {
+		batch[i] = &sarama.ProducerMessage{
+			Topic: msg.Topic,
+			Key:   sarama.ByteEncoder(msg.Key),
+			Value: sarama.ByteEncoder(msg.Value),
+		}
+	}
+	return p.producer.SendMessages(batch)
+}
+
+func (p *kafkaPublisher) Close() error {
+	return p.producer.Close()
+}
@@ -0,0 +1,61 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnexport
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// natsFlushTimeout is the time to wait for the server to acknowledge a batch
+// of published messages.
+const natsFlushTimeout = 10 * time.Second
+
+func init() {
+	RegisterPublisher("nats", newNATSPublisher)
+}
+
+// natsPublisher publishes to a NATS server. A batch is acknowledged once the
+// server answered the ping following it.
+type natsPublisher struct {
+	conn *nats.Conn
+}
+
+// newNATSPublisher connects to the server of a url like nats://host:4222.
+func newNATSPublisher(u *url.URL) (Publisher, error) {
+	conn, err := nats.Connect(u.String(), nats.Name("efsn"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+	return &natsPublisher{conn: conn}, nil
+}
+
+func (p *natsPublisher) Publish(msgs []*Message) error {
+	for _, msg := range msgs {
+		if err := p.conn.Publish(msg.Topic, msg.Value); err != nil {
+			return err
+		}
+	}
+	return p.conn.FlushTimeout(natsFlushTimeout)
+}
+
+func (p *natsPublisher) Close() error {
+	p.conn.Close()
+	return nil
+}
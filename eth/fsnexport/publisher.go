@@ -0,0 +1,76 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnexport
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+// Message is one record to be delivered to a topic of the message broker.
+type Message struct {
+	Topic string
+	Key   []byte // partitioning key, ignored by brokers without partitions
+	Value []byte
+}
+
+// Publisher delivers messages to a message broker.
+type Publisher interface {
+	// Publish delivers the messages in order. It must only return nil once
+	// the broker acknowledged all of them, a failed call is retried with
+	// the same messages.
+	Publish(msgs []*Message) error
+
+	// Close releases the connection to the broker.
+	Close() error
+}
+
+// PublisherFunc creates a publisher connected to the broker of the url.
+type PublisherFunc func(u *url.URL) (Publisher, error)
+
+var (
+	publishersMu sync.RWMutex
+	publishers   = make(map[string]PublisherFunc)
+)
+
+// RegisterPublisher makes a publisher available for the urls of the given
+// scheme. It panics if the scheme is already registered.
+func RegisterPublisher(scheme string, fn PublisherFunc) {
+	publishersMu.Lock()
+	defer publishersMu.Unlock()
+
+	if _, ok := publishers[scheme]; ok {
+		panic("fsnexport: publisher registered twice for scheme " + scheme)
+	}
+	publishers[scheme] = fn
+}
+
+// NewPublisher creates the publisher registered for the scheme of rawurl.
+func NewPublisher(rawurl string) (Publisher, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	publishersMu.RLock()
+	fn, ok := publishers[u.Scheme]
+	publishersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no publisher for scheme %q", u.Scheme)
+	}
+	return fn(u)
+}
@@ -0,0 +1,509 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnindex
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/rpc"
+)
+
+// HoldersPageSize is the number of holders returned by one fsn_getAssetHolders call
+const HoldersPageSize = 100
+
+// AssetHolder wacom
+type AssetHolder struct {
+	Address         common.Address   `json:"address"`
+	Balance         string           `json:"balance"`
+	TimeLockBalance *common.TimeLock `json:"timeLockBalance"`
+}
+
+// AssetHolders wacom
+type AssetHolders struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	AssetID     common.Hash    `json:"assetID"`
+	Page        uint64         `json:"page"`
+	HasMore     bool           `json:"hasMore"`
+	Holders     []AssetHolder  `json:"holders"`
+}
+
+// PublicHoldersAPI provides the asset holders index in the fsn namespace
+type PublicHoldersAPI struct {
+	indexer *HoldersIndexer
+	chain   *core.BlockChain
+}
+
+// NewPublicHoldersAPI creates a new asset holders api
+func NewPublicHoldersAPI(indexer *HoldersIndexer, chain *core.BlockChain) *PublicHoldersAPI {
+	return &PublicHoldersAPI{indexer: indexer, chain: chain}
+}
+
+// GetAssetHolders returns one page of the holders of assetID, ordered by
+// address. The balances are read from the state of the last indexed block.
+func (api *PublicHoldersAPI) GetAssetHolders(assetID common.Hash, page uint64) (*AssetHolders, error) {
+	number, hash := api.indexer.Head()
+	header := api.chain.GetHeader(hash, number)
+	if header == nil {
+		return nil, fmt.Errorf("asset holders index is not ready")
+	}
+	statedb, err := api.chain.StateAt(header.Root, header.MixDigest)
+	if err != nil {
+		return nil, err
+	}
+	result := &AssetHolders{
+		BlockNumber: hexutil.Uint64(number),
+		BlockHash:   hash,
+		AssetID:     assetID,
+		Page:        page,
+		Holders:     []AssetHolder{},
+	}
+	skip := page * HoldersPageSize
+	err = api.indexer.ForEachHolder(assetID, func(address common.Address) bool {
+		balance := statedb.GetBalance(assetID, address)
+		timelock := statedb.GetTimeLockBalance(assetID, address).Clone().ClearExpired(header.Time)
+		if balance.Sign() == 0 && timelock.IsEmpty() {
+			return true
+		}
+		if skip > 0 {
+			skip--
+			return true
+		}
+		if len(result.Holders) == HoldersPageSize {
+			result.HasMore = true
+			return false
+		}
+		result.Holders = append(result.Holders, AssetHolder{
+			Address:         address,
+			Balance:         balance.String(),
+			TimeLockBalance: timelock.ToDisplay(),
+		})
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, statedb.Error()
+}
+
+// AssetHolderPage wacom
+type AssetHolderPage struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	AssetID     common.Hash    `json:"assetID"`
+	Holders     []AssetHolder  `json:"holders"`
+	NextCursor  string         `json:"nextCursor"` // empty after the last page
+}
+
+// GetAssetHolderPage returns the holders of assetID after the cursor of the
+// page request in ascending address order. The balances are read from the
+// state of the last indexed block.
+func (api *PublicHoldersAPI) GetAssetHolderPage(assetID common.Hash, page *common.PageRequest) (*AssetHolderPage, error) {
+	pager, err := common.NewPager("holders", page)
+	if err != nil {
+		return nil, err
+	}
+	number, hash := api.indexer.Head()
+	header := api.chain.GetHeader(hash, number)
+	if header == nil {
+		return nil, fmt.Errorf("asset holders index is not ready")
+	}
+	statedb, err := api.chain.StateAt(header.Root, header.MixDigest)
+	if err != nil {
+		return nil, err
+	}
+	result := &AssetHolderPage{
+		BlockNumber: hexutil.Uint64(number),
+		BlockHash:   hash,
+		AssetID:     assetID,
+		Holders:     []AssetHolder{},
+	}
+	err = api.indexer.ForEachHolder(assetID, func(address common.Address) bool {
+		if pager.Skip(address[:]) {
+			return true
+		}
+		balance := statedb.GetBalance(assetID, address)
+		timelock := statedb.GetTimeLockBalance(assetID, address).Clone().ClearExpired(header.Time)
+		if balance.Sign() == 0 && timelock.IsEmpty() {
+			return true
+		}
+		if !pager.Add(address[:]) {
+			return false
+		}
+		result.Holders = append(result.Holders, AssetHolder{
+			Address:         address,
+			Balance:         balance.String(),
+			TimeLockBalance: timelock.ToDisplay(),
+		})
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.NextCursor = pager.NextCursor()
+	return result, statedb.Error()
+}
+
+// maxSwapHistory is the maximum number of records returned by one
+// fsn_getSwapHistory call
+const maxSwapHistory = 10000
+
+// SwapHistoryFilter selects the swaps of either one asset pair or one address
+type SwapHistoryFilter struct {
+	Pair    []common.Hash   `json:"pair"`
+	Address *common.Address `json:"address"`
+}
+
+// marketVolumePeriod is the period in seconds of the take volume returned by
+// fsn_getMarketStats
+const marketVolumePeriod = 24 * 60 * 60
+
+// PublicSwapHistoryAPI provides the swap history index in the fsn namespace
+type PublicSwapHistoryAPI struct {
+	indexer *SwapIndexer
+	chain   *core.BlockChain
+}
+
+// NewPublicSwapHistoryAPI creates a new swap history api
+func NewPublicSwapHistoryAPI(indexer *SwapIndexer, chain *core.BlockChain) *PublicSwapHistoryAPI {
+	return &PublicSwapHistoryAPI{indexer: indexer, chain: chain}
+}
+
+// GetSwapHistory returns the swaps made, taken and recalled in the given block
+// range, either between the two assets of filter.Pair or by filter.Address
+func (api *PublicSwapHistoryAPI) GetSwapHistory(filter SwapHistoryFilter, fromBlock, toBlock *rpc.BlockNumber) ([]*SwapRecord, error) {
+	indexed := api.indexer.Indexed()
+	if indexed == 0 {
+		return nil, fmt.Errorf("swap history index is not ready")
+	}
+	from, to := uint64(0), indexed-1
+	if fromBlock != nil && *fromBlock >= 0 {
+		from = uint64(*fromBlock)
+	}
+	if toBlock != nil && *toBlock >= 0 && uint64(*toBlock) < to {
+		to = uint64(*toBlock)
+	}
+	var (
+		records []*SwapRecord
+		err     error
+	)
+	switch {
+	case len(filter.Pair) == 2 && filter.Address == nil:
+		records, err = api.indexer.PairHistory(filter.Pair[0], filter.Pair[1], from, to, maxSwapHistory)
+	case len(filter.Pair) == 0 && filter.Address != nil:
+		records, err = api.indexer.AddressHistory(*filter.Address, from, to, maxSwapHistory)
+	default:
+		return nil, fmt.Errorf("either a pair of two asset IDs or an address must be given")
+	}
+	if err == errTooManySwapRecords {
+		return nil, fmt.Errorf("more than %d swap records, narrow the block range", maxSwapHistory)
+	}
+	if records == nil {
+		records = []*SwapRecord{}
+	}
+	return records, err
+}
+
+// SwapHistoryPage wacom
+type SwapHistoryPage struct {
+	Records    []*SwapRecord `json:"records"`
+	NextCursor string        `json:"nextCursor"` // empty after the last indexed record
+}
+
+// GetSwapHistoryPage returns the swap records of filter after the cursor of
+// the page request, in block and log order
+func (api *PublicSwapHistoryAPI) GetSwapHistoryPage(filter SwapHistoryFilter, page *common.PageRequest) (*SwapHistoryPage, error) {
+	pager, err := common.NewPager("swaps", page)
+	if err != nil {
+		return nil, err
+	}
+	indexed := api.indexer.Indexed()
+	if indexed == 0 {
+		return nil, fmt.Errorf("swap history index is not ready")
+	}
+	var prefix []byte
+	switch {
+	case len(filter.Pair) == 2 && filter.Address == nil:
+		prefix = swapPairKey(filter.Pair[0], filter.Pair[1])
+	case len(filter.Pair) == 0 && filter.Address != nil:
+		prefix = append(append([]byte{}, swapAddressPrefix...), filter.Address[:]...)
+	default:
+		return nil, fmt.Errorf("either a pair of two asset IDs or an address must be given")
+	}
+	var from uint64
+	if after := pager.After(); len(after) == 12 {
+		from = binary.BigEndian.Uint64(after[:8])
+	}
+	result := &SwapHistoryPage{Records: []*SwapRecord{}}
+	err = api.indexer.forEachRecord(prefix, from, indexed-1, func(record *SwapRecord) error {
+		pos := recordPos(record.BlockNumber, record.LogIndex)
+		if pager.Skip(pos) {
+			return nil
+		}
+		if !pager.Add(pos) {
+			return errTooManySwapRecords
+		}
+		result.Records = append(result.Records, record)
+		return nil
+	})
+	if err != nil && err != errTooManySwapRecords {
+		return nil, err
+	}
+	result.NextCursor = pager.NextCursor()
+	return result, nil
+}
+
+// GetMarketStats returns the open swaps between the base and quote assets at
+// the last indexed block, with their best prices in quote asset per base
+// asset, and the volume taken in the last 24 hours
+func (api *PublicSwapHistoryAPI) GetMarketStats(base, quote common.Hash) (*MarketStats, error) {
+	indexed := api.indexer.Indexed()
+	if indexed == 0 {
+		return nil, fmt.Errorf("swap history index is not ready")
+	}
+	if base == quote {
+		return nil, fmt.Errorf("base and quote assets must differ")
+	}
+	head := api.chain.GetHeaderByNumber(indexed - 1)
+	if head == nil {
+		return nil, fmt.Errorf("swap history index is not ready")
+	}
+	var since uint64
+	if head.Time > marketVolumePeriod {
+		since = head.Time - marketVolumePeriod
+	}
+	// First block of the volume period
+	from := uint64(sort.Search(int(indexed), func(i int) bool {
+		header := api.chain.GetHeaderByNumber(uint64(i))
+		return header == nil || header.Time > since
+	}))
+	return api.indexer.MarketStats(base, quote, head.Time, from, indexed-1)
+}
+
+// maxHistoricalStats is the maximum number of blocks returned by one
+// fsn_getHistoricalStats call
+const maxHistoricalStats = 10000
+
+// PublicHistoryAPI provides the historical FSN stats index in the fsn namespace
+type PublicHistoryAPI struct {
+	indexer *HistoryIndexer
+}
+
+// NewPublicHistoryAPI creates a new historical stats api
+func NewPublicHistoryAPI(indexer *HistoryIndexer) *PublicHistoryAPI {
+	return &PublicHistoryAPI{indexer: indexer}
+}
+
+// GetHistoricalStats returns the ticket, asset supply and swap statistics of
+// the blocks in the given range
+func (api *PublicHistoryAPI) GetHistoricalStats(fromBlock, toBlock rpc.BlockNumber) ([]*HistoricalStats, error) {
+	indexed := api.indexer.Indexed()
+	if indexed == 0 {
+		return nil, fmt.Errorf("historical stats index is not ready")
+	}
+	from, to := uint64(0), indexed-1
+	if fromBlock >= 0 {
+		from = uint64(fromBlock)
+	}
+	if toBlock >= 0 && uint64(toBlock) < to {
+		to = uint64(toBlock)
+	}
+	if from > to {
+		return []*HistoricalStats{}, nil
+	}
+	if to-from >= maxHistoricalStats {
+		return nil, fmt.Errorf("more than %d blocks, narrow the block range", maxHistoricalStats)
+	}
+	return api.indexer.Stats(from, to)
+}
+
+// HistoricalStatsPage wacom
+type HistoricalStatsPage struct {
+	Stats      []*HistoricalStats `json:"stats"`
+	NextCursor string             `json:"nextCursor"` // empty after the last indexed block
+}
+
+// GetHistoricalStatsPage returns the statistics of the blocks after the
+// cursor of the page request, starting at fromBlock for the first page
+func (api *PublicHistoryAPI) GetHistoricalStatsPage(fromBlock rpc.BlockNumber, page *common.PageRequest) (*HistoricalStatsPage, error) {
+	pager, err := common.NewPager("history", page)
+	if err != nil {
+		return nil, err
+	}
+	indexed := api.indexer.Indexed()
+	if indexed == 0 {
+		return nil, fmt.Errorf("historical stats index is not ready")
+	}
+	var from uint64
+	if fromBlock >= 0 {
+		from = uint64(fromBlock)
+	}
+	if after := pager.After(); len(after) == 8 {
+		from = binary.BigEndian.Uint64(after) + 1
+	}
+	result := &HistoricalStatsPage{Stats: []*HistoricalStats{}}
+	for n := from; n < indexed; n++ {
+		if !pager.Add(historyKey(n)) {
+			break
+		}
+		stats, err := readHistoricalStats(api.indexer.db, n)
+		if err != nil {
+			return nil, fmt.Errorf("stats of block #%d: %v", n, err)
+		}
+		result.Stats = append(result.Stats, stats)
+	}
+	result.NextCursor = pager.NextCursor()
+	return result, nil
+}
+
+// PublicCacheAPI serves the historical fsn queries addressed by block hash
+// from the result cache
+type PublicCacheAPI struct {
+	cache *ResultCache
+	chain *core.BlockChain
+}
+
+// NewPublicCacheAPI creates a new cached historical query api
+func NewPublicCacheAPI(cache *ResultCache, chain *core.BlockChain) *PublicCacheAPI {
+	return &PublicCacheAPI{cache: cache, chain: chain}
+}
+
+func (api *PublicCacheAPI) headerByHash(blockHash common.Hash) (*types.Header, error) {
+	header := api.chain.GetHeaderByHash(blockHash)
+	if header == nil {
+		return nil, fmt.Errorf("block %x not found", blockHash)
+	}
+	return header, nil
+}
+
+// GetAllAssetsAtHash returns the assets in the state of the given block
+func (api *PublicCacheAPI) GetAllAssetsAtHash(blockHash common.Hash) (map[common.Hash]common.Asset, error) {
+	var assets map[common.Hash]common.Asset
+	if api.cache.get(cacheAssets, blockHash, &assets) {
+		return assets, nil
+	}
+	header, err := api.headerByHash(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	statedb, err := api.chain.StateAt(header.Root, header.MixDigest)
+	if err != nil {
+		return nil, err
+	}
+	if assets, err = statedb.AllAssets(); err == nil {
+		err = statedb.Error()
+	}
+	if err != nil {
+		return nil, err
+	}
+	api.cache.put(cacheAssets, blockHash, assets)
+	return assets, nil
+}
+
+// GetAllTicketsAtHash returns the tickets in the state of the given block,
+// they are cached by the ticket set hash so that the blocks which did not
+// change the tickets share the entry.
+func (api *PublicCacheAPI) GetAllTicketsAtHash(blockHash common.Hash) (map[common.Hash]common.TicketDisplay, error) {
+	header, err := api.headerByHash(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	var tickets map[common.Hash]common.TicketDisplay
+	if api.cache.get(cacheTickets, header.MixDigest, &tickets) {
+		return tickets, nil
+	}
+	statedb, err := api.chain.StateAt(header.Root, header.MixDigest)
+	if err != nil {
+		return nil, err
+	}
+	all, err := statedb.AllTickets()
+	if err == nil {
+		err = statedb.Error()
+	}
+	if err != nil {
+		return nil, err
+	}
+	tickets = all.ToMap()
+	api.cache.put(cacheTickets, header.MixDigest, tickets)
+	return tickets, nil
+}
+
+// PublicFuncIndexAPI provides the FSN func index in the fsn namespace
+type PublicFuncIndexAPI struct {
+	indexer *FuncIndexer
+}
+
+// NewPublicFuncIndexAPI creates a new FSN func index api
+func NewPublicFuncIndexAPI(indexer *FuncIndexer) *PublicFuncIndexAPI {
+	return &PublicFuncIndexAPI{indexer: indexer}
+}
+
+// FuncTxPage wacom
+type FuncTxPage struct {
+	Transactions []*FuncTxRecord `json:"transactions"`
+	NextCursor   string          `json:"nextCursor"` // empty after the last transaction of the range
+}
+
+// GetTransactionsByFunc returns the FSN call transactions of fn in the given
+// block range after the cursor of the page request, in block and transaction
+// order
+func (api *PublicFuncIndexAPI) GetTransactionsByFunc(fn common.FSNCallFunc, fromBlock, toBlock rpc.BlockNumber, page *common.PageRequest) (*FuncTxPage, error) {
+	pager, err := common.NewPager("funcs", page)
+	if err != nil {
+		return nil, err
+	}
+	indexed := api.indexer.Indexed()
+	if indexed == 0 {
+		return nil, fmt.Errorf("FSN func index is not ready")
+	}
+	from, to := uint64(0), indexed-1
+	if fromBlock >= 0 {
+		from = uint64(fromBlock)
+	}
+	if toBlock >= 0 && uint64(toBlock) < to {
+		to = uint64(toBlock)
+	}
+	if after := pager.After(); len(after) == 12 && binary.BigEndian.Uint64(after[:8]) > from {
+		from = binary.BigEndian.Uint64(after[:8])
+	}
+	result := &FuncTxPage{Transactions: []*FuncTxRecord{}}
+	if from > to {
+		return result, nil
+	}
+	err = api.indexer.forEachRecord(fn, from, to, func(record *FuncTxRecord) error {
+		pos := recordPos(record.BlockNumber, record.TxIndex)
+		if pager.Skip(pos) {
+			return nil
+		}
+		if !pager.Add(pos) {
+			return errTooManyFuncTxs
+		}
+		result.Transactions = append(result.Transactions, record)
+		return nil
+	})
+	if err != nil && err != errTooManyFuncTxs {
+		return nil, err
+	}
+	result.NextCursor = pager.NextCursor()
+	return result, nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnindex
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/consensus/datong"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/ethdb"
+	"github.com/FusionFoundation/go-fusion/log"
+	"github.com/FusionFoundation/go-fusion/metrics"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// Kinds of cached results, a result is identified by its kind and the hash
+// of the data it was computed from.
+const (
+	cacheAssets   byte = 'a' // cacheAssets + block hash -> json(map[assetID]Asset)
+	cacheTickets  byte = 't' // cacheTickets + ticket set hash -> json(map[ticketID]TicketDisplay)
+	cacheSnapshot byte = 'S' // cacheSnapshot + block hash -> json(datong.Snapshot), with the ticket details
+)
+
+var (
+	cacheTablePrefix = "fsnindex-cache-" // cacheTablePrefix + kind + hash -> result
+
+	cacheHitMeter   = metrics.NewRegisteredMeter("fsn/cache/hit", nil)
+	cacheMissMeter  = metrics.NewRegisteredMeter("fsn/cache/miss", nil)
+	cacheEvictMeter = metrics.NewRegisteredMeter("fsn/cache/evict", nil)
+)
+
+// ResultCache keeps the results of historical fsn queries on disk. Only
+// results addressed by a hash are cached, they never change, so entries
+// are dropped solely to bound the size of the cache, least recently used
+// first.
+type ResultCache struct {
+	hits, misses, evicted uint64 // accessed atomically, keep 64-bit aligned
+
+	db      ethdb.Database
+	size    int
+	entries *lru.Cache // keys of the entries on disk, in use order
+	lock    sync.Mutex // serializes the writes with the purges
+}
+
+// ResultCacheStats wacom
+type ResultCacheStats struct {
+	Size    int    `json:"size"`
+	Len     int    `json:"len"`
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Evicted uint64 `json:"evicted"`
+}
+
+// NewResultCache opens the result cache in db, keeping at most size entries.
+func NewResultCache(db ethdb.Database, size int) *ResultCache {
+	c := &ResultCache{db: rawdb.NewTable(db, cacheTablePrefix), size: size}
+	c.entries, _ = lru.NewWithEvict(size, c.drop)
+
+	// The use order is not persisted, the entries found on disk are evicted
+	// in the iteration order if there are too many of them.
+	it := c.db.NewIterator()
+	for it.Next() {
+		c.entries.Add(string(it.Key()[len(cacheTablePrefix):]), nil)
+	}
+	it.Release()
+	log.Info("Opened fsn result cache", "entries", c.entries.Len(), "limit", size)
+	return c
+}
+
+// drop deletes an entry removed from the use order from disk.
+func (c *ResultCache) drop(key, value interface{}) {
+	c.db.Delete([]byte(key.(string)))
+}
+
+func (c *ResultCache) hit() {
+	atomic.AddUint64(&c.hits, 1)
+	cacheHitMeter.Mark(1)
+}
+
+func (c *ResultCache) miss() {
+	atomic.AddUint64(&c.misses, 1)
+	cacheMissMeter.Mark(1)
+}
+
+func cacheKey(kind byte, hash common.Hash) string {
+	return string(append([]byte{kind}, hash[:]...))
+}
+
+// get decodes the cached result of kind for hash into v and reports
+// whether it was cached.
+func (c *ResultCache) get(kind byte, hash common.Hash, v interface{}) bool {
+	key := cacheKey(kind, hash)
+	if _, ok := c.entries.Get(key); !ok {
+		c.miss()
+		return false
+	}
+	blob, err := c.db.Get([]byte(key))
+	if err == nil {
+		err = json.Unmarshal(blob, v)
+	}
+	if err != nil {
+		log.Debug("Dropping unreadable fsn cache entry", "kind", string(kind), "hash", hash, "err", err)
+		c.entries.Remove(key)
+		c.miss()
+		return false
+	}
+	c.hit()
+	return true
+}
+
+// put caches the result v of kind for hash.
+func (c *ResultCache) put(kind byte, hash common.Hash, v interface{}) {
+	blob, err := json.Marshal(v)
+	if err != nil {
+		log.Warn("Failed to encode fsn cache entry", "kind", string(kind), "hash", hash, "err", err)
+		return
+	}
+	key := cacheKey(kind, hash)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.db.Put([]byte(key), blob); err != nil {
+		log.Warn("Failed to store fsn cache entry", "kind", string(kind), "hash", hash, "err", err)
+		return
+	}
+	if c.entries.Add(key, nil) {
+		atomic.AddUint64(&c.evicted, 1)
+		cacheEvictMeter.Mark(1)
+	}
+}
+
+// Snapshot returns the cached datong snapshot of the block with the given hash.
+func (c *ResultCache) Snapshot(hash common.Hash) (*datong.Snapshot, bool) {
+	snap := new(datong.Snapshot)
+	if !c.get(cacheSnapshot, hash, snap) {
+		return nil, false
+	}
+	return snap, true
+}
+
+// AddSnapshot caches the datong snapshot of the block with the given hash.
+func (c *ResultCache) AddSnapshot(hash common.Hash, snap *datong.Snapshot) {
+	c.put(cacheSnapshot, hash, snap)
+}
+
+// Purge removes all cached results.
+func (c *ResultCache) Purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries.Purge()
+}
+
+// Stats returns the size and usage of the cache.
+func (c *ResultCache) Stats() ResultCacheStats {
+	return ResultCacheStats{
+		Size:    c.size,
+		Len:     c.entries.Len(),
+		Hits:    atomic.LoadUint64(&c.hits),
+		Misses:  atomic.LoadUint64(&c.misses),
+		Evicted: atomic.LoadUint64(&c.evicted),
+	}
+}
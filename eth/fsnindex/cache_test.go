@@ -0,0 +1,87 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnindex
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/consensus/datong"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+)
+
+func TestResultCache(t *testing.T) {
+	var (
+		chainDb = rawdb.NewMemoryDatabase()
+		cache   = NewResultCache(chainDb, 2)
+
+		block1 = common.HexToHash("0x01")
+		block2 = common.HexToHash("0x02")
+		block3 = common.HexToHash("0x03")
+	)
+	assets := map[common.Hash]common.Asset{
+		common.SystemAssetID: {ID: common.SystemAssetID, Name: "Fusion", Symbol: "FSN", Decimals: 18, Total: big.NewInt(1e18)},
+	}
+	cache.put(cacheAssets, block1, assets)
+
+	var cached map[common.Hash]common.Asset
+	if !cache.get(cacheAssets, block1, &cached) {
+		t.Fatal("assets not cached")
+	}
+	if !reflect.DeepEqual(cached, assets) {
+		t.Fatalf("cached assets mismatch: have %v, want %v", cached, assets)
+	}
+	if cache.get(cacheTickets, block1, &cached) {
+		t.Fatal("result cached under another kind")
+	}
+
+	// Use block1 again so that block2 is the least recently used entry
+	snap := &datong.Snapshot{Selected: common.HexToHash("0x5e"), TicketNumber: 7}
+	cache.AddSnapshot(block2, snap)
+	cache.get(cacheAssets, block1, &cached)
+	cache.AddSnapshot(block3, snap)
+	if _, ok := cache.Snapshot(block2); ok {
+		t.Fatal("least recently used entry not evicted")
+	}
+	if have, ok := cache.Snapshot(block3); !ok || !reflect.DeepEqual(have, snap) {
+		t.Fatalf("cached snapshot mismatch: have %v, want %v", have, snap)
+	}
+	stats := cache.Stats()
+	if stats.Len != 2 || stats.Hits != 3 || stats.Misses != 2 || stats.Evicted != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	// The entries survive a restart
+	cache = NewResultCache(chainDb, 2)
+	if !cache.get(cacheAssets, block1, &cached) {
+		t.Fatal("assets not reloaded")
+	}
+	if _, ok := cache.Snapshot(block3); !ok {
+		t.Fatal("snapshot not reloaded")
+	}
+	cache.Purge()
+	if _, ok := cache.Snapshot(block3); ok {
+		t.Fatal("snapshot not purged")
+	}
+	it := chainDb.NewIteratorWithPrefix([]byte(cacheTablePrefix))
+	defer it.Release()
+	if it.Next() {
+		t.Fatalf("purged entry %x left on disk", it.Key())
+	}
+}
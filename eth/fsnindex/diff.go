@@ -0,0 +1,266 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnindex
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/rpc"
+)
+
+// maxDiffSwapRecords is the maximum number of swap records debug_diffState
+// reads for one block range
+const maxDiffSwapRecords = 10000
+
+// ObjectDiff lists the IDs of the objects of one kind which differ between
+// two blocks, sorted
+type ObjectDiff struct {
+	Created  []common.Hash `json:"created"`
+	Removed  []common.Hash `json:"removed"`
+	Modified []common.Hash `json:"modified"`
+}
+
+func (d *ObjectDiff) add(id common.Hash, before, after bool, modified bool) {
+	switch {
+	case !before && after:
+		d.Created = append(d.Created, id)
+	case before && !after:
+		d.Removed = append(d.Removed, id)
+	case before && after && modified:
+		d.Modified = append(d.Modified, id)
+	}
+}
+
+func (d *ObjectDiff) sort() {
+	for _, ids := range [][]common.Hash{d.Created, d.Removed, d.Modified} {
+		sort.Slice(ids, func(i, j int) bool { return bytes.Compare(ids[i][:], ids[j][:]) < 0 })
+	}
+}
+
+func newObjectDiff() ObjectDiff {
+	return ObjectDiff{Created: []common.Hash{}, Removed: []common.Hash{}, Modified: []common.Hash{}}
+}
+
+// StateDiff is the difference of the FSN objects between two blocks. Balances
+// holds the non zero balance deltas of the address, if one is given.
+type StateDiff struct {
+	FromBlock hexutil.Uint64         `json:"fromBlock"`
+	FromHash  common.Hash            `json:"fromHash"`
+	ToBlock   hexutil.Uint64         `json:"toBlock"`
+	ToHash    common.Hash            `json:"toHash"`
+	Assets    ObjectDiff             `json:"assets"`
+	Tickets   ObjectDiff             `json:"tickets"`
+	Swaps     ObjectDiff             `json:"swaps"`
+	Balances  map[common.Hash]string `json:"balances,omitempty"`
+}
+
+// PrivateDiffAPI provides the state diff between two blocks in the debug
+// namespace, as it loads all assets and tickets of both blocks. The swaps
+// which changed are found with the swap history index.
+type PrivateDiffAPI struct {
+	swaps *SwapIndexer
+	cache *PublicCacheAPI // nil without result cache
+	chain *core.BlockChain
+}
+
+// NewPrivateDiffAPI creates a new state diff api, cache may be nil
+func NewPrivateDiffAPI(swaps *SwapIndexer, cache *ResultCache, chain *core.BlockChain) *PrivateDiffAPI {
+	api := &PrivateDiffAPI{swaps: swaps, chain: chain}
+	if cache != nil {
+		api.cache = NewPublicCacheAPI(cache, chain)
+	}
+	return api
+}
+
+func (api *PrivateDiffAPI) header(number rpc.BlockNumber) (*types.Header, error) {
+	if number < 0 {
+		return api.chain.CurrentHeader(), nil
+	}
+	header := api.chain.GetHeaderByNumber(uint64(number))
+	if header == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	return header, nil
+}
+
+func (api *PrivateDiffAPI) assetsAt(header *types.Header, statedb *state.StateDB) (map[common.Hash]common.Asset, error) {
+	if api.cache != nil {
+		return api.cache.GetAllAssetsAtHash(header.Hash())
+	}
+	assets, err := statedb.AllAssets()
+	if err == nil {
+		err = statedb.Error()
+	}
+	return assets, err
+}
+
+func (api *PrivateDiffAPI) ticketsAt(header *types.Header, statedb *state.StateDB) (map[common.Hash]common.TicketDisplay, error) {
+	if api.cache != nil {
+		return api.cache.GetAllTicketsAtHash(header.Hash())
+	}
+	tickets, err := statedb.AllTickets()
+	if err == nil {
+		err = statedb.Error()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tickets.ToMap(), nil
+}
+
+// DiffState returns the assets, tickets and swaps created, removed or
+// modified between blockA and blockB, of the given address only if one is
+// given, together with the balance deltas of the address
+func (api *PrivateDiffAPI) DiffState(blockA, blockB rpc.BlockNumber, addr *common.Address) (*StateDiff, error) {
+	headerA, err := api.header(blockA)
+	if err != nil {
+		return nil, err
+	}
+	headerB, err := api.header(blockB)
+	if err != nil {
+		return nil, err
+	}
+	from, to := headerA.Number.Uint64(), headerB.Number.Uint64()
+	if from > to {
+		return nil, fmt.Errorf("block #%d is after block #%d", from, to)
+	}
+	if indexed := api.swaps.Indexed(); indexed <= to {
+		return nil, fmt.Errorf("swap history index is behind block #%d", to)
+	}
+	stateA, err := api.chain.StateAt(headerA.Root, headerA.MixDigest)
+	if err != nil {
+		return nil, err
+	}
+	stateB, err := api.chain.StateAt(headerB.Root, headerB.MixDigest)
+	if err != nil {
+		return nil, err
+	}
+	result := &StateDiff{
+		FromBlock: hexutil.Uint64(from),
+		FromHash:  headerA.Hash(),
+		ToBlock:   hexutil.Uint64(to),
+		ToHash:    headerB.Hash(),
+		Assets:    newObjectDiff(),
+		Tickets:   newObjectDiff(),
+		Swaps:     newObjectDiff(),
+	}
+	owned := func(owner common.Address) bool { return addr == nil || owner == *addr }
+
+	// Assets
+	assetsA, err := api.assetsAt(headerA, stateA)
+	if err != nil {
+		return nil, err
+	}
+	assetsB, err := api.assetsAt(headerB, stateB)
+	if err != nil {
+		return nil, err
+	}
+	diffObjects(&result.Assets, assetsA, assetsB, func(v interface{}) bool { return owned(v.(common.Asset).Owner) })
+
+	// Tickets
+	ticketsA, err := api.ticketsAt(headerA, stateA)
+	if err != nil {
+		return nil, err
+	}
+	ticketsB, err := api.ticketsAt(headerB, stateB)
+	if err != nil {
+		return nil, err
+	}
+	diffObjects(&result.Tickets, ticketsA, ticketsB, func(v interface{}) bool { return owned(v.(common.TicketDisplay).Owner) })
+
+	// Swaps, only the ones with a record after blockA can differ
+	if from < to {
+		var records []*SwapRecord
+		if addr != nil {
+			records, err = api.swaps.AddressHistory(*addr, from+1, to, maxDiffSwapRecords)
+		} else {
+			records, err = api.swaps.history(swapRecordPrefix, from+1, to, maxDiffSwapRecords)
+		}
+		if err == errTooManySwapRecords {
+			return nil, fmt.Errorf("more than %d swap records, narrow the block range", maxDiffSwapRecords)
+		}
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[common.Hash]bool)
+		for _, record := range records {
+			if seen[record.SwapID] {
+				continue
+			}
+			seen[record.SwapID] = true
+			swapA, errA := stateA.GetSwap(record.SwapID)
+			swapB, errB := stateB.GetSwap(record.SwapID)
+			result.Swaps.add(record.SwapID, errA == nil, errB == nil, !reflect.DeepEqual(swapA, swapB))
+		}
+	}
+	result.Assets.sort()
+	result.Tickets.sort()
+	result.Swaps.sort()
+
+	// Balances
+	if addr != nil {
+		result.Balances = make(map[common.Hash]string)
+		balancesA, balancesB := stateA.GetAllBalances(*addr), stateB.GetAllBalances(*addr)
+		for assetID := range balancesA {
+			if _, ok := balancesB[assetID]; !ok {
+				balancesB[assetID] = "0"
+			}
+		}
+		for assetID, b := range balancesB {
+			before, _ := new(big.Int).SetString(balancesA[assetID], 10)
+			after, _ := new(big.Int).SetString(b, 10)
+			if before == nil {
+				before = new(big.Int)
+			}
+			if after == nil {
+				after = new(big.Int)
+			}
+			if delta := after.Sub(after, before); delta.Sign() != 0 {
+				result.Balances[assetID] = delta.String()
+			}
+		}
+	}
+	return result, nil
+}
+
+// diffObjects adds to d the objects of before and after, which are maps of
+// the same object type by ID, selected by filter in either block
+func diffObjects(d *ObjectDiff, before, after interface{}, filter func(interface{}) bool) {
+	a, b := reflect.ValueOf(before), reflect.ValueOf(after)
+	for _, key := range a.MapKeys() {
+		va, vb := a.MapIndex(key), b.MapIndex(key)
+		if !filter(va.Interface()) && (!vb.IsValid() || !filter(vb.Interface())) {
+			continue
+		}
+		d.add(key.Interface().(common.Hash), true, vb.IsValid(), vb.IsValid() && !reflect.DeepEqual(va.Interface(), vb.Interface()))
+	}
+	for _, key := range b.MapKeys() {
+		if a.MapIndex(key).IsValid() || !filter(b.MapIndex(key).Interface()) {
+			continue
+		}
+		d.add(key.Interface().(common.Hash), false, true, false)
+	}
+}
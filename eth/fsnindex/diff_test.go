@@ -0,0 +1,64 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnindex
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+)
+
+func TestDiffObjects(t *testing.T) {
+	var (
+		owner = common.HexToAddress("0x01")
+		other = common.HexToAddress("0x02")
+
+		kept, changed, removed, created = common.HexToHash("0x11"), common.HexToHash("0x12"), common.HexToHash("0x13"), common.HexToHash("0x14")
+		foreign, transferred            = common.HexToHash("0x15"), common.HexToHash("0x16")
+	)
+	asset := func(owner common.Address, total int64) common.Asset {
+		return common.Asset{Owner: owner, Total: big.NewInt(total)}
+	}
+	before := map[common.Hash]common.Asset{
+		kept:        asset(owner, 1),
+		changed:     asset(owner, 1),
+		removed:     asset(owner, 1),
+		foreign:     asset(other, 1),
+		transferred: asset(other, 1),
+	}
+	after := map[common.Hash]common.Asset{
+		kept:        asset(owner, 1),
+		changed:     asset(owner, 2),
+		created:     asset(owner, 1),
+		foreign:     asset(other, 2),
+		transferred: asset(owner, 1),
+	}
+	diff := newObjectDiff()
+	diffObjects(&diff, before, after, func(v interface{}) bool { return v.(common.Asset).Owner == owner })
+	diff.sort()
+
+	want := ObjectDiff{
+		Created:  []common.Hash{created},
+		Removed:  []common.Hash{removed},
+		Modified: []common.Hash{changed, transferred},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("diff mismatch:\nhave %+v\nwant %+v", diff, want)
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnindex
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/ethdb"
+	"github.com/FusionFoundation/go-fusion/params"
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+const (
+	// funcsSectionSize is the number of blocks in one FSN func section. Every
+	// block is its own section so that the index follows the head.
+	funcsSectionSize = 1
+
+	// funcsConfirms is the number of confirmations before a block is indexed,
+	// reorgs are handled by Reset removing the entries of the section.
+	funcsConfirms = 0
+)
+
+var (
+	funcsTablePrefix = "fsnindex-funcs-"      // funcsTablePrefix + key -> data
+	funcsMetaPrefix  = "fsnindex-funcs-meta-" // chain indexer metadata
+
+	funcTxPrefix    = []byte("f") // funcTxPrefix + func + num (uint64 big endian) + tx index (uint32 big endian) -> rlp(FuncTxRecord)
+	funcBlockPrefix = []byte("b") // funcBlockPrefix + num (uint64 big endian) + tx index (uint32 big endian) -> func
+
+	errTooManyFuncTxs = errors.New("too many FSN call transactions")
+)
+
+// FuncTxRecord wacom
+type FuncTxRecord struct {
+	Func        common.FSNCallFunc
+	TxHash      common.Hash
+	From        common.Address
+	BlockNumber uint64
+	TxIndex     uint
+	Status      uint64 // receipt status, failed FSN calls are indexed too
+}
+
+// FuncIndexer records the FSN call transactions of every block by FSN
+// function, saving the scan of all receipts to find the calls of one function.
+type FuncIndexer struct {
+	db      ethdb.Database
+	indexer *core.ChainIndexer
+}
+
+// NewFuncIndexer creates an FSN func indexer storing its data in the given
+// chain database.
+func NewFuncIndexer(chainDb ethdb.Database, config *params.ChainConfig) *FuncIndexer {
+	db := rawdb.NewTable(chainDb, funcsTablePrefix)
+	backend := &funcIndexerBackend{
+		chainDb: chainDb,
+		db:      db,
+		config:  config,
+	}
+	table := rawdb.NewTable(chainDb, funcsMetaPrefix)
+
+	return &FuncIndexer{
+		db:      db,
+		indexer: core.NewChainIndexer(chainDb, table, backend, funcsSectionSize, funcsConfirms, 0, "funcs"),
+	}
+}
+
+// Start starts indexing the given chain in the background.
+func (f *FuncIndexer) Start(chain core.ChainIndexerChain) {
+	f.indexer.Start(chain)
+}
+
+// Close terminates the indexer.
+func (f *FuncIndexer) Close() error {
+	return f.indexer.Close()
+}
+
+// Indexed returns the number of blocks included in the index.
+func (f *FuncIndexer) Indexed() uint64 {
+	sections, _, _ := f.indexer.Sections()
+	return sections * funcsSectionSize
+}
+
+// Transactions returns the FSN call transactions of fn in blocks [from, to].
+func (f *FuncIndexer) Transactions(fn common.FSNCallFunc, from, to uint64, limit int) ([]*FuncTxRecord, error) {
+	var records []*FuncTxRecord
+	err := f.forEachRecord(fn, from, to, func(record *FuncTxRecord) error {
+		if len(records) == limit {
+			return errTooManyFuncTxs
+		}
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// forEachRecord calls fn for the FSN call transactions of the function in
+// blocks [from, to], stopping at the first error.
+func (f *FuncIndexer) forEachRecord(fn common.FSNCallFunc, from, to uint64, cb func(*FuncTxRecord) error) error {
+	prefix := funcTxKey(fn, nil)
+	it := f.db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+
+	for it.Next() {
+		// Keys are returned with the table prefix included
+		key := it.Key()
+		if len(key) != len(funcsTablePrefix)+len(prefix)+12 {
+			continue
+		}
+		number := binary.BigEndian.Uint64(key[len(key)-12:])
+		if number < from {
+			continue
+		}
+		if number > to {
+			break
+		}
+		record := new(FuncTxRecord)
+		if err := rlp.DecodeBytes(it.Value(), record); err != nil {
+			return err
+		}
+		if err := cb(record); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// funcIndexerBackend implements core.ChainIndexerBackend.
+type funcIndexerBackend struct {
+	chainDb ethdb.Database
+	db      ethdb.Database
+	config  *params.ChainConfig
+
+	batch ethdb.Batch
+}
+
+// Reset implements core.ChainIndexerBackend, removing the entries of a
+// section which is going to be reprocessed.
+func (b *funcIndexerBackend) Reset(ctx context.Context, section uint64, prevHead common.Hash) error {
+	b.batch = b.db.NewBatch()
+	for n := section * funcsSectionSize; n < (section+1)*funcsSectionSize; n++ {
+		prefix := append(append([]byte{}, funcBlockPrefix...), encodeUint64(n)...)
+		it := b.db.NewIteratorWithPrefix(prefix)
+		for it.Next() {
+			key := it.Key()[len(funcsTablePrefix):]
+			if len(it.Value()) == 1 {
+				pos := key[len(funcBlockPrefix):]
+				b.batch.Delete(funcTxKey(common.FSNCallFunc(it.Value()[0]), pos))
+			}
+			b.batch.Delete(common.CopyBytes(key))
+		}
+		it.Release()
+		if err := it.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Process implements core.ChainIndexerBackend, recording the FSN call
+// transactions of a block.
+func (b *funcIndexerBackend) Process(ctx context.Context, header *types.Header) error {
+	hash, number := header.Hash(), header.Number.Uint64()
+	block := rawdb.ReadBlock(b.chainDb, hash, number)
+	if block == nil {
+		return fmt.Errorf("block #%d [%x…] not found", number, hash[:4])
+	}
+	txs := block.Transactions()
+	if len(txs) == 0 {
+		return nil
+	}
+	receipts := rawdb.ReadRawReceipts(b.chainDb, hash, number)
+	if len(receipts) != len(txs) {
+		return fmt.Errorf("receipts of block #%d [%x…] not found", number, hash[:4])
+	}
+	signer := types.MakeSigner(b.config, header.Number)
+	for i, tx := range txs {
+		if !common.IsFsnCall(tx.To()) {
+			continue
+		}
+		param := common.FSNCallParam{}
+		if rlp.DecodeBytes(tx.Data(), &param) != nil {
+			continue
+		}
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return err
+		}
+		if err := b.writeRecord(&FuncTxRecord{
+			Func:        param.Func,
+			TxHash:      tx.Hash(),
+			From:        from,
+			BlockNumber: number,
+			TxIndex:     uint(i),
+			Status:      receipts[i].Status,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *funcIndexerBackend) writeRecord(record *FuncTxRecord) error {
+	enc, err := rlp.EncodeToBytes(record)
+	if err != nil {
+		return err
+	}
+	pos := recordPos(record.BlockNumber, record.TxIndex)
+	b.batch.Put(funcTxKey(record.Func, pos), enc)
+	b.batch.Put(append(append([]byte{}, funcBlockPrefix...), pos...), []byte{byte(record.Func)})
+	return nil
+}
+
+// Commit implements core.ChainIndexerBackend, writing out the entries of the
+// section.
+func (b *funcIndexerBackend) Commit() error {
+	return b.batch.Write()
+}
+
+// funcTxKey = funcTxPrefix + func + pos
+func funcTxKey(fn common.FSNCallFunc, pos []byte) []byte {
+	key := make([]byte, 0, len(funcTxPrefix)+1+len(pos))
+	key = append(key, funcTxPrefix...)
+	key = append(key, byte(fn))
+	return append(key, pos...)
+}
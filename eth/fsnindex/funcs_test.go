@@ -0,0 +1,73 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnindex
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+)
+
+func TestFuncRecords(t *testing.T) {
+	var (
+		chainDb = rawdb.NewMemoryDatabase()
+		indexer = &FuncIndexer{db: rawdb.NewTable(chainDb, funcsTablePrefix)}
+		backend = &funcIndexerBackend{chainDb: chainDb, db: indexer.db}
+	)
+	process := func(section uint64, fns ...common.FSNCallFunc) {
+		if err := backend.Reset(context.Background(), section, common.Hash{}); err != nil {
+			t.Fatalf("reset failed: %v", err)
+		}
+		for i, fn := range fns {
+			record := &FuncTxRecord{Func: fn, BlockNumber: section, TxIndex: uint(i), TxHash: common.BigToHash(common.Big1)}
+			if err := backend.writeRecord(record); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+		}
+		if err := backend.Commit(); err != nil {
+			t.Fatalf("commit failed: %v", err)
+		}
+	}
+	process(1, common.GenAssetFunc, common.SendAssetFunc, common.GenAssetFunc)
+	process(2, common.SendAssetFunc)
+	process(3, common.GenAssetFunc)
+
+	records, err := indexer.Transactions(common.GenAssetFunc, 0, 10, 100)
+	if err != nil {
+		t.Fatalf("transactions failed: %v", err)
+	}
+	if len(records) != 3 || records[1].TxIndex != 2 || records[2].BlockNumber != 3 {
+		t.Fatalf("GenAsset transactions mismatch: %+v", records)
+	}
+	if records, _ := indexer.Transactions(common.GenAssetFunc, 2, 10, 100); len(records) != 1 {
+		t.Errorf("ranged transactions length mismatch: have %d, want 1", len(records))
+	}
+	if _, err := indexer.Transactions(common.GenAssetFunc, 0, 10, 2); err != errTooManyFuncTxs {
+		t.Errorf("expected too many transactions error, have %v", err)
+	}
+
+	// Reprocessing a block must replace its entries
+	process(1, common.SendAssetFunc)
+	if records, _ := indexer.Transactions(common.GenAssetFunc, 0, 10, 100); len(records) != 1 {
+		t.Errorf("GenAsset transactions length mismatch after reset: have %d, want 1", len(records))
+	}
+	if records, _ := indexer.Transactions(common.SendAssetFunc, 0, 10, 100); len(records) != 2 {
+		t.Errorf("SendAsset transactions length mismatch after reset: have %d, want 2", len(records))
+	}
+}
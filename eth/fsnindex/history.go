@@ -0,0 +1,268 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnindex
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/consensus/datong"
+	"github.com/FusionFoundation/go-fusion/core"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/ethdb"
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+const (
+	// historySectionSize is the number of blocks in one historical stats
+	// section. Every block is its own section so that the stats follow the
+	// head.
+	historySectionSize = 1
+
+	// historyConfirms is the number of confirmations before a block is
+	// indexed, reorgs are handled by Reset removing the stats of the section.
+	historyConfirms = 0
+)
+
+var (
+	historyTablePrefix = "fsnindex-history-"      // historyTablePrefix + num (uint64 big endian) -> rlp(HistoricalStats)
+	historyMetaPrefix  = "fsnindex-history-meta-" // chain indexer metadata
+)
+
+// AssetSupplyChange is the change of the total supply of one asset in a
+// block.
+type AssetSupplyChange struct {
+	AssetID   common.Hash `json:"assetID"`
+	Generated bool        `json:"generated"` // asset created in the block
+	Increased *big.Int    `json:"increased"`
+	Decreased *big.Int    `json:"decreased"`
+}
+
+// HistoricalStats wacom
+type HistoricalStats struct {
+	Number        uint64               `json:"number"`
+	TicketsHash   common.Hash          `json:"ticketsHash"`
+	Tickets       uint64               `json:"tickets"`
+	SupplyChanges []*AssetSupplyChange `json:"supplyChanges"`
+	OpenSwaps     uint64               `json:"openSwaps"` // swaps open at the end of the block
+	MadeSwaps     uint64               `json:"madeSwaps"`
+	ClosedSwaps   uint64               `json:"closedSwaps"` // swaps recalled or completely taken
+}
+
+// historyLogData is the part of the asset and swap logs needed by the index.
+type historyLogData struct {
+	AssetID common.Hash
+	Total   *big.Int
+	Value   *big.Int
+	IsInc   bool
+	Deleted string
+	Error   string
+}
+
+// HistoryIndexer records per block statistics of the tickets, the asset
+// supplies and the open swaps, saving the replay of the chain to compute
+// their series.
+type HistoryIndexer struct {
+	db      ethdb.Database
+	indexer *core.ChainIndexer
+}
+
+// NewHistoryIndexer creates a historical stats indexer storing its data in
+// the given chain database.
+func NewHistoryIndexer(chainDb ethdb.Database) *HistoryIndexer {
+	db := rawdb.NewTable(chainDb, historyTablePrefix)
+	backend := &historyIndexerBackend{
+		chainDb: chainDb,
+		db:      db,
+	}
+	table := rawdb.NewTable(chainDb, historyMetaPrefix)
+
+	return &HistoryIndexer{
+		db:      db,
+		indexer: core.NewChainIndexer(chainDb, table, backend, historySectionSize, historyConfirms, 0, "history"),
+	}
+}
+
+// Start starts indexing the given chain in the background.
+func (h *HistoryIndexer) Start(chain core.ChainIndexerChain) {
+	h.indexer.Start(chain)
+}
+
+// Close terminates the indexer.
+func (h *HistoryIndexer) Close() error {
+	return h.indexer.Close()
+}
+
+// Indexed returns the number of blocks included in the index.
+func (h *HistoryIndexer) Indexed() uint64 {
+	sections, _, _ := h.indexer.Sections()
+	return sections * historySectionSize
+}
+
+// Stats returns the statistics of the blocks [from, to].
+func (h *HistoryIndexer) Stats(from, to uint64) ([]*HistoricalStats, error) {
+	var result []*HistoricalStats
+	for n := from; n <= to; n++ {
+		stats, err := readHistoricalStats(h.db, n)
+		if err != nil {
+			return nil, fmt.Errorf("stats of block #%d: %v", n, err)
+		}
+		result = append(result, stats)
+	}
+	return result, nil
+}
+
+func historyKey(number uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, number)
+	return key
+}
+
+func readHistoricalStats(db ethdb.KeyValueReader, number uint64) (*HistoricalStats, error) {
+	blob, err := db.Get(historyKey(number))
+	if err != nil {
+		return nil, err
+	}
+	stats := new(HistoricalStats)
+	if err := rlp.DecodeBytes(blob, stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// historyIndexerBackend implements core.ChainIndexerBackend.
+type historyIndexerBackend struct {
+	chainDb ethdb.Database
+	db      ethdb.Database
+
+	batch ethdb.Batch
+}
+
+// Reset implements core.ChainIndexerBackend, removing the stats of a section
+// which is going to be reprocessed.
+func (b *historyIndexerBackend) Reset(ctx context.Context, section uint64, prevHead common.Hash) error {
+	b.batch = b.db.NewBatch()
+	for n := section * historySectionSize; n < (section+1)*historySectionSize; n++ {
+		b.batch.Delete(historyKey(n))
+	}
+	return nil
+}
+
+// Process implements core.ChainIndexerBackend, recording the statistics of a
+// block.
+func (b *historyIndexerBackend) Process(ctx context.Context, header *types.Header) error {
+	hash, number := header.Hash(), header.Number.Uint64()
+	stats := &HistoricalStats{
+		Number:      number,
+		TicketsHash: header.MixDigest,
+	}
+	if snap, err := datong.NewSnapshotFromHeader(header); err == nil {
+		stats.Tickets = uint64(snap.TicketNumber)
+	}
+	if number > 0 {
+		parent, err := readHistoricalStats(b.db, number-1)
+		if err != nil {
+			return fmt.Errorf("stats of block #%d: %v", number-1, err)
+		}
+		stats.OpenSwaps = parent.OpenSwaps
+	}
+	receipts := rawdb.ReadRawReceipts(b.chainDb, hash, number)
+	for _, receipt := range receipts {
+		for _, l := range receipt.Logs {
+			if l.Address != common.FSNCallAddress || len(l.Topics) == 0 {
+				continue
+			}
+			fn, ok := common.FsnCallFuncOfTopic(l.Topics[0])
+			if !ok {
+				continue
+			}
+			var data historyLogData
+			if err := json.Unmarshal(l.Data, &data); err != nil || data.Error != "" {
+				continue
+			}
+			stats.apply(fn, &data)
+		}
+	}
+	enc, err := rlp.EncodeToBytes(stats)
+	if err != nil {
+		return err
+	}
+	return b.batch.Put(historyKey(number), enc)
+}
+
+// apply adds the effect of a successful FSN call log to the stats.
+func (stats *HistoricalStats) apply(fn common.FSNCallFunc, data *historyLogData) {
+	switch fn {
+	case common.GenAssetFunc:
+		if data.Total != nil {
+			change := stats.supplyChange(data.AssetID)
+			change.Generated = true
+			change.Increased.Add(change.Increased, data.Total)
+		}
+	case common.AssetValueChangeFunc:
+		if data.Value != nil {
+			change := stats.supplyChange(data.AssetID)
+			if data.IsInc {
+				change.Increased.Add(change.Increased, data.Value)
+			} else {
+				change.Decreased.Add(change.Decreased, data.Value)
+			}
+		}
+	case common.MakeSwapFunc, common.MakeMultiSwapFunc:
+		stats.OpenSwaps++
+		stats.MadeSwaps++
+	case common.RecallSwapFunc, common.RecallMultiSwapFunc:
+		stats.closeSwap()
+	case common.TakeSwapFunc, common.TakeMultiSwapFunc:
+		if data.Deleted == "true" {
+			stats.closeSwap()
+		}
+	}
+}
+
+func (stats *HistoricalStats) supplyChange(assetID common.Hash) *AssetSupplyChange {
+	for _, change := range stats.SupplyChanges {
+		if change.AssetID == assetID {
+			return change
+		}
+	}
+	change := &AssetSupplyChange{
+		AssetID:   assetID,
+		Increased: new(big.Int),
+		Decreased: new(big.Int),
+	}
+	stats.SupplyChanges = append(stats.SupplyChanges, change)
+	return change
+}
+
+func (stats *HistoricalStats) closeSwap() {
+	if stats.OpenSwaps > 0 {
+		stats.OpenSwaps--
+	}
+	stats.ClosedSwaps++
+}
+
+// Commit implements core.ChainIndexerBackend, writing out the stats of the
+// section.
+func (b *historyIndexerBackend) Commit() error {
+	return b.batch.Write()
+}
@@ -0,0 +1,105 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnindex
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/types"
+)
+
+func fsnCallLog(t *testing.T, fn common.FSNCallFunc, data map[string]interface{}) *types.Log {
+	blob, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("failed to encode log data: %v", err)
+	}
+	topic := common.Hash{}
+	topic[common.HashLength-1] = uint8(fn)
+	return &types.Log{Address: common.FSNCallAddress, Topics: []common.Hash{topic}, Data: blob}
+}
+
+func TestHistoricalStats(t *testing.T) {
+	var (
+		chainDb = rawdb.NewMemoryDatabase()
+		indexer = &HistoryIndexer{db: rawdb.NewTable(chainDb, historyTablePrefix)}
+		backend = &historyIndexerBackend{chainDb: chainDb, db: indexer.db}
+
+		asset = common.HexToHash("0xa5")
+	)
+	process := func(number uint64, logs ...*types.Log) {
+		header := &types.Header{Number: new(big.Int).SetUint64(number), MixDigest: common.BigToHash(new(big.Int).SetUint64(number))}
+		rawdb.WriteReceipts(chainDb, header.Hash(), number, types.Receipts{{Logs: logs}})
+		if err := backend.Reset(context.Background(), number, common.Hash{}); err != nil {
+			t.Fatalf("reset failed: %v", err)
+		}
+		if err := backend.Process(context.Background(), header); err != nil {
+			t.Fatalf("process failed: %v", err)
+		}
+		if err := backend.Commit(); err != nil {
+			t.Fatalf("commit failed: %v", err)
+		}
+	}
+	process(0)
+	process(1,
+		fsnCallLog(t, common.GenAssetFunc, map[string]interface{}{"AssetID": asset, "Total": big.NewInt(1000)}),
+		fsnCallLog(t, common.AssetValueChangeFunc, map[string]interface{}{"AssetID": asset, "Value": big.NewInt(300), "IsInc": false}),
+		fsnCallLog(t, common.MakeSwapFunc, map[string]interface{}{"SwapID": common.HexToHash("0x01")}),
+		fsnCallLog(t, common.MakeMultiSwapFunc, map[string]interface{}{"SwapID": common.HexToHash("0x02")}),
+		fsnCallLog(t, common.MakeSwapFunc, map[string]interface{}{"Error": "Swap already exist"}),
+	)
+	process(2,
+		fsnCallLog(t, common.TakeSwapFunc, map[string]interface{}{"SwapID": common.HexToHash("0x01"), "Deleted": "false"}),
+		fsnCallLog(t, common.RecallMultiSwapFunc, map[string]interface{}{"SwapID": common.HexToHash("0x02")}),
+	)
+
+	stats, err := indexer.Stats(0, 2)
+	if err != nil {
+		t.Fatalf("failed to read stats: %v", err)
+	}
+	if len(stats) != 3 {
+		t.Fatalf("stats length mismatch: have %d, want 3", len(stats))
+	}
+	if stats[1].TicketsHash != common.BigToHash(big.NewInt(1)) {
+		t.Errorf("tickets hash mismatch: have %x", stats[1].TicketsHash)
+	}
+	if len(stats[1].SupplyChanges) != 1 {
+		t.Fatalf("supply changes mismatch: have %d, want 1", len(stats[1].SupplyChanges))
+	}
+	if change := stats[1].SupplyChanges[0]; !change.Generated || change.Increased.Int64() != 1000 || change.Decreased.Int64() != 300 {
+		t.Errorf("supply change mismatch: %+v", change)
+	}
+	if stats[1].OpenSwaps != 2 || stats[1].MadeSwaps != 2 {
+		t.Errorf("swaps of block 1 mismatch: open %d, made %d", stats[1].OpenSwaps, stats[1].MadeSwaps)
+	}
+	if stats[2].OpenSwaps != 1 || stats[2].ClosedSwaps != 1 {
+		t.Errorf("swaps of block 2 mismatch: open %d, closed %d", stats[2].OpenSwaps, stats[2].ClosedSwaps)
+	}
+
+	// Reprocessing a block after a reorg replaces its stats
+	process(2, fsnCallLog(t, common.TakeSwapFunc, map[string]interface{}{"SwapID": common.HexToHash("0x01"), "Deleted": "true"}))
+	if stats, _ := indexer.Stats(2, 2); len(stats) != 1 || stats[0].OpenSwaps != 1 || stats[0].ClosedSwaps != 1 {
+		t.Errorf("stats after reorg mismatch: %+v", stats[0])
+	}
+	if _, err := indexer.Stats(2, 3); err == nil {
+		t.Errorf("expected error for unindexed block")
+	}
+}
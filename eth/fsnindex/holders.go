@@ -0,0 +1,343 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package fsnindex implements optional indexes over the fusion specific
+// chain data which are not needed for consensus.
+package fsnindex
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/ethdb"
+	"github.com/FusionFoundation/go-fusion/log"
+	"github.com/FusionFoundation/go-fusion/rlp"
+	"github.com/FusionFoundation/go-fusion/trie"
+)
+
+const (
+	// chainHeadChanSize is the size of channel listening to ChainHeadEvent.
+	chainHeadChanSize = 10
+
+	// holdersLogInterval is the time between progress logs of a long running
+	// index operation.
+	holdersLogInterval = 8 * time.Second
+)
+
+var (
+	holdersTablePrefix = "fsnindex-holders-" // holdersTablePrefix + holderKeyPrefix + assetID + address -> nil
+	holderKeyPrefix    = []byte("h")
+	holdersHeadKey     = []byte("head") // holdersTablePrefix + holdersHeadKey -> rlp(holdersHead)
+
+	errIndexerStopped = errors.New("holders indexer stopped")
+)
+
+// holdersHead is the last block whose state has been included in the index.
+type holdersHead struct {
+	Number uint64
+	Hash   common.Hash
+}
+
+// HoldersIndexer tracks the addresses which hold each asset. The index is a
+// superset of the actual holders: entries are only ever added, so readers
+// must check the balance of every returned address against the state.
+//
+// The index follows the chain head by diffing the account tries of
+// consecutive blocks. When the state needed for that is not available any
+// more (first start, or a pruned node which fell behind) the index is
+// rebuilt from the state of the current head.
+type HoldersIndexer struct {
+	chainDb ethdb.Database
+	db      ethdb.Database
+	chain   *core.BlockChain
+
+	lock sync.RWMutex
+	head holdersHead
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewHoldersIndexer creates an asset holders indexer storing its data in the
+// given chain database.
+func NewHoldersIndexer(chainDb ethdb.Database, chain *core.BlockChain) *HoldersIndexer {
+	h := &HoldersIndexer{
+		chainDb: chainDb,
+		db:      rawdb.NewTable(chainDb, holdersTablePrefix),
+		chain:   chain,
+		quit:    make(chan struct{}),
+	}
+	if blob, err := h.db.Get(holdersHeadKey); err == nil {
+		if err := rlp.DecodeBytes(blob, &h.head); err != nil {
+			log.Error("Invalid asset holders index head", "err", err)
+			h.head = holdersHead{}
+		}
+	}
+	return h
+}
+
+// Start starts following the chain head in the background.
+func (h *HoldersIndexer) Start() {
+	h.wg.Add(1)
+	go h.loop()
+}
+
+// Stop terminates the indexer, waiting for the running update to finish.
+func (h *HoldersIndexer) Stop() {
+	close(h.quit)
+	h.wg.Wait()
+}
+
+// Head returns the number and hash of the last indexed block.
+func (h *HoldersIndexer) Head() (uint64, common.Hash) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	return h.head.Number, h.head.Hash
+}
+
+// ForEachHolder calls fn for every indexed holder of assetID in ascending
+// address order, until fn returns false.
+func (h *HoldersIndexer) ForEachHolder(assetID common.Hash, fn func(common.Address) bool) error {
+	prefix := append(append([]byte{}, holderKeyPrefix...), assetID[:]...)
+	it := h.db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+
+	for it.Next() {
+		// Keys are returned with the table prefix included
+		key := it.Key()
+		if len(key) != len(holdersTablePrefix)+len(prefix)+common.AddressLength {
+			continue
+		}
+		if !fn(common.BytesToAddress(key[len(key)-common.AddressLength:])) {
+			break
+		}
+	}
+	return it.Error()
+}
+
+func (h *HoldersIndexer) loop() {
+	defer h.wg.Done()
+
+	headCh := make(chan core.ChainHeadEvent, chainHeadChanSize)
+	sub := h.chain.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	h.update(h.chain.CurrentBlock())
+	for {
+		select {
+		case ev := <-headCh:
+			h.update(ev.Block)
+		case <-sub.Err():
+			return
+		case <-h.quit:
+			return
+		}
+	}
+}
+
+// update brings the index up to the given block, rebuilding it from the
+// current head state if it cannot be followed from the last indexed block.
+func (h *HoldersIndexer) update(head *types.Block) {
+	number, hash := h.Head()
+	if number >= head.NumberU64() && rawdb.ReadCanonicalHash(h.chainDb, number) == hash {
+		return
+	}
+	var err error
+	if hash == (common.Hash{}) {
+		err = h.rebuild(head)
+	} else if err = h.follow(head); err != nil && err != errIndexerStopped {
+		log.Warn("Asset holders index cannot follow the chain, rebuilding", "indexed", number, "head", head.NumberU64(), "err", err)
+		err = h.rebuild(h.chain.CurrentBlock())
+	}
+	if err != nil && err != errIndexerStopped {
+		log.Error("Failed to update asset holders index", "err", err)
+	}
+}
+
+// follow indexes the accounts changed by every canonical block between the
+// last indexed block and head. If the last indexed block was reorged out, it
+// restarts from the common ancestor with the canonical chain.
+func (h *HoldersIndexer) follow(head *types.Block) error {
+	number, hash := h.Head()
+	for rawdb.ReadCanonicalHash(h.chainDb, number) != hash {
+		header := h.chain.GetHeader(hash, number)
+		if header == nil || number == 0 {
+			return errors.New("indexed block not found")
+		}
+		number, hash = number-1, header.ParentHash
+	}
+	parent := h.chain.GetBlock(hash, number)
+	if parent == nil {
+		return errors.New("indexed block not found")
+	}
+	var (
+		batch  = h.db.NewBatch()
+		logged = time.Now()
+	)
+	for n := number + 1; n <= head.NumberU64(); n++ {
+		select {
+		case <-h.quit:
+			return errIndexerStopped
+		default:
+		}
+		block := h.chain.GetBlockByNumber(n)
+		if block == nil || block.ParentHash() != parent.Hash() {
+			// The canonical chain changed underneath, the next head event
+			// will continue from the last written block
+			break
+		}
+		if err := h.indexDiff(parent, block, batch); err != nil {
+			return err
+		}
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := h.writeHead(batch, block); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		if time.Since(logged) > holdersLogInterval {
+			log.Info("Indexing asset holders", "number", n, "head", head.NumberU64())
+			logged = time.Now()
+		}
+		parent = block
+	}
+	if parent.NumberU64() > number {
+		return h.writeHead(batch, parent)
+	}
+	return nil
+}
+
+// rebuild indexes every account in the state of the given block.
+func (h *HoldersIndexer) rebuild(block *types.Block) error {
+	start := time.Now()
+	log.Info("Rebuilding asset holders index", "number", block.NumberU64(), "root", block.Root())
+
+	db := h.chain.StateCache()
+	tr, err := db.OpenTrie(block.Root())
+	if err != nil {
+		return err
+	}
+	batch := h.db.NewBatch()
+	if err := h.indexAccounts(tr, tr.NodeIterator(nil), batch); err != nil {
+		return err
+	}
+	if err := h.writeHead(batch, block); err != nil {
+		return err
+	}
+	log.Info("Rebuilt asset holders index", "number", block.NumberU64(), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// indexDiff indexes the accounts which changed between parent and block.
+func (h *HoldersIndexer) indexDiff(parent, block *types.Block, batch ethdb.Batch) error {
+	if parent.Root() == block.Root() {
+		return nil
+	}
+	db := h.chain.StateCache()
+	oldTrie, err := db.OpenTrie(parent.Root())
+	if err != nil {
+		return err
+	}
+	newTrie, err := db.OpenTrie(block.Root())
+	if err != nil {
+		return err
+	}
+	it, _ := trie.NewDifferenceIterator(oldTrie.NodeIterator(nil), newTrie.NodeIterator(nil))
+	return h.indexAccounts(newTrie, it, batch)
+}
+
+// indexAccounts adds an entry for every asset with a balance or a time lock
+// balance of the accounts iterated by it.
+func (h *HoldersIndexer) indexAccounts(tr state.Trie, it trie.NodeIterator, batch ethdb.Batch) error {
+	var (
+		accounts = trie.NewIterator(it)
+		missing  int
+		logged   = time.Now()
+	)
+	for accounts.Next() {
+		var account state.Account
+		if err := rlp.DecodeBytes(accounts.Value, &account); err != nil {
+			return err
+		}
+		preimage := tr.GetKey(accounts.Key)
+		if preimage == nil {
+			missing++
+			continue
+		}
+		address := common.BytesToAddress(preimage)
+		for i, assetID := range account.BalancesHash {
+			if account.BalancesVal[i].Sign() > 0 {
+				batch.Put(holderKey(assetID, address), nil)
+			}
+		}
+		for i, assetID := range account.TimeLockBalancesHash {
+			if !account.TimeLockBalancesVal[i].IsEmpty() {
+				batch.Put(holderKey(assetID, address), nil)
+			}
+		}
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		if time.Since(logged) > holdersLogInterval {
+			select {
+			case <-h.quit:
+				return errIndexerStopped
+			default:
+			}
+			log.Info("Indexing asset holders", "at", common.BytesToHash(accounts.Key))
+			logged = time.Now()
+		}
+	}
+	if missing > 0 {
+		log.Warn("Asset holders index incomplete due to missing preimages", "missing", missing)
+	}
+	return accounts.Err
+}
+
+// writeHead flushes batch together with the new index head.
+func (h *HoldersIndexer) writeHead(batch ethdb.Batch, block *types.Block) error {
+	head := holdersHead{Number: block.NumberU64(), Hash: block.Hash()}
+	enc, err := rlp.EncodeToBytes(&head)
+	if err != nil {
+		return err
+	}
+	batch.Put(holdersHeadKey, enc)
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	h.lock.Lock()
+	h.head = head
+	h.lock.Unlock()
+	return nil
+}
+
+// holderKey = holderKeyPrefix + assetID + address
+func holderKey(assetID common.Hash, address common.Address) []byte {
+	key := make([]byte, 0, len(holderKeyPrefix)+common.HashLength+common.AddressLength)
+	key = append(key, holderKeyPrefix...)
+	key = append(key, assetID[:]...)
+	return append(key, address[:]...)
+}
@@ -0,0 +1,116 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnindex
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/trie"
+)
+
+func TestHoldersIndex(t *testing.T) {
+	var (
+		chainDb = rawdb.NewMemoryDatabase()
+		sdb     = state.NewDatabase(chainDb)
+		indexer = NewHoldersIndexer(chainDb, nil)
+
+		fsn    = common.SystemAssetID
+		asset  = common.HexToHash("0xa5")
+		first  = common.HexToAddress("0x01")
+		second = common.HexToAddress("0x02")
+		third  = common.HexToAddress("0x03")
+	)
+	commit := func(statedb *state.StateDB) common.Hash {
+		root, err := statedb.Commit(false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sdb.TrieDB().Commit(root, false); err != nil {
+			t.Fatal(err)
+		}
+		return root
+	}
+	holders := func(assetID common.Hash) []common.Address {
+		var addrs []common.Address
+		if err := indexer.ForEachHolder(assetID, func(addr common.Address) bool {
+			addrs = append(addrs, addr)
+			return true
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return addrs
+	}
+
+	// the first state is indexed as a whole
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, sdb)
+	statedb.AddBalance(second, fsn, big.NewInt(10))
+	statedb.AddBalance(first, fsn, big.NewInt(5))
+	statedb.AddTimeLockBalance(third, asset, common.GetTimeLock(big.NewInt(1), 100, 200), big.NewInt(1), 100)
+	parent := commit(statedb)
+
+	tr, err := sdb.OpenTrie(parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch := indexer.db.NewBatch()
+	if err := indexer.indexAccounts(tr, tr.NodeIterator(nil), batch); err != nil {
+		t.Fatal(err)
+	}
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), Root: parent})
+	if err := indexer.writeHead(batch, block); err != nil {
+		t.Fatal(err)
+	}
+	if have, want := holders(fsn), []common.Address{first, second}; !reflect.DeepEqual(have, want) {
+		t.Errorf("FSN holders: have %x, want %x", have, want)
+	}
+	if have, want := holders(asset), []common.Address{third}; !reflect.DeepEqual(have, want) {
+		t.Errorf("time locked asset holders: have %x, want %x", have, want)
+	}
+
+	// the next block only indexes the changed accounts, holders are never
+	// removed from the index
+	statedb, _ = state.New(parent, common.Hash{}, sdb)
+	statedb.SubBalance(first, fsn, big.NewInt(5))
+	statedb.AddBalance(third, fsn, big.NewInt(1))
+	root := commit(statedb)
+
+	oldTrie, _ := sdb.OpenTrie(parent)
+	newTrie, _ := sdb.OpenTrie(root)
+	it, _ := trie.NewDifferenceIterator(oldTrie.NodeIterator(nil), newTrie.NodeIterator(nil))
+	batch = indexer.db.NewBatch()
+	if err := indexer.indexAccounts(newTrie, it, batch); err != nil {
+		t.Fatal(err)
+	}
+	if err := indexer.writeHead(batch, types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2), Root: root})); err != nil {
+		t.Fatal(err)
+	}
+	if have, want := holders(fsn), []common.Address{first, second, third}; !reflect.DeepEqual(have, want) {
+		t.Errorf("FSN holders after the diff: have %x, want %x", have, want)
+	}
+
+	// the head survives a restart
+	number, hash := NewHoldersIndexer(chainDb, nil).Head()
+	if number != 2 || hash == (common.Hash{}) {
+		t.Errorf("reloaded head: have #%d %x, want #2", number, hash)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnindex
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+// MarketSide aggregates the open swaps selling one asset of a pair
+type MarketSide struct {
+	OpenSwaps  int    `json:"openSwaps"`
+	OpenAmount string `json:"openAmount"` // total amount offered by the open swaps
+	BestPrice  string `json:"bestPrice"`  // quote asset per base asset, empty without open swaps
+}
+
+// MarketStats wacom
+type MarketStats struct {
+	BlockNumber  hexutil.Uint64 `json:"blockNumber"`
+	BaseAssetID  common.Hash    `json:"baseAssetID"`
+	QuoteAssetID common.Hash    `json:"quoteAssetID"`
+	Asks         MarketSide     `json:"asks"` // swaps selling the base asset
+	Bids         MarketSide     `json:"bids"` // swaps selling the quote asset
+	Takes        int            `json:"takes"`
+	BaseVolume   string         `json:"baseVolume"`  // base asset traded by the takes
+	QuoteVolume  string         `json:"quoteVolume"` // quote asset traded by the takes
+}
+
+// MarketStats returns the open swaps between base and quote which have not
+// expired at time now, and the volume of the swaps taken in blocks
+// [from, to].
+func (s *SwapIndexer) MarketStats(base, quote common.Hash, now, from, to uint64) (*MarketStats, error) {
+	var (
+		asks, bids        marketSide
+		baseVol, quoteVol = new(big.Int), new(big.Int)
+		takes             int
+		prefix            = append(append([]byte{}, swapOpenPrefix...), swapPairKey(base, quote)[len(swapPairPrefix):]...)
+		it                = s.db.NewIteratorWithPrefix(prefix)
+	)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		swapID := common.BytesToHash(key[len(key)-common.HashLength:])
+		if blob, err := s.db.Get(swapExpiryKey(swapID)); err == nil && len(blob) == 8 && binary.BigEndian.Uint64(blob) <= now {
+			continue
+		}
+		blob, err := s.db.Get(append(append([]byte{}, swapInfoPrefix...), swapID[:]...))
+		if err != nil {
+			return nil, err
+		}
+		info := new(swapInfo)
+		if err := rlp.DecodeBytes(blob, info); err != nil {
+			return nil, err
+		}
+		if info.MinFromAmount.Sign() <= 0 || info.MinToAmount.Sign() <= 0 {
+			continue
+		}
+		if info.FromAssetID == base {
+			asks.add(info, new(big.Rat).SetFrac(info.MinToAmount, info.MinFromAmount), true)
+		} else {
+			bids.add(info, new(big.Rat).SetFrac(info.MinFromAmount, info.MinToAmount), false)
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	err := s.forEachRecord(swapPairKey(base, quote), from, to, func(record *SwapRecord) error {
+		if record.Action != SwapActionTake {
+			return nil
+		}
+		takes++
+		if record.FromAssetID == base {
+			baseVol.Add(baseVol, record.FromAmount)
+			quoteVol.Add(quoteVol, record.ToAmount)
+		} else {
+			baseVol.Add(baseVol, record.ToAmount)
+			quoteVol.Add(quoteVol, record.FromAmount)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MarketStats{
+		BlockNumber:  hexutil.Uint64(to),
+		BaseAssetID:  base,
+		QuoteAssetID: quote,
+		Asks:         asks.toDisplay(),
+		Bids:         bids.toDisplay(),
+		Takes:        takes,
+		BaseVolume:   baseVol.String(),
+		QuoteVolume:  quoteVol.String(),
+	}, nil
+}
+
+// marketSide accumulates one side of a market
+type marketSide struct {
+	count  int
+	amount big.Int
+	best   *big.Rat
+}
+
+// add accounts for an open swap with the given price, the best ask is the
+// lowest price and the best bid the highest.
+func (m *marketSide) add(info *swapInfo, price *big.Rat, ask bool) {
+	m.count++
+	m.amount.Add(&m.amount, new(big.Int).Mul(info.MinFromAmount, info.Remaining))
+	if m.best == nil || (ask && price.Cmp(m.best) < 0) || (!ask && price.Cmp(m.best) > 0) {
+		m.best = price
+	}
+}
+
+func (m *marketSide) toDisplay() MarketSide {
+	side := MarketSide{OpenSwaps: m.count, OpenAmount: m.amount.String()}
+	if m.best != nil {
+		side.BestPrice = m.best.RatString()
+	}
+	return side
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnindex
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+)
+
+func TestMarketStats(t *testing.T) {
+	var (
+		chainDb = rawdb.NewMemoryDatabase()
+		indexer = &SwapIndexer{db: rawdb.NewTable(chainDb, swapsTablePrefix)}
+		backend = &swapIndexerBackend{chainDb: chainDb, db: indexer.db}
+
+		maker = common.HexToAddress("0x01")
+		taker = common.HexToAddress("0x02")
+		fsn   = common.SystemAssetID
+		asset = common.HexToHash("0xa5")
+	)
+	process := func(section uint64, fn common.FSNCallFunc, data *swapLogData, sender common.Address) {
+		if err := backend.Reset(context.Background(), section, common.Hash{}); err != nil {
+			t.Fatalf("reset failed: %v", err)
+		}
+		if data != nil {
+			record := backend.newRecord(fn, data, sender)
+			record.BlockNumber = section
+			if err := backend.writeRecord(record); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+		}
+		if err := backend.Commit(); err != nil {
+			t.Fatalf("commit failed: %v", err)
+		}
+	}
+	makeSwap := func(section uint64, id byte, from, to common.Hash, minFrom, minTo, size int64, expiry uint64) {
+		process(section, common.MakeSwapFunc, &swapLogData{
+			SwapID:        common.BytesToHash([]byte{id}),
+			FromAssetID:   from,
+			ToAssetID:     to,
+			MinFromAmount: big.NewInt(minFrom),
+			MinToAmount:   big.NewInt(minTo),
+			SwapSize:      big.NewInt(size),
+			FromEndTime:   expiry,
+			ToEndTime:     common.TimeLockForever,
+		}, maker)
+	}
+	// Asks selling the asset for FSN at 2 and 3 FSN, one expiring at 500
+	makeSwap(1, 1, asset, fsn, 1, 2, 10, common.TimeLockForever)
+	makeSwap(2, 2, asset, fsn, 1, 3, 5, common.TimeLockForever)
+	makeSwap(3, 3, asset, fsn, 1, 1, 5, 500)
+	// Bids selling FSN for the asset at 1.5 FSN, and a recalled one at 1.8
+	makeSwap(4, 4, fsn, asset, 3, 2, 4, common.TimeLockForever)
+	makeSwap(5, 5, fsn, asset, 9, 5, 4, common.TimeLockForever)
+	process(6, common.RecallSwapFunc, &swapLogData{SwapID: common.BytesToHash([]byte{5})}, maker)
+	// Takes of 4 asks and 1 bid
+	process(7, common.TakeSwapFunc, &swapLogData{SwapID: common.BytesToHash([]byte{1}), Size: big.NewInt(4)}, taker)
+	process(8, common.TakeSwapFunc, &swapLogData{SwapID: common.BytesToHash([]byte{4}), Size: big.NewInt(4)}, taker)
+
+	stats, err := indexer.MarketStats(asset, fsn, 1000, 7, 8)
+	if err != nil {
+		t.Fatalf("market stats failed: %v", err)
+	}
+	if stats.Asks.OpenSwaps != 2 || stats.Asks.OpenAmount != "11" || stats.Asks.BestPrice != "2" {
+		t.Errorf("asks mismatch: %+v", stats.Asks)
+	}
+	if stats.Bids.OpenSwaps != 0 || stats.Bids.BestPrice != "" {
+		t.Errorf("bids mismatch: %+v", stats.Bids)
+	}
+	if stats.Takes != 2 || stats.BaseVolume != "12" || stats.QuoteVolume != "20" {
+		t.Errorf("volume mismatch: %d takes, %s/%s", stats.Takes, stats.BaseVolume, stats.QuoteVolume)
+	}
+	checkOpen := func(now uint64, want int) {
+		t.Helper()
+		if ids, err := indexer.OpenSwaps(maker, now); err != nil || len(ids) != want {
+			t.Errorf("open swaps mismatch: have %d (err %v), want %d", len(ids), err, want)
+		}
+	}
+	checkOpen(1000, 2)
+
+	// Reverting the bid take and the recall reopens the bids, and the
+	// swap expiring at 500 is open before it expires
+	process(8, 0, nil, common.Address{})
+	process(7, 0, nil, common.Address{})
+	process(6, 0, nil, common.Address{})
+	stats, err = indexer.MarketStats(asset, fsn, 400, 0, 8)
+	if err != nil {
+		t.Fatalf("market stats failed: %v", err)
+	}
+	if stats.Asks.OpenSwaps != 3 || stats.Asks.OpenAmount != "20" || stats.Asks.BestPrice != "1" {
+		t.Errorf("asks mismatch after reset: %+v", stats.Asks)
+	}
+	if stats.Bids.OpenSwaps != 2 || stats.Bids.OpenAmount != "48" || stats.Bids.BestPrice != "9/5" {
+		t.Errorf("bids mismatch after reset: %+v", stats.Bids)
+	}
+	if stats.Takes != 0 {
+		t.Errorf("takes mismatch after reset: have %d, want 0", stats.Takes)
+	}
+	checkOpen(400, 5)
+
+	// Reverting a make removes the swap
+	process(5, 0, nil, common.Address{})
+	if stats, _ := indexer.MarketStats(asset, fsn, 400, 0, 8); stats.Bids.OpenSwaps != 1 || stats.Bids.BestPrice != "3/2" {
+		t.Errorf("bids mismatch after make reset: %+v", stats.Bids)
+	}
+	checkOpen(400, 4)
+}
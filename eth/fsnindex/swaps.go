@@ -0,0 +1,628 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnindex
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/ethdb"
+	"github.com/FusionFoundation/go-fusion/log"
+	"github.com/FusionFoundation/go-fusion/params"
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+const (
+	// swapsSectionSize is the number of blocks in one swap history section.
+	// Every block is its own section so that the history follows the head.
+	swapsSectionSize = 1
+
+	// swapsConfirms is the number of confirmations before a block is indexed,
+	// reorgs are handled by Reset removing the records of the section.
+	swapsConfirms = 0
+
+	// swapsVersion is the version of the swap index data, the index is
+	// rebuilt when it was created by another version.
+	swapsVersion = 2
+)
+
+var (
+	swapsTablePrefix = "fsnindex-swaps-"      // swapsTablePrefix + key -> data
+	swapsMetaPrefix  = "fsnindex-swaps-meta-" // chain indexer metadata
+
+	swapRecordPrefix  = []byte("r") // swapRecordPrefix + num (uint64 big endian) + log index (uint32 big endian) -> rlp(SwapRecord)
+	swapPairPrefix    = []byte("p") // swapPairPrefix + assetID + assetID + num + log index -> nil
+	swapAddressPrefix = []byte("a") // swapAddressPrefix + address + num + log index -> nil
+	swapInfoPrefix    = []byte("s") // swapInfoPrefix + swapID -> rlp(swapInfo)
+	swapOpenPrefix    = []byte("o") // swapOpenPrefix + assetID + assetID + swapID -> nil
+	swapExpiryPrefix  = []byte("e") // swapExpiryPrefix + swapID -> expiry time (uint64 big endian)
+	swapOwnerPrefix   = []byte("w") // swapOwnerPrefix + owner + swapID -> nil, for open swaps
+	swapsVersionKey   = []byte("v") // swapsVersionKey -> swapsVersion (uint64 big endian)
+
+	errTooManySwapRecords = errors.New("too many swap records")
+)
+
+// Swap history actions
+const (
+	SwapActionMake   = "MakeSwap"
+	SwapActionTake   = "TakeSwap"
+	SwapActionRecall = "RecallSwap"
+)
+
+// SwapRecord wacom
+type SwapRecord struct {
+	Action        string
+	SwapID        common.Hash
+	Owner         common.Address
+	Taker         common.Address // zero unless Action is TakeSwap
+	FromAssetID   common.Hash
+	ToAssetID     common.Hash
+	MinFromAmount *big.Int `json:",string"`
+	MinToAmount   *big.Int `json:",string"`
+	Size          *big.Int `json:",string"` // made, taken or recalled swap size
+	FromAmount    *big.Int `json:",string"` // MinFromAmount * Size
+	ToAmount      *big.Int `json:",string"` // MinToAmount * Size
+	BlockNumber   uint64
+	TxHash        common.Hash
+	LogIndex      uint
+}
+
+// swapRecordRLP is the storage encoding of SwapRecord, the amounts are
+// derived from the sizes when decoding.
+type swapRecordRLP struct {
+	Action        string
+	SwapID        common.Hash
+	Owner         common.Address
+	Taker         common.Address
+	FromAssetID   common.Hash
+	ToAssetID     common.Hash
+	MinFromAmount *big.Int
+	MinToAmount   *big.Int
+	Size          *big.Int
+	BlockNumber   uint64
+	TxHash        common.Hash
+	LogIndex      uint
+}
+
+// swapInfo is what the index remembers of a made swap to be able to record
+// its takes and recalls.
+type swapInfo struct {
+	Owner         common.Address
+	FromAssetID   common.Hash
+	ToAssetID     common.Hash
+	MinFromAmount *big.Int
+	MinToAmount   *big.Int
+	Remaining     *big.Int
+}
+
+// swapLogData is the part of the MakeSwap, TakeSwap and RecallSwap logs
+// needed by the index.
+type swapLogData struct {
+	SwapID        common.Hash
+	FromAssetID   common.Hash
+	ToAssetID     common.Hash
+	MinFromAmount *big.Int
+	MinToAmount   *big.Int
+	SwapSize      *big.Int
+	FromEndTime   uint64
+	ToEndTime     uint64
+	Size          *big.Int
+	Error         string
+}
+
+// SwapIndexer records every successful MakeSwap, TakeSwap and RecallSwap and
+// indexes them by asset pair and by participant address.
+type SwapIndexer struct {
+	db      ethdb.Database
+	indexer *core.ChainIndexer
+}
+
+// NewSwapIndexer creates a swap history indexer storing its data in the
+// given chain database.
+func NewSwapIndexer(chainDb ethdb.Database, config *params.ChainConfig) *SwapIndexer {
+	db := rawdb.NewTable(chainDb, swapsTablePrefix)
+	if err := checkSwapsVersion(chainDb, db); err != nil {
+		log.Error("Failed to reset the swap index", "err", err)
+	}
+	backend := &swapIndexerBackend{
+		chainDb: chainDb,
+		db:      db,
+		config:  config,
+	}
+	table := rawdb.NewTable(chainDb, swapsMetaPrefix)
+
+	return &SwapIndexer{
+		db:      db,
+		indexer: core.NewChainIndexer(chainDb, table, backend, swapsSectionSize, swapsConfirms, 0, "swaps"),
+	}
+}
+
+// Start starts indexing the given chain in the background.
+func (s *SwapIndexer) Start(chain core.ChainIndexerChain) {
+	s.indexer.Start(chain)
+}
+
+// Close terminates the indexer.
+func (s *SwapIndexer) Close() error {
+	return s.indexer.Close()
+}
+
+// Indexed returns the number of blocks included in the index.
+func (s *SwapIndexer) Indexed() uint64 {
+	sections, _, _ := s.indexer.Sections()
+	return sections * swapsSectionSize
+}
+
+// PairHistory returns the records of the swaps between the two assets, in
+// either direction, made, taken or recalled in blocks [from, to].
+func (s *SwapIndexer) PairHistory(a, b common.Hash, from, to uint64, limit int) ([]*SwapRecord, error) {
+	return s.history(swapPairKey(a, b), from, to, limit)
+}
+
+// AddressHistory returns the records of the swaps made, taken or recalled by
+// address in blocks [from, to].
+func (s *SwapIndexer) AddressHistory(address common.Address, from, to uint64, limit int) ([]*SwapRecord, error) {
+	return s.history(append(append([]byte{}, swapAddressPrefix...), address[:]...), from, to, limit)
+}
+
+// OpenSwaps returns the IDs of the swaps made by owner which are neither
+// taken, recalled nor expired at time now.
+func (s *SwapIndexer) OpenSwaps(owner common.Address, now uint64) ([]common.Hash, error) {
+	return openSwaps(s.db, owner, now)
+}
+
+// ReadOpenSwaps returns the IDs of the swaps made by owner which are open at
+// time now according to the swap index stored in chainDb, without starting
+// the indexer. Nothing is returned if the node never built the index.
+func ReadOpenSwaps(chainDb ethdb.Database, owner common.Address, now uint64) ([]common.Hash, error) {
+	return openSwaps(rawdb.NewTable(chainDb, swapsTablePrefix), owner, now)
+}
+
+func openSwaps(db ethdb.Database, owner common.Address, now uint64) ([]common.Hash, error) {
+	var (
+		ids    []common.Hash
+		prefix = append(append([]byte{}, swapOwnerPrefix...), owner[:]...)
+		it     = db.NewIteratorWithPrefix(prefix)
+	)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		swapID := common.BytesToHash(key[len(key)-common.HashLength:])
+		if blob, err := db.Get(swapExpiryKey(swapID)); err == nil && len(blob) == 8 && binary.BigEndian.Uint64(blob) <= now {
+			continue
+		}
+		ids = append(ids, swapID)
+	}
+	return ids, it.Error()
+}
+
+func (s *SwapIndexer) history(prefix []byte, from, to uint64, limit int) ([]*SwapRecord, error) {
+	var records []*SwapRecord
+	err := s.forEachRecord(prefix, from, to, func(record *SwapRecord) error {
+		if len(records) == limit {
+			return errTooManySwapRecords
+		}
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// forEachRecord calls fn for the records of the index entries with the given
+// prefix in blocks [from, to], stopping at the first error.
+func (s *SwapIndexer) forEachRecord(prefix []byte, from, to uint64, fn func(*SwapRecord) error) error {
+	it := s.db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+
+	for it.Next() {
+		// Keys are returned with the table prefix included
+		key := it.Key()
+		if len(key) != len(swapsTablePrefix)+len(prefix)+12 {
+			continue
+		}
+		pos := key[len(key)-12:]
+		number := binary.BigEndian.Uint64(pos[:8])
+		if number < from {
+			continue
+		}
+		if number > to {
+			break
+		}
+		record, err := s.readRecord(pos)
+		if err != nil {
+			return err
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+func (s *SwapIndexer) readRecord(pos []byte) (*SwapRecord, error) {
+	blob, err := s.db.Get(append(append([]byte{}, swapRecordPrefix...), pos...))
+	if err != nil {
+		return nil, err
+	}
+	return decodeSwapRecord(blob)
+}
+
+// swapIndexerBackend implements core.ChainIndexerBackend.
+type swapIndexerBackend struct {
+	chainDb ethdb.Database
+	db      ethdb.Database
+	config  *params.ChainConfig
+
+	batch    ethdb.Batch
+	swaps    map[common.Hash]*swapInfo // swap infos changed in the section, nil if removed
+	expiries map[common.Hash]uint64    // expiry times of the swaps made in the section
+}
+
+// Reset implements core.ChainIndexerBackend, removing the records of a
+// section which is going to be reprocessed.
+func (b *swapIndexerBackend) Reset(ctx context.Context, section uint64, prevHead common.Hash) error {
+	b.batch = b.db.NewBatch()
+	b.swaps = make(map[common.Hash]*swapInfo)
+	b.expiries = make(map[common.Hash]uint64)
+
+	start := make([]byte, 8)
+	for n := section * swapsSectionSize; n < (section+1)*swapsSectionSize; n++ {
+		binary.BigEndian.PutUint64(start, n)
+		if err := b.removeRecords(append(append([]byte{}, swapRecordPrefix...), start...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeRecords deletes the records of one block together with their index
+// entries, and reverts their effect on the swap infos.
+func (b *swapIndexerBackend) removeRecords(prefix []byte) error {
+	it := b.db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()[len(swapsTablePrefix):]
+		record, err := decodeSwapRecord(it.Value())
+		if err != nil {
+			return err
+		}
+		pos := key[len(swapRecordPrefix):]
+		b.batch.Delete(common.CopyBytes(key))
+		for _, k := range recordIndexKeys(record, pos) {
+			b.batch.Delete(k)
+		}
+		switch record.Action {
+		case SwapActionMake:
+			b.swaps[record.SwapID] = nil
+		case SwapActionTake:
+			if info := b.swapInfo(record.SwapID); info != nil {
+				info.Remaining = new(big.Int).Add(info.Remaining, record.Size)
+			}
+		case SwapActionRecall:
+			if info := b.swapInfo(record.SwapID); info != nil {
+				info.Remaining = record.Size
+			}
+		}
+	}
+	return it.Error()
+}
+
+// Process implements core.ChainIndexerBackend, recording the swap logs of a
+// block.
+func (b *swapIndexerBackend) Process(ctx context.Context, header *types.Header) error {
+	hash, number := header.Hash(), header.Number.Uint64()
+	block := rawdb.ReadBlock(b.chainDb, hash, number)
+	if block == nil {
+		return fmt.Errorf("block #%d [%x…] not found", number, hash[:4])
+	}
+	if len(block.Transactions()) == 0 {
+		return nil
+	}
+	receipts := rawdb.ReadRawReceipts(b.chainDb, hash, number)
+	if receipts == nil {
+		return fmt.Errorf("receipts of block #%d [%x…] not found", number, hash[:4])
+	}
+	if err := receipts.DeriveFields(b.config, hash, number, block.Transactions()); err != nil {
+		return err
+	}
+	signer := types.MakeSigner(b.config, header.Number)
+	for _, receipt := range receipts {
+		for _, l := range receipt.Logs {
+			if l.Address != common.FSNCallAddress || len(l.Topics) == 0 {
+				continue
+			}
+			fn, ok := common.FsnCallFuncOfTopic(l.Topics[0])
+			if !ok || fn != common.MakeSwapFunc && fn != common.TakeSwapFunc && fn != common.RecallSwapFunc {
+				continue
+			}
+			var data swapLogData
+			if err := json.Unmarshal(l.Data, &data); err != nil || data.Error != "" {
+				continue
+			}
+			sender, err := types.Sender(signer, block.Transactions()[l.TxIndex])
+			if err != nil {
+				return err
+			}
+			if record := b.newRecord(fn, &data, sender); record != nil {
+				record.BlockNumber, record.TxHash, record.LogIndex = number, l.TxHash, l.Index
+				if err := b.writeRecord(record); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// newRecord converts a swap log into a record, updating the swap infos.
+func (b *swapIndexerBackend) newRecord(fn common.FSNCallFunc, data *swapLogData, sender common.Address) *SwapRecord {
+	if fn == common.MakeSwapFunc {
+		if data.MinFromAmount == nil || data.MinToAmount == nil || data.SwapSize == nil {
+			return nil
+		}
+		b.swaps[data.SwapID] = &swapInfo{
+			Owner:         sender,
+			FromAssetID:   data.FromAssetID,
+			ToAssetID:     data.ToAssetID,
+			MinFromAmount: data.MinFromAmount,
+			MinToAmount:   data.MinToAmount,
+			Remaining:     data.SwapSize,
+		}
+		b.expiries[data.SwapID] = data.FromEndTime
+		if data.ToEndTime < data.FromEndTime {
+			b.expiries[data.SwapID] = data.ToEndTime
+		}
+		return &SwapRecord{
+			Action:        SwapActionMake,
+			SwapID:        data.SwapID,
+			Owner:         sender,
+			FromAssetID:   data.FromAssetID,
+			ToAssetID:     data.ToAssetID,
+			MinFromAmount: data.MinFromAmount,
+			MinToAmount:   data.MinToAmount,
+			Size:          data.SwapSize,
+		}
+	}
+	info := b.swapInfo(data.SwapID)
+	if info == nil {
+		return nil
+	}
+	record := &SwapRecord{
+		SwapID:        data.SwapID,
+		Owner:         info.Owner,
+		FromAssetID:   info.FromAssetID,
+		ToAssetID:     info.ToAssetID,
+		MinFromAmount: info.MinFromAmount,
+		MinToAmount:   info.MinToAmount,
+	}
+	switch fn {
+	case common.TakeSwapFunc:
+		if data.Size == nil {
+			return nil
+		}
+		record.Action, record.Taker, record.Size = SwapActionTake, sender, data.Size
+		info.Remaining = new(big.Int).Sub(info.Remaining, data.Size)
+	case common.RecallSwapFunc:
+		record.Action, record.Size = SwapActionRecall, info.Remaining
+		info.Remaining = new(big.Int)
+	}
+	return record
+}
+
+// swapInfo returns the info of a made swap, taking the changes of the
+// section being processed into account.
+func (b *swapIndexerBackend) swapInfo(swapID common.Hash) *swapInfo {
+	if info, ok := b.swaps[swapID]; ok {
+		return info
+	}
+	blob, err := b.db.Get(append(append([]byte{}, swapInfoPrefix...), swapID[:]...))
+	if err != nil {
+		return nil
+	}
+	info := new(swapInfo)
+	if err := rlp.DecodeBytes(blob, info); err != nil {
+		return nil
+	}
+	b.swaps[swapID] = info
+	return info
+}
+
+func (b *swapIndexerBackend) writeRecord(record *SwapRecord) error {
+	enc, err := rlp.EncodeToBytes(&swapRecordRLP{
+		Action:        record.Action,
+		SwapID:        record.SwapID,
+		Owner:         record.Owner,
+		Taker:         record.Taker,
+		FromAssetID:   record.FromAssetID,
+		ToAssetID:     record.ToAssetID,
+		MinFromAmount: record.MinFromAmount,
+		MinToAmount:   record.MinToAmount,
+		Size:          record.Size,
+		BlockNumber:   record.BlockNumber,
+		TxHash:        record.TxHash,
+		LogIndex:      record.LogIndex,
+	})
+	if err != nil {
+		return err
+	}
+	pos := recordPos(record.BlockNumber, record.LogIndex)
+	b.batch.Put(append(append([]byte{}, swapRecordPrefix...), pos...), enc)
+	for _, k := range recordIndexKeys(record, pos) {
+		b.batch.Put(k, nil)
+	}
+	return nil
+}
+
+// Commit implements core.ChainIndexerBackend, writing out the records and
+// the swap infos of the section.
+func (b *swapIndexerBackend) Commit() error {
+	for swapID, expiry := range b.expiries {
+		if b.swaps[swapID] != nil {
+			b.batch.Put(swapExpiryKey(swapID), encodeUint64(expiry))
+		}
+	}
+	for swapID, info := range b.swaps {
+		key := append(append([]byte{}, swapInfoPrefix...), swapID[:]...)
+		if info == nil {
+			// The index entries of a removed swap are found through its
+			// stored info
+			if blob, err := b.db.Get(key); err == nil {
+				old := new(swapInfo)
+				if err := rlp.DecodeBytes(blob, old); err == nil {
+					b.batch.Delete(swapOpenKey(old.FromAssetID, old.ToAssetID, swapID))
+					b.batch.Delete(swapOwnerKey(old.Owner, swapID))
+				}
+			}
+			b.batch.Delete(swapExpiryKey(swapID))
+			b.batch.Delete(key)
+			continue
+		}
+		enc, err := rlp.EncodeToBytes(info)
+		if err != nil {
+			return err
+		}
+		b.batch.Put(key, enc)
+		if info.Remaining.Sign() > 0 {
+			b.batch.Put(swapOpenKey(info.FromAssetID, info.ToAssetID, swapID), nil)
+			b.batch.Put(swapOwnerKey(info.Owner, swapID), nil)
+		} else {
+			b.batch.Delete(swapOpenKey(info.FromAssetID, info.ToAssetID, swapID))
+			b.batch.Delete(swapOwnerKey(info.Owner, swapID))
+		}
+	}
+	return b.batch.Write()
+}
+
+// checkSwapsVersion removes the swap index data and the indexer metadata if
+// they were created by another version of the index, so it is rebuilt.
+func checkSwapsVersion(chainDb, db ethdb.Database) error {
+	if blob, err := db.Get(swapsVersionKey); err == nil && len(blob) == 8 && binary.BigEndian.Uint64(blob) == swapsVersion {
+		return nil
+	}
+	// The metadata table prefix extends the data table prefix, so both are
+	// removed together
+	it := chainDb.NewIteratorWithPrefix([]byte(swapsTablePrefix))
+	defer it.Release()
+
+	batch := chainDb.NewBatch()
+	for it.Next() {
+		batch.Delete(common.CopyBytes(it.Key()))
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	return db.Put(swapsVersionKey, encodeUint64(swapsVersion))
+}
+
+func decodeSwapRecord(blob []byte) (*SwapRecord, error) {
+	var dec swapRecordRLP
+	if err := rlp.DecodeBytes(blob, &dec); err != nil {
+		return nil, err
+	}
+	return &SwapRecord{
+		Action:        dec.Action,
+		SwapID:        dec.SwapID,
+		Owner:         dec.Owner,
+		Taker:         dec.Taker,
+		FromAssetID:   dec.FromAssetID,
+		ToAssetID:     dec.ToAssetID,
+		MinFromAmount: dec.MinFromAmount,
+		MinToAmount:   dec.MinToAmount,
+		Size:          dec.Size,
+		FromAmount:    new(big.Int).Mul(dec.MinFromAmount, dec.Size),
+		ToAmount:      new(big.Int).Mul(dec.MinToAmount, dec.Size),
+		BlockNumber:   dec.BlockNumber,
+		TxHash:        dec.TxHash,
+		LogIndex:      dec.LogIndex,
+	}, nil
+}
+
+// recordPos = num (uint64 big endian) + log index (uint32 big endian)
+func recordPos(number uint64, index uint) []byte {
+	pos := make([]byte, 12)
+	binary.BigEndian.PutUint64(pos[:8], number)
+	binary.BigEndian.PutUint32(pos[8:], uint32(index))
+	return pos
+}
+
+// recordIndexKeys returns the pair and address index keys of a record.
+func recordIndexKeys(record *SwapRecord, pos []byte) [][]byte {
+	keys := [][]byte{
+		append(swapPairKey(record.FromAssetID, record.ToAssetID), pos...),
+		append(append(append([]byte{}, swapAddressPrefix...), record.Owner[:]...), pos...),
+	}
+	if record.Taker != (common.Address{}) && record.Taker != record.Owner {
+		keys = append(keys, append(append(append([]byte{}, swapAddressPrefix...), record.Taker[:]...), pos...))
+	}
+	return keys
+}
+
+// swapOpenKey = swapOpenPrefix + lower assetID + higher assetID + swapID
+func swapOpenKey(a, b common.Hash, swapID common.Hash) []byte {
+	key := append(append([]byte{}, swapOpenPrefix...), swapPairKey(a, b)[len(swapPairPrefix):]...)
+	return append(key, swapID[:]...)
+}
+
+// swapOwnerKey = swapOwnerPrefix + owner + swapID
+func swapOwnerKey(owner common.Address, swapID common.Hash) []byte {
+	return append(append(append([]byte{}, swapOwnerPrefix...), owner[:]...), swapID[:]...)
+}
+
+// swapExpiryKey = swapExpiryPrefix + swapID
+func swapExpiryKey(swapID common.Hash) []byte {
+	return append(append([]byte{}, swapExpiryPrefix...), swapID[:]...)
+}
+
+func encodeUint64(v uint64) []byte {
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, v)
+	return enc
+}
+
+// swapPairKey = swapPairPrefix + lower assetID + higher assetID
+func swapPairKey(a, b common.Hash) []byte {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	key := make([]byte, 0, len(swapPairPrefix)+2*common.HashLength)
+	key = append(key, swapPairPrefix...)
+	key = append(key, a[:]...)
+	return append(key, b[:]...)
+}
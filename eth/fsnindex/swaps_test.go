@@ -0,0 +1,103 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnindex
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+)
+
+func TestSwapRecords(t *testing.T) {
+	var (
+		chainDb = rawdb.NewMemoryDatabase()
+		indexer = &SwapIndexer{db: rawdb.NewTable(chainDb, swapsTablePrefix)}
+		backend = &swapIndexerBackend{chainDb: chainDb, db: indexer.db}
+
+		maker  = common.HexToAddress("0x01")
+		taker  = common.HexToAddress("0x02")
+		swapID = common.HexToHash("0x5a")
+		fsn    = common.SystemAssetID
+		asset  = common.HexToHash("0xa5")
+	)
+	process := func(section uint64, fn common.FSNCallFunc, data *swapLogData, sender common.Address) {
+		if err := backend.Reset(context.Background(), section, common.Hash{}); err != nil {
+			t.Fatalf("reset failed: %v", err)
+		}
+		if data != nil {
+			record := backend.newRecord(fn, data, sender)
+			if record == nil {
+				t.Fatalf("no record for %v", fn.Name())
+			}
+			record.BlockNumber = section
+			if err := backend.writeRecord(record); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+		}
+		if err := backend.Commit(); err != nil {
+			t.Fatalf("commit failed: %v", err)
+		}
+	}
+	process(1, common.MakeSwapFunc, &swapLogData{
+		SwapID:        swapID,
+		FromAssetID:   fsn,
+		ToAssetID:     asset,
+		MinFromAmount: big.NewInt(10),
+		MinToAmount:   big.NewInt(3),
+		SwapSize:      big.NewInt(5),
+	}, maker)
+	process(2, common.TakeSwapFunc, &swapLogData{SwapID: swapID, Size: big.NewInt(2)}, taker)
+	process(3, common.RecallSwapFunc, &swapLogData{SwapID: swapID}, maker)
+
+	records, err := indexer.PairHistory(asset, fsn, 0, 10, 100)
+	if err != nil {
+		t.Fatalf("pair history failed: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("pair history length mismatch: have %d, want 3", len(records))
+	}
+	take, recall := records[1], records[2]
+	if take.Action != SwapActionTake || take.Owner != maker || take.Taker != taker {
+		t.Errorf("take record mismatch: %+v", take)
+	}
+	if take.FromAmount.Int64() != 20 || take.ToAmount.Int64() != 6 {
+		t.Errorf("take amounts mismatch: have %v/%v, want 20/6", take.FromAmount, take.ToAmount)
+	}
+	if recall.Action != SwapActionRecall || recall.Size.Int64() != 3 {
+		t.Errorf("recall record mismatch: %+v", recall)
+	}
+	if records, _ := indexer.AddressHistory(taker, 0, 10, 100); len(records) != 1 {
+		t.Errorf("taker history length mismatch: have %d, want 1", len(records))
+	}
+	if _, err := indexer.AddressHistory(maker, 0, 10, 2); err != errTooManySwapRecords {
+		t.Errorf("expected too many records error, have %v", err)
+	}
+
+	// Reprocessing the take and recall blocks must revert them
+	process(3, 0, nil, common.Address{})
+	process(2, 0, nil, common.Address{})
+	if records, _ := indexer.AddressHistory(maker, 0, 10, 100); len(records) != 1 {
+		t.Fatalf("maker history length mismatch after reset: have %d, want 1", len(records))
+	}
+	process(2, common.RecallSwapFunc, &swapLogData{SwapID: swapID}, maker)
+	if records, _ := indexer.AddressHistory(maker, 2, 2, 100); len(records) != 1 || records[0].Size.Int64() != 5 {
+		t.Errorf("recall after reset mismatch: %v", records)
+	}
+}
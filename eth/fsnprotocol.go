@@ -0,0 +1,229 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"sync"
+	"time"
+
+	"github.com/FusionFoundation/go-fusion/core/forkid"
+	"github.com/FusionFoundation/go-fusion/log"
+	"github.com/FusionFoundation/go-fusion/p2p"
+	"github.com/FusionFoundation/go-fusion/p2p/enode"
+)
+
+// The fsn protocol exchanges the FSN hard fork schedule, which is not covered
+// by the EIP-2124 fork ID of the eth handshake, so that nodes of a network
+// with a different schedule (e.g. a misconfigured private net) are dropped
+// before they sync. Nodes without the protocol are not checked.
+const (
+	fsnProtocolName    = "fsn"
+	fsnProtocolVersion = 1
+	fsnProtocolLength  = 1
+
+	fsnStatusMsg = 0x00
+)
+
+// fsnStatusData is the network packet for the fsn protocol status message.
+type fsnStatusData struct {
+	Head  uint64
+	Forks []uint64
+}
+
+// FsnPeerInfo represents a short summary of the fsn sub-protocol metadata
+// known about a connected peer.
+type FsnPeerInfo struct {
+	Head  uint64   `json:"head"`  // Head block number at the handshake
+	Forks []uint64 `json:"forks"` // FSN hard fork heights
+}
+
+// fsnHandshake is the result of the fsn handshake with a peer.
+type fsnHandshake struct {
+	info *FsnPeerInfo
+	err  error
+	done chan struct{}
+}
+
+// fsnPeerSet keeps the fsn handshakes of the connected peers, which the eth
+// protocol waits for before handling a peer.
+type fsnPeerSet struct {
+	handshakes map[enode.ID]*fsnHandshake
+	lock       sync.Mutex
+}
+
+func newFsnPeerSet() *fsnPeerSet {
+	return &fsnPeerSet{handshakes: make(map[enode.ID]*fsnHandshake)}
+}
+
+func (ps *fsnPeerSet) handshake(id enode.ID) *fsnHandshake {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	h := ps.handshakes[id]
+	if h == nil {
+		h = &fsnHandshake{done: make(chan struct{})}
+		ps.handshakes[id] = h
+	}
+	return h
+}
+
+func (ps *fsnPeerSet) remove(id enode.ID, h *fsnHandshake) {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	if ps.handshakes[id] == h {
+		delete(ps.handshakes, id)
+	}
+}
+
+// info returns the fsn metadata of a peer, nil until the handshake is done.
+func (ps *fsnPeerSet) info(id enode.ID) *FsnPeerInfo {
+	ps.lock.Lock()
+	h := ps.handshakes[id]
+	ps.lock.Unlock()
+
+	if h == nil {
+		return nil
+	}
+	select {
+	case <-h.done:
+		return h.info
+	default:
+		return nil
+	}
+}
+
+// wait blocks until the fsn handshake with a peer running the fsn protocol
+// is done, returning its error.
+func (ps *fsnPeerSet) wait(p *p2p.Peer) error {
+	supported := false
+	for _, c := range p.Caps() {
+		if c.Name == fsnProtocolName && c.Version == fsnProtocolVersion {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return nil
+	}
+	h := ps.handshake(p.ID())
+	timeout := time.NewTimer(2 * handshakeTimeout)
+	defer timeout.Stop()
+
+	select {
+	case <-h.done:
+		return h.err
+	case <-timeout.C:
+		ps.remove(p.ID(), h)
+		return p2p.DiscReadTimeout
+	}
+}
+
+func (pm *ProtocolManager) makeFsnProtocol() p2p.Protocol {
+	return p2p.Protocol{
+		Name:    fsnProtocolName,
+		Version: fsnProtocolVersion,
+		Length:  fsnProtocolLength,
+		Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+			return pm.handleFsn(p, rw)
+		},
+		NodeInfo: func() interface{} {
+			return &FsnPeerInfo{
+				Head:  pm.blockchain.CurrentHeader().Number.Uint64(),
+				Forks: pm.fsnForks,
+			}
+		},
+		PeerInfo: func(id enode.ID) interface{} {
+			if info := pm.fsnPeers.info(id); info != nil {
+				return info
+			}
+			return nil
+		},
+	}
+}
+
+// handleFsn runs the fsn handshake with a peer and keeps the protocol alive
+// until the peer disconnects.
+func (pm *ProtocolManager) handleFsn(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+	h := pm.fsnPeers.handshake(p.ID())
+	defer pm.fsnPeers.remove(p.ID(), h)
+
+	h.info, h.err = pm.fsnHandshake(rw)
+	close(h.done)
+	if h.err != nil {
+		p.Log().Debug("FSN handshake failed", "err", h.err)
+		return h.err
+	}
+	p.Log().Trace("FSN handshake done", "head", h.info.Head, "forks", h.info.Forks)
+	for {
+		msg, err := rw.ReadMsg()
+		if err != nil {
+			return err
+		}
+		// later versions may add messages
+		msg.Discard()
+	}
+}
+
+func (pm *ProtocolManager) fsnHandshake(rw p2p.MsgReadWriter) (*FsnPeerInfo, error) {
+	head := pm.blockchain.CurrentHeader().Number.Uint64()
+	errc := make(chan error, 2)
+	var status fsnStatusData // safe to read after two values have been received from errc
+
+	go func() {
+		errc <- p2p.Send(rw, fsnStatusMsg, &fsnStatusData{Head: head, Forks: pm.fsnForks})
+	}()
+	go func() {
+		errc <- readFsnStatus(rw, &status)
+	}()
+	timeout := time.NewTimer(handshakeTimeout)
+	defer timeout.Stop()
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errc:
+			if err != nil {
+				return nil, err
+			}
+		case <-timeout.C:
+			return nil, p2p.DiscReadTimeout
+		}
+	}
+	if err := forkid.CheckFsnForks(pm.fsnForks, status.Forks, head, status.Head); err != nil {
+		log.Debug("Rejecting peer with different FSN hard forks", "local", pm.fsnForks, "remote", status.Forks, "err", err)
+		return nil, errResp(ErrForkIDRejected, "%v", err)
+	}
+	return &FsnPeerInfo{Head: status.Head, Forks: status.Forks}, nil
+}
+
+func readFsnStatus(rw p2p.MsgReadWriter, status *fsnStatusData) error {
+	msg, err := rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	defer msg.Discard()
+
+	if msg.Code != fsnStatusMsg {
+		return errResp(ErrNoStatusMsg, "first msg has code %x (!= %x)", msg.Code, fsnStatusMsg)
+	}
+	if msg.Size > protocolMaxMsgSize {
+		return errResp(ErrMsgTooLarge, "%v > %v", msg.Size, protocolMaxMsgSize)
+	}
+	if err := msg.Decode(status); err != nil {
+		return errResp(ErrDecode, "msg %v: %v", msg, err)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/FusionFoundation/go-fusion/consensus/ethash"
 	"github.com/FusionFoundation/go-fusion/core"
 	"github.com/FusionFoundation/go-fusion/eth/downloader"
+	"github.com/FusionFoundation/go-fusion/eth/fsnexport"
 	"github.com/FusionFoundation/go-fusion/eth/gasprice"
 	"github.com/FusionFoundation/go-fusion/miner"
 	"github.com/FusionFoundation/go-fusion/params"
@@ -24,6 +25,12 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		DiscoveryURLs           []string
 		NoPruning               bool
 		NoPrefetch              bool
+		AssetHoldersIndex       bool
+		SwapHistoryIndex        bool
+		FsnHistoryIndex         bool
+		FsnFuncIndex            bool
+		FsnResultCache          int
+		FsnExport               fsnexport.Config
 		Whitelist               map[uint64]common.Hash `toml:"-"`
 		LightServ               int                    `toml:",omitempty"`
 		LightIngress            int                    `toml:",omitempty"`
@@ -39,6 +46,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		TrieCleanCache          int
 		TrieDirtyCache          int
 		TrieTimeout             time.Duration
+		TicketCache             int
 		Miner                   miner.Config
 		Ethash                  ethash.Config
 		TxPool                  core.TxPoolConfig
@@ -60,6 +68,12 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.DiscoveryURLs = c.DiscoveryURLs
 	enc.NoPruning = c.NoPruning
 	enc.NoPrefetch = c.NoPrefetch
+	enc.AssetHoldersIndex = c.AssetHoldersIndex
+	enc.SwapHistoryIndex = c.SwapHistoryIndex
+	enc.FsnHistoryIndex = c.FsnHistoryIndex
+	enc.FsnFuncIndex = c.FsnFuncIndex
+	enc.FsnResultCache = c.FsnResultCache
+	enc.FsnExport = c.FsnExport
 	enc.Whitelist = c.Whitelist
 	enc.LightServ = c.LightServ
 	enc.LightIngress = c.LightIngress
@@ -75,6 +89,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.TrieCleanCache = c.TrieCleanCache
 	enc.TrieDirtyCache = c.TrieDirtyCache
 	enc.TrieTimeout = c.TrieTimeout
+	enc.TicketCache = c.TicketCache
 	enc.Miner = c.Miner
 	enc.Ethash = c.Ethash
 	enc.TxPool = c.TxPool
@@ -100,6 +115,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		DiscoveryURLs           []string
 		NoPruning               *bool
 		NoPrefetch              *bool
+		AssetHoldersIndex       *bool
+		SwapHistoryIndex        *bool
+		FsnHistoryIndex         *bool
+		FsnFuncIndex            *bool
+		FsnResultCache          *int
+		FsnExport               *fsnexport.Config
 		Whitelist               map[uint64]common.Hash `toml:"-"`
 		LightServ               *int                   `toml:",omitempty"`
 		LightIngress            *int                   `toml:",omitempty"`
@@ -115,6 +136,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		TrieCleanCache          *int
 		TrieDirtyCache          *int
 		TrieTimeout             *time.Duration
+		TicketCache             *int
 		Miner                   *miner.Config
 		Ethash                  *ethash.Config
 		TxPool                  *core.TxPoolConfig
@@ -151,6 +173,24 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.NoPrefetch != nil {
 		c.NoPrefetch = *dec.NoPrefetch
 	}
+	if dec.AssetHoldersIndex != nil {
+		c.AssetHoldersIndex = *dec.AssetHoldersIndex
+	}
+	if dec.SwapHistoryIndex != nil {
+		c.SwapHistoryIndex = *dec.SwapHistoryIndex
+	}
+	if dec.FsnHistoryIndex != nil {
+		c.FsnHistoryIndex = *dec.FsnHistoryIndex
+	}
+	if dec.FsnFuncIndex != nil {
+		c.FsnFuncIndex = *dec.FsnFuncIndex
+	}
+	if dec.FsnResultCache != nil {
+		c.FsnResultCache = *dec.FsnResultCache
+	}
+	if dec.FsnExport != nil {
+		c.FsnExport = *dec.FsnExport
+	}
 	if dec.Whitelist != nil {
 		c.Whitelist = dec.Whitelist
 	}
@@ -196,6 +236,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.TrieTimeout != nil {
 		c.TrieTimeout = *dec.TrieTimeout
 	}
+	if dec.TicketCache != nil {
+		c.TicketCache = *dec.TicketCache
+	}
 	if dec.Miner != nil {
 		c.Miner = *dec.Miner
 	}
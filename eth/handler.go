@@ -64,6 +64,8 @@ func errResp(code errCode, format string, v ...interface{}) error {
 type ProtocolManager struct {
 	networkID  uint64
 	forkFilter forkid.Filter // Fork ID filter, constant across the lifetime of the node
+	fsnForks   []uint64      // FSN hard fork heights, checked by the fsn protocol
+	fsnPeers   *fsnPeerSet
 
 	fastSync  uint32 // Flag whether fast sync is enabled (gets disabled if we already have blocks)
 	acceptTxs uint32 // Flag whether we're considered synchronised (enables transaction processing)
@@ -108,6 +110,8 @@ func NewProtocolManager(config *params.ChainConfig, checkpoint *params.TrustedCh
 	manager := &ProtocolManager{
 		networkID:   networkID,
 		forkFilter:  forkid.NewFilter(blockchain),
+		fsnForks:    forkid.FsnForks(config),
+		fsnPeers:    newFsnPeerSet(),
 		eventMux:    mux,
 		txpool:      txpool,
 		blockchain:  blockchain,
@@ -218,6 +222,9 @@ func (pm *ProtocolManager) makeProtocol(version uint) p2p.Protocol {
 		Version: version,
 		Length:  length,
 		Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+			if err := pm.fsnPeers.wait(p); err != nil {
+				return err
+			}
 			peer := pm.newPeer(int(version), p, rw, pm.txpool.Get)
 			select {
 			case pm.newPeerCh <- peer:
@@ -714,6 +721,9 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		request.Block.ReceivedAt = msg.ReceivedAt
 		request.Block.ReceivedFrom = p
 
+		// Let the double mining watcher see blocks that may never be imported
+		datong.WatchHeader(request.Block.Header())
+
 		// Mark the peer as owning the block and schedule it for import
 		p.MarkBlock(request.Block.Hash())
 		pm.blockFetcher.Enqueue(p.id, request.Block)
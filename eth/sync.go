@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/consensus/datong"
 	"github.com/FusionFoundation/go-fusion/core/types"
 	"github.com/FusionFoundation/go-fusion/eth/downloader"
 	"github.com/FusionFoundation/go-fusion/log"
@@ -213,6 +214,16 @@ func (pm *ProtocolManager) synchronise(peer *peer) {
 		return
 	}
 	if atomic.LoadUint32(&pm.fastSync) == 1 {
+		// The tickets of the synced state are only covered by the header
+		// chain, drop the synced chain and the peer serving it on mismatch
+		if err := datong.VerifySyncedTickets(pm.blockchain.StateCache().TrieDB().DiskDB(), pm.blockchain.CurrentBlock().Header()); err != nil {
+			log.Error("Fast synced tickets invalid, rewinding", "peer", peer.id, "err", err)
+			pm.removePeer(peer.id)
+			if err := pm.blockchain.SetHead(0); err != nil {
+				log.Error("Failed to rewind fast synced chain", "err", err)
+			}
+			return
+		}
 		log.Info("Fast sync complete, auto disabling")
 		atomic.StoreUint32(&pm.fastSync, 0)
 	}
@@ -0,0 +1,929 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnclient
+
+import (
+	"context"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core/types"
+)
+
+// Every FSN call is available as
+//
+//   - BuildXxxTx, which returns the unsigned transaction built by the node,
+//   - Xxx, which sends it signed by the unlocked account args.From,
+//   - SignXxxTx, which returns it signed by the unlocked account args.From,
+//   - XxxWithPassphrase, which sends it signed by args.From unlocked with passwd,
+//
+// the node fills in the nonce, gas and gas price left unset. See SignCall to
+// build and sign the calls locally instead.
+
+func (fc *Client) buildTx(ctx context.Context, method string, args ...interface{}) (*types.Transaction, error) {
+	var result *types.Transaction
+	err := fc.c.CallContext(ctx, &result, method, args...)
+	return result, err
+}
+
+func (fc *Client) sendTx(ctx context.Context, method string, args ...interface{}) (common.Hash, error) {
+	var result common.Hash
+	err := fc.c.CallContext(ctx, &result, method, args...)
+	return result, err
+}
+
+func (fc *Client) signTx(ctx context.Context, method string, args ...interface{}) (*SignTransactionResult, error) {
+	var result *SignTransactionResult
+	err := fc.c.CallContext(ctx, &result, method, args...)
+	return result, err
+}
+
+func (fc *Client) buildSendTxArgs(ctx context.Context, method string, args interface{}) (*SendTxArgs, error) {
+	var result *SendTxArgs
+	err := fc.c.CallContext(ctx, &result, method, args)
+	return result, err
+}
+
+// BuildGenNotationTx returns the transaction which generates a notation (USAN) for the sender.
+func (fc *Client) BuildGenNotationTx(ctx context.Context, args common.FusionBaseArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildGenNotationTx", args)
+}
+
+// GenNotation generates a notation (USAN) for the sender, signed by the node.
+func (fc *Client) GenNotation(ctx context.Context, args common.FusionBaseArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_genNotation", args)
+}
+
+// SignGenNotationTx returns the transaction which generates a notation (USAN) for the sender, signed by the node.
+func (fc *Client) SignGenNotationTx(ctx context.Context, args common.FusionBaseArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signGenNotationTx", args)
+}
+
+// GenNotationWithPassphrase generates a notation (USAN) for the sender, signed by the node with the key unlocked by passwd.
+func (fc *Client) GenNotationWithPassphrase(ctx context.Context, args common.FusionBaseArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_genNotation", args, passwd)
+}
+
+// BuildGenNotationSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildGenNotationSendTxArgs(ctx context.Context, args common.FusionBaseArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildGenNotationSendTxArgs", args)
+}
+
+// BuildGenAssetTx returns the transaction which creates an asset.
+func (fc *Client) BuildGenAssetTx(ctx context.Context, args common.GenAssetArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildGenAssetTx", args)
+}
+
+// GenAsset creates an asset, signed by the node.
+func (fc *Client) GenAsset(ctx context.Context, args common.GenAssetArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_genAsset", args)
+}
+
+// SignGenAssetTx returns the transaction which creates an asset, signed by the node.
+func (fc *Client) SignGenAssetTx(ctx context.Context, args common.GenAssetArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signGenAssetTx", args)
+}
+
+// GenAssetWithPassphrase creates an asset, signed by the node with the key unlocked by passwd.
+func (fc *Client) GenAssetWithPassphrase(ctx context.Context, args common.GenAssetArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_genAsset", args, passwd)
+}
+
+// BuildGenAssetSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildGenAssetSendTxArgs(ctx context.Context, args common.GenAssetArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildGenAssetSendTxArgs", args)
+}
+
+// BuildSendAssetTx returns the transaction which sends an asset.
+func (fc *Client) BuildSendAssetTx(ctx context.Context, args common.SendAssetArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildSendAssetTx", args)
+}
+
+// SendAsset sends an asset, signed by the node.
+func (fc *Client) SendAsset(ctx context.Context, args common.SendAssetArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_sendAsset", args)
+}
+
+// SignSendAssetTx returns the transaction which sends an asset, signed by the node.
+func (fc *Client) SignSendAssetTx(ctx context.Context, args common.SendAssetArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signSendAssetTx", args)
+}
+
+// SendAssetWithPassphrase sends an asset, signed by the node with the key unlocked by passwd.
+func (fc *Client) SendAssetWithPassphrase(ctx context.Context, args common.SendAssetArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_sendAsset", args, passwd)
+}
+
+// BuildSendAssetSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildSendAssetSendTxArgs(ctx context.Context, args common.SendAssetArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildSendAssetSendTxArgs", args)
+}
+
+// BuildAssetToTimeLockTx returns the transaction which time locks an asset balance.
+func (fc *Client) BuildAssetToTimeLockTx(ctx context.Context, args common.TimeLockArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildAssetToTimeLockTx", args)
+}
+
+// AssetToTimeLock time locks an asset balance, signed by the node.
+func (fc *Client) AssetToTimeLock(ctx context.Context, args common.TimeLockArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_assetToTimeLock", args)
+}
+
+// SignAssetToTimeLockTx returns the transaction which time locks an asset balance, signed by the node.
+func (fc *Client) SignAssetToTimeLockTx(ctx context.Context, args common.TimeLockArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signAssetToTimeLockTx", args)
+}
+
+// AssetToTimeLockWithPassphrase time locks an asset balance, signed by the node with the key unlocked by passwd.
+func (fc *Client) AssetToTimeLockWithPassphrase(ctx context.Context, args common.TimeLockArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_assetToTimeLock", args, passwd)
+}
+
+// BuildAssetToTimeLockSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildAssetToTimeLockSendTxArgs(ctx context.Context, args common.TimeLockArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildAssetToTimeLockSendTxArgs", args)
+}
+
+// BuildTimeLockToTimeLockTx returns the transaction which sends a part of a time locked balance.
+func (fc *Client) BuildTimeLockToTimeLockTx(ctx context.Context, args common.TimeLockArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildTimeLockToTimeLockTx", args)
+}
+
+// TimeLockToTimeLock sends a part of a time locked balance, signed by the node.
+func (fc *Client) TimeLockToTimeLock(ctx context.Context, args common.TimeLockArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_timeLockToTimeLock", args)
+}
+
+// SignTimeLockToTimeLockTx returns the transaction which sends a part of a time locked balance, signed by the node.
+func (fc *Client) SignTimeLockToTimeLockTx(ctx context.Context, args common.TimeLockArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signTimeLockToTimeLockTx", args)
+}
+
+// TimeLockToTimeLockWithPassphrase sends a part of a time locked balance, signed by the node with the key unlocked by passwd.
+func (fc *Client) TimeLockToTimeLockWithPassphrase(ctx context.Context, args common.TimeLockArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_timeLockToTimeLock", args, passwd)
+}
+
+// BuildTimeLockToTimeLockSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildTimeLockToTimeLockSendTxArgs(ctx context.Context, args common.TimeLockArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildTimeLockToTimeLockSendTxArgs", args)
+}
+
+// BuildTimeLockToAssetTx returns the transaction which unlocks a time locked balance without end.
+func (fc *Client) BuildTimeLockToAssetTx(ctx context.Context, args common.TimeLockArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildTimeLockToAssetTx", args)
+}
+
+// TimeLockToAsset unlocks a time locked balance without end, signed by the node.
+func (fc *Client) TimeLockToAsset(ctx context.Context, args common.TimeLockArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_timeLockToAsset", args)
+}
+
+// SignTimeLockToAssetTx returns the transaction which unlocks a time locked balance without end, signed by the node.
+func (fc *Client) SignTimeLockToAssetTx(ctx context.Context, args common.TimeLockArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signTimeLockToAssetTx", args)
+}
+
+// TimeLockToAssetWithPassphrase unlocks a time locked balance without end, signed by the node with the key unlocked by passwd.
+func (fc *Client) TimeLockToAssetWithPassphrase(ctx context.Context, args common.TimeLockArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_timeLockToAsset", args, passwd)
+}
+
+// BuildTimeLockToAssetSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildTimeLockToAssetSendTxArgs(ctx context.Context, args common.TimeLockArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildTimeLockToAssetSendTxArgs", args)
+}
+
+// BuildSendTimeLockTx returns the transaction which sends a time lock from the asset and time locked balances.
+func (fc *Client) BuildSendTimeLockTx(ctx context.Context, args common.TimeLockArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildSendTimeLockTx", args)
+}
+
+// SendTimeLock sends a time lock from the asset and time locked balances, signed by the node.
+func (fc *Client) SendTimeLock(ctx context.Context, args common.TimeLockArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_sendTimeLock", args)
+}
+
+// SignSendTimeLockTx returns the transaction which sends a time lock from the asset and time locked balances, signed by the node.
+func (fc *Client) SignSendTimeLockTx(ctx context.Context, args common.TimeLockArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signSendTimeLockTx", args)
+}
+
+// SendTimeLockWithPassphrase sends a time lock from the asset and time locked balances, signed by the node with the key unlocked by passwd.
+func (fc *Client) SendTimeLockWithPassphrase(ctx context.Context, args common.TimeLockArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_sendTimeLock", args, passwd)
+}
+
+// BuildSendTimeLockSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildSendTimeLockSendTxArgs(ctx context.Context, args common.TimeLockArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildSendTimeLockSendTxArgs", args)
+}
+
+// BuildBuyTicketTx returns the transaction which buys a mining ticket.
+func (fc *Client) BuildBuyTicketTx(ctx context.Context, args common.BuyTicketArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildBuyTicketTx", args)
+}
+
+// BuyTicket buys a mining ticket, signed by the node.
+func (fc *Client) BuyTicket(ctx context.Context, args common.BuyTicketArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_buyTicket", args)
+}
+
+// SignBuyTicketTx returns the transaction which buys a mining ticket, signed by the node.
+func (fc *Client) SignBuyTicketTx(ctx context.Context, args common.BuyTicketArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signBuyTicketTx", args)
+}
+
+// BuyTicketWithPassphrase buys a mining ticket, signed by the node with the key unlocked by passwd.
+func (fc *Client) BuyTicketWithPassphrase(ctx context.Context, args common.BuyTicketArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_buyTicket", args, passwd)
+}
+
+// BuildBuyTicketSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildBuyTicketSendTxArgs(ctx context.Context, args common.BuyTicketArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildBuyTicketSendTxArgs", args)
+}
+
+// BuildIncAssetTx returns the transaction which increases the supply of an asset.
+func (fc *Client) BuildIncAssetTx(ctx context.Context, args common.AssetValueChangeExArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildIncAssetTx", args)
+}
+
+// IncAsset increases the supply of an asset, signed by the node.
+func (fc *Client) IncAsset(ctx context.Context, args common.AssetValueChangeExArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_incAsset", args)
+}
+
+// SignIncAssetTx returns the transaction which increases the supply of an asset, signed by the node.
+func (fc *Client) SignIncAssetTx(ctx context.Context, args common.AssetValueChangeExArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signIncAssetTx", args)
+}
+
+// IncAssetWithPassphrase increases the supply of an asset, signed by the node with the key unlocked by passwd.
+func (fc *Client) IncAssetWithPassphrase(ctx context.Context, args common.AssetValueChangeExArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_incAsset", args, passwd)
+}
+
+// BuildAssetValueChangeSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildAssetValueChangeSendTxArgs(ctx context.Context, args common.AssetValueChangeExArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildAssetValueChangeSendTxArgs", args)
+}
+
+// BuildDecAssetTx returns the transaction which decreases the supply of an asset.
+func (fc *Client) BuildDecAssetTx(ctx context.Context, args common.AssetValueChangeExArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildDecAssetTx", args)
+}
+
+// DecAsset decreases the supply of an asset, signed by the node.
+func (fc *Client) DecAsset(ctx context.Context, args common.AssetValueChangeExArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_decAsset", args)
+}
+
+// SignDecAssetTx returns the transaction which decreases the supply of an asset, signed by the node.
+func (fc *Client) SignDecAssetTx(ctx context.Context, args common.AssetValueChangeExArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signDecAssetTx", args)
+}
+
+// DecAssetWithPassphrase decreases the supply of an asset, signed by the node with the key unlocked by passwd.
+func (fc *Client) DecAssetWithPassphrase(ctx context.Context, args common.AssetValueChangeExArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_decAsset", args, passwd)
+}
+
+// BuildMakeSwapTx returns the transaction which makes a swap.
+func (fc *Client) BuildMakeSwapTx(ctx context.Context, args common.MakeSwapArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildMakeSwapTx", args)
+}
+
+// MakeSwap makes a swap, signed by the node.
+func (fc *Client) MakeSwap(ctx context.Context, args common.MakeSwapArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_makeSwap", args)
+}
+
+// SignMakeSwapTx returns the transaction which makes a swap, signed by the node.
+func (fc *Client) SignMakeSwapTx(ctx context.Context, args common.MakeSwapArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signMakeSwapTx", args)
+}
+
+// MakeSwapWithPassphrase makes a swap, signed by the node with the key unlocked by passwd.
+func (fc *Client) MakeSwapWithPassphrase(ctx context.Context, args common.MakeSwapArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_makeSwap", args, passwd)
+}
+
+// BuildMakeSwapSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildMakeSwapSendTxArgs(ctx context.Context, args common.MakeSwapArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildMakeSwapSendTxArgs", args)
+}
+
+// BuildRecallSwapTx returns the transaction which recalls a swap.
+func (fc *Client) BuildRecallSwapTx(ctx context.Context, args common.RecallSwapArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildRecallSwapTx", args)
+}
+
+// RecallSwap recalls a swap, signed by the node.
+func (fc *Client) RecallSwap(ctx context.Context, args common.RecallSwapArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_recallSwap", args)
+}
+
+// SignRecallSwapTx returns the transaction which recalls a swap, signed by the node.
+func (fc *Client) SignRecallSwapTx(ctx context.Context, args common.RecallSwapArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signRecallSwapTx", args)
+}
+
+// RecallSwapWithPassphrase recalls a swap, signed by the node with the key unlocked by passwd.
+func (fc *Client) RecallSwapWithPassphrase(ctx context.Context, args common.RecallSwapArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_recallSwap", args, passwd)
+}
+
+// BuildRecallSwapSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildRecallSwapSendTxArgs(ctx context.Context, args common.RecallSwapArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildRecallSwapSendTxArgs", args)
+}
+
+// BuildTakeSwapTx returns the transaction which takes a swap.
+func (fc *Client) BuildTakeSwapTx(ctx context.Context, args common.TakeSwapArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildTakeSwapTx", args)
+}
+
+// TakeSwap takes a swap, signed by the node.
+func (fc *Client) TakeSwap(ctx context.Context, args common.TakeSwapArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_takeSwap", args)
+}
+
+// SignTakeSwapTx returns the transaction which takes a swap, signed by the node.
+func (fc *Client) SignTakeSwapTx(ctx context.Context, args common.TakeSwapArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signTakeSwapTx", args)
+}
+
+// TakeSwapWithPassphrase takes a swap, signed by the node with the key unlocked by passwd.
+func (fc *Client) TakeSwapWithPassphrase(ctx context.Context, args common.TakeSwapArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_takeSwap", args, passwd)
+}
+
+// BuildTakeSwapSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildTakeSwapSendTxArgs(ctx context.Context, args common.TakeSwapArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildTakeSwapSendTxArgs", args)
+}
+
+// BuildMakeMultiSwapTx returns the transaction which makes a multi swap.
+func (fc *Client) BuildMakeMultiSwapTx(ctx context.Context, args common.MakeMultiSwapArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildMakeMultiSwapTx", args)
+}
+
+// MakeMultiSwap makes a multi swap, signed by the node.
+func (fc *Client) MakeMultiSwap(ctx context.Context, args common.MakeMultiSwapArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_makeMultiSwap", args)
+}
+
+// SignMakeMultiSwapTx returns the transaction which makes a multi swap, signed by the node.
+func (fc *Client) SignMakeMultiSwapTx(ctx context.Context, args common.MakeMultiSwapArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signMakeMultiSwapTx", args)
+}
+
+// MakeMultiSwapWithPassphrase makes a multi swap, signed by the node with the key unlocked by passwd.
+func (fc *Client) MakeMultiSwapWithPassphrase(ctx context.Context, args common.MakeMultiSwapArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_makeMultiSwap", args, passwd)
+}
+
+// BuildMakeMultiSwapSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildMakeMultiSwapSendTxArgs(ctx context.Context, args common.MakeMultiSwapArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildMakeMultiSwapSendTxArgs", args)
+}
+
+// BuildRecallMultiSwapTx returns the transaction which recalls a multi swap.
+func (fc *Client) BuildRecallMultiSwapTx(ctx context.Context, args common.RecallMultiSwapArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildRecallMultiSwapTx", args)
+}
+
+// RecallMultiSwap recalls a multi swap, signed by the node.
+func (fc *Client) RecallMultiSwap(ctx context.Context, args common.RecallMultiSwapArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_recallMultiSwap", args)
+}
+
+// SignRecallMultiSwapTx returns the transaction which recalls a multi swap, signed by the node.
+func (fc *Client) SignRecallMultiSwapTx(ctx context.Context, args common.RecallMultiSwapArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signRecallMultiSwapTx", args)
+}
+
+// RecallMultiSwapWithPassphrase recalls a multi swap, signed by the node with the key unlocked by passwd.
+func (fc *Client) RecallMultiSwapWithPassphrase(ctx context.Context, args common.RecallMultiSwapArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_recallMultiSwap", args, passwd)
+}
+
+// BuildRecallMultiSwapSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildRecallMultiSwapSendTxArgs(ctx context.Context, args common.RecallMultiSwapArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildRecallMultiSwapSendTxArgs", args)
+}
+
+// BuildTakeMultiSwapTx returns the transaction which takes a multi swap.
+func (fc *Client) BuildTakeMultiSwapTx(ctx context.Context, args common.TakeMultiSwapArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildTakeMultiSwapTx", args)
+}
+
+// TakeMultiSwap takes a multi swap, signed by the node.
+func (fc *Client) TakeMultiSwap(ctx context.Context, args common.TakeMultiSwapArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_takeMultiSwap", args)
+}
+
+// SignTakeMultiSwapTx returns the transaction which takes a multi swap, signed by the node.
+func (fc *Client) SignTakeMultiSwapTx(ctx context.Context, args common.TakeMultiSwapArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signTakeMultiSwapTx", args)
+}
+
+// TakeMultiSwapWithPassphrase takes a multi swap, signed by the node with the key unlocked by passwd.
+func (fc *Client) TakeMultiSwapWithPassphrase(ctx context.Context, args common.TakeMultiSwapArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_takeMultiSwap", args, passwd)
+}
+
+// BuildTakeMultiSwapSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildTakeMultiSwapSendTxArgs(ctx context.Context, args common.TakeMultiSwapArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildTakeMultiSwapSendTxArgs", args)
+}
+
+// BuildTypedCallTx returns the transaction which relays a typed call signed by another account.
+func (fc *Client) BuildTypedCallTx(ctx context.Context, args common.TypedCallArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildTypedCallTx", args)
+}
+
+// TypedCall relays a typed call signed by another account, signed by the node.
+func (fc *Client) TypedCall(ctx context.Context, args common.TypedCallArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_typedCall", args)
+}
+
+// SignTypedCallTx returns the transaction which relays a typed call signed by another account, signed by the node.
+func (fc *Client) SignTypedCallTx(ctx context.Context, args common.TypedCallArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signTypedCallTx", args)
+}
+
+// TypedCallWithPassphrase relays a typed call signed by another account, signed by the node with the key unlocked by passwd.
+func (fc *Client) TypedCallWithPassphrase(ctx context.Context, args common.TypedCallArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_typedCall", args, passwd)
+}
+
+// BuildTypedCallSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildTypedCallSendTxArgs(ctx context.Context, args common.TypedCallArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildTypedCallSendTxArgs", args)
+}
+
+// BuildAssetTransferListTx returns the transaction which changes the transfer list of an asset.
+func (fc *Client) BuildAssetTransferListTx(ctx context.Context, args common.AssetTransferListArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildAssetTransferListTx", args)
+}
+
+// SetAssetTransferList changes the transfer list of an asset, signed by the node.
+func (fc *Client) SetAssetTransferList(ctx context.Context, args common.AssetTransferListArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_setAssetTransferList", args)
+}
+
+// SignAssetTransferListTx returns the transaction which changes the transfer list of an asset, signed by the node.
+func (fc *Client) SignAssetTransferListTx(ctx context.Context, args common.AssetTransferListArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signAssetTransferListTx", args)
+}
+
+// SetAssetTransferListWithPassphrase changes the transfer list of an asset, signed by the node with the key unlocked by passwd.
+func (fc *Client) SetAssetTransferListWithPassphrase(ctx context.Context, args common.AssetTransferListArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_setAssetTransferList", args, passwd)
+}
+
+// BuildAssetTransferListSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildAssetTransferListSendTxArgs(ctx context.Context, args common.AssetTransferListArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildAssetTransferListSendTxArgs", args)
+}
+
+// BuildSetFsnCallFeeTx returns the transaction which changes the fee of an FSN call.
+func (fc *Client) BuildSetFsnCallFeeTx(ctx context.Context, args common.SetFsnCallFeeArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildSetFsnCallFeeTx", args)
+}
+
+// SetFsnCallFee changes the fee of an FSN call, signed by the node.
+func (fc *Client) SetFsnCallFee(ctx context.Context, args common.SetFsnCallFeeArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_setFsnCallFee", args)
+}
+
+// SignSetFsnCallFeeTx returns the transaction which changes the fee of an FSN call, signed by the node.
+func (fc *Client) SignSetFsnCallFeeTx(ctx context.Context, args common.SetFsnCallFeeArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signSetFsnCallFeeTx", args)
+}
+
+// SetFsnCallFeeWithPassphrase changes the fee of an FSN call, signed by the node with the key unlocked by passwd.
+func (fc *Client) SetFsnCallFeeWithPassphrase(ctx context.Context, args common.SetFsnCallFeeArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_setFsnCallFee", args, passwd)
+}
+
+// BuildSetFsnCallFeeSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildSetFsnCallFeeSendTxArgs(ctx context.Context, args common.SetFsnCallFeeArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildSetFsnCallFeeSendTxArgs", args)
+}
+
+// BuildCreateProposalTx returns the transaction which creates a governance proposal.
+func (fc *Client) BuildCreateProposalTx(ctx context.Context, args common.CreateProposalArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildCreateProposalTx", args)
+}
+
+// CreateProposal creates a governance proposal, signed by the node.
+func (fc *Client) CreateProposal(ctx context.Context, args common.CreateProposalArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_createProposal", args)
+}
+
+// SignCreateProposalTx returns the transaction which creates a governance proposal, signed by the node.
+func (fc *Client) SignCreateProposalTx(ctx context.Context, args common.CreateProposalArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signCreateProposalTx", args)
+}
+
+// CreateProposalWithPassphrase creates a governance proposal, signed by the node with the key unlocked by passwd.
+func (fc *Client) CreateProposalWithPassphrase(ctx context.Context, args common.CreateProposalArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_createProposal", args, passwd)
+}
+
+// BuildCreateProposalSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildCreateProposalSendTxArgs(ctx context.Context, args common.CreateProposalArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildCreateProposalSendTxArgs", args)
+}
+
+// BuildVoteProposalTx returns the transaction which votes on a governance proposal.
+func (fc *Client) BuildVoteProposalTx(ctx context.Context, args common.VoteProposalArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildVoteProposalTx", args)
+}
+
+// VoteProposal votes on a governance proposal, signed by the node.
+func (fc *Client) VoteProposal(ctx context.Context, args common.VoteProposalArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_voteProposal", args)
+}
+
+// SignVoteProposalTx returns the transaction which votes on a governance proposal, signed by the node.
+func (fc *Client) SignVoteProposalTx(ctx context.Context, args common.VoteProposalArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signVoteProposalTx", args)
+}
+
+// VoteProposalWithPassphrase votes on a governance proposal, signed by the node with the key unlocked by passwd.
+func (fc *Client) VoteProposalWithPassphrase(ctx context.Context, args common.VoteProposalArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_voteProposal", args, passwd)
+}
+
+// BuildVoteProposalSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildVoteProposalSendTxArgs(ctx context.Context, args common.VoteProposalArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildVoteProposalSendTxArgs", args)
+}
+
+// BuildRevokeTicketTx returns the transaction which revokes an unexpired ticket.
+func (fc *Client) BuildRevokeTicketTx(ctx context.Context, args common.RevokeTicketArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildRevokeTicketTx", args)
+}
+
+// RevokeTicket revokes an unexpired ticket, signed by the node.
+func (fc *Client) RevokeTicket(ctx context.Context, args common.RevokeTicketArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_revokeTicket", args)
+}
+
+// SignRevokeTicketTx returns the transaction which revokes an unexpired ticket, signed by the node.
+func (fc *Client) SignRevokeTicketTx(ctx context.Context, args common.RevokeTicketArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signRevokeTicketTx", args)
+}
+
+// RevokeTicketWithPassphrase revokes an unexpired ticket, signed by the node with the key unlocked by passwd.
+func (fc *Client) RevokeTicketWithPassphrase(ctx context.Context, args common.RevokeTicketArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_revokeTicket", args, passwd)
+}
+
+// BuildRevokeTicketSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildRevokeTicketSendTxArgs(ctx context.Context, args common.RevokeTicketArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildRevokeTicketSendTxArgs", args)
+}
+
+// BuildEscrowAssetTx returns the transaction which sends an asset into an escrow claimable by the recipient until a deadline.
+func (fc *Client) BuildEscrowAssetTx(ctx context.Context, args common.EscrowAssetArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildEscrowAssetTx", args)
+}
+
+// EscrowAsset sends an asset into an escrow claimable by the recipient until a deadline, signed by the node.
+func (fc *Client) EscrowAsset(ctx context.Context, args common.EscrowAssetArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_escrowAsset", args)
+}
+
+// SignEscrowAssetTx returns the transaction which sends an asset into an escrow claimable by the recipient until a deadline, signed by the node.
+func (fc *Client) SignEscrowAssetTx(ctx context.Context, args common.EscrowAssetArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signEscrowAssetTx", args)
+}
+
+// EscrowAssetWithPassphrase sends an asset into an escrow claimable by the recipient until a deadline, signed by the node with the key unlocked by passwd.
+func (fc *Client) EscrowAssetWithPassphrase(ctx context.Context, args common.EscrowAssetArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_escrowAsset", args, passwd)
+}
+
+// BuildEscrowAssetSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildEscrowAssetSendTxArgs(ctx context.Context, args common.EscrowAssetArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildEscrowAssetSendTxArgs", args)
+}
+
+// BuildClaimEscrowTx returns the transaction which claims an escrow, or refunds it to its sender after the deadline.
+func (fc *Client) BuildClaimEscrowTx(ctx context.Context, args common.ClaimEscrowArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildClaimEscrowTx", args)
+}
+
+// ClaimEscrow claims an escrow, or refunds it to its sender after the deadline, signed by the node.
+func (fc *Client) ClaimEscrow(ctx context.Context, args common.ClaimEscrowArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_claimEscrow", args)
+}
+
+// SignClaimEscrowTx returns the transaction which claims an escrow, or refunds it to its sender after the deadline, signed by the node.
+func (fc *Client) SignClaimEscrowTx(ctx context.Context, args common.ClaimEscrowArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signClaimEscrowTx", args)
+}
+
+// ClaimEscrowWithPassphrase claims an escrow, or refunds it to its sender after the deadline, signed by the node with the key unlocked by passwd.
+func (fc *Client) ClaimEscrowWithPassphrase(ctx context.Context, args common.ClaimEscrowArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_claimEscrow", args, passwd)
+}
+
+// BuildClaimEscrowSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildClaimEscrowSendTxArgs(ctx context.Context, args common.ClaimEscrowArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildClaimEscrowSendTxArgs", args)
+}
+
+// BuildCreateStreamTx returns the transaction which streams an asset to the recipient, vesting linearly between a start and an end time.
+func (fc *Client) BuildCreateStreamTx(ctx context.Context, args common.CreateStreamArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildCreateStreamTx", args)
+}
+
+// CreateStream streams an asset to the recipient, vesting linearly between a start and an end time, signed by the node.
+func (fc *Client) CreateStream(ctx context.Context, args common.CreateStreamArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_createStream", args)
+}
+
+// SignCreateStreamTx returns the transaction which streams an asset to the recipient, vesting linearly between a start and an end time, signed by the node.
+func (fc *Client) SignCreateStreamTx(ctx context.Context, args common.CreateStreamArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signCreateStreamTx", args)
+}
+
+// CreateStreamWithPassphrase streams an asset to the recipient, vesting linearly between a start and an end time, signed by the node with the key unlocked by passwd.
+func (fc *Client) CreateStreamWithPassphrase(ctx context.Context, args common.CreateStreamArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_createStream", args, passwd)
+}
+
+// BuildCreateStreamSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildCreateStreamSendTxArgs(ctx context.Context, args common.CreateStreamArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildCreateStreamSendTxArgs", args)
+}
+
+// BuildWithdrawStreamTx returns the transaction which withdraws the vested part of a stream to its recipient.
+func (fc *Client) BuildWithdrawStreamTx(ctx context.Context, args common.WithdrawStreamArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildWithdrawStreamTx", args)
+}
+
+// WithdrawStream withdraws the vested part of a stream to its recipient, signed by the node.
+func (fc *Client) WithdrawStream(ctx context.Context, args common.WithdrawStreamArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_withdrawStream", args)
+}
+
+// SignWithdrawStreamTx returns the transaction which withdraws the vested part of a stream to its recipient, signed by the node.
+func (fc *Client) SignWithdrawStreamTx(ctx context.Context, args common.WithdrawStreamArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signWithdrawStreamTx", args)
+}
+
+// WithdrawStreamWithPassphrase withdraws the vested part of a stream to its recipient, signed by the node with the key unlocked by passwd.
+func (fc *Client) WithdrawStreamWithPassphrase(ctx context.Context, args common.WithdrawStreamArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_withdrawStream", args, passwd)
+}
+
+// BuildWithdrawStreamSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildWithdrawStreamSendTxArgs(ctx context.Context, args common.WithdrawStreamArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildWithdrawStreamSendTxArgs", args)
+}
+
+// BuildCreateConditionTx returns the transaction which registers a hash lock or oracle condition.
+func (fc *Client) BuildCreateConditionTx(ctx context.Context, args common.CreateConditionArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildCreateConditionTx", args)
+}
+
+// CreateCondition registers a hash lock or oracle condition, signed by the node.
+func (fc *Client) CreateCondition(ctx context.Context, args common.CreateConditionArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_createCondition", args)
+}
+
+// SignCreateConditionTx returns the transaction which registers a hash lock or oracle condition, signed by the node.
+func (fc *Client) SignCreateConditionTx(ctx context.Context, args common.CreateConditionArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signCreateConditionTx", args)
+}
+
+// CreateConditionWithPassphrase registers a hash lock or oracle condition, signed by the node with the key unlocked by passwd.
+func (fc *Client) CreateConditionWithPassphrase(ctx context.Context, args common.CreateConditionArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_createCondition", args, passwd)
+}
+
+// BuildCreateConditionSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildCreateConditionSendTxArgs(ctx context.Context, args common.CreateConditionArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildCreateConditionSendTxArgs", args)
+}
+
+// BuildResolveConditionTx returns the transaction which resolves a condition by its preimage or its oracle.
+func (fc *Client) BuildResolveConditionTx(ctx context.Context, args common.ResolveConditionArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildResolveConditionTx", args)
+}
+
+// ResolveCondition resolves a condition by its preimage or its oracle, signed by the node.
+func (fc *Client) ResolveCondition(ctx context.Context, args common.ResolveConditionArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_resolveCondition", args)
+}
+
+// SignResolveConditionTx returns the transaction which resolves a condition by its preimage or its oracle, signed by the node.
+func (fc *Client) SignResolveConditionTx(ctx context.Context, args common.ResolveConditionArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signResolveConditionTx", args)
+}
+
+// ResolveConditionWithPassphrase resolves a condition by its preimage or its oracle, signed by the node with the key unlocked by passwd.
+func (fc *Client) ResolveConditionWithPassphrase(ctx context.Context, args common.ResolveConditionArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_resolveCondition", args, passwd)
+}
+
+// BuildResolveConditionSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildResolveConditionSendTxArgs(ctx context.Context, args common.ResolveConditionArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildResolveConditionSendTxArgs", args)
+}
+
+// BuildConditionalTransferTx returns the transaction which sends an asset to the recipient once a condition is resolved before a deadline.
+func (fc *Client) BuildConditionalTransferTx(ctx context.Context, args common.ConditionalTransferArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildConditionalTransferTx", args)
+}
+
+// ConditionalTransfer sends an asset to the recipient once a condition is resolved before a deadline, signed by the node.
+func (fc *Client) ConditionalTransfer(ctx context.Context, args common.ConditionalTransferArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_conditionalTransfer", args)
+}
+
+// SignConditionalTransferTx returns the transaction which sends an asset to the recipient once a condition is resolved before a deadline, signed by the node.
+func (fc *Client) SignConditionalTransferTx(ctx context.Context, args common.ConditionalTransferArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signConditionalTransferTx", args)
+}
+
+// ConditionalTransferWithPassphrase sends an asset to the recipient once a condition is resolved before a deadline, signed by the node with the key unlocked by passwd.
+func (fc *Client) ConditionalTransferWithPassphrase(ctx context.Context, args common.ConditionalTransferArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_conditionalTransfer", args, passwd)
+}
+
+// BuildConditionalTransferSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildConditionalTransferSendTxArgs(ctx context.Context, args common.ConditionalTransferArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildConditionalTransferSendTxArgs", args)
+}
+
+// BuildSettleConditionalTx returns the transaction which pays a conditional transfer to its recipient or refunds it to its sender.
+func (fc *Client) BuildSettleConditionalTx(ctx context.Context, args common.SettleConditionalArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildSettleConditionalTx", args)
+}
+
+// SettleConditional pays a conditional transfer to its recipient or refunds it to its sender, signed by the node.
+func (fc *Client) SettleConditional(ctx context.Context, args common.SettleConditionalArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_settleConditional", args)
+}
+
+// SignSettleConditionalTx returns the transaction which pays a conditional transfer to its recipient or refunds it to its sender, signed by the node.
+func (fc *Client) SignSettleConditionalTx(ctx context.Context, args common.SettleConditionalArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signSettleConditionalTx", args)
+}
+
+// SettleConditionalWithPassphrase pays a conditional transfer to its recipient or refunds it to its sender, signed by the node with the key unlocked by passwd.
+func (fc *Client) SettleConditionalWithPassphrase(ctx context.Context, args common.SettleConditionalArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_settleConditional", args, passwd)
+}
+
+// BuildSettleConditionalSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildSettleConditionalSendTxArgs(ctx context.Context, args common.SettleConditionalArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildSettleConditionalSendTxArgs", args)
+}
+
+// BuildAttestDepositTx returns the transaction which attests a deposit on an external chain, minting it once the committee majority attested it.
+func (fc *Client) BuildAttestDepositTx(ctx context.Context, args common.AttestDepositArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildAttestDepositTx", args)
+}
+
+// AttestDeposit attests a deposit on an external chain, minting it once the committee majority attested it, signed by the node.
+func (fc *Client) AttestDeposit(ctx context.Context, args common.AttestDepositArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_attestDeposit", args)
+}
+
+// SignAttestDepositTx returns the transaction which attests a deposit on an external chain, minting it once the committee majority attested it, signed by the node.
+func (fc *Client) SignAttestDepositTx(ctx context.Context, args common.AttestDepositArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signAttestDepositTx", args)
+}
+
+// AttestDepositWithPassphrase attests a deposit on an external chain, minting it once the committee majority attested it, signed by the node with the key unlocked by passwd.
+func (fc *Client) AttestDepositWithPassphrase(ctx context.Context, args common.AttestDepositArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_attestDeposit", args, passwd)
+}
+
+// BuildAttestDepositSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildAttestDepositSendTxArgs(ctx context.Context, args common.AttestDepositArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildAttestDepositSendTxArgs", args)
+}
+
+// BuildBridgeWithdrawTx returns the transaction which burns a bridge asset to release it on an external chain.
+func (fc *Client) BuildBridgeWithdrawTx(ctx context.Context, args common.BridgeWithdrawArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildBridgeWithdrawTx", args)
+}
+
+// BridgeWithdraw burns a bridge asset to release it on an external chain, signed by the node.
+func (fc *Client) BridgeWithdraw(ctx context.Context, args common.BridgeWithdrawArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_bridgeWithdraw", args)
+}
+
+// SignBridgeWithdrawTx returns the transaction which burns a bridge asset to release it on an external chain, signed by the node.
+func (fc *Client) SignBridgeWithdrawTx(ctx context.Context, args common.BridgeWithdrawArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signBridgeWithdrawTx", args)
+}
+
+// BridgeWithdrawWithPassphrase burns a bridge asset to release it on an external chain, signed by the node with the key unlocked by passwd.
+func (fc *Client) BridgeWithdrawWithPassphrase(ctx context.Context, args common.BridgeWithdrawArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_bridgeWithdraw", args, passwd)
+}
+
+// BuildBridgeWithdrawSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildBridgeWithdrawSendTxArgs(ctx context.Context, args common.BridgeWithdrawArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildBridgeWithdrawSendTxArgs", args)
+}
+
+// BuildStakingKeyTx returns the transaction which authorizes a staking key.
+func (fc *Client) BuildStakingKeyTx(ctx context.Context, args common.StakingKeyArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildStakingKeyTx", args)
+}
+
+// SetStakingKey authorizes a staking key, signed by the node.
+func (fc *Client) SetStakingKey(ctx context.Context, args common.StakingKeyArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_setStakingKey", args)
+}
+
+// SignStakingKeyTx returns the transaction which authorizes a staking key, signed by the node.
+func (fc *Client) SignStakingKeyTx(ctx context.Context, args common.StakingKeyArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signStakingKeyTx", args)
+}
+
+// SetStakingKeyWithPassphrase authorizes a staking key, signed by the node with the key unlocked by passwd.
+func (fc *Client) SetStakingKeyWithPassphrase(ctx context.Context, args common.StakingKeyArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_setStakingKey", args, passwd)
+}
+
+// BuildStakingKeySendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildStakingKeySendTxArgs(ctx context.Context, args common.StakingKeyArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildStakingKeySendTxArgs", args)
+}
+
+// BuildStakingBuyTicketTx returns the transaction which buys a ticket with a staking key.
+func (fc *Client) BuildStakingBuyTicketTx(ctx context.Context, args common.StakingBuyTicketArgs) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildStakingBuyTicketTx", args)
+}
+
+// StakingBuyTicket buys a ticket with a staking key, signed by the node.
+func (fc *Client) StakingBuyTicket(ctx context.Context, args common.StakingBuyTicketArgs) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_stakingBuyTicket", args)
+}
+
+// SignStakingBuyTicketTx returns the transaction which buys a ticket with a staking key, signed by the node.
+func (fc *Client) SignStakingBuyTicketTx(ctx context.Context, args common.StakingBuyTicketArgs) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signStakingBuyTicketTx", args)
+}
+
+// StakingBuyTicketWithPassphrase buys a ticket with a staking key, signed by the node with the key unlocked by passwd.
+func (fc *Client) StakingBuyTicketWithPassphrase(ctx context.Context, args common.StakingBuyTicketArgs, passwd string) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsn_stakingBuyTicket", args, passwd)
+}
+
+// BuildStakingBuyTicketSendTxArgs returns the eth_sendTransaction arguments of the FSN call.
+func (fc *Client) BuildStakingBuyTicketSendTxArgs(ctx context.Context, args common.StakingBuyTicketArgs) (*SendTxArgs, error) {
+	return fc.buildSendTxArgs(ctx, "fsn_buildStakingBuyTicketSendTxArgs", args)
+}
+
+// BuildReportIllegalTx returns the transaction which reports the double
+// signing proven by content.
+func (fc *Client) BuildReportIllegalTx(ctx context.Context, args common.FusionBaseArgs, content []byte) (*types.Transaction, error) {
+	return fc.buildTx(ctx, "fsntx_buildReportIllegalTx", args, hexutil.Bytes(content))
+}
+
+// ReportIllegal reports the double signing proven by content, signed by the node.
+func (fc *Client) ReportIllegal(ctx context.Context, args common.FusionBaseArgs, content []byte) (common.Hash, error) {
+	return fc.sendTx(ctx, "fsntx_reportIllegal", args, content)
+}
+
+// SignReportIllegalTx returns the transaction which reports the double signing
+// proven by content, signed by the node.
+func (fc *Client) SignReportIllegalTx(ctx context.Context, args common.FusionBaseArgs, content []byte) (*SignTransactionResult, error) {
+	return fc.signTx(ctx, "fsntx_signReportIllegalTx", args, hexutil.Bytes(content))
+}
+
+// SignTypedCall returns the typed call args of call signed by signer, which
+// is unlocked with passwd, for another account to relay.
+func (fc *Client) SignTypedCall(ctx context.Context, signer common.Address, call []byte, deadline uint64, passwd string) (*common.TypedCallArgs, error) {
+	var result *common.TypedCallArgs
+	err := fc.c.CallContext(ctx, &result, "fsn_signTypedCall", signer, hexutil.Bytes(call), hexutil.Uint64(deadline), passwd)
+	return result, err
+}
@@ -0,0 +1,707 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package fsnclient provides a client for the fsn and fsntx RPC APIs.
+//
+// Block numbers are given as *big.Int like in ethclient, nil selects the
+// latest block and a negative number the pending block.
+package fsnclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/rpc"
+)
+
+// Client defines typed wrappers for the fsn and fsntx RPC APIs.
+type Client struct {
+	c *rpc.Client
+}
+
+// Dial connects a client to the given URL.
+func Dial(rawurl string) (*Client, error) {
+	return DialContext(context.Background(), rawurl)
+}
+
+// DialContext connects a client to the given URL with the given context.
+func DialContext(ctx context.Context, rawurl string) (*Client, error) {
+	c, err := rpc.DialContext(ctx, rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(c), nil
+}
+
+// NewClient creates a client that uses the given RPC client.
+func NewClient(c *rpc.Client) *Client {
+	return &Client{c}
+}
+
+// Close closes the underlying RPC connection.
+func (fc *Client) Close() {
+	fc.c.Close()
+}
+
+func toBlockNumArg(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+	if number.Sign() < 0 {
+		return "pending"
+	}
+	return hexutil.EncodeBig(number)
+}
+
+// toBig parses the decimal amounts returned as strings.
+func toBig(s string) (*big.Int, error) {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	return v, nil
+}
+
+func toBigs(m map[common.Hash]string) (map[common.Hash]*big.Int, error) {
+	result := make(map[common.Hash]*big.Int, len(m))
+	for id, s := range m {
+		v, err := toBig(s)
+		if err != nil {
+			return nil, err
+		}
+		result[id] = v
+	}
+	return result, nil
+}
+
+func (fc *Client) callBig(ctx context.Context, method string, args ...interface{}) (*big.Int, error) {
+	var result string
+	if err := fc.c.CallContext(ctx, &result, method, args...); err != nil {
+		return nil, err
+	}
+	return toBig(result)
+}
+
+// Node status
+
+// IsAutoBuyTicket reports whether the node buys tickets automatically.
+func (fc *Client) IsAutoBuyTicket(ctx context.Context) (bool, error) {
+	var result bool
+	err := fc.c.CallContext(ctx, &result, "fsn_isAutoBuyTicket")
+	return result, err
+}
+
+// AutoBuyTicketStatus returns the state of the automatic ticket buying of the node.
+func (fc *Client) AutoBuyTicketStatus(ctx context.Context) (*AutoBuyTicketStatus, error) {
+	var result *AutoBuyTicketStatus
+	err := fc.c.CallContext(ctx, &result, "fsn_autoBuyTicketStatus")
+	return result, err
+}
+
+// AutoReportStatus returns the state of the automatic double sign reporting of the node.
+func (fc *Client) AutoReportStatus(ctx context.Context) (*AutoReportStatus, error) {
+	var result *AutoReportStatus
+	err := fc.c.CallContext(ctx, &result, "fsn_autoReportStatus")
+	return result, err
+}
+
+// Balances
+
+// GetBalance returns the balance of assetID held by addr.
+func (fc *Client) GetBalance(ctx context.Context, assetID common.Hash, addr common.Address, number *big.Int) (*big.Int, error) {
+	return fc.callBig(ctx, "fsn_getBalance", assetID, addr, toBlockNumArg(number))
+}
+
+// GetAllBalances returns the balances of all assets held by addr.
+func (fc *Client) GetAllBalances(ctx context.Context, addr common.Address, number *big.Int) (map[common.Hash]*big.Int, error) {
+	var result map[common.Hash]string
+	if err := fc.c.CallContext(ctx, &result, "fsn_getAllBalances", addr, toBlockNumArg(number)); err != nil {
+		return nil, err
+	}
+	return toBigs(result)
+}
+
+// GetBalances returns the balances of the given assets held by the given addresses.
+func (fc *Client) GetBalances(ctx context.Context, addrs []common.Address, assetIDs []common.Hash, number *big.Int) (*BatchBalances, error) {
+	var result *BatchBalances
+	err := fc.c.CallContext(ctx, &result, "fsn_getBalances", addrs, assetIDs, toBlockNumArg(number))
+	return result, err
+}
+
+// GetTimeLockBalance returns the unexpired time locked balance of assetID held by addr.
+func (fc *Client) GetTimeLockBalance(ctx context.Context, assetID common.Hash, addr common.Address, number *big.Int) (*common.TimeLock, error) {
+	var result *common.TimeLock
+	err := fc.c.CallContext(ctx, &result, "fsn_getTimeLockBalance", assetID, addr, toBlockNumArg(number))
+	return result, err
+}
+
+// GetTimeLockValueByInterval returns the value of assetID time locked for addr over the whole interval.
+func (fc *Client) GetTimeLockValueByInterval(ctx context.Context, assetID common.Hash, addr common.Address, startTime, endTime uint64, number *big.Int) (*big.Int, error) {
+	return fc.callBig(ctx, "fsn_getTimeLockValueByInterval", assetID, addr, startTime, endTime, toBlockNumArg(number))
+}
+
+// GetAllTimeLockBalances returns the unexpired time locked balances of all assets held by addr.
+func (fc *Client) GetAllTimeLockBalances(ctx context.Context, addr common.Address, number *big.Int) (map[common.Hash]*common.TimeLock, error) {
+	var result map[common.Hash]*common.TimeLock
+	err := fc.c.CallContext(ctx, &result, "fsn_getAllTimeLockBalances", addr, toBlockNumArg(number))
+	return result, err
+}
+
+// GetRawTimeLockBalance returns the time locked balance of assetID held by addr, including expired items.
+func (fc *Client) GetRawTimeLockBalance(ctx context.Context, assetID common.Hash, addr common.Address, number *big.Int) (*common.TimeLock, error) {
+	var result *common.TimeLock
+	err := fc.c.CallContext(ctx, &result, "fsn_getRawTimeLockBalance", assetID, addr, toBlockNumArg(number))
+	return result, err
+}
+
+// GetAllRawTimeLockBalances returns the time locked balances of all assets held by addr, including expired items.
+func (fc *Client) GetAllRawTimeLockBalances(ctx context.Context, addr common.Address, number *big.Int) (map[common.Hash]*common.TimeLock, error) {
+	var result map[common.Hash]*common.TimeLock
+	err := fc.c.CallContext(ctx, &result, "fsn_getAllRawTimeLockBalances", addr, toBlockNumArg(number))
+	return result, err
+}
+
+// Notations
+
+// GetNotation returns the notation (USAN) of addr, zero if it has none.
+func (fc *Client) GetNotation(ctx context.Context, addr common.Address, number *big.Int) (uint64, error) {
+	var result uint64
+	err := fc.c.CallContext(ctx, &result, "fsn_getNotation", addr, toBlockNumArg(number))
+	return result, err
+}
+
+// GetAddressByNotation returns the address holding notation.
+func (fc *Client) GetAddressByNotation(ctx context.Context, notation uint64, number *big.Int) (common.Address, error) {
+	var result common.Address
+	err := fc.c.CallContext(ctx, &result, "fsn_getAddressByNotation", notation, toBlockNumArg(number))
+	return result, err
+}
+
+// GetAddressesByNotations returns the addresses holding the notations, nil for unknown ones.
+func (fc *Client) GetAddressesByNotations(ctx context.Context, notations []uint64, number *big.Int) ([]*common.Address, error) {
+	var result []*common.Address
+	err := fc.c.CallContext(ctx, &result, "fsn_getAddressesByNotations", notations, toBlockNumArg(number))
+	return result, err
+}
+
+// GetNotations returns the notations of the addresses.
+func (fc *Client) GetNotations(ctx context.Context, addrs []common.Address, number *big.Int) ([]uint64, error) {
+	var result []uint64
+	err := fc.c.CallContext(ctx, &result, "fsn_getNotations", addrs, toBlockNumArg(number))
+	return result, err
+}
+
+// GetNotationHistory returns the owners notation had over time.
+func (fc *Client) GetNotationHistory(ctx context.Context, notation uint64, number *big.Int) ([]common.NotationRecord, error) {
+	var result []common.NotationRecord
+	err := fc.c.CallContext(ctx, &result, "fsn_getNotationHistory", notation, toBlockNumArg(number))
+	return result, err
+}
+
+// AllNotation returns the notations of all addresses.
+func (fc *Client) AllNotation(ctx context.Context, number *big.Int) (map[common.Address]uint64, error) {
+	var result map[common.Address]uint64
+	err := fc.c.CallContext(ctx, &result, "fsn_allNotation", toBlockNumArg(number))
+	return result, err
+}
+
+// GetLatestNotation returns the last generated notation.
+func (fc *Client) GetLatestNotation(ctx context.Context, number *big.Int) (uint64, error) {
+	var result uint64
+	err := fc.c.CallContext(ctx, &result, "fsn_getLatestNotation", toBlockNumArg(number))
+	return result, err
+}
+
+// Assets
+
+// GetAsset returns the asset with the given ID.
+func (fc *Client) GetAsset(ctx context.Context, assetID common.Hash, number *big.Int) (*common.Asset, error) {
+	var result *common.Asset
+	err := fc.c.CallContext(ctx, &result, "fsn_getAsset", assetID, toBlockNumArg(number))
+	return result, err
+}
+
+// GetAssetSupplyHistory returns the supply changes of assetID after the cursor
+// of page, oldest first.
+func (fc *Client) GetAssetSupplyHistory(ctx context.Context, assetID common.Hash, number *big.Int, page *common.PageRequest) (*AssetSupplyHistory, error) {
+	var result *AssetSupplyHistory
+	err := fc.c.CallContext(ctx, &result, "fsn_getAssetSupplyHistory", assetID, toBlockNumArg(number), page)
+	return result, err
+}
+
+// GetAssetOwners returns the key set owning assetID, nil if it is owned by a
+// single account.
+func (fc *Client) GetAssetOwners(ctx context.Context, assetID common.Hash, number *big.Int) (*common.AssetOwnerSet, error) {
+	var result *common.AssetOwnerSet
+	err := fc.c.CallContext(ctx, &result, "fsn_getAssetOwners", assetID, toBlockNumArg(number))
+	return result, err
+}
+
+// GetAssetTransferStatus returns whether addr may send and receive assetID.
+func (fc *Client) GetAssetTransferStatus(ctx context.Context, assetID common.Hash, addr common.Address, number *big.Int) (*AssetTransferStatus, error) {
+	var result *AssetTransferStatus
+	err := fc.c.CallContext(ctx, &result, "fsn_getAssetTransferStatus", assetID, addr, toBlockNumArg(number))
+	return result, err
+}
+
+// CheckAssetSymbol returns whether symbol is free to be registered.
+func (fc *Client) CheckAssetSymbol(ctx context.Context, symbol string, number *big.Int) (*AssetSymbolCheck, error) {
+	var result *AssetSymbolCheck
+	err := fc.c.CallContext(ctx, &result, "fsn_checkAssetSymbol", symbol, toBlockNumArg(number))
+	return result, err
+}
+
+// GetTotalSupply returns the supply of assetID split by holding type. It is
+// served in the debug namespace as it scans the whole state.
+func (fc *Client) GetTotalSupply(ctx context.Context, assetID common.Hash, number *big.Int) (*AssetSupply, error) {
+	var result *AssetSupply
+	err := fc.c.CallContext(ctx, &result, "debug_getTotalSupply", assetID, toBlockNumArg(number))
+	return result, err
+}
+
+// GetCirculatingSupply returns the circulating supply of assetID, in the debug
+// namespace as well.
+func (fc *Client) GetCirculatingSupply(ctx context.Context, assetID common.Hash, number *big.Int) (*big.Int, error) {
+	return fc.callBig(ctx, "debug_getCirculatingSupply", assetID, toBlockNumArg(number))
+}
+
+// AllAssets returns all assets.
+func (fc *Client) AllAssets(ctx context.Context, number *big.Int) (map[common.Hash]common.Asset, error) {
+	var result map[common.Hash]common.Asset
+	err := fc.c.CallContext(ctx, &result, "fsn_allAssets", toBlockNumArg(number))
+	return result, err
+}
+
+// AllAssetsByAddress returns the assets owned by addr.
+func (fc *Client) AllAssetsByAddress(ctx context.Context, addr common.Address, number *big.Int) (map[common.Hash]common.Asset, error) {
+	var result map[common.Hash]common.Asset
+	err := fc.c.CallContext(ctx, &result, "fsn_allAssetsByAddress", addr, toBlockNumArg(number))
+	return result, err
+}
+
+// AssetExistForAddress returns the ID of the asset named assetName owned by addr.
+func (fc *Client) AssetExistForAddress(ctx context.Context, assetName string, addr common.Address, number *big.Int) (common.Hash, error) {
+	var result common.Hash
+	err := fc.c.CallContext(ctx, &result, "fsn_assetExistForAddress", assetName, addr, toBlockNumArg(number))
+	return result, err
+}
+
+// GetAllAssetsAtHash returns all assets at the given block, served from the
+// result cache of the node.
+func (fc *Client) GetAllAssetsAtHash(ctx context.Context, blockHash common.Hash) (map[common.Hash]common.Asset, error) {
+	var result map[common.Hash]common.Asset
+	err := fc.c.CallContext(ctx, &result, "fsn_getAllAssetsAtHash", blockHash)
+	return result, err
+}
+
+// GetAssetHolders returns one page of the holders of assetID.
+func (fc *Client) GetAssetHolders(ctx context.Context, assetID common.Hash, page uint64) (*AssetHolders, error) {
+	var result *AssetHolders
+	err := fc.c.CallContext(ctx, &result, "fsn_getAssetHolders", assetID, page)
+	return result, err
+}
+
+// GetAssetHolderPage returns the holders of assetID after the cursor of page.
+func (fc *Client) GetAssetHolderPage(ctx context.Context, assetID common.Hash, page *common.PageRequest) (*AssetHolderPage, error) {
+	var result *AssetHolderPage
+	err := fc.c.CallContext(ctx, &result, "fsn_getAssetHolderPage", assetID, page)
+	return result, err
+}
+
+// GetFsnCallFees returns the fees of the FSN calls.
+func (fc *Client) GetFsnCallFees(ctx context.Context, number *big.Int) (*FsnCallFeeSchedule, error) {
+	var result *FsnCallFeeSchedule
+	err := fc.c.CallContext(ctx, &result, "fsn_getFsnCallFees", toBlockNumArg(number))
+	return result, err
+}
+
+// Tickets
+
+// AllTickets returns all tickets.
+func (fc *Client) AllTickets(ctx context.Context, number *big.Int) (map[common.Hash]common.TicketDisplay, error) {
+	var result map[common.Hash]common.TicketDisplay
+	err := fc.c.CallContext(ctx, &result, "fsn_allTickets", toBlockNumArg(number))
+	return result, err
+}
+
+// AllTicketsByAddress returns the tickets owned by addr.
+func (fc *Client) AllTicketsByAddress(ctx context.Context, addr common.Address, number *big.Int) (map[common.Hash]common.TicketDisplay, error) {
+	var result map[common.Hash]common.TicketDisplay
+	err := fc.c.CallContext(ctx, &result, "fsn_allTicketsByAddress", addr, toBlockNumArg(number))
+	return result, err
+}
+
+// GetAllTicketsAtHash returns all tickets at the given block, served from the
+// result cache of the node.
+func (fc *Client) GetAllTicketsAtHash(ctx context.Context, blockHash common.Hash) (map[common.Hash]common.TicketDisplay, error) {
+	var result map[common.Hash]common.TicketDisplay
+	err := fc.c.CallContext(ctx, &result, "fsn_getAllTicketsAtHash", blockHash)
+	return result, err
+}
+
+// GetTicketsPage returns the tickets, of owner if not nil, after the cursor of page.
+func (fc *Client) GetTicketsPage(ctx context.Context, owner *common.Address, number *big.Int, page *common.PageRequest) (*TicketsPage, error) {
+	var result *TicketsPage
+	err := fc.c.CallContext(ctx, &result, "fsn_getTicketsPage", owner, toBlockNumArg(number), page)
+	return result, err
+}
+
+// TotalNumberOfTickets returns the number of tickets.
+func (fc *Client) TotalNumberOfTickets(ctx context.Context, number *big.Int) (int, error) {
+	var result int
+	err := fc.c.CallContext(ctx, &result, "fsn_totalNumberOfTickets", toBlockNumArg(number))
+	return result, err
+}
+
+// TotalNumberOfTicketsByAddress returns the number of tickets owned by addr.
+func (fc *Client) TotalNumberOfTicketsByAddress(ctx context.Context, addr common.Address, number *big.Int) (int, error) {
+	var result int
+	err := fc.c.CallContext(ctx, &result, "fsn_totalNumberOfTicketsByAddress", addr, toBlockNumArg(number))
+	return result, err
+}
+
+// TicketPrice returns the price of a ticket.
+func (fc *Client) TicketPrice(ctx context.Context, number *big.Int) (*big.Int, error) {
+	return fc.callBig(ctx, "fsn_ticketPrice", toBlockNumArg(number))
+}
+
+// GetTicketExpirySchedule returns the tickets, of owner if not nil, expiring
+// within horizonBlocks blocks by day.
+func (fc *Client) GetTicketExpirySchedule(ctx context.Context, owner *common.Address, horizonBlocks uint64) (*TicketExpirySchedule, error) {
+	var result *TicketExpirySchedule
+	err := fc.c.CallContext(ctx, &result, "fsn_getTicketExpirySchedule", owner, hexutil.Uint64(horizonBlocks))
+	return result, err
+}
+
+// GetRetreatTickets returns the tickets retreated by the block.
+func (fc *Client) GetRetreatTickets(ctx context.Context, number *big.Int) ([]RetreatTicketInfo, error) {
+	var result []RetreatTicketInfo
+	err := fc.c.CallContext(ctx, &result, "fsn_getRetreatTickets", toBlockNumArg(number))
+	return result, err
+}
+
+// GetStakeInfo returns the tickets by miner.
+func (fc *Client) GetStakeInfo(ctx context.Context, number *big.Int) (*StakeInfo, error) {
+	var result *StakeInfo
+	err := fc.c.CallContext(ctx, &result, "fsn_getStakeInfo", toBlockNumArg(number))
+	return result, err
+}
+
+// GetBlockReward returns the mining reward of the block.
+func (fc *Client) GetBlockReward(ctx context.Context, number *big.Int) (*big.Int, error) {
+	return fc.callBig(ctx, "fsn_getBlockReward", toBlockNumArg(number))
+}
+
+// GetSnapshot returns the ticket snapshot of the block.
+func (fc *Client) GetSnapshot(ctx context.Context, number *big.Int) (*Snapshot, error) {
+	var result *Snapshot
+	err := fc.c.CallContext(ctx, &result, "fsn_getSnapshot", toBlockNumArg(number))
+	return result, err
+}
+
+// GetSnapshotAtHash returns the ticket snapshot of the block with the given hash.
+func (fc *Client) GetSnapshotAtHash(ctx context.Context, hash common.Hash) (*Snapshot, error) {
+	var result *Snapshot
+	err := fc.c.CallContext(ctx, &result, "fsn_getSnapshotAtHash", hash)
+	return result, err
+}
+
+// GetTicketSelectionProof returns the data proving the ticket selection of the block.
+func (fc *Client) GetTicketSelectionProof(ctx context.Context, number *big.Int) (*SelectionProof, error) {
+	var result *SelectionProof
+	err := fc.c.CallContext(ctx, &result, "fsn_getTicketSelectionProof", toBlockNumArg(number))
+	return result, err
+}
+
+// GetStakingStatus returns the staking state of addr, or of the node's
+// account if addr is nil.
+func (fc *Client) GetStakingStatus(ctx context.Context, addr *common.Address, number *big.Int) (*StakingStatus, error) {
+	var result *StakingStatus
+	err := fc.c.CallContext(ctx, &result, "fsn_getStakingStatus", addr, toBlockNumArg(number))
+	return result, err
+}
+
+// GetStakingKey returns the staking key authorized by addr.
+func (fc *Client) GetStakingKey(ctx context.Context, addr common.Address, number *big.Int) (common.Address, error) {
+	var result common.Address
+	err := fc.c.CallContext(ctx, &result, "fsn_getStakingKey", addr, toBlockNumArg(number))
+	return result, err
+}
+
+// Swaps
+
+// GetSwap returns the swap with the given ID, with its raw stored form if includeRaw is set.
+func (fc *Client) GetSwap(ctx context.Context, swapID common.Hash, number *big.Int, includeRaw bool) (*RPCSwap, error) {
+	var result *RPCSwap
+	err := fc.c.CallContext(ctx, &result, "fsn_getSwap", swapID, toBlockNumArg(number), includeRaw)
+	return result, err
+}
+
+// GetEscrow returns the unclaimed escrow with the given ID.
+func (fc *Client) GetEscrow(ctx context.Context, escrowID common.Hash, number *big.Int) (*common.Escrow, error) {
+	var result *common.Escrow
+	err := fc.c.CallContext(ctx, &result, "fsn_getEscrow", escrowID, toBlockNumArg(number))
+	return result, err
+}
+
+// GetStream returns the payment stream with the given ID and the value its
+// recipient can withdraw.
+func (fc *Client) GetStream(ctx context.Context, streamID common.Hash, number *big.Int) (*RPCStream, error) {
+	var result *RPCStream
+	err := fc.c.CallContext(ctx, &result, "fsn_getStream", streamID, toBlockNumArg(number))
+	return result, err
+}
+
+// GetCondition returns the condition with the given ID from the condition registry.
+func (fc *Client) GetCondition(ctx context.Context, conditionID common.Hash, number *big.Int) (*common.Condition, error) {
+	var result *common.Condition
+	err := fc.c.CallContext(ctx, &result, "fsn_getCondition", conditionID, toBlockNumArg(number))
+	return result, err
+}
+
+// GetConditionalTransfer returns the unsettled conditional transfer with the given ID.
+func (fc *Client) GetConditionalTransfer(ctx context.Context, transferID common.Hash, number *big.Int) (*common.ConditionalTransfer, error) {
+	var result *common.ConditionalTransfer
+	err := fc.c.CallContext(ctx, &result, "fsn_getConditionalTransfer", transferID, toBlockNumArg(number))
+	return result, err
+}
+
+// GetBridgeInfo returns the bridge committee and the assets it mints.
+func (fc *Client) GetBridgeInfo(ctx context.Context) (*BridgeInfo, error) {
+	var result *BridgeInfo
+	err := fc.c.CallContext(ctx, &result, "fsn_getBridgeInfo")
+	return result, err
+}
+
+// GetBridgeDeposit returns the attestations of a deposit, nil if it was never attested.
+func (fc *Client) GetBridgeDeposit(ctx context.Context, depositID common.Hash, number *big.Int) (*common.BridgeDeposit, error) {
+	var result *common.BridgeDeposit
+	err := fc.c.CallContext(ctx, &result, "fsn_getBridgeDeposit", depositID, toBlockNumArg(number))
+	return result, err
+}
+
+// GetMultiSwap returns the multi swap with the given ID, with its raw stored form if includeRaw is set.
+func (fc *Client) GetMultiSwap(ctx context.Context, swapID common.Hash, number *big.Int, includeRaw bool) (*RPCMultiSwap, error) {
+	var result *RPCMultiSwap
+	err := fc.c.CallContext(ctx, &result, "fsn_getMultiSwap", swapID, toBlockNumArg(number), includeRaw)
+	return result, err
+}
+
+// AllSwaps returns all swaps.
+func (fc *Client) AllSwaps(ctx context.Context, number *big.Int) (map[common.Hash]*RPCSwap, error) {
+	var result map[common.Hash]*RPCSwap
+	err := fc.c.CallContext(ctx, &result, "fsn_allSwaps", toBlockNumArg(number))
+	return result, err
+}
+
+// AllSwapsByAddress returns the open swaps made by addr.
+func (fc *Client) AllSwapsByAddress(ctx context.Context, addr common.Address, number *big.Int) (map[common.Hash]*RPCSwap, error) {
+	var result map[common.Hash]*RPCSwap
+	err := fc.c.CallContext(ctx, &result, "fsn_allSwapsByAddress", addr, toBlockNumArg(number))
+	return result, err
+}
+
+// GetSwapHistory returns the swap records matching filter between the blocks.
+func (fc *Client) GetSwapHistory(ctx context.Context, filter SwapHistoryFilter, fromBlock, toBlock *big.Int) ([]*SwapRecord, error) {
+	var result []*SwapRecord
+	err := fc.c.CallContext(ctx, &result, "fsn_getSwapHistory", filter, toBlockNumArg(fromBlock), toBlockNumArg(toBlock))
+	return result, err
+}
+
+// DiffState returns the assets, tickets and swaps which differ between the
+// blocks fromBlock and toBlock, of addr only if it is not nil, and the balance
+// deltas of addr. The node needs the swap history index and serves it in the
+// debug namespace.
+func (fc *Client) DiffState(ctx context.Context, fromBlock, toBlock *big.Int, addr *common.Address) (*StateDiff, error) {
+	var result *StateDiff
+	err := fc.c.CallContext(ctx, &result, "debug_diffState", toBlockNumArg(fromBlock), toBlockNumArg(toBlock), addr)
+	return result, err
+}
+
+// GetSwapHistoryPage returns the swap records matching filter after the cursor of page.
+func (fc *Client) GetSwapHistoryPage(ctx context.Context, filter SwapHistoryFilter, page *common.PageRequest) (*SwapHistoryPage, error) {
+	var result *SwapHistoryPage
+	err := fc.c.CallContext(ctx, &result, "fsn_getSwapHistoryPage", filter, page)
+	return result, err
+}
+
+// GetMarketStats returns the swap market statistics of the base and quote assets.
+func (fc *Client) GetMarketStats(ctx context.Context, base, quote common.Hash) (*MarketStats, error) {
+	var result *MarketStats
+	err := fc.c.CallContext(ctx, &result, "fsn_getMarketStats", base, quote)
+	return result, err
+}
+
+// Accounts and blocks
+
+// AllInfoByAddress returns the tickets, balances and notation of addr.
+func (fc *Client) AllInfoByAddress(ctx context.Context, addr common.Address, number *big.Int) (*AllInfoForAddress, error) {
+	var result *AllInfoForAddress
+	err := fc.c.CallContext(ctx, &result, "fsn_allInfoByAddress", addr, toBlockNumArg(number))
+	return result, err
+}
+
+// GetAccountOverview returns the holdings, tickets, open swaps and pending calls of addr.
+func (fc *Client) GetAccountOverview(ctx context.Context, addr common.Address, number *big.Int) (*AccountOverview, error) {
+	var result *AccountOverview
+	err := fc.c.CallContext(ctx, &result, "fsn_getAccountOverview", addr, toBlockNumArg(number))
+	return result, err
+}
+
+// GetSpendableBalance returns the balance of assetID addr can spend once its
+// pooled transactions are mined, see SpendableBalance.
+func (fc *Client) GetSpendableBalance(ctx context.Context, assetID common.Hash, addr common.Address) (*SpendableBalance, error) {
+	var result *SpendableBalance
+	err := fc.c.CallContext(ctx, &result, "fsn_getSpendableBalance", assetID, addr)
+	return result, err
+}
+
+// GetOpenCommitments returns the open swaps, live tickets and not yet started
+// time locks encumbering the funds of addr.
+func (fc *Client) GetOpenCommitments(ctx context.Context, addr common.Address, number *big.Int) (*OpenCommitments, error) {
+	var result *OpenCommitments
+	err := fc.c.CallContext(ctx, &result, "fsn_getOpenCommitments", addr, toBlockNumArg(number))
+	return result, err
+}
+
+// GetTransactionAndReceipt returns the transaction with the given hash, its
+// decoded FSN call and its receipt.
+func (fc *Client) GetTransactionAndReceipt(ctx context.Context, hash common.Hash) (*TxAndReceipt, error) {
+	var result *TxAndReceipt
+	err := fc.c.CallContext(ctx, &result, "fsn_getTransactionAndReceipt", hash)
+	return result, err
+}
+
+// GetBlockFsnSummary returns the FSN calls of the block by kind.
+func (fc *Client) GetBlockFsnSummary(ctx context.Context, number *big.Int) (*BlockFsnSummary, error) {
+	var result *BlockFsnSummary
+	err := fc.c.CallContext(ctx, &result, "fsn_getBlockFsnSummary", toBlockNumArg(number))
+	return result, err
+}
+
+// GetHardForkReceipt returns the receipt of the hard fork of the block.
+func (fc *Client) GetHardForkReceipt(ctx context.Context, number *big.Int) (*types.Receipt, error) {
+	var result *types.Receipt
+	err := fc.c.CallContext(ctx, &result, "fsn_getHardForkReceipt", toBlockNumArg(number))
+	return result, err
+}
+
+// GetTicketRetreatRecord returns the tickets of the address retreated up to the block.
+func (fc *Client) GetTicketRetreatRecord(ctx context.Context, addr common.Address, number *big.Int) (*common.TicketRetreatRecord, error) {
+	var result *common.TicketRetreatRecord
+	err := fc.c.CallContext(ctx, &result, "fsn_getTicketRetreatRecord", addr, toBlockNumArg(number))
+	return result, err
+}
+
+// GetTicketRetreatReceipt returns the receipt of the tickets retreated by the block.
+func (fc *Client) GetTicketRetreatReceipt(ctx context.Context, number *big.Int) (*types.Receipt, error) {
+	var result *types.Receipt
+	err := fc.c.CallContext(ctx, &result, "fsn_getTicketRetreatReceipt", toBlockNumArg(number))
+	return result, err
+}
+
+// GetInternalTransfers returns the value moved by the FSN call of the transaction.
+func (fc *Client) GetInternalTransfers(ctx context.Context, txHash common.Hash) ([]*common.InternalTransfer, error) {
+	var result []*common.InternalTransfer
+	err := fc.c.CallContext(ctx, &result, "fsn_getInternalTransfers", txHash)
+	return result, err
+}
+
+// GetHistoricalStats returns the ticket, supply and swap statistics of the blocks.
+func (fc *Client) GetHistoricalStats(ctx context.Context, fromBlock, toBlock *big.Int) ([]*HistoricalStats, error) {
+	var result []*HistoricalStats
+	err := fc.c.CallContext(ctx, &result, "fsn_getHistoricalStats", toBlockNumArg(fromBlock), toBlockNumArg(toBlock))
+	return result, err
+}
+
+// GetHistoricalStatsPage returns the statistics of the blocks from fromBlock after the cursor of page.
+func (fc *Client) GetHistoricalStatsPage(ctx context.Context, fromBlock *big.Int, page *common.PageRequest) (*HistoricalStatsPage, error) {
+	var result *HistoricalStatsPage
+	err := fc.c.CallContext(ctx, &result, "fsn_getHistoricalStatsPage", toBlockNumArg(fromBlock), page)
+	return result, err
+}
+
+// GetTransactionsByFunc returns the FSN call transactions of fn between the blocks after the cursor of page.
+func (fc *Client) GetTransactionsByFunc(ctx context.Context, fn common.FSNCallFunc, fromBlock, toBlock *big.Int, page *common.PageRequest) (*FuncTxPage, error) {
+	var result *FuncTxPage
+	err := fc.c.CallContext(ctx, &result, "fsn_getTransactionsByFunc", fn, toBlockNumArg(fromBlock), toBlockNumArg(toBlock), page)
+	return result, err
+}
+
+// GetEpochSummary returns the summary of the epoch committed to the state.
+func (fc *Client) GetEpochSummary(ctx context.Context, epoch uint64, number *big.Int) (*EpochSummaryResult, error) {
+	var result *EpochSummaryResult
+	err := fc.c.CallContext(ctx, &result, "fsn_getEpochSummary", epoch, toBlockNumArg(number))
+	return result, err
+}
+
+// VerifyEpochSummary checks the summary of the epoch against the chain.
+func (fc *Client) VerifyEpochSummary(ctx context.Context, epoch uint64) (*EpochSummaryProof, error) {
+	var result *EpochSummaryProof
+	err := fc.c.CallContext(ctx, &result, "fsn_verifyEpochSummary", epoch)
+	return result, err
+}
+
+// Governance
+
+// GetProposal returns the proposal with the given ID and its tally.
+func (fc *Client) GetProposal(ctx context.Context, id common.Hash, number *big.Int) (*ProposalResult, error) {
+	var result *ProposalResult
+	err := fc.c.CallContext(ctx, &result, "fsn_getProposal", id, toBlockNumArg(number))
+	return result, err
+}
+
+// GetProposals returns all proposals and their tallies.
+func (fc *Client) GetProposals(ctx context.Context, number *big.Int) ([]*ProposalResult, error) {
+	var result []*ProposalResult
+	err := fc.c.CallContext(ctx, &result, "fsn_getProposals", toBlockNumArg(number))
+	return result, err
+}
+
+// GetProposalVote returns the vote of addr on the proposal with the given ID.
+func (fc *Client) GetProposalVote(ctx context.Context, id common.Hash, addr common.Address, number *big.Int) (*common.ProposalVote, error) {
+	var result *common.ProposalVote
+	err := fc.c.CallContext(ctx, &result, "fsn_getProposalVote", id, addr, toBlockNumArg(number))
+	return result, err
+}
+
+// Typed calls
+
+// GetTypedCallNonce returns the next typed call nonce of addr.
+func (fc *Client) GetTypedCallNonce(ctx context.Context, addr common.Address, number *big.Int) (uint64, error) {
+	var result uint64
+	err := fc.c.CallContext(ctx, &result, "fsn_getTypedCallNonce", addr, toBlockNumArg(number))
+	return result, err
+}
+
+// GetTypedData returns the typed data signer signs to authorize call. The
+// next typed call nonce of signer is used if nonce is nil.
+func (fc *Client) GetTypedData(ctx context.Context, call []byte, signer common.Address, deadline uint64, nonce *uint64) (*types.FSNTypedData, error) {
+	var result *types.FSNTypedData
+	err := fc.c.CallContext(ctx, &result, "fsn_getTypedData", hexutil.Bytes(call), signer, hexutil.Uint64(deadline), (*hexutil.Uint64)(nonce))
+	return result, err
+}
+
+// GetTypedCallSigner returns the signer of a typed call.
+func (fc *Client) GetTypedCallSigner(ctx context.Context, args common.TypedCallArgs) (common.Address, error) {
+	var result common.Address
+	err := fc.c.CallContext(ctx, &result, "fsn_getTypedCallSigner", args)
+	return result, err
+}
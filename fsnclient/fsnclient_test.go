@@ -0,0 +1,203 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnclient
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/crypto"
+	"github.com/FusionFoundation/go-fusion/rlp"
+	"github.com/FusionFoundation/go-fusion/rpc"
+)
+
+var testChainID = big.NewInt(46688)
+
+// testFsnAPI records the arguments of the fsn methods it serves
+type testFsnAPI struct {
+	blockNr rpc.BlockNumber
+}
+
+func (api *testFsnAPI) GetBalance(assetID common.Hash, addr common.AddressOrNotation, blockNr rpc.BlockNumber) (string, error) {
+	api.blockNr = blockNr
+	return "1000000000000000000000", nil
+}
+
+func (api *testFsnAPI) GetAllBalances(addr common.AddressOrNotation, blockNr rpc.BlockNumber) (map[common.Hash]string, error) {
+	api.blockNr = blockNr
+	return map[common.Hash]string{common.SystemAssetID: "5"}, nil
+}
+
+// testFsntxAPI serves the fsntx methods of the node
+type testFsntxAPI struct {
+	args common.SendAssetArgs
+}
+
+func (api *testFsntxAPI) SendAsset(args common.SendAssetArgs) (common.Hash, error) {
+	api.args = args
+	return common.HexToHash("0x5e"), nil
+}
+
+// testEthAPI serves the eth methods used to sign FSN calls locally
+type testEthAPI struct {
+	sent *types.Transaction
+}
+
+func (api *testEthAPI) GetTransactionCount(addr common.Address, blockNr rpc.BlockNumber) hexutil.Uint64 {
+	return 7
+}
+
+func (api *testEthAPI) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(1e9))
+}
+
+func (api *testEthAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(testChainID)
+}
+
+func (api *testEthAPI) GetBlockByNumber(blockNr rpc.BlockNumber, fullTx bool) map[string]interface{} {
+	return map[string]interface{}{"timestamp": hexutil.Uint64(1600000000)}
+}
+
+func (api *testEthAPI) SendRawTransaction(encodedTx hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	api.sent = tx
+	return tx.Hash(), nil
+}
+
+func newTestClient(t *testing.T) (*Client, *testFsnAPI, *testFsntxAPI, *testEthAPI) {
+	var (
+		server = rpc.NewServer()
+		fsn    = new(testFsnAPI)
+		fsntx  = new(testFsntxAPI)
+		eth    = new(testEthAPI)
+	)
+	for name, service := range map[string]interface{}{"fsn": fsn, "fsntx": fsntx, "eth": eth} {
+		if err := server.RegisterName(name, service); err != nil {
+			t.Fatalf("failed to register %s: %v", name, err)
+		}
+	}
+	return NewClient(rpc.DialInProc(server)), fsn, fsntx, eth
+}
+
+func TestQueries(t *testing.T) {
+	client, fsn, _, _ := newTestClient(t)
+	defer client.Close()
+
+	ctx := context.Background()
+	addr := common.HexToAddress("0x01")
+	balance, err := client.GetBalance(ctx, common.SystemAssetID, addr, big.NewInt(12))
+	if err != nil {
+		t.Fatalf("GetBalance failed: %v", err)
+	}
+	if want, _ := new(big.Int).SetString("1000000000000000000000", 10); balance.Cmp(want) != 0 {
+		t.Errorf("balance mismatch: have %v, want %v", balance, want)
+	}
+	if fsn.blockNr != 12 {
+		t.Errorf("block number mismatch: have %d, want 12", fsn.blockNr)
+	}
+	for _, test := range []struct {
+		number *big.Int
+		want   rpc.BlockNumber
+	}{
+		{nil, rpc.LatestBlockNumber},
+		{big.NewInt(-1), rpc.PendingBlockNumber},
+	} {
+		balances, err := client.GetAllBalances(ctx, addr, test.number)
+		if err != nil {
+			t.Fatalf("GetAllBalances failed: %v", err)
+		}
+		if balances[common.SystemAssetID].Int64() != 5 {
+			t.Errorf("balances mismatch: have %v", balances)
+		}
+		if fsn.blockNr != test.want {
+			t.Errorf("block number of %v mismatch: have %d, want %d", test.number, fsn.blockNr, test.want)
+		}
+	}
+}
+
+func TestSendAsset(t *testing.T) {
+	client, _, fsntx, _ := newTestClient(t)
+	defer client.Close()
+
+	args := common.SendAssetArgs{
+		FusionBaseArgs: common.FusionBaseArgs{From: common.HexToAddress("0x01")},
+		AssetID:        common.SystemAssetID,
+		To:             common.HexToAddress("0x02"),
+		Value:          (*hexutil.Big)(big.NewInt(3)),
+	}
+	hash, err := client.SendAsset(context.Background(), args)
+	if err != nil {
+		t.Fatalf("SendAsset failed: %v", err)
+	}
+	if hash != common.HexToHash("0x5e") {
+		t.Errorf("hash mismatch: have %x", hash)
+	}
+	if fsntx.args.To != args.To || fsntx.args.Value.ToInt().Int64() != 3 {
+		t.Errorf("args mismatch: have %+v", fsntx.args)
+	}
+}
+
+func TestSendCall(t *testing.T) {
+	client, _, _, eth := newTestClient(t)
+	defer client.Close()
+
+	key, _ := crypto.GenerateKey()
+	args := &common.SendAssetArgs{
+		AssetID: common.SystemAssetID,
+		To:      common.HexToAddress("0x02"),
+		Value:   (*hexutil.Big)(big.NewInt(3)),
+	}
+	tx, err := client.SendCall(context.Background(), "sendAsset", args, key)
+	if err != nil {
+		t.Fatalf("SendCall failed: %v", err)
+	}
+	if eth.sent == nil || eth.sent.Hash() != tx.Hash() {
+		t.Fatal("transaction not sent")
+	}
+	from, err := types.Sender(types.NewEIP155Signer(testChainID), eth.sent)
+	if err != nil {
+		t.Fatalf("invalid signature: %v", err)
+	}
+	if from != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("sender mismatch: have %x", from)
+	}
+	if tx.Nonce() != 7 || tx.Gas() != DefaultCallGas || tx.GasPrice().Int64() != 1e9 {
+		t.Errorf("defaults mismatch: nonce %d, gas %d, gas price %v", tx.Nonce(), tx.Gas(), tx.GasPrice())
+	}
+	if *tx.To() != common.FSNCallAddress {
+		t.Errorf("recipient mismatch: have %x", tx.To())
+	}
+	var call common.FSNCallParam
+	if err := rlp.DecodeBytes(tx.Data(), &call); err != nil {
+		t.Fatalf("invalid call data: %v", err)
+	}
+	var param common.SendAssetParam
+	if err := rlp.DecodeBytes(call.Data, &param); err != nil {
+		t.Fatalf("invalid call param: %v", err)
+	}
+	if call.Func != common.SendAssetFunc || param.To != args.To || param.Value.Int64() != 3 {
+		t.Errorf("call mismatch: have %v %+v", call.Func, param)
+	}
+}
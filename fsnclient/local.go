@@ -0,0 +1,149 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/crypto"
+	"github.com/FusionFoundation/go-fusion/internal/fsntx"
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+// DefaultCallGas is the gas limit of the FSN calls filled in without gas,
+// the same default the node uses.
+const DefaultCallGas = 90000
+
+// BuildCall builds the unsigned transaction of the FSN call fn without a
+// node. fn is named like the fsntx build methods, e.g. "sendAsset" for
+// fsntx_buildSendAssetTx. The nonce, gas and gas price of args must be set,
+// now replaces the chain head time in the checks of time locked values.
+func BuildCall(fn string, args common.FSNBaseArgsInterface, now uint64) (*types.Transaction, error) {
+	input, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	return fsntx.Build(&fsntx.Request{Func: fn, Args: input}, now)
+}
+
+// FillCallArgs sets the nonce, gas price and gas of args which are not set,
+// from the pending state and the gas price oracle of the node.
+func (fc *Client) FillCallArgs(ctx context.Context, args common.FSNBaseArgsInterface) error {
+	base := args.BaseArgs()
+	if base.Nonce == nil {
+		var nonce hexutil.Uint64
+		if err := fc.c.CallContext(ctx, &nonce, "eth_getTransactionCount", base.From, "pending"); err != nil {
+			return err
+		}
+		base.Nonce = &nonce
+	}
+	if base.GasPrice == nil {
+		var price hexutil.Big
+		if err := fc.c.CallContext(ctx, &price, "eth_gasPrice"); err != nil {
+			return err
+		}
+		base.GasPrice = &price
+	}
+	if base.Gas == nil {
+		gas := hexutil.Uint64(DefaultCallGas)
+		base.Gas = &gas
+	}
+	return nil
+}
+
+// headTime returns the time of the latest block.
+func (fc *Client) headTime(ctx context.Context) (uint64, error) {
+	var head struct {
+		Time hexutil.Uint64 `json:"timestamp"`
+	}
+	if err := fc.c.CallContext(ctx, &head, "eth_getBlockByNumber", "latest", false); err != nil {
+		return 0, err
+	}
+	return uint64(head.Time), nil
+}
+
+// PrepareCall builds the unsigned FSN call fn locally, see BuildCall. The
+// unset transaction fields are filled in by FillCallArgs and the time of the
+// checks is the time of the latest block.
+func (fc *Client) PrepareCall(ctx context.Context, fn string, args common.FSNBaseArgsInterface) (*types.Transaction, error) {
+	if err := fc.FillCallArgs(ctx, args); err != nil {
+		return nil, err
+	}
+	now, err := fc.headTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return BuildCall(fn, args, now)
+}
+
+// SignCall prepares the FSN call fn, see PrepareCall, and signs it with key.
+// The sender of args is set to the address of key, the chain ID is read from
+// the node.
+func (fc *Client) SignCall(ctx context.Context, fn string, args common.FSNBaseArgsInterface, key *ecdsa.PrivateKey) (*types.Transaction, error) {
+	args.BaseArgs().From = crypto.PubkeyToAddress(key.PublicKey)
+	tx, err := fc.PrepareCall(ctx, fn, args)
+	if err != nil {
+		return nil, err
+	}
+	var chainID hexutil.Big
+	if err := fc.c.CallContext(ctx, &chainID, "eth_chainId"); err != nil {
+		return nil, err
+	}
+	return types.SignTx(tx, types.NewEIP155Signer((*big.Int)(&chainID)), key)
+}
+
+// SendTransaction submits a signed transaction to the node.
+func (fc *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	data, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return err
+	}
+	return fc.c.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Encode(data))
+}
+
+// GetUniqueHash returns the hash the node derives the IDs of the assets and
+// swaps created by tx of from in the next block from. Before the salted
+// unique hash fork it equals tx.UniqueHash(), after it tx.SaltedUniqueHash(from),
+// neither needs a node. from may be nil for a signed tx.
+func (fc *Client) GetUniqueHash(ctx context.Context, tx *types.Transaction, from *common.Address) (common.Hash, error) {
+	var result common.Hash
+	err := fc.c.CallContext(ctx, &result, "fsntx_getUniqueHash", tx, from)
+	return result, err
+}
+
+// CheckUniqueHash is like GetUniqueHash, it also returns an error if the
+// asset or swap tx would create already exists.
+func (fc *Client) CheckUniqueHash(ctx context.Context, tx *types.Transaction, from *common.Address) (common.Hash, error) {
+	var result common.Hash
+	err := fc.c.CallContext(ctx, &result, "fsntx_checkUniqueHash", tx, from)
+	return result, err
+}
+
+// SendCall signs the FSN call fn with key, see SignCall, and submits it.
+func (fc *Client) SendCall(ctx context.Context, fn string, args common.FSNBaseArgsInterface, key *ecdsa.PrivateKey) (*types.Transaction, error) {
+	tx, err := fc.SignCall(ctx, fn, args, key)
+	if err != nil {
+		return nil, err
+	}
+	return tx, fc.SendTransaction(ctx, tx)
+}
@@ -0,0 +1,71 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsnclient
+
+import (
+	"github.com/FusionFoundation/go-fusion/consensus/datong"
+	"github.com/FusionFoundation/go-fusion/eth/fsnindex"
+	"github.com/FusionFoundation/go-fusion/internal/ethapi"
+)
+
+// The results of the RPC methods are the types the node encodes them from,
+// aliased here as the API package is internal.
+type (
+	AutoBuyTicketStatus   = ethapi.AutoBuyTicketStatus
+	AutoReportStatus      = ethapi.AutoReportStatus
+	BatchBalances         = ethapi.BatchBalances
+	AssetTransferStatus   = ethapi.AssetTransferStatus
+	AssetSymbolCheck      = ethapi.AssetSymbolCheck
+	AssetSupply           = ethapi.AssetSupply
+	AssetSupplyHistory    = ethapi.AssetSupplyHistory
+	FsnCallFeeSchedule    = ethapi.FsnCallFeeSchedule
+	TicketsPage           = ethapi.TicketsPage
+	TicketExpirySchedule  = ethapi.TicketExpirySchedule
+	RetreatTicketInfo     = ethapi.RetreatTicketInfo
+	StakeInfo             = ethapi.StakeInfo
+	RPCSwap               = ethapi.RPCSwap
+	RPCMultiSwap          = ethapi.RPCMultiSwap
+	RPCStream             = ethapi.RPCStream
+	BridgeInfo            = ethapi.BridgeInfo
+	AllInfoForAddress     = ethapi.AllInfoForAddress
+	AccountOverview       = ethapi.AccountOverview
+	OpenCommitments       = ethapi.OpenCommitments
+	SpendableBalance      = ethapi.SpendableBalance
+	TxAndReceipt          = ethapi.TxAndReceipt
+	BlockFsnSummary       = ethapi.BlockFsnSummary
+	SendTxArgs            = ethapi.SendTxArgs
+	SignTransactionResult = ethapi.SignTransactionResult
+
+	Snapshot           = datong.Snapshot
+	SelectionProof     = datong.SelectionProof
+	StakingStatus      = datong.StakingStatus
+	ProposalResult     = datong.ProposalResult
+	EpochSummaryResult = datong.EpochSummaryResult
+	EpochSummaryProof  = datong.EpochSummaryProof
+
+	AssetHolders        = fsnindex.AssetHolders
+	AssetHolderPage     = fsnindex.AssetHolderPage
+	SwapHistoryFilter   = fsnindex.SwapHistoryFilter
+	SwapRecord          = fsnindex.SwapRecord
+	SwapHistoryPage     = fsnindex.SwapHistoryPage
+	MarketStats         = fsnindex.MarketStats
+	HistoricalStats     = fsnindex.HistoricalStats
+	HistoricalStatsPage = fsnindex.HistoricalStatsPage
+	FuncTxRecord        = fsnindex.FuncTxRecord
+	FuncTxPage          = fsnindex.FuncTxPage
+	StateDiff           = fsnindex.StateDiff
+)
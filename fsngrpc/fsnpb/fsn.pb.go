@@ -0,0 +1,1338 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: fsn.proto
+
+package fsnpb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// BlockNumber selects the state to query, the latest block if absent.
+type BlockNumber struct {
+	Number               uint64   `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockNumber) Reset()         { *m = BlockNumber{} }
+func (m *BlockNumber) String() string { return proto.CompactTextString(m) }
+func (*BlockNumber) ProtoMessage()    {}
+func (*BlockNumber) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ba700be32457ed7, []int{0}
+}
+
+func (m *BlockNumber) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockNumber.Unmarshal(m, b)
+}
+func (m *BlockNumber) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockNumber.Marshal(b, m, deterministic)
+}
+func (m *BlockNumber) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockNumber.Merge(m, src)
+}
+func (m *BlockNumber) XXX_Size() int {
+	return xxx_messageInfo_BlockNumber.Size(m)
+}
+func (m *BlockNumber) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockNumber.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockNumber proto.InternalMessageInfo
+
+func (m *BlockNumber) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+// BlockRef identifies the block whose state answered a query.
+type BlockRef struct {
+	Number               uint64   `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	Hash                 []byte   `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockRef) Reset()         { *m = BlockRef{} }
+func (m *BlockRef) String() string { return proto.CompactTextString(m) }
+func (*BlockRef) ProtoMessage()    {}
+func (*BlockRef) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ba700be32457ed7, []int{1}
+}
+
+func (m *BlockRef) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockRef.Unmarshal(m, b)
+}
+func (m *BlockRef) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockRef.Marshal(b, m, deterministic)
+}
+func (m *BlockRef) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockRef.Merge(m, src)
+}
+func (m *BlockRef) XXX_Size() int {
+	return xxx_messageInfo_BlockRef.Size(m)
+}
+func (m *BlockRef) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockRef.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockRef proto.InternalMessageInfo
+
+func (m *BlockRef) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *BlockRef) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+type BalanceRequest struct {
+	Address              []byte       `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	AssetId              []byte       `protobuf:"bytes,2,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	Block                *BlockNumber `protobuf:"bytes,3,opt,name=block,proto3" json:"block,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *BalanceRequest) Reset()         { *m = BalanceRequest{} }
+func (m *BalanceRequest) String() string { return proto.CompactTextString(m) }
+func (*BalanceRequest) ProtoMessage()    {}
+func (*BalanceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ba700be32457ed7, []int{2}
+}
+
+func (m *BalanceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BalanceRequest.Unmarshal(m, b)
+}
+func (m *BalanceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BalanceRequest.Marshal(b, m, deterministic)
+}
+func (m *BalanceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BalanceRequest.Merge(m, src)
+}
+func (m *BalanceRequest) XXX_Size() int {
+	return xxx_messageInfo_BalanceRequest.Size(m)
+}
+func (m *BalanceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BalanceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BalanceRequest proto.InternalMessageInfo
+
+func (m *BalanceRequest) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *BalanceRequest) GetAssetId() []byte {
+	if m != nil {
+		return m.AssetId
+	}
+	return nil
+}
+
+func (m *BalanceRequest) GetBlock() *BlockNumber {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+type BalanceReply struct {
+	Block                *BlockRef `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	Balance              string    `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *BalanceReply) Reset()         { *m = BalanceReply{} }
+func (m *BalanceReply) String() string { return proto.CompactTextString(m) }
+func (*BalanceReply) ProtoMessage()    {}
+func (*BalanceReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ba700be32457ed7, []int{3}
+}
+
+func (m *BalanceReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BalanceReply.Unmarshal(m, b)
+}
+func (m *BalanceReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BalanceReply.Marshal(b, m, deterministic)
+}
+func (m *BalanceReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BalanceReply.Merge(m, src)
+}
+func (m *BalanceReply) XXX_Size() int {
+	return xxx_messageInfo_BalanceReply.Size(m)
+}
+func (m *BalanceReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_BalanceReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BalanceReply proto.InternalMessageInfo
+
+func (m *BalanceReply) GetBlock() *BlockRef {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *BalanceReply) GetBalance() string {
+	if m != nil {
+		return m.Balance
+	}
+	return ""
+}
+
+type TimeLockItem struct {
+	StartTime            uint64   `protobuf:"varint,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime              uint64   `protobuf:"varint,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Value                string   `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TimeLockItem) Reset()         { *m = TimeLockItem{} }
+func (m *TimeLockItem) String() string { return proto.CompactTextString(m) }
+func (*TimeLockItem) ProtoMessage()    {}
+func (*TimeLockItem) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ba700be32457ed7, []int{4}
+}
+
+func (m *TimeLockItem) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TimeLockItem.Unmarshal(m, b)
+}
+func (m *TimeLockItem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TimeLockItem.Marshal(b, m, deterministic)
+}
+func (m *TimeLockItem) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TimeLockItem.Merge(m, src)
+}
+func (m *TimeLockItem) XXX_Size() int {
+	return xxx_messageInfo_TimeLockItem.Size(m)
+}
+func (m *TimeLockItem) XXX_DiscardUnknown() {
+	xxx_messageInfo_TimeLockItem.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TimeLockItem proto.InternalMessageInfo
+
+func (m *TimeLockItem) GetStartTime() uint64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *TimeLockItem) GetEndTime() uint64 {
+	if m != nil {
+		return m.EndTime
+	}
+	return 0
+}
+
+func (m *TimeLockItem) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type TimeLockBalanceReply struct {
+	Block                *BlockRef       `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	Items                []*TimeLockItem `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *TimeLockBalanceReply) Reset()         { *m = TimeLockBalanceReply{} }
+func (m *TimeLockBalanceReply) String() string { return proto.CompactTextString(m) }
+func (*TimeLockBalanceReply) ProtoMessage()    {}
+func (*TimeLockBalanceReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ba700be32457ed7, []int{5}
+}
+
+func (m *TimeLockBalanceReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TimeLockBalanceReply.Unmarshal(m, b)
+}
+func (m *TimeLockBalanceReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TimeLockBalanceReply.Marshal(b, m, deterministic)
+}
+func (m *TimeLockBalanceReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TimeLockBalanceReply.Merge(m, src)
+}
+func (m *TimeLockBalanceReply) XXX_Size() int {
+	return xxx_messageInfo_TimeLockBalanceReply.Size(m)
+}
+func (m *TimeLockBalanceReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_TimeLockBalanceReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TimeLockBalanceReply proto.InternalMessageInfo
+
+func (m *TimeLockBalanceReply) GetBlock() *BlockRef {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *TimeLockBalanceReply) GetItems() []*TimeLockItem {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+type TicketsRequest struct {
+	Owner                []byte       `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	Block                *BlockNumber `protobuf:"bytes,2,opt,name=block,proto3" json:"block,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *TicketsRequest) Reset()         { *m = TicketsRequest{} }
+func (m *TicketsRequest) String() string { return proto.CompactTextString(m) }
+func (*TicketsRequest) ProtoMessage()    {}
+func (*TicketsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ba700be32457ed7, []int{6}
+}
+
+func (m *TicketsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TicketsRequest.Unmarshal(m, b)
+}
+func (m *TicketsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TicketsRequest.Marshal(b, m, deterministic)
+}
+func (m *TicketsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TicketsRequest.Merge(m, src)
+}
+func (m *TicketsRequest) XXX_Size() int {
+	return xxx_messageInfo_TicketsRequest.Size(m)
+}
+func (m *TicketsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TicketsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TicketsRequest proto.InternalMessageInfo
+
+func (m *TicketsRequest) GetOwner() []byte {
+	if m != nil {
+		return m.Owner
+	}
+	return nil
+}
+
+func (m *TicketsRequest) GetBlock() *BlockNumber {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+type Ticket struct {
+	Id                   []byte   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Owner                []byte   `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	Height               uint64   `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	StartTime            uint64   `protobuf:"varint,4,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	ExpireTime           uint64   `protobuf:"varint,5,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	Value                string   `protobuf:"bytes,6,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Ticket) Reset()         { *m = Ticket{} }
+func (m *Ticket) String() string { return proto.CompactTextString(m) }
+func (*Ticket) ProtoMessage()    {}
+func (*Ticket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ba700be32457ed7, []int{7}
+}
+
+func (m *Ticket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Ticket.Unmarshal(m, b)
+}
+func (m *Ticket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Ticket.Marshal(b, m, deterministic)
+}
+func (m *Ticket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Ticket.Merge(m, src)
+}
+func (m *Ticket) XXX_Size() int {
+	return xxx_messageInfo_Ticket.Size(m)
+}
+func (m *Ticket) XXX_DiscardUnknown() {
+	xxx_messageInfo_Ticket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Ticket proto.InternalMessageInfo
+
+func (m *Ticket) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *Ticket) GetOwner() []byte {
+	if m != nil {
+		return m.Owner
+	}
+	return nil
+}
+
+func (m *Ticket) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *Ticket) GetStartTime() uint64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *Ticket) GetExpireTime() uint64 {
+	if m != nil {
+		return m.ExpireTime
+	}
+	return 0
+}
+
+func (m *Ticket) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type TicketsReply struct {
+	Block                *BlockRef `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	Tickets              []*Ticket `protobuf:"bytes,2,rep,name=tickets,proto3" json:"tickets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *TicketsReply) Reset()         { *m = TicketsReply{} }
+func (m *TicketsReply) String() string { return proto.CompactTextString(m) }
+func (*TicketsReply) ProtoMessage()    {}
+func (*TicketsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ba700be32457ed7, []int{8}
+}
+
+func (m *TicketsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TicketsReply.Unmarshal(m, b)
+}
+func (m *TicketsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TicketsReply.Marshal(b, m, deterministic)
+}
+func (m *TicketsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TicketsReply.Merge(m, src)
+}
+func (m *TicketsReply) XXX_Size() int {
+	return xxx_messageInfo_TicketsReply.Size(m)
+}
+func (m *TicketsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_TicketsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TicketsReply proto.InternalMessageInfo
+
+func (m *TicketsReply) GetBlock() *BlockRef {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *TicketsReply) GetTickets() []*Ticket {
+	if m != nil {
+		return m.Tickets
+	}
+	return nil
+}
+
+type AssetRequest struct {
+	AssetId              []byte       `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	Block                *BlockNumber `protobuf:"bytes,2,opt,name=block,proto3" json:"block,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *AssetRequest) Reset()         { *m = AssetRequest{} }
+func (m *AssetRequest) String() string { return proto.CompactTextString(m) }
+func (*AssetRequest) ProtoMessage()    {}
+func (*AssetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ba700be32457ed7, []int{9}
+}
+
+func (m *AssetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AssetRequest.Unmarshal(m, b)
+}
+func (m *AssetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AssetRequest.Marshal(b, m, deterministic)
+}
+func (m *AssetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AssetRequest.Merge(m, src)
+}
+func (m *AssetRequest) XXX_Size() int {
+	return xxx_messageInfo_AssetRequest.Size(m)
+}
+func (m *AssetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AssetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AssetRequest proto.InternalMessageInfo
+
+func (m *AssetRequest) GetAssetId() []byte {
+	if m != nil {
+		return m.AssetId
+	}
+	return nil
+}
+
+func (m *AssetRequest) GetBlock() *BlockNumber {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+type AssetReply struct {
+	Block                *BlockRef `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	Id                   []byte    `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Owner                []byte    `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
+	Name                 string    `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Symbol               string    `protobuf:"bytes,5,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Decimals             uint32    `protobuf:"varint,6,opt,name=decimals,proto3" json:"decimals,omitempty"`
+	Total                string    `protobuf:"bytes,7,opt,name=total,proto3" json:"total,omitempty"`
+	CanChange            bool      `protobuf:"varint,8,opt,name=can_change,json=canChange,proto3" json:"can_change,omitempty"`
+	Description          string    `protobuf:"bytes,9,opt,name=description,proto3" json:"description,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *AssetReply) Reset()         { *m = AssetReply{} }
+func (m *AssetReply) String() string { return proto.CompactTextString(m) }
+func (*AssetReply) ProtoMessage()    {}
+func (*AssetReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ba700be32457ed7, []int{10}
+}
+
+func (m *AssetReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AssetReply.Unmarshal(m, b)
+}
+func (m *AssetReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AssetReply.Marshal(b, m, deterministic)
+}
+func (m *AssetReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AssetReply.Merge(m, src)
+}
+func (m *AssetReply) XXX_Size() int {
+	return xxx_messageInfo_AssetReply.Size(m)
+}
+func (m *AssetReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_AssetReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AssetReply proto.InternalMessageInfo
+
+func (m *AssetReply) GetBlock() *BlockRef {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *AssetReply) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *AssetReply) GetOwner() []byte {
+	if m != nil {
+		return m.Owner
+	}
+	return nil
+}
+
+func (m *AssetReply) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AssetReply) GetSymbol() string {
+	if m != nil {
+		return m.Symbol
+	}
+	return ""
+}
+
+func (m *AssetReply) GetDecimals() uint32 {
+	if m != nil {
+		return m.Decimals
+	}
+	return 0
+}
+
+func (m *AssetReply) GetTotal() string {
+	if m != nil {
+		return m.Total
+	}
+	return ""
+}
+
+func (m *AssetReply) GetCanChange() bool {
+	if m != nil {
+		return m.CanChange
+	}
+	return false
+}
+
+func (m *AssetReply) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+type SwapRequest struct {
+	SwapId               []byte       `protobuf:"bytes,1,opt,name=swap_id,json=swapId,proto3" json:"swap_id,omitempty"`
+	Block                *BlockNumber `protobuf:"bytes,2,opt,name=block,proto3" json:"block,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *SwapRequest) Reset()         { *m = SwapRequest{} }
+func (m *SwapRequest) String() string { return proto.CompactTextString(m) }
+func (*SwapRequest) ProtoMessage()    {}
+func (*SwapRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ba700be32457ed7, []int{11}
+}
+
+func (m *SwapRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SwapRequest.Unmarshal(m, b)
+}
+func (m *SwapRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SwapRequest.Marshal(b, m, deterministic)
+}
+func (m *SwapRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SwapRequest.Merge(m, src)
+}
+func (m *SwapRequest) XXX_Size() int {
+	return xxx_messageInfo_SwapRequest.Size(m)
+}
+func (m *SwapRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SwapRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SwapRequest proto.InternalMessageInfo
+
+func (m *SwapRequest) GetSwapId() []byte {
+	if m != nil {
+		return m.SwapId
+	}
+	return nil
+}
+
+func (m *SwapRequest) GetBlock() *BlockNumber {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+type SwapReply struct {
+	Block                *BlockRef `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	Id                   []byte    `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Owner                []byte    `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
+	FromAssetId          []byte    `protobuf:"bytes,4,opt,name=from_asset_id,json=fromAssetId,proto3" json:"from_asset_id,omitempty"`
+	FromStartTime        uint64    `protobuf:"varint,5,opt,name=from_start_time,json=fromStartTime,proto3" json:"from_start_time,omitempty"`
+	FromEndTime          uint64    `protobuf:"varint,6,opt,name=from_end_time,json=fromEndTime,proto3" json:"from_end_time,omitempty"`
+	MinFromAmount        string    `protobuf:"bytes,7,opt,name=min_from_amount,json=minFromAmount,proto3" json:"min_from_amount,omitempty"`
+	ToAssetId            []byte    `protobuf:"bytes,8,opt,name=to_asset_id,json=toAssetId,proto3" json:"to_asset_id,omitempty"`
+	ToStartTime          uint64    `protobuf:"varint,9,opt,name=to_start_time,json=toStartTime,proto3" json:"to_start_time,omitempty"`
+	ToEndTime            uint64    `protobuf:"varint,10,opt,name=to_end_time,json=toEndTime,proto3" json:"to_end_time,omitempty"`
+	MinToAmount          string    `protobuf:"bytes,11,opt,name=min_to_amount,json=minToAmount,proto3" json:"min_to_amount,omitempty"`
+	SwapSize             string    `protobuf:"bytes,12,opt,name=swap_size,json=swapSize,proto3" json:"swap_size,omitempty"`
+	Targets              [][]byte  `protobuf:"bytes,13,rep,name=targets,proto3" json:"targets,omitempty"`
+	Description          string    `protobuf:"bytes,14,opt,name=description,proto3" json:"description,omitempty"`
+	Notation             uint64    `protobuf:"varint,15,opt,name=notation,proto3" json:"notation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *SwapReply) Reset()         { *m = SwapReply{} }
+func (m *SwapReply) String() string { return proto.CompactTextString(m) }
+func (*SwapReply) ProtoMessage()    {}
+func (*SwapReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ba700be32457ed7, []int{12}
+}
+
+func (m *SwapReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SwapReply.Unmarshal(m, b)
+}
+func (m *SwapReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SwapReply.Marshal(b, m, deterministic)
+}
+func (m *SwapReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SwapReply.Merge(m, src)
+}
+func (m *SwapReply) XXX_Size() int {
+	return xxx_messageInfo_SwapReply.Size(m)
+}
+func (m *SwapReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_SwapReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SwapReply proto.InternalMessageInfo
+
+func (m *SwapReply) GetBlock() *BlockRef {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *SwapReply) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *SwapReply) GetOwner() []byte {
+	if m != nil {
+		return m.Owner
+	}
+	return nil
+}
+
+func (m *SwapReply) GetFromAssetId() []byte {
+	if m != nil {
+		return m.FromAssetId
+	}
+	return nil
+}
+
+func (m *SwapReply) GetFromStartTime() uint64 {
+	if m != nil {
+		return m.FromStartTime
+	}
+	return 0
+}
+
+func (m *SwapReply) GetFromEndTime() uint64 {
+	if m != nil {
+		return m.FromEndTime
+	}
+	return 0
+}
+
+func (m *SwapReply) GetMinFromAmount() string {
+	if m != nil {
+		return m.MinFromAmount
+	}
+	return ""
+}
+
+func (m *SwapReply) GetToAssetId() []byte {
+	if m != nil {
+		return m.ToAssetId
+	}
+	return nil
+}
+
+func (m *SwapReply) GetToStartTime() uint64 {
+	if m != nil {
+		return m.ToStartTime
+	}
+	return 0
+}
+
+func (m *SwapReply) GetToEndTime() uint64 {
+	if m != nil {
+		return m.ToEndTime
+	}
+	return 0
+}
+
+func (m *SwapReply) GetMinToAmount() string {
+	if m != nil {
+		return m.MinToAmount
+	}
+	return ""
+}
+
+func (m *SwapReply) GetSwapSize() string {
+	if m != nil {
+		return m.SwapSize
+	}
+	return ""
+}
+
+func (m *SwapReply) GetTargets() [][]byte {
+	if m != nil {
+		return m.Targets
+	}
+	return nil
+}
+
+func (m *SwapReply) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *SwapReply) GetNotation() uint64 {
+	if m != nil {
+		return m.Notation
+	}
+	return 0
+}
+
+type EventsRequest struct {
+	// funcs restricts the feed to these FSNCall functions, all if empty.
+	Funcs                []uint32 `protobuf:"varint,1,rep,packed,name=funcs,proto3" json:"funcs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EventsRequest) Reset()         { *m = EventsRequest{} }
+func (m *EventsRequest) String() string { return proto.CompactTextString(m) }
+func (*EventsRequest) ProtoMessage()    {}
+func (*EventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ba700be32457ed7, []int{13}
+}
+
+func (m *EventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EventsRequest.Unmarshal(m, b)
+}
+func (m *EventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EventsRequest.Marshal(b, m, deterministic)
+}
+func (m *EventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventsRequest.Merge(m, src)
+}
+func (m *EventsRequest) XXX_Size() int {
+	return xxx_messageInfo_EventsRequest.Size(m)
+}
+func (m *EventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventsRequest proto.InternalMessageInfo
+
+func (m *EventsRequest) GetFuncs() []uint32 {
+	if m != nil {
+		return m.Funcs
+	}
+	return nil
+}
+
+type Event struct {
+	Block                *BlockRef `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	TxHash               []byte    `protobuf:"bytes,2,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	LogIndex             uint32    `protobuf:"varint,3,opt,name=log_index,json=logIndex,proto3" json:"log_index,omitempty"`
+	Func                 uint32    `protobuf:"varint,4,opt,name=func,proto3" json:"func,omitempty"`
+	FuncName             string    `protobuf:"bytes,5,opt,name=func_name,json=funcName,proto3" json:"func_name,omitempty"`
+	Data                 string    `protobuf:"bytes,6,opt,name=data,proto3" json:"data,omitempty"`
+	Removed              bool      `protobuf:"varint,7,opt,name=removed,proto3" json:"removed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6ba700be32457ed7, []int{14}
+}
+
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Event.Unmarshal(m, b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+}
+func (m *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(m, src)
+}
+func (m *Event) XXX_Size() int {
+	return xxx_messageInfo_Event.Size(m)
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetBlock() *BlockRef {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *Event) GetTxHash() []byte {
+	if m != nil {
+		return m.TxHash
+	}
+	return nil
+}
+
+func (m *Event) GetLogIndex() uint32 {
+	if m != nil {
+		return m.LogIndex
+	}
+	return 0
+}
+
+func (m *Event) GetFunc() uint32 {
+	if m != nil {
+		return m.Func
+	}
+	return 0
+}
+
+func (m *Event) GetFuncName() string {
+	if m != nil {
+		return m.FuncName
+	}
+	return ""
+}
+
+func (m *Event) GetData() string {
+	if m != nil {
+		return m.Data
+	}
+	return ""
+}
+
+func (m *Event) GetRemoved() bool {
+	if m != nil {
+		return m.Removed
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*BlockNumber)(nil), "fsn.BlockNumber")
+	proto.RegisterType((*BlockRef)(nil), "fsn.BlockRef")
+	proto.RegisterType((*BalanceRequest)(nil), "fsn.BalanceRequest")
+	proto.RegisterType((*BalanceReply)(nil), "fsn.BalanceReply")
+	proto.RegisterType((*TimeLockItem)(nil), "fsn.TimeLockItem")
+	proto.RegisterType((*TimeLockBalanceReply)(nil), "fsn.TimeLockBalanceReply")
+	proto.RegisterType((*TicketsRequest)(nil), "fsn.TicketsRequest")
+	proto.RegisterType((*Ticket)(nil), "fsn.Ticket")
+	proto.RegisterType((*TicketsReply)(nil), "fsn.TicketsReply")
+	proto.RegisterType((*AssetRequest)(nil), "fsn.AssetRequest")
+	proto.RegisterType((*AssetReply)(nil), "fsn.AssetReply")
+	proto.RegisterType((*SwapRequest)(nil), "fsn.SwapRequest")
+	proto.RegisterType((*SwapReply)(nil), "fsn.SwapReply")
+	proto.RegisterType((*EventsRequest)(nil), "fsn.EventsRequest")
+	proto.RegisterType((*Event)(nil), "fsn.Event")
+}
+
+func init() { proto.RegisterFile("fsn.proto", fileDescriptor_6ba700be32457ed7) }
+
+var fileDescriptor_6ba700be32457ed7 = []byte{
+	// 942 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x4f, 0x6f, 0x1b, 0x45,
+	0x14, 0xd7, 0xfa, 0xef, 0xee, 0x5b, 0x6f, 0x4c, 0x87, 0xa8, 0x75, 0x8d, 0x00, 0x6b, 0x51, 0x8a,
+	0x25, 0xa4, 0x08, 0xa5, 0x88, 0x7b, 0x03, 0x6d, 0x88, 0x04, 0x91, 0x98, 0xe4, 0xd4, 0x03, 0xd6,
+	0x78, 0x77, 0x6c, 0xaf, 0xe2, 0x9d, 0x31, 0x9e, 0x71, 0x92, 0xf6, 0xb3, 0xf0, 0x65, 0xb8, 0xf3,
+	0x4d, 0xb8, 0x73, 0x46, 0xef, 0xcd, 0xac, 0xbd, 0x4e, 0x29, 0x34, 0x12, 0x27, 0xef, 0xfb, 0xff,
+	0xe6, 0xf7, 0x7e, 0xf3, 0xc6, 0x10, 0xcd, 0x8c, 0x3a, 0x5e, 0xad, 0xb5, 0xd5, 0xac, 0x39, 0x33,
+	0x2a, 0x3d, 0x82, 0xf8, 0x74, 0xa9, 0xb3, 0xeb, 0x8b, 0x4d, 0x39, 0x95, 0x6b, 0xf6, 0x18, 0x3a,
+	0x8a, 0xbe, 0x06, 0xc1, 0x28, 0x18, 0xb7, 0xb8, 0x97, 0xd2, 0x6f, 0x21, 0x24, 0x37, 0x2e, 0x67,
+	0xef, 0xf3, 0x61, 0x0c, 0x5a, 0x0b, 0x61, 0x16, 0x83, 0xc6, 0x28, 0x18, 0xf7, 0x38, 0x7d, 0xa7,
+	0x25, 0x1c, 0x9c, 0x8a, 0xa5, 0x50, 0x99, 0xe4, 0xf2, 0xd7, 0x8d, 0x34, 0x96, 0x0d, 0xa0, 0x2b,
+	0xf2, 0x7c, 0x2d, 0x8d, 0xa1, 0xf0, 0x1e, 0xaf, 0x44, 0xf6, 0x14, 0x42, 0x61, 0x8c, 0xb4, 0x93,
+	0x22, 0xf7, 0x39, 0xba, 0x24, 0x9f, 0xe7, 0xec, 0x19, 0xb4, 0xa7, 0x58, 0x7e, 0xd0, 0x1c, 0x05,
+	0xe3, 0xf8, 0xe4, 0xa3, 0x63, 0x3c, 0x45, 0xad, 0x6f, 0xee, 0xcc, 0xe9, 0x4f, 0xd0, 0xdb, 0x96,
+	0x5b, 0x2d, 0xdf, 0xb0, 0x2f, 0xaa, 0xb8, 0x80, 0xe2, 0x92, 0x5d, 0x1c, 0x97, 0x33, 0x1f, 0x84,
+	0x1d, 0x4d, 0x5d, 0x10, 0x95, 0x8d, 0x78, 0x25, 0xa6, 0xbf, 0x40, 0xef, 0xaa, 0x28, 0xe5, 0x8f,
+	0x3a, 0xbb, 0x3e, 0xb7, 0xb2, 0x64, 0x9f, 0x02, 0x18, 0x2b, 0xd6, 0x76, 0x62, 0x8b, 0x52, 0xfa,
+	0xd3, 0x47, 0xa4, 0x41, 0x37, 0x3c, 0x80, 0x54, 0xb9, 0x33, 0x36, 0xc8, 0xd8, 0x95, 0x2a, 0x27,
+	0xd3, 0x21, 0xb4, 0x6f, 0xc4, 0x72, 0x23, 0xe9, 0x00, 0x11, 0x77, 0x42, 0x9a, 0xc3, 0x61, 0x95,
+	0xff, 0xe1, 0x6d, 0x7f, 0x09, 0xed, 0xc2, 0xca, 0xd2, 0x0c, 0x1a, 0xa3, 0xe6, 0x38, 0x3e, 0x79,
+	0x44, 0x4e, 0xf5, 0x76, 0xb9, 0xb3, 0xa7, 0x17, 0x70, 0x70, 0x55, 0x64, 0xd7, 0xd2, 0x9a, 0x6a,
+	0x06, 0x87, 0xd0, 0xd6, 0xb7, 0xca, 0x0f, 0xb0, 0xc7, 0x9d, 0xb0, 0x03, 0xb9, 0xf1, 0xef, 0x20,
+	0xff, 0x16, 0x40, 0xc7, 0x25, 0x64, 0x07, 0xd0, 0x28, 0x72, 0x9f, 0xa5, 0x51, 0xe4, 0xbb, 0xc4,
+	0x8d, 0x7a, 0xe2, 0xc7, 0xd0, 0x59, 0xc8, 0x62, 0xbe, 0xb0, 0x74, 0xfa, 0x16, 0xf7, 0xd2, 0x3d,
+	0x38, 0x5b, 0xf7, 0xe1, 0xfc, 0x1c, 0x62, 0x79, 0xb7, 0x2a, 0xd6, 0xd2, 0xd9, 0xdb, 0x64, 0x07,
+	0xa7, 0xda, 0x07, 0xb5, 0x53, 0x07, 0xf5, 0x35, 0xf4, 0x5c, 0x77, 0xe6, 0x01, 0x60, 0x1e, 0x41,
+	0xd7, 0xba, 0x20, 0x0f, 0x67, 0xec, 0xe1, 0x44, 0x1d, 0xaf, 0x6c, 0xe9, 0xcf, 0xd0, 0x7b, 0x81,
+	0x94, 0xac, 0x80, 0xac, 0x53, 0x36, 0x78, 0x0f, 0x65, 0xff, 0x03, 0xcd, 0xbf, 0x02, 0x00, 0x9f,
+	0xf3, 0x83, 0xbb, 0x75, 0xb0, 0x37, 0xde, 0x85, 0xbd, 0x59, 0x87, 0x9d, 0x41, 0x4b, 0x09, 0x0f,
+	0x6c, 0xc4, 0xe9, 0x1b, 0x47, 0x61, 0xde, 0x94, 0x53, 0xbd, 0x24, 0x38, 0x23, 0xee, 0x25, 0x36,
+	0x84, 0x30, 0x97, 0x59, 0x51, 0x8a, 0xa5, 0x21, 0x34, 0x13, 0xbe, 0x95, 0x31, 0xbb, 0xd5, 0x56,
+	0x2c, 0x07, 0x5d, 0x07, 0x33, 0x09, 0x38, 0xbc, 0x4c, 0xa8, 0x49, 0xb6, 0x10, 0x6a, 0x2e, 0x07,
+	0xe1, 0x28, 0x18, 0x87, 0x3c, 0xca, 0x84, 0xfa, 0x8e, 0x14, 0x6c, 0x04, 0x71, 0x2e, 0x4d, 0xb6,
+	0x2e, 0x56, 0xb6, 0xd0, 0x6a, 0x10, 0x51, 0x68, 0x5d, 0x95, 0x5e, 0x40, 0x7c, 0x79, 0x2b, 0x56,
+	0x15, 0x94, 0x4f, 0xa0, 0x6b, 0x6e, 0xc5, 0x6a, 0x87, 0x64, 0x07, 0xc5, 0x07, 0x00, 0xf9, 0x67,
+	0x13, 0x22, 0x97, 0xf0, 0x7f, 0xc6, 0x31, 0x85, 0x64, 0xb6, 0xd6, 0xe5, 0x64, 0x3b, 0xe9, 0x16,
+	0x59, 0x63, 0x54, 0xbe, 0xd8, 0x4e, 0xbb, 0x4f, 0x3e, 0x35, 0x3e, 0x3b, 0xbe, 0x52, 0xe8, 0xe5,
+	0x96, 0xd3, 0x55, 0xae, 0xed, 0x9e, 0xe8, 0x90, 0x17, 0xe5, 0x7a, 0xe9, 0x77, 0xc5, 0x33, 0xe8,
+	0x97, 0x85, 0x9a, 0xb8, 0x9a, 0xa5, 0xde, 0x28, 0xeb, 0x91, 0x4f, 0xca, 0x42, 0xbd, 0xc2, 0xa2,
+	0xa4, 0x64, 0x9f, 0x41, 0x6c, 0xf5, 0xae, 0xab, 0x90, 0xba, 0x8a, 0xac, 0xae, 0x7a, 0x4a, 0x21,
+	0xb1, 0xba, 0xde, 0x51, 0xe4, 0x6a, 0x59, 0xbd, 0xeb, 0xc7, 0xe5, 0xd8, 0x76, 0x03, 0xee, 0x0e,
+	0x5a, 0x5d, 0xf5, 0x92, 0x02, 0x16, 0x9d, 0x60, 0x1d, 0xd7, 0x49, 0xec, 0x06, 0x59, 0x16, 0xea,
+	0x4a, 0xfb, 0x3e, 0x3e, 0x81, 0x88, 0x26, 0x67, 0x8a, 0xb7, 0x72, 0xd0, 0x23, 0x7b, 0x88, 0x8a,
+	0xcb, 0xe2, 0xad, 0xc4, 0xe5, 0x6a, 0xc5, 0x7a, 0x8e, 0x17, 0x2b, 0x19, 0x35, 0xf1, 0x82, 0x78,
+	0xf1, 0x3e, 0x43, 0x0e, 0xde, 0x61, 0x08, 0x92, 0x52, 0x69, 0x2b, 0xc8, 0xdc, 0xa7, 0xce, 0xb6,
+	0x72, 0x7a, 0x04, 0xc9, 0xcb, 0x1b, 0xa9, 0xf6, 0x76, 0xda, 0x6c, 0xa3, 0x32, 0x7c, 0x55, 0x9a,
+	0xe3, 0x84, 0x3b, 0x21, 0xfd, 0x3d, 0x80, 0x36, 0xf9, 0x7d, 0x18, 0x21, 0x9e, 0x40, 0xd7, 0xde,
+	0x4d, 0x6a, 0xaf, 0x58, 0xc7, 0xde, 0xfd, 0x20, 0xcc, 0x02, 0xcf, 0xb8, 0xd4, 0xf3, 0x49, 0xa1,
+	0x72, 0x79, 0x47, 0xec, 0x48, 0x78, 0xb8, 0xd4, 0xf3, 0x73, 0x94, 0xf1, 0xa2, 0x61, 0x35, 0xe2,
+	0x45, 0xc2, 0xe9, 0x1b, 0x03, 0xf0, 0x77, 0xa2, 0x84, 0xa7, 0x42, 0xc4, 0x43, 0x54, 0x5c, 0xe0,
+	0x2d, 0x64, 0xd0, 0xca, 0x85, 0x15, 0x7e, 0x6f, 0xd1, 0x37, 0x02, 0xb5, 0x96, 0xa5, 0xbe, 0x91,
+	0x39, 0x4d, 0x3b, 0xe4, 0x95, 0x78, 0xf2, 0x47, 0x03, 0x3a, 0xaf, 0x36, 0x06, 0x11, 0xf9, 0x06,
+	0xe0, 0x4c, 0x5a, 0xff, 0x56, 0xb0, 0x8f, 0xdd, 0x19, 0xf6, 0xde, 0xd7, 0xe1, 0xa3, 0x7d, 0x25,
+	0xde, 0x85, 0xef, 0x81, 0x9d, 0x49, 0x7b, 0xef, 0xa5, 0xf9, 0xe7, 0xe8, 0xa7, 0x7b, 0xaf, 0xc8,
+	0x5e, 0x16, 0x57, 0xdb, 0xaf, 0x56, 0x1f, 0xbd, 0xff, 0xae, 0x0c, 0x1f, 0xed, 0x2b, 0x31, 0xea,
+	0x18, 0xc2, 0x33, 0x69, 0x89, 0x92, 0xcc, 0x99, 0xeb, 0x0b, 0x74, 0xd8, 0xaf, 0xab, 0xd0, 0xff,
+	0x2b, 0xe8, 0x9e, 0x49, 0x8b, 0xf7, 0x98, 0xb9, 0x9b, 0x5e, 0xdb, 0x11, 0xc3, 0x83, 0x9a, 0x06,
+	0x9d, 0x9f, 0x43, 0xff, 0x72, 0x33, 0x45, 0xc2, 0x4c, 0xa5, 0x63, 0x03, 0x63, 0xe4, 0xb2, 0x47,
+	0x8d, 0x21, 0xec, 0x74, 0x5f, 0x07, 0xa7, 0xdd, 0xd7, 0xed, 0x99, 0x51, 0xab, 0xe9, 0xb4, 0x43,
+	0x7f, 0x83, 0x9e, 0xff, 0x3d, 0x00, 0xd0, 0x6b, 0xa9, 0xc0, 0x13, 0x09, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// FusionClient is the client API for Fusion service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type FusionClient interface {
+	GetBalance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceReply, error)
+	GetTimeLockBalance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*TimeLockBalanceReply, error)
+	GetTickets(ctx context.Context, in *TicketsRequest, opts ...grpc.CallOption) (*TicketsReply, error)
+	GetAsset(ctx context.Context, in *AssetRequest, opts ...grpc.CallOption) (*AssetReply, error)
+	GetSwap(ctx context.Context, in *SwapRequest, opts ...grpc.CallOption) (*SwapReply, error)
+	// SubscribeEvents streams the FSNCall logs of every new canonical block.
+	SubscribeEvents(ctx context.Context, in *EventsRequest, opts ...grpc.CallOption) (Fusion_SubscribeEventsClient, error)
+}
+
+type fusionClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewFusionClient(cc *grpc.ClientConn) FusionClient {
+	return &fusionClient{cc}
+}
+
+func (c *fusionClient) GetBalance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceReply, error) {
+	out := new(BalanceReply)
+	err := c.cc.Invoke(ctx, "/fsn.Fusion/GetBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fusionClient) GetTimeLockBalance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*TimeLockBalanceReply, error) {
+	out := new(TimeLockBalanceReply)
+	err := c.cc.Invoke(ctx, "/fsn.Fusion/GetTimeLockBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fusionClient) GetTickets(ctx context.Context, in *TicketsRequest, opts ...grpc.CallOption) (*TicketsReply, error) {
+	out := new(TicketsReply)
+	err := c.cc.Invoke(ctx, "/fsn.Fusion/GetTickets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fusionClient) GetAsset(ctx context.Context, in *AssetRequest, opts ...grpc.CallOption) (*AssetReply, error) {
+	out := new(AssetReply)
+	err := c.cc.Invoke(ctx, "/fsn.Fusion/GetAsset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fusionClient) GetSwap(ctx context.Context, in *SwapRequest, opts ...grpc.CallOption) (*SwapReply, error) {
+	out := new(SwapReply)
+	err := c.cc.Invoke(ctx, "/fsn.Fusion/GetSwap", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fusionClient) SubscribeEvents(ctx context.Context, in *EventsRequest, opts ...grpc.CallOption) (Fusion_SubscribeEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Fusion_serviceDesc.Streams[0], "/fsn.Fusion/SubscribeEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fusionSubscribeEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Fusion_SubscribeEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type fusionSubscribeEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *fusionSubscribeEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// FusionServer is the server API for Fusion service.
+type FusionServer interface {
+	GetBalance(context.Context, *BalanceRequest) (*BalanceReply, error)
+	GetTimeLockBalance(context.Context, *BalanceRequest) (*TimeLockBalanceReply, error)
+	GetTickets(context.Context, *TicketsRequest) (*TicketsReply, error)
+	GetAsset(context.Context, *AssetRequest) (*AssetReply, error)
+	GetSwap(context.Context, *SwapRequest) (*SwapReply, error)
+	// SubscribeEvents streams the FSNCall logs of every new canonical block.
+	SubscribeEvents(*EventsRequest, Fusion_SubscribeEventsServer) error
+}
+
+// UnimplementedFusionServer can be embedded to have forward compatible implementations.
+type UnimplementedFusionServer struct {
+}
+
+func (*UnimplementedFusionServer) GetBalance(ctx context.Context, req *BalanceRequest) (*BalanceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
+}
+func (*UnimplementedFusionServer) GetTimeLockBalance(ctx context.Context, req *BalanceRequest) (*TimeLockBalanceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTimeLockBalance not implemented")
+}
+func (*UnimplementedFusionServer) GetTickets(ctx context.Context, req *TicketsRequest) (*TicketsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTickets not implemented")
+}
+func (*UnimplementedFusionServer) GetAsset(ctx context.Context, req *AssetRequest) (*AssetReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAsset not implemented")
+}
+func (*UnimplementedFusionServer) GetSwap(ctx context.Context, req *SwapRequest) (*SwapReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSwap not implemented")
+}
+func (*UnimplementedFusionServer) SubscribeEvents(req *EventsRequest, srv Fusion_SubscribeEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeEvents not implemented")
+}
+
+func RegisterFusionServer(s *grpc.Server, srv FusionServer) {
+	s.RegisterService(&_Fusion_serviceDesc, srv)
+}
+
+func _Fusion_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FusionServer).GetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fsn.Fusion/GetBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FusionServer).GetBalance(ctx, req.(*BalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fusion_GetTimeLockBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FusionServer).GetTimeLockBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fsn.Fusion/GetTimeLockBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FusionServer).GetTimeLockBalance(ctx, req.(*BalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fusion_GetTickets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TicketsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FusionServer).GetTickets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fsn.Fusion/GetTickets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FusionServer).GetTickets(ctx, req.(*TicketsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fusion_GetAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FusionServer).GetAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fsn.Fusion/GetAsset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FusionServer).GetAsset(ctx, req.(*AssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fusion_GetSwap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SwapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FusionServer).GetSwap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fsn.Fusion/GetSwap",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FusionServer).GetSwap(ctx, req.(*SwapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Fusion_SubscribeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FusionServer).SubscribeEvents(m, &fusionSubscribeEventsServer{stream})
+}
+
+type Fusion_SubscribeEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type fusionSubscribeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *fusionSubscribeEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Fusion_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "fsn.Fusion",
+	HandlerType: (*FusionServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBalance",
+			Handler:    _Fusion_GetBalance_Handler,
+		},
+		{
+			MethodName: "GetTimeLockBalance",
+			Handler:    _Fusion_GetTimeLockBalance_Handler,
+		},
+		{
+			MethodName: "GetTickets",
+			Handler:    _Fusion_GetTickets_Handler,
+		},
+		{
+			MethodName: "GetAsset",
+			Handler:    _Fusion_GetAsset_Handler,
+		},
+		{
+			MethodName: "GetSwap",
+			Handler:    _Fusion_GetSwap_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeEvents",
+			Handler:       _Fusion_SubscribeEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "fsn.proto",
+}
@@ -0,0 +1,277 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsngrpc
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/fsngrpc/fsnpb"
+	"github.com/FusionFoundation/go-fusion/internal/ethapi"
+	"github.com/FusionFoundation/go-fusion/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// logsChanSize is the size of the channels listening to logs events.
+const logsChanSize = 64
+
+// fusionServer implements fsnpb.FusionServer on top of the ethapi backend.
+type fusionServer struct {
+	backend ethapi.Backend
+	quit    chan struct{}
+}
+
+// stateAt returns the state of the requested block, the latest if nil.
+func (s *fusionServer) stateAt(ctx context.Context, block *fsnpb.BlockNumber) (*state.StateDB, *fsnpb.BlockRef, error) {
+	number := rpc.LatestBlockNumber
+	if block != nil {
+		number = rpc.BlockNumber(block.Number)
+	}
+	statedb, header, err := s.backend.StateAndHeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, nil, status.Error(codes.Unavailable, err.Error())
+	}
+	if statedb == nil || header == nil {
+		return nil, nil, status.Error(codes.NotFound, "block not found")
+	}
+	return statedb, &fsnpb.BlockRef{Number: header.Number.Uint64(), Hash: header.Hash().Bytes()}, nil
+}
+
+func (s *fusionServer) GetBalance(ctx context.Context, req *fsnpb.BalanceRequest) (*fsnpb.BalanceReply, error) {
+	address, assetID, err := balanceArgs(req)
+	if err != nil {
+		return nil, err
+	}
+	statedb, block, err := s.stateAt(ctx, req.Block)
+	if err != nil {
+		return nil, err
+	}
+	balance := statedb.GetBalance(assetID, address)
+	if err := statedb.Error(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &fsnpb.BalanceReply{Block: block, Balance: balance.String()}, nil
+}
+
+func (s *fusionServer) GetTimeLockBalance(ctx context.Context, req *fsnpb.BalanceRequest) (*fsnpb.TimeLockBalanceReply, error) {
+	address, assetID, err := balanceArgs(req)
+	if err != nil {
+		return nil, err
+	}
+	statedb, block, err := s.stateAt(ctx, req.Block)
+	if err != nil {
+		return nil, err
+	}
+	timelock := statedb.GetTimeLockBalance(assetID, address)
+	if err := statedb.Error(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	reply := &fsnpb.TimeLockBalanceReply{Block: block}
+	if !timelock.IsEmpty() {
+		for _, item := range timelock.ToDisplay().Items {
+			reply.Items = append(reply.Items, &fsnpb.TimeLockItem{
+				StartTime: item.StartTime,
+				EndTime:   item.EndTime,
+				Value:     item.Value.String(),
+			})
+		}
+	}
+	return reply, nil
+}
+
+func (s *fusionServer) GetTickets(ctx context.Context, req *fsnpb.TicketsRequest) (*fsnpb.TicketsReply, error) {
+	var owner *common.Address
+	if len(req.Owner) != 0 {
+		if len(req.Owner) != common.AddressLength {
+			return nil, status.Error(codes.InvalidArgument, "invalid owner address")
+		}
+		address := common.BytesToAddress(req.Owner)
+		owner = &address
+	}
+	statedb, block, err := s.stateAt(ctx, req.Block)
+	if err != nil {
+		return nil, err
+	}
+	tickets, err := statedb.AllTickets()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	reply := &fsnpb.TicketsReply{Block: block}
+	for _, data := range tickets {
+		if owner != nil && data.Owner != *owner {
+			continue
+		}
+		for _, ticket := range data.Tickets {
+			reply.Tickets = append(reply.Tickets, &fsnpb.Ticket{
+				Id:         ticket.ID.Bytes(),
+				Owner:      data.Owner.Bytes(),
+				Height:     ticket.Height,
+				StartTime:  ticket.StartTime,
+				ExpireTime: ticket.ExpireTime,
+				Value:      ticket.Value().String(),
+			})
+		}
+	}
+	return reply, nil
+}
+
+func (s *fusionServer) GetAsset(ctx context.Context, req *fsnpb.AssetRequest) (*fsnpb.AssetReply, error) {
+	if len(req.AssetId) != common.HashLength {
+		return nil, status.Error(codes.InvalidArgument, "invalid asset id")
+	}
+	statedb, block, err := s.stateAt(ctx, req.Block)
+	if err != nil {
+		return nil, err
+	}
+	asset, err := statedb.GetAsset(common.BytesToHash(req.AssetId))
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &fsnpb.AssetReply{
+		Block:       block,
+		Id:          asset.ID.Bytes(),
+		Owner:       asset.Owner.Bytes(),
+		Name:        asset.Name,
+		Symbol:      asset.Symbol,
+		Decimals:    uint32(asset.Decimals),
+		Total:       bigString(asset.Total),
+		CanChange:   asset.CanChange,
+		Description: asset.Description,
+	}, nil
+}
+
+func (s *fusionServer) GetSwap(ctx context.Context, req *fsnpb.SwapRequest) (*fsnpb.SwapReply, error) {
+	if len(req.SwapId) != common.HashLength {
+		return nil, status.Error(codes.InvalidArgument, "invalid swap id")
+	}
+	statedb, block, err := s.stateAt(ctx, req.Block)
+	if err != nil {
+		return nil, err
+	}
+	swap, err := statedb.GetSwap(common.BytesToHash(req.SwapId))
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	targets := make([][]byte, len(swap.Targes))
+	for i, target := range swap.Targes {
+		targets[i] = target.Bytes()
+	}
+	return &fsnpb.SwapReply{
+		Block:         block,
+		Id:            swap.ID.Bytes(),
+		Owner:         swap.Owner.Bytes(),
+		FromAssetId:   swap.FromAssetID.Bytes(),
+		FromStartTime: swap.FromStartTime,
+		FromEndTime:   swap.FromEndTime,
+		MinFromAmount: bigString(swap.MinFromAmount),
+		ToAssetId:     swap.ToAssetID.Bytes(),
+		ToStartTime:   swap.ToStartTime,
+		ToEndTime:     swap.ToEndTime,
+		MinToAmount:   bigString(swap.MinToAmount),
+		SwapSize:      bigString(swap.SwapSize),
+		Targets:       targets,
+		Description:   swap.Description,
+		Notation:      swap.Notation,
+	}, nil
+}
+
+func (s *fusionServer) SubscribeEvents(req *fsnpb.EventsRequest, stream fsnpb.Fusion_SubscribeEventsServer) error {
+	funcs := make(map[common.FSNCallFunc]bool)
+	for _, fn := range req.Funcs {
+		funcs[common.FSNCallFunc(fn)] = true
+	}
+	var (
+		logsCh    = make(chan []*types.Log, logsChanSize)
+		removedCh = make(chan core.RemovedLogsEvent, logsChanSize)
+		logsSub   = s.backend.SubscribeLogsEvent(logsCh)
+		removeSub = s.backend.SubscribeRemovedLogsEvent(removedCh)
+	)
+	defer logsSub.Unsubscribe()
+	defer removeSub.Unsubscribe()
+
+	send := func(logs []*types.Log, removed bool) error {
+		for _, l := range logs {
+			if l.Address != common.FSNCallAddress || len(l.Topics) == 0 {
+				continue
+			}
+			fn, ok := common.FsnCallFuncOfTopic(l.Topics[0])
+			if !ok || len(funcs) != 0 && !funcs[fn] {
+				continue
+			}
+			err := stream.Send(&fsnpb.Event{
+				Block:    &fsnpb.BlockRef{Number: l.BlockNumber, Hash: l.BlockHash.Bytes()},
+				TxHash:   l.TxHash.Bytes(),
+				LogIndex: uint32(l.Index),
+				Func:     uint32(fn),
+				FuncName: fn.Name(),
+				Data:     string(l.Data),
+				Removed:  removed,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for {
+		select {
+		case logs := <-logsCh:
+			if err := send(logs, false); err != nil {
+				return err
+			}
+		case ev := <-removedCh:
+			if err := send(ev.Logs, true); err != nil {
+				return err
+			}
+		case err := <-logsSub.Err():
+			return err
+		case err := <-removeSub.Err():
+			return err
+		case <-stream.Context().Done():
+			return nil
+		case <-s.quit:
+			return status.Error(codes.Unavailable, "server stopped")
+		}
+	}
+}
+
+// balanceArgs validates the address and asset of a balance request.
+func balanceArgs(req *fsnpb.BalanceRequest) (common.Address, common.Hash, error) {
+	if len(req.Address) != common.AddressLength {
+		return common.Address{}, common.Hash{}, status.Error(codes.InvalidArgument, "invalid address")
+	}
+	assetID := common.SystemAssetID
+	if len(req.AssetId) != 0 {
+		if len(req.AssetId) != common.HashLength {
+			return common.Address{}, common.Hash{}, status.Error(codes.InvalidArgument, "invalid asset id")
+		}
+		assetID = common.BytesToHash(req.AssetId)
+	}
+	return common.BytesToAddress(req.Address), assetID, nil
+}
+
+func bigString(v *big.Int) string {
+	if v == nil {
+		return "0"
+	}
+	return v.String()
+}
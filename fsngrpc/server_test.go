@@ -0,0 +1,53 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsngrpc
+
+import (
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/fsngrpc/fsnpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBalanceArgs(t *testing.T) {
+	address := common.HexToAddress("0x10")
+	assetID := common.HexToHash("0xa5")
+
+	tests := []struct {
+		req     *fsnpb.BalanceRequest
+		address common.Address
+		assetID common.Hash
+		code    codes.Code
+	}{
+		{req: &fsnpb.BalanceRequest{Address: address.Bytes()}, address: address, assetID: common.SystemAssetID},
+		{req: &fsnpb.BalanceRequest{Address: address.Bytes(), AssetId: assetID.Bytes()}, address: address, assetID: assetID},
+		{req: &fsnpb.BalanceRequest{}, code: codes.InvalidArgument},
+		{req: &fsnpb.BalanceRequest{Address: address.Bytes(), AssetId: []byte{1}}, code: codes.InvalidArgument},
+	}
+	for i, test := range tests {
+		addr, id, err := balanceArgs(test.req)
+		if code := status.Code(err); code != test.code {
+			t.Errorf("test %d: error code mismatch: have %v, want %v", i, code, test.code)
+			continue
+		}
+		if err == nil && (addr != test.address || id != test.assetID) {
+			t.Errorf("test %d: have (%x, %x), want (%x, %x)", i, addr, id, test.address, test.assetID)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package fsngrpc provides a gRPC gateway to the fusion specific node data.
+package fsngrpc
+
+//go:generate protoc --go_out=plugins=grpc:fsnpb -Ifsnpb fsnpb/fsn.proto
+
+import (
+	"net"
+
+	"github.com/FusionFoundation/go-fusion/fsngrpc/fsnpb"
+	"github.com/FusionFoundation/go-fusion/internal/ethapi"
+	"github.com/FusionFoundation/go-fusion/log"
+	"github.com/FusionFoundation/go-fusion/p2p"
+	"github.com/FusionFoundation/go-fusion/rpc"
+	"google.golang.org/grpc"
+)
+
+const (
+	DefaultHost = "localhost" // Default host interface for the gRPC server
+	DefaultPort = 9545        // Default TCP port for the gRPC server
+)
+
+// Service encapsulates the gRPC gateway.
+type Service struct {
+	endpoint string         // The host:port endpoint for this service.
+	backend  ethapi.Backend // The backend that queries will operate on.
+	server   *grpc.Server
+	quit     chan struct{}
+}
+
+// New constructs a new gRPC gateway instance.
+func New(backend ethapi.Backend, endpoint string) (*Service, error) {
+	return &Service{
+		endpoint: endpoint,
+		backend:  backend,
+	}, nil
+}
+
+// Protocols returns the list of protocols exported by this service.
+func (s *Service) Protocols() []p2p.Protocol { return nil }
+
+// APIs returns the list of APIs exported by this service.
+func (s *Service) APIs() []rpc.API { return nil }
+
+// Start is called after all services have been constructed and the networking
+// layer was also initialized to spawn any goroutines required by the service.
+func (s *Service) Start(server *p2p.Server) error {
+	listener, err := net.Listen("tcp", s.endpoint)
+	if err != nil {
+		return err
+	}
+	s.quit = make(chan struct{})
+	s.server = grpc.NewServer()
+	fsnpb.RegisterFusionServer(s.server, &fusionServer{backend: s.backend, quit: s.quit})
+
+	go s.server.Serve(listener)
+	log.Info("gRPC endpoint opened", "endpoint", s.endpoint)
+	return nil
+}
+
+// Stop terminates all goroutines belonging to the service, blocking until they
+// are all terminated.
+func (s *Service) Stop() error {
+	if s.server != nil {
+		close(s.quit)
+		s.server.Stop()
+		s.server = nil
+		log.Info("gRPC endpoint closed", "endpoint", s.endpoint)
+	}
+	return nil
+}
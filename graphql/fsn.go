@@ -0,0 +1,617 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/internal/ethapi"
+	"github.com/FusionFoundation/go-fusion/rpc"
+)
+
+const (
+	// defaultPageSize is the number of entries returned by a paginated field
+	// if first is not given.
+	defaultPageSize = 100
+
+	// maxPageSize is the maximum number of entries returned by a paginated field.
+	maxPageSize = 1000
+)
+
+// PageArgs are the pagination arguments of list fields.
+type PageArgs struct {
+	First *int32
+	Skip  *int32
+}
+
+// bounds returns the [start, end) range of a list of length n selected by the
+// pagination arguments.
+func (p PageArgs) bounds(n int) (int, int) {
+	first, skip := defaultPageSize, 0
+	if p.First != nil && *p.First >= 0 {
+		first = int(*p.First)
+	}
+	if first > maxPageSize {
+		first = maxPageSize
+	}
+	if p.Skip != nil && *p.Skip > 0 {
+		skip = int(*p.Skip)
+	}
+	if skip > n {
+		skip = n
+	}
+	if skip+first > n {
+		first = n - skip
+	}
+	return skip, skip + first
+}
+
+// Asset represents a fusion asset at a particular block.
+type Asset struct {
+	backend       ethapi.Backend
+	blockNrOrHash rpc.BlockNumberOrHash
+	asset         common.Asset
+}
+
+// newAsset returns the asset with the given ID, or nil if it doesn't exist.
+func newAsset(backend ethapi.Backend, blockNrOrHash rpc.BlockNumberOrHash, state *state.StateDB, id common.Hash) *Asset {
+	asset, err := state.GetAsset(id)
+	if err != nil {
+		return nil
+	}
+	return &Asset{backend: backend, blockNrOrHash: blockNrOrHash, asset: asset}
+}
+
+func (a *Asset) ID(ctx context.Context) common.Hash {
+	return a.asset.ID
+}
+
+func (a *Asset) Owner(ctx context.Context) *Account {
+	return &Account{backend: a.backend, address: a.asset.Owner, blockNrOrHash: a.blockNrOrHash}
+}
+
+func (a *Asset) Name(ctx context.Context) string {
+	return a.asset.Name
+}
+
+func (a *Asset) Symbol(ctx context.Context) string {
+	return a.asset.Symbol
+}
+
+func (a *Asset) Decimals(ctx context.Context) int32 {
+	return int32(a.asset.Decimals)
+}
+
+func (a *Asset) Total(ctx context.Context) hexutil.Big {
+	return bigOrZero(a.asset.Total)
+}
+
+func (a *Asset) CanChange(ctx context.Context) bool {
+	return a.asset.CanChange
+}
+
+func (a *Asset) Description(ctx context.Context) string {
+	return a.asset.Description
+}
+
+// AssetBalance is the balance of one asset held by an account.
+type AssetBalance struct {
+	account *Account
+	assetID common.Hash
+	balance hexutil.Big
+}
+
+func (b *AssetBalance) AssetID(ctx context.Context) common.Hash {
+	return b.assetID
+}
+
+func (b *AssetBalance) Asset(ctx context.Context) (*Asset, error) {
+	state, err := b.account.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newAsset(b.account.backend, b.account.blockNrOrHash, state, b.assetID), nil
+}
+
+func (b *AssetBalance) Balance(ctx context.Context) hexutil.Big {
+	return b.balance
+}
+
+// TimeLockBalance is the time lock balance of one asset held by an account.
+type TimeLockBalance struct {
+	account  *Account
+	assetID  common.Hash
+	timelock *common.TimeLock
+}
+
+func (b *TimeLockBalance) AssetID(ctx context.Context) common.Hash {
+	return b.assetID
+}
+
+func (b *TimeLockBalance) Asset(ctx context.Context) (*Asset, error) {
+	state, err := b.account.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newAsset(b.account.backend, b.account.blockNrOrHash, state, b.assetID), nil
+}
+
+func (b *TimeLockBalance) Items(ctx context.Context) []*TimeLockItem {
+	items := make([]*TimeLockItem, 0, len(b.timelock.Items))
+	for _, item := range b.timelock.ToDisplay().Items {
+		items = append(items, &TimeLockItem{item: item})
+	}
+	return items
+}
+
+// TimeLockItem is one [startTime, endTime] interval of a time lock balance.
+type TimeLockItem struct {
+	item *common.TimeLockItem
+}
+
+func (i *TimeLockItem) StartTime(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(i.item.StartTime)
+}
+
+func (i *TimeLockItem) EndTime(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(i.item.EndTime)
+}
+
+func (i *TimeLockItem) Value(ctx context.Context) hexutil.Big {
+	return bigOrZero(i.item.Value)
+}
+
+// Ticket represents a mining ticket at a particular block.
+type Ticket struct {
+	backend       ethapi.Backend
+	blockNrOrHash rpc.BlockNumberOrHash
+	owner         common.Address
+	ticket        common.TicketBody
+}
+
+func (t *Ticket) ID(ctx context.Context) common.Hash {
+	return t.ticket.ID
+}
+
+func (t *Ticket) Owner(ctx context.Context) *Account {
+	return &Account{backend: t.backend, address: t.owner, blockNrOrHash: t.blockNrOrHash}
+}
+
+func (t *Ticket) Height(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(t.ticket.Height)
+}
+
+func (t *Ticket) StartTime(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(t.ticket.StartTime)
+}
+
+func (t *Ticket) ExpireTime(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(t.ticket.ExpireTime)
+}
+
+func (t *Ticket) Value(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*t.ticket.Value())
+}
+
+// TicketArgs are the filtering and pagination arguments of ticket lists.
+type TicketArgs struct {
+	Owner *common.Address
+	PageArgs
+}
+
+// listTickets returns the page of the tickets of state selected by args.
+func listTickets(backend ethapi.Backend, blockNrOrHash rpc.BlockNumberOrHash, state *state.StateDB, args TicketArgs) ([]*Ticket, error) {
+	tickets, err := state.AllTickets()
+	if err != nil {
+		return nil, err
+	}
+	var all []*Ticket
+	for _, data := range tickets {
+		if args.Owner != nil && data.Owner != *args.Owner {
+			continue
+		}
+		for _, body := range data.Tickets {
+			all = append(all, &Ticket{backend: backend, blockNrOrHash: blockNrOrHash, owner: data.Owner, ticket: body})
+		}
+	}
+	start, end := args.bounds(len(all))
+	return all[start:end], nil
+}
+
+// Swap represents an open swap at a particular block.
+type Swap struct {
+	backend       ethapi.Backend
+	blockNrOrHash rpc.BlockNumberOrHash
+	state         *state.StateDB
+	swap          common.Swap
+}
+
+func (s *Swap) ID(ctx context.Context) common.Hash {
+	return s.swap.ID
+}
+
+func (s *Swap) Owner(ctx context.Context) *Account {
+	return &Account{backend: s.backend, address: s.swap.Owner, blockNrOrHash: s.blockNrOrHash}
+}
+
+func (s *Swap) FromAssetID(ctx context.Context) common.Hash {
+	return s.swap.FromAssetID
+}
+
+func (s *Swap) FromAsset(ctx context.Context) *Asset {
+	return newAsset(s.backend, s.blockNrOrHash, s.state, s.swap.FromAssetID)
+}
+
+func (s *Swap) FromStartTime(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(s.swap.FromStartTime)
+}
+
+func (s *Swap) FromEndTime(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(s.swap.FromEndTime)
+}
+
+func (s *Swap) MinFromAmount(ctx context.Context) hexutil.Big {
+	return bigOrZero(s.swap.MinFromAmount)
+}
+
+func (s *Swap) ToAssetID(ctx context.Context) common.Hash {
+	return s.swap.ToAssetID
+}
+
+func (s *Swap) ToAsset(ctx context.Context) *Asset {
+	return newAsset(s.backend, s.blockNrOrHash, s.state, s.swap.ToAssetID)
+}
+
+func (s *Swap) ToStartTime(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(s.swap.ToStartTime)
+}
+
+func (s *Swap) ToEndTime(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(s.swap.ToEndTime)
+}
+
+func (s *Swap) MinToAmount(ctx context.Context) hexutil.Big {
+	return bigOrZero(s.swap.MinToAmount)
+}
+
+func (s *Swap) SwapSize(ctx context.Context) hexutil.Big {
+	return bigOrZero(s.swap.SwapSize)
+}
+
+func (s *Swap) Targets(ctx context.Context) []common.Address {
+	return s.swap.Targes
+}
+
+func (s *Swap) Time(ctx context.Context) hexutil.Big {
+	return bigOrZero(s.swap.Time)
+}
+
+func (s *Swap) Description(ctx context.Context) string {
+	return s.swap.Description
+}
+
+func (s *Swap) Notation(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(s.swap.Notation)
+}
+
+// MultiSwap represents an open multi swap at a particular block.
+type MultiSwap struct {
+	backend       ethapi.Backend
+	blockNrOrHash rpc.BlockNumberOrHash
+	state         *state.StateDB
+	swap          common.MultiSwap
+}
+
+func (s *MultiSwap) ID(ctx context.Context) common.Hash {
+	return s.swap.ID
+}
+
+func (s *MultiSwap) Owner(ctx context.Context) *Account {
+	return &Account{backend: s.backend, address: s.swap.Owner, blockNrOrHash: s.blockNrOrHash}
+}
+
+func (s *MultiSwap) FromAssetIDs(ctx context.Context) []common.Hash {
+	return s.swap.FromAssetID
+}
+
+func (s *MultiSwap) FromAssets(ctx context.Context) []*Asset {
+	return s.assets(s.swap.FromAssetID)
+}
+
+func (s *MultiSwap) FromStartTimes(ctx context.Context) []hexutil.Uint64 {
+	return toLongs(s.swap.FromStartTime)
+}
+
+func (s *MultiSwap) FromEndTimes(ctx context.Context) []hexutil.Uint64 {
+	return toLongs(s.swap.FromEndTime)
+}
+
+func (s *MultiSwap) MinFromAmounts(ctx context.Context) []hexutil.Big {
+	return toBigInts(s.swap.MinFromAmount)
+}
+
+func (s *MultiSwap) ToAssetIDs(ctx context.Context) []common.Hash {
+	return s.swap.ToAssetID
+}
+
+func (s *MultiSwap) ToAssets(ctx context.Context) []*Asset {
+	return s.assets(s.swap.ToAssetID)
+}
+
+func (s *MultiSwap) ToStartTimes(ctx context.Context) []hexutil.Uint64 {
+	return toLongs(s.swap.ToStartTime)
+}
+
+func (s *MultiSwap) ToEndTimes(ctx context.Context) []hexutil.Uint64 {
+	return toLongs(s.swap.ToEndTime)
+}
+
+func (s *MultiSwap) MinToAmounts(ctx context.Context) []hexutil.Big {
+	return toBigInts(s.swap.MinToAmount)
+}
+
+func (s *MultiSwap) SwapSize(ctx context.Context) hexutil.Big {
+	return bigOrZero(s.swap.SwapSize)
+}
+
+func (s *MultiSwap) Targets(ctx context.Context) []common.Address {
+	return s.swap.Targes
+}
+
+func (s *MultiSwap) Time(ctx context.Context) hexutil.Big {
+	return bigOrZero(s.swap.Time)
+}
+
+func (s *MultiSwap) Description(ctx context.Context) string {
+	return s.swap.Description
+}
+
+func (s *MultiSwap) assets(ids []common.Hash) []*Asset {
+	assets := make([]*Asset, len(ids))
+	for i, id := range ids {
+		assets[i] = newAsset(s.backend, s.blockNrOrHash, s.state, id)
+	}
+	return assets
+}
+
+// Notation represents a short account number (USAN) at a particular block.
+type Notation struct {
+	backend       ethapi.Backend
+	blockNrOrHash rpc.BlockNumberOrHash
+	state         *state.StateDB
+	notation      uint64
+}
+
+func (n *Notation) Number(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(n.notation)
+}
+
+func (n *Notation) Account(ctx context.Context) *Account {
+	address, err := n.state.GetAddressByNotation(n.notation)
+	if err != nil {
+		return nil
+	}
+	return &Account{backend: n.backend, address: address, blockNrOrHash: n.blockNrOrHash}
+}
+
+func (n *Notation) History(ctx context.Context) ([]*NotationRecord, error) {
+	records, err := n.state.GetNotationHistory(n.notation)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*NotationRecord, len(records))
+	for i := range records {
+		result[i] = &NotationRecord{record: records[i]}
+	}
+	return result, nil
+}
+
+// NotationRecord is one entry of the ownership history of a notation.
+type NotationRecord struct {
+	record common.NotationRecord
+}
+
+func (r *NotationRecord) Action(ctx context.Context) string {
+	return r.record.Action.Name()
+}
+
+func (r *NotationRecord) From(ctx context.Context) common.Address {
+	return r.record.From
+}
+
+func (r *NotationRecord) To(ctx context.Context) common.Address {
+	return r.record.To
+}
+
+func (r *NotationRecord) BlockNumber(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(r.record.BlockNumber)
+}
+
+// Fusion fields of Account
+
+func (a *Account) AssetBalance(ctx context.Context, args struct{ AssetID common.Hash }) (hexutil.Big, error) {
+	state, err := a.getState(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	return hexutil.Big(*state.GetBalance(args.AssetID, a.address)), nil
+}
+
+func (a *Account) Balances(ctx context.Context) ([]*AssetBalance, error) {
+	state, err := a.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var balances []*AssetBalance
+	for _, b := range state.GetSortedBalances(a.address) {
+		balances = append(balances, &AssetBalance{account: a, assetID: b.AssetID, balance: hexutil.Big(*b.Balance)})
+	}
+	return balances, nil
+}
+
+func (a *Account) TimeLockBalances(ctx context.Context, args struct{ AssetID *common.Hash }) ([]*TimeLockBalance, error) {
+	state, err := a.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var balances []*TimeLockBalance
+	for _, b := range state.GetSortedTimeLockBalances(a.address) {
+		if args.AssetID != nil && *args.AssetID != b.AssetID {
+			continue
+		}
+		balances = append(balances, &TimeLockBalance{account: a, assetID: b.AssetID, timelock: b.TimeLock})
+	}
+	return balances, nil
+}
+
+func (a *Account) Notation(ctx context.Context) (*Notation, error) {
+	state, err := a.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	notation := state.GetNotation(a.address)
+	if notation == 0 {
+		return nil, nil
+	}
+	return &Notation{backend: a.backend, blockNrOrHash: a.blockNrOrHash, state: state, notation: notation}, nil
+}
+
+func (a *Account) Tickets(ctx context.Context, args PageArgs) ([]*Ticket, error) {
+	state, err := a.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return listTickets(a.backend, a.blockNrOrHash, state, TicketArgs{Owner: &a.address, PageArgs: args})
+}
+
+func (a *Account) TicketCount(ctx context.Context) (int32, error) {
+	state, err := a.getState(ctx)
+	if err != nil {
+		return 0, err
+	}
+	tickets, err := state.AllTickets()
+	if err != nil {
+		return 0, err
+	}
+	return int32(tickets.NumberOfTicketsByAddress(a.address)), nil
+}
+
+// Fusion fields of Block
+
+// getState returns the state after this block.
+func (b *Block) getState(ctx context.Context) (*state.StateDB, rpc.BlockNumberOrHash, error) {
+	if b.numberOrHash == nil {
+		header, err := b.resolveHeader(ctx)
+		if err != nil {
+			return nil, rpc.BlockNumberOrHash{}, err
+		}
+		numberOrHash := rpc.BlockNumberOrHashWithHash(header.Hash(), false)
+		b.numberOrHash = &numberOrHash
+	}
+	state, _, err := b.backend.StateAndHeaderByNumberOrHash(ctx, *b.numberOrHash)
+	return state, *b.numberOrHash, err
+}
+
+func (b *Block) Asset(ctx context.Context, args struct{ ID common.Hash }) (*Asset, error) {
+	state, blockNrOrHash, err := b.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newAsset(b.backend, blockNrOrHash, state, args.ID), nil
+}
+
+func (b *Block) Swap(ctx context.Context, args struct{ ID common.Hash }) (*Swap, error) {
+	state, blockNrOrHash, err := b.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	swap, err := state.GetSwap(args.ID)
+	if err != nil {
+		return nil, nil
+	}
+	return &Swap{backend: b.backend, blockNrOrHash: blockNrOrHash, state: state, swap: swap}, nil
+}
+
+func (b *Block) MultiSwap(ctx context.Context, args struct{ ID common.Hash }) (*MultiSwap, error) {
+	state, blockNrOrHash, err := b.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	swap, err := state.GetMultiSwap(args.ID)
+	if err != nil {
+		return nil, nil
+	}
+	return &MultiSwap{backend: b.backend, blockNrOrHash: blockNrOrHash, state: state, swap: swap}, nil
+}
+
+func (b *Block) Tickets(ctx context.Context, args TicketArgs) ([]*Ticket, error) {
+	state, blockNrOrHash, err := b.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return listTickets(b.backend, blockNrOrHash, state, args)
+}
+
+func (b *Block) TicketCount(ctx context.Context) (int32, error) {
+	state, _, err := b.getState(ctx)
+	if err != nil {
+		return 0, err
+	}
+	tickets, err := state.AllTickets()
+	if err != nil {
+		return 0, err
+	}
+	return int32(tickets.NumberOfTickets()), nil
+}
+
+func (b *Block) Notation(ctx context.Context, args struct{ Number hexutil.Uint64 }) (*Notation, error) {
+	state, blockNrOrHash, err := b.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := state.GetAddressByNotation(uint64(args.Number)); err != nil {
+		return nil, nil
+	}
+	return &Notation{backend: b.backend, blockNrOrHash: blockNrOrHash, state: state, notation: uint64(args.Number)}, nil
+}
+
+func bigOrZero(v *big.Int) hexutil.Big {
+	if v == nil {
+		return hexutil.Big{}
+	}
+	return hexutil.Big(*v)
+}
+
+func toBigInts(values []*big.Int) []hexutil.Big {
+	result := make([]hexutil.Big, len(values))
+	for i, v := range values {
+		result[i] = bigOrZero(v)
+	}
+	return result
+}
+
+func toLongs(values []uint64) []hexutil.Uint64 {
+	result := make([]hexutil.Uint64, len(values))
+	for i, v := range values {
+		result[i] = hexutil.Uint64(v)
+	}
+	return result
+}
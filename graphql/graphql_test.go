@@ -26,3 +26,25 @@ func TestBuildSchema(t *testing.T) {
 		t.Errorf("Could not construct GraphQL handler: %v", err)
 	}
 }
+
+func TestPageArgsBounds(t *testing.T) {
+	i32 := func(v int32) *int32 { return &v }
+	tests := []struct {
+		args       PageArgs
+		n          int
+		start, end int
+	}{
+		{PageArgs{}, 10, 0, 10},
+		{PageArgs{}, 250, 0, defaultPageSize},
+		{PageArgs{First: i32(3)}, 10, 0, 3},
+		{PageArgs{First: i32(3), Skip: i32(8)}, 10, 8, 10},
+		{PageArgs{Skip: i32(20)}, 10, 10, 10},
+		{PageArgs{First: i32(5000)}, 2000, 0, maxPageSize},
+	}
+	for i, test := range tests {
+		start, end := test.args.bounds(test.n)
+		if start != test.start || end != test.end {
+			t.Errorf("test %d: have [%d, %d), want [%d, %d)", i, start, end, test.start, test.end)
+		}
+	}
+}
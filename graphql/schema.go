@@ -52,6 +52,131 @@ const schema string = `
         # Storage provides access to the storage of a contract account, indexed
         # by its 32 byte slot identifier.
         storage(slot: Bytes32!): Bytes32!
+        # AssetBalance is the balance of the account in the given asset.
+        assetBalance(assetID: Bytes32!): BigInt!
+        # Balances lists the non-zero balances of the account, ordered by asset ID.
+        balances: [AssetBalance!]!
+        # TimeLockBalances lists the time lock balances of the account, ordered
+        # by asset ID, optionally restricted to one asset.
+        timeLockBalances(assetID: Bytes32): [TimeLockBalance!]!
+        # Notation is the short account number of the account, if it has one.
+        notation: Notation
+        # Tickets lists the mining tickets owned by the account.
+        tickets(first: Int, skip: Int): [Ticket!]!
+        # TicketCount is the number of mining tickets owned by the account.
+        ticketCount: Int!
+    }
+
+    # Asset is a fusion asset at a particular block.
+    type Asset {
+        id: Bytes32!
+        # Owner is the account which issued the asset.
+        owner: Account!
+        name: String!
+        symbol: String!
+        decimals: Int!
+        # Total is the current supply of the asset.
+        total: BigInt!
+        # CanChange is whether the owner can change the supply.
+        canChange: Boolean!
+        description: String!
+    }
+
+    # AssetBalance is the balance of one asset held by an account.
+    type AssetBalance {
+        assetID: Bytes32!
+        # Asset is null if the asset does not exist in the state.
+        asset: Asset
+        balance: BigInt!
+    }
+
+    # TimeLockBalance is the time lock balance of one asset held by an account.
+    type TimeLockBalance {
+        assetID: Bytes32!
+        # Asset is null if the asset does not exist in the state.
+        asset: Asset
+        items: [TimeLockItem!]!
+    }
+
+    # TimeLockItem is an amount available from startTime to endTime, inclusive.
+    type TimeLockItem {
+        startTime: Long!
+        endTime: Long!
+        value: BigInt!
+    }
+
+    # Ticket is a mining ticket at a particular block.
+    type Ticket {
+        id: Bytes32!
+        owner: Account!
+        # Height is the number of the block in which the ticket was bought.
+        height: Long!
+        startTime: Long!
+        expireTime: Long!
+        # Value is the amount of FSN locked by the ticket.
+        value: BigInt!
+    }
+
+    # Swap is an open swap at a particular block.
+    type Swap {
+        id: Bytes32!
+        owner: Account!
+        fromAssetID: Bytes32!
+        fromAsset: Asset
+        fromStartTime: Long!
+        fromEndTime: Long!
+        minFromAmount: BigInt!
+        toAssetID: Bytes32!
+        toAsset: Asset
+        toStartTime: Long!
+        toEndTime: Long!
+        minToAmount: BigInt!
+        # SwapSize is the number of remaining swap units.
+        swapSize: BigInt!
+        # Targets are the only accounts allowed to take the swap, if not empty.
+        targets: [Address!]!
+        time: BigInt!
+        description: String!
+        # Notation is set if the swap sells the owner's notation.
+        notation: Long!
+    }
+
+    # MultiSwap is an open multi asset swap at a particular block.
+    type MultiSwap {
+        id: Bytes32!
+        owner: Account!
+        fromAssetIDs: [Bytes32!]!
+        fromAssets: [Asset]!
+        fromStartTimes: [Long!]!
+        fromEndTimes: [Long!]!
+        minFromAmounts: [BigInt!]!
+        toAssetIDs: [Bytes32!]!
+        toAssets: [Asset]!
+        toStartTimes: [Long!]!
+        toEndTimes: [Long!]!
+        minToAmounts: [BigInt!]!
+        swapSize: BigInt!
+        targets: [Address!]!
+        time: BigInt!
+        description: String!
+    }
+
+    # Notation is a short account number at a particular block.
+    type Notation {
+        number: Long!
+        # Account is the account the notation is assigned to, if any.
+        account: Account
+        # History lists the ownership changes of the notation.
+        history: [NotationRecord!]!
+    }
+
+    # NotationRecord is one ownership change of a notation.
+    type NotationRecord {
+        # Action is one of "Gen", "Transfer" or "Burn".
+        action: String!
+        from: Address!
+        to: Address!
+        blockNumber: Long!
     }
 
     # Log is an Ethereum event log.
@@ -204,6 +329,19 @@ const schema string = `
         logs(filter: BlockFilterCriteria!): [Log!]!
         # Account fetches an Ethereum account at the current block's state.
         account(address: Address!): Account!
+        # Asset fetches a fusion asset at the current block's state.
+        asset(id: Bytes32!): Asset
+        # Swap fetches an open swap at the current block's state.
+        swap(id: Bytes32!): Swap
+        # MultiSwap fetches an open multi swap at the current block's state.
+        multiSwap(id: Bytes32!): MultiSwap
+        # Tickets lists the mining tickets at the current block's state,
+        # optionally restricted to one owner.
+        tickets(owner: Address, first: Int, skip: Int): [Ticket!]!
+        # TicketCount is the total number of mining tickets.
+        ticketCount: Int!
+        # Notation fetches an assigned notation at the current block's state.
+        notation(number: Long!): Notation
         # Call executes a local call operation at the current block's state.
         call(data: CallData!): CallResult
         # EstimateGas estimates the amount of gas that will be required for
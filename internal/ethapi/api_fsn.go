@@ -3,6 +3,8 @@ package ethapi
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -14,9 +16,11 @@ import (
 	"github.com/FusionFoundation/go-fusion/common"
 	"github.com/FusionFoundation/go-fusion/common/hexutil"
 	"github.com/FusionFoundation/go-fusion/consensus/datong"
+	"github.com/FusionFoundation/go-fusion/core"
 	"github.com/FusionFoundation/go-fusion/core/rawdb"
 	"github.com/FusionFoundation/go-fusion/core/state"
 	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/crypto"
 	"github.com/FusionFoundation/go-fusion/log"
 	"github.com/FusionFoundation/go-fusion/rlp"
 	"github.com/FusionFoundation/go-fusion/rpc"
@@ -26,6 +30,37 @@ var lastBlockOfBuyTickets = int64(0)
 var buyTicketOnBlockMap map[common.Address]bool
 var buyTicketOnBlockMapMutex sync.Mutex
 
+// ExpensiveMethods are the fsn methods reading whole lists of the state or
+// scanning blocks, public RPC servers limit them apart from the cheap methods
+var ExpensiveMethods = []string{
+	"fsn_allAssets",
+	"fsn_allTickets",
+	"fsn_allTicketsByAddress",
+	"fsn_allInfoByAddress",
+	"fsn_allSwaps",
+	"fsn_allSwapsByAddress",
+	"fsn_getAllBalances",
+	"fsn_getStakeInfo",
+	"fsn_getRetreatTickets",
+	"fsn_totalNumberOfTickets",
+	"fsn_getTotalSupply",
+	"fsn_getCirculatingSupply",
+	"fsn_getTicketExpirySchedule",
+	"fsn_getTicketsPage",
+	"fsn_getAssetSupplyHistory",
+	"fsn_getAccountOverview",
+	"fsn_getBlockFsnSummary",
+	"fsn_getAssetHolders",
+	"fsn_getAssetHolderPage",
+	"fsn_getSwapHistory",
+	"fsn_getSwapHistoryPage",
+	"fsn_getMarketStats",
+	"fsn_getHistoricalStats",
+	"fsn_getHistoricalStatsPage",
+	"fsn_getAllAssetsAtHash",
+	"fsn_getAllTicketsAtHash",
+}
+
 //--------------------------------------------- PublicFusionAPI -------------------------------------
 
 // PublicFusionAPI ss
@@ -45,8 +80,22 @@ func (s *PublicFusionAPI) IsAutoBuyTicket(ctx context.Context) bool {
 	return common.AutoBuyTicket
 }
 
+// AutoBuyTicketStatus returns the state of the auto ticket purchaser
+func (s *PublicFusionAPI) AutoBuyTicketStatus(ctx context.Context) AutoBuyTicketStatus {
+	return GetAutoBuyTicketStatus()
+}
+
+// AutoReportStatus returns the state of the double mining auto reporter
+func (s *PublicFusionAPI) AutoReportStatus(ctx context.Context) AutoReportStatus {
+	return GetAutoReportStatus()
+}
+
 // GetBalance wacom
-func (s *PublicFusionAPI) GetBalance(ctx context.Context, assetID common.Hash, address common.Address, blockNr rpc.BlockNumber) (string, error) {
+func (s *PublicFusionAPI) GetBalance(ctx context.Context, assetID common.Hash, addr common.AddressOrNotation, blockNr rpc.BlockNumber) (string, error) {
+	address, err := s.resolveAddress(ctx, addr)
+	if err != nil {
+		return "0", err
+	}
 	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
 		return "0", err
@@ -56,7 +105,11 @@ func (s *PublicFusionAPI) GetBalance(ctx context.Context, assetID common.Hash, a
 }
 
 // GetAllBalances wacom
-func (s *PublicFusionAPI) GetAllBalances(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (map[common.Hash]string, error) {
+func (s *PublicFusionAPI) GetAllBalances(ctx context.Context, addr common.AddressOrNotation, blockNr rpc.BlockNumber) (map[common.Hash]string, error) {
+	address, err := s.resolveAddress(ctx, addr)
+	if err != nil {
+		return make(map[common.Hash]string), err
+	}
 	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
 		return make(map[common.Hash]string), err
@@ -66,7 +119,11 @@ func (s *PublicFusionAPI) GetAllBalances(ctx context.Context, address common.Add
 }
 
 // GetTimeLockBalance wacom
-func (s *PublicFusionAPI) GetTimeLockBalance(ctx context.Context, assetID common.Hash, address common.Address, blockNr rpc.BlockNumber) (*common.TimeLock, error) {
+func (s *PublicFusionAPI) GetTimeLockBalance(ctx context.Context, assetID common.Hash, addr common.AddressOrNotation, blockNr rpc.BlockNumber) (*common.TimeLock, error) {
+	address, err := s.resolveAddress(ctx, addr)
+	if err != nil {
+		return new(common.TimeLock), err
+	}
 	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
 		return new(common.TimeLock), err
@@ -78,8 +135,70 @@ func (s *PublicFusionAPI) GetTimeLockBalance(ctx context.Context, assetID common
 	return b, state.Error()
 }
 
+// maxBatchBalances is the maximum number of (address, asset) pairs in one fsn_getBalances call
+const maxBatchBalances = 10000
+
+// AssetBalance wacom
+type AssetBalance struct {
+	Balance         string           `json:"balance"`
+	TimeLockBalance *common.TimeLock `json:"timeLockBalance"`
+}
+
+// BatchBalances wacom
+type BatchBalances struct {
+	BlockNumber hexutil.Uint64   `json:"blockNumber"`
+	BlockHash   common.Hash      `json:"blockHash"`
+	Addresses   []common.Address `json:"addresses"`
+	AssetIDs    []common.Hash    `json:"assetIDs"`
+	Balances    [][]AssetBalance `json:"balances"` // indexed by [address][assetID]
+}
+
+// GetBalances returns the balances and time lock balances of all the given
+// addresses and assets, all read from the same state
+func (s *PublicFusionAPI) GetBalances(ctx context.Context, addrs []common.AddressOrNotation, assetIDs []common.Hash, blockNr rpc.BlockNumber) (*BatchBalances, error) {
+	if len(assetIDs) == 0 {
+		assetIDs = []common.Hash{common.SystemAssetID}
+	}
+	if len(addrs)*len(assetIDs) > maxBatchBalances {
+		return nil, fmt.Errorf("too many balances requested, the limit is %d", maxBatchBalances)
+	}
+	addresses := make([]common.Address, len(addrs))
+	for i, addr := range addrs {
+		address, err := s.resolveAddress(ctx, addr)
+		if err != nil {
+			return nil, err
+		}
+		addresses[i] = address
+	}
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	result := &BatchBalances{
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		BlockHash:   header.Hash(),
+		Addresses:   addresses,
+		AssetIDs:    assetIDs,
+		Balances:    make([][]AssetBalance, len(addresses)),
+	}
+	for i, address := range addresses {
+		result.Balances[i] = make([]AssetBalance, len(assetIDs))
+		for j, assetID := range assetIDs {
+			result.Balances[i][j] = AssetBalance{
+				Balance:         state.GetBalance(assetID, address).String(),
+				TimeLockBalance: state.GetTimeLockBalance(assetID, address).ToDisplay(),
+			}
+		}
+	}
+	return result, state.Error()
+}
+
 // GetTimeLockValueByInterval wacom
-func (s *PublicFusionAPI) GetTimeLockValueByInterval(ctx context.Context, assetID common.Hash, address common.Address, startTime, endTime uint64, blockNr rpc.BlockNumber) (string, error) {
+func (s *PublicFusionAPI) GetTimeLockValueByInterval(ctx context.Context, assetID common.Hash, addr common.AddressOrNotation, startTime, endTime uint64, blockNr rpc.BlockNumber) (string, error) {
+	address, err := s.resolveAddress(ctx, addr)
+	if err != nil {
+		return "0", err
+	}
 	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
 		return "0", err
@@ -98,7 +217,11 @@ func (s *PublicFusionAPI) GetTimeLockValueByInterval(ctx context.Context, assetI
 }
 
 // GetAllTimeLockBalances wacom
-func (s *PublicFusionAPI) GetAllTimeLockBalances(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (map[common.Hash]*common.TimeLock, error) {
+func (s *PublicFusionAPI) GetAllTimeLockBalances(ctx context.Context, addr common.AddressOrNotation, blockNr rpc.BlockNumber) (map[common.Hash]*common.TimeLock, error) {
+	address, err := s.resolveAddress(ctx, addr)
+	if err != nil {
+		return make(map[common.Hash]*common.TimeLock), err
+	}
 	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
 		return make(map[common.Hash]*common.TimeLock), err
@@ -113,7 +236,11 @@ func (s *PublicFusionAPI) GetAllTimeLockBalances(ctx context.Context, address co
 }
 
 // GetRawTimeLockBalance wacom
-func (s *PublicFusionAPI) GetRawTimeLockBalance(ctx context.Context, assetID common.Hash, address common.Address, blockNr rpc.BlockNumber) (*common.TimeLock, error) {
+func (s *PublicFusionAPI) GetRawTimeLockBalance(ctx context.Context, assetID common.Hash, addr common.AddressOrNotation, blockNr rpc.BlockNumber) (*common.TimeLock, error) {
+	address, err := s.resolveAddress(ctx, addr)
+	if err != nil {
+		return new(common.TimeLock), err
+	}
 	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
 		return new(common.TimeLock), err
@@ -123,7 +250,11 @@ func (s *PublicFusionAPI) GetRawTimeLockBalance(ctx context.Context, assetID com
 }
 
 // GetAllRawTimeLockBalances wacom
-func (s *PublicFusionAPI) GetAllRawTimeLockBalances(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (map[common.Hash]*common.TimeLock, error) {
+func (s *PublicFusionAPI) GetAllRawTimeLockBalances(ctx context.Context, addr common.AddressOrNotation, blockNr rpc.BlockNumber) (map[common.Hash]*common.TimeLock, error) {
+	address, err := s.resolveAddress(ctx, addr)
+	if err != nil {
+		return make(map[common.Hash]*common.TimeLock), err
+	}
 	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
 		return make(map[common.Hash]*common.TimeLock), err
@@ -133,7 +264,11 @@ func (s *PublicFusionAPI) GetAllRawTimeLockBalances(ctx context.Context, address
 }
 
 // GetNotation wacom
-func (s *PublicFusionAPI) GetNotation(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (uint64, error) {
+func (s *PublicFusionAPI) GetNotation(ctx context.Context, addr common.AddressOrNotation, blockNr rpc.BlockNumber) (uint64, error) {
+	address, err := s.resolveAddress(ctx, addr)
+	if err != nil {
+		return 0, err
+	}
 	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
 		return 0, err
@@ -156,6 +291,59 @@ func (s *PublicFusionAPI) GetAddressByNotation(ctx context.Context, notation uin
 	return address, nil
 }
 
+// maxBatchNotations is the maximum number of notations or addresses resolved
+// in one call
+const maxBatchNotations = 10000
+
+// GetAddressesByNotations resolves a list of notations in one state, the
+// address of a notation which does not exist or was burnt is null
+func (s *PublicFusionAPI) GetAddressesByNotations(ctx context.Context, notations []uint64, blockNr rpc.BlockNumber) ([]*common.Address, error) {
+	if len(notations) > maxBatchNotations {
+		return nil, fmt.Errorf("too many notations requested, the limit is %d", maxBatchNotations)
+	}
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	result := make([]*common.Address, len(notations))
+	for i, notation := range notations {
+		if address, err := state.GetAddressByNotation(notation); err == nil {
+			result[i] = &address
+		}
+	}
+	return result, state.Error()
+}
+
+// GetNotations returns the notations of a list of addresses in one state, 0
+// for the addresses without notation
+func (s *PublicFusionAPI) GetNotations(ctx context.Context, addrs []common.Address, blockNr rpc.BlockNumber) ([]uint64, error) {
+	if len(addrs) > maxBatchNotations {
+		return nil, fmt.Errorf("too many addresses requested, the limit is %d", maxBatchNotations)
+	}
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	result := make([]uint64, len(addrs))
+	for i, addr := range addrs {
+		result[i] = state.GetNotation(addr)
+	}
+	return result, state.Error()
+}
+
+// GetNotationHistory returns the ownership change records of a notation
+func (s *PublicFusionAPI) GetNotationHistory(ctx context.Context, notation uint64, blockNr rpc.BlockNumber) ([]common.NotationRecord, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	records, err := state.GetNotationHistory(notation)
+	if err != nil {
+		return nil, err
+	}
+	return records, state.Error()
+}
+
 // AllNotation wacom
 func (s *PublicFusionAPI) AllNotation(ctx context.Context, blockNr rpc.BlockNumber) (map[common.Address]uint64, error) {
 	return nil, fmt.Errorf("AllNotations has been depreciated please use api.fusionnetwork.io")
@@ -180,6 +368,253 @@ func (s *PublicFusionAPI) GetAsset(ctx context.Context, assetID common.Hash, blo
 	return nil, fmt.Errorf("Asset not found")
 }
 
+// GetAssetOwners returns the key set owning an asset, nil if the asset is
+// owned by a single account
+func (s *PublicFusionAPI) GetAssetOwners(ctx context.Context, assetID common.Hash, blockNr rpc.BlockNumber) (*common.AssetOwnerSet, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if _, err := state.GetAsset(assetID); err != nil {
+		return nil, fmt.Errorf("Asset not found")
+	}
+	return state.GetAssetOwnerSet(assetID), state.Error()
+}
+
+// AssetSupplyHistory is a page of the supply history of an asset
+type AssetSupplyHistory struct {
+	BlockNumber hexutil.Uint64              `json:"blockNumber"`
+	AssetID     common.Hash                 `json:"assetID"`
+	Count       hexutil.Uint64              `json:"count"` // length of the whole history
+	Changes     []*common.AssetSupplyChange `json:"changes"`
+	NextCursor  string                      `json:"nextCursor"` // empty after the last page
+}
+
+// GetAssetSupplyHistory returns one page of the supply changes of an asset,
+// oldest first
+func (s *PublicFusionAPI) GetAssetSupplyHistory(ctx context.Context, assetID common.Hash, blockNr rpc.BlockNumber, page *common.PageRequest) (*AssetSupplyHistory, error) {
+	pager, err := common.NewPager("supply", page)
+	if err != nil {
+		return nil, err
+	}
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if _, err := state.GetAsset(assetID); err != nil {
+		return nil, fmt.Errorf("Asset not found")
+	}
+	start := uint64(0)
+	if after := pager.After(); after != nil {
+		if len(after) != 8 {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+		start = binary.BigEndian.Uint64(after) + 1
+	}
+	count := state.GetAssetSupplyChangeCount(assetID)
+	result := &AssetSupplyHistory{
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		AssetID:     assetID,
+		Count:       hexutil.Uint64(count),
+		Changes:     []*common.AssetSupplyChange{},
+	}
+	key := make([]byte, 8)
+	for index := start; index < count; index++ {
+		binary.BigEndian.PutUint64(key, index)
+		if !pager.Add(key) {
+			break
+		}
+		change, err := state.GetAssetSupplyChange(assetID, index)
+		if err != nil {
+			return nil, err
+		}
+		result.Changes = append(result.Changes, change)
+	}
+	result.NextCursor = pager.NextCursor()
+	return result, state.Error()
+}
+
+// AssetTransferStatus is the transfer restriction of an asset for an address.
+type AssetTransferStatus struct {
+	AssetID     common.Hash                     `json:"assetID"`
+	Restriction common.AssetTransferRestriction `json:"restriction"`
+	Address     common.Address                  `json:"address"`
+	Listed      bool                            `json:"listed"`
+	Allowed     bool                            `json:"allowed"`
+}
+
+// GetAssetTransferStatus returns whether addr is on the transfer list of an
+// asset and may send and receive it
+func (s *PublicFusionAPI) GetAssetTransferStatus(ctx context.Context, assetID common.Hash, addr common.AddressOrNotation, blockNr rpc.BlockNumber) (*AssetTransferStatus, error) {
+	address, err := s.resolveAddress(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if _, err := state.GetAsset(assetID); err != nil {
+		return nil, fmt.Errorf("Asset not found")
+	}
+	return &AssetTransferStatus{
+		AssetID:     assetID,
+		Restriction: state.GetAssetTransferRestriction(assetID),
+		Address:     address,
+		Listed:      state.IsAssetTransferListed(assetID, address),
+		Allowed:     state.IsAssetTransferAllowed(assetID, address),
+	}, state.Error()
+}
+
+// FsnCallFee is the fee of an FSN call and its scheduled change.
+type FsnCallFee struct {
+	Func          common.FSNCallFunc `json:"func"`
+	Name          string             `json:"name"`
+	Fee           string             `json:"fee"`
+	PendingFee    *string            `json:"pendingFee"`
+	PendingHeight *uint64            `json:"pendingHeight"`
+}
+
+// FsnCallFeeSchedule is the fee schedule of the FSN calls in a block and the
+// governors which can change it.
+type FsnCallFeeSchedule struct {
+	BlockNumber uint64           `json:"blockNumber"`
+	Governors   []common.Address `json:"governors"`
+	Threshold   int              `json:"threshold"`
+	Fees        []*FsnCallFee    `json:"fees"`
+}
+
+// GetFsnCallFees returns the fees of the FSN calls in a block
+func (s *PublicFusionAPI) GetFsnCallFees(ctx context.Context, blockNr rpc.BlockNumber) (*FsnCallFeeSchedule, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	schedule := &FsnCallFeeSchedule{
+		BlockNumber: header.Number.Uint64(),
+		Governors:   common.GetFeeGovernors(),
+		Threshold:   common.FeeGovernanceThreshold(),
+	}
+	for fn := common.FSNCallFunc(common.GenNotationFunc); fn < common.UnknownFunc; fn++ {
+		if fn.Name() == "Unknown" || fn == common.SetFsnCallFeeFunc {
+			continue
+		}
+		fee := &FsnCallFee{
+			Func: fn,
+			Name: fn.Name(),
+			Fee:  common.GetFsnCallFeeAt(&common.FSNCallAddress, fn, header.Number, state).String(),
+		}
+		if entry := state.GetFsnCallFeeEntry(fn); entry != nil && entry.Height > header.Number.Uint64() && common.IsFsnCallFeeScheduleEnabled(header.Number) {
+			pendingFee, pendingHeight := entry.Fee.String(), entry.Height
+			fee.PendingFee, fee.PendingHeight = &pendingFee, &pendingHeight
+		}
+		schedule.Fees = append(schedule.Fees, fee)
+	}
+	return schedule, state.Error()
+}
+
+// GetHardForkReceipt returns the synthetic receipt logging the balances, time
+// locks, tickets and notations moved by the hard fork transfers of a block, nil
+// if the block has none. Blocks processed before the receipts were recorded
+// have none either, resync them to get it.
+func (s *PublicFusionAPI) GetHardForkReceipt(ctx context.Context, blockNr rpc.BlockNumber) (*types.Receipt, error) {
+	header, err := s.b.HeaderByNumber(ctx, blockNr)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	return rawdb.ReadHardForkReceipt(s.b.ChainDb(), header.Hash(), header.Number.Uint64()), nil
+}
+
+// AssetSymbolCheck is the registry entry of a normalized asset symbol.
+type AssetSymbolCheck struct {
+	Symbol     string       `json:"symbol"`
+	Normalized string       `json:"normalized"`
+	Registered bool         `json:"registered"`
+	AssetID    *common.Hash `json:"assetID"`
+}
+
+// CheckAssetSymbol looks up the asset registered with a symbol colliding with
+// the given one. Wallets should warn about GenAsset calls or assets whose
+// symbol is registered by another asset. Only the assets generated after the
+// registry hard fork are registered.
+func (s *PublicFusionAPI) CheckAssetSymbol(ctx context.Context, symbol string, blockNr rpc.BlockNumber) (*AssetSymbolCheck, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	result := &AssetSymbolCheck{
+		Symbol:     symbol,
+		Normalized: common.NormalizeAssetSymbol(symbol),
+	}
+	if id, ok := state.GetAssetIDBySymbol(symbol); ok {
+		result.Registered, result.AssetID = true, &id
+	}
+	return result, state.Error()
+}
+
+// AssetSupply is the distribution of an asset's total supply at a block.
+type AssetSupply struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	AssetID     common.Hash    `json:"assetID"`
+	Total       string         `json:"total"`      // Asset.Total
+	TimeLocked  string         `json:"timeLocked"` // time lock balances usable at the block time
+	Tickets     string         `json:"tickets"`    // value of the live tickets, FSN only
+	Liquid      string         `json:"liquid"`     // plain balances
+	Other       string         `json:"other"`      // the remainder, held by open swaps
+}
+
+// GetTotalSupply returns the total supply of an asset split into the time
+// locked, ticket and liquid amounts. It scans all accounts of the state, so it
+// is served in the debug namespace and not on the public endpoints.
+func (api *PrivateDebugAPI) GetTotalSupply(ctx context.Context, assetID common.Hash, blockNr rpc.BlockNumber) (*AssetSupply, error) {
+	state, header, err := api.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	asset, err := state.GetAsset(assetID)
+	if err != nil {
+		return nil, fmt.Errorf("Asset not found")
+	}
+	liquid, timeLocked, err := state.AssetHoldings(assetID, header.Time)
+	if err != nil {
+		return nil, err
+	}
+	tickets := new(big.Int)
+	if assetID == common.SystemAssetID {
+		all, err := state.AllTickets()
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range all.ToTicketSlice() {
+			tickets.Add(tickets, t.Value())
+		}
+	}
+	other := new(big.Int).Sub(asset.Total, liquid)
+	other.Sub(other, timeLocked)
+	other.Sub(other, tickets)
+	return &AssetSupply{
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		BlockHash:   header.Hash(),
+		AssetID:     assetID,
+		Total:       asset.Total.String(),
+		TimeLocked:  timeLocked.String(),
+		Tickets:     tickets.String(),
+		Liquid:      liquid.String(),
+		Other:       other.String(),
+	}, state.Error()
+}
+
+// GetCirculatingSupply returns the liquid amount of an asset, that is its
+// total supply without the time locked, ticket and swap amounts.
+func (api *PrivateDebugAPI) GetCirculatingSupply(ctx context.Context, assetID common.Hash, blockNr rpc.BlockNumber) (string, error) {
+	supply, err := api.GetTotalSupply(ctx, assetID, blockNr)
+	if err != nil {
+		return "", err
+	}
+	return supply.Liquid, nil
+}
+
 // AllAssets wacom
 func (s *PublicFusionAPI) AllAssets(ctx context.Context, blockNr rpc.BlockNumber) (map[common.Hash]common.Asset, error) {
 	return nil, fmt.Errorf("AllAssets has been depreciated, use api.fusionnetwork.io")
@@ -230,7 +665,11 @@ func (s *PublicFusionAPI) TotalNumberOfTickets(ctx context.Context, blockNr rpc.
 }
 
 // TotalNumberOfTicketsByAddress wacom
-func (s *PublicFusionAPI) TotalNumberOfTicketsByAddress(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (int, error) {
+func (s *PublicFusionAPI) TotalNumberOfTicketsByAddress(ctx context.Context, addr common.AddressOrNotation, blockNr rpc.BlockNumber) (int, error) {
+	address, err := s.resolveAddress(ctx, addr)
+	if err != nil {
+		return 0, err
+	}
 	tickets, err := s.getAllTickets(ctx, blockNr)
 	if err != nil {
 		return 0, err
@@ -248,7 +687,11 @@ func (s *PublicFusionAPI) TicketPrice(ctx context.Context, blockNr rpc.BlockNumb
 }
 
 // AllTicketsByAddress wacom
-func (s *PublicFusionAPI) AllTicketsByAddress(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (map[common.Hash]common.TicketDisplay, error) {
+func (s *PublicFusionAPI) AllTicketsByAddress(ctx context.Context, addr common.AddressOrNotation, blockNr rpc.BlockNumber) (map[common.Hash]common.TicketDisplay, error) {
+	address, err := s.resolveAddress(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
 	tickets, err := s.getAllTickets(ctx, blockNr)
 	if err != nil {
 		return nil, err
@@ -261,6 +704,108 @@ func (s *PublicFusionAPI) AllTicketsByAddress(ctx context.Context, address commo
 	return nil, nil
 }
 
+const (
+	// ticketExpiryWindow is the length of a window of a ticket expiry schedule
+	ticketExpiryWindow = 24 * 3600
+	// maxTicketExpiryDays bounds the horizon of a ticket expiry schedule
+	maxTicketExpiryDays = 400
+)
+
+// TicketExpirySchedule wacom
+type TicketExpirySchedule struct {
+	BlockNumber hexutil.Uint64              `json:"blockNumber"`
+	Time        hexutil.Uint64              `json:"time"`
+	Owner       *common.Address             `json:"owner,omitempty"`
+	Windows     []common.TicketExpiryWindow `json:"windows"` // one window per day from Time
+}
+
+// GetTicketExpirySchedule returns the number and value of the tickets, of
+// owner or of everyone if owner is nil, expiring each day of the next
+// horizonBlocks blocks.
+func (s *PublicFusionAPI) GetTicketExpirySchedule(ctx context.Context, owner *common.AddressOrNotation, horizonBlocks hexutil.Uint64) (*TicketExpirySchedule, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	result := &TicketExpirySchedule{
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		Time:        hexutil.Uint64(header.Time),
+	}
+	if owner != nil {
+		address, err := s.resolveAddress(ctx, *owner)
+		if err != nil {
+			return nil, err
+		}
+		result.Owner = &address
+	}
+	period := uint64(15)
+	if config := s.b.ChainConfig().DaTong; config != nil && config.Period != 0 {
+		period = config.Period
+	}
+	horizon := uint64(horizonBlocks) * period
+	if horizon/ticketExpiryWindow > maxTicketExpiryDays {
+		return nil, fmt.Errorf("horizon is longer than %d days", maxTicketExpiryDays)
+	}
+	tickets, err := state.AllTickets()
+	if err != nil {
+		return nil, err
+	}
+	result.Windows = tickets.ExpirySchedule(result.Owner, header.Time, header.Time+horizon, ticketExpiryWindow)
+	return result, nil
+}
+
+// TicketsPage wacom
+type TicketsPage struct {
+	BlockNumber hexutil.Uint64                       `json:"blockNumber"`
+	Tickets     map[common.Hash]common.TicketDisplay `json:"tickets"`
+	NextCursor  string                               `json:"nextCursor"` // empty after the last page
+}
+
+// GetTicketsPage returns one page of the tickets, of owner or of everyone if
+// owner is nil, in ascending ticket ID order
+func (s *PublicFusionAPI) GetTicketsPage(ctx context.Context, owner *common.AddressOrNotation, blockNr rpc.BlockNumber, page *common.PageRequest) (*TicketsPage, error) {
+	pager, err := common.NewPager("tickets", page)
+	if err != nil {
+		return nil, err
+	}
+	var address *common.Address
+	if owner != nil {
+		addr, err := s.resolveAddress(ctx, *owner)
+		if err != nil {
+			return nil, err
+		}
+		address = &addr
+	}
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	tickets, err := state.AllTickets()
+	if err != nil {
+		return nil, err
+	}
+	var list common.TicketSlice
+	for _, ticket := range tickets.ToTicketSlice() {
+		if (address == nil || ticket.Owner == *address) && !pager.Skip(ticket.ID[:]) {
+			list = append(list, ticket)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return bytes.Compare(list[i].ID[:], list[j].ID[:]) < 0 })
+
+	result := &TicketsPage{
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		Tickets:     make(map[common.Hash]common.TicketDisplay),
+	}
+	for i := range list {
+		if !pager.Add(list[i].ID[:]) {
+			break
+		}
+		result.Tickets[list[i].ID] = list[i].ToDisplay()
+	}
+	result.NextCursor = pager.NextCursor()
+	return result, nil
+}
+
 // TxAndReceipt wacom
 type TxAndReceipt struct {
 	FsnTxInput   interface{}            `json:"fsnTxInput,omitempty"`
@@ -269,6 +814,30 @@ type TxAndReceipt struct {
 	ReceiptFound bool                   `json:"receiptFound"`
 }
 
+// setFsnError sets the code and name of the failure of a failed FSN call,
+// as recorded in its log, in the receipt fields. Logs of blocks before the
+// codes were recorded report FsnErrUnknown.
+func setFsnError(fields map[string]interface{}, logs []*types.Log) {
+	for _, log := range logs {
+		if log.Address != common.FSNCallAddress {
+			continue
+		}
+		maps := make(map[string]interface{})
+		if err := json.Unmarshal(log.Data, &maps); err != nil {
+			continue
+		}
+		if _, ok := maps["Error"].(string); ok {
+			code := common.FsnErrUnknown
+			if c, ok := maps["ErrorCode"].(float64); ok {
+				code = common.FsnErrorCode(c)
+			}
+			fields["fsnErrorCode"] = int(code)
+			fields["fsnError"] = code.Name()
+			return
+		}
+	}
+}
+
 // GetTransactionAndReceipt returns the transaction receipt for the given transaction hash.
 func (s *PublicFusionAPI) GetTransactionAndReceipt(ctx context.Context, hash common.Hash) (TxAndReceipt, error) {
 	// Try to return an already finalized transaction
@@ -320,10 +889,9 @@ func (s *PublicFusionAPI) GetTransactionAndReceipt(ctx context.Context, hash com
 
 	if isFsnCall && len(receipt.Logs) > 0 && len(receipt.Logs[0].Topics) > 0 {
 		log := receipt.Logs[0]
-		topic := log.Topics[0]
-		fsnCallFunc := common.FSNCallFunc(topic[common.HashLength-1])
+		fsnCallFunc, _ := common.FsnCallFuncOfTopic(log.Topics[0])
 		fsnLogTopic = fsnCallFunc.Name()
-		if decodedLog, err := datong.DecodeLogData(log.Data); err == nil {
+		if decodedLog, err := datong.DecodeLogData(log.Data, new(big.Int).SetUint64(blockNumber)); err == nil {
 			fsnLogData = decodedLog
 		}
 	}
@@ -346,6 +914,7 @@ func (s *PublicFusionAPI) GetTransactionAndReceipt(ctx context.Context, hash com
 		fields["fsnLogTopic"] = fsnLogTopic
 		fields["fsnLogData"] = fsnLogData
 	}
+	setFsnError(fields, receipt.Logs)
 
 	// Assign receipt status or post state.
 	if len(receipt.PostState) > 0 {
@@ -377,20 +946,26 @@ type AllInfoForAddress struct {
 }
 
 // AllInfoByAddress wacom
-func (s *PublicFusionAPI) AllInfoByAddress(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (AllInfoForAddress, error) {
-	allTickets, err := s.AllTicketsByAddress(ctx, address, blockNr)
+func (s *PublicFusionAPI) AllInfoByAddress(ctx context.Context, addr common.AddressOrNotation, blockNr rpc.BlockNumber) (AllInfoForAddress, error) {
+	address, err := s.resolveAddress(ctx, addr)
+	if err != nil {
+		return AllInfoForAddress{}, err
+	}
+	// resolve only once, all the queries below use the same address
+	addr = common.AddressOrNotation{Address: address}
+	allTickets, err := s.AllTicketsByAddress(ctx, addr, blockNr)
 	if err != nil {
 		return AllInfoForAddress{}, err
 	}
-	allBalances, err := s.GetAllBalances(ctx, address, blockNr)
+	allBalances, err := s.GetAllBalances(ctx, addr, blockNr)
 	if err != nil {
 		return AllInfoForAddress{}, err
 	}
-	allTimeLockBalances, err := s.GetAllTimeLockBalances(ctx, address, blockNr)
+	allTimeLockBalances, err := s.GetAllTimeLockBalances(ctx, addr, blockNr)
 	if err != nil {
 		return AllInfoForAddress{}, err
 	}
-	notation, _ := s.GetNotation(ctx, address, blockNr)
+	notation, _ := s.GetNotation(ctx, addr, blockNr)
 
 	return AllInfoForAddress{
 		Tickets:   allTickets,
@@ -400,34 +975,146 @@ func (s *PublicFusionAPI) AllInfoByAddress(ctx context.Context, address common.A
 	}, nil
 }
 
-func (s *PublicFusionAPI) getIDByTxHash(ctx context.Context, hash common.Hash, logKey string) common.Hash {
-	var id common.Hash
-	tx, blockHash, _, index := rawdb.ReadTransaction(s.b.ChainDb(), hash)
-	if tx == nil {
-		return id
-	}
-	// get from receipt's log
-	receipts, err := s.b.GetReceipts(ctx, blockHash)
-	if err == nil && len(receipts) > int(index) {
-		receipt := receipts[index]
+// TimeLockSummary wacom
+type TimeLockSummary struct {
+	Spendable       string           `json:"spendable"`                 // value usable at the block time
+	NextUnlock      *hexutil.Uint64  `json:"nextUnlock,omitempty"`      // start of the next time lock item
+	NextUnlockValue string           `json:"nextUnlockValue,omitempty"` // value usable from NextUnlock on
+	TimeLock        *common.TimeLock `json:"timeLock"`
+}
 
-		for _, log := range receipt.Logs {
-			if log.Address != common.FSNCallAddress {
-				continue
-			}
-			maps := make(map[string]interface{})
-			err := json.Unmarshal(log.Data, &maps)
-			if err != nil {
-				continue
-			}
+// PendingFsnCall wacom
+type PendingFsnCall struct {
+	Hash  common.Hash    `json:"hash"`
+	Nonce hexutil.Uint64 `json:"nonce"`
+	Func  string         `json:"func"`
+}
 
-			if _, hasError := maps["Error"]; hasError {
-				continue
-			}
+// AccountOverview wacom
+type AccountOverview struct {
+	BlockNumber hexutil.Uint64                       `json:"blockNumber"`
+	Address     common.Address                       `json:"address"`
+	Notation    uint64                               `json:"notation"`
+	Balances    map[common.Hash]string               `json:"balances"`
+	TimeLocks   map[common.Hash]*TimeLockSummary     `json:"timeLocks"`
+	OpenSwaps   []*RPCSwap                           `json:"openSwaps"` // null without the swap history index
+	Tickets     map[common.Hash]common.TicketDisplay `json:"tickets"`   // live tickets
+	Pending     []PendingFsnCall                     `json:"pending"`   // FSN calls pending in the pool
+}
 
-			idstr, idok := maps[logKey].(string)
-			if idok {
-				id = common.HexToHash(idstr)
+// openSwapsBackend is implemented by backends with a swap history index
+type openSwapsBackend interface {
+	OpenSwaps(owner common.Address, now uint64) ([]common.Hash, error)
+}
+
+// GetAccountOverview returns what a wallet shows of an address in one call
+func (s *PublicFusionAPI) GetAccountOverview(ctx context.Context, addr common.AddressOrNotation, blockNr rpc.BlockNumber) (*AccountOverview, error) {
+	address, err := s.resolveAddress(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	now := header.Time
+	result := &AccountOverview{
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		Address:     address,
+		Notation:    state.GetNotation(address),
+		Balances:    state.GetAllBalances(address),
+		TimeLocks:   make(map[common.Hash]*TimeLockSummary),
+		Tickets:     make(map[common.Hash]common.TicketDisplay),
+		Pending:     []PendingFsnCall{},
+	}
+	for assetID, timelock := range state.GetAllTimeLockBalances(address) {
+		summary := &TimeLockSummary{
+			Spendable: timelock.GetSpendableValue(now, now).String(),
+			TimeLock:  timelock.ToDisplay(),
+		}
+		for _, item := range timelock.Items {
+			if item.StartTime > now && (summary.NextUnlock == nil || item.StartTime < uint64(*summary.NextUnlock)) {
+				next := hexutil.Uint64(item.StartTime)
+				summary.NextUnlock = &next
+			}
+		}
+		if summary.NextUnlock != nil {
+			next := uint64(*summary.NextUnlock)
+			summary.NextUnlockValue = timelock.GetSpendableValue(next, next).String()
+		}
+		result.TimeLocks[assetID] = summary
+	}
+	tickets, err := state.AllTickets()
+	if err != nil {
+		return nil, err
+	}
+	for _, ticket := range tickets.ToTicketSlice() {
+		if ticket.Owner == address && ticket.ExpireTime > now {
+			result.Tickets[ticket.ID] = ticket.ToDisplay()
+		}
+	}
+	if backend, ok := s.b.(openSwapsBackend); ok {
+		if ids, err := backend.OpenSwaps(address, now); err == nil {
+			result.OpenSwaps = make([]*RPCSwap, 0, len(ids))
+			for _, id := range ids {
+				// the index may lag behind the requested block
+				if swap, err := state.GetSwap(id); err == nil && swap.Owner == address {
+					result.OpenSwaps = append(result.OpenSwaps, newRPCSwap(&swap, false))
+				}
+			}
+		}
+	}
+	if blockNr == rpc.LatestBlockNumber || blockNr == rpc.PendingBlockNumber {
+		pending, err := s.b.GetPoolTransactions()
+		if err != nil {
+			return nil, err
+		}
+		signer := types.MakeSigner(s.b.ChainConfig(), header.Number)
+		for _, tx := range pending {
+			if !common.IsFsnCall(tx.To()) {
+				continue
+			}
+			if from, err := types.Sender(signer, tx); err != nil || from != address {
+				continue
+			}
+			param := common.FSNCallParam{}
+			if rlp.DecodeBytes(tx.Data(), &param) != nil {
+				continue
+			}
+			result.Pending = append(result.Pending, PendingFsnCall{Hash: tx.Hash(), Nonce: hexutil.Uint64(tx.Nonce()), Func: param.Func.Name()})
+		}
+	}
+	return result, state.Error()
+}
+
+func (s *PublicFusionAPI) getIDByTxHash(ctx context.Context, hash common.Hash, logKey string) common.Hash {
+	var id common.Hash
+	tx, blockHash, _, index := rawdb.ReadTransaction(s.b.ChainDb(), hash)
+	if tx == nil {
+		return id
+	}
+	// get from receipt's log
+	receipts, err := s.b.GetReceipts(ctx, blockHash)
+	if err == nil && len(receipts) > int(index) {
+		receipt := receipts[index]
+
+		for _, log := range receipt.Logs {
+			if log.Address != common.FSNCallAddress {
+				continue
+			}
+			maps := make(map[string]interface{})
+			err := json.Unmarshal(log.Data, &maps)
+			if err != nil {
+				continue
+			}
+
+			if _, hasError := maps["Error"]; hasError {
+				continue
+			}
+
+			idstr, idok := maps[logKey].(string)
+			if idok {
+				id = common.HexToHash(idstr)
 				return id
 			}
 
@@ -436,50 +1123,174 @@ func (s *PublicFusionAPI) getIDByTxHash(ctx context.Context, hash common.Hash, l
 	return id
 }
 
-// GetSwap wacom
-func (s *PublicFusionAPI) GetSwap(ctx context.Context, swapID common.Hash, blockNr rpc.BlockNumber) (*common.Swap, error) {
+// GetSwap returns the swap with its description sanitized, its targets
+// checksummed and its implied price, includeRaw adds the swap as stored
+func (s *PublicFusionAPI) GetSwap(ctx context.Context, swapID common.Hash, blockNr rpc.BlockNumber, includeRaw *bool) (*RPCSwap, error) {
 	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
 		return nil, err
 	}
+	raw := includeRaw != nil && *includeRaw
 	if swap, err := state.GetSwap(swapID); err == nil {
-		return &swap, nil
+		return newRPCSwap(&swap, raw), nil
 	}
 	// treat swapId as tx hash, deduct swap id from the tx
 	if id := s.getIDByTxHash(ctx, swapID, "SwapID"); id != (common.Hash{}) {
 		if swap, err := state.GetSwap(id); err == nil {
-			return &swap, nil
+			return newRPCSwap(&swap, raw), nil
 		}
 	}
-	return nil, fmt.Errorf("Swap not found")
+	return nil, common.ErrSwapNotFound
 }
 
-// GetMultiSwap wacom
-func (s *PublicFusionAPI) GetMultiSwap(ctx context.Context, swapID common.Hash, blockNr rpc.BlockNumber) (*common.MultiSwap, error) {
+// GetEscrow returns the unclaimed escrow with the given ID, the ID may also be
+// the hash of the transaction which created it
+func (s *PublicFusionAPI) GetEscrow(ctx context.Context, escrowID common.Hash, blockNr rpc.BlockNumber) (*common.Escrow, error) {
 	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
 		return nil, err
 	}
+	if escrow, err := state.GetEscrow(escrowID); err == nil {
+		return &escrow, nil
+	}
+	if id := s.getIDByTxHash(ctx, escrowID, "EscrowID"); id != (common.Hash{}) {
+		if escrow, err := state.GetEscrow(id); err == nil {
+			return &escrow, nil
+		}
+	}
+	return nil, fmt.Errorf("Escrow not found")
+}
+
+// RPCStream is a payment stream with the value its recipient can withdraw at
+// the time of the requested block
+type RPCStream struct {
+	common.Stream
+	Withdrawable *big.Int `json:",string"`
+}
+
+// GetStream returns the payment stream with the given ID, the ID may also be
+// the hash of the transaction which created it
+func (s *PublicFusionAPI) GetStream(ctx context.Context, streamID common.Hash, blockNr rpc.BlockNumber) (*RPCStream, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	stream, err := state.GetStream(streamID)
+	if err != nil {
+		id := s.getIDByTxHash(ctx, streamID, "StreamID")
+		if id == (common.Hash{}) {
+			return nil, fmt.Errorf("Stream not found")
+		}
+		if stream, err = state.GetStream(id); err != nil {
+			return nil, fmt.Errorf("Stream not found")
+		}
+	}
+	return &RPCStream{Stream: stream, Withdrawable: stream.Withdrawable(header.Time)}, nil
+}
+
+// GetCondition returns the condition with the given ID from the condition
+// registry, the ID may also be the hash of the transaction which created it
+func (s *PublicFusionAPI) GetCondition(ctx context.Context, conditionID common.Hash, blockNr rpc.BlockNumber) (*common.Condition, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if condition, err := state.GetCondition(conditionID); err == nil {
+		return &condition, nil
+	}
+	if id := s.getIDByTxHash(ctx, conditionID, "ConditionID"); id != (common.Hash{}) {
+		if condition, err := state.GetCondition(id); err == nil {
+			return &condition, nil
+		}
+	}
+	return nil, fmt.Errorf("Condition not found")
+}
+
+// GetConditionalTransfer returns the unsettled conditional transfer with the
+// given ID, the ID may also be the hash of the transaction which created it
+func (s *PublicFusionAPI) GetConditionalTransfer(ctx context.Context, transferID common.Hash, blockNr rpc.BlockNumber) (*common.ConditionalTransfer, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if transfer, err := state.GetConditionalTransfer(transferID); err == nil {
+		return &transfer, nil
+	}
+	if id := s.getIDByTxHash(ctx, transferID, "TransferID"); id != (common.Hash{}) {
+		if transfer, err := state.GetConditionalTransfer(id); err == nil {
+			return &transfer, nil
+		}
+	}
+	return nil, fmt.Errorf("Conditional transfer not found")
+}
+
+// BridgeInfo is the bridge configuration of the network
+type BridgeInfo struct {
+	Committee []common.Address `json:"committee"`
+	Threshold int              `json:"threshold"` // attestations minting a deposit
+	Assets    []common.Hash    `json:"assets"`
+}
+
+// GetBridgeInfo returns the bridge committee and the assets it mints
+func (s *PublicFusionAPI) GetBridgeInfo(ctx context.Context) *BridgeInfo {
+	return &BridgeInfo{
+		Committee: common.GetBridgeCommittee(),
+		Threshold: common.BridgeAttestationThreshold(),
+		Assets:    common.GetBridgeAssets(),
+	}
+}
+
+// GetBridgeDeposit returns the attestations of a deposit, nil if it was never
+// attested
+func (s *PublicFusionAPI) GetBridgeDeposit(ctx context.Context, depositID common.Hash, blockNr rpc.BlockNumber) (*common.BridgeDeposit, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	return state.GetBridgeDeposit(depositID), nil
+}
+
+// GetMultiSwap returns the multi swap with its description sanitized and its
+// targets checksummed, includeRaw adds the multi swap as stored
+func (s *PublicFusionAPI) GetMultiSwap(ctx context.Context, swapID common.Hash, blockNr rpc.BlockNumber, includeRaw *bool) (*RPCMultiSwap, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	raw := includeRaw != nil && *includeRaw
 	if swap, err := state.GetMultiSwap(swapID); err == nil {
-		return &swap, nil
+		return newRPCMultiSwap(&swap, raw), nil
 	}
 	// treat swapId as tx hash, deduct swap id from the tx
 	if id := s.getIDByTxHash(ctx, swapID, "SwapID"); id != (common.Hash{}) {
 		if swap, err := state.GetMultiSwap(id); err == nil {
-			return &swap, nil
+			return newRPCMultiSwap(&swap, raw), nil
 		}
 	}
 	return nil, fmt.Errorf("MultiSwap not found")
 }
 
 // AllSwaps wacom
-func (s *PublicFusionAPI) AllSwaps(ctx context.Context, blockNr rpc.BlockNumber) (map[common.Hash]common.Swap, error) {
+func (s *PublicFusionAPI) AllSwaps(ctx context.Context, blockNr rpc.BlockNumber) (map[common.Hash]*RPCSwap, error) {
 	return nil, fmt.Errorf("AllSwaps has been depreciated please use api.fusionnetwork.io")
 }
 
-// AllSwapsByAddress wacom
-func (s *PublicFusionAPI) AllSwapsByAddress(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (map[common.Hash]common.Swap, error) {
-	return nil, fmt.Errorf("AllSwapsByAddress has been depreciated please use api.fusionnetwork.io")
+// AllSwapsByAddress returns the open swaps made by address, decoded as by
+// fsn_getSwap. It needs the swap history index.
+func (s *PublicFusionAPI) AllSwapsByAddress(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (map[common.Hash]*RPCSwap, error) {
+	backend, ok := s.b.(openSwapsBackend)
+	if !ok {
+		return nil, fmt.Errorf("AllSwapsByAddress has been depreciated please use api.fusionnetwork.io")
+	}
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	ids, err := backend.OpenSwaps(address, header.Time)
+	if err != nil {
+		return nil, err
+	}
+	return ownedRPCSwaps(state, address, ids), nil
 }
 
 type Summary struct {
@@ -541,6 +1352,15 @@ func (s *PublicFusionAPI) GetBlockReward(ctx context.Context, blockNr rpc.BlockN
 	if err != nil {
 		return "", err
 	}
+	// the fees of the block are in the fee schedule of its state
+	var schedule common.FsnCallFeeSchedule
+	if common.IsFsnCallFeeScheduleEnabled(block.Number()) {
+		state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+		if state == nil || err != nil {
+			return "", err
+		}
+		schedule = state
+	}
 	// block creation reward
 	reward := datong.CalcRewards(block.Number())
 	gasUses := make(map[common.Hash]uint64)
@@ -558,7 +1378,7 @@ func (s *PublicFusionAPI) GetBlockReward(ctx context.Context, blockNr rpc.BlockN
 		if common.IsFsnCall(tx.To()) {
 			fsnCallParam := &common.FSNCallParam{}
 			rlp.DecodeBytes(tx.Data(), fsnCallParam)
-			feeReward := common.GetFsnCallFee(tx.To(), fsnCallParam.Func)
+			feeReward := common.GetFsnCallFeeAt(tx.To(), fsnCallParam.Func, block.Number(), schedule)
 			if feeReward.Sign() > 0 {
 				// transaction fee reward
 				reward.Add(reward, feeReward)
@@ -671,6 +1491,115 @@ func (s *PublicFusionAPI) GetRetreatTickets(ctx context.Context, blockNr rpc.Blo
 	return result, nil
 }
 
+// GetTicketRetreatRecord returns the number of tickets of an address retreated
+// by the blocks since the retreat records started, and the number and value
+// of their units which were not returned
+func (s *PublicFusionAPI) GetTicketRetreatRecord(ctx context.Context, addr common.AddressOrNotation, blockNr rpc.BlockNumber) (*common.TicketRetreatRecord, error) {
+	address, err := s.resolveAddress(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	return state.GetTicketRetreatRecord(address), state.Error()
+}
+
+// GetTicketRetreatReceipt returns the synthetic receipt logging the tickets
+// retreated by a block, nil if the block retreated none or was processed
+// before the retreat records started
+func (s *PublicFusionAPI) GetTicketRetreatReceipt(ctx context.Context, blockNr rpc.BlockNumber) (*types.Receipt, error) {
+	header, err := s.b.HeaderByNumber(ctx, blockNr)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	return rawdb.ReadTicketRetreatReceipt(s.b.ChainDb(), header.Hash(), header.Number.Uint64()), nil
+}
+
+// BlockFsnSummary aggregates the FSN calls of a block
+type BlockFsnSummary struct {
+	BlockNumber   hexutil.Uint64 `json:"blockNumber"`
+	BlockHash     common.Hash    `json:"blockHash"`
+	Calls         map[string]int `json:"calls"`  // successful calls per FSN func
+	Failed        map[string]int `json:"failed"` // failed calls per FSN func
+	AssetsCreated []common.Hash  `json:"assetsCreated"`
+	SwapsMade     int            `json:"swapsMade"`
+	SwapsTaken    int            `json:"swapsTaken"`
+	SwapsRecalled int            `json:"swapsRecalled"`
+	TicketsBought int            `json:"ticketsBought"`
+	TicketWeight  uint64         `json:"ticketWeight"` // ticket prices paid by the tickets bought
+}
+
+// GetBlockFsnSummary returns the aggregate of the FSN call logs of a block
+func (s *PublicFusionAPI) GetBlockFsnSummary(ctx context.Context, blockNr rpc.BlockNumber) (*BlockFsnSummary, error) {
+	block, err := s.b.BlockByNumber(ctx, blockNr)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	receipts, err := s.b.GetReceipts(ctx, block.Hash())
+	if err != nil {
+		return nil, err
+	}
+	summary := &BlockFsnSummary{
+		BlockNumber:   hexutil.Uint64(block.NumberU64()),
+		BlockHash:     block.Hash(),
+		Calls:         make(map[string]int),
+		Failed:        make(map[string]int),
+		AssetsCreated: []common.Hash{},
+	}
+	for _, receipt := range receipts {
+		if err := summary.addLogs(receipt.Logs, block.Number()); err != nil {
+			return nil, err
+		}
+	}
+	return summary, nil
+}
+
+// addLogs adds the FSN call logs of a block of the given number to the summary
+func (summary *BlockFsnSummary) addLogs(logs []*types.Log, number *big.Int) error {
+	for _, l := range logs {
+		if l.Address != common.FSNCallAddress || len(l.Topics) == 0 {
+			continue
+		}
+		fn, ok := common.FsnCallFuncOfTopic(l.Topics[0])
+		if !ok {
+			continue
+		}
+		maps := make(map[string]interface{})
+		if err := json.Unmarshal(l.Data, &maps); err != nil {
+			return err
+		}
+		if _, hasError := maps["Error"]; hasError {
+			summary.Failed[fn.Name()]++
+			continue
+		}
+		summary.Calls[fn.Name()]++
+		switch fn {
+		case common.GenAssetFunc:
+			if id, ok := maps["AssetID"].(string); ok {
+				summary.AssetsCreated = append(summary.AssetsCreated, common.HexToHash(id))
+			}
+		case common.MakeSwapFunc, common.MakeSwapFuncExt, common.MakeMultiSwapFunc:
+			summary.SwapsMade++
+		case common.TakeSwapFunc, common.TakeSwapFuncExt, common.TakeMultiSwapFunc:
+			summary.SwapsTaken++
+		case common.RecallSwapFunc, common.RecallMultiSwapFunc:
+			summary.SwapsRecalled++
+		case common.BuyTicketFunc:
+			param := common.BuyTicketParam{}
+			if base, ok := maps["Base"].(string); ok {
+				if data, err := base64.StdEncoding.DecodeString(base); err == nil {
+					rlp.DecodeBytes(data, &param)
+				}
+			}
+			summary.TicketsBought++
+			summary.TicketWeight += param.Weight(number)
+		}
+	}
+	return nil
+}
+
 //--------------------------------------------- PublicFusionAPI buile send tx args-------------------------------------
 func FSNCallArgsToSendTxArgs(args common.FSNBaseArgsInterface, funcType common.FSNCallFunc, funcData []byte) (*SendTxArgs, error) {
 	var param = common.FSNCallParam{Func: funcType, Data: funcData}
@@ -706,7 +1635,16 @@ func (s *PublicFusionAPI) BuildGenNotationSendTxArgs(ctx context.Context, args c
 }
 
 func (s *PublicFusionAPI) BuildGenAssetSendTxArgs(ctx context.Context, args common.GenAssetArgs) (*SendTxArgs, error) {
-	if err := args.ToParam().Check(common.BigMaxUint64); err != nil {
+	if args.FuncType() != common.GenAssetFunc {
+		_, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+		if header == nil || err != nil {
+			return nil, err
+		}
+		nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+		if err := args.Check(nextBlockNumber); err != nil {
+			return nil, err
+		}
+	} else if err := args.ToParam().Check(common.BigMaxUint64); err != nil {
 		return nil, err
 	}
 
@@ -714,1120 +1652,2611 @@ func (s *PublicFusionAPI) BuildGenAssetSendTxArgs(ctx context.Context, args comm
 	if err != nil {
 		return nil, err
 	}
-	return FSNCallArgsToSendTxArgs(&args, common.GenAssetFunc, funcData)
-}
-
-func CheckAndSetToAddress(args *common.SendAssetArgs, state *state.StateDB) error {
-	if args.ToUSAN != 0 {
-		address, err := state.GetAddressByNotation(args.ToUSAN)
-		if err != nil {
-			return err
-		}
-		if args.To == (common.Address{}) {
-			args.To = address
-		} else if args.To != address {
-			return fmt.Errorf("'to' and 'toUSAN' conflicts")
-		}
-	}
-	if args.To == (common.Address{}) {
-		return fmt.Errorf("receiver address must be set and not zero address")
-	}
-	return nil
+	return FSNCallArgsToSendTxArgs(&args, args.FuncType(), funcData)
 }
 
-func (s *PublicFusionAPI) BuildSendAssetSendTxArgs(ctx context.Context, args common.SendAssetArgs) (*SendTxArgs, error) {
-	state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+func (s *PublicFusionAPI) BuildAssetTransferListSendTxArgs(ctx context.Context, args common.AssetTransferListArgs) (*SendTxArgs, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
 	if state == nil || err != nil {
 		return nil, err
 	}
-	if err = CheckAndSetToAddress(&args, state); err != nil {
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := args.ToParam().Check(nextBlockNumber); err != nil {
 		return nil, err
 	}
-	if err := args.ToParam().Check(common.BigMaxUint64); err != nil {
-		return nil, err
+	asset, err := state.GetAsset(args.AssetID)
+	if err != nil {
+		return nil, common.ErrAssetNotFound
 	}
-
-	if state.GetBalance(args.AssetID, args.From).Cmp(args.Value.ToInt()) < 0 {
-		return nil, fmt.Errorf("not enough asset")
+	if !isAssetOwner(state, &asset, args.From) {
+		return nil, common.ErrNotAssetOwner
+	}
+	if state.GetAssetTransferRestriction(args.AssetID) == common.AssetTransferUnrestricted {
+		return nil, fmt.Errorf("asset transfers are not restricted")
 	}
-
 	funcData, err := args.ToData()
 	if err != nil {
 		return nil, err
 	}
-	return FSNCallArgsToSendTxArgs(&args, common.SendAssetFunc, funcData)
+	return FSNCallArgsToSendTxArgs(&args, common.AssetTransferListFunc, funcData)
 }
 
-func (s *PublicFusionAPI) BuildAssetToTimeLockSendTxArgs(ctx context.Context, args common.TimeLockArgs) (*SendTxArgs, error) {
+func (s *PublicFusionAPI) BuildSetFsnCallFeeSendTxArgs(ctx context.Context, args common.SetFsnCallFeeArgs) (*SendTxArgs, error) {
 	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
 	if state == nil || err != nil {
 		return nil, err
 	}
-	if err = CheckAndSetToAddress(&args.SendAssetArgs, state); err != nil {
-		return nil, err
+	if args.Fee == nil {
+		return nil, fmt.Errorf("fee must be set")
 	}
-	args.Init(common.AssetToTimeLock)
-	if err := args.ToParam().Check(common.BigMaxUint64, header.Time); err != nil {
+	param := args.ToParam()
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := param.Check(nextBlockNumber); err != nil {
 		return nil, err
 	}
-	needValue := common.NewTimeLock(&common.TimeLockItem{
-		StartTime: common.MaxUint64(uint64(*args.StartTime), header.Time),
-		EndTime:   uint64(*args.EndTime),
-		Value:     args.Value.ToInt(),
-	})
-	if err := needValue.IsValid(); err != nil {
-		return nil, fmt.Errorf("BuildAssetToTimeLockTx err:%v", err.Error())
+	if !common.IsFeeGovernor(args.From) {
+		return nil, fmt.Errorf("%v is not a fee governor", args.From.Hex())
 	}
-	if state.GetBalance(args.AssetID, args.From).Cmp(args.Value.ToInt()) < 0 {
-		return nil, fmt.Errorf("not enough asset")
+	if entry := state.GetFsnCallFeeEntry(param.Func); entry != nil && entry.Height > nextBlockNumber.Uint64() {
+		return nil, fmt.Errorf("fee change of %v pending until block %d", param.Func.Name(), entry.Height)
 	}
-
 	funcData, err := args.ToData()
 	if err != nil {
 		return nil, err
 	}
-	return FSNCallArgsToSendTxArgs(&args, common.TimeLockFunc, funcData)
+	return FSNCallArgsToSendTxArgs(&args, common.SetFsnCallFeeFunc, funcData)
 }
 
-func (s *PublicFusionAPI) BuildTimeLockToTimeLockSendTxArgs(ctx context.Context, args common.TimeLockArgs) (*SendTxArgs, error) {
+func (s *PublicFusionAPI) BuildCreateProposalSendTxArgs(ctx context.Context, args common.CreateProposalArgs) (*SendTxArgs, error) {
 	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
 	if state == nil || err != nil {
 		return nil, err
 	}
-	if err = CheckAndSetToAddress(&args.SendAssetArgs, state); err != nil {
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := args.ToParam().Check(nextBlockNumber); err != nil {
 		return nil, err
 	}
-	args.Init(common.TimeLockToTimeLock)
-	if err := args.ToParam().Check(common.BigMaxUint64, header.Time); err != nil {
+	tickets, err := state.AllTickets()
+	if err != nil {
 		return nil, err
 	}
-	needValue := common.NewTimeLock(&common.TimeLockItem{
-		StartTime: common.MaxUint64(uint64(*args.StartTime), header.Time),
-		EndTime:   uint64(*args.EndTime),
-		Value:     args.Value.ToInt(),
-	})
-	if err := needValue.IsValid(); err != nil {
-		return nil, fmt.Errorf("BuildTimeLockToTimeLockTx err:%v", err.Error())
-	}
-
-	if state.GetTimeLockBalance(args.AssetID, args.From).Cmp(needValue) < 0 {
-		return nil, fmt.Errorf("not enough time lock balance")
+	if owned, _ := tickets.NumberOfLiveTickets(args.From, header.Time); owned == 0 {
+		return nil, common.NewFsnError(common.FsnErrNotTicketHolder, "only ticket holders can create proposals")
 	}
-
 	funcData, err := args.ToData()
 	if err != nil {
 		return nil, err
 	}
-	return FSNCallArgsToSendTxArgs(&args, common.TimeLockFunc, funcData)
+	return FSNCallArgsToSendTxArgs(&args, common.CreateProposalFunc, funcData)
 }
 
-func (s *PublicFusionAPI) BuildTimeLockToAssetSendTxArgs(ctx context.Context, args common.TimeLockArgs) (*SendTxArgs, error) {
+func (s *PublicFusionAPI) BuildVoteProposalSendTxArgs(ctx context.Context, args common.VoteProposalArgs) (*SendTxArgs, error) {
 	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
 	if state == nil || err != nil {
 		return nil, err
 	}
-	if err = CheckAndSetToAddress(&args.SendAssetArgs, state); err != nil {
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := args.ToParam().Check(nextBlockNumber); err != nil {
 		return nil, err
 	}
-	args.Init(common.TimeLockToAsset)
-	*(*uint64)(args.StartTime) = header.Time
-	*(*uint64)(args.EndTime) = common.TimeLockForever
-	if err := args.ToParam().Check(common.BigMaxUint64, header.Time); err != nil {
+	proposal, err := state.GetProposal(args.ProposalID)
+	if err != nil {
 		return nil, err
 	}
-	needValue := common.NewTimeLock(&common.TimeLockItem{
-		StartTime: uint64(*args.StartTime),
-		EndTime:   uint64(*args.EndTime),
-		Value:     args.Value.ToInt(),
-	})
-	if err := needValue.IsValid(); err != nil {
-		return nil, fmt.Errorf("BuildTimeLockToAssetTx err:%v", err.Error())
+	if nextBlockNumber.Uint64() > proposal.EndHeight {
+		return nil, fmt.Errorf("proposal voting ended at block %d", proposal.EndHeight)
 	}
-	if state.GetTimeLockBalance(args.AssetID, args.From).Cmp(needValue) < 0 {
-		return nil, fmt.Errorf("not enough time lock balance")
+	tickets, err := state.AllTickets()
+	if err != nil {
+		return nil, err
+	}
+	if owned, _ := tickets.NumberOfLiveTickets(args.From, header.Time); owned == 0 {
+		return nil, common.NewFsnError(common.FsnErrNotTicketHolder, "only ticket holders can vote")
 	}
-
 	funcData, err := args.ToData()
 	if err != nil {
 		return nil, err
 	}
-	return FSNCallArgsToSendTxArgs(&args, common.TimeLockFunc, funcData)
+	return FSNCallArgsToSendTxArgs(&args, common.VoteProposalFunc, funcData)
 }
 
-func (s *PublicFusionAPI) BuildSendTimeLockSendTxArgs(ctx context.Context, args common.TimeLockArgs) (*SendTxArgs, error) {
+func (s *PublicFusionAPI) BuildRevokeTicketSendTxArgs(ctx context.Context, args common.RevokeTicketArgs) (*SendTxArgs, error) {
 	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
 	if state == nil || err != nil {
 		return nil, err
 	}
-	if err = CheckAndSetToAddress(&args.SendAssetArgs, state); err != nil {
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := args.ToParam().Check(nextBlockNumber); err != nil {
 		return nil, err
 	}
-	args.Init(common.SmartTransfer)
-	if err := args.ToParam().Check(common.BigMaxUint64, header.Time); err != nil {
+	ticket, err := state.GetTicket(args.TicketID)
+	if err != nil {
 		return nil, err
 	}
-	needValue := common.NewTimeLock(&common.TimeLockItem{
-		StartTime: common.MaxUint64(uint64(*args.StartTime), header.Time),
-		EndTime:   uint64(*args.EndTime),
-		Value:     args.Value.ToInt(),
-	})
-	if err := needValue.IsValid(); err != nil {
-		return nil, fmt.Errorf("BuildSendTimeLockSendTxArgs err:%v", err.Error())
+	if ticket.Owner != args.From {
+		return nil, fmt.Errorf("%v is not the owner of ticket %v", args.From.Hex(), args.TicketID.Hex())
+	}
+	if ticket.IsInGenesis() {
+		return nil, common.ErrGenesisTicketRevoke
+	}
+	if ticket.ExpireTime <= header.Time {
+		return nil, fmt.Errorf("ticket %v is expired", args.TicketID.Hex())
 	}
-
 	funcData, err := args.ToData()
 	if err != nil {
 		return nil, err
 	}
-	return FSNCallArgsToSendTxArgs(&args, common.TimeLockFunc, funcData)
+	return FSNCallArgsToSendTxArgs(&args, common.RevokeTicketFunc, funcData)
 }
 
-func (s *PublicFusionAPI) BuildBuyTicketSendTxArgs(ctx context.Context, args common.BuyTicketArgs) (*SendTxArgs, error) {
+func (s *PublicFusionAPI) BuildEscrowAssetSendTxArgs(ctx context.Context, args common.EscrowAssetArgs) (*SendTxArgs, error) {
 	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
 	if state == nil || err != nil {
 		return nil, err
 	}
-
-	if doesTicketPurchaseExistsForBlock(header.Number.Int64(), args.From) {
-		return nil, fmt.Errorf("Purchase of BuyTicket for this block already submitted")
-	}
-
-	parentTime := header.Time
-	args.Init(parentTime)
-	if err := args.ToParam().Check(common.BigMaxUint64, parentTime); err != nil {
+	if err := checkAndSetAddress(state, &args.To, args.ToUSAN); err != nil {
 		return nil, err
 	}
-
-	start := uint64(*args.Start)
-	end := uint64(*args.End)
-	value := common.TicketPrice(header.Number)
-	needValue := common.NewTimeLock(&common.TimeLockItem{
-		StartTime: common.MaxUint64(start, header.Time),
-		EndTime:   end,
-		Value:     value,
-	})
-	if err := needValue.IsValid(); err != nil {
-		return nil, fmt.Errorf("BuildBuyTicketTx err:%v", err.Error())
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := args.ToParam().Check(nextBlockNumber, header.Time); err != nil {
+		return nil, err
 	}
-
-	if state.GetTimeLockBalance(common.SystemAssetID, args.From).Cmp(needValue) < 0 {
-		if state.GetBalance(common.SystemAssetID, args.From).Cmp(value) < 0 {
-			return nil, fmt.Errorf("not enough time lock or asset balance")
-		}
+	if state.GetBalance(args.AssetID, args.From).Cmp(args.Value.ToInt()) < 0 {
+		return nil, common.ErrNotEnoughAsset
 	}
-
 	funcData, err := args.ToData()
 	if err != nil {
 		return nil, err
 	}
-	return FSNCallArgsToSendTxArgs(&args, common.BuyTicketFunc, funcData)
+	return FSNCallArgsToSendTxArgs(&args, common.EscrowAssetFunc, funcData)
 }
 
-func (s *PublicFusionAPI) BuildAssetValueChangeSendTxArgs(ctx context.Context, args common.AssetValueChangeExArgs) (*SendTxArgs, error) {
-	state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+func (s *PublicFusionAPI) BuildClaimEscrowSendTxArgs(ctx context.Context, args common.ClaimEscrowArgs) (*SendTxArgs, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
 	if state == nil || err != nil {
 		return nil, err
 	}
-
-	if err := args.ToParam().Check(common.BigMaxUint64); err != nil {
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := args.ToParam().Check(nextBlockNumber); err != nil {
 		return nil, err
 	}
-
-	asset, assetError := state.GetAsset(args.AssetID)
-	if assetError != nil {
-		return nil, fmt.Errorf("asset not found")
-	}
-
-	if !asset.CanChange {
-		return nil, fmt.Errorf("asset can't inc or dec")
-	}
-
-	if asset.Owner != args.From {
-		return nil, fmt.Errorf("can only be changed by onwer")
-	}
-
-	if asset.Owner != args.To && !args.IsInc {
-		return nil, fmt.Errorf("decrement can only happen to asset's own account")
+	escrow, err := state.GetEscrow(args.EscrowID)
+	if err != nil {
+		return nil, err
 	}
-
-	currentBalance := state.GetBalance(args.AssetID, args.To)
-	val := args.Value.ToInt()
-	if !args.IsInc {
-		if currentBalance.Cmp(val) < 0 {
-			return nil, fmt.Errorf("not enough asset")
-		}
+	switch {
+	case args.From == escrow.To && header.Time < escrow.Deadline:
+	case args.From == escrow.Sender && header.Time >= escrow.Deadline:
+	case args.From == escrow.To:
+		return nil, fmt.Errorf("escrow %v expired at %d", escrow.ID.Hex(), escrow.Deadline)
+	case args.From == escrow.Sender:
+		return nil, fmt.Errorf("escrow %v can not be refunded before %d", escrow.ID.Hex(), escrow.Deadline)
+	default:
+		return nil, fmt.Errorf("%v can not claim escrow %v", args.From.Hex(), escrow.ID.Hex())
 	}
-
 	funcData, err := args.ToData()
 	if err != nil {
 		return nil, err
 	}
-	return FSNCallArgsToSendTxArgs(&args, common.AssetValueChangeFunc, funcData)
+	return FSNCallArgsToSendTxArgs(&args, common.ClaimEscrowFunc, funcData)
 }
 
-func (s *PublicFusionAPI) BuildMakeSwapSendTxArgs(ctx context.Context, args common.MakeSwapArgs) (*SendTxArgs, error) {
+func (s *PublicFusionAPI) BuildCreateStreamSendTxArgs(ctx context.Context, args common.CreateStreamArgs) (*SendTxArgs, error) {
 	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
 	if state == nil || err != nil {
 		return nil, err
 	}
-
-	args.Init(new(big.Int).SetUint64(header.Time))
-	now := uint64(time.Now().Unix())
-	if err := args.ToParam().Check(common.BigMaxUint64, now); err != nil {
+	if err := checkAndSetAddress(state, &args.To, args.ToUSAN); err != nil {
 		return nil, err
 	}
-
-	total := new(big.Int).Mul(args.MinFromAmount.ToInt(), args.SwapSize)
-	start := uint64(*args.FromStartTime)
-	end := uint64(*args.FromEndTime)
-
-	if args.FromAssetID == common.OwnerUSANAssetID {
-		notation := state.GetNotation(args.From)
-		if notation == 0 {
-			return nil, fmt.Errorf("from address does not have a notation")
-		}
-	} else if start == common.TimeLockNow && end == common.TimeLockForever {
-		if state.GetBalance(args.FromAssetID, args.From).Cmp(total) < 0 {
-			return nil, fmt.Errorf("not enough from asset")
-		}
-	} else {
-		needValue := common.NewTimeLock(&common.TimeLockItem{
-			StartTime: common.MaxUint64(start, header.Time),
-			EndTime:   end,
-			Value:     total,
-		})
-		if err := needValue.IsValid(); err != nil {
-			return nil, fmt.Errorf("BuildMakeSwapTx from err:%v", err.Error())
-		}
-		if state.GetTimeLockBalance(args.FromAssetID, args.From).Cmp(needValue) < 0 {
-			if state.GetBalance(args.FromAssetID, args.From).Cmp(total) < 0 {
-				return nil, fmt.Errorf("not enough time lock or asset balance")
-			}
-		}
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := args.ToParam().Check(nextBlockNumber, header.Time); err != nil {
+		return nil, err
+	}
+	if state.GetBalance(args.AssetID, args.From).Cmp(args.Value.ToInt()) < 0 {
+		return nil, common.ErrNotEnoughAsset
 	}
-
 	funcData, err := args.ToData()
 	if err != nil {
 		return nil, err
 	}
-	return FSNCallArgsToSendTxArgs(&args, common.MakeSwapFuncExt, funcData)
+	return FSNCallArgsToSendTxArgs(&args, common.CreateStreamFunc, funcData)
 }
 
-func (s *PublicFusionAPI) BuildRecallSwapSendTxArgs(ctx context.Context, args common.RecallSwapArgs) (*SendTxArgs, error) {
-	state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+func (s *PublicFusionAPI) BuildWithdrawStreamSendTxArgs(ctx context.Context, args common.WithdrawStreamArgs) (*SendTxArgs, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
 	if state == nil || err != nil {
 		return nil, err
 	}
-
-	var swap common.Swap
-	swap, err = state.GetSwap(args.SwapID)
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := args.ToParam().Check(nextBlockNumber); err != nil {
+		return nil, err
+	}
+	stream, err := state.GetStream(args.StreamID)
 	if err != nil {
 		return nil, err
 	}
-
-	if err := args.ToParam().Check(common.BigMaxUint64, &swap); err != nil {
+	if args.From != stream.To {
+		return nil, fmt.Errorf("%v is not the recipient of stream %v", args.From.Hex(), stream.ID.Hex())
+	}
+	if stream.Withdrawable(header.Time).Sign() <= 0 {
+		return nil, fmt.Errorf("nothing to withdraw from stream %v", stream.ID.Hex())
+	}
+	funcData, err := args.ToData()
+	if err != nil {
 		return nil, err
 	}
+	return FSNCallArgsToSendTxArgs(&args, common.WithdrawStreamFunc, funcData)
+}
 
-	if swap.Owner != args.From {
-		return nil, fmt.Errorf("Must be swap onwer can recall")
+func (s *PublicFusionAPI) BuildCreateConditionSendTxArgs(ctx context.Context, args common.CreateConditionArgs) (*SendTxArgs, error) {
+	_, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := args.ToParam().Check(nextBlockNumber); err != nil {
+		return nil, err
 	}
-
 	funcData, err := args.ToData()
 	if err != nil {
 		return nil, err
 	}
-	return FSNCallArgsToSendTxArgs(&args, common.RecallSwapFunc, funcData)
+	return FSNCallArgsToSendTxArgs(&args, common.CreateConditionFunc, funcData)
 }
 
-func (s *PublicFusionAPI) BuildTakeSwapSendTxArgs(ctx context.Context, args common.TakeSwapArgs) (*SendTxArgs, error) {
-	state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+func (s *PublicFusionAPI) BuildResolveConditionSendTxArgs(ctx context.Context, args common.ResolveConditionArgs) (*SendTxArgs, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
 	if state == nil || err != nil {
 		return nil, err
 	}
-	var swap common.Swap
-	swap, err = state.GetSwap(args.SwapID)
-	if err != nil {
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := args.ToParam().Check(nextBlockNumber); err != nil {
 		return nil, err
 	}
-
-	now := uint64(time.Now().Unix())
-	if err := args.ToParam().Check(common.BigMaxUint64, &swap, now); err != nil {
+	condition, err := state.GetCondition(args.ConditionID)
+	if err != nil {
 		return nil, err
 	}
-
-	total := new(big.Int).Mul(swap.MinToAmount, args.Size)
-	start := swap.ToStartTime
-	end := swap.ToEndTime
-
-	if start == common.TimeLockNow && end == common.TimeLockForever {
-		if state.GetBalance(swap.ToAssetID, args.From).Cmp(total) < 0 {
-			return nil, fmt.Errorf("not enough from asset")
+	if condition.ResolvedTime != 0 {
+		return nil, fmt.Errorf("condition %v is already resolved", condition.ID.Hex())
+	}
+	switch condition.Kind {
+	case common.HashLockCondition:
+		if crypto.Keccak256Hash(args.Preimage) != condition.Hash {
+			return nil, fmt.Errorf("preimage does not match condition %v", condition.ID.Hex())
 		}
-	} else {
-		needValue := common.NewTimeLock(&common.TimeLockItem{
-			StartTime: start,
-			EndTime:   end,
-			Value:     total,
-		})
-		if err := needValue.IsValid(); err != nil {
-			return nil, fmt.Errorf("BuildTakeSwapTx to err:%v", err.Error())
+	case common.OracleCondition:
+		if args.From == condition.Oracle {
+			break
 		}
-		if state.GetTimeLockBalance(swap.ToAssetID, args.From).Cmp(needValue) < 0 {
-			if state.GetBalance(swap.ToAssetID, args.From).Cmp(total) < 0 {
-				return nil, fmt.Errorf("not enough time lock or asset balance")
-			}
+		pub, err := crypto.SigToPub(condition.StatementHash().Bytes(), args.Signature)
+		if err != nil || crypto.PubkeyToAddress(*pub) != condition.Oracle {
+			return nil, fmt.Errorf("statement of condition %v is not signed by its oracle", condition.ID.Hex())
 		}
 	}
-
 	funcData, err := args.ToData()
 	if err != nil {
 		return nil, err
 	}
-	return FSNCallArgsToSendTxArgs(&args, common.TakeSwapFuncExt, funcData)
+	return FSNCallArgsToSendTxArgs(&args, common.ResolveConditionFunc, funcData)
 }
 
-func (s *PublicFusionAPI) BuildMakeMultiSwapSendTxArgs(ctx context.Context, args common.MakeMultiSwapArgs) (*SendTxArgs, error) {
+func (s *PublicFusionAPI) BuildConditionalTransferSendTxArgs(ctx context.Context, args common.ConditionalTransferArgs) (*SendTxArgs, error) {
 	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
 	if state == nil || err != nil {
 		return nil, err
 	}
-
-	args.Init(new(big.Int).SetUint64(header.Time))
-	now := uint64(time.Now().Unix())
-	if err := args.ToParam().Check(common.BigMaxUint64, now); err != nil {
+	if err := checkAndSetAddress(state, &args.To, args.ToUSAN); err != nil {
 		return nil, err
 	}
-
-	ln := len(args.MinFromAmount)
-	for i := 0; i < ln; i++ {
-		total := new(big.Int).Mul(args.MinFromAmount[i].ToInt(), args.SwapSize)
-		start := uint64(*args.FromStartTime[i])
-		end := uint64(*args.FromEndTime[i])
-
-		if args.FromAssetID[i] == common.OwnerUSANAssetID {
-			return nil, fmt.Errorf("USANs cannot be multi-swapped")
-		} else if start == common.TimeLockNow && end == common.TimeLockForever {
-			if state.GetBalance(args.FromAssetID[i], args.From).Cmp(total) < 0 {
-				return nil, fmt.Errorf("not enough from asset")
-			}
-		} else {
-			needValue := common.NewTimeLock(&common.TimeLockItem{
-				StartTime: common.MaxUint64(start, header.Time),
-				EndTime:   end,
-				Value:     total,
-			})
-			if err := needValue.IsValid(); err != nil {
-				return nil, fmt.Errorf("BuildMakeSwapTx from err:%v", err.Error())
-			}
-			if state.GetTimeLockBalance(args.FromAssetID[i], args.From).Cmp(needValue) < 0 {
-				if state.GetBalance(args.FromAssetID[i], args.From).Cmp(total) < 0 {
-					return nil, fmt.Errorf("not enough time lock or asset balance")
-				}
-			}
-		}
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := args.ToParam().Check(nextBlockNumber, header.Time); err != nil {
+		return nil, err
+	}
+	if _, err := state.GetCondition(args.ConditionID); err != nil {
+		return nil, err
+	}
+	if state.GetBalance(args.AssetID, args.From).Cmp(args.Value.ToInt()) < 0 {
+		return nil, common.ErrNotEnoughAsset
 	}
-
 	funcData, err := args.ToData()
 	if err != nil {
 		return nil, err
 	}
-	return FSNCallArgsToSendTxArgs(&args, common.MakeMultiSwapFunc, funcData)
+	return FSNCallArgsToSendTxArgs(&args, common.ConditionalTransferFunc, funcData)
 }
 
-func (s *PublicFusionAPI) BuildRecallMultiSwapSendTxArgs(ctx context.Context, args common.RecallMultiSwapArgs) (*SendTxArgs, error) {
-	state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+func (s *PublicFusionAPI) BuildSettleConditionalSendTxArgs(ctx context.Context, args common.SettleConditionalArgs) (*SendTxArgs, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
 	if state == nil || err != nil {
 		return nil, err
 	}
-
-	var swap common.MultiSwap
-	swap, err = state.GetMultiSwap(args.SwapID)
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := args.ToParam().Check(nextBlockNumber); err != nil {
+		return nil, err
+	}
+	transfer, err := state.GetConditionalTransfer(args.TransferID)
 	if err != nil {
 		return nil, err
 	}
-
-	if err := args.ToParam().Check(common.BigMaxUint64, &swap); err != nil {
+	condition, err := state.GetCondition(transfer.ConditionID)
+	if err != nil {
 		return nil, err
 	}
-
-	if swap.Owner != args.From {
-		return nil, fmt.Errorf("Must be swap onwer can recall")
+	if !condition.ResolvedBefore(transfer.Deadline) && header.Time < transfer.Deadline {
+		return nil, fmt.Errorf("condition of transfer %v is unresolved", transfer.ID.Hex())
 	}
-
 	funcData, err := args.ToData()
 	if err != nil {
 		return nil, err
 	}
-	return FSNCallArgsToSendTxArgs(&args, common.RecallMultiSwapFunc, funcData)
+	return FSNCallArgsToSendTxArgs(&args, common.SettleConditionalFunc, funcData)
 }
 
-func (s *PublicFusionAPI) BuildTakeMultiSwapSendTxArgs(ctx context.Context, args common.TakeMultiSwapArgs) (*SendTxArgs, error) {
-	state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+func (s *PublicFusionAPI) BuildAttestDepositSendTxArgs(ctx context.Context, args common.AttestDepositArgs) (*SendTxArgs, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
 	if state == nil || err != nil {
 		return nil, err
 	}
-	var swap common.MultiSwap
-	swap, err = state.GetMultiSwap(args.SwapID)
-	if err != nil {
+	param := args.ToParam()
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := param.Check(nextBlockNumber); err != nil {
 		return nil, err
 	}
-
-	now := uint64(time.Now().Unix())
-	if err := args.ToParam().Check(common.BigMaxUint64, &swap, now); err != nil {
-		return nil, err
+	if !common.IsBridgeCommitteeMember(args.From) {
+		return nil, fmt.Errorf("%v is not a member of the bridge committee", args.From.Hex())
 	}
-
-	ln := len(swap.MinToAmount)
-	for i := 0; i < ln; i++ {
-		total := new(big.Int).Mul(swap.MinToAmount[i], args.Size)
-		start := swap.ToStartTime[i]
-		end := swap.ToEndTime[i]
-
-		if start == common.TimeLockNow && end == common.TimeLockForever {
-			if state.GetBalance(swap.ToAssetID[i], args.From).Cmp(total) < 0 {
-				return nil, fmt.Errorf("not enough from asset")
-			}
-		} else {
-			needValue := common.NewTimeLock(&common.TimeLockItem{
-				StartTime: start,
-				EndTime:   end,
-				Value:     total,
-			})
-			if err := needValue.IsValid(); err != nil {
-				return nil, fmt.Errorf("BuildTakeSwapTx to err:%v", err.Error())
-			}
-			if state.GetTimeLockBalance(swap.ToAssetID[i], args.From).Cmp(needValue) < 0 {
-				if state.GetBalance(swap.ToAssetID[i], args.From).Cmp(total) < 0 {
-					return nil, fmt.Errorf("not enough time lock or asset balance")
-				}
+	if deposit := state.GetBridgeDeposit(param.DepositID()); deposit != nil {
+		if deposit.Minted != 0 {
+			return nil, fmt.Errorf("deposit %v is already minted", param.DepositID().Hex())
+		}
+		if !deposit.Matches(param) {
+			return nil, fmt.Errorf("deposit %v was attested with another asset, receiver or value", param.DepositID().Hex())
+		}
+		for _, member := range deposit.Attestations {
+			if member == args.From {
+				return nil, fmt.Errorf("%v already attested deposit %v", args.From.Hex(), param.DepositID().Hex())
 			}
 		}
 	}
+	funcData, err := args.ToData()
+	if err != nil {
+		return nil, err
+	}
+	return FSNCallArgsToSendTxArgs(&args, common.AttestDepositFunc, funcData)
+}
 
+func (s *PublicFusionAPI) BuildBridgeWithdrawSendTxArgs(ctx context.Context, args common.BridgeWithdrawArgs) (*SendTxArgs, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := args.ToParam().Check(nextBlockNumber); err != nil {
+		return nil, err
+	}
+	if state.GetBalance(args.AssetID, args.From).Cmp(args.Value.ToInt()) < 0 {
+		return nil, common.ErrNotEnoughAsset
+	}
 	funcData, err := args.ToData()
 	if err != nil {
 		return nil, err
 	}
-	return FSNCallArgsToSendTxArgs(&args, common.TakeMultiSwapFunc, funcData)
+	return FSNCallArgsToSendTxArgs(&args, common.BridgeWithdrawFunc, funcData)
 }
 
-//--------------------------------------------- PrivateFusionAPI -------------------------------------
+// isAssetOwner reports whether addr can act as the owner of the asset, as
+// its single owner or as a key of the set owning it
+func isAssetOwner(state *state.StateDB, asset *common.Asset, addr common.Address) bool {
+	if owners := state.GetAssetOwnerSet(asset.ID); owners != nil {
+		return owners.IsOwner(addr)
+	}
+	return asset.Owner == addr
+}
+
+// resolveNotation returns the address which the notation is assigned to
+func resolveNotation(state *state.StateDB, notation uint64) (common.Address, error) {
+	if state.CalcNotationDisplay(notation/100) != notation {
+		return common.Address{}, fmt.Errorf("invalid notation %d: check digits mismatch", notation)
+	}
+	address, err := state.GetAddressByNotation(notation)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("notation %d is not assigned to any address", notation)
+	}
+	return address, nil
+}
+
+// resolveAddress returns the given address, or the address which the given
+// notation is assigned to at the current head
+func (s *PublicFusionAPI) resolveAddress(ctx context.Context, addr common.AddressOrNotation) (common.Address, error) {
+	if !addr.IsNotation() {
+		return addr.Address, nil
+	}
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return common.Address{}, err
+	}
+	return resolveNotation(state, addr.Notation)
+}
+
+func checkAndSetAddress(state *state.StateDB, to *common.Address, toUSAN uint64) error {
+	if toUSAN != 0 {
+		address, err := resolveNotation(state, toUSAN)
+		if err != nil {
+			return err
+		}
+		if *to == (common.Address{}) {
+			*to = address
+		} else if *to != address {
+			return fmt.Errorf("'to' and 'toUSAN' conflicts")
+		}
+	}
+	if *to == (common.Address{}) {
+		return fmt.Errorf("receiver address must be set and not zero address")
+	}
+	return nil
+}
+
+func CheckAndSetToAddress(args *common.SendAssetArgs, state *state.StateDB) error {
+	return checkAndSetAddress(state, &args.To, args.ToUSAN)
+}
+
+// checkAndSetTargets appends the addresses of the target notations to the targets
+func checkAndSetTargets(state *state.StateDB, targets *[]common.Address, usans []uint64) error {
+	for _, usan := range usans {
+		address, err := resolveNotation(state, usan)
+		if err != nil {
+			return err
+		}
+		*targets = append(*targets, address)
+	}
+	return nil
+}
+
+func (s *PublicFusionAPI) BuildSendAssetSendTxArgs(ctx context.Context, args common.SendAssetArgs) (*SendTxArgs, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if err = CheckAndSetToAddress(&args, state); err != nil {
+		return nil, err
+	}
+	if err := args.ToParam().Check(common.BigMaxUint64); err != nil {
+		return nil, err
+	}
+
+	if state.GetBalance(args.AssetID, args.From).Cmp(args.Value.ToInt()) < 0 {
+		return nil, common.ErrNotEnoughAsset
+	}
+
+	funcData, err := args.ToData()
+	if err != nil {
+		return nil, err
+	}
+	return FSNCallArgsToSendTxArgs(&args, common.SendAssetFunc, funcData)
+}
+
+func (s *PublicFusionAPI) BuildAssetToTimeLockSendTxArgs(ctx context.Context, args common.TimeLockArgs) (*SendTxArgs, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if err = CheckAndSetToAddress(&args.SendAssetArgs, state); err != nil {
+		return nil, err
+	}
+	args.Init(common.AssetToTimeLock)
+	if err := args.ToParam().Check(common.BigMaxUint64, header.Time); err != nil {
+		return nil, err
+	}
+	needValue := common.NewTimeLock(&common.TimeLockItem{
+		StartTime: common.MaxUint64(uint64(*args.StartTime), header.Time),
+		EndTime:   uint64(*args.EndTime),
+		Value:     args.Value.ToInt(),
+	})
+	if err := needValue.IsValid(); err != nil {
+		return nil, fmt.Errorf("BuildAssetToTimeLockTx err:%v", err.Error())
+	}
+	if state.GetBalance(args.AssetID, args.From).Cmp(args.Value.ToInt()) < 0 {
+		return nil, common.ErrNotEnoughAsset
+	}
+
+	funcData, err := args.ToData()
+	if err != nil {
+		return nil, err
+	}
+	return FSNCallArgsToSendTxArgs(&args, common.TimeLockFunc, funcData)
+}
+
+func (s *PublicFusionAPI) BuildTimeLockToTimeLockSendTxArgs(ctx context.Context, args common.TimeLockArgs) (*SendTxArgs, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if err = CheckAndSetToAddress(&args.SendAssetArgs, state); err != nil {
+		return nil, err
+	}
+	args.Init(common.TimeLockToTimeLock)
+	if err := args.ToParam().Check(common.BigMaxUint64, header.Time); err != nil {
+		return nil, err
+	}
+	needValue := common.NewTimeLock(&common.TimeLockItem{
+		StartTime: common.MaxUint64(uint64(*args.StartTime), header.Time),
+		EndTime:   uint64(*args.EndTime),
+		Value:     args.Value.ToInt(),
+	})
+	if err := needValue.IsValid(); err != nil {
+		return nil, fmt.Errorf("BuildTimeLockToTimeLockTx err:%v", err.Error())
+	}
+
+	if state.GetTimeLockBalance(args.AssetID, args.From).Cmp(needValue) < 0 {
+		return nil, common.ErrNotEnoughTimeLock
+	}
+
+	funcData, err := args.ToData()
+	if err != nil {
+		return nil, err
+	}
+	return FSNCallArgsToSendTxArgs(&args, common.TimeLockFunc, funcData)
+}
+
+func (s *PublicFusionAPI) BuildTimeLockToAssetSendTxArgs(ctx context.Context, args common.TimeLockArgs) (*SendTxArgs, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if err = CheckAndSetToAddress(&args.SendAssetArgs, state); err != nil {
+		return nil, err
+	}
+	args.Init(common.TimeLockToAsset)
+	*(*uint64)(args.StartTime) = header.Time
+	*(*uint64)(args.EndTime) = common.TimeLockForever
+	if err := args.ToParam().Check(common.BigMaxUint64, header.Time); err != nil {
+		return nil, err
+	}
+	needValue := common.NewTimeLock(&common.TimeLockItem{
+		StartTime: uint64(*args.StartTime),
+		EndTime:   uint64(*args.EndTime),
+		Value:     args.Value.ToInt(),
+	})
+	if err := needValue.IsValid(); err != nil {
+		return nil, fmt.Errorf("BuildTimeLockToAssetTx err:%v", err.Error())
+	}
+	if state.GetTimeLockBalance(args.AssetID, args.From).Cmp(needValue) < 0 {
+		return nil, common.ErrNotEnoughTimeLock
+	}
+
+	funcData, err := args.ToData()
+	if err != nil {
+		return nil, err
+	}
+	return FSNCallArgsToSendTxArgs(&args, common.TimeLockFunc, funcData)
+}
+
+func (s *PublicFusionAPI) BuildSendTimeLockSendTxArgs(ctx context.Context, args common.TimeLockArgs) (*SendTxArgs, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if err = CheckAndSetToAddress(&args.SendAssetArgs, state); err != nil {
+		return nil, err
+	}
+	args.Init(common.SmartTransfer)
+	if err := args.ToParam().Check(common.BigMaxUint64, header.Time); err != nil {
+		return nil, err
+	}
+	needValue := common.NewTimeLock(&common.TimeLockItem{
+		StartTime: common.MaxUint64(uint64(*args.StartTime), header.Time),
+		EndTime:   uint64(*args.EndTime),
+		Value:     args.Value.ToInt(),
+	})
+	if err := needValue.IsValid(); err != nil {
+		return nil, fmt.Errorf("BuildSendTimeLockSendTxArgs err:%v", err.Error())
+	}
+
+	funcData, err := args.ToData()
+	if err != nil {
+		return nil, err
+	}
+	return FSNCallArgsToSendTxArgs(&args, common.TimeLockFunc, funcData)
+}
+
+func (s *PublicFusionAPI) BuildBuyTicketSendTxArgs(ctx context.Context, args common.BuyTicketArgs) (*SendTxArgs, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+
+	if doesTicketPurchaseExistsForBlock(header.Number.Int64(), args.From) {
+		return nil, fmt.Errorf("Purchase of BuyTicket for this block already submitted")
+	}
+
+	parentTime := header.Time
+	args.Init(parentTime)
+	if err := args.ToParam().Check(common.BigMaxUint64, parentTime); err != nil {
+		return nil, err
+	}
+
+	start := uint64(*args.Start)
+	end := uint64(*args.End)
+	value := new(big.Int).Mul(common.TicketPrice(header.Number), new(big.Int).SetUint64(args.ToParam().Weight(new(big.Int).Add(header.Number, big.NewInt(1)))))
+	needValue := common.NewTimeLock(&common.TimeLockItem{
+		StartTime: common.MaxUint64(start, header.Time),
+		EndTime:   end,
+		Value:     value,
+	})
+	if err := needValue.IsValid(); err != nil {
+		return nil, fmt.Errorf("BuildBuyTicketTx err:%v", err.Error())
+	}
+
+	if state.GetTimeLockBalance(common.SystemAssetID, args.From).Cmp(needValue) < 0 {
+		if state.GetBalance(common.SystemAssetID, args.From).Cmp(value) < 0 {
+			return nil, common.ErrNotEnoughTimeLockOrAsset
+		}
+	}
+
+	funcData, err := args.ToData()
+	if err != nil {
+		return nil, err
+	}
+	return FSNCallArgsToSendTxArgs(&args, common.BuyTicketFunc, funcData)
+}
+
+func (s *PublicFusionAPI) BuildAssetValueChangeSendTxArgs(ctx context.Context, args common.AssetValueChangeExArgs) (*SendTxArgs, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+
+	if err = checkAndSetAddress(state, &args.To, args.ToUSAN); err != nil {
+		return nil, err
+	}
+	if err := args.ToParam().Check(common.BigMaxUint64); err != nil {
+		return nil, err
+	}
+
+	asset, assetError := state.GetAsset(args.AssetID)
+	if assetError != nil {
+		return nil, common.ErrAssetNotFound
+	}
+
+	if !asset.CanChange {
+		return nil, common.ErrAssetNotChangeable
+	}
+
+	if !isAssetOwner(state, &asset, args.From) {
+		return nil, fmt.Errorf("can only be changed by onwer")
+	}
+
+	if asset.Owner != args.To && !args.IsInc {
+		return nil, fmt.Errorf("decrement can only happen to asset's own account")
+	}
+
+	currentBalance := state.GetBalance(args.AssetID, args.To)
+	val := args.Value.ToInt()
+	if !args.IsInc {
+		if currentBalance.Cmp(val) < 0 {
+			return nil, common.ErrNotEnoughAsset
+		}
+	}
+
+	funcData, err := args.ToData()
+	if err != nil {
+		return nil, err
+	}
+	return FSNCallArgsToSendTxArgs(&args, common.AssetValueChangeFunc, funcData)
+}
+
+func (s *PublicFusionAPI) BuildMakeSwapSendTxArgs(ctx context.Context, args common.MakeSwapArgs) (*SendTxArgs, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+
+	if err = checkAndSetTargets(state, &args.Targes, args.TargesUSAN); err != nil {
+		return nil, err
+	}
+	args.Init(new(big.Int).SetUint64(header.Time))
+	now := uint64(time.Now().Unix())
+	if err := args.ToParam().Check(common.BigMaxUint64, now); err != nil {
+		return nil, err
+	}
+
+	total := new(big.Int).Mul(args.MinFromAmount.ToInt(), args.SwapSize)
+	start := uint64(*args.FromStartTime)
+	end := uint64(*args.FromEndTime)
+
+	if args.FromAssetID == common.OwnerUSANAssetID {
+		notation := state.GetNotation(args.From)
+		if notation == 0 {
+			return nil, fmt.Errorf("from address does not have a notation")
+		}
+	} else if start == common.TimeLockNow && end == common.TimeLockForever {
+		if state.GetBalance(args.FromAssetID, args.From).Cmp(total) < 0 {
+			return nil, common.ErrNotEnoughFromAsset
+		}
+	} else {
+		needValue := common.NewTimeLock(&common.TimeLockItem{
+			StartTime: common.MaxUint64(start, header.Time),
+			EndTime:   end,
+			Value:     total,
+		})
+		if err := needValue.IsValid(); err != nil {
+			return nil, fmt.Errorf("BuildMakeSwapTx from err:%v", err.Error())
+		}
+		if state.GetTimeLockBalance(args.FromAssetID, args.From).Cmp(needValue) < 0 {
+			if state.GetBalance(args.FromAssetID, args.From).Cmp(total) < 0 {
+				return nil, common.ErrNotEnoughTimeLockOrAsset
+			}
+		}
+	}
+
+	funcData, err := args.ToData()
+	if err != nil {
+		return nil, err
+	}
+	return FSNCallArgsToSendTxArgs(&args, common.MakeSwapFuncExt, funcData)
+}
+
+func (s *PublicFusionAPI) BuildRecallSwapSendTxArgs(ctx context.Context, args common.RecallSwapArgs) (*SendTxArgs, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+
+	var swap common.Swap
+	swap, err = state.GetSwap(args.SwapID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := args.ToParam().Check(common.BigMaxUint64, &swap); err != nil {
+		return nil, err
+	}
+
+	if swap.Owner != args.From {
+		return nil, common.ErrNotSwapOwner
+	}
+
+	funcData, err := args.ToData()
+	if err != nil {
+		return nil, err
+	}
+	return FSNCallArgsToSendTxArgs(&args, common.RecallSwapFunc, funcData)
+}
+
+func (s *PublicFusionAPI) BuildTakeSwapSendTxArgs(ctx context.Context, args common.TakeSwapArgs) (*SendTxArgs, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	var swap common.Swap
+	swap, err = state.GetSwap(args.SwapID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uint64(time.Now().Unix())
+	param := args.ToParam()
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := param.Check(nextBlockNumber, &swap, now); err != nil {
+		return nil, err
+	}
+
+	total := new(big.Int).Mul(swap.MinToAmount, param.FillSize(nextBlockNumber, &swap))
+	start := swap.ToStartTime
+	end := swap.ToEndTime
+
+	if start == common.TimeLockNow && end == common.TimeLockForever {
+		if state.GetBalance(swap.ToAssetID, args.From).Cmp(total) < 0 {
+			return nil, common.ErrNotEnoughFromAsset
+		}
+	} else {
+		needValue := common.NewTimeLock(&common.TimeLockItem{
+			StartTime: start,
+			EndTime:   end,
+			Value:     total,
+		})
+		if err := needValue.IsValid(); err != nil {
+			return nil, fmt.Errorf("BuildTakeSwapTx to err:%v", err.Error())
+		}
+		if state.GetTimeLockBalance(swap.ToAssetID, args.From).Cmp(needValue) < 0 {
+			if state.GetBalance(swap.ToAssetID, args.From).Cmp(total) < 0 {
+				return nil, common.ErrNotEnoughTimeLockOrAsset
+			}
+		}
+	}
+
+	funcData, err := args.ToData()
+	if err != nil {
+		return nil, err
+	}
+	return FSNCallArgsToSendTxArgs(&args, common.TakeSwapFuncExt, funcData)
+}
+
+func (s *PublicFusionAPI) BuildMakeMultiSwapSendTxArgs(ctx context.Context, args common.MakeMultiSwapArgs) (*SendTxArgs, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+
+	if err = checkAndSetTargets(state, &args.Targes, args.TargesUSAN); err != nil {
+		return nil, err
+	}
+	args.Init(new(big.Int).SetUint64(header.Time))
+	now := uint64(time.Now().Unix())
+	if err := args.ToParam().Check(common.BigMaxUint64, now); err != nil {
+		return nil, err
+	}
+
+	ln := len(args.MinFromAmount)
+	for i := 0; i < ln; i++ {
+		total := new(big.Int).Mul(args.MinFromAmount[i].ToInt(), args.SwapSize)
+		start := uint64(*args.FromStartTime[i])
+		end := uint64(*args.FromEndTime[i])
+
+		if args.FromAssetID[i] == common.OwnerUSANAssetID {
+			return nil, fmt.Errorf("USANs cannot be multi-swapped")
+		} else if start == common.TimeLockNow && end == common.TimeLockForever {
+			if state.GetBalance(args.FromAssetID[i], args.From).Cmp(total) < 0 {
+				return nil, common.ErrNotEnoughFromAsset
+			}
+		} else {
+			needValue := common.NewTimeLock(&common.TimeLockItem{
+				StartTime: common.MaxUint64(start, header.Time),
+				EndTime:   end,
+				Value:     total,
+			})
+			if err := needValue.IsValid(); err != nil {
+				return nil, fmt.Errorf("BuildMakeSwapTx from err:%v", err.Error())
+			}
+			if state.GetTimeLockBalance(args.FromAssetID[i], args.From).Cmp(needValue) < 0 {
+				if state.GetBalance(args.FromAssetID[i], args.From).Cmp(total) < 0 {
+					return nil, common.ErrNotEnoughTimeLockOrAsset
+				}
+			}
+		}
+	}
+
+	funcData, err := args.ToData()
+	if err != nil {
+		return nil, err
+	}
+	return FSNCallArgsToSendTxArgs(&args, common.MakeMultiSwapFunc, funcData)
+}
+
+func (s *PublicFusionAPI) BuildRecallMultiSwapSendTxArgs(ctx context.Context, args common.RecallMultiSwapArgs) (*SendTxArgs, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+
+	var swap common.MultiSwap
+	swap, err = state.GetMultiSwap(args.SwapID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := args.ToParam().Check(common.BigMaxUint64, &swap); err != nil {
+		return nil, err
+	}
+
+	if swap.Owner != args.From {
+		return nil, common.ErrNotSwapOwner
+	}
+
+	funcData, err := args.ToData()
+	if err != nil {
+		return nil, err
+	}
+	return FSNCallArgsToSendTxArgs(&args, common.RecallMultiSwapFunc, funcData)
+}
+
+func (s *PublicFusionAPI) BuildTakeMultiSwapSendTxArgs(ctx context.Context, args common.TakeMultiSwapArgs) (*SendTxArgs, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	var swap common.MultiSwap
+	swap, err = state.GetMultiSwap(args.SwapID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uint64(time.Now().Unix())
+	if err := args.ToParam().Check(common.BigMaxUint64, &swap, now); err != nil {
+		return nil, err
+	}
+
+	ln := len(swap.MinToAmount)
+	for i := 0; i < ln; i++ {
+		total := new(big.Int).Mul(swap.MinToAmount[i], args.Size)
+		start := swap.ToStartTime[i]
+		end := swap.ToEndTime[i]
+
+		if start == common.TimeLockNow && end == common.TimeLockForever {
+			if state.GetBalance(swap.ToAssetID[i], args.From).Cmp(total) < 0 {
+				return nil, common.ErrNotEnoughFromAsset
+			}
+		} else {
+			needValue := common.NewTimeLock(&common.TimeLockItem{
+				StartTime: start,
+				EndTime:   end,
+				Value:     total,
+			})
+			if err := needValue.IsValid(); err != nil {
+				return nil, fmt.Errorf("BuildTakeSwapTx to err:%v", err.Error())
+			}
+			if state.GetTimeLockBalance(swap.ToAssetID[i], args.From).Cmp(needValue) < 0 {
+				if state.GetBalance(swap.ToAssetID[i], args.From).Cmp(total) < 0 {
+					return nil, common.ErrNotEnoughTimeLockOrAsset
+				}
+			}
+		}
+	}
+
+	funcData, err := args.ToData()
+	if err != nil {
+		return nil, err
+	}
+	return FSNCallArgsToSendTxArgs(&args, common.TakeMultiSwapFunc, funcData)
+}
+
+// GetTypedCallNonce returns the nonce of the next typed call signed by addr
+func (s *PublicFusionAPI) GetTypedCallNonce(ctx context.Context, addr common.AddressOrNotation, blockNr rpc.BlockNumber) (uint64, error) {
+	address, err := s.resolveAddress(ctx, addr)
+	if err != nil {
+		return 0, err
+	}
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return 0, err
+	}
+	return state.GetTypedCallNonce(address), state.Error()
+}
+
+// GetStakingKey returns the key authorized to buy tickets for addr
+func (s *PublicFusionAPI) GetStakingKey(ctx context.Context, addr common.AddressOrNotation, blockNr rpc.BlockNumber) (common.Address, error) {
+	address, err := s.resolveAddress(ctx, addr)
+	if err != nil {
+		return common.Address{}, err
+	}
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return common.Address{}, err
+	}
+	return state.GetStakingKey(address), state.Error()
+}
+
+// GetTypedData returns the EIP-712 typed data of an FSN call, given as the
+// data of its transaction, to be signed by signer for a typed call. The
+// signer's next typed call nonce is used if nonce is not given.
+func (s *PublicFusionAPI) GetTypedData(ctx context.Context, call hexutil.Bytes, signer common.Address, deadline hexutil.Uint64, nonce *hexutil.Uint64) (*types.FSNTypedData, error) {
+	var param common.FSNCallParam
+	if err := rlp.DecodeBytes(call, &param); err != nil {
+		return nil, fmt.Errorf("decode call to FSNCallParam err %v", err)
+	}
+	if nonce == nil {
+		state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+		if state == nil || err != nil {
+			return nil, err
+		}
+		n := hexutil.Uint64(state.GetTypedCallNonce(signer))
+		nonce = &n
+	}
+	return types.NewFSNTypedData(s.b.ChainConfig().ChainID, &param, uint64(*nonce), uint64(deadline))
+}
+
+// GetTypedCallSigner returns the address which signed the typed call
+func (s *PublicFusionAPI) GetTypedCallSigner(ctx context.Context, args common.TypedCallArgs) (common.Address, error) {
+	param, err := args.ToParam()
+	if err != nil {
+		return common.Address{}, err
+	}
+	typedData, err := types.NewFSNTypedData(s.b.ChainConfig().ChainID, &param.Call, param.Nonce, param.Deadline)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return typedData.Signer(param.Signature)
+}
+
+func (s *PublicFusionAPI) BuildTypedCallSendTxArgs(ctx context.Context, args common.TypedCallArgs) (*SendTxArgs, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	param, err := args.ToParam()
+	if err != nil {
+		return nil, err
+	}
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := param.Check(nextBlockNumber, header.Time); err != nil {
+		return nil, err
+	}
+	signer, err := s.GetTypedCallSigner(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if nonce := state.GetTypedCallNonce(signer); nonce != param.Nonce {
+		return nil, fmt.Errorf("typed call nonce mismatch: have %d, want %d", param.Nonce, nonce)
+	}
+	funcData, err := param.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+	return FSNCallArgsToSendTxArgs(&args, common.TypedCallFunc, funcData)
+}
+
+func (s *PublicFusionAPI) BuildStakingKeySendTxArgs(ctx context.Context, args common.StakingKeyArgs) (*SendTxArgs, error) {
+	_, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := args.ToParam().Check(nextBlockNumber, args.From); err != nil {
+		return nil, err
+	}
+	funcData, err := args.ToData()
+	if err != nil {
+		return nil, err
+	}
+	return FSNCallArgsToSendTxArgs(&args, common.StakingKeyFunc, funcData)
+}
+
+func (s *PublicFusionAPI) BuildStakingBuyTicketSendTxArgs(ctx context.Context, args common.StakingBuyTicketArgs) (*SendTxArgs, error) {
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if key := state.GetStakingKey(args.Owner); key != args.From {
+		return nil, fmt.Errorf("%v is not the staking key of %v", args.From.Hex(), args.Owner.Hex())
+	}
+
+	args.Init(header.Time)
+	nextBlockNumber := new(big.Int).Add(header.Number, big.NewInt(1))
+	if err := args.ToParam().Check(nextBlockNumber, header.Time); err != nil {
+		return nil, err
+	}
+
+	needValue := common.NewTimeLock(&common.TimeLockItem{
+		StartTime: common.MaxUint64(uint64(*args.Start), header.Time),
+		EndTime:   uint64(*args.End),
+		Value:     new(big.Int).Mul(common.TicketPrice(header.Number), new(big.Int).SetUint64(args.ToParam().ToBuyTicketParam().Weight(nextBlockNumber))),
+	})
+	if err := needValue.IsValid(); err != nil {
+		return nil, fmt.Errorf("BuildStakingBuyTicketTx err:%v", err.Error())
+	}
+	if state.GetTimeLockBalance(common.SystemAssetID, args.Owner).Cmp(needValue) < 0 {
+		return nil, fmt.Errorf("owner has not enough time lock balance")
+	}
+
+	funcData, err := args.ToData()
+	if err != nil {
+		return nil, err
+	}
+	return FSNCallArgsToSendTxArgs(&args, common.StakingBuyTicketFunc, funcData)
+}
+
+//--------------------------------------------- PrivateFusionAPI -------------------------------------
 
 // PrivateFusionAPI ss
 type PrivateFusionAPI struct {
 	PublicFusionAPI
 	nonceLock *AddrLocker
-	papi      *PrivateAccountAPI
+	papi      *PrivateAccountAPI
+}
+
+// NewPrivateFusionAPI ss
+func NewPrivateFusionAPI(b Backend, nonceLock *AddrLocker, papi *PrivateAccountAPI) *PrivateFusionAPI {
+	return &PrivateFusionAPI{
+		PublicFusionAPI: *NewPublicFusionAPI(b),
+		nonceLock:       nonceLock,
+		papi:            papi,
+	}
+}
+
+// GenNotation ss
+func (s *PrivateFusionAPI) GenNotation(ctx context.Context, args common.FusionBaseArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildGenNotationSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// GenAsset ss
+func (s *PrivateFusionAPI) GenAsset(ctx context.Context, args common.GenAssetArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildGenAssetSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// SendAsset ss
+func (s *PrivateFusionAPI) SendAsset(ctx context.Context, args common.SendAssetArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildSendAssetSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// AssetToTimeLock ss
+func (s *PrivateFusionAPI) AssetToTimeLock(ctx context.Context, args common.TimeLockArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildAssetToTimeLockSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// TimeLockToTimeLock ss
+func (s *PrivateFusionAPI) TimeLockToTimeLock(ctx context.Context, args common.TimeLockArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildTimeLockToTimeLockSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// TimeLockToAsset ss
+func (s *PrivateFusionAPI) TimeLockToAsset(ctx context.Context, args common.TimeLockArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildTimeLockToAssetSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// SendTimeLock ss
+func (s *PrivateFusionAPI) SendTimeLock(ctx context.Context, args common.TimeLockArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildSendTimeLockSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+/** on our public gateways too many buyTickets are past through
+this cache of purchase on block will stop multiple purchase
+attempt on a block (which state_transistion also flags).
+the goals is to limit the number of buytickets being processed
+if it is know that they will fail anyway
+*/
+func doesTicketPurchaseExistsForBlock(blockNbr int64, from common.Address) bool {
+	buyTicketOnBlockMapMutex.Lock()
+	defer buyTicketOnBlockMapMutex.Unlock()
+	if lastBlockOfBuyTickets == 0 || lastBlockOfBuyTickets != blockNbr {
+		lastBlockOfBuyTickets = blockNbr
+		buyTicketOnBlockMap = make(map[common.Address]bool)
+	}
+	_, found := buyTicketOnBlockMap[from]
+	return found
+}
+
+// only record on purchase ticket successfully
+func addTicketPurchaseForBlock(from common.Address) {
+	buyTicketOnBlockMapMutex.Lock()
+	defer buyTicketOnBlockMapMutex.Unlock()
+	buyTicketOnBlockMap[from] = true
+}
+
+// BuyTicket ss
+func (s *PrivateFusionAPI) BuyTicket(ctx context.Context, args common.BuyTicketArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildBuyTicketSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	hash, err := s.papi.SendTransaction(ctx, *sendArgs, passwd)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	addTicketPurchaseForBlock(args.From)
+	return hash, err
+}
+
+// IncAsset ss
+func (s *PrivateFusionAPI) IncAsset(ctx context.Context, args common.AssetValueChangeExArgs, passwd string) (common.Hash, error) {
+	args.IsInc = true
+	sendArgs, err := s.BuildAssetValueChangeSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// DecAsset ss
+func (s *PrivateFusionAPI) DecAsset(ctx context.Context, args common.AssetValueChangeExArgs, passwd string) (common.Hash, error) {
+	args.IsInc = false
+	sendArgs, err := s.BuildAssetValueChangeSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// MakeSwap ss
+func (s *PrivateFusionAPI) MakeSwap(ctx context.Context, args common.MakeSwapArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildMakeSwapSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// RecallSwap ss
+func (s *PrivateFusionAPI) RecallSwap(ctx context.Context, args common.RecallSwapArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildRecallSwapSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// TakeSwap ss
+func (s *PrivateFusionAPI) TakeSwap(ctx context.Context, args common.TakeSwapArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildTakeSwapSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// MakeMultiSwap ss
+func (s *PrivateFusionAPI) MakeMultiSwap(ctx context.Context, args common.MakeMultiSwapArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildMakeMultiSwapSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// RecallMultiSwap ss
+func (s *PrivateFusionAPI) RecallMultiSwap(ctx context.Context, args common.RecallMultiSwapArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildRecallMultiSwapSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// TakeMultiSwap ss
+func (s *PrivateFusionAPI) TakeMultiSwap(ctx context.Context, args common.TakeMultiSwapArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildTakeMultiSwapSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// SignTypedCall signs the EIP-712 typed data of an FSN call, given as the data
+// of its transaction, with the key of signer. The returned args only lack
+// the base args of the relayer sending the typed call.
+func (s *PrivateFusionAPI) SignTypedCall(ctx context.Context, signer common.Address, call hexutil.Bytes, deadline hexutil.Uint64, passwd string) (*common.TypedCallArgs, error) {
+	nonce, err := s.GetTypedCallNonce(ctx, common.AddressOrNotation{Address: signer}, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	typedNonce := hexutil.Uint64(nonce)
+	typedData, err := s.GetTypedData(ctx, call, signer, deadline, &typedNonce)
+	if err != nil {
+		return nil, err
+	}
+	account := accounts.Account{Address: signer}
+	wallet, err := s.b.AccountManager().Find(account)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := wallet.SignDataWithPassphrase(account, passwd, accounts.MimetypeTypedData, typedData.RawData())
+	if err != nil {
+		return nil, err
+	}
+	signature[64] += 27 // Transform V from 0/1 to 27/28 as produced by wallets
+	return &common.TypedCallArgs{
+		Call:      call,
+		Nonce:     typedNonce,
+		Deadline:  deadline,
+		Signature: signature,
+	}, nil
+}
+
+// TypedCall ss
+func (s *PrivateFusionAPI) TypedCall(ctx context.Context, args common.TypedCallArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildTypedCallSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// SetAssetTransferList ss
+func (s *PrivateFusionAPI) SetAssetTransferList(ctx context.Context, args common.AssetTransferListArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildAssetTransferListSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// SetFsnCallFee ss
+func (s *PrivateFusionAPI) SetFsnCallFee(ctx context.Context, args common.SetFsnCallFeeArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildSetFsnCallFeeSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// CreateProposal ss
+func (s *PrivateFusionAPI) CreateProposal(ctx context.Context, args common.CreateProposalArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildCreateProposalSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// VoteProposal ss
+func (s *PrivateFusionAPI) VoteProposal(ctx context.Context, args common.VoteProposalArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildVoteProposalSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// RevokeTicket ss
+func (s *PrivateFusionAPI) RevokeTicket(ctx context.Context, args common.RevokeTicketArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildRevokeTicketSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// EscrowAsset ss
+func (s *PrivateFusionAPI) EscrowAsset(ctx context.Context, args common.EscrowAssetArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildEscrowAssetSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// ClaimEscrow ss
+func (s *PrivateFusionAPI) ClaimEscrow(ctx context.Context, args common.ClaimEscrowArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildClaimEscrowSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// CreateStream ss
+func (s *PrivateFusionAPI) CreateStream(ctx context.Context, args common.CreateStreamArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildCreateStreamSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// WithdrawStream ss
+func (s *PrivateFusionAPI) WithdrawStream(ctx context.Context, args common.WithdrawStreamArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildWithdrawStreamSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// CreateCondition ss
+func (s *PrivateFusionAPI) CreateCondition(ctx context.Context, args common.CreateConditionArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildCreateConditionSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// ResolveCondition ss
+func (s *PrivateFusionAPI) ResolveCondition(ctx context.Context, args common.ResolveConditionArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildResolveConditionSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// ConditionalTransfer ss
+func (s *PrivateFusionAPI) ConditionalTransfer(ctx context.Context, args common.ConditionalTransferArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildConditionalTransferSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// SettleConditional ss
+func (s *PrivateFusionAPI) SettleConditional(ctx context.Context, args common.SettleConditionalArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildSettleConditionalSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// AttestDeposit ss
+func (s *PrivateFusionAPI) AttestDeposit(ctx context.Context, args common.AttestDepositArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildAttestDepositSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// BridgeWithdraw ss
+func (s *PrivateFusionAPI) BridgeWithdraw(ctx context.Context, args common.BridgeWithdrawArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildBridgeWithdrawSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// SetStakingKey ss
+func (s *PrivateFusionAPI) SetStakingKey(ctx context.Context, args common.StakingKeyArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildStakingKeySendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+// StakingBuyTicket ss
+func (s *PrivateFusionAPI) StakingBuyTicket(ctx context.Context, args common.StakingBuyTicketArgs, passwd string) (common.Hash, error) {
+	sendArgs, err := s.BuildStakingBuyTicketSendTxArgs(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+}
+
+//--------------------------------------------- FusionTransactionAPI -------------------------------------
+
+// FusionTransactionAPI ss
+type FusionTransactionAPI struct {
+	b         Backend
+	pubapi    *PublicFusionAPI
+	nonceLock *AddrLocker
+	txapi     *PublicTransactionPoolAPI
+}
+
+var fusionTransactionAPI *FusionTransactionAPI
+
+// NewFusionTransactionAPI ss
+func NewFusionTransactionAPI(b Backend, nonceLock *AddrLocker, txapi *PublicTransactionPoolAPI) *FusionTransactionAPI {
+	fusionTransactionAPI = &FusionTransactionAPI{
+		b:         b,
+		pubapi:    NewPublicFusionAPI(b),
+		nonceLock: nonceLock,
+		txapi:     txapi,
+	}
+	return fusionTransactionAPI
+}
+
+// BuildReportIllegalTx ss
+func (s *FusionTransactionAPI) BuildReportIllegalTx(ctx context.Context, args common.FusionBaseArgs, content hexutil.Bytes) (*types.Transaction, error) {
+	oldtx := s.b.GetPoolTransactionByPredicate(func(tx *types.Transaction) bool {
+		param := common.FSNCallParam{}
+		rlp.DecodeBytes(tx.Data(), &param)
+		return param.Func == common.ReportIllegalFunc && bytes.Equal(param.Data, content)
+	})
+	if oldtx != nil {
+		return nil, fmt.Errorf("ReportIllegal: already reported in txpool")
+	}
+	sendArgs, err := FSNCallArgsToSendTxArgs(&args, common.ReportIllegalFunc, content)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildTransaction(ctx, *sendArgs)
+}
+
+// ReportIllegal ss
+func (s *FusionTransactionAPI) ReportIllegal(ctx context.Context, args common.FusionBaseArgs, content []byte) (common.Hash, error) {
+	tx, err := s.BuildReportIllegalTx(ctx, args, content)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.sendTransaction(ctx, args.From, tx)
+}
+
+func (s *FusionTransactionAPI) buildTransaction(ctx context.Context, args SendTxArgs) (*types.Transaction, error) {
+	if args.Nonce == nil {
+		s.nonceLock.LockAddr(args.From)
+		defer s.nonceLock.UnlockAddr(args.From)
+	}
+	if err := args.setDefaults(ctx, s.b); err != nil {
+		return nil, err
+	}
+	tx := args.toTransaction()
+	return tx, nil
+}
+
+func (s *FusionTransactionAPI) signTransaction(ctx context.Context, from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+	account := accounts.Account{Address: from}
+	wallet, err := s.b.AccountManager().Find(account)
+	if err != nil {
+		return nil, err
+	}
+	var chainID *big.Int
+	if config := s.b.ChainConfig(); config.IsEIP155(s.b.CurrentBlock().Number()) {
+		chainID = config.ChainID
+	}
+	return wallet.SignTx(account, tx, chainID)
 }
 
-// NewPrivateFusionAPI ss
-func NewPrivateFusionAPI(b Backend, nonceLock *AddrLocker, papi *PrivateAccountAPI) *PrivateFusionAPI {
-	return &PrivateFusionAPI{
-		PublicFusionAPI: *NewPublicFusionAPI(b),
-		nonceLock:       nonceLock,
-		papi:            papi,
+func (s *FusionTransactionAPI) signTransactionResult(ctx context.Context, from common.Address, tx *types.Transaction) (*SignTransactionResult, error) {
+	signed, err := s.signTransaction(ctx, from, tx)
+	if err != nil {
+		return nil, err
+	}
+	data, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		return nil, err
+	}
+	return &SignTransactionResult{data, signed}, nil
+}
+
+func (s *FusionTransactionAPI) sendTransaction(ctx context.Context, from common.Address, tx *types.Transaction) (common.Hash, error) {
+	signed, err := s.signTransaction(ctx, from, tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.SendRawTransaction(ctx, signed)
+}
+
+// SendRawTransaction wacom
+func (s *FusionTransactionAPI) SendRawTransaction(ctx context.Context, tx *types.Transaction) (common.Hash, error) {
+	encodedTx, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.txapi.SendRawTransaction(ctx, encodedTx)
+}
+
+// SignSponsorTx signs an FSN call transaction signed by its sender as its
+// sponsor, which pays the gas and the fee of the call, e.g. an exchange paying
+// for the ticket purchases of its users.
+func (s *FusionTransactionAPI) SignSponsorTx(ctx context.Context, tx *types.Transaction, sponsor common.Address) (*SignTransactionResult, error) {
+	if tx.Sponsored() {
+		return nil, fmt.Errorf("transaction is sponsored already")
+	}
+	if !common.IsFsnCall(tx.To()) {
+		return nil, fmt.Errorf("only FSN calls can be sponsored")
+	}
+	account := accounts.Account{Address: sponsor}
+	wallet, err := s.b.AccountManager().Find(account)
+	if err != nil {
+		return nil, err
+	}
+	// the sponsor signs the hash of the transaction signed by its sender
+	encoded, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := wallet.SignData(account, accounts.MimetypeDataWithValidator, encoded)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := tx.WithSponsorSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	data, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		return nil, err
+	}
+	return &SignTransactionResult{data, signed}, nil
+}
+
+// GetUniqueHash returns the hash the IDs of the assets and swaps created by
+// the transaction are derived from in the next block, the transaction may be
+// unsigned. Once IsSaltedUniqueHashEnabled the hash depends on the sender,
+// from is required for an unsigned transaction then.
+func (s *FusionTransactionAPI) GetUniqueHash(ctx context.Context, tx *types.Transaction, from *common.Address) (common.Hash, error) {
+	number := new(big.Int).Add(s.b.CurrentBlock().Number(), big.NewInt(1))
+	if !common.IsSaltedUniqueHashEnabled(number) {
+		return tx.UniqueHash(), nil
+	}
+	sender, err := uniqueHashSender(s.b, tx, from)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return core.GetUniqueHashAt(tx, sender, number), nil
+}
+
+// CheckUniqueHash returns the hash the IDs created by the transaction are
+// derived from in the next block, see GetUniqueHash, and an error if the
+// asset or swap the transaction would create already exists, so that the
+// collision is detected before the transaction is sent.
+func (s *FusionTransactionAPI) CheckUniqueHash(ctx context.Context, tx *types.Transaction, from *common.Address) (common.Hash, error) {
+	hash, err := s.GetUniqueHash(ctx, tx, from)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if !common.IsFsnCall(tx.To()) {
+		return hash, nil
+	}
+	param := common.FSNCallParam{}
+	if err := rlp.DecodeBytes(tx.Data(), &param); err != nil {
+		return common.Hash{}, fmt.Errorf("decode FSNCallParam error")
+	}
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.PendingBlockNumber)
+	if state == nil || err != nil {
+		return common.Hash{}, err
+	}
+	switch param.Func {
+	case common.GenAssetFunc, common.GenRestrictedAssetFunc, common.GenMultiOwnerAssetFunc:
+		if _, err := state.GetAsset(hash); err == nil {
+			return hash, common.NewFsnError(common.FsnErrAssetExists, "%s asset exists", hash.String())
+		}
+	case common.MakeSwapFunc, common.MakeSwapFuncExt, common.MakeMultiSwapFunc:
+		_, swapErr := state.GetSwap(hash)
+		_, multiSwapErr := state.GetMultiSwap(hash)
+		if swapErr == nil || multiSwapErr == nil {
+			return hash, common.ErrSwapExists
+		}
+	}
+	return hash, nil
+}
+
+// uniqueHashSender returns from if set, the sender of tx otherwise
+func uniqueHashSender(b Backend, tx *types.Transaction, from *common.Address) (common.Address, error) {
+	if from != nil {
+		return *from, nil
+	}
+	if v, _, _ := tx.RawSignatureValues(); v == nil || v.Sign() == 0 {
+		return common.Address{}, fmt.Errorf("from is required for an unsigned transaction")
+	}
+	return types.Sender(types.NewEIP155Signer(b.ChainConfig().ChainID), tx)
+}
+
+// BuildGenNotationTx ss
+func (s *FusionTransactionAPI) BuildGenNotationTx(ctx context.Context, args common.FusionBaseArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildGenNotationSendTxArgs(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildTransaction(ctx, *sendArgs)
+}
+
+// GenNotation ss
+func (s *FusionTransactionAPI) GenNotation(ctx context.Context, args common.FusionBaseArgs) (common.Hash, error) {
+	tx, err := s.BuildGenNotationTx(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.sendTransaction(ctx, args.From, tx)
+}
+
+// BuildGenAssetTx ss
+func (s *FusionTransactionAPI) BuildGenAssetTx(ctx context.Context, args common.GenAssetArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildGenAssetSendTxArgs(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildTransaction(ctx, *sendArgs)
+}
+
+// GenAsset ss
+func (s *FusionTransactionAPI) GenAsset(ctx context.Context, args common.GenAssetArgs) (common.Hash, error) {
+	tx, err := s.BuildGenAssetTx(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.sendTransaction(ctx, args.From, tx)
+}
+
+// BuildSendAssetTx ss
+func (s *FusionTransactionAPI) BuildSendAssetTx(ctx context.Context, args common.SendAssetArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildSendAssetSendTxArgs(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildTransaction(ctx, *sendArgs)
+}
+
+// SendAsset ss
+func (s *FusionTransactionAPI) SendAsset(ctx context.Context, args common.SendAssetArgs) (common.Hash, error) {
+	tx, err := s.BuildSendAssetTx(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.sendTransaction(ctx, args.From, tx)
+}
+
+// BuildAssetToTimeLockTx ss
+func (s *FusionTransactionAPI) BuildAssetToTimeLockTx(ctx context.Context, args common.TimeLockArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildAssetToTimeLockSendTxArgs(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildTransaction(ctx, *sendArgs)
+}
+
+// AssetToTimeLock ss
+func (s *FusionTransactionAPI) AssetToTimeLock(ctx context.Context, args common.TimeLockArgs) (common.Hash, error) {
+	tx, err := s.BuildAssetToTimeLockTx(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.sendTransaction(ctx, args.From, tx)
+}
+
+// BuildTimeLockToTimeLockTx ss
+func (s *FusionTransactionAPI) BuildTimeLockToTimeLockTx(ctx context.Context, args common.TimeLockArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildTimeLockToTimeLockSendTxArgs(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildTransaction(ctx, *sendArgs)
+}
+
+// TimeLockToTimeLock ss
+func (s *FusionTransactionAPI) TimeLockToTimeLock(ctx context.Context, args common.TimeLockArgs) (common.Hash, error) {
+	tx, err := s.BuildTimeLockToTimeLockTx(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.sendTransaction(ctx, args.From, tx)
+}
+
+// BuildTimeLockToAssetTx ss
+func (s *FusionTransactionAPI) BuildTimeLockToAssetTx(ctx context.Context, args common.TimeLockArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildTimeLockToAssetSendTxArgs(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildTransaction(ctx, *sendArgs)
+}
+
+// TimeLockToAsset ss
+func (s *FusionTransactionAPI) TimeLockToAsset(ctx context.Context, args common.TimeLockArgs) (common.Hash, error) {
+	tx, err := s.BuildTimeLockToAssetTx(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.sendTransaction(ctx, args.From, tx)
+}
+
+// BuildSendTimeLockTx ss
+func (s *FusionTransactionAPI) BuildSendTimeLockTx(ctx context.Context, args common.TimeLockArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildSendTimeLockSendTxArgs(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildTransaction(ctx, *sendArgs)
+}
+
+// SendTimeLock ss
+func (s *FusionTransactionAPI) SendTimeLock(ctx context.Context, args common.TimeLockArgs) (common.Hash, error) {
+	tx, err := s.BuildSendTimeLockTx(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.sendTransaction(ctx, args.From, tx)
+}
+
+// BuildBuyTicketTx ss
+func (s *FusionTransactionAPI) BuildBuyTicketTx(ctx context.Context, args common.BuyTicketArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildBuyTicketSendTxArgs(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildTransaction(ctx, *sendArgs)
+}
+
+// BuyTicket ss
+func (s *FusionTransactionAPI) BuyTicket(ctx context.Context, args common.BuyTicketArgs) (common.Hash, error) {
+	tx, err := s.BuildBuyTicketTx(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	hash, err := s.sendTransaction(ctx, args.From, tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	addTicketPurchaseForBlock(args.From)
+	return hash, err
+}
+
+// BuildIncAssetTx ss
+func (s *FusionTransactionAPI) BuildIncAssetTx(ctx context.Context, args common.AssetValueChangeExArgs) (*types.Transaction, error) {
+	args.IsInc = true
+	sendArgs, err := s.pubapi.BuildAssetValueChangeSendTxArgs(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildTransaction(ctx, *sendArgs)
+}
+
+// IncAsset ss
+func (s *FusionTransactionAPI) IncAsset(ctx context.Context, args common.AssetValueChangeExArgs) (common.Hash, error) {
+	tx, err := s.BuildIncAssetTx(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.sendTransaction(ctx, args.From, tx)
+}
+
+// BuildDecAssetTx ss
+func (s *FusionTransactionAPI) BuildDecAssetTx(ctx context.Context, args common.AssetValueChangeExArgs) (*types.Transaction, error) {
+	args.IsInc = false
+	sendArgs, err := s.pubapi.BuildAssetValueChangeSendTxArgs(ctx, args)
+	if err != nil {
+		return nil, err
 	}
+	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// GenNotation ss
-func (s *PrivateFusionAPI) GenNotation(ctx context.Context, args common.FusionBaseArgs, passwd string) (common.Hash, error) {
-	sendArgs, err := s.BuildGenNotationSendTxArgs(ctx, args)
+// DecAsset ss
+func (s *FusionTransactionAPI) DecAsset(ctx context.Context, args common.AssetValueChangeExArgs) (common.Hash, error) {
+	tx, err := s.BuildDecAssetTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+	return s.sendTransaction(ctx, args.From, tx)
 }
 
-// GenAsset ss
-func (s *PrivateFusionAPI) GenAsset(ctx context.Context, args common.GenAssetArgs, passwd string) (common.Hash, error) {
-	sendArgs, err := s.BuildGenAssetSendTxArgs(ctx, args)
+// BuildMakeSwapTx ss
+func (s *FusionTransactionAPI) BuildMakeSwapTx(ctx context.Context, args common.MakeSwapArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildMakeSwapSendTxArgs(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// SendAsset ss
-func (s *PrivateFusionAPI) SendAsset(ctx context.Context, args common.SendAssetArgs, passwd string) (common.Hash, error) {
-	sendArgs, err := s.BuildSendAssetSendTxArgs(ctx, args)
+// MakeSwap ss
+func (s *FusionTransactionAPI) MakeSwap(ctx context.Context, args common.MakeSwapArgs) (common.Hash, error) {
+	tx, err := s.BuildMakeSwapTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+	return s.sendTransaction(ctx, args.From, tx)
 }
 
-// AssetToTimeLock ss
-func (s *PrivateFusionAPI) AssetToTimeLock(ctx context.Context, args common.TimeLockArgs, passwd string) (common.Hash, error) {
-	sendArgs, err := s.BuildAssetToTimeLockSendTxArgs(ctx, args)
+// BuildRecallSwapTx ss
+func (s *FusionTransactionAPI) BuildRecallSwapTx(ctx context.Context, args common.RecallSwapArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildRecallSwapSendTxArgs(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// TimeLockToTimeLock ss
-func (s *PrivateFusionAPI) TimeLockToTimeLock(ctx context.Context, args common.TimeLockArgs, passwd string) (common.Hash, error) {
-	sendArgs, err := s.BuildTimeLockToTimeLockSendTxArgs(ctx, args)
+// RecallSwap ss
+func (s *FusionTransactionAPI) RecallSwap(ctx context.Context, args common.RecallSwapArgs) (common.Hash, error) {
+	tx, err := s.BuildRecallSwapTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+	return s.sendTransaction(ctx, args.From, tx)
 }
 
-// TimeLockToAsset ss
-func (s *PrivateFusionAPI) TimeLockToAsset(ctx context.Context, args common.TimeLockArgs, passwd string) (common.Hash, error) {
-	sendArgs, err := s.BuildTimeLockToAssetSendTxArgs(ctx, args)
+// BuildTakeSwapTx ss
+func (s *FusionTransactionAPI) BuildTakeSwapTx(ctx context.Context, args common.TakeSwapArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildTakeSwapSendTxArgs(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// SendTimeLock ss
-func (s *PrivateFusionAPI) SendTimeLock(ctx context.Context, args common.TimeLockArgs, passwd string) (common.Hash, error) {
-	sendArgs, err := s.BuildSendTimeLockSendTxArgs(ctx, args)
+// TakeSwap ss
+func (s *FusionTransactionAPI) TakeSwap(ctx context.Context, args common.TakeSwapArgs) (common.Hash, error) {
+	tx, err := s.BuildTakeSwapTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+	return s.sendTransaction(ctx, args.From, tx)
 }
 
-/** on our public gateways too many buyTickets are past through
-this cache of purchase on block will stop multiple purchase
-attempt on a block (which state_transistion also flags).
-the goals is to limit the number of buytickets being processed
-if it is know that they will fail anyway
-*/
-func doesTicketPurchaseExistsForBlock(blockNbr int64, from common.Address) bool {
-	buyTicketOnBlockMapMutex.Lock()
-	defer buyTicketOnBlockMapMutex.Unlock()
-	if lastBlockOfBuyTickets == 0 || lastBlockOfBuyTickets != blockNbr {
-		lastBlockOfBuyTickets = blockNbr
-		buyTicketOnBlockMap = make(map[common.Address]bool)
+// MakeMultiSwap wacom
+func (s *FusionTransactionAPI) MakeMultiSwap(ctx context.Context, args common.MakeMultiSwapArgs) (common.Hash, error) {
+	tx, err := s.BuildMakeMultiSwapTx(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
 	}
-	_, found := buyTicketOnBlockMap[from]
-	return found
+	return s.sendTransaction(ctx, args.From, tx)
 }
 
-// only record on purchase ticket successfully
-func addTicketPurchaseForBlock(from common.Address) {
-	buyTicketOnBlockMapMutex.Lock()
-	defer buyTicketOnBlockMapMutex.Unlock()
-	buyTicketOnBlockMap[from] = true
+// BuildMakeMultiSwapTx ss
+func (s *FusionTransactionAPI) BuildMakeMultiSwapTx(ctx context.Context, args common.MakeMultiSwapArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildMakeMultiSwapSendTxArgs(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// BuyTicket ss
-func (s *PrivateFusionAPI) BuyTicket(ctx context.Context, args common.BuyTicketArgs, passwd string) (common.Hash, error) {
-	sendArgs, err := s.BuildBuyTicketSendTxArgs(ctx, args)
+// RecallMultiSwap wacom
+func (s *FusionTransactionAPI) RecallMultiSwap(ctx context.Context, args common.RecallMultiSwapArgs) (common.Hash, error) {
+	tx, err := s.BuildRecallMultiSwapTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	hash, err := s.papi.SendTransaction(ctx, *sendArgs, passwd)
+	return s.sendTransaction(ctx, args.From, tx)
+}
+
+// BuildRecallMultiSwapTx ss
+func (s *FusionTransactionAPI) BuildRecallMultiSwapTx(ctx context.Context, args common.RecallMultiSwapArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildRecallMultiSwapSendTxArgs(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	addTicketPurchaseForBlock(args.From)
-	return hash, err
+	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// IncAsset ss
-func (s *PrivateFusionAPI) IncAsset(ctx context.Context, args common.AssetValueChangeExArgs, passwd string) (common.Hash, error) {
-	args.IsInc = true
-	sendArgs, err := s.BuildAssetValueChangeSendTxArgs(ctx, args)
+// TakeMultiSwap wacom
+func (s *FusionTransactionAPI) TakeMultiSwap(ctx context.Context, args common.TakeMultiSwapArgs) (common.Hash, error) {
+	tx, err := s.BuildTakeMultiSwapTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+	return s.sendTransaction(ctx, args.From, tx)
 }
 
-// DecAsset ss
-func (s *PrivateFusionAPI) DecAsset(ctx context.Context, args common.AssetValueChangeExArgs, passwd string) (common.Hash, error) {
-	args.IsInc = false
-	sendArgs, err := s.BuildAssetValueChangeSendTxArgs(ctx, args)
+// BuildTakeSwapTx ss
+func (s *FusionTransactionAPI) BuildTakeMultiSwapTx(ctx context.Context, args common.TakeMultiSwapArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildTakeMultiSwapSendTxArgs(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// MakeSwap ss
-func (s *PrivateFusionAPI) MakeSwap(ctx context.Context, args common.MakeSwapArgs, passwd string) (common.Hash, error) {
-	sendArgs, err := s.BuildMakeSwapSendTxArgs(ctx, args)
+// BuildTypedCallTx ss
+func (s *FusionTransactionAPI) BuildTypedCallTx(ctx context.Context, args common.TypedCallArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildTypedCallSendTxArgs(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// RecallSwap ss
-func (s *PrivateFusionAPI) RecallSwap(ctx context.Context, args common.RecallSwapArgs, passwd string) (common.Hash, error) {
-	sendArgs, err := s.BuildRecallSwapSendTxArgs(ctx, args)
+// TypedCall ss
+func (s *FusionTransactionAPI) TypedCall(ctx context.Context, args common.TypedCallArgs) (common.Hash, error) {
+	tx, err := s.BuildTypedCallTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+	return s.sendTransaction(ctx, args.From, tx)
 }
 
-// TakeSwap ss
-func (s *PrivateFusionAPI) TakeSwap(ctx context.Context, args common.TakeSwapArgs, passwd string) (common.Hash, error) {
-	sendArgs, err := s.BuildTakeSwapSendTxArgs(ctx, args)
+// BuildAssetTransferListTx ss
+func (s *FusionTransactionAPI) BuildAssetTransferListTx(ctx context.Context, args common.AssetTransferListArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildAssetTransferListSendTxArgs(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// MakeMultiSwap ss
-func (s *PrivateFusionAPI) MakeMultiSwap(ctx context.Context, args common.MakeMultiSwapArgs, passwd string) (common.Hash, error) {
-	sendArgs, err := s.BuildMakeMultiSwapSendTxArgs(ctx, args)
+// SetAssetTransferList ss
+func (s *FusionTransactionAPI) SetAssetTransferList(ctx context.Context, args common.AssetTransferListArgs) (common.Hash, error) {
+	tx, err := s.BuildAssetTransferListTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+	return s.sendTransaction(ctx, args.From, tx)
 }
 
-// RecallMultiSwap ss
-func (s *PrivateFusionAPI) RecallMultiSwap(ctx context.Context, args common.RecallMultiSwapArgs, passwd string) (common.Hash, error) {
-	sendArgs, err := s.BuildRecallMultiSwapSendTxArgs(ctx, args)
+// BuildSetFsnCallFeeTx ss
+func (s *FusionTransactionAPI) BuildSetFsnCallFeeTx(ctx context.Context, args common.SetFsnCallFeeArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildSetFsnCallFeeSendTxArgs(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// TakeMultiSwap ss
-func (s *PrivateFusionAPI) TakeMultiSwap(ctx context.Context, args common.TakeMultiSwapArgs, passwd string) (common.Hash, error) {
-	sendArgs, err := s.BuildTakeMultiSwapSendTxArgs(ctx, args)
+// SetFsnCallFee ss
+func (s *FusionTransactionAPI) SetFsnCallFee(ctx context.Context, args common.SetFsnCallFeeArgs) (common.Hash, error) {
+	tx, err := s.BuildSetFsnCallFeeTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return s.papi.SendTransaction(ctx, *sendArgs, passwd)
+	return s.sendTransaction(ctx, args.From, tx)
 }
 
-//--------------------------------------------- FusionTransactionAPI -------------------------------------
-
-// FusionTransactionAPI ss
-type FusionTransactionAPI struct {
-	b         Backend
-	pubapi    *PublicFusionAPI
-	nonceLock *AddrLocker
-	txapi     *PublicTransactionPoolAPI
+// BuildCreateProposalTx ss
+func (s *FusionTransactionAPI) BuildCreateProposalTx(ctx context.Context, args common.CreateProposalArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildCreateProposalSendTxArgs(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildTransaction(ctx, *sendArgs)
 }
 
-var fusionTransactionAPI *FusionTransactionAPI
+// CreateProposal ss
+func (s *FusionTransactionAPI) CreateProposal(ctx context.Context, args common.CreateProposalArgs) (common.Hash, error) {
+	tx, err := s.BuildCreateProposalTx(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.sendTransaction(ctx, args.From, tx)
+}
 
-// NewFusionTransactionAPI ss
-func NewFusionTransactionAPI(b Backend, nonceLock *AddrLocker, txapi *PublicTransactionPoolAPI) *FusionTransactionAPI {
-	fusionTransactionAPI = &FusionTransactionAPI{
-		b:         b,
-		pubapi:    NewPublicFusionAPI(b),
-		nonceLock: nonceLock,
-		txapi:     txapi,
+// BuildVoteProposalTx ss
+func (s *FusionTransactionAPI) BuildVoteProposalTx(ctx context.Context, args common.VoteProposalArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildVoteProposalSendTxArgs(ctx, args)
+	if err != nil {
+		return nil, err
 	}
-	return fusionTransactionAPI
+	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// auto buy ticket
-func AutoBuyTicket(enable bool) {
-	if enable {
-		_, err := fusionTransactionAPI.b.Coinbase()
-		if err != nil {
-			log.Warn("AutoBuyTicket not enabled as no coinbase account exist")
-			enable = false
-		}
+// VoteProposal ss
+func (s *FusionTransactionAPI) VoteProposal(ctx context.Context, args common.VoteProposalArgs) (common.Hash, error) {
+	tx, err := s.BuildVoteProposalTx(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
 	}
-	common.AutoBuyTicket = enable
+	return s.sendTransaction(ctx, args.From, tx)
+}
 
-	for {
-		<-common.AutoBuyTicketChan
-	COMSUMEALL:
-		for {
-			select {
-			case <-common.AutoBuyTicketChan:
-			default:
-				break COMSUMEALL
-			}
-		}
+// BuildRevokeTicketTx ss
+func (s *FusionTransactionAPI) BuildRevokeTicketTx(ctx context.Context, args common.RevokeTicketArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildRevokeTicketSendTxArgs(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildTransaction(ctx, *sendArgs)
+}
 
-		// prevent auto buy ticket in syncing
-		if !fusionTransactionAPI.b.IsMining() {
-			common.DebugInfo("ignore AutoBuyTicket as isMining is false")
-			continue
-		}
+// RevokeTicket ss
+func (s *FusionTransactionAPI) RevokeTicket(ctx context.Context, args common.RevokeTicketArgs) (common.Hash, error) {
+	tx, err := s.BuildRevokeTicketTx(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return s.sendTransaction(ctx, args.From, tx)
+}
 
-		coinbase, err := fusionTransactionAPI.b.Coinbase()
-		if err == nil {
-			fbase := common.FusionBaseArgs{From: coinbase}
-			args := common.BuyTicketArgs{FusionBaseArgs: fbase}
-			fusionTransactionAPI.BuyTicket(context.TODO(), args)
-		}
+// BuildEscrowAssetTx ss
+func (s *FusionTransactionAPI) BuildEscrowAssetTx(ctx context.Context, args common.EscrowAssetArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildEscrowAssetSendTxArgs(ctx, args)
+	if err != nil {
+		return nil, err
 	}
+	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// report illegal
-func ReportIllegal() {
-	for {
-		select {
-		case content := <-common.ReportIllegalChan:
-			coinbase, err := fusionTransactionAPI.b.Coinbase()
-			if err == nil {
-				args := common.FusionBaseArgs{From: coinbase}
-				fusionTransactionAPI.ReportIllegal(context.TODO(), args, content)
-			}
-		}
+// EscrowAsset ss
+func (s *FusionTransactionAPI) EscrowAsset(ctx context.Context, args common.EscrowAssetArgs) (common.Hash, error) {
+	tx, err := s.BuildEscrowAssetTx(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
 	}
+	return s.sendTransaction(ctx, args.From, tx)
 }
 
-func (s *FusionTransactionAPI) ReportIllegal(ctx context.Context, args common.FusionBaseArgs, content []byte) (common.Hash, error) {
-	oldtx := s.b.GetPoolTransactionByPredicate(func(tx *types.Transaction) bool {
-		param := common.FSNCallParam{}
-		rlp.DecodeBytes(tx.Data(), &param)
-		return param.Func == common.ReportIllegalFunc && bytes.Equal(param.Data, content)
-	})
-	if oldtx != nil {
-		return common.Hash{}, fmt.Errorf("ReportIllegal: already reported in txpool")
+// BuildClaimEscrowTx ss
+func (s *FusionTransactionAPI) BuildClaimEscrowTx(ctx context.Context, args common.ClaimEscrowArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildClaimEscrowSendTxArgs(ctx, args)
+	if err != nil {
+		return nil, err
 	}
-	sendArgs, err := FSNCallArgsToSendTxArgs(&args, common.ReportIllegalFunc, content)
+	return s.buildTransaction(ctx, *sendArgs)
+}
+
+// ClaimEscrow ss
+func (s *FusionTransactionAPI) ClaimEscrow(ctx context.Context, args common.ClaimEscrowArgs) (common.Hash, error) {
+	tx, err := s.BuildClaimEscrowTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	tx, err := s.buildTransaction(ctx, *sendArgs)
+	return s.sendTransaction(ctx, args.From, tx)
+}
+
+// BuildCreateStreamTx ss
+func (s *FusionTransactionAPI) BuildCreateStreamTx(ctx context.Context, args common.CreateStreamArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildCreateStreamSendTxArgs(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildTransaction(ctx, *sendArgs)
+}
+
+// CreateStream ss
+func (s *FusionTransactionAPI) CreateStream(ctx context.Context, args common.CreateStreamArgs) (common.Hash, error) {
+	tx, err := s.BuildCreateStreamTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
 	return s.sendTransaction(ctx, args.From, tx)
 }
 
-func (s *FusionTransactionAPI) buildTransaction(ctx context.Context, args SendTxArgs) (*types.Transaction, error) {
-	if args.Nonce == nil {
-		s.nonceLock.LockAddr(args.From)
-		defer s.nonceLock.UnlockAddr(args.From)
-	}
-	if err := args.setDefaults(ctx, s.b); err != nil {
+// BuildWithdrawStreamTx ss
+func (s *FusionTransactionAPI) BuildWithdrawStreamTx(ctx context.Context, args common.WithdrawStreamArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildWithdrawStreamSendTxArgs(ctx, args)
+	if err != nil {
 		return nil, err
 	}
-	tx := args.toTransaction()
-	return tx, nil
+	return s.buildTransaction(ctx, *sendArgs)
 }
 
-func (s *FusionTransactionAPI) sendTransaction(ctx context.Context, from common.Address, tx *types.Transaction) (common.Hash, error) {
-	account := accounts.Account{Address: from}
-	wallet, err := s.b.AccountManager().Find(account)
+// WithdrawStream ss
+func (s *FusionTransactionAPI) WithdrawStream(ctx context.Context, args common.WithdrawStreamArgs) (common.Hash, error) {
+	tx, err := s.BuildWithdrawStreamTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	var chainID *big.Int
-	if config := s.b.ChainConfig(); config.IsEIP155(s.b.CurrentBlock().Number()) {
-		chainID = config.ChainID
-	}
-	signed, err := wallet.SignTx(account, tx, chainID)
+	return s.sendTransaction(ctx, args.From, tx)
+}
+
+// BuildCreateConditionTx ss
+func (s *FusionTransactionAPI) BuildCreateConditionTx(ctx context.Context, args common.CreateConditionArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildCreateConditionSendTxArgs(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	return s.SendRawTransaction(ctx, signed)
+	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// SendRawTransaction wacom
-func (s *FusionTransactionAPI) SendRawTransaction(ctx context.Context, tx *types.Transaction) (common.Hash, error) {
-	encodedTx, err := rlp.EncodeToBytes(tx)
+// CreateCondition ss
+func (s *FusionTransactionAPI) CreateCondition(ctx context.Context, args common.CreateConditionArgs) (common.Hash, error) {
+	tx, err := s.BuildCreateConditionTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return s.txapi.SendRawTransaction(ctx, encodedTx)
+	return s.sendTransaction(ctx, args.From, tx)
 }
 
-// BuildGenNotationTx ss
-func (s *FusionTransactionAPI) BuildGenNotationTx(ctx context.Context, args common.FusionBaseArgs) (*types.Transaction, error) {
-	sendArgs, err := s.pubapi.BuildGenNotationSendTxArgs(ctx, args)
+// BuildResolveConditionTx ss
+func (s *FusionTransactionAPI) BuildResolveConditionTx(ctx context.Context, args common.ResolveConditionArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildResolveConditionSendTxArgs(ctx, args)
 	if err != nil {
 		return nil, err
 	}
 	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// GenNotation ss
-func (s *FusionTransactionAPI) GenNotation(ctx context.Context, args common.FusionBaseArgs) (common.Hash, error) {
-	tx, err := s.BuildGenNotationTx(ctx, args)
+// ResolveCondition ss
+func (s *FusionTransactionAPI) ResolveCondition(ctx context.Context, args common.ResolveConditionArgs) (common.Hash, error) {
+	tx, err := s.BuildResolveConditionTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
 	return s.sendTransaction(ctx, args.From, tx)
 }
 
-// BuildGenAssetTx ss
-func (s *FusionTransactionAPI) BuildGenAssetTx(ctx context.Context, args common.GenAssetArgs) (*types.Transaction, error) {
-	sendArgs, err := s.pubapi.BuildGenAssetSendTxArgs(ctx, args)
+// BuildConditionalTransferTx ss
+func (s *FusionTransactionAPI) BuildConditionalTransferTx(ctx context.Context, args common.ConditionalTransferArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildConditionalTransferSendTxArgs(ctx, args)
 	if err != nil {
 		return nil, err
 	}
 	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// GenAsset ss
-func (s *FusionTransactionAPI) GenAsset(ctx context.Context, args common.GenAssetArgs) (common.Hash, error) {
-	tx, err := s.BuildGenAssetTx(ctx, args)
+// ConditionalTransfer ss
+func (s *FusionTransactionAPI) ConditionalTransfer(ctx context.Context, args common.ConditionalTransferArgs) (common.Hash, error) {
+	tx, err := s.BuildConditionalTransferTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
 	return s.sendTransaction(ctx, args.From, tx)
 }
 
-// BuildSendAssetTx ss
-func (s *FusionTransactionAPI) BuildSendAssetTx(ctx context.Context, args common.SendAssetArgs) (*types.Transaction, error) {
-	sendArgs, err := s.pubapi.BuildSendAssetSendTxArgs(ctx, args)
+// BuildSettleConditionalTx ss
+func (s *FusionTransactionAPI) BuildSettleConditionalTx(ctx context.Context, args common.SettleConditionalArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildSettleConditionalSendTxArgs(ctx, args)
 	if err != nil {
 		return nil, err
 	}
 	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// SendAsset ss
-func (s *FusionTransactionAPI) SendAsset(ctx context.Context, args common.SendAssetArgs) (common.Hash, error) {
-	tx, err := s.BuildSendAssetTx(ctx, args)
+// SettleConditional ss
+func (s *FusionTransactionAPI) SettleConditional(ctx context.Context, args common.SettleConditionalArgs) (common.Hash, error) {
+	tx, err := s.BuildSettleConditionalTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
 	return s.sendTransaction(ctx, args.From, tx)
 }
 
-// BuildAssetToTimeLockTx ss
-func (s *FusionTransactionAPI) BuildAssetToTimeLockTx(ctx context.Context, args common.TimeLockArgs) (*types.Transaction, error) {
-	sendArgs, err := s.pubapi.BuildAssetToTimeLockSendTxArgs(ctx, args)
+// BuildAttestDepositTx ss
+func (s *FusionTransactionAPI) BuildAttestDepositTx(ctx context.Context, args common.AttestDepositArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildAttestDepositSendTxArgs(ctx, args)
 	if err != nil {
 		return nil, err
 	}
 	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// AssetToTimeLock ss
-func (s *FusionTransactionAPI) AssetToTimeLock(ctx context.Context, args common.TimeLockArgs) (common.Hash, error) {
-	tx, err := s.BuildAssetToTimeLockTx(ctx, args)
+// AttestDeposit ss
+func (s *FusionTransactionAPI) AttestDeposit(ctx context.Context, args common.AttestDepositArgs) (common.Hash, error) {
+	tx, err := s.BuildAttestDepositTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
 	return s.sendTransaction(ctx, args.From, tx)
 }
 
-// BuildTimeLockToTimeLockTx ss
-func (s *FusionTransactionAPI) BuildTimeLockToTimeLockTx(ctx context.Context, args common.TimeLockArgs) (*types.Transaction, error) {
-	sendArgs, err := s.pubapi.BuildTimeLockToTimeLockSendTxArgs(ctx, args)
+// BuildBridgeWithdrawTx ss
+func (s *FusionTransactionAPI) BuildBridgeWithdrawTx(ctx context.Context, args common.BridgeWithdrawArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildBridgeWithdrawSendTxArgs(ctx, args)
 	if err != nil {
 		return nil, err
 	}
 	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// TimeLockToTimeLock ss
-func (s *FusionTransactionAPI) TimeLockToTimeLock(ctx context.Context, args common.TimeLockArgs) (common.Hash, error) {
-	tx, err := s.BuildTimeLockToTimeLockTx(ctx, args)
+// BridgeWithdraw ss
+func (s *FusionTransactionAPI) BridgeWithdraw(ctx context.Context, args common.BridgeWithdrawArgs) (common.Hash, error) {
+	tx, err := s.BuildBridgeWithdrawTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
 	return s.sendTransaction(ctx, args.From, tx)
 }
 
-// BuildTimeLockToAssetTx ss
-func (s *FusionTransactionAPI) BuildTimeLockToAssetTx(ctx context.Context, args common.TimeLockArgs) (*types.Transaction, error) {
-	sendArgs, err := s.pubapi.BuildTimeLockToAssetSendTxArgs(ctx, args)
+// BuildStakingKeyTx ss
+func (s *FusionTransactionAPI) BuildStakingKeyTx(ctx context.Context, args common.StakingKeyArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildStakingKeySendTxArgs(ctx, args)
 	if err != nil {
 		return nil, err
 	}
 	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// TimeLockToAsset ss
-func (s *FusionTransactionAPI) TimeLockToAsset(ctx context.Context, args common.TimeLockArgs) (common.Hash, error) {
-	tx, err := s.BuildTimeLockToAssetTx(ctx, args)
+// SetStakingKey ss
+func (s *FusionTransactionAPI) SetStakingKey(ctx context.Context, args common.StakingKeyArgs) (common.Hash, error) {
+	tx, err := s.BuildStakingKeyTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
 	return s.sendTransaction(ctx, args.From, tx)
 }
 
-// BuildSendTimeLockTx ss
-func (s *FusionTransactionAPI) BuildSendTimeLockTx(ctx context.Context, args common.TimeLockArgs) (*types.Transaction, error) {
-	sendArgs, err := s.pubapi.BuildSendTimeLockSendTxArgs(ctx, args)
+// BuildStakingBuyTicketTx ss
+func (s *FusionTransactionAPI) BuildStakingBuyTicketTx(ctx context.Context, args common.StakingBuyTicketArgs) (*types.Transaction, error) {
+	sendArgs, err := s.pubapi.BuildStakingBuyTicketSendTxArgs(ctx, args)
 	if err != nil {
 		return nil, err
 	}
 	return s.buildTransaction(ctx, *sendArgs)
 }
 
-// SendTimeLock ss
-func (s *FusionTransactionAPI) SendTimeLock(ctx context.Context, args common.TimeLockArgs) (common.Hash, error) {
-	tx, err := s.BuildSendTimeLockTx(ctx, args)
+// StakingBuyTicket ss
+func (s *FusionTransactionAPI) StakingBuyTicket(ctx context.Context, args common.StakingBuyTicketArgs) (common.Hash, error) {
+	tx, err := s.BuildStakingBuyTicketTx(ctx, args)
 	if err != nil {
 		return common.Hash{}, err
 	}
 	return s.sendTransaction(ctx, args.From, tx)
 }
 
-// BuildBuyTicketTx ss
-func (s *FusionTransactionAPI) BuildBuyTicketTx(ctx context.Context, args common.BuyTicketArgs) (*types.Transaction, error) {
-	sendArgs, err := s.pubapi.BuildBuyTicketSendTxArgs(ctx, args)
+//--------------------------------------------- FusionTransactionAPI sign tx -------------------------------------
+// The Sign*Tx methods build and sign FSN call transactions without sending
+// them, returning the RLP encoded transaction for later submission.
+
+// SignGenNotationTx ss
+func (s *FusionTransactionAPI) SignGenNotationTx(ctx context.Context, args common.FusionBaseArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildGenNotationTx(ctx, args)
 	if err != nil {
 		return nil, err
 	}
-	return s.buildTransaction(ctx, *sendArgs)
+	return s.signTransactionResult(ctx, args.From, tx)
 }
 
-// BuyTicket ss
-func (s *FusionTransactionAPI) BuyTicket(ctx context.Context, args common.BuyTicketArgs) (common.Hash, error) {
-	tx, err := s.BuildBuyTicketTx(ctx, args)
+// SignGenAssetTx ss
+func (s *FusionTransactionAPI) SignGenAssetTx(ctx context.Context, args common.GenAssetArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildGenAssetTx(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	hash, err := s.sendTransaction(ctx, args.From, tx)
+	return s.signTransactionResult(ctx, args.From, tx)
+}
+
+// SignSendAssetTx ss
+func (s *FusionTransactionAPI) SignSendAssetTx(ctx context.Context, args common.SendAssetArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildSendAssetTx(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	addTicketPurchaseForBlock(args.From)
-	return hash, err
+	return s.signTransactionResult(ctx, args.From, tx)
 }
 
-// BuildIncAssetTx ss
-func (s *FusionTransactionAPI) BuildIncAssetTx(ctx context.Context, args common.AssetValueChangeExArgs) (*types.Transaction, error) {
-	args.IsInc = true
-	sendArgs, err := s.pubapi.BuildAssetValueChangeSendTxArgs(ctx, args)
+// SignAssetToTimeLockTx ss
+func (s *FusionTransactionAPI) SignAssetToTimeLockTx(ctx context.Context, args common.TimeLockArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildAssetToTimeLockTx(ctx, args)
 	if err != nil {
 		return nil, err
 	}
-	return s.buildTransaction(ctx, *sendArgs)
+	return s.signTransactionResult(ctx, args.From, tx)
 }
 
-// IncAsset ss
-func (s *FusionTransactionAPI) IncAsset(ctx context.Context, args common.AssetValueChangeExArgs) (common.Hash, error) {
-	tx, err := s.BuildIncAssetTx(ctx, args)
+// SignTimeLockToTimeLockTx ss
+func (s *FusionTransactionAPI) SignTimeLockToTimeLockTx(ctx context.Context, args common.TimeLockArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildTimeLockToTimeLockTx(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	return s.sendTransaction(ctx, args.From, tx)
+	return s.signTransactionResult(ctx, args.From, tx)
 }
 
-// BuildDecAssetTx ss
-func (s *FusionTransactionAPI) BuildDecAssetTx(ctx context.Context, args common.AssetValueChangeExArgs) (*types.Transaction, error) {
-	args.IsInc = false
-	sendArgs, err := s.pubapi.BuildAssetValueChangeSendTxArgs(ctx, args)
+// SignTimeLockToAssetTx ss
+func (s *FusionTransactionAPI) SignTimeLockToAssetTx(ctx context.Context, args common.TimeLockArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildTimeLockToAssetTx(ctx, args)
 	if err != nil {
 		return nil, err
 	}
-	return s.buildTransaction(ctx, *sendArgs)
+	return s.signTransactionResult(ctx, args.From, tx)
 }
 
-// DecAsset ss
-func (s *FusionTransactionAPI) DecAsset(ctx context.Context, args common.AssetValueChangeExArgs) (common.Hash, error) {
-	tx, err := s.BuildDecAssetTx(ctx, args)
+// SignSendTimeLockTx ss
+func (s *FusionTransactionAPI) SignSendTimeLockTx(ctx context.Context, args common.TimeLockArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildSendTimeLockTx(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	return s.sendTransaction(ctx, args.From, tx)
+	return s.signTransactionResult(ctx, args.From, tx)
 }
 
-// BuildMakeSwapTx ss
-func (s *FusionTransactionAPI) BuildMakeSwapTx(ctx context.Context, args common.MakeSwapArgs) (*types.Transaction, error) {
-	sendArgs, err := s.pubapi.BuildMakeSwapSendTxArgs(ctx, args)
+// SignBuyTicketTx ss
+func (s *FusionTransactionAPI) SignBuyTicketTx(ctx context.Context, args common.BuyTicketArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildBuyTicketTx(ctx, args)
 	if err != nil {
 		return nil, err
 	}
-	return s.buildTransaction(ctx, *sendArgs)
+	return s.signTransactionResult(ctx, args.From, tx)
 }
 
-// MakeSwap ss
-func (s *FusionTransactionAPI) MakeSwap(ctx context.Context, args common.MakeSwapArgs) (common.Hash, error) {
-	tx, err := s.BuildMakeSwapTx(ctx, args)
+// SignIncAssetTx ss
+func (s *FusionTransactionAPI) SignIncAssetTx(ctx context.Context, args common.AssetValueChangeExArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildIncAssetTx(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	return s.sendTransaction(ctx, args.From, tx)
+	return s.signTransactionResult(ctx, args.From, tx)
 }
 
-// BuildRecallSwapTx ss
-func (s *FusionTransactionAPI) BuildRecallSwapTx(ctx context.Context, args common.RecallSwapArgs) (*types.Transaction, error) {
-	sendArgs, err := s.pubapi.BuildRecallSwapSendTxArgs(ctx, args)
+// SignDecAssetTx ss
+func (s *FusionTransactionAPI) SignDecAssetTx(ctx context.Context, args common.AssetValueChangeExArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildDecAssetTx(ctx, args)
 	if err != nil {
 		return nil, err
 	}
-	return s.buildTransaction(ctx, *sendArgs)
+	return s.signTransactionResult(ctx, args.From, tx)
 }
 
-// RecallSwap ss
-func (s *FusionTransactionAPI) RecallSwap(ctx context.Context, args common.RecallSwapArgs) (common.Hash, error) {
-	tx, err := s.BuildRecallSwapTx(ctx, args)
+// SignMakeSwapTx ss
+func (s *FusionTransactionAPI) SignMakeSwapTx(ctx context.Context, args common.MakeSwapArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildMakeSwapTx(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	return s.sendTransaction(ctx, args.From, tx)
+	return s.signTransactionResult(ctx, args.From, tx)
 }
 
-// BuildTakeSwapTx ss
-func (s *FusionTransactionAPI) BuildTakeSwapTx(ctx context.Context, args common.TakeSwapArgs) (*types.Transaction, error) {
-	sendArgs, err := s.pubapi.BuildTakeSwapSendTxArgs(ctx, args)
+// SignRecallSwapTx ss
+func (s *FusionTransactionAPI) SignRecallSwapTx(ctx context.Context, args common.RecallSwapArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildRecallSwapTx(ctx, args)
 	if err != nil {
 		return nil, err
 	}
-	return s.buildTransaction(ctx, *sendArgs)
+	return s.signTransactionResult(ctx, args.From, tx)
 }
 
-// TakeSwap ss
-func (s *FusionTransactionAPI) TakeSwap(ctx context.Context, args common.TakeSwapArgs) (common.Hash, error) {
+// SignTakeSwapTx ss
+func (s *FusionTransactionAPI) SignTakeSwapTx(ctx context.Context, args common.TakeSwapArgs) (*SignTransactionResult, error) {
 	tx, err := s.BuildTakeSwapTx(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	return s.sendTransaction(ctx, args.From, tx)
+	return s.signTransactionResult(ctx, args.From, tx)
 }
 
-// MakeMultiSwap wacom
-func (s *FusionTransactionAPI) MakeMultiSwap(ctx context.Context, args common.MakeMultiSwapArgs) (common.Hash, error) {
+// SignMakeMultiSwapTx ss
+func (s *FusionTransactionAPI) SignMakeMultiSwapTx(ctx context.Context, args common.MakeMultiSwapArgs) (*SignTransactionResult, error) {
 	tx, err := s.BuildMakeMultiSwapTx(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	return s.sendTransaction(ctx, args.From, tx)
+	return s.signTransactionResult(ctx, args.From, tx)
 }
 
-// BuildMakeMultiSwapTx ss
-func (s *FusionTransactionAPI) BuildMakeMultiSwapTx(ctx context.Context, args common.MakeMultiSwapArgs) (*types.Transaction, error) {
-	sendArgs, err := s.pubapi.BuildMakeMultiSwapSendTxArgs(ctx, args)
+// SignRecallMultiSwapTx ss
+func (s *FusionTransactionAPI) SignRecallMultiSwapTx(ctx context.Context, args common.RecallMultiSwapArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildRecallMultiSwapTx(ctx, args)
 	if err != nil {
 		return nil, err
 	}
-	return s.buildTransaction(ctx, *sendArgs)
+	return s.signTransactionResult(ctx, args.From, tx)
 }
 
-// RecallMultiSwap wacom
-func (s *FusionTransactionAPI) RecallMultiSwap(ctx context.Context, args common.RecallMultiSwapArgs) (common.Hash, error) {
-	tx, err := s.BuildRecallMultiSwapTx(ctx, args)
+// SignTakeMultiSwapTx ss
+func (s *FusionTransactionAPI) SignTakeMultiSwapTx(ctx context.Context, args common.TakeMultiSwapArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildTakeMultiSwapTx(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	return s.sendTransaction(ctx, args.From, tx)
+	return s.signTransactionResult(ctx, args.From, tx)
 }
 
-// BuildRecallMultiSwapTx ss
-func (s *FusionTransactionAPI) BuildRecallMultiSwapTx(ctx context.Context, args common.RecallMultiSwapArgs) (*types.Transaction, error) {
-	sendArgs, err := s.pubapi.BuildRecallMultiSwapSendTxArgs(ctx, args)
+// SignTypedCallTx ss
+func (s *FusionTransactionAPI) SignTypedCallTx(ctx context.Context, args common.TypedCallArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildTypedCallTx(ctx, args)
 	if err != nil {
 		return nil, err
 	}
-	return s.buildTransaction(ctx, *sendArgs)
+	return s.signTransactionResult(ctx, args.From, tx)
 }
 
-// TakeMultiSwap wacom
-func (s *FusionTransactionAPI) TakeMultiSwap(ctx context.Context, args common.TakeMultiSwapArgs) (common.Hash, error) {
-	tx, err := s.BuildTakeMultiSwapTx(ctx, args)
+// SignAssetTransferListTx ss
+func (s *FusionTransactionAPI) SignAssetTransferListTx(ctx context.Context, args common.AssetTransferListArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildAssetTransferListTx(ctx, args)
 	if err != nil {
-		return common.Hash{}, err
+		return nil, err
 	}
-	return s.sendTransaction(ctx, args.From, tx)
+	return s.signTransactionResult(ctx, args.From, tx)
 }
 
-// BuildTakeSwapTx ss
-func (s *FusionTransactionAPI) BuildTakeMultiSwapTx(ctx context.Context, args common.TakeMultiSwapArgs) (*types.Transaction, error) {
-	sendArgs, err := s.pubapi.BuildTakeMultiSwapSendTxArgs(ctx, args)
+// SignSetFsnCallFeeTx ss
+func (s *FusionTransactionAPI) SignSetFsnCallFeeTx(ctx context.Context, args common.SetFsnCallFeeArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildSetFsnCallFeeTx(ctx, args)
 	if err != nil {
 		return nil, err
 	}
-	return s.buildTransaction(ctx, *sendArgs)
+	return s.signTransactionResult(ctx, args.From, tx)
+}
+
+// SignCreateProposalTx ss
+func (s *FusionTransactionAPI) SignCreateProposalTx(ctx context.Context, args common.CreateProposalArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildCreateProposalTx(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.signTransactionResult(ctx, args.From, tx)
+}
+
+// SignVoteProposalTx ss
+func (s *FusionTransactionAPI) SignVoteProposalTx(ctx context.Context, args common.VoteProposalArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildVoteProposalTx(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.signTransactionResult(ctx, args.From, tx)
+}
+
+// SignRevokeTicketTx ss
+func (s *FusionTransactionAPI) SignRevokeTicketTx(ctx context.Context, args common.RevokeTicketArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildRevokeTicketTx(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.signTransactionResult(ctx, args.From, tx)
+}
+
+// SignEscrowAssetTx ss
+func (s *FusionTransactionAPI) SignEscrowAssetTx(ctx context.Context, args common.EscrowAssetArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildEscrowAssetTx(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.signTransactionResult(ctx, args.From, tx)
+}
+
+// SignClaimEscrowTx ss
+func (s *FusionTransactionAPI) SignClaimEscrowTx(ctx context.Context, args common.ClaimEscrowArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildClaimEscrowTx(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.signTransactionResult(ctx, args.From, tx)
+}
+
+// SignCreateStreamTx ss
+func (s *FusionTransactionAPI) SignCreateStreamTx(ctx context.Context, args common.CreateStreamArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildCreateStreamTx(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.signTransactionResult(ctx, args.From, tx)
+}
+
+// SignWithdrawStreamTx ss
+func (s *FusionTransactionAPI) SignWithdrawStreamTx(ctx context.Context, args common.WithdrawStreamArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildWithdrawStreamTx(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.signTransactionResult(ctx, args.From, tx)
+}
+
+// SignCreateConditionTx ss
+func (s *FusionTransactionAPI) SignCreateConditionTx(ctx context.Context, args common.CreateConditionArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildCreateConditionTx(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.signTransactionResult(ctx, args.From, tx)
+}
+
+// SignResolveConditionTx ss
+func (s *FusionTransactionAPI) SignResolveConditionTx(ctx context.Context, args common.ResolveConditionArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildResolveConditionTx(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.signTransactionResult(ctx, args.From, tx)
+}
+
+// SignConditionalTransferTx ss
+func (s *FusionTransactionAPI) SignConditionalTransferTx(ctx context.Context, args common.ConditionalTransferArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildConditionalTransferTx(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.signTransactionResult(ctx, args.From, tx)
+}
+
+// SignSettleConditionalTx ss
+func (s *FusionTransactionAPI) SignSettleConditionalTx(ctx context.Context, args common.SettleConditionalArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildSettleConditionalTx(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.signTransactionResult(ctx, args.From, tx)
+}
+
+// SignAttestDepositTx ss
+func (s *FusionTransactionAPI) SignAttestDepositTx(ctx context.Context, args common.AttestDepositArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildAttestDepositTx(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.signTransactionResult(ctx, args.From, tx)
+}
+
+// SignBridgeWithdrawTx ss
+func (s *FusionTransactionAPI) SignBridgeWithdrawTx(ctx context.Context, args common.BridgeWithdrawArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildBridgeWithdrawTx(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.signTransactionResult(ctx, args.From, tx)
+}
+
+// SignStakingKeyTx ss
+func (s *FusionTransactionAPI) SignStakingKeyTx(ctx context.Context, args common.StakingKeyArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildStakingKeyTx(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.signTransactionResult(ctx, args.From, tx)
+}
+
+// SignStakingBuyTicketTx ss
+func (s *FusionTransactionAPI) SignStakingBuyTicketTx(ctx context.Context, args common.StakingBuyTicketArgs) (*SignTransactionResult, error) {
+	tx, err := s.BuildStakingBuyTicketTx(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return s.signTransactionResult(ctx, args.From, tx)
+}
+
+// SignReportIllegalTx ss
+func (s *FusionTransactionAPI) SignReportIllegalTx(ctx context.Context, args common.FusionBaseArgs, content hexutil.Bytes) (*SignTransactionResult, error) {
+	tx, err := s.BuildReportIllegalTx(ctx, args, content)
+	if err != nil {
+		return nil, err
+	}
+	return s.signTransactionResult(ctx, args.From, tx)
 }
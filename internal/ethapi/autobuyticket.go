@@ -0,0 +1,193 @@
+package ethapi
+
+import (
+	"context"
+	"sync"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/log"
+	"github.com/FusionFoundation/go-fusion/metrics"
+	"github.com/FusionFoundation/go-fusion/rpc"
+)
+
+const (
+	// autoBuyTicketMaxBackoff is the maximum number of blocks the auto ticket
+	// purchaser waits after consecutive failed purchases
+	autoBuyTicketMaxBackoff = 256
+)
+
+var (
+	autoBuyTicketBoughtMeter  = metrics.NewRegisteredMeter("fsn/autobt/bought", nil)
+	autoBuyTicketFailedMeter  = metrics.NewRegisteredMeter("fsn/autobt/failed", nil)
+	autoBuyTicketTicketsGauge = metrics.NewRegisteredGauge("fsn/autobt/tickets", nil)
+)
+
+// AutoBuyTicketStatus is the state of the auto ticket purchaser
+type AutoBuyTicketStatus struct {
+	Enabled   bool           `json:"enabled"`
+	Target    uint64         `json:"target"` // 0 buys a ticket every block
+	Tickets   uint64         `json:"tickets"`
+	Pending   uint64         `json:"pending"`
+	Bought    uint64         `json:"bought"`
+	Failures  uint64         `json:"failures"` // consecutive failures
+	LastError string         `json:"lastError,omitempty"`
+	RetryAt   hexutil.Uint64 `json:"retryAt"`
+}
+
+var (
+	autoBuyTicketStatus AutoBuyTicketStatus
+	autoBuyTicketMutex  sync.Mutex
+)
+
+// SetAutoBuyTicketTarget sets the number of tickets the auto ticket purchaser
+// maintains for the coinbase, 0 buys a ticket every block
+func SetAutoBuyTicketTarget(target uint64) {
+	autoBuyTicketMutex.Lock()
+	defer autoBuyTicketMutex.Unlock()
+	autoBuyTicketStatus.Target = target
+	// give a changed target an immediate chance
+	autoBuyTicketStatus.Failures = 0
+	autoBuyTicketStatus.RetryAt = 0
+}
+
+// GetAutoBuyTicketStatus returns the state of the auto ticket purchaser
+func GetAutoBuyTicketStatus() AutoBuyTicketStatus {
+	autoBuyTicketMutex.Lock()
+	defer autoBuyTicketMutex.Unlock()
+	status := autoBuyTicketStatus
+	status.Enabled = common.AutoBuyTicket
+	return status
+}
+
+// autoBuyTicketBackoff returns the number of blocks to wait after the given
+// number of consecutive failures
+func autoBuyTicketBackoff(failures uint64) uint64 {
+	if failures == 0 {
+		return 0
+	}
+	if failures > 8 {
+		return autoBuyTicketMaxBackoff
+	}
+	return 1 << (failures - 1)
+}
+
+// pendingBuyTickets counts the ticket purchases of the given address in the txpool
+func pendingBuyTickets(b Backend, from common.Address) uint64 {
+	txs, err := b.GetPoolTransactions()
+	if err != nil {
+		return 0
+	}
+	signer := types.MakeSigner(b.ChainConfig(), b.CurrentBlock().Number())
+	count := uint64(0)
+	for _, tx := range txs {
+		if !tx.IsBuyTicketTx() {
+			continue
+		}
+		if sender, err := types.Sender(signer, tx); err == nil && sender == from {
+			count++
+		}
+	}
+	return count
+}
+
+// auto buy ticket
+func AutoBuyTicket(enable bool, target uint64) {
+	if enable {
+		_, err := fusionTransactionAPI.b.Coinbase()
+		if err != nil {
+			log.Warn("AutoBuyTicket not enabled as no coinbase account exist")
+			enable = false
+		}
+	}
+	SetAutoBuyTicketTarget(target)
+	common.AutoBuyTicket = enable
+
+	for {
+		<-common.AutoBuyTicketChan
+	COMSUMEALL:
+		for {
+			select {
+			case <-common.AutoBuyTicketChan:
+			default:
+				break COMSUMEALL
+			}
+		}
+
+		// prevent auto buy ticket in syncing
+		if !fusionTransactionAPI.b.IsMining() {
+			common.DebugInfo("ignore AutoBuyTicket as isMining is false")
+			continue
+		}
+
+		coinbase, err := fusionTransactionAPI.b.Coinbase()
+		if err == nil {
+			autoBuyTicket(fusionTransactionAPI, coinbase)
+		}
+	}
+}
+
+// autoBuyTicket buys a ticket for the coinbase if it owns less than the target
+// number of tickets and it is not backing off from failed purchases
+func autoBuyTicket(s *FusionTransactionAPI, coinbase common.Address) {
+	ctx := context.TODO()
+	number := s.b.CurrentBlock().NumberU64()
+
+	tickets, err := s.pubapi.getAllTickets(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return
+	}
+	owned := tickets.NumberOfTicketsByAddress(coinbase)
+	pending := pendingBuyTickets(s.b, coinbase)
+	autoBuyTicketTicketsGauge.Update(int64(owned))
+
+	autoBuyTicketMutex.Lock()
+	status := &autoBuyTicketStatus
+	status.Tickets, status.Pending = owned, pending
+	if !status.wantsTicket(number, owned, pending) || doesTicketPurchaseExistsForBlock(int64(number), coinbase) {
+		autoBuyTicketMutex.Unlock()
+		return
+	}
+	autoBuyTicketMutex.Unlock()
+
+	fbase := common.FusionBaseArgs{From: coinbase}
+	args := common.BuyTicketArgs{FusionBaseArgs: fbase}
+	hash, err := s.BuyTicket(ctx, args)
+
+	autoBuyTicketMutex.Lock()
+	defer autoBuyTicketMutex.Unlock()
+	status.recordPurchase(number, err)
+	if err != nil {
+		autoBuyTicketFailedMeter.Mark(1)
+		log.Debug("AutoBuyTicket failed", "coinbase", coinbase, "failures", status.Failures, "retryAt", uint64(status.RetryAt), "err", err)
+		return
+	}
+	autoBuyTicketBoughtMeter.Mark(1)
+	log.Info("AutoBuyTicket bought ticket", "coinbase", coinbase, "tickets", owned, "hash", hash)
+}
+
+// wantsTicket reports whether a ticket is bought at block number for a
+// coinbase owning owned tickets, with pending purchases in the txpool
+func (status *AutoBuyTicketStatus) wantsTicket(number, owned, pending uint64) bool {
+	if number < uint64(status.RetryAt) {
+		return false
+	}
+	return status.Target == 0 || owned+pending < status.Target
+}
+
+// recordPurchase records the result of a purchase at block number, failures
+// back off exponentially
+func (status *AutoBuyTicketStatus) recordPurchase(number uint64, err error) {
+	if err != nil {
+		status.Failures++
+		status.LastError = err.Error()
+		status.RetryAt = hexutil.Uint64(number + autoBuyTicketBackoff(status.Failures))
+		return
+	}
+	status.Bought++
+	status.Pending++
+	status.Failures = 0
+	status.LastError = ""
+	status.RetryAt = 0
+}
@@ -0,0 +1,52 @@
+package ethapi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAutoBuyTicketTarget(t *testing.T) {
+	status := &AutoBuyTicketStatus{Target: 3}
+	tests := []struct {
+		owned, pending uint64
+		want           bool
+	}{
+		{0, 0, true},
+		{2, 0, true},
+		{2, 1, false},
+		{3, 0, false},
+		{5, 0, false},
+	}
+	for _, test := range tests {
+		if have := status.wantsTicket(10, test.owned, test.pending); have != test.want {
+			t.Errorf("%d owned, %d pending: have %v, want %v", test.owned, test.pending, have, test.want)
+		}
+	}
+	// without target a ticket is bought every block
+	status.Target = 0
+	if !status.wantsTicket(10, 100, 1) {
+		t.Errorf("no ticket bought without target")
+	}
+}
+
+func TestAutoBuyTicketBackoff(t *testing.T) {
+	status := new(AutoBuyTicketStatus)
+	failed := errors.New("not enough time lock")
+	for i, wait := range []uint64{1, 2, 4, 8} {
+		status.recordPurchase(100, failed)
+		if status.Failures != uint64(i+1) || uint64(status.RetryAt) != 100+wait || status.LastError != failed.Error() {
+			t.Fatalf("failure %d: have %+v, want retry at %d", i+1, status, 100+wait)
+		}
+	}
+	if status.wantsTicket(107, 0, 0) || !status.wantsTicket(108, 0, 0) {
+		t.Errorf("purchase not retried at block %d only", status.RetryAt)
+	}
+	if have := autoBuyTicketBackoff(100); have != autoBuyTicketMaxBackoff {
+		t.Errorf("backoff after many failures: have %d, want %d", have, autoBuyTicketMaxBackoff)
+	}
+
+	status.recordPurchase(108, nil)
+	if status.Failures != 0 || status.RetryAt != 0 || status.LastError != "" || status.Bought != 1 || status.Pending != 1 {
+		t.Errorf("success not recorded: %+v", status)
+	}
+}
@@ -0,0 +1,121 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/consensus/datong"
+	"github.com/FusionFoundation/go-fusion/log"
+	"github.com/FusionFoundation/go-fusion/metrics"
+	"github.com/FusionFoundation/go-fusion/rpc"
+)
+
+var (
+	autoReportSentMeter    = metrics.NewRegisteredMeter("fsn/autoreport/sent", nil)
+	autoReportSkippedMeter = metrics.NewRegisteredMeter("fsn/autoreport/skipped", nil)
+
+	errReportNothingToPunish = errors.New("miner has no tickets or time locked FSN to punish")
+)
+
+// AutoReportStatus is the state of the double mining auto reporter
+type AutoReportStatus struct {
+	Enabled   bool         `json:"enabled"`
+	MaxFee    *hexutil.Big `json:"maxFee,omitempty"` // nil spends any fee
+	Reported  uint64       `json:"reported"`
+	Skipped   uint64       `json:"skipped"`
+	LastError string       `json:"lastError,omitempty"`
+}
+
+var (
+	autoReportStatus AutoReportStatus
+	autoReportMutex  sync.Mutex
+)
+
+// GetAutoReportStatus returns the state of the double mining auto reporter
+func GetAutoReportStatus() AutoReportStatus {
+	autoReportMutex.Lock()
+	defer autoReportMutex.Unlock()
+	status := autoReportStatus
+	status.Enabled = common.AutoReportIllegal
+	return status
+}
+
+// AutoReportIllegal submits the double mining reports of the watcher from the
+// coinbase, skipping reports whose fee exceeds maxFee
+func AutoReportIllegal(enable bool, maxFee *big.Int) {
+	if enable {
+		if _, err := fusionTransactionAPI.b.Coinbase(); err != nil {
+			log.Warn("AutoReportIllegal not enabled as no coinbase account exist")
+			enable = false
+		}
+	}
+	autoReportMutex.Lock()
+	if maxFee != nil && maxFee.Sign() > 0 {
+		autoReportStatus.MaxFee = (*hexutil.Big)(maxFee)
+	}
+	autoReportMutex.Unlock()
+	common.AutoReportIllegal = enable
+
+	for content := range common.ReportIllegalChan {
+		coinbase, err := fusionTransactionAPI.b.Coinbase()
+		if err != nil {
+			continue
+		}
+		hash, err := autoReportIllegal(fusionTransactionAPI, coinbase, content, maxFee)
+
+		autoReportMutex.Lock()
+		if err != nil {
+			autoReportStatus.Skipped++
+			autoReportStatus.LastError = err.Error()
+			autoReportSkippedMeter.Mark(1)
+			log.Warn("AutoReportIllegal skipped report", "err", err)
+		} else {
+			autoReportStatus.Reported++
+			autoReportSentMeter.Mark(1)
+			log.Info("AutoReportIllegal sent report", "hash", hash)
+		}
+		autoReportMutex.Unlock()
+	}
+}
+
+// autoReportIllegal sends the report if it is still acceptable, punishes the
+// miner and costs no more than maxFee
+func autoReportIllegal(s *FusionTransactionAPI, coinbase common.Address, content []byte, maxFee *big.Int) (common.Hash, error) {
+	ctx := context.TODO()
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return common.Hash{}, err
+	}
+	header1, _, err := datong.CheckAddingReport(state, content, header.Number)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	miner := header1.Coinbase
+	if miner == coinbase {
+		return common.Hash{}, fmt.Errorf("double miner %v is the coinbase", miner)
+	}
+	if tickets, err := state.TicketsByOwner(miner); err != nil || len(tickets) == 0 {
+		if state.GetTimeLockBalance(common.SystemAssetID, miner).IsEmpty() {
+			return common.Hash{}, errReportNothingToPunish
+		}
+	}
+
+	args := common.FusionBaseArgs{From: coinbase}
+	tx, err := s.BuildReportIllegalTx(ctx, args, content)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasPrice())
+	if maxFee != nil && maxFee.Sign() > 0 && fee.Cmp(maxFee) > 0 {
+		return common.Hash{}, fmt.Errorf("report fee %v exceeds maximum %v", fee, maxFee)
+	}
+	if balance := state.GetBalance(common.SystemAssetID, coinbase); balance.Cmp(fee) < 0 {
+		return common.Hash{}, fmt.Errorf("insufficient balance %v for report fee %v", balance, fee)
+	}
+	return s.sendTransaction(ctx, coinbase, tx)
+}
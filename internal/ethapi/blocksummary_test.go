@@ -0,0 +1,67 @@
+package ethapi
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+// fsnCallLog builds the log of an FSN call the way the state transition does
+func fsnCallLog(t *testing.T, fn common.FSNCallFunc, maps map[string]interface{}) *types.Log {
+	data, err := json.Marshal(maps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &types.Log{Address: common.FSNCallAddress, Topics: []common.Hash{fn.Topic()}, Data: data}
+}
+
+func TestBlockFsnSummaryLogs(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	ticket, err := rlp.EncodeToBytes(&common.BuyTicketParam{Start: 1, End: 2, Denomination: []uint64{5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assetID := common.HexToHash("0x01")
+	logs := []*types.Log{
+		fsnCallLog(t, common.GenAssetFunc, map[string]interface{}{"AssetID": assetID}),
+		{Address: common.FSNCallAddress, Topics: []common.Hash{common.FsnCallIndexTopic, common.FSNCallFunc(common.GenAssetFunc).Topic()}},
+		fsnCallLog(t, common.MakeSwapFunc, map[string]interface{}{"SwapID": common.HexToHash("0x02")}),
+		fsnCallLog(t, common.TakeSwapFuncExt, map[string]interface{}{"SwapID": common.HexToHash("0x02")}),
+		fsnCallLog(t, common.RecallMultiSwapFunc, map[string]interface{}{"SwapID": common.HexToHash("0x03")}),
+		fsnCallLog(t, common.BuyTicketFunc, map[string]interface{}{"Base": ticket}),
+		fsnCallLog(t, common.BuyTicketFunc, map[string]interface{}{"Base": []byte{}}),
+		fsnCallLog(t, common.SendAssetFunc, map[string]interface{}{"Error": "not enough asset"}),
+		{Address: common.HexToAddress("0x1234"), Topics: []common.Hash{common.FSNCallFunc(common.GenAssetFunc).Topic()}, Data: []byte("not json")},
+	}
+	summary := &BlockFsnSummary{Calls: make(map[string]int), Failed: make(map[string]int), AssetsCreated: []common.Hash{}}
+	if err := summary.addLogs(logs, big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+	if summary.Calls["GenAssetFunc"] != 1 || summary.Calls["BuyTicketFunc"] != 2 || len(summary.Calls) != 5 {
+		t.Errorf("calls: have %v", summary.Calls)
+	}
+	if summary.Failed["SendAssetFunc"] != 1 || len(summary.Failed) != 1 {
+		t.Errorf("failed calls: have %v", summary.Failed)
+	}
+	if len(summary.AssetsCreated) != 1 || summary.AssetsCreated[0] != assetID {
+		t.Errorf("assets created: have %v, want [%x]", summary.AssetsCreated, assetID)
+	}
+	if summary.SwapsMade != 1 || summary.SwapsTaken != 1 || summary.SwapsRecalled != 1 {
+		t.Errorf("swaps: have %d made, %d taken, %d recalled, want 1 each", summary.SwapsMade, summary.SwapsTaken, summary.SwapsRecalled)
+	}
+	// the ticket without denomination weighs one
+	if summary.TicketsBought != 2 || summary.TicketWeight != 6 {
+		t.Errorf("tickets: have %d bought of weight %d, want 2 of weight 6", summary.TicketsBought, summary.TicketWeight)
+	}
+
+	bad := []*types.Log{{Address: common.FSNCallAddress, Topics: []common.Hash{common.FSNCallFunc(common.GenAssetFunc).Topic()}, Data: []byte("not json")}}
+	if err := summary.addLogs(bad, big.NewInt(1)); err == nil {
+		t.Errorf("no error on undecodable log data")
+	}
+}
@@ -0,0 +1,190 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/rpc"
+)
+
+// SwapCommitment is the amount of an asset an open swap of an account holds
+type SwapCommitment struct {
+	SwapID    common.Hash    `json:"swapID"`
+	AssetID   common.Hash    `json:"assetID"`
+	Amount    string         `json:"amount"`
+	StartTime hexutil.Uint64 `json:"startTime"`
+	EndTime   hexutil.Uint64 `json:"endTime"`
+}
+
+// TicketCommitment is the FSN a live ticket of an account holds until it
+// expires
+type TicketCommitment struct {
+	TicketID   common.Hash    `json:"ticketID"`
+	Value      string         `json:"value"`
+	StartTime  hexutil.Uint64 `json:"startTime"`
+	ExpireTime hexutil.Uint64 `json:"expireTime"`
+}
+
+// TimeLockCommitment is a time lock segment of an account which has not
+// started yet
+type TimeLockCommitment struct {
+	AssetID   common.Hash    `json:"assetID"`
+	Value     string         `json:"value"`
+	StartTime hexutil.Uint64 `json:"startTime"`
+	EndTime   hexutil.Uint64 `json:"endTime"`
+}
+
+// OpenCommitments lists everything encumbering the funds of an account.
+// Swaps is nil if the node has no swap history index to find them.
+type OpenCommitments struct {
+	BlockNumber hexutil.Uint64       `json:"blockNumber"`
+	Address     common.Address       `json:"address"`
+	Swaps       []SwapCommitment     `json:"swaps"`
+	Tickets     []TicketCommitment   `json:"tickets"`
+	TimeLocks   []TimeLockCommitment `json:"timeLocks"`
+}
+
+// GetOpenCommitments returns the open swaps, the live tickets and the not yet
+// started time lock segments of an address, which make its spendable balance
+// lower than its total balance
+func (s *PublicFusionAPI) GetOpenCommitments(ctx context.Context, addr common.AddressOrNotation, blockNr rpc.BlockNumber) (*OpenCommitments, error) {
+	address, err := s.resolveAddress(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	now := header.Time
+	result := &OpenCommitments{
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		Address:     address,
+		Tickets:     []TicketCommitment{},
+		TimeLocks:   []TimeLockCommitment{},
+	}
+	if backend, ok := s.b.(openSwapsBackend); ok {
+		if ids, err := backend.OpenSwaps(address, now); err == nil {
+			result.Swaps = make([]SwapCommitment, 0, len(ids))
+			for _, id := range ids {
+				// the index may lag behind the requested block
+				swap, err := state.GetSwap(id)
+				if err != nil || swap.Owner != address {
+					continue
+				}
+				result.Swaps = append(result.Swaps, SwapCommitment{
+					SwapID:    swap.ID,
+					AssetID:   swap.FromAssetID,
+					Amount:    new(big.Int).Mul(swap.MinFromAmount, swap.SwapSize).String(),
+					StartTime: hexutil.Uint64(swap.FromStartTime),
+					EndTime:   hexutil.Uint64(swap.FromEndTime),
+				})
+			}
+		}
+	}
+	tickets, err := state.AllTickets()
+	if err != nil {
+		return nil, err
+	}
+	for _, ticket := range tickets.ToTicketSlice() {
+		if ticket.Owner == address && ticket.ExpireTime > now {
+			result.Tickets = append(result.Tickets, TicketCommitment{
+				TicketID:   ticket.ID,
+				Value:      ticket.Value().String(),
+				StartTime:  hexutil.Uint64(ticket.StartTime),
+				ExpireTime: hexutil.Uint64(ticket.ExpireTime),
+			})
+		}
+	}
+	sort.Slice(result.Tickets, func(i, j int) bool { return result.Tickets[i].ExpireTime < result.Tickets[j].ExpireTime })
+	for assetID, timelock := range state.GetAllTimeLockBalances(address) {
+		for _, item := range timelock.Items {
+			if item.StartTime > now {
+				result.TimeLocks = append(result.TimeLocks, TimeLockCommitment{
+					AssetID:   assetID,
+					Value:     item.Value.String(),
+					StartTime: hexutil.Uint64(item.StartTime),
+					EndTime:   hexutil.Uint64(item.EndTime),
+				})
+			}
+		}
+	}
+	sort.Slice(result.TimeLocks, func(i, j int) bool {
+		if result.TimeLocks[i].StartTime != result.TimeLocks[j].StartTime {
+			return result.TimeLocks[i].StartTime < result.TimeLocks[j].StartTime
+		}
+		return result.TimeLocks[i].AssetID.Big().Cmp(result.TimeLocks[j].AssetID.Big()) < 0
+	})
+	return result, state.Error()
+}
+
+// SpendableBalance is the balance of an asset an account can spend once its
+// pooled transactions are mined
+type SpendableBalance struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	AssetID     common.Hash    `json:"assetID"`
+	Address     common.Address `json:"address"`
+	Balance     string         `json:"balance"`    // balance at the block
+	Pending     string         `json:"pending"`    // balance the pooled transactions spend, fees and gas included
+	PendingTxs  int            `json:"pendingTxs"` // pooled transactions applied
+	Spendable   string         `json:"spendable"`  // balance left once the pooled transactions are mined
+
+	TimeLockSpendable  string `json:"timeLockSpendable"`  // time locked value usable from now on
+	TimeLockNotStarted string `json:"timeLockNotStarted"` // time locked value of the segments not started yet
+}
+
+// GetSpendableBalance returns the balance of an asset an address can spend,
+// net of what its pooled transactions spend, fees included, and of the time
+// locked value not started yet. The pooled transactions of the address are
+// applied on the latest state in nonce order as the pool validates them, so
+// the spendable balance is what the pool accepts for a new transaction.
+func (s *PublicFusionAPI) GetSpendableBalance(ctx context.Context, assetID common.Hash, addr common.AddressOrNotation) (*SpendableBalance, error) {
+	address, err := s.resolveAddress(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	state, header, err := s.b.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	balance := new(big.Int).Set(state.GetBalance(assetID, address))
+
+	pending, queued := s.b.TxPoolContent()
+	pooled := make(map[uint64]*types.Transaction)
+	for _, tx := range append(pending[address], queued[address]...) {
+		pooled[tx.Nonce()] = tx
+	}
+	var txs types.Transactions
+	for nonce := state.GetNonce(address); pooled[nonce] != nil; nonce++ {
+		txs = append(txs, pooled[nonce])
+	}
+	signer := types.MakeSigner(s.b.ChainConfig(), header.Number)
+	core.ApplyPooledTxs(s.b.ChainConfig(), signer, state, header, txs)
+	spendable := state.GetBalance(assetID, address)
+
+	now := uint64(time.Now().Unix())
+	timelock := state.GetTimeLockBalance(assetID, address)
+	notStarted := new(big.Int)
+	for _, item := range timelock.Items {
+		if item.StartTime > now {
+			notStarted.Add(notStarted, item.Value)
+		}
+	}
+	return &SpendableBalance{
+		BlockNumber:        hexutil.Uint64(header.Number.Uint64()),
+		AssetID:            assetID,
+		Address:            address,
+		Balance:            balance.String(),
+		Pending:            new(big.Int).Sub(balance, spendable).String(),
+		PendingTxs:         len(txs),
+		Spendable:          spendable.String(),
+		TimeLockSpendable:  timelock.GetSpendableValue(now, now).String(),
+		TimeLockNotStarted: notStarted.String(),
+	}, state.Error()
+}
@@ -0,0 +1,81 @@
+package ethapi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/rlp"
+	"github.com/FusionFoundation/go-fusion/rpc"
+)
+
+// fsnCallError is returned by the gas estimation of an FSN call which would
+// fail, with the code of the failure as error code and the called function
+// and the failure reason as error data
+type fsnCallError struct {
+	fn  common.FSNCallFunc
+	err error
+}
+
+func (e *fsnCallError) Error() string {
+	return fmt.Sprintf("FSN call %s would fail: %v", e.fn.Name(), e.err)
+}
+
+func (e *fsnCallError) ErrorCode() int { return int(common.FsnErrorCodeOf(e.err)) }
+
+func (e *fsnCallError) ErrorData() interface{} {
+	code := common.FsnErrorCodeOf(e.err)
+	return map[string]string{"func": e.fn.Name(), "error": code.Name(), "reason": e.err.Error()}
+}
+
+// doEstimateFsnCallGas estimates the gas of an FSN call. The call does not
+// run any EVM code, so it is executed once with the gas allowance and the gas
+// it used is returned. handleFsnCall is run as when mining, so that a failing
+// call returns its error instead of being included as failed.
+func doEstimateFsnCallGas(ctx context.Context, b Backend, args CallArgs, blockNrOrHash rpc.BlockNumberOrHash, allowance uint64) (hexutil.Uint64, error) {
+	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return 0, err
+	}
+	var data []byte
+	if args.Data != nil {
+		data = []byte(*args.Data)
+	}
+	param := common.FSNCallParam{}
+	if err := rlp.DecodeBytes(data, &param); err != nil {
+		return 0, fmt.Errorf("decode FSNCallParam error")
+	}
+	gasPrice := new(big.Int).SetUint64(defaultGasPrice)
+	if args.GasPrice != nil {
+		gasPrice = args.GasPrice.ToInt()
+	}
+	value := new(big.Int)
+	if args.Value != nil {
+		value = args.Value.ToInt()
+	}
+	msg := types.NewMessage(*args.From, args.To, 0, value, allowance, gasPrice, data, false)
+
+	evm, vmError, err := b.GetEVM(ctx, msg, state, header)
+	if err != nil {
+		return 0, err
+	}
+	evm.Context.MixDigest = common.Hash{}
+
+	gp := new(core.GasPool).AddGas(math.MaxUint64)
+	_, gas, failed, err := core.ApplyMessage(evm, msg, gp)
+	if err := vmError(); err != nil {
+		return 0, err
+	}
+	if err != nil {
+		return 0, &fsnCallError{fn: param.Func, err: err}
+	}
+	if failed {
+		return 0, fmt.Errorf("gas required exceeds allowance (%d) or always failing transaction", allowance)
+	}
+	return hexutil.Uint64(gas), nil
+}
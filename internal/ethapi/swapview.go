@@ -0,0 +1,94 @@
+package ethapi
+
+import (
+	"math/big"
+	"strings"
+	"unicode"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/state"
+)
+
+// RPCSwap is a swap as returned by fsn_getSwap, with its description
+// sanitized, its targets checksummed and its implied price
+type RPCSwap struct {
+	common.Swap
+	Description string
+	Targes      []string
+	Price       string       `json:",omitempty"` // MinToAmount/MinFromAmount as a reduced rational
+	Raw         *common.Swap `json:",omitempty"`
+}
+
+// RPCMultiSwap is a multi swap as returned by fsn_getMultiSwap, with its
+// description sanitized and its targets checksummed
+type RPCMultiSwap struct {
+	common.MultiSwap
+	Description string
+	Targes      []string
+	Raw         *common.MultiSwap `json:",omitempty"`
+}
+
+// sanitizeDescription returns the description as valid UTF-8 without control
+// and bidirectional formatting characters, which could spoof the display
+func sanitizeDescription(description string) string {
+	description = strings.ToValidUTF8(description, string(unicode.ReplacementChar))
+	return strings.Map(func(r rune) rune {
+		if (unicode.IsControl(r) && r != '\n' && r != '\t') || unicode.Is(unicode.Bidi_Control, r) {
+			return -1
+		}
+		return r
+	}, description)
+}
+
+func checksumAddresses(addresses []common.Address) []string {
+	ret := make([]string, len(addresses))
+	for i, addr := range addresses {
+		ret[i] = addr.Hex()
+	}
+	return ret
+}
+
+// swapPrice returns the amount of ToAssetID asked per unit of FromAssetID
+func swapPrice(minFrom, minTo *big.Int) string {
+	if minFrom == nil || minTo == nil || minFrom.Sign() <= 0 {
+		return ""
+	}
+	return new(big.Rat).SetFrac(minTo, minFrom).String()
+}
+
+func newRPCSwap(swap *common.Swap, includeRaw bool) *RPCSwap {
+	ret := &RPCSwap{
+		Swap:        *swap,
+		Description: sanitizeDescription(swap.Description),
+		Targes:      checksumAddresses(swap.Targes),
+		Price:       swapPrice(swap.MinFromAmount, swap.MinToAmount),
+	}
+	if includeRaw {
+		ret.Raw = swap
+	}
+	return ret
+}
+
+func newRPCMultiSwap(swap *common.MultiSwap, includeRaw bool) *RPCMultiSwap {
+	ret := &RPCMultiSwap{
+		MultiSwap:   *swap,
+		Description: sanitizeDescription(swap.Description),
+		Targes:      checksumAddresses(swap.Targes),
+	}
+	if includeRaw {
+		ret.Raw = swap
+	}
+	return ret
+}
+
+// ownedRPCSwaps returns the swaps of ids which owner made, the index they come
+// from may lag behind the state of the requested block
+func ownedRPCSwaps(statedb *state.StateDB, owner common.Address, ids []common.Hash) map[common.Hash]*RPCSwap {
+	swaps := make(map[common.Hash]*RPCSwap, len(ids))
+	for _, id := range ids {
+		if swap, err := statedb.GetSwap(id); err == nil && swap.Owner == owner {
+			swaps[id] = newRPCSwap(&swap, false)
+		}
+	}
+	return swaps
+}
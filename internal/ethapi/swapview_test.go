@@ -0,0 +1,63 @@
+package ethapi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/state"
+)
+
+func TestNewRPCSwap(t *testing.T) {
+	target := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	swap := &common.Swap{
+		ID:            common.HexToHash("0x01"),
+		MinFromAmount: big.NewInt(4),
+		MinToAmount:   big.NewInt(6),
+		SwapSize:      big.NewInt(1),
+		Targes:        []common.Address{target},
+		Description:   "buy\u202e ti\x00me\n\xff",
+	}
+	rpcSwap := newRPCSwap(swap, false)
+	if want := "buy time\n\ufffd"; rpcSwap.Description != want {
+		t.Errorf("description: have %q, want %q", rpcSwap.Description, want)
+	}
+	if len(rpcSwap.Targes) != 1 || rpcSwap.Targes[0] != target.Hex() {
+		t.Errorf("targets: have %v, want [%s]", rpcSwap.Targes, target.Hex())
+	}
+	if rpcSwap.Price != "3/2" {
+		t.Errorf("price: have %q, want 3/2", rpcSwap.Price)
+	}
+	if rpcSwap.Raw != nil {
+		t.Errorf("raw swap returned without includeRaw")
+	}
+	if rpcSwap = newRPCSwap(swap, true); rpcSwap.Raw != swap {
+		t.Errorf("raw swap not returned with includeRaw")
+	}
+	swap.MinFromAmount = new(big.Int)
+	if rpcSwap = newRPCSwap(swap, false); rpcSwap.Price != "" {
+		t.Errorf("price of a swap from nothing: have %q, want none", rpcSwap.Price)
+	}
+}
+
+func TestOwnedRPCSwaps(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	owner, other := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	for i, swapOwner := range []common.Address{owner, other} {
+		swap := common.Swap{ID: common.BigToHash(big.NewInt(int64(i + 1))), Owner: swapOwner, MinFromAmount: big.NewInt(1), MinToAmount: big.NewInt(1), SwapSize: big.NewInt(1), Description: "swap\x07"}
+		if err := statedb.AddSwap(swap); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the index lists a swap of another owner and a swap not in the state
+	ids := []common.Hash{common.BigToHash(big.NewInt(1)), common.BigToHash(big.NewInt(2)), common.BigToHash(big.NewInt(3))}
+	swaps := ownedRPCSwaps(statedb, owner, ids)
+	if len(swaps) != 1 {
+		t.Fatalf("have %d swaps, want 1", len(swaps))
+	}
+	swap := swaps[ids[0]]
+	if swap == nil || swap.Owner != owner || swap.Description != "swap" {
+		t.Errorf("have swap %+v, want the decoded swap of owner", swap)
+	}
+}
@@ -0,0 +1,511 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package fsntx constructs FSN call transactions without access to a running
+// node, for cold wallet workflows. The call parameters are encoded and checked
+// with the same args and params the fsntx API, the transaction pool and the
+// state transition use; checks which need chain state (balances, notations,
+// swaps) are left to the node the transaction is finally submitted to.
+package fsntx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core/types"
+)
+
+var (
+	// ErrMissingField is returned if a transaction field which a node would fill
+	// in by default is not given.
+	ErrMissingField = errors.New("nonce, gas and gasPrice must be set for offline builds")
+
+	// ErrUSAN is returned if an USAN recipient is given, which can only be
+	// resolved against chain state.
+	ErrUSAN = errors.New("USAN recipients can not be resolved offline, use addresses instead")
+)
+
+// Request is the JSON input of an offline build. Func is the name of the fsntx
+// API method (e.g. "sendAsset", "buyTicket") and Args holds its arguments in
+// the same format the API accepts.
+type Request struct {
+	Func string          `json:"func"`
+	Args json.RawMessage `json:"args"`
+}
+
+// builder decodes and checks the arguments of an FSN call at the given unix
+// time, returning the base args and the encoded call.
+type builder func(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error)
+
+var builders = map[string]builder{
+	"genNotation":         buildGenNotation,
+	"genAsset":            buildGenAsset,
+	"sendAsset":           buildSendAsset,
+	"assetToTimeLock":     buildTimeLock(common.AssetToTimeLock),
+	"timeLockToTimeLock":  buildTimeLock(common.TimeLockToTimeLock),
+	"timeLockToAsset":     buildTimeLock(common.TimeLockToAsset),
+	"sendTimeLock":        buildTimeLock(common.SmartTransfer),
+	"buyTicket":           buildBuyTicket,
+	"incAsset":            buildAssetValueChange(true),
+	"decAsset":            buildAssetValueChange(false),
+	"makeSwap":            buildMakeSwap,
+	"recallSwap":          buildRecallSwap,
+	"takeSwap":            buildTakeSwap,
+	"makeMultiSwap":       buildMakeMultiSwap,
+	"recallMultiSwap":     buildRecallMultiSwap,
+	"takeMultiSwap":       buildTakeMultiSwap,
+	"typedCall":           buildTypedCall,
+	"stakingKey":          buildStakingKey,
+	"stakingBuyTicket":    buildStakingBuyTicket,
+	"assetTransferList":   buildAssetTransferList,
+	"setFsnCallFee":       buildSetFsnCallFee,
+	"createProposal":      buildCreateProposal,
+	"voteProposal":        buildVoteProposal,
+	"revokeTicket":        buildRevokeTicket,
+	"escrowAsset":         buildEscrowAsset,
+	"claimEscrow":         buildClaimEscrow,
+	"createStream":        buildCreateStream,
+	"withdrawStream":      buildWithdrawStream,
+	"createCondition":     buildCreateCondition,
+	"resolveCondition":    buildResolveCondition,
+	"conditionalTransfer": buildConditionalTransfer,
+	"settleConditional":   buildSettleConditional,
+	"attestDeposit":       buildAttestDeposit,
+	"bridgeWithdraw":      buildBridgeWithdraw,
+}
+
+// Funcs returns the names of the supported FSN calls.
+func Funcs() []string {
+	names := make([]string, 0, len(builders))
+	for name := range builders {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Build constructs the unsigned FSN call transaction described by the request.
+// The timestamp is used in place of the chain head time for default and
+// validity checks of time locked values.
+func Build(req *Request, now uint64) (*types.Transaction, error) {
+	name := req.Func
+	if len(name) > 0 {
+		name = strings.ToLower(name[:1]) + name[1:]
+	}
+	build, ok := builders[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown FSN call %q", req.Func)
+	}
+	base, call, err := build(req.Args, now)
+	if err != nil {
+		return nil, err
+	}
+	if base.Nonce == nil || base.Gas == nil || base.GasPrice == nil {
+		return nil, ErrMissingField
+	}
+	data, err := call.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+	return types.NewTransaction(uint64(*base.Nonce), common.FSNCallAddress, big.NewInt(0), uint64(*base.Gas), base.GasPrice.ToInt(), data), nil
+}
+
+func decode(input json.RawMessage, args interface{}) error {
+	if len(input) == 0 {
+		return errors.New("missing call arguments")
+	}
+	if err := json.Unmarshal(input, args); err != nil {
+		return fmt.Errorf("invalid call arguments: %v", err)
+	}
+	return nil
+}
+
+func encode(args common.FSNBaseArgsInterface, fn common.FSNCallFunc) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	data, err := args.ToData()
+	if err != nil {
+		return nil, nil, err
+	}
+	return args.BaseArgs(), &common.FSNCallParam{Func: fn, Data: data}, nil
+}
+
+func buildGenNotation(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.FusionBaseArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.GenNotationFunc)
+}
+
+func buildGenAsset(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.GenAssetArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if err := args.Check(common.BigMaxUint64); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, args.FuncType())
+}
+
+func buildSendAsset(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.SendAssetArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if args.ToUSAN != 0 {
+		return nil, nil, ErrUSAN
+	}
+	if err := args.ToParam().Check(common.BigMaxUint64); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.SendAssetFunc)
+}
+
+func buildTimeLock(typ common.TimeLockType) builder {
+	return func(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+		var args common.TimeLockArgs
+		if err := decode(input, &args); err != nil {
+			return nil, nil, err
+		}
+		if args.ToUSAN != 0 {
+			return nil, nil, ErrUSAN
+		}
+		args.Init(typ)
+		if typ == common.TimeLockToAsset {
+			*(*uint64)(args.StartTime) = now
+			*(*uint64)(args.EndTime) = common.TimeLockForever
+		}
+		if err := args.ToParam().Check(common.BigMaxUint64, now); err != nil {
+			return nil, nil, err
+		}
+		return encode(&args, common.TimeLockFunc)
+	}
+}
+
+func buildBuyTicket(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.BuyTicketArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	args.Init(now)
+	if err := args.ToParam().Check(common.BigMaxUint64, now); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.BuyTicketFunc)
+}
+
+func buildAssetValueChange(inc bool) builder {
+	return func(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+		var args common.AssetValueChangeExArgs
+		if err := decode(input, &args); err != nil {
+			return nil, nil, err
+		}
+		if args.ToUSAN != 0 {
+			return nil, nil, ErrUSAN
+		}
+		args.IsInc = inc
+		if err := args.ToParam().Check(common.BigMaxUint64); err != nil {
+			return nil, nil, err
+		}
+		return encode(&args, common.AssetValueChangeFunc)
+	}
+}
+
+func buildMakeSwap(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.MakeSwapArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if len(args.TargesUSAN) != 0 {
+		return nil, nil, ErrUSAN
+	}
+	args.Init(new(big.Int).SetUint64(now))
+	if err := args.ToParam().Check(common.BigMaxUint64, now); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.MakeSwapFuncExt)
+}
+
+func buildRecallSwap(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.RecallSwapArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.RecallSwapFunc)
+}
+
+func buildTakeSwap(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.TakeSwapArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if args.Size == nil || args.Size.Sign() <= 0 {
+		return nil, nil, errors.New("take swap size must be positive")
+	}
+	return encode(&args, common.TakeSwapFuncExt)
+}
+
+func buildMakeMultiSwap(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.MakeMultiSwapArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if len(args.TargesUSAN) != 0 {
+		return nil, nil, ErrUSAN
+	}
+	args.Init(new(big.Int).SetUint64(now))
+	if err := args.ToParam().Check(common.BigMaxUint64, now); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.MakeMultiSwapFunc)
+}
+
+func buildRecallMultiSwap(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.RecallMultiSwapArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.RecallMultiSwapFunc)
+}
+
+func buildTakeMultiSwap(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.TakeMultiSwapArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if args.Size == nil || args.Size.Sign() <= 0 {
+		return nil, nil, errors.New("take swap size must be positive")
+	}
+	return encode(&args, common.TakeMultiSwapFunc)
+}
+
+func buildTypedCall(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.TypedCallArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	param, err := args.ToParam()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := param.Check(common.BigMaxUint64, now); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.TypedCallFunc)
+}
+
+func buildStakingKey(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.StakingKeyArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if err := args.ToParam().Check(common.BigMaxUint64, args.From); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.StakingKeyFunc)
+}
+
+func buildStakingBuyTicket(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.StakingBuyTicketArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	args.Init(now)
+	if err := args.ToParam().Check(common.BigMaxUint64, now); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.StakingBuyTicketFunc)
+}
+
+func buildAssetTransferList(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.AssetTransferListArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if err := args.ToParam().Check(common.BigMaxUint64); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.AssetTransferListFunc)
+}
+
+func buildSetFsnCallFee(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.SetFsnCallFeeArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if args.Fee == nil {
+		return nil, nil, fmt.Errorf("fee must be set")
+	}
+	if err := args.ToParam().Check(nil); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.SetFsnCallFeeFunc)
+}
+
+func buildCreateProposal(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.CreateProposalArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if err := args.ToParam().Check(nil); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.CreateProposalFunc)
+}
+
+func buildVoteProposal(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.VoteProposalArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if err := args.ToParam().Check(nil); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.VoteProposalFunc)
+}
+
+func buildRevokeTicket(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.RevokeTicketArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if err := args.ToParam().Check(common.BigMaxUint64); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.RevokeTicketFunc)
+}
+
+func buildEscrowAsset(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.EscrowAssetArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if args.ToUSAN != 0 {
+		return nil, nil, ErrUSAN
+	}
+	if err := args.ToParam().Check(common.BigMaxUint64, now); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.EscrowAssetFunc)
+}
+
+func buildClaimEscrow(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.ClaimEscrowArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if err := args.ToParam().Check(common.BigMaxUint64); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.ClaimEscrowFunc)
+}
+
+func buildCreateStream(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.CreateStreamArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if args.ToUSAN != 0 {
+		return nil, nil, ErrUSAN
+	}
+	if err := args.ToParam().Check(common.BigMaxUint64, now); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.CreateStreamFunc)
+}
+
+func buildWithdrawStream(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.WithdrawStreamArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if err := args.ToParam().Check(common.BigMaxUint64); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.WithdrawStreamFunc)
+}
+
+func buildCreateCondition(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.CreateConditionArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if err := args.ToParam().Check(common.BigMaxUint64); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.CreateConditionFunc)
+}
+
+func buildResolveCondition(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.ResolveConditionArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if err := args.ToParam().Check(common.BigMaxUint64); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.ResolveConditionFunc)
+}
+
+func buildConditionalTransfer(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.ConditionalTransferArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if args.ToUSAN != 0 {
+		return nil, nil, ErrUSAN
+	}
+	if err := args.ToParam().Check(common.BigMaxUint64, now); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.ConditionalTransferFunc)
+}
+
+func buildSettleConditional(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.SettleConditionalArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if err := args.ToParam().Check(common.BigMaxUint64); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.SettleConditionalFunc)
+}
+
+func buildAttestDeposit(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.AttestDepositArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if err := args.ToParam().Check(common.BigMaxUint64); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.AttestDepositFunc)
+}
+
+func buildBridgeWithdraw(input json.RawMessage, now uint64) (*common.FusionBaseArgs, *common.FSNCallParam, error) {
+	var args common.BridgeWithdrawArgs
+	if err := decode(input, &args); err != nil {
+		return nil, nil, err
+	}
+	if err := args.ToParam().Check(common.BigMaxUint64); err != nil {
+		return nil, nil, err
+	}
+	return encode(&args, common.BridgeWithdrawFunc)
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsntx
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+const base = `"from": "0x0000000000000000000000000000000000001337", "nonce": "0x7", "gas": "0x15f90", "gasPrice": "0x3b9aca00"`
+
+func request(t *testing.T, input string) *Request {
+	var req Request
+	if err := json.Unmarshal([]byte(input), &req); err != nil {
+		t.Fatalf("invalid request %s: %v", input, err)
+	}
+	return &req
+}
+
+func TestBuildSendAsset(t *testing.T) {
+	req := request(t, `{"func": "sendAsset", "args": {`+base+`,
+		"asset": "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"to": "0x000000000000000000000000000000000000dead", "value": "0x64"}}`)
+
+	tx, err := Build(req, 1000)
+	if err != nil {
+		t.Fatalf("failed to build: %v", err)
+	}
+	if tx.Nonce() != 7 || tx.Gas() != 90000 || tx.GasPrice().Cmp(big.NewInt(1000000000)) != 0 {
+		t.Errorf("transaction fields mismatch: nonce %d, gas %d, gasPrice %v", tx.Nonce(), tx.Gas(), tx.GasPrice())
+	}
+	if to := tx.To(); to == nil || *to != common.FSNCallAddress {
+		t.Errorf("recipient mismatch: have %v, want %v", to, common.FSNCallAddress)
+	}
+	// The call data must match the encoding the fsntx API produces
+	param := &common.SendAssetParam{
+		AssetID: common.SystemAssetID,
+		To:      common.HexToAddress("0x000000000000000000000000000000000000dead"),
+		Value:   big.NewInt(100),
+	}
+	data, _ := param.ToBytes()
+	want, _ := rlp.EncodeToBytes(&common.FSNCallParam{Func: common.SendAssetFunc, Data: data})
+	if string(tx.Data()) != string(want) {
+		t.Errorf("call data mismatch:\nhave %x\nwant %x", tx.Data(), want)
+	}
+}
+
+func TestBuildBuyTicketDefaults(t *testing.T) {
+	tx, err := Build(request(t, `{"func": "BuyTicket", "args": {`+base+`}}`), 1000)
+	if err != nil {
+		t.Fatalf("failed to build: %v", err)
+	}
+	var call common.FSNCallParam
+	if err := rlp.DecodeBytes(tx.Data(), &call); err != nil {
+		t.Fatalf("failed to decode call: %v", err)
+	}
+	var param common.BuyTicketParam
+	if err := rlp.DecodeBytes(call.Data, &param); err != nil {
+		t.Fatalf("failed to decode param: %v", err)
+	}
+	if call.Func != common.BuyTicketFunc || param.Start != 1000 || param.End != 1000+30*24*3600 {
+		t.Errorf("unexpected ticket call: func %v, start %d, end %d", call.Func, param.Start, param.End)
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	tests := []string{
+		// unknown function
+		`{"func": "mintMoney", "args": {` + base + `}}`,
+		// missing nonce
+		`{"func": "genNotation", "args": {"from": "0x0000000000000000000000000000000000001337", "gas": "0x1", "gasPrice": "0x1"}}`,
+		// USAN recipient
+		`{"func": "sendAsset", "args": {` + base + `, "asset": "0x01", "toUSAN": 1234, "value": "0x1"}}`,
+		// invalid params, rejected by the shared param checks
+		`{"func": "genAsset", "args": {` + base + `, "name": "", "symbol": "T", "total": "0x1"}}`,
+	}
+	for i, input := range tests {
+		if _, err := Build(request(t, input), 1000); err == nil {
+			t.Errorf("test %d: expected error", i)
+		}
+	}
+}
@@ -182,6 +182,31 @@ web3._extend({
 			call: 'admin_importChain',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'ticketCacheStats',
+			call: 'admin_ticketCacheStats',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'resizeTicketCache',
+			call: 'admin_resizeTicketCache',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'purgeTicketCache',
+			call: 'admin_purgeTicketCache',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'fsnResultCacheStats',
+			call: 'admin_fsnResultCacheStats',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'purgeFsnResultCache',
+			call: 'admin_purgeFsnResultCache',
+			params: 0
+		}),
 		new web3._extend.Method({
 			name: 'sleepBlocks',
 			call: 'admin_sleepBlocks',
@@ -462,6 +487,28 @@ web3._extend({
 			call: 'debug_freezeClient',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'getTotalSupply',
+			call: 'debug_getTotalSupply',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getCirculatingSupply',
+			call: 'debug_getCirculatingSupply',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'diffState',
+			call: 'debug_diffState',
+			params: 3,
+			inputFormatter: [
+				web3._extend.formatters.inputBlockNumberFormatter,
+				web3._extend.formatters.inputBlockNumberFormatter,
+				null
+			]
+		}),
 	],
 	properties: []
 });
@@ -613,6 +660,12 @@ web3._extend({
 			call: 'miner_stopAutoBuyTicket',
 			params: 0
 		}),
+		new web3._extend.Method({
+			name: 'setAutoBuyTicketTarget',
+			call: 'miner_setAutoBuyTicketTarget',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
 	],
 	properties: []
 });
@@ -871,6 +924,13 @@ web3._extend({
 
 // FsnJS wacom
 const FsnJS = `
+var inputAddressOrNotationFormatter = function(address) {
+	if (/^[0-9]+$/.test(address)) {
+		return address;
+	}
+	return web3._extend.formatters.inputAddressFormatter(address);
+};
+
 web3._extend({
 	property: 'fsn',
 	methods: [
@@ -882,11 +942,54 @@ web3._extend({
 				web3._extend.formatters.inputDefaultBlockNumberFormatter
 			]
 		}),
+		new web3._extend.Method({
+			name: 'getTicketSelectionProof',
+			call: 'fsn_getTicketSelectionProof',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputDefaultBlockNumberFormatter
+			]
+		}),
 		new web3._extend.Method({
 			name: 'getSnapshotAtHash',
 			call: 'fsn_getSnapshotAtHash',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getStakingStatus',
+			call: 'fsn_getStakingStatus',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getEpochSummary',
+			call: 'fsn_getEpochSummary',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'verifyEpochSummary',
+			call: 'fsn_verifyEpochSummary',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getProposal',
+			call: 'fsn_getProposal',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getProposals',
+			call: 'fsn_getProposals',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getProposalVote',
+			call: 'fsn_getProposalVote',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'getBlockReward',
 			call: 'fsn_getBlockReward',
@@ -901,7 +1004,7 @@ web3._extend({
 			params: 3,
 			inputFormatter: [
 				null,
-				web3._extend.formatters.inputAddressFormatter,
+				inputAddressOrNotationFormatter,
 				web3._extend.formatters.inputDefaultBlockNumberFormatter
 			]
 		}),
@@ -910,17 +1013,100 @@ web3._extend({
 			call: 'fsn_getAllBalances',
 			params: 2,
 			inputFormatter: [
-				web3._extend.formatters.inputAddressFormatter,
+				inputAddressOrNotationFormatter,
+				web3._extend.formatters.inputDefaultBlockNumberFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getBalances',
+			call: 'fsn_getBalances',
+			params: 3,
+			inputFormatter: [
+				function(addresses) {
+					return addresses.map(inputAddressOrNotationFormatter);
+				},
+				null,
 				web3._extend.formatters.inputDefaultBlockNumberFormatter
 			]
 		}),
+		new web3._extend.Method({
+			name: 'getAssetHolders',
+			call: 'fsn_getAssetHolders',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'getAssetHolderPage',
+			call: 'fsn_getAssetHolderPage',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'checkAssetSymbol',
+			call: 'fsn_checkAssetSymbol',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getFsnCallFees',
+			call: 'fsn_getFsnCallFees',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getHardForkReceipt',
+			call: 'fsn_getHardForkReceipt',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getSwapHistory',
+			call: 'fsn_getSwapHistory',
+			params: 3
+		}),
+		new web3._extend.Method({
+			name: 'getSwapHistoryPage',
+			call: 'fsn_getSwapHistoryPage',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'getMarketStats',
+			call: 'fsn_getMarketStats',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'getHistoricalStats',
+			call: 'fsn_getHistoricalStats',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getHistoricalStatsPage',
+			call: 'fsn_getHistoricalStatsPage',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionsByFunc',
+			call: 'fsn_getTransactionsByFunc',
+			params: 4,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'getAllAssetsAtHash',
+			call: 'fsn_getAllAssetsAtHash',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getAllTicketsAtHash',
+			call: 'fsn_getAllTicketsAtHash',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'getTimeLockBalance',
 			call: 'fsn_getTimeLockBalance',
 			params: 3,
 			inputFormatter: [
 				null,
-				web3._extend.formatters.inputAddressFormatter,
+				inputAddressOrNotationFormatter,
 				web3._extend.formatters.inputDefaultBlockNumberFormatter
 			]
 		}),
@@ -930,7 +1116,7 @@ web3._extend({
 			params: 5,
 			inputFormatter: [
 				null,
-				web3._extend.formatters.inputAddressFormatter,
+				inputAddressOrNotationFormatter,
 				null,
 				null,
 				web3._extend.formatters.inputDefaultBlockNumberFormatter
@@ -941,7 +1127,7 @@ web3._extend({
 			call: 'fsn_getAllTimeLockBalances',
 			params: 2,
 			inputFormatter: [
-				web3._extend.formatters.inputAddressFormatter,
+				inputAddressOrNotationFormatter,
 				web3._extend.formatters.inputDefaultBlockNumberFormatter
 			]
 		}),
@@ -951,7 +1137,7 @@ web3._extend({
 			params: 3,
 			inputFormatter: [
 				null,
-				web3._extend.formatters.inputAddressFormatter,
+				inputAddressOrNotationFormatter,
 				web3._extend.formatters.inputDefaultBlockNumberFormatter
 			]
 		}),
@@ -960,7 +1146,7 @@ web3._extend({
 			call: 'fsn_getAllRawTimeLockBalances',
 			params: 2,
 			inputFormatter: [
-				web3._extend.formatters.inputAddressFormatter,
+				inputAddressOrNotationFormatter,
 				web3._extend.formatters.inputDefaultBlockNumberFormatter
 			]
 		}),
@@ -969,7 +1155,25 @@ web3._extend({
 			call: 'fsn_getNotation',
 			params: 2,
 			inputFormatter: [
-				web3._extend.formatters.inputAddressFormatter,
+				inputAddressOrNotationFormatter,
+				web3._extend.formatters.inputDefaultBlockNumberFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getAddressesByNotations',
+			call: 'fsn_getAddressesByNotations',
+			params: 2,
+			inputFormatter: [
+				null,
+				web3._extend.formatters.inputDefaultBlockNumberFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getNotations',
+			call: 'fsn_getNotations',
+			params: 2,
+			inputFormatter: [
+				null,
 				web3._extend.formatters.inputDefaultBlockNumberFormatter
 			]
 		}),
@@ -982,6 +1186,15 @@ web3._extend({
 				web3._extend.formatters.inputDefaultBlockNumberFormatter
 			]
 		}),
+		new web3._extend.Method({
+			name: 'getNotationHistory',
+			call: 'fsn_getNotationHistory',
+			params: 2,
+			inputFormatter: [
+				null,
+				web3._extend.formatters.inputDefaultBlockNumberFormatter
+			]
+		}),
 		new web3._extend.Method({
 			name: 'allNotation',
 			call: 'fsn_allNotation',
@@ -1098,12 +1311,58 @@ web3._extend({
 				web3._extend.formatters.inputDefaultBlockNumberFormatter
 			]
 		}),
+		new web3._extend.Method({
+			name: 'getTicketExpirySchedule',
+			call: 'fsn_getTicketExpirySchedule',
+			params: 2,
+			inputFormatter: [
+				function(owner) { return owner == null ? null : inputAddressOrNotationFormatter(owner); },
+				web3._extend.utils.fromDecimal
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getTicketsPage',
+			call: 'fsn_getTicketsPage',
+			params: 3,
+			inputFormatter: [
+				function(owner) { return owner == null ? null : inputAddressOrNotationFormatter(owner); },
+				web3._extend.formatters.inputDefaultBlockNumberFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getAccountOverview',
+			call: 'fsn_getAccountOverview',
+			params: 2,
+			inputFormatter: [
+				inputAddressOrNotationFormatter,
+				web3._extend.formatters.inputDefaultBlockNumberFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getSpendableBalance',
+			call: 'fsn_getSpendableBalance',
+			params: 2,
+			inputFormatter: [
+				null,
+				inputAddressOrNotationFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getOpenCommitments',
+			call: 'fsn_getOpenCommitments',
+			params: 2,
+			inputFormatter: [
+				inputAddressOrNotationFormatter,
+				web3._extend.formatters.inputDefaultBlockNumberFormatter
+			]
+		}),
 		new web3._extend.Method({
 			name: 'allTicketsByAddress',
 			call: 'fsn_allTicketsByAddress',
 			params: 2,
 			inputFormatter: [
-				web3._extend.formatters.inputAddressFormatter,
+				inputAddressOrNotationFormatter,
 				web3._extend.formatters.inputDefaultBlockNumberFormatter
 			]
 		}),
@@ -1112,7 +1371,7 @@ web3._extend({
 			call: 'fsn_allInfoByAddress',
 			params: 2,
 			inputFormatter: [
-				web3._extend.formatters.inputAddressFormatter,
+				inputAddressOrNotationFormatter,
 				web3._extend.formatters.inputDefaultBlockNumberFormatter
 			]
 		}),
@@ -1153,7 +1412,7 @@ web3._extend({
 			call: 'fsn_totalNumberOfTicketsByAddress',
 			params: 2,
 			inputFormatter: [
-				web3._extend.formatters.inputAddressFormatter,
+				inputAddressOrNotationFormatter,
 				web3._extend.formatters.inputDefaultBlockNumberFormatter
 			]
 		}),
@@ -1246,35 +1505,54 @@ web3._extend({
 			]
 		}),
 		new web3._extend.Method({
-			name: 'recallSwap',
-			call: 'fsn_recallSwap',
+			name: 'getTypedCallNonce',
+			call: 'fsn_getTypedCallNonce',
 			params: 2,
 			inputFormatter: [
-				web3._extend.formatters.inputTransactionFormatter,
-				null
+				inputAddressOrNotationFormatter,
+				web3._extend.formatters.inputDefaultBlockNumberFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'takeSwap',
-			call: 'fsn_takeSwap',
-			params: 2,
+			name: 'getTypedData',
+			call: 'fsn_getTypedData',
+			params: 4,
 			inputFormatter: [
-				web3._extend.formatters.inputTransactionFormatter,
+				null,
+				web3._extend.formatters.inputAddressFormatter,
+				web3._extend.utils.fromDecimal,
+				function(nonce) { return nonce == null ? null : web3._extend.utils.fromDecimal(nonce); }
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getTypedCallSigner',
+			call: 'fsn_getTypedCallSigner',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'signTypedCall',
+			call: 'fsn_signTypedCall',
+			params: 4,
+			inputFormatter: [
+				web3._extend.formatters.inputAddressFormatter,
+				null,
+				web3._extend.utils.fromDecimal,
 				null
 			]
 		}),
 		new web3._extend.Method({
-			name: 'getSwap',
-			call: 'fsn_getSwap',
-			params: 2,
+			name: 'getAssetSupplyHistory',
+			call: 'fsn_getAssetSupplyHistory',
+			params: 3,
 			inputFormatter: [
 				null,
-				web3._extend.formatters.inputDefaultBlockNumberFormatter
+				web3._extend.formatters.inputDefaultBlockNumberFormatter,
+				null
 			]
 		}),
 		new web3._extend.Method({
-			name: 'getMultiSwap',
-			call: 'fsn_getMultiSwap',
+			name: 'getAssetOwners',
+			call: 'fsn_getAssetOwners',
 			params: 2,
 			inputFormatter: [
 				null,
@@ -1282,95 +1560,976 @@ web3._extend({
 			]
 		}),
 		new web3._extend.Method({
-			name: 'getStakeInfo',
-			call: 'fsn_getStakeInfo',
-			params: 1,
+			name: 'getAssetTransferStatus',
+			call: 'fsn_getAssetTransferStatus',
+			params: 3,
 			inputFormatter: [
+				null,
+				inputAddressOrNotationFormatter,
 				web3._extend.formatters.inputDefaultBlockNumberFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'isAutoBuyTicket',
-			call: 'fsn_isAutoBuyTicket',
-			params: 0
+			name: 'setAssetTransferList',
+			call: 'fsn_setAssetTransferList',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
 		}),
 		new web3._extend.Method({
-			name: 'getLatestNotation',
-			call: 'fsn_getLatestNotation',
-			params: 1,
+			name: 'setFsnCallFee',
+			call: 'fsn_setFsnCallFee',
+			params: 2,
 			inputFormatter: [
-				web3._extend.formatters.inputDefaultBlockNumberFormatter
+				web3._extend.formatters.inputTransactionFormatter,
+				null
 			]
 		}),
 		new web3._extend.Method({
-			name: 'getRetreatTickets',
-			call: 'fsn_getRetreatTickets',
-			params: 1,
+			name: 'getStakingKey',
+			call: 'fsn_getStakingKey',
+			params: 2,
 			inputFormatter: [
+				inputAddressOrNotationFormatter,
 				web3._extend.formatters.inputDefaultBlockNumberFormatter
 			]
 		}),
-	],
-	properties:[
-		new web3._extend.Property({
-			name: 'coinbase',
-			getter: 'eth_coinbase'
-		}),
-	]
-});
-`
-
-// FsnTxJS wacom
-const FsnTxJS = `
-web3._extend({
-	property: 'fsntx',
-	methods: [
 		new web3._extend.Method({
-			name: 'sendRawTransaction',
-			call: 'fsntx_sendRawTransaction',
-			params: 1
+			name: 'createProposal',
+			call: 'fsn_createProposal',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
 		}),
 		new web3._extend.Method({
-			name: 'buildGenNotationTx',
-			call: 'fsntx_buildGenNotationTx',
-			params: 1,
+			name: 'voteProposal',
+			call: 'fsn_voteProposal',
+			params: 2,
 			inputFormatter: [
-				web3._extend.formatters.inputTransactionFormatter
+				web3._extend.formatters.inputTransactionFormatter,
+				null
 			]
 		}),
 		new web3._extend.Method({
-			name: 'genNotation',
-			call: 'fsntx_genNotation',
-			params: 1,
+			name: 'revokeTicket',
+			call: 'fsn_revokeTicket',
+			params: 2,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter,
+				null
 			]
 		}),
 		new web3._extend.Method({
-			name: 'buildGenAssetTx',
-			call: 'fsntx_buildGenAssetTx',
-			params: 1,
+			name: 'escrowAsset',
+			call: 'fsn_escrowAsset',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'claimEscrow',
+			call: 'fsn_claimEscrow',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'createStream',
+			call: 'fsn_createStream',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'withdrawStream',
+			call: 'fsn_withdrawStream',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'createCondition',
+			call: 'fsn_createCondition',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'resolveCondition',
+			call: 'fsn_resolveCondition',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'conditionalTransfer',
+			call: 'fsn_conditionalTransfer',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'settleConditional',
+			call: 'fsn_settleConditional',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'attestDeposit',
+			call: 'fsn_attestDeposit',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'bridgeWithdraw',
+			call: 'fsn_bridgeWithdraw',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'setStakingKey',
+			call: 'fsn_setStakingKey',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'stakingBuyTicket',
+			call: 'fsn_stakingBuyTicket',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'typedCall',
+			call: 'fsn_typedCall',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'recallSwap',
+			call: 'fsn_recallSwap',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'takeSwap',
+			call: 'fsn_takeSwap',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getSwap',
+			call: 'fsn_getSwap',
+			params: 3,
+			inputFormatter: [
+				null,
+				web3._extend.formatters.inputDefaultBlockNumberFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getMultiSwap',
+			call: 'fsn_getMultiSwap',
+			params: 3,
+			inputFormatter: [
+				null,
+				web3._extend.formatters.inputDefaultBlockNumberFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getEscrow',
+			call: 'fsn_getEscrow',
+			params: 2,
+			inputFormatter: [
+				null,
+				web3._extend.formatters.inputDefaultBlockNumberFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getStream',
+			call: 'fsn_getStream',
+			params: 2,
+			inputFormatter: [
+				null,
+				web3._extend.formatters.inputDefaultBlockNumberFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getCondition',
+			call: 'fsn_getCondition',
+			params: 2,
+			inputFormatter: [
+				null,
+				web3._extend.formatters.inputDefaultBlockNumberFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getConditionalTransfer',
+			call: 'fsn_getConditionalTransfer',
+			params: 2,
+			inputFormatter: [
+				null,
+				web3._extend.formatters.inputDefaultBlockNumberFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getBridgeInfo',
+			call: 'fsn_getBridgeInfo',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getBridgeDeposit',
+			call: 'fsn_getBridgeDeposit',
+			params: 2,
+			inputFormatter: [
+				null,
+				web3._extend.formatters.inputDefaultBlockNumberFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getStakeInfo',
+			call: 'fsn_getStakeInfo',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputDefaultBlockNumberFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'isAutoBuyTicket',
+			call: 'fsn_isAutoBuyTicket',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'autoBuyTicketStatus',
+			call: 'fsn_autoBuyTicketStatus',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'autoReportStatus',
+			call: 'fsn_autoReportStatus',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getLatestNotation',
+			call: 'fsn_getLatestNotation',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputDefaultBlockNumberFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getBlockFsnSummary',
+			call: 'fsn_getBlockFsnSummary',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputDefaultBlockNumberFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getRetreatTickets',
+			call: 'fsn_getRetreatTickets',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputDefaultBlockNumberFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'getTicketRetreatRecord',
+			call: 'fsn_getTicketRetreatRecord',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getTicketRetreatReceipt',
+			call: 'fsn_getTicketRetreatReceipt',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getInternalTransfers',
+			call: 'fsn_getInternalTransfers',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getBlockInternalTransfers',
+			call: 'fsn_getBlockInternalTransfers',
+			params: 1
+		}),
+	],
+	properties:[
+		new web3._extend.Property({
+			name: 'coinbase',
+			getter: 'eth_coinbase'
+		}),
+	]
+});
+`
+
+// FsnTxJS wacom
+const FsnTxJS = `
+web3._extend({
+	property: 'fsntx',
+	methods: [
+		new web3._extend.Method({
+			name: 'sendRawTransaction',
+			call: 'fsntx_sendRawTransaction',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'signSponsorTx',
+			call: 'fsntx_signSponsorTx',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getUniqueHash',
+			call: 'fsntx_getUniqueHash',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'checkUniqueHash',
+			call: 'fsntx_checkUniqueHash',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'buildGenNotationTx',
+			call: 'fsntx_buildGenNotationTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'genNotation',
+			call: 'fsntx_genNotation',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildGenAssetTx',
+			call: 'fsntx_buildGenAssetTx',
+			params: 1,
+			inputFormatter: [
+				function(options){
+					if(options.name === undefined || !options.name){
+						throw new Error('invalid name');
+					}
+					if(options.symbol === undefined || !options.symbol){
+						throw new Error('invalid symbol');
+					}
+					if(options.decimals === undefined || options.decimals <= 0 || options.decimals > 255){
+						throw new Error('invalid decimals');
+					}
+					if(options.total !== undefined){
+						options.total = web3.fromDecimal(options.total)
+					}
+					return web3._extend.formatters.inputTransactionFormatter(options)
+				}
+			]
+		}),
+		new web3._extend.Method({
+			name: 'genAsset',
+			call: 'fsntx_genAsset',
+			params: 1,
+			inputFormatter: [
+				function(options){
+					if(options.name === undefined || !options.name){
+						throw new Error('invalid name');
+					}
+					if(options.symbol === undefined || !options.symbol){
+						throw new Error('invalid symbol');
+					}
+					if(options.decimals === undefined || options.decimals <= 0 || options.decimals > 255){
+						throw new Error('invalid decimals');
+					}
+					if(options.total !== undefined){
+						options.total = web3.fromDecimal(options.total)
+					}
+					return web3._extend.formatters.inputTransactionFormatter(options)
+				}
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildSendAssetTx',
+			call: 'fsntx_buildSendAssetTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'sendAsset',
+			call: 'fsntx_sendAsset',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildAssetToTimeLockTx',
+			call: 'fsntx_buildAssetToTimeLockTx',
+			params: 1,
+			inputFormatter: [
+				function(options){
+					return web3._extend.formatters.inputTransactionFormatter(options)
+				}
+			]
+		}),
+		new web3._extend.Method({
+			name: 'assetToTimeLock',
+			call: 'fsntx_assetToTimeLock',
+			params: 1,
+			inputFormatter: [
+				function(options){
+					return web3._extend.formatters.inputTransactionFormatter(options)
+				}
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildTimeLockToTimeLockTx',
+			call: 'fsntx_buildTimeLockToTimeLockTx',
+			params: 1,
+			inputFormatter: [
+				function(options){
+					return web3._extend.formatters.inputTransactionFormatter(options)
+				}
+			]
+		}),
+		new web3._extend.Method({
+			name: 'timeLockToTimeLock',
+			call: 'fsntx_timeLockToTimeLock',
+			params: 1,
+			inputFormatter: [
+				function(options){
+					return web3._extend.formatters.inputTransactionFormatter(options)
+				}
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildTimeLockToAssetTx',
+			call: 'fsntx_buildTimeLockToAssetTx',
+			params: 1,
+			inputFormatter: [
+				function(options){
+					return web3._extend.formatters.inputTransactionFormatter(options)
+				}
+			]
+		}),
+		new web3._extend.Method({
+			name: 'timeLockToAsset',
+			call: 'fsntx_timeLockToAsset',
+			params: 1,
+			inputFormatter: [
+				function(options){
+					return web3._extend.formatters.inputTransactionFormatter(options)
+				}
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildSendTimeLockTx',
+			call: 'fsntx_buildSendTimeLockTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'sendTimeLock',
+			call: 'fsntx_sendTimeLock',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildBuyTicketTx',
+			call: 'fsntx_buildBuyTicketTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buyTicket',
+			call: 'fsntx_buyTicket',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildIncAssetTx',
+			call: 'fsntx_buildIncAssetTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'incAsset',
+			call: 'fsntx_incAsset',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildDecAssetTx',
+			call: 'fsntx_buildDecAssetTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'decAsset',
+			call: 'fsntx_decAsset',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildMakeSwapTx',
+			call: 'fsntx_buildMakeSwapTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'makeSwap',
+			call: 'fsntx_makeSwap',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildRecallSwapTx',
+			call: 'fsntx_buildRecallSwapTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'recallSwap',
+			call: 'fsntx_recallSwap',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildTakeSwapTx',
+			call: 'fsntx_buildTakeSwapTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'takeSwap',
+			call: 'fsntx_takeSwap',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildMakeMultiSwapTx',
+			call: 'fsntx_buildMakeMultiSwapTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'makeMultiSwap',
+			call: 'fsntx_makeMultiSwap',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildRecallMultiSwapTx',
+			call: 'fsntx_buildRecallMultiSwapTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'recallMultiSwap',
+			call: 'fsntx_recallMultiSwap',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildTakeMultiSwapTx',
+			call: 'fsntx_buildTakeMultiSwapTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'takeMultiSwap',
+			call: 'fsntx_takeMultiSwap',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildTypedCallTx',
+			call: 'fsntx_buildTypedCallTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'typedCall',
+			call: 'fsntx_typedCall',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildAssetTransferListTx',
+			call: 'fsntx_buildAssetTransferListTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'setAssetTransferList',
+			call: 'fsntx_setAssetTransferList',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildSetFsnCallFeeTx',
+			call: 'fsntx_buildSetFsnCallFeeTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'setFsnCallFee',
+			call: 'fsntx_setFsnCallFee',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildCreateProposalTx',
+			call: 'fsntx_buildCreateProposalTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'createProposal',
+			call: 'fsntx_createProposal',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildVoteProposalTx',
+			call: 'fsntx_buildVoteProposalTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'voteProposal',
+			call: 'fsntx_voteProposal',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildRevokeTicketTx',
+			call: 'fsntx_buildRevokeTicketTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'revokeTicket',
+			call: 'fsntx_revokeTicket',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildEscrowAssetTx',
+			call: 'fsntx_buildEscrowAssetTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'escrowAsset',
+			call: 'fsntx_escrowAsset',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildClaimEscrowTx',
+			call: 'fsntx_buildClaimEscrowTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'claimEscrow',
+			call: 'fsntx_claimEscrow',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildCreateStreamTx',
+			call: 'fsntx_buildCreateStreamTx',
+			params: 1,
 			inputFormatter: [
-				function(options){
-					if(options.name === undefined || !options.name){
-						throw new Error('invalid name');
-					}
-					if(options.symbol === undefined || !options.symbol){
-						throw new Error('invalid symbol');
-					}
-					if(options.decimals === undefined || options.decimals <= 0 || options.decimals > 255){
-						throw new Error('invalid decimals');
-					}
-					if(options.total !== undefined){
-						options.total = web3.fromDecimal(options.total)
-					}
-					return web3._extend.formatters.inputTransactionFormatter(options)
-				}
+				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'genAsset',
-			call: 'fsntx_genAsset',
+			name: 'createStream',
+			call: 'fsntx_createStream',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildWithdrawStreamTx',
+			call: 'fsntx_buildWithdrawStreamTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'withdrawStream',
+			call: 'fsntx_withdrawStream',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildCreateConditionTx',
+			call: 'fsntx_buildCreateConditionTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'createCondition',
+			call: 'fsntx_createCondition',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildResolveConditionTx',
+			call: 'fsntx_buildResolveConditionTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'resolveCondition',
+			call: 'fsntx_resolveCondition',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildConditionalTransferTx',
+			call: 'fsntx_buildConditionalTransferTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'conditionalTransfer',
+			call: 'fsntx_conditionalTransfer',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildSettleConditionalTx',
+			call: 'fsntx_buildSettleConditionalTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'settleConditional',
+			call: 'fsntx_settleConditional',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildAttestDepositTx',
+			call: 'fsntx_buildAttestDepositTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'attestDeposit',
+			call: 'fsntx_attestDeposit',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildBridgeWithdrawTx',
+			call: 'fsntx_buildBridgeWithdrawTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'bridgeWithdraw',
+			call: 'fsntx_bridgeWithdraw',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildStakingKeyTx',
+			call: 'fsntx_buildStakingKeyTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'setStakingKey',
+			call: 'fsntx_setStakingKey',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildStakingBuyTicketTx',
+			call: 'fsntx_buildStakingBuyTicketTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'stakingBuyTicket',
+			call: 'fsntx_stakingBuyTicket',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'signGenNotationTx',
+			call: 'fsntx_signGenNotationTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'signGenAssetTx',
+			call: 'fsntx_signGenAssetTx',
 			params: 1,
 			inputFormatter: [
 				function(options){
@@ -1391,24 +2550,26 @@ web3._extend({
 			]
 		}),
 		new web3._extend.Method({
-			name: 'buildSendAssetTx',
-			call: 'fsntx_buildSendAssetTx',
+			name: 'signSendAssetTx',
+			call: 'fsntx_signSendAssetTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'sendAsset',
-			call: 'fsntx_sendAsset',
+			name: 'signAssetToTimeLockTx',
+			call: 'fsntx_signAssetToTimeLockTx',
 			params: 1,
 			inputFormatter: [
-				web3._extend.formatters.inputTransactionFormatter
+				function(options){
+					return web3._extend.formatters.inputTransactionFormatter(options)
+				}
 			]
 		}),
 		new web3._extend.Method({
-			name: 'buildAssetToTimeLockTx',
-			call: 'fsntx_buildAssetToTimeLockTx',
+			name: 'signTimeLockToTimeLockTx',
+			call: 'fsntx_signTimeLockToTimeLockTx',
 			params: 1,
 			inputFormatter: [
 				function(options){
@@ -1417,8 +2578,8 @@ web3._extend({
 			]
 		}),
 		new web3._extend.Method({
-			name: 'assetToTimeLock',
-			call: 'fsntx_assetToTimeLock',
+			name: 'signTimeLockToAssetTx',
+			call: 'fsntx_signTimeLockToAssetTx',
 			params: 1,
 			inputFormatter: [
 				function(options){
@@ -1427,205 +2588,247 @@ web3._extend({
 			]
 		}),
 		new web3._extend.Method({
-			name: 'buildTimeLockToTimeLockTx',
-			call: 'fsntx_buildTimeLockToTimeLockTx',
+			name: 'signSendTimeLockTx',
+			call: 'fsntx_signSendTimeLockTx',
 			params: 1,
 			inputFormatter: [
-				function(options){
-					return web3._extend.formatters.inputTransactionFormatter(options)
-				}
+				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'timeLockToTimeLock',
-			call: 'fsntx_timeLockToTimeLock',
+			name: 'signBuyTicketTx',
+			call: 'fsntx_signBuyTicketTx',
 			params: 1,
 			inputFormatter: [
-				function(options){
-					return web3._extend.formatters.inputTransactionFormatter(options)
-				}
+				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'buildTimeLockToAssetTx',
-			call: 'fsntx_buildTimeLockToAssetTx',
+			name: 'signIncAssetTx',
+			call: 'fsntx_signIncAssetTx',
 			params: 1,
 			inputFormatter: [
-				function(options){
-					return web3._extend.formatters.inputTransactionFormatter(options)
-				}
+				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'timeLockToAsset',
-			call: 'fsntx_timeLockToAsset',
+			name: 'signDecAssetTx',
+			call: 'fsntx_signDecAssetTx',
 			params: 1,
 			inputFormatter: [
-				function(options){
-					return web3._extend.formatters.inputTransactionFormatter(options)
-				}
+				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'buildSendTimeLockTx',
-			call: 'fsntx_buildSendTimeLockTx',
+			name: 'signMakeSwapTx',
+			call: 'fsntx_signMakeSwapTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'sendTimeLock',
-			call: 'fsntx_sendTimeLock',
+			name: 'signRecallSwapTx',
+			call: 'fsntx_signRecallSwapTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'buildBuyTicketTx',
-			call: 'fsntx_buildBuyTicketTx',
+			name: 'signTakeSwapTx',
+			call: 'fsntx_signTakeSwapTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'buyTicket',
-			call: 'fsntx_buyTicket',
+			name: 'signMakeMultiSwapTx',
+			call: 'fsntx_signMakeMultiSwapTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'buildIncAssetTx',
-			call: 'fsntx_buildIncAssetTx',
+			name: 'signRecallMultiSwapTx',
+			call: 'fsntx_signRecallMultiSwapTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'incAsset',
-			call: 'fsntx_incAsset',
+			name: 'signTakeMultiSwapTx',
+			call: 'fsntx_signTakeMultiSwapTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'buildDecAssetTx',
-			call: 'fsntx_buildDecAssetTx',
+			name: 'signTypedCallTx',
+			call: 'fsntx_signTypedCallTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'decAsset',
-			call: 'fsntx_decAsset',
+			name: 'signAssetTransferListTx',
+			call: 'fsntx_signAssetTransferListTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'buildMakeSwapTx',
-			call: 'fsntx_buildMakeSwapTx',
+			name: 'signSetFsnCallFeeTx',
+			call: 'fsntx_signSetFsnCallFeeTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'makeSwap',
-			call: 'fsntx_makeSwap',
+			name: 'signCreateProposalTx',
+			call: 'fsntx_signCreateProposalTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'buildRecallSwapTx',
-			call: 'fsntx_buildRecallSwapTx',
+			name: 'signVoteProposalTx',
+			call: 'fsntx_signVoteProposalTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'recallSwap',
-			call: 'fsntx_recallSwap',
+			name: 'signRevokeTicketTx',
+			call: 'fsntx_signRevokeTicketTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'buildTakeSwapTx',
-			call: 'fsntx_buildTakeSwapTx',
+			name: 'signEscrowAssetTx',
+			call: 'fsntx_signEscrowAssetTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'takeSwap',
-			call: 'fsntx_takeSwap',
+			name: 'signClaimEscrowTx',
+			call: 'fsntx_signClaimEscrowTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'buildMakeMultiSwapTx',
-			call: 'fsntx_buildMakeMultiSwapTx',
+			name: 'signCreateStreamTx',
+			call: 'fsntx_signCreateStreamTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'makeMultiSwap',
-			call: 'fsntx_makeMultiSwap',
+			name: 'signWithdrawStreamTx',
+			call: 'fsntx_signWithdrawStreamTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'buildRecallMultiSwapTx',
-			call: 'fsntx_buildRecallMultiSwapTx',
+			name: 'signCreateConditionTx',
+			call: 'fsntx_signCreateConditionTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'recallMultiSwap',
-			call: 'fsntx_recallMultiSwap',
+			name: 'signResolveConditionTx',
+			call: 'fsntx_signResolveConditionTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'buildTakeMultiSwapTx',
-			call: 'fsntx_buildTakeMultiSwapTx',
+			name: 'signConditionalTransferTx',
+			call: 'fsntx_signConditionalTransferTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
 		new web3._extend.Method({
-			name: 'takeMultiSwap',
-			call: 'fsntx_takeMultiSwap',
+			name: 'signSettleConditionalTx',
+			call: 'fsntx_signSettleConditionalTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'signAttestDepositTx',
+			call: 'fsntx_signAttestDepositTx',
 			params: 1,
 			inputFormatter: [
 				web3._extend.formatters.inputTransactionFormatter
 			]
 		}),
+		new web3._extend.Method({
+			name: 'signBridgeWithdrawTx',
+			call: 'fsntx_signBridgeWithdrawTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'signStakingKeyTx',
+			call: 'fsntx_signStakingKeyTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'signStakingBuyTicketTx',
+			call: 'fsntx_signStakingBuyTicketTx',
+			params: 1,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter
+			]
+		}),
+		new web3._extend.Method({
+			name: 'buildReportIllegalTx',
+			call: 'fsntx_buildReportIllegalTx',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
+		}),
+		new web3._extend.Method({
+			name: 'signReportIllegalTx',
+			call: 'fsntx_signReportIllegalTx',
+			params: 2,
+			inputFormatter: [
+				web3._extend.formatters.inputTransactionFormatter,
+				null
+			]
+		}),
 	]
 });
 `
@@ -35,13 +35,16 @@ func NewState(ctx context.Context, head *types.Header, odr OdrBackend) *state.St
 }
 
 func NewStateDatabase(ctx context.Context, head *types.Header, odr OdrBackend) state.Database {
-	return &odrDatabase{ctx, StateTrieID(head), odr}
+	// the database only serves the state of head, so only its ticket set is
+	// cached
+	return &odrDatabase{ctx, StateTrieID(head), odr, state.NewTicketCache(1)}
 }
 
 type odrDatabase struct {
 	ctx     context.Context
 	id      *TrieID
 	backend OdrBackend
+	tickets *state.TicketCache
 }
 
 func (db *odrDatabase) OpenTrie(root common.Hash) (state.Trie, error) {
@@ -89,6 +92,10 @@ func (db *odrDatabase) TrieDB() *trie.Database {
 	return nil
 }
 
+func (db *odrDatabase) TicketCache() *state.TicketCache {
+	return db.tickets
+}
+
 type odrTrie struct {
 	db   *odrDatabase
 	id   *TrieID
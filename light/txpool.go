@@ -380,6 +380,21 @@ func (pool *TxPool) validateTx(ctx context.Context, tx *types.Transaction) error
 		return core.ErrInsufficientFunds
 	}
 
+	// A sponsored transaction is an FSN call whose sponsor pays the gas
+	if tx.Sponsored() {
+		if !common.IsSponsoredTxEnabled(new(big.Int).Add(header.Number, big.NewInt(1))) || !common.IsFsnCall(tx.To()) {
+			return core.ErrSponsoredTx
+		}
+		sponsor, err := types.Sponsor(pool.signer, tx)
+		if err != nil {
+			return core.ErrInvalidSponsor
+		}
+		gasCost := new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas()))
+		if b := currentState.GetBalance(common.SystemAssetID, sponsor); b.Cmp(gasCost) < 0 {
+			return core.ErrInsufficientFunds
+		}
+	}
+
 	// Should supply enough intrinsic gas
 	gas, err := core.IntrinsicGas(tx.Data(), tx.To() == nil, true)
 	if err != nil {
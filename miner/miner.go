@@ -51,6 +51,8 @@ type Config struct {
 	GasPrice  *big.Int       // Minimum gas price for mining a transaction
 	Recommit  time.Duration  // The time interval for miner to re-create mining work.
 	Noverify  bool           // Disable remote mining solution verification(only useful in ethash).
+
+	FsnFeeWeight uint64 // Percentage of the FSN call fee counted with the gas price when ordering transactions (0 = gas price only)
 }
 
 // Miner creates blocks and searches for proof-of-work values.
@@ -32,6 +32,7 @@ import (
 	"github.com/FusionFoundation/go-fusion/core/types"
 	"github.com/FusionFoundation/go-fusion/event"
 	"github.com/FusionFoundation/go-fusion/log"
+	"github.com/FusionFoundation/go-fusion/metrics"
 	"github.com/FusionFoundation/go-fusion/params"
 )
 
@@ -78,6 +79,12 @@ const (
 	maxTimelockTxCount = 50
 )
 
+var (
+	// prepareTimer measures the time spent preparing the header of the sealing
+	// work, which for datong includes the ticket selection unless precomputed.
+	prepareTimer = metrics.NewRegisteredTimer("miner/prepare", nil)
+)
+
 // environment is the worker's current environment and holds all of the current state information.
 type environment struct {
 	signer types.Signer
@@ -149,6 +156,7 @@ type worker struct {
 	exitCh             chan struct{}
 	resubmitIntervalCh chan time.Duration
 	resubmitAdjustCh   chan *intervalAdjust
+	precomputeCh       chan *types.Header // new heads to precompute the datong ticket selection of
 
 	current     *environment       // An environment for current running cycle.
 	unconfirmed *unconfirmedBlocks // A set of locally mined blocks pending canonicalness confirmations.
@@ -199,6 +207,7 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 		startCh:            make(chan struct{}, 1),
 		resubmitIntervalCh: make(chan time.Duration),
 		resubmitAdjustCh:   make(chan *intervalAdjust, resubmitAdjustChanSize),
+		precomputeCh:       make(chan *types.Header, 1),
 	}
 	// Subscribe NewTxsEvent for tx pool
 	worker.txsSub = eth.TxPool().SubscribeNewTxsEvent(worker.txsCh)
@@ -217,6 +226,9 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 	go worker.newWorkLoop(recommit)
 	go worker.resultLoop()
 	go worker.taskLoop()
+	if _, ok := engine.(*datong.DaTong); ok {
+		go worker.precomputeLoop()
+	}
 
 	// Submit first work to initialize pending state.
 	if init {
@@ -281,6 +293,13 @@ func (w *worker) isRunning() bool {
 	return atomic.LoadInt32(&w.running) == 1
 }
 
+// sealsOnDemand returns whether the consensus engine only seals blocks with
+// transactions, as clique and datong do with a 0 period.
+func (w *worker) sealsOnDemand() bool {
+	return (w.chainConfig.Clique != nil && w.chainConfig.Clique.Period == 0) ||
+		(w.chainConfig.DaTong != nil && w.chainConfig.DaTong.Period == 0)
+}
+
 // close terminates all background threads maintained by the worker.
 // Note the worker does not support being closed multiple times.
 func (w *worker) close() {
@@ -348,18 +367,20 @@ func (w *worker) newWorkLoop(recommit time.Duration) {
 		select {
 		case <-w.startCh:
 			clearPending(w.chain.CurrentBlock())
+			w.precompute(w.chain.CurrentHeader())
 			timestamp = time.Now().Unix()
 			commit(false, commitInterruptNewHead)
 
 		case head := <-w.chainHeadCh:
 			clearPending(head.Block)
+			w.precompute(head.Block.Header())
 			timestamp = time.Now().Unix()
 			commit(false, commitInterruptNewHead)
 
 		case <-timer.C:
 			// If mining is running resubmit a new work cycle periodically to pull in
 			// higher priced transactions. Disable this overhead for pending blocks.
-			if w.isRunning() && (w.chainConfig.Clique == nil || w.chainConfig.Clique.Period > 0) {
+			if w.isRunning() && !w.sealsOnDemand() {
 				// Short circuit if no new transaction arrives.
 				if atomic.LoadInt32(&w.newTxs) == 0 {
 					timer.Reset(recommit)
@@ -403,6 +424,39 @@ func (w *worker) newWorkLoop(recommit time.Duration) {
 	}
 }
 
+// precompute schedules the precomputation of the datong ticket selection of
+// the blocks after head, replacing the head not precomputed yet.
+func (w *worker) precompute(head *types.Header) {
+	if !w.isRunning() {
+		return
+	}
+	select {
+	case <-w.precomputeCh:
+	default:
+	}
+	select {
+	case w.precomputeCh <- head:
+	default:
+	}
+}
+
+// precomputeLoop is a standalone goroutine computing the datong ticket
+// selection of the new heads in the background, while the sealing work waits
+// for the minimal block time.
+func (w *worker) precomputeLoop() {
+	dt := w.engine.(*datong.DaTong)
+	for {
+		select {
+		case head := <-w.precomputeCh:
+			if err := dt.PrecomputeSelection(w.chain, head); err != nil {
+				log.Debug("Failed to precompute ticket selection", "number", head.Number, "err", err)
+			}
+		case <-w.exitCh:
+			return
+		}
+	}
+}
+
 // mainLoop is a standalone goroutine to regenerate the sealing task based on the received event.
 func (w *worker) mainLoop() {
 	defer w.txsSub.Unsubscribe()
@@ -449,7 +503,7 @@ func (w *worker) mainLoop() {
 					acc, _ := types.Sender(w.current.signer, tx)
 					txs[acc] = append(txs[acc], tx)
 				}
-				txset := types.NewTransactionsByPriceAndNonce(w.current.signer, txs)
+				txset := w.transactionsByTip(txs)
 				tcount := w.current.tcount
 				w.commitTransactions(txset, coinbase, nil)
 				// Only update the snapshot if any new transactons were added
@@ -458,9 +512,9 @@ func (w *worker) mainLoop() {
 					w.updateSnapshot()
 				}
 			} else {
-				// If clique is running in dev mode(period is 0), disable
-				// advance sealing here.
-				if w.chainConfig.Clique != nil && w.chainConfig.Clique.Period == 0 {
+				// If clique or datong is running in dev mode(period is 0),
+				// disable advance sealing here.
+				if w.sealsOnDemand() {
 					w.commitNewWork(nil, true, time.Now().Unix())
 				}
 			}
@@ -655,6 +709,26 @@ func (w *worker) commitTransaction(tx *types.Transaction, coinbase common.Addres
 	return receipt.Logs, nil
 }
 
+// transactionsByTip sorts txs by their revenue per gas in the current work:
+// the gas price plus the share of the FSN call fee set by FsnFeeWeight, spread
+// over the gas limit of the call.
+func (w *worker) transactionsByTip(txs map[common.Address]types.Transactions) *types.TransactionsByPriceAndNonce {
+	weight := w.config.FsnFeeWeight
+	if weight == 0 {
+		return types.NewTransactionsByPriceAndNonce(w.current.signer, txs)
+	}
+	number, statedb := w.current.header.Number, w.current.state
+	return types.NewTransactionsByTipAndNonce(w.current.signer, txs, func(tx *types.Transaction) *big.Int {
+		fee := core.FsnCallFee(tx, number, statedb)
+		if fee.Sign() == 0 || tx.Gas() == 0 {
+			return tx.GasPrice()
+		}
+		fee.Mul(fee, new(big.Int).SetUint64(weight))
+		fee.Div(fee, new(big.Int).SetUint64(100*tx.Gas()))
+		return fee.Add(fee, tx.GasPrice())
+	})
+}
+
 func (w *worker) commitTransactions(txs *types.TransactionsByPriceAndNonce, coinbase common.Address, interrupt *int32) bool {
 	// Short circuit if current is nil
 	if w.current == nil {
@@ -830,7 +904,10 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 		}
 		header.Coinbase = w.coinbase
 	}
-	if err := w.engine.Prepare(w.chain, header); err != nil {
+	prepareStart := time.Now()
+	err := w.engine.Prepare(w.chain, header)
+	prepareTimer.UpdateSince(prepareStart)
+	if err != nil {
 		switch err {
 		case datong.ErrNoTicket:
 			common.DebugInfo("Miner doesn't have ticket", "number", parent.Number())
@@ -853,7 +930,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 		}
 	}
 	// Could potentially happen if starting to mine in an odd state.
-	err := w.makeCurrent(parent, header)
+	err = w.makeCurrent(parent, header)
 	if err != nil {
 		log.Error("Failed to create mining context", "err", err)
 		return
@@ -890,13 +967,13 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64)
 		}
 	}
 	if len(localTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, localTxs)
+		txs := w.transactionsByTip(localTxs)
 		if w.commitTransactions(txs, w.coinbase, interrupt) {
 			return
 		}
 	}
 	if len(remoteTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, remoteTxs)
+		txs := w.transactionsByTip(remoteTxs)
 		if w.commitTransactions(txs, w.coinbase, interrupt) {
 			return
 		}
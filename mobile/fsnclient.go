@@ -0,0 +1,302 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+// Contains a wrapper for the Fusion specific client methods.
+
+package geth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/fsnclient"
+)
+
+// FusionClient provides access to the Fusion asset, time lock and swap APIs.
+// Signing and sending the FSN calls it builds is left to KeyStore.SignTx and
+// EthereumClient.SendTransaction.
+type FusionClient struct {
+	client *fsnclient.Client
+}
+
+// NewFusionClient connects a client to the given URL.
+func NewFusionClient(rawurl string) (client *FusionClient, _ error) {
+	rawClient, err := fsnclient.Dial(rawurl)
+	return &FusionClient{rawClient}, err
+}
+
+// blockNumber converts a mobile block number, <0 meaning the latest block.
+func blockNumber(number int64) *big.Int {
+	if number < 0 {
+		return nil
+	}
+	return big.NewInt(number)
+}
+
+// GetBalance returns the balance of an asset of the account at the given block
+// number. If number is <0, the latest known block is used.
+func (fc *FusionClient) GetBalance(ctx *Context, assetID *Hash, account *Address, number int64) (balance *BigInt, _ error) {
+	rawBalance, err := fc.client.GetBalance(ctx.context, assetID.hash, account.address, blockNumber(number))
+	return &BigInt{rawBalance}, err
+}
+
+// GetAllBalances returns the balances of all assets of the account at the given
+// block number. If number is <0, the latest known block is used.
+func (fc *FusionClient) GetAllBalances(ctx *Context, account *Address, number int64) (balances *AssetBalances, _ error) {
+	rawBalances, err := fc.client.GetAllBalances(ctx.context, account.address, blockNumber(number))
+	if err != nil {
+		return nil, err
+	}
+	balances = &AssetBalances{
+		assetIDs: make([]common.Hash, 0, len(rawBalances)),
+		balances: make([]*big.Int, 0, len(rawBalances)),
+	}
+	for assetID := range rawBalances {
+		balances.assetIDs = append(balances.assetIDs, assetID)
+	}
+	sort.Slice(balances.assetIDs, func(i, j int) bool {
+		return bytes.Compare(balances.assetIDs[i][:], balances.assetIDs[j][:]) < 0
+	})
+	for _, assetID := range balances.assetIDs {
+		balances.balances = append(balances.balances, rawBalances[assetID])
+	}
+	return balances, nil
+}
+
+// GetTimeLockBalance returns the time locked balance of an asset of the account
+// at the given block number. If number is <0, the latest known block is used.
+func (fc *FusionClient) GetTimeLockBalance(ctx *Context, assetID *Hash, account *Address, number int64) (timelock *TimeLock, _ error) {
+	rawTimeLock, err := fc.client.GetTimeLockBalance(ctx.context, assetID.hash, account.address, blockNumber(number))
+	if err != nil {
+		return nil, err
+	}
+	if rawTimeLock == nil {
+		rawTimeLock = new(common.TimeLock)
+	}
+	return &TimeLock{rawTimeLock}, nil
+}
+
+// GetSwaps returns the open swaps at the given block number, ordered by ID. If
+// number is <0, the latest known block is used.
+func (fc *FusionClient) GetSwaps(ctx *Context, number int64) (swaps *Swaps, _ error) {
+	rawSwaps, err := fc.client.AllSwaps(ctx.context, blockNumber(number))
+	if err != nil {
+		return nil, err
+	}
+	return newSwaps(rawSwaps), nil
+}
+
+// GetSwapsByAddress returns the open swaps of the owner at the given block
+// number, ordered by ID. If number is <0, the latest known block is used.
+func (fc *FusionClient) GetSwapsByAddress(ctx *Context, owner *Address, number int64) (swaps *Swaps, _ error) {
+	rawSwaps, err := fc.client.AllSwapsByAddress(ctx.context, owner.address, blockNumber(number))
+	if err != nil {
+		return nil, err
+	}
+	return newSwaps(rawSwaps), nil
+}
+
+// GetSwap returns the open swap with the given ID at the given block number. If
+// number is <0, the latest known block is used.
+func (fc *FusionClient) GetSwap(ctx *Context, swapID *Hash, number int64) (swap *Swap, _ error) {
+	rawSwap, err := fc.client.GetSwap(ctx.context, swapID.hash, blockNumber(number), false)
+	if err != nil {
+		return nil, err
+	}
+	return &Swap{rawSwap.Swap}, nil
+}
+
+// BuildFSNCall builds the unsigned FSN call fn sent from the given account, e.g.
+// "sendAsset" or "buyTicket". The arguments of the call are given as the JSON
+// object taken by the fsntx API, the nonce, gas and gas price left out of it are
+// filled in from the node.
+func (fc *FusionClient) BuildFSNCall(ctx *Context, from *Address, fn string, args string) (tx *Transaction, _ error) {
+	callArgs, err := newCallArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	callArgs.From = from.address
+	rawTx, err := fc.client.PrepareCall(ctx.context, fn, callArgs)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{rawTx}, nil
+}
+
+// BuildFSNCallOffline builds the unsigned FSN call fn without a node. The JSON
+// arguments must contain the nonce, gas and gas price of the transaction, now is
+// the unix time the time locked values are checked against.
+func BuildFSNCallOffline(fn string, args string, now int64) (tx *Transaction, _ error) {
+	callArgs, err := newCallArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	rawTx, err := fsnclient.BuildCall(fn, callArgs, uint64(now))
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{rawTx}, nil
+}
+
+// callArgs are the JSON arguments of an FSN call of any kind, passed on to the
+// call builder with the base arguments filled in.
+type callArgs struct {
+	common.FusionBaseArgs
+	fields map[string]json.RawMessage
+}
+
+func newCallArgs(args string) (*callArgs, error) {
+	callArgs := new(callArgs)
+	if err := json.Unmarshal([]byte(args), &callArgs.fields); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(args), &callArgs.FusionBaseArgs); err != nil {
+		return nil, err
+	}
+	return callArgs, nil
+}
+
+// ToData is not supported, the builder encodes the call from the JSON.
+func (args *callArgs) ToData() ([]byte, error) {
+	return nil, errors.New("call data of untyped arguments")
+}
+
+// MarshalJSON returns the JSON arguments with the base arguments set.
+func (args *callArgs) MarshalJSON() ([]byte, error) {
+	base, err := json.Marshal(&args.FusionBaseArgs)
+	if err != nil {
+		return nil, err
+	}
+	var baseFields map[string]json.RawMessage
+	if err := json.Unmarshal(base, &baseFields); err != nil {
+		return nil, err
+	}
+	fields := make(map[string]json.RawMessage, len(args.fields)+len(baseFields))
+	for name, value := range args.fields {
+		fields[name] = value
+	}
+	for name, value := range baseFields {
+		fields[name] = value
+	}
+	return json.Marshal(fields)
+}
+
+// AssetBalances represents the balances of the assets of an account, ordered
+// by asset ID.
+type AssetBalances struct {
+	assetIDs []common.Hash
+	balances []*big.Int
+}
+
+// Size returns the number of assets in the balances.
+func (ab *AssetBalances) Size() int {
+	return len(ab.assetIDs)
+}
+
+// GetAssetID returns the asset ID at the given index.
+func (ab *AssetBalances) GetAssetID(index int) (assetID *Hash, _ error) {
+	if index < 0 || index >= len(ab.assetIDs) {
+		return nil, errors.New("index out of bounds")
+	}
+	return &Hash{ab.assetIDs[index]}, nil
+}
+
+// GetBalance returns the balance of the asset at the given index.
+func (ab *AssetBalances) GetBalance(index int) (balance *BigInt, _ error) {
+	if index < 0 || index >= len(ab.balances) {
+		return nil, errors.New("index out of bounds")
+	}
+	return &BigInt{ab.balances[index]}, nil
+}
+
+// TimeLock represents the time locked balance of an asset.
+type TimeLock struct {
+	timelock *common.TimeLock
+}
+
+// Size returns the number of items in the time lock.
+func (tl *TimeLock) Size() int {
+	return len(tl.timelock.Items)
+}
+
+// Get returns the time lock item at the given index.
+func (tl *TimeLock) Get(index int) (item *TimeLockItem, _ error) {
+	if index < 0 || index >= len(tl.timelock.Items) {
+		return nil, errors.New("index out of bounds")
+	}
+	return &TimeLockItem{tl.timelock.Items[index]}, nil
+}
+
+// TimeLockItem represents a value locked between a start and an end time.
+type TimeLockItem struct {
+	item *common.TimeLockItem
+}
+
+func (tli *TimeLockItem) GetStartTime() int64 { return int64(tli.item.StartTime) }
+func (tli *TimeLockItem) GetEndTime() int64   { return int64(tli.item.EndTime) }
+func (tli *TimeLockItem) GetValue() *BigInt   { return &BigInt{tli.item.Value} }
+
+// Swap represents an open swap of assets.
+type Swap struct {
+	swap common.Swap
+}
+
+func (s *Swap) GetID() *Hash              { return &Hash{s.swap.ID} }
+func (s *Swap) GetOwner() *Address        { return &Address{s.swap.Owner} }
+func (s *Swap) GetFromAssetID() *Hash     { return &Hash{s.swap.FromAssetID} }
+func (s *Swap) GetFromStartTime() int64   { return int64(s.swap.FromStartTime) }
+func (s *Swap) GetFromEndTime() int64     { return int64(s.swap.FromEndTime) }
+func (s *Swap) GetMinFromAmount() *BigInt { return &BigInt{s.swap.MinFromAmount} }
+func (s *Swap) GetToAssetID() *Hash       { return &Hash{s.swap.ToAssetID} }
+func (s *Swap) GetToStartTime() int64     { return int64(s.swap.ToStartTime) }
+func (s *Swap) GetToEndTime() int64       { return int64(s.swap.ToEndTime) }
+func (s *Swap) GetMinToAmount() *BigInt   { return &BigInt{s.swap.MinToAmount} }
+func (s *Swap) GetSwapSize() *BigInt      { return &BigInt{s.swap.SwapSize} }
+func (s *Swap) GetTargets() *Addresses    { return &Addresses{s.swap.Targes} }
+func (s *Swap) GetTime() *BigInt          { return &BigInt{s.swap.Time} }
+func (s *Swap) GetDescription() string    { return s.swap.Description }
+func (s *Swap) GetNotation() int64        { return int64(s.swap.Notation) }
+
+// Swaps represents a slice of swaps.
+type Swaps struct{ swaps []common.Swap }
+
+func newSwaps(rawSwaps map[common.Hash]*fsnclient.RPCSwap) *Swaps {
+	swaps := &Swaps{make([]common.Swap, 0, len(rawSwaps))}
+	for _, swap := range rawSwaps {
+		swaps.swaps = append(swaps.swaps, swap.Swap)
+	}
+	sort.Slice(swaps.swaps, func(i, j int) bool {
+		return bytes.Compare(swaps.swaps[i].ID[:], swaps.swaps[j].ID[:]) < 0
+	})
+	return swaps
+}
+
+// Size returns the number of swaps in the slice.
+func (s *Swaps) Size() int {
+	return len(s.swaps)
+}
+
+// Get returns the swap at the given index from the slice.
+func (s *Swaps) Get(index int) (swap *Swap, _ error) {
+	if index < 0 || index >= len(s.swaps) {
+		return nil, errors.New("index out of bounds")
+	}
+	return &Swap{s.swaps[index]}, nil
+}
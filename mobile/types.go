@@ -22,8 +22,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core"
 	"github.com/FusionFoundation/go-fusion/core/types"
 	"github.com/FusionFoundation/go-fusion/rlp"
 	whisper "github.com/FusionFoundation/go-fusion/whisper/whisperv6"
@@ -254,6 +256,13 @@ func (tx *Transaction) GetNonce() int64      { return int64(tx.tx.Nonce()) }
 func (tx *Transaction) GetHash() *Hash   { return &Hash{tx.tx.Hash()} }
 func (tx *Transaction) GetCost() *BigInt { return &BigInt{tx.tx.Cost()} }
 
+// GetUniqueHash returns the hash the IDs of the assets and swaps created by the
+// transaction sent by from are derived from when it is included in the block of
+// the given number, known before the transaction is signed.
+func (tx *Transaction) GetUniqueHash(from *Address, number int64) *Hash {
+	return &Hash{core.GetUniqueHashAt(tx.tx, from.address, big.NewInt(number))}
+}
+
 // Deprecated: GetSigHash cannot know which signer to use.
 func (tx *Transaction) GetSigHash() *Hash { return &Hash{types.HomesteadSigner{}.Hash(tx.tx)} }
 
@@ -162,6 +162,10 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// RPCPolicy limits the rate and the concurrency of the calls of each client
+	// served by the HTTP and websocket RPC interfaces, nil serves every call.
+	RPCPolicy *rpc.PolicyConfig `toml:",omitempty"`
+
 	// GraphQLHost is the host interface on which to start the GraphQL server. If this
 	// field is empty, no GraphQL API endpoint will be started.
 	GraphQLHost string `toml:",omitempty"`
@@ -300,9 +300,34 @@ func (tab *Table) doRefresh(done chan struct{}) {
 	}
 }
 
+// addFallbackNodes adds bootstrap nodes found after the table started, e.g.
+// from a DNS tree. They are seeded at once and kept for later refreshes.
+func (tab *Table) addFallbackNodes(nodes []*enode.Node) {
+	var added []*node
+	for _, n := range nodes {
+		if err := n.ValidateComplete(); err != nil {
+			tab.log.Debug("Skipping bad bootstrap node", "node", n, "err", err)
+			continue
+		}
+		added = append(added, wrapNode(n))
+	}
+	if len(added) == 0 {
+		return
+	}
+	tab.mutex.Lock()
+	tab.nursery = append(tab.nursery[:len(tab.nursery):len(tab.nursery)], added...)
+	tab.mutex.Unlock()
+	for _, n := range added {
+		tab.addSeenNode(n)
+	}
+	tab.refresh()
+}
+
 func (tab *Table) loadSeedNodes() {
 	seeds := wrapNodes(tab.db.QuerySeeds(seedCount, seedMaxAge))
+	tab.mutex.Lock()
 	seeds = append(seeds, tab.nursery...)
+	tab.mutex.Unlock()
 	for i := range seeds {
 		seed := seeds[i]
 		age := log.Lazy{Fn: func() interface{} { return time.Since(tab.db.LastPongReceived(seed.ID(), seed.IP())) }}
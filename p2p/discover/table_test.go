@@ -370,6 +370,30 @@ func TestTable_addSeenNode(t *testing.T) {
 
 // This test checks that ENR updates happen during revalidation. If a node in the table
 // announces a new sequence number, the new record should be pulled.
+// This test checks that bootstrap nodes added after the start are seeded and
+// kept for the later refreshes, the incomplete ones are skipped.
+func TestTable_addFallbackNodes(t *testing.T) {
+	tab, db := newTestTable(newPingRecorder())
+	<-tab.initDone
+	defer db.Close()
+	defer tab.close()
+
+	key, _ := crypto.GenerateKey()
+	n := enode.NewV4(&key.PublicKey, net.IP{88, 77, 66, 1}, 30303, 30303)
+	incomplete := unwrapNode(nodeAtDistance(tab.self().ID(), 256, net.IP{88, 77, 66, 2}))
+	tab.addFallbackNodes([]*enode.Node{incomplete, n})
+
+	tab.mutex.Lock()
+	nursery := unwrapNodes(tab.nursery)
+	tab.mutex.Unlock()
+	if len(nursery) != 1 || nursery[0].ID() != n.ID() {
+		t.Fatalf("wrong bootstrap nodes: %v", nursery)
+	}
+	if tab.getNode(n.ID()) == nil {
+		t.Error("bootstrap node not added to the table")
+	}
+}
+
 func TestTable_revalidateSyncRecord(t *testing.T) {
 	transport := newPingRecorder()
 	tab, db := newTestTable(transport)
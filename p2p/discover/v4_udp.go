@@ -303,6 +303,12 @@ func (t *UDPv4) Close() {
 	})
 }
 
+// AddBootnodes adds bootstrap nodes to the table after it started, e.g. the
+// nodes of a DNS tree resolved in the background.
+func (t *UDPv4) AddBootnodes(nodes []*enode.Node) {
+	t.tab.addFallbackNodes(nodes)
+}
+
 // Resolve searches for a specific node with the given ID and tries to get the most recent
 // version of the node record for it. It returns n if the node could not be resolved.
 func (t *UDPv4) Resolve(n *enode.Node) *enode.Node {
@@ -36,6 +36,7 @@ import (
 	"github.com/FusionFoundation/go-fusion/log"
 	"github.com/FusionFoundation/go-fusion/p2p/discover"
 	"github.com/FusionFoundation/go-fusion/p2p/discv5"
+	"github.com/FusionFoundation/go-fusion/p2p/dnsdisc"
 	"github.com/FusionFoundation/go-fusion/p2p/enode"
 	"github.com/FusionFoundation/go-fusion/p2p/enr"
 	"github.com/FusionFoundation/go-fusion/p2p/nat"
@@ -107,6 +108,11 @@ type Config struct {
 	// protocol.
 	BootstrapNodesV5 []*discv5.Node `toml:",omitempty"`
 
+	// BootstrapDNS are EIP-1459 node tree URLs (enrtree://...) which are
+	// resolved on start, their nodes are used as bootstrap nodes in
+	// addition to BootstrapNodes.
+	BootstrapDNS []string `toml:",omitempty"`
+
 	// Static nodes are used as pre-configured connections which are always
 	// maintained and re-connected on disconnects.
 	StaticNodes []*enode.Node
@@ -533,6 +539,31 @@ func (srv *Server) setupLocalNode() error {
 	return nil
 }
 
+// resolveBootstrapDNS downloads the node trees of BootstrapDNS in the
+// background and adds their nodes to the discovery table, so that a slow or
+// unreachable DNS server does not hold up the start of the server. A tree
+// which cannot be resolved is skipped, the static bootstrap nodes are still
+// used.
+func (srv *Server) resolveBootstrapDNS(ntab *discover.UDPv4) {
+	defer srv.loopWG.Done()
+
+	client := dnsdisc.NewClient(dnsdisc.Config{Logger: srv.log})
+	for _, url := range srv.BootstrapDNS {
+		select {
+		case <-srv.quit:
+			return
+		default:
+		}
+		tree, err := client.SyncTree(url)
+		if err != nil {
+			srv.log.Warn("Failed to resolve DNS bootstrap nodes", "url", url, "err", err)
+			continue
+		}
+		srv.log.Debug("Resolved DNS bootstrap nodes", "url", url, "nodes", len(tree.Nodes()))
+		ntab.AddBootnodes(tree.Nodes())
+	}
+}
+
 func (srv *Server) setupDiscovery() error {
 	srv.discmix = enode.NewFairMix(discmixTimeout)
 
@@ -588,6 +619,10 @@ func (srv *Server) setupDiscovery() error {
 		}
 		srv.ntab = ntab
 		srv.discmix.AddSource(ntab.RandomNodes())
+		if len(srv.BootstrapDNS) > 0 {
+			srv.loopWG.Add(1)
+			go srv.resolveBootstrapDNS(ntab)
+		}
 	}
 
 	// Discovery V5
@@ -64,6 +64,18 @@ var GoerliBootnodes = []string{
 // experimental RLPx v5 topic-discovery network.
 var DiscoveryV5Bootnodes = []string{}
 
-// These DNS names provide bootstrap connectivity for public testnets and the mainnet.
+// KnownDNSNetworks are the EIP-1459 DNS trees (enrtree://<key>@<domain>) of the
+// networks by genesis hash, used for both --discovery.dns and --bootnodes.dns
+// unless overridden. A network without an entry has no DNS default, its trees
+// have to be set by the operator with the flags or the config file
+// ([Eth] DiscoveryURLs and [Node.P2P] BootstrapDNS).
 // See https://github.com/ethereum/discv4-dns-lists for more information.
-var KnownDNSNetworks = map[common.Hash]string{}
+var KnownDNSNetworks = map[common.Hash]string{
+	RinkebyGenesisHash: ethDNSPrefix + "all.rinkeby.ethdisco.net",
+	GoerliGenesisHash:  ethDNSPrefix + "all.goerli.ethdisco.net",
+	// The Fusion mainnet and testnet trees are added here once their signing
+	// keys are published, until then the static bootnodes are used.
+}
+
+// ethDNSPrefix is the signing key of the Ethereum Foundation node trees
+const ethDNSPrefix = "enrtree://AKA3AM6LPBYEUDMVNU3BSVQJ5AD45Y7YPOHJLEF6W26QOE4VTUDPE@"
@@ -69,6 +69,12 @@ var (
 		MuirGlacierBlock:    nil,
 		DaTong: &DaTongConfig{
 			Period: 15,
+			// vote1 fork, the hacked accounts are drained to the refund address
+			Recoveries: []*AssetRecovery{
+				{Height: 786000, From: common.HexToAddress("0xb66cce16736feb5a50a9883675708027d3427c3c"), To: common.HexToAddress("0xff948d492c31814dEde4CAA0af8824eF02Eb48D2")},
+				{Height: 786000, From: common.HexToAddress("0x782da6fb0562074ec21942a7829064a8c2bb05c4"), To: common.HexToAddress("0xff948d492c31814dEde4CAA0af8824eF02Eb48D2")},
+				{Height: 786000, From: common.HexToAddress("0x6deed6878d062cd8754b3284306be814b215e332"), To: common.HexToAddress("0xff948d492c31814dEde4CAA0af8824eF02Eb48D2")},
+			},
 		},
 	}
 
@@ -110,6 +116,12 @@ var (
 		MuirGlacierBlock:    nil,
 		DaTong: &DaTongConfig{
 			Period: 15,
+			// vote1 fork, the hacked accounts are drained to the refund address
+			Recoveries: []*AssetRecovery{
+				{Height: 646700, From: common.HexToAddress("0x07f35aba9555a532c0edc2bd6350c891b6f2c8d0"), To: common.HexToAddress("0xf97a9980808a2cae0d09ff693f02a4f80abb22c4")},
+				{Height: 646700, From: common.HexToAddress("0x3dfaef310a1044fd7d96750b42b44cf3775c00bf"), To: common.HexToAddress("0xf97a9980808a2cae0d09ff693f02a4f80abb22c4")},
+				{Height: 646700, From: common.HexToAddress("0x32095bb7f699a139036d68defd4f12b682795fb6"), To: common.HexToAddress("0xf97a9980808a2cae0d09ff693f02a4f80abb22c4")},
+			},
 		},
 	}
 
@@ -345,7 +357,76 @@ func (c *CliqueConfig) String() string {
 
 // DaTongConfig is the consensus engine configs for proof-of-stake based sealing.
 type DaTongConfig struct {
-	Period uint64 `json:"period"`
+	Period          uint64           `json:"period"`
+	Recoveries      []*AssetRecovery `json:"recoveries,omitempty"`      // hard fork asset recoveries, applied in order
+	TicketRateLimit *TicketRateLimit `json:"ticketRateLimit,omitempty"` // tickets an account can buy per window, from the hard fork
+	MinTickets      uint64           `json:"minTickets,omitempty"`      // ticket pool size below which blocks spend no tickets, from the hard fork
+	LowTicketPeriod uint64           `json:"lowTicketPeriod,omitempty"` // block period while the ticket pool is below MinTickets
+}
+
+// TicketRateLimit limits the tickets bought by one account in a window of
+// blocks. Windows are aligned on multiples of Window.
+type TicketRateLimit struct {
+	Window     uint64 `json:"window"`     // number of blocks of a window
+	MaxTickets uint64 `json:"maxTickets"` // tickets one account can buy per window
+}
+
+// WindowOf returns the index of the window containing block number.
+func (l *TicketRateLimit) WindowOf(number uint64) uint64 {
+	return number / l.Window
+}
+
+// AssetRecovery is a hard fork directive moving everything owned by From,
+// its balances, time locks and tickets, to To and burning its notation at
+// the end of block Height.
+type AssetRecovery struct {
+	Height uint64         `json:"height"`
+	From   common.Address `json:"from"`
+	To     common.Address `json:"to"`
+}
+
+// RecoveriesAt returns the asset recoveries of block number in config order.
+func (c *DaTongConfig) RecoveriesAt(number uint64) []*AssetRecovery {
+	var recoveries []*AssetRecovery
+	for _, r := range c.Recoveries {
+		if r.Height == number {
+			recoveries = append(recoveries, r)
+		}
+	}
+	return recoveries
+}
+
+// CheckRecoveries validates the asset recovery directives.
+func (c *DaTongConfig) CheckRecoveries() error {
+	seen := make(map[AssetRecovery]bool)
+	for i, r := range c.Recoveries {
+		switch {
+		case r == nil:
+			return fmt.Errorf("asset recovery %d: empty directive", i)
+		case r.Height == 0:
+			return fmt.Errorf("asset recovery %d: can not apply to the genesis block", i)
+		case r.From == r.To:
+			return fmt.Errorf("asset recovery %d: from and to are both %v", i, r.From.Hex())
+		case r.From == (common.Address{}) || r.To == (common.Address{}):
+			return fmt.Errorf("asset recovery %d: empty address", i)
+		case r.From == common.FSNCallAddress || r.From.IsSpecialKeyAddress(),
+			r.To == common.FSNCallAddress || r.To.IsSpecialKeyAddress():
+			return fmt.Errorf("asset recovery %d: system address", i)
+		}
+		key := AssetRecovery{Height: r.Height, From: r.From}
+		if seen[key] {
+			return fmt.Errorf("asset recovery %d: %v recovered twice in block %d", i, r.From.Hex(), r.Height)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// IsLowTicketPool reports whether a pool of tickets is below the minimum viable
+// pool size at block number. Blocks of a low pool spend neither the selected
+// nor the retreat tickets, so that the pool does not run out.
+func (c *DaTongConfig) IsLowTicketPool(number *big.Int, tickets uint64) bool {
+	return c != nil && tickets < c.MinTickets && common.IsMinTicketGuardEnabled(number)
 }
 
 // String implements the stringer interface, returning the consensus engine details.
@@ -353,6 +434,15 @@ func (c *DaTongConfig) String() string {
 	return "datong"
 }
 
+// TicketRateLimitAt returns the ticket purchase rate limit applying to block
+// number, or nil if purchases are not limited.
+func (c *ChainConfig) TicketRateLimitAt(number *big.Int) *TicketRateLimit {
+	if c.DaTong == nil || c.DaTong.TicketRateLimit == nil || !common.IsTicketRateLimitEnabled(number) {
+		return nil
+	}
+	return c.DaTong.TicketRateLimit
+}
+
 // String implements the fmt.Stringer interface.
 func (c *ChainConfig) String() string {
 	var engine interface{}
@@ -478,7 +568,8 @@ func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64) *Confi
 }
 
 // CheckConfigForkOrder checks that we don't "skip" any forks, geth isn't pluggable enough
-// to guarantee that forks can be implemented in a different order than on official networks
+// to guarantee that forks can be implemented in a different order than on official networks.
+// The hard fork asset recoveries are validated as well.
 func (c *ChainConfig) CheckConfigForkOrder() error {
 	type fork struct {
 		name  string
@@ -511,6 +602,17 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 		}
 		lastFork = cur
 	}
+	if c.DaTong != nil {
+		if err := c.DaTong.CheckRecoveries(); err != nil {
+			return err
+		}
+		if l := c.DaTong.TicketRateLimit; l != nil && (l.Window == 0 || l.MaxTickets == 0) {
+			return fmt.Errorf("invalid ticket rate limit: window %d, max tickets %d", l.Window, l.MaxTickets)
+		}
+		if c.DaTong.LowTicketPeriod != 0 && c.DaTong.LowTicketPeriod < c.DaTong.Period {
+			return fmt.Errorf("low ticket period %d shorter than the block period %d", c.DaTong.LowTicketPeriod, c.DaTong.Period)
+		}
+	}
 	return nil
 }
 
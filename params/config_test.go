@@ -20,6 +20,8 @@ import (
 	"math/big"
 	"reflect"
 	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
 )
 
 func TestCheckCompatible(t *testing.T) {
@@ -79,3 +81,74 @@ func TestCheckCompatible(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckRecoveries(t *testing.T) {
+	a, b := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	tests := []struct {
+		recoveries []*AssetRecovery
+		ok         bool
+	}{
+		{MainnetChainConfig.DaTong.Recoveries, true},
+		{TestnetChainConfig.DaTong.Recoveries, true},
+		{[]*AssetRecovery{{Height: 10, From: a, To: b}, {Height: 11, From: a, To: b}}, true},
+		{[]*AssetRecovery{{Height: 0, From: a, To: b}}, false},
+		{[]*AssetRecovery{{Height: 10, From: a, To: a}}, false},
+		{[]*AssetRecovery{{Height: 10, From: a}}, false},
+		{[]*AssetRecovery{{Height: 10, From: a, To: common.TicketKeyAddress}}, false},
+		{[]*AssetRecovery{{Height: 10, From: a, To: b}, {Height: 10, From: a, To: b}}, false},
+	}
+	for i, test := range tests {
+		err := (&DaTongConfig{Recoveries: test.recoveries}).CheckRecoveries()
+		if (err == nil) != test.ok {
+			t.Errorf("test %d: error mismatch: have %v, want ok %v", i, err, test.ok)
+		}
+	}
+}
+
+func TestTicketRateLimit(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	limit := &TicketRateLimit{Window: 100, MaxTickets: 5}
+	config := *TestChainConfig
+	config.DaTong = &DaTongConfig{TicketRateLimit: limit}
+	if have := config.TicketRateLimitAt(big.NewInt(1)); have != limit {
+		t.Errorf("rate limit mismatch: have %v, want %v", have, limit)
+	}
+	if w := limit.WindowOf(199); w != 1 {
+		t.Errorf("window mismatch: have %d, want 1", w)
+	}
+	if err := config.CheckConfigForkOrder(); err != nil {
+		t.Errorf("valid rate limit rejected: %v", err)
+	}
+	config.DaTong.TicketRateLimit = &TicketRateLimit{Window: 0, MaxTickets: 5}
+	if err := config.CheckConfigForkOrder(); err == nil {
+		t.Errorf("rate limit without window accepted")
+	}
+	config.DaTong = &DaTongConfig{}
+	if have := config.TicketRateLimitAt(big.NewInt(1)); have != nil {
+		t.Errorf("rate limit without config: have %v, want nil", have)
+	}
+}
+
+func TestLowTicketPool(t *testing.T) {
+	common.UseDevnetRule = true
+	defer func() { common.UseDevnetRule = false }()
+
+	var nilConfig *DaTongConfig
+	if nilConfig.IsLowTicketPool(big.NewInt(1), 0) {
+		t.Errorf("pool low without config")
+	}
+	config := *TestChainConfig
+	config.DaTong = &DaTongConfig{Period: 15, MinTickets: 10, LowTicketPeriod: 60}
+	if !config.DaTong.IsLowTicketPool(big.NewInt(1), 9) || config.DaTong.IsLowTicketPool(big.NewInt(1), 10) {
+		t.Errorf("minimum ticket pool mismatch")
+	}
+	if err := config.CheckConfigForkOrder(); err != nil {
+		t.Errorf("valid minimum ticket pool rejected: %v", err)
+	}
+	config.DaTong.LowTicketPeriod = 5
+	if err := config.CheckConfigForkOrder(); err == nil {
+		t.Errorf("low ticket period shorter than the block period accepted")
+	}
+}
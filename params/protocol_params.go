@@ -86,7 +86,19 @@ const (
 	Bn256PairingBaseGas     uint64 = 100000 // Base price for an elliptic curve pairing check
 	Bn256PairingPerPointGas uint64 = 80000  // Per-point price for an elliptic curve pairing check
 
-	FsnContractGas uint64 = 10000
+	FsnContractGas             uint64 = 10000
+	FsnContractBatchItemGas    uint64 = 5000 // Per-item price of a batch send asset
+	FsnContractTimeLockItemGas uint64 = 400  // Per time lock segment price of a send asset, since the FSN contract gas schedule fork
+
+	UsanContractReadGas  uint64 = 2000  // Price of a USAN contract query
+	UsanContractWriteGas uint64 = 30000 // Price of a USAN contract transfer or approval
+
+	BtcSpvBaseGas      uint64 = 3000 // Base price of a Bitcoin SPV proof verification
+	BtcSpvPerHeaderGas uint64 = 1500 // Per-header price of a Bitcoin SPV proof verification
+	BtcSpvPerProofGas  uint64 = 300  // Per Merkle branch hash price of a Bitcoin SPV proof verification
+
+	EthProofBaseGas    uint64 = 5000 // Base price of an Ethereum state proof verification
+	EthProofPerWordGas uint64 = 30   // Per-word price of an Ethereum state proof verification
 )
 
 var (
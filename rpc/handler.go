@@ -327,6 +327,13 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 	if err != nil {
 		return msg.errorResponse(&invalidParamsError{err.Error()})
 	}
+	if policy := h.reg.callPolicy(); policy != nil && !msg.isUnsubscribe() {
+		release, err := policy.acquire(h.conn.remoteAddr(), msg.Method)
+		if err != nil {
+			return msg.errorResponse(err)
+		}
+		defer release()
+	}
 
 	return h.runMethod(cp.ctx, msg, callb, args)
 }
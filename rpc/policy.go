@@ -0,0 +1,150 @@
+// Copyright 2020 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+	"golang.org/x/time/rate"
+)
+
+// MethodClass is the cost class of an RPC method
+type MethodClass int
+
+const (
+	// ClassCheap is the class of the methods not listed as expensive
+	ClassCheap MethodClass = iota
+	// ClassExpensive is the class of the methods reading large parts of the
+	// state or of the chain
+	ClassExpensive
+
+	numMethodClasses
+)
+
+func (c MethodClass) String() string {
+	if c == ClassExpensive {
+		return "expensive"
+	}
+	return "cheap"
+}
+
+// ClassLimits limits the calls of the methods of one class, zero values
+// are unlimited
+type ClassLimits struct {
+	Rate        float64 // calls per second, with a burst of one second of calls
+	Concurrency int     // calls running at the same time
+}
+
+// PolicyConfig configures the limits of the calls served by a server
+type PolicyConfig struct {
+	Cheap            ClassLimits
+	Expensive        ClassLimits
+	ExpensiveMethods []string // full method names, e.g. fsn_allTickets
+}
+
+// maxPolicyClients is the number of clients whose limits are tracked, the
+// least recently seen client starts over with fresh limits
+const maxPolicyClients = 4096
+
+// callPolicy enforces a PolicyConfig per client. Calls over the limits are
+// rejected instead of queued, so that slow methods can not pile up.
+type callPolicy struct {
+	config    PolicyConfig
+	expensive map[string]bool
+	clients   *lru.Cache // client host -> *clientLimits
+	mu        sync.Mutex // guards the creation of client limits
+}
+
+// clientLimits are the limiters and the call slots of one client
+type clientLimits struct {
+	limiters [numMethodClasses]*rate.Limiter
+	slots    [numMethodClasses]chan struct{}
+}
+
+func newCallPolicy(config *PolicyConfig) *callPolicy {
+	p := &callPolicy{config: *config, expensive: make(map[string]bool)}
+	for _, method := range config.ExpensiveMethods {
+		p.expensive[method] = true
+	}
+	p.clients, _ = lru.New(maxPolicyClients)
+	return p
+}
+
+func (p *callPolicy) newClientLimits() *clientLimits {
+	l := new(clientLimits)
+	for class, limits := range [numMethodClasses]ClassLimits{p.config.Cheap, p.config.Expensive} {
+		if limits.Rate > 0 {
+			burst := int(limits.Rate)
+			if burst < 1 {
+				burst = 1
+			}
+			l.limiters[class] = rate.NewLimiter(rate.Limit(limits.Rate), burst)
+		}
+		if limits.Concurrency > 0 {
+			l.slots[class] = make(chan struct{}, limits.Concurrency)
+		}
+	}
+	return l
+}
+
+// client returns the limits of the client at remote, the clients are told
+// apart by host so that the connections of a client share its limits
+func (p *callPolicy) client(remote string) *clientLimits {
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if l, ok := p.clients.Get(remote); ok {
+		return l.(*clientLimits)
+	}
+	l := p.newClientLimits()
+	p.clients.Add(remote, l)
+	return l
+}
+
+// class returns the class of a method
+func (p *callPolicy) class(method string) MethodClass {
+	if p.expensive[method] {
+		return ClassExpensive
+	}
+	return ClassCheap
+}
+
+// acquire admits a call of method by the client at remote, the returned
+// function must be called when the call is done.
+func (p *callPolicy) acquire(remote, method string) (func(), error) {
+	class := p.class(method)
+	client := p.client(remote)
+	if limiter := client.limiters[class]; limiter != nil && !limiter.Allow() {
+		return nil, &limitExceededError{fmt.Sprintf("rate limit of %s methods exceeded", class)}
+	}
+	slots := client.slots[class]
+	if slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	default:
+		return nil, &limitExceededError{fmt.Sprintf("too many concurrent %s calls", class)}
+	}
+}
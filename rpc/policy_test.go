@@ -0,0 +1,100 @@
+// Copyright 2020 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCallPolicy(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	server.SetPolicy(&PolicyConfig{
+		Cheap:            ClassLimits{Rate: 2},
+		Expensive:        ClassLimits{Concurrency: 1},
+		ExpensiveMethods: []string{"test_sleep"},
+	})
+	client := DialInProc(server)
+	defer client.Close()
+
+	isLimited := func(err error) bool {
+		e, ok := err.(Error)
+		return ok && e.ErrorCode() == -32005
+	}
+	// the expensive class admits one call at a time
+	done := make(chan error)
+	go func() { done <- client.Call(nil, "test_sleep", 200*time.Millisecond) }()
+	time.Sleep(50 * time.Millisecond)
+	if err := client.Call(nil, "test_sleep", 0); !isLimited(err) {
+		t.Errorf("concurrent expensive call not limited: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("expensive call failed: %v", err)
+	}
+	if err := client.Call(nil, "test_sleep", 0); err != nil {
+		t.Errorf("expensive call after the first one failed: %v", err)
+	}
+
+	// the cheap class admits a burst of two calls
+	for i := 0; i < 2; i++ {
+		if err := client.Call(nil, "test_rets"); err != nil {
+			t.Fatalf("cheap call %d failed: %v", i, err)
+		}
+	}
+	if err := client.Call(nil, "test_rets"); !isLimited(err) {
+		t.Errorf("cheap call over the rate not limited: %v", err)
+	}
+
+	server.SetPolicy(nil)
+	if err := client.Call(nil, "test_rets"); err != nil {
+		t.Errorf("call without policy failed: %v", err)
+	}
+}
+
+func TestCallPolicyPerClient(t *testing.T) {
+	policy := newCallPolicy(&PolicyConfig{
+		Cheap:            ClassLimits{Rate: 1},
+		Expensive:        ClassLimits{Concurrency: 1},
+		ExpensiveMethods: []string{"fsn_allTickets"},
+	})
+	// the connections of a host share its limits
+	if _, err := policy.acquire("10.0.0.1:3000", "fsn_getBalance"); err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+	if _, err := policy.acquire("10.0.0.1:3001", "fsn_getBalance"); err == nil {
+		t.Errorf("call of another connection of the same host not limited")
+	}
+	if _, err := policy.acquire("10.0.0.2:3000", "fsn_getBalance"); err != nil {
+		t.Errorf("call of another client limited: %v", err)
+	}
+
+	release, err := policy.acquire("10.0.0.1:3000", "fsn_allTickets")
+	if err != nil {
+		t.Fatalf("expensive call failed: %v", err)
+	}
+	if _, err := policy.acquire("10.0.0.1:3000", "fsn_allTickets"); err == nil {
+		t.Errorf("concurrent expensive call of the same client not limited")
+	}
+	if _, err := policy.acquire("10.0.0.2:3000", "fsn_allTickets"); err != nil {
+		t.Errorf("concurrent expensive call of another client limited: %v", err)
+	}
+	release()
+	if _, err := policy.acquire("10.0.0.1:3000", "fsn_allTickets"); err != nil {
+		t.Errorf("expensive call after the release failed: %v", err)
+	}
+}
@@ -66,6 +66,17 @@ func (s *Server) RegisterName(name string, receiver interface{}) error {
 	return s.services.registerName(name, receiver)
 }
 
+// SetPolicy limits the calls served by the server, nil removes the limits.
+func (s *Server) SetPolicy(config *PolicyConfig) {
+	var policy *callPolicy
+	if config != nil {
+		policy = newCallPolicy(config)
+	}
+	s.services.mu.Lock()
+	s.services.policy = policy
+	s.services.mu.Unlock()
+}
+
 // ServeCodec reads incoming requests from codec, calls the appropriate callback and writes
 // the response back using the given codec. It will block until the codec is closed or the
 // server is stopped. In either case the codec is closed.
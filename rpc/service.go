@@ -39,6 +39,7 @@ var (
 type serviceRegistry struct {
 	mu       sync.Mutex
 	services map[string]service
+	policy   *callPolicy // nil without limits
 }
 
 // service represents a registered object.
@@ -103,6 +104,13 @@ func (r *serviceRegistry) callback(method string) *callback {
 	return r.services[elem[0]].callbacks[elem[1]]
 }
 
+// callPolicy returns the limits of the calls, nil if there are none.
+func (r *serviceRegistry) callPolicy() *callPolicy {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.policy
+}
+
 // subscription returns a subscription callback in the given service.
 func (r *serviceRegistry) subscription(service, name string) *callback {
 	r.mu.Lock()
@@ -0,0 +1,4 @@
+// This test calls a method that returns an error with error data.
+
+--> {"jsonrpc": "2.0", "id": 2, "method": "test_returnError", "params": []}
+<-- {"jsonrpc":"2.0","id":2,"error":{"code":444,"message":"testError","data":"testError data"}}
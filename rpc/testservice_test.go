@@ -82,6 +82,16 @@ func (s *testService) Block(ctx context.Context) error {
 	return errors.New("context canceled in testservice_block")
 }
 
+type testError struct{}
+
+func (testError) Error() string          { return "testError" }
+func (testError) ErrorCode() int         { return 444 }
+func (testError) ErrorData() interface{} { return "testError data" }
+
+func (s *testService) ReturnError() error {
+	return testError{}
+}
+
 func (s *testService) Rets() (string, error) {
 	return "", nil
 }
@@ -117,6 +117,14 @@ func (ui *CommandlineUI) ApproveTx(request *SignTxRequest) (SignTxResponse, erro
 			fmt.Printf("data:     %v\n", hexutil.Encode(d))
 		}
 	}
+	if call := request.FSNCall; call != nil {
+		fmt.Printf("\nFSN call: %v\n", call)
+		if call.Param != nil {
+			if param, err := json.MarshalIndent(call.Param, "  ", "  "); err == nil {
+				fmt.Printf("  %s\n", param)
+			}
+		}
+	}
 	if request.Callinfo != nil {
 		fmt.Printf("\nTransaction validation:\n")
 		for _, m := range request.Callinfo {
@@ -0,0 +1,88 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/core/types"
+)
+
+// TestFSNTypedDataHash checks that the typed data of the FSN calls hash the
+// same as any other typed data signed through SignTypedData.
+func TestFSNTypedDataHash(t *testing.T) {
+	makeSwap, _ := (&common.MakeSwapParam{
+		FromAssetID:   common.SystemAssetID,
+		FromStartTime: common.TimeLockNow,
+		FromEndTime:   common.TimeLockForever,
+		MinFromAmount: big.NewInt(10),
+		ToAssetID:     common.HexToHash("0xa5"),
+		ToEndTime:     common.TimeLockForever,
+		MinToAmount:   big.NewInt(3),
+		SwapSize:      big.NewInt(5),
+		Targes:        []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")},
+		Description:   "fsn for a5",
+	}).ToBytes()
+	assetChange, _ := (&common.AssetValueChangeExParam{
+		AssetID: common.HexToHash("0xa5"),
+		To:      common.HexToAddress("0x01"),
+		Value:   big.NewInt(7),
+		IsInc:   true,
+	}).ToBytes()
+
+	for _, call := range []*common.FSNCallParam{
+		{Func: common.GenNotationFunc},
+		{Func: common.MakeSwapFunc, Data: makeSwap},
+		{Func: common.AssetValueChangeFunc, Data: assetChange},
+	} {
+		td, err := types.NewFSNTypedData(big.NewInt(32659), call, 1, 1600000000)
+		if err != nil {
+			t.Fatalf("%v: failed to create typed data: %v", call.Func.Name(), err)
+		}
+		enc, err := json.Marshal(td)
+		if err != nil {
+			t.Fatalf("%v: failed to encode typed data: %v", call.Func.Name(), err)
+		}
+		var typedData TypedData
+		if err := json.Unmarshal(enc, &typedData); err != nil {
+			t.Fatalf("%v: failed to decode typed data: %v", call.Func.Name(), err)
+		}
+		// Fixed size byte values are expected to be hexutil.Bytes
+		for _, field := range typedData.Types[typedData.PrimaryType] {
+			if field.Type == "bytes32" {
+				typedData.Message[field.Name] = hexutil.Bytes(hexutil.MustDecode(typedData.Message[field.Name].(string)))
+			}
+		}
+		domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+		if err != nil {
+			t.Fatalf("%v: failed to hash domain: %v", call.Func.Name(), err)
+		}
+		messageHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+		if err != nil {
+			t.Fatalf("%v: failed to hash message: %v", call.Func.Name(), err)
+		}
+		want := append(append([]byte{0x19, 0x01}, domainSeparator...), messageHash...)
+		if have := td.RawData(); !bytes.Equal(have, want) {
+			t.Errorf("%v: raw data mismatch:\nhave %x\nwant %x", call.Func.Name(), have, want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/common/hexutil"
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+// FSNCallInfo is the decoded form of a transaction sent to the FSN call address,
+// so that the UI and the rule engine can inspect FSN calls instead of seeing an
+// opaque data blob. Asset, amount and recipient are only set for the functions
+// that carry them.
+type FSNCallInfo struct {
+	Func   string          `json:"func"`
+	Asset  *common.Hash    `json:"asset,omitempty"`
+	Amount *hexutil.Big    `json:"amount,omitempty"`
+	To     *common.Address `json:"to,omitempty"`
+	Param  interface{}     `json:"param,omitempty"`
+}
+
+// String implements fmt.Stringer.
+func (info *FSNCallInfo) String() string {
+	parts := []string{info.Func}
+	if info.Asset != nil {
+		parts = append(parts, fmt.Sprintf("asset %v", info.Asset.Hex()))
+	}
+	if info.Amount != nil {
+		parts = append(parts, fmt.Sprintf("amount %v", info.Amount.ToInt()))
+	}
+	if info.To != nil {
+		parts = append(parts, fmt.Sprintf("to %v", info.To.Hex()))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// DecodeFSNCall decodes the FSN call carried by the given transaction. It returns
+// nil without error if the transaction is not sent to the FSN call address.
+func DecodeFSNCall(args *SendTxArgs) (*FSNCallInfo, error) {
+	if args.To == nil || args.To.Address() != common.FSNCallAddress {
+		return nil, nil
+	}
+	var data []byte
+	if args.Input != nil {
+		data = *args.Input
+	} else if args.Data != nil {
+		data = *args.Data
+	}
+	var call common.FSNCallParam
+	if err := rlp.DecodeBytes(data, &call); err != nil {
+		return nil, fmt.Errorf("invalid FSN call: %v", err)
+	}
+	info := &FSNCallInfo{Func: call.Func.Name()}
+	if call.Func == common.ReportIllegalFunc {
+		// Report content is decoded by the consensus engine, nothing to show
+		return info, nil
+	}
+	decoded, err := common.DecodeTxInput(data)
+	if err != nil {
+		return nil, err
+	}
+	if decoded, ok := decoded.(*struct {
+		FuncType  string
+		FuncParam interface{}
+	}); ok {
+		info.Param = decoded.FuncParam
+	}
+
+	setAsset := func(asset common.Hash, to *common.Address, amount *big.Int) {
+		info.Asset, info.To = &asset, to
+		if amount != nil {
+			info.Amount = (*hexutil.Big)(amount)
+		}
+	}
+	switch p := info.Param.(type) {
+	case *common.GenAssetParam:
+		info.Amount = (*hexutil.Big)(p.Total)
+	case *common.SendAssetParam:
+		setAsset(p.AssetID, &p.To, p.Value)
+	case *common.TimeLockParam:
+		setAsset(p.AssetID, &p.To, p.Value)
+	case *common.AssetValueChangeExParam:
+		setAsset(p.AssetID, &p.To, p.Value)
+	case *common.MakeSwapParam:
+		// The maker locks up the minimum amount for every unit of the swap
+		var amount *big.Int
+		if p.MinFromAmount != nil && p.SwapSize != nil {
+			amount = new(big.Int).Mul(p.MinFromAmount, p.SwapSize)
+		}
+		setAsset(p.FromAssetID, nil, amount)
+	}
+	return info, nil
+}
@@ -73,6 +73,15 @@ func (db *Database) ValidateTransaction(selector *string, tx *core.SendTxArgs) (
 	if bytes.Equal(tx.To.Address().Bytes(), common.Address{}.Bytes()) {
 		messages.Crit("Transaction recipient is the zero address")
 	}
+	// FSN calls carry RLP encoded parameters instead of ABI call data
+	if tx.To.Address() == common.FSNCallAddress {
+		call, err := core.DecodeFSNCall(tx)
+		if err != nil {
+			return nil, err
+		}
+		messages.Info(fmt.Sprintf("FSN call: %v", call))
+		return messages, nil
+	}
 	// Semantic fields validated, try to make heads or tails of the call data
 	db.ValidateCallData(selector, data, messages)
 	return messages, nil
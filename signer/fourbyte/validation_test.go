@@ -106,6 +106,12 @@ func TestTransactionValidation(t *testing.T) {
 		// Small payload for create
 		{from: "000000000000000000000000000000000000dead", to: "",
 			n: "0x01", g: "0x20", gp: "0x40", value: "0x01", d: "0x01", numMessages: 1},
+		// FSN call (GenNotation) is decoded instead of ABI-validated
+		{from: "000000000000000000000000000000000000dead", to: common.FSNCallAddress.Hex(),
+			n: "0x01", g: "0x20", gp: "0x40", value: "0x00", d: "0xc28080", numMessages: 1},
+		// Malformed FSN call
+		{from: "000000000000000000000000000000000000dead", to: common.FSNCallAddress.Hex(),
+			n: "0x01", g: "0x20", gp: "0x40", value: "0x00", d: "0x0102", expectErr: true},
 	}
 	for i, test := range testcases {
 		msgs, err := db.ValidateTransaction(nil, dummyTxArgs(test))
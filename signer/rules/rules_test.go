@@ -27,6 +27,7 @@ import (
 	"github.com/FusionFoundation/go-fusion/common/hexutil"
 	"github.com/FusionFoundation/go-fusion/core/types"
 	"github.com/FusionFoundation/go-fusion/internal/ethapi"
+	"github.com/FusionFoundation/go-fusion/rlp"
 	"github.com/FusionFoundation/go-fusion/signer/core"
 	"github.com/FusionFoundation/go-fusion/signer/storage"
 )
@@ -194,6 +195,70 @@ func TestSignTxRequest(t *testing.T) {
 	}
 }
 
+func TestFSNCallRequest(t *testing.T) {
+	js := `
+	function big(str){
+		if(str.slice(0,2) == "0x"){ return new BigNumber(str.slice(2),16)}
+		return new BigNumber(str)
+	}
+	function ApproveTx(r){
+		var call = r.fsn_call
+		if(!call){ return }
+		if(call.func == "BuyTicketFunc"){ return "Approve" }
+		if(call.func == "MakeSwapFunc" && big(call.amount).lt(new BigNumber("1e18"))){ return "Approve" }
+	}`
+
+	r, err := initRuleEngine(js)
+	if err != nil {
+		t.Errorf("Couldn't create evaluator %v", err)
+		return
+	}
+	from, _ := mixAddr("0000000000000000000000000000000000001337")
+	to := common.NewMixedcaseAddress(common.FSNCallAddress)
+
+	request := func(fn common.FSNCallFunc, param interface{}) *core.SignTxRequest {
+		var data []byte
+		if param != nil {
+			data, _ = rlp.EncodeToBytes(param)
+		}
+		input, _ := rlp.EncodeToBytes(&common.FSNCallParam{Func: fn, Data: data})
+		args := core.SendTxArgs{From: *from, To: &to, Data: (*hexutil.Bytes)(&input)}
+		call, err := core.DecodeFSNCall(&args)
+		if err != nil {
+			t.Fatalf("failed to decode FSN call: %v", err)
+		}
+		return &core.SignTxRequest{Transaction: args, FSNCall: call}
+	}
+	swap := func(size int64) *common.MakeSwapParam {
+		return &common.MakeSwapParam{
+			FromAssetID:   common.SystemAssetID,
+			MinFromAmount: big.NewInt(1e17),
+			ToAssetID:     common.SystemAssetID,
+			MinToAmount:   big.NewInt(1),
+			SwapSize:      big.NewInt(size),
+			Time:          big.NewInt(0),
+		}
+	}
+	tests := []struct {
+		req      *core.SignTxRequest
+		approved bool
+	}{
+		{request(common.BuyTicketFunc, &common.BuyTicketParam{Start: 1, End: 2}), true},
+		{request(common.MakeSwapFunc, swap(5)), true},
+		{request(common.MakeSwapFunc, swap(50)), false},
+		{request(common.GenNotationFunc, nil), false},
+	}
+	for i, tt := range tests {
+		resp, err := r.ApproveTx(tt.req)
+		if err != nil {
+			t.Errorf("test %d: unexpected error %v", i, err)
+		}
+		if resp.Approved != tt.approved {
+			t.Errorf("test %d (%v): approved mismatch: have %v, want %v", i, tt.req.FSNCall, resp.Approved, tt.approved)
+		}
+	}
+}
+
 type dummyUI struct {
 	calls []string
 }
@@ -0,0 +1,40 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build go1.18
+// +build go1.18
+
+package fsncall
+
+import "testing"
+
+func FuzzFsnParams(f *testing.F) {
+	for _, input := range readCorpus(f) {
+		f.Add(input)
+	}
+	f.Fuzz(func(t *testing.T, input []byte) {
+		FuzzParams(input)
+	})
+}
+
+func FuzzFsnCall(f *testing.F) {
+	for _, input := range readCorpus(f) {
+		f.Add(input)
+	}
+	f.Fuzz(func(t *testing.T, input []byte) {
+		FuzzCall(input)
+	})
+}
@@ -0,0 +1,230 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package fsncall fuzzes the decoding of the FSN call parameters and their
+// execution by the state transition.
+//
+// The fuzzers run with the devnet rules, so that every FSN call function is
+// enabled. Build them with go-fuzz-build and pick the entry point with -func:
+//
+//	(cd ./fsncall && CGO_ENABLED=0 go-fuzz-build -func FuzzCall .)
+//
+// or run them with go test -fuzz FuzzFsnCall on go 1.18 and above.
+package fsncall
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/core"
+	"github.com/FusionFoundation/go-fusion/core/rawdb"
+	"github.com/FusionFoundation/go-fusion/core/state"
+	"github.com/FusionFoundation/go-fusion/core/types"
+	"github.com/FusionFoundation/go-fusion/core/vm"
+	"github.com/FusionFoundation/go-fusion/params"
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+// paramTypes creates an empty parameter of every FSN call function.
+var paramTypes = []func() interface{}{
+	func() interface{} { return new(common.FSNCallParam) },
+	func() interface{} { return new(common.GenAssetParam) },
+	func() interface{} { return new(common.SendAssetParam) },
+	func() interface{} { return new(common.TimeLockParam) },
+	func() interface{} { return new(common.BuyTicketParam) },
+	func() interface{} { return new(common.AssetValueChangeExParam) },
+	func() interface{} { return new(common.MakeSwapParam) },
+	func() interface{} { return new(common.RecallSwapParam) },
+	func() interface{} { return new(common.TakeSwapParam) },
+	func() interface{} { return new(common.MakeMultiSwapParam) },
+	func() interface{} { return new(common.RecallMultiSwapParam) },
+	func() interface{} { return new(common.TakeMultiSwapParam) },
+	func() interface{} { return new(common.TypedCallParam) },
+	func() interface{} { return new(common.StakingKeyParam) },
+	func() interface{} { return new(common.StakingBuyTicketParam) },
+	func() interface{} { return new(common.GenRestrictedAssetParam) },
+	func() interface{} { return new(common.GenMultiOwnerAssetParam) },
+	func() interface{} { return new(common.AssetTransferListParam) },
+	func() interface{} { return new(common.SetFsnCallFeeParam) },
+	func() interface{} { return new(common.CreateProposalParam) },
+	func() interface{} { return new(common.VoteProposalParam) },
+	func() interface{} { return new(common.RevokeTicketParam) },
+	func() interface{} { return new(common.EscrowAssetParam) },
+	func() interface{} { return new(common.ClaimEscrowParam) },
+	func() interface{} { return new(common.CreateStreamParam) },
+	func() interface{} { return new(common.WithdrawStreamParam) },
+	func() interface{} { return new(common.CreateConditionParam) },
+	func() interface{} { return new(common.ResolveConditionParam) },
+	func() interface{} { return new(common.ConditionalTransferParam) },
+	func() interface{} { return new(common.SettleConditionalParam) },
+	func() interface{} { return new(common.AttestDepositParam) },
+	func() interface{} { return new(common.BridgeWithdrawParam) },
+}
+
+var (
+	sender  = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	assetID = common.HexToHash("0x2000000000000000000000000000000000000000000000000000000000000002")
+	swapID  = common.HexToHash("0x3000000000000000000000000000000000000000000000000000000000000003")
+	multiID = common.HexToHash("0x4000000000000000000000000000000000000000000000000000000000000004")
+
+	number    = big.NewInt(100)
+	timestamp = uint64(1500000000)
+
+	// fixture is the state the fuzzed FSN calls are executed on, its sender
+	// holds FSN, an asset, a ticket and open swaps
+	fixture struct {
+		db          state.Database
+		root        common.Hash
+		ticketsHash common.Hash
+	}
+)
+
+func init() {
+	common.UseDevnetRule = true
+
+	fixture.db = state.NewDatabase(rawdb.NewMemoryDatabase())
+	statedb, _ := state.New(common.Hash{}, common.Hash{}, fixture.db)
+	statedb.GenAsset(common.SystemAsset)
+
+	value := new(big.Int).Mul(big.NewInt(1e6), big.NewInt(1e18))
+	statedb.AddBalance(sender, common.SystemAssetID, value)
+	statedb.AddTimeLockBalance(sender, common.SystemAssetID, common.NewTimeLock(&common.TimeLockItem{
+		StartTime: 0,
+		EndTime:   common.TimeLockForever,
+		Value:     value,
+	}), number, timestamp)
+	statedb.GenAsset(common.Asset{ID: assetID, Owner: sender, Name: "Fuzz", Symbol: "FZZ", Total: value, CanChange: true})
+	statedb.AddBalance(sender, assetID, value)
+	statedb.AddTicket(common.Ticket{Owner: sender, TicketBody: common.TicketBody{ID: common.HexToHash("0x05"), Height: 1, StartTime: timestamp, ExpireTime: common.TimeLockForever}})
+	statedb.AddSwap(*fixtureSwap())
+	statedb.AddMultiSwap(*fixtureMultiSwap())
+	statedb.AllTickets()
+	fixture.ticketsHash, _ = statedb.UpdateTickets(number, timestamp)
+	fixture.root, _ = statedb.Commit(true)
+	if err := fixture.db.TrieDB().Commit(fixture.root, false); err != nil {
+		panic(err)
+	}
+}
+
+// fixtureSwap returns the open swap of the fixture, selling the asset for FSN.
+func fixtureSwap() *common.Swap {
+	return &common.Swap{
+		ID: swapID, Owner: sender, FromAssetID: assetID, FromEndTime: common.TimeLockForever, MinFromAmount: big.NewInt(1),
+		ToAssetID: common.SystemAssetID, ToEndTime: common.TimeLockForever, MinToAmount: big.NewInt(1), SwapSize: big.NewInt(100), Time: big.NewInt(0),
+	}
+}
+
+// fixtureMultiSwap returns the open multi swap of the fixture, selling the
+// asset for FSN.
+func fixtureMultiSwap() *common.MultiSwap {
+	return &common.MultiSwap{
+		ID: multiID, Owner: sender, FromAssetID: []common.Hash{assetID}, FromStartTime: []uint64{0}, FromEndTime: []uint64{common.TimeLockForever},
+		MinFromAmount: []*big.Int{big.NewInt(1)}, ToAssetID: []common.Hash{common.SystemAssetID}, ToStartTime: []uint64{0},
+		ToEndTime: []uint64{common.TimeLockForever}, MinToAmount: []*big.Int{big.NewInt(1)}, SwapSize: big.NewInt(100), Time: big.NewInt(0),
+	}
+}
+
+// checkParam runs the checks of a decoded parameter against the fixture.
+func checkParam(p interface{}) {
+	switch p := p.(type) {
+	case *common.RecallSwapParam:
+		p.Check(number, fixtureSwap())
+	case *common.TakeSwapParam:
+		p.Check(number, fixtureSwap(), timestamp)
+	case *common.RecallMultiSwapParam:
+		p.Check(number, fixtureMultiSwap())
+	case *common.TakeMultiSwapParam:
+		p.Check(number, fixtureMultiSwap(), timestamp)
+	case *common.StakingKeyParam:
+		p.Check(number, sender)
+	case interface {
+		Check(*big.Int, uint64) error
+	}:
+		p.Check(number, timestamp)
+	case interface{ Check(*big.Int) error }:
+		p.Check(number)
+	default:
+		panic(fmt.Sprintf("no check for %T", p))
+	}
+}
+
+// FuzzParams decodes the input as every FSN call parameter and checks it. As
+// the state transition ignores decoding errors, the partly decoded parameters
+// are checked too. The parameters decoded without error must encode back to
+// the input.
+func FuzzParams(input []byte) int {
+	common.DecodeTxInput(input)
+
+	valid := 0
+	for i, newParam := range paramTypes {
+		p := newParam()
+		err := rlp.DecodeBytes(input, p)
+		checkParam(p)
+		if err != nil {
+			continue
+		}
+		valid = 1
+		output, err := rlp.EncodeToBytes(p)
+		if err != nil {
+			panic(fmt.Sprintf("case %d: %T decoded but does not encode: %v", i, p, err))
+		}
+		if !bytes.Equal(input, output) {
+			panic(fmt.Sprintf("case %d: %T encode-decode is not equal, \ninput : %x\noutput: %x", i, p, input, output))
+		}
+	}
+	return valid
+}
+
+// FuzzCall executes an FSN call whose function is the first byte of the input
+// and whose parameter is the rest of it. The call is sent by the owner of the
+// fixture asset, ticket and swaps, so that the fixture IDs lead the fuzzer
+// deep into the call handlers.
+func FuzzCall(input []byte) int {
+	if len(input) == 0 || len(input) > 16*1024 {
+		return -1
+	}
+	data, err := rlp.EncodeToBytes(&common.FSNCallParam{Func: common.FSNCallFunc(input[0]), Data: input[1:]})
+	if err != nil {
+		panic(err)
+	}
+	statedb, err := state.New(fixture.root, fixture.ticketsHash, fixture.db)
+	if err != nil {
+		panic(err)
+	}
+	context := vm.Context{
+		CanTransfer:         core.CanTransfer,
+		Transfer:            core.Transfer,
+		CanTransferTimeLock: core.CanTransferTimeLock,
+		TransferTimeLock:    core.TransferTimeLock,
+		GetHash:             func(n uint64) common.Hash { return common.BigToHash(new(big.Int).SetUint64(n)) },
+		Origin:              sender,
+		GasPrice:            new(big.Int),
+		GasLimit:            params.GenesisGasLimit,
+		BlockNumber:         new(big.Int).Set(number),
+		Time:                new(big.Int).SetUint64(timestamp + 15),
+		ParentTime:          new(big.Int).SetUint64(timestamp),
+		Difficulty:          big.NewInt(1),
+	}
+	evm := vm.NewEVM(context, statedb, params.DevnetChainConfig, vm.Config{})
+	msg := types.NewMessage(sender, &common.FSNCallAddress, 0, new(big.Int), 1000000, new(big.Int), data, false)
+	if _, _, _, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(params.GenesisGasLimit)); err != nil {
+		return 0
+	}
+	statedb.IntermediateRoot(true)
+	return 1
+}
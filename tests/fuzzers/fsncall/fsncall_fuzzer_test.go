@@ -0,0 +1,73 @@
+// Copyright 2018 The go-fusion Authors
+// This file is part of the go-fusion library.
+//
+// The go-fusion library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-fusion library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-fusion library. If not, see <http://www.gnu.org/licenses/>.
+
+package fsncall
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FusionFoundation/go-fusion/common"
+	"github.com/FusionFoundation/go-fusion/rlp"
+)
+
+// readCorpus returns the seed inputs of the fuzzers by file name.
+func readCorpus(t testing.TB) map[string][]byte {
+	files, err := filepath.Glob(filepath.Join("corpus", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	corpus := make(map[string][]byte, len(files))
+	for _, file := range files {
+		input, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		corpus[filepath.Base(file)] = input
+	}
+	return corpus
+}
+
+func TestCorpus(t *testing.T) {
+	for name, input := range readCorpus(t) {
+		FuzzParams(input)
+		res := FuzzCall(input)
+		// the call seeds run on the fixture and must be executed
+		if strings.HasPrefix(name, "call-") && res != 1 {
+			t.Errorf("%s: FSN call failed", name)
+		}
+	}
+}
+
+// The state transition ignores the errors decoding the parameters, so every
+// function must cope with empty and zero value parameters.
+func TestZeroValueParams(t *testing.T) {
+	for fn := common.GenNotationFunc; fn <= common.BridgeWithdrawFunc; fn++ {
+		FuzzCall([]byte{byte(fn)})
+		FuzzCall([]byte{byte(fn), 0xc0})
+	}
+	FuzzCall([]byte{common.UnknownFunc})
+	for _, newParam := range paramTypes {
+		input, err := rlp.EncodeToBytes(newParam())
+		if err != nil {
+			t.Fatal(err)
+		}
+		FuzzParams(input)
+		FuzzCall(append([]byte{byte(common.TypedCallFunc)}, input...))
+	}
+}